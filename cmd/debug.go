@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	kcptenancy "github.com/kcp-dev/kcp/sdk/apis/tenancy/v1alpha1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/recording"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Troubleshooting helpers for the Gateway and the Listener",
+}
+
+var debugKcpListCmd = &cobra.Command{
+	Use:     "kcp-list",
+	Short:   "List the kcp workspaces visible from the current kubeconfig",
+	Example: "KUBECONFIG=<path to kcp kubeconfig file> go run . debug kcp-list",
+	Args:    cobra.NoArgs,
+	RunE:    runDebugKcpList,
+}
+
+var debugReplayCmd = &cobra.Command{
+	Use:     "replay <bundle>",
+	Short:   "Re-execute an operation recorded by the Gateway against the recorded API server responses",
+	Example: "go run . debug replay ./recordings/root-DashboardPods-1760000000000000000.json",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runDebugReplay,
+}
+
+// runDebugKcpList prints the child workspaces of the workspace the current kubeconfig points to
+func runDebugKcpList(cmd *cobra.Command, _ []string) error {
+	debugScheme := runtime.NewScheme()
+	utilruntime.Must(kcptenancy.AddToScheme(debugScheme))
+
+	clt, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: debugScheme})
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	workspaces := &kcptenancy.WorkspaceList{}
+	if err := clt.List(cmd.Context(), workspaces); err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tPHASE\tCLUSTER\tURL")
+	for _, ws := range workspaces.Items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ws.Name, ws.Status.Phase, ws.Spec.Cluster, ws.Spec.URL)
+	}
+
+	return w.Flush()
+}
+
+// runDebugReplay prints the result of the recorded operation, resolved without access to the cluster
+func runDebugReplay(cmd *cobra.Command, args []string) error {
+	bundle, err := recording.ReadBundle(args[0])
+	if err != nil {
+		return err
+	}
+
+	result, err := recording.Replay(cmd.Context(), log, bundle)
+	if err != nil {
+		return fmt.Errorf("failed to replay %s: %w", args[0], err)
+	}
+
+	encoder := json.NewEncoder(cmd.OutOrStdout())
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
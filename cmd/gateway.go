@@ -10,9 +10,9 @@ import (
 	openmfpcontext "github.com/openmfp/golang-commons/context"
 	"github.com/openmfp/golang-commons/logger"
 	"github.com/openmfp/golang-commons/sentry"
-	"github.com/openmfp/golang-commons/traces"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager"
@@ -21,38 +21,49 @@ import (
 var gatewayCmd = &cobra.Command{
 	Use:     "gateway",
 	Short:   "Run the GQL Gateway",
-	Example: "go run main.go gateway",
-	Run: func(_ *cobra.Command, _ []string) {
-		log.Info().Str("LogLevel", log.GetLevel().String()).Msg("Starting the Gateway...")
+	Example: "go run main.go gateway serve",
+	// Running the bare command is kept for backward compatibility and is equivalent to `gateway serve`
+	Run: runGateway,
+}
 
-		ctx, _, shutdown := openmfpcontext.StartContext(log, appCfg, 1*time.Second)
-		defer shutdown()
+var gatewayServeCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   "Serve the GraphQL API for all clusters found in the definitions directory",
+	Example: "go run main.go gateway serve",
+	Run:     runGateway,
+}
 
-		if err := initializeSentry(ctx, log); err != nil {
-			log.Fatal().Err(err).Msg("Failed to initialize Sentry")
-		}
+// runGateway starts the gateway and blocks until the process receives a termination signal
+func runGateway(_ *cobra.Command, _ []string) {
+	log.Info().Str("LogLevel", log.GetLevel().String()).Msg("Starting the Gateway...")
 
-		ctrl.SetLogger(log.Logr())
+	ctx, _, shutdown := openmfpcontext.StartContext(log, appCfg, 1*time.Second)
+	defer shutdown()
 
-		gatewayInstance, err := manager.NewGateway(ctx, log, appCfg)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to create gateway")
-		}
+	if err := initializeSentry(ctx, log); err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize Sentry")
+	}
 
-		tracingShutdown, err := initializeTracing(ctx, log)
-		if err != nil {
-			log.Fatal().Err(err).Msg("Failed to initialize tracing")
-		}
-		defer func() {
-			if err := tracingShutdown(ctx); err != nil {
-				log.Error().Err(err).Msg("failed to shutdown TracerProvider")
-			}
-		}()
-
-		if err := runServers(ctx, log, gatewayInstance); err != nil {
-			log.Fatal().Err(err).Msg("Failed to run servers")
+	ctrl.SetLogger(log.Logr())
+
+	gatewayInstance, err := manager.NewGateway(ctx, log, appCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create gateway")
+	}
+
+	telemetryProvider, err := initializeTelemetry(ctx, "kubernetes-graphql-gateway")
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize telemetry")
+	}
+	defer func() {
+		if err := telemetryProvider.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msg("failed to shutdown telemetry exporters")
 		}
-	},
+	}()
+
+	if err := runServers(ctx, log, gatewayInstance); err != nil {
+		log.Fatal().Err(err).Msg("Failed to run servers")
+	}
 }
 
 func initializeSentry(ctx context.Context, log *logger.Logger) error {
@@ -72,29 +83,14 @@ func initializeSentry(ctx context.Context, log *logger.Logger) error {
 	return nil
 }
 
-func initializeTracing(ctx context.Context, log *logger.Logger) (func(ctx context.Context) error, error) {
-	if defaultCfg.Tracing.Enabled {
-		shutdown, err := traces.InitProvider(ctx, defaultCfg.Tracing.Collector)
-		if err != nil {
-			log.Fatal().Err(err).Msg("unable to start gRPC-Sidecar TracerProvider")
-		}
-		return shutdown, nil
-	}
-
-	shutdown, err := traces.InitLocalProvider(ctx, defaultCfg.Tracing.Collector, false)
-	if err != nil {
-		log.Fatal().Err(err).Msg("unable to start local TracerProvider")
-	}
-	return shutdown, nil
-}
-
-func createServers(gatewayInstance http.Handler) (*http.Server, *http.Server, *http.Server) {
+func createServers(gatewayInstance *manager.Service) (*http.Server, *http.Server, *http.Server) {
 	// Main server for GraphQL
 	mainMux := http.NewServeMux()
 	mainMux.Handle("/", gatewayInstance)
 	mainServer := &http.Server{
-		Addr:    fmt.Sprintf(":%s", appCfg.Gateway.Port),
-		Handler: mainMux,
+		Addr: fmt.Sprintf(":%s", appCfg.Gateway.Port),
+		// The trace context sent by the clients becomes the parent of the resolver spans
+		Handler: otelhttp.NewHandler(mainMux, "graphql-gateway"),
 	}
 
 	// Metrics server
@@ -110,9 +106,15 @@ func createServers(gatewayInstance http.Handler) (*http.Server, *http.Server, *h
 	healthMux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
-	healthMux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-	})
+	healthMux.HandleFunc("/readyz", gatewayInstance.ServeReadiness)
+	healthMux.HandleFunc("/schemaz", gatewayInstance.ServeSchemaStatus)
+	healthMux.HandleFunc("/fieldusagez", gatewayInstance.ServeFieldUsage)
+	if faultInjection := gatewayInstance.FaultInjectionHandler(); faultInjection != nil {
+		healthMux.Handle("/faultz", faultInjection)
+	}
+	if adminHandler := gatewayInstance.AdminHandler(); adminHandler != nil {
+		healthMux.Handle("/admin/", adminHandler)
+	}
 	healthServer := &http.Server{
 		Addr:    defaultCfg.HealthProbeBindAddress,
 		Handler: healthMux,
@@ -121,12 +123,29 @@ func createServers(gatewayInstance http.Handler) (*http.Server, *http.Server, *h
 	return mainServer, metricsServer, healthServer
 }
 
-func shutdownServers(ctx context.Context, log *logger.Logger, mainServer, metricsServer, healthServer *http.Server) {
-	log.Info().Msg("Shutting down HTTP servers...")
+// drainServer stops the main server from accepting connections, completes the open subscriptions and waits for the
+// running operations within the drain timeout, the connections still open afterwards are dropped
+func drainServer(log *logger.Logger, gatewayInstance *manager.Service, mainServer *http.Server) {
+	log.Info().Dur("timeout", appCfg.Gateway.Shutdown.DrainTimeout).Msg("Draining main HTTP server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), appCfg.Gateway.Shutdown.DrainTimeout)
+	defer cancel()
+
+	// Subscriptions would keep Shutdown waiting, and WebSocket connections aren't tracked by the server at all
+	drained := make(chan error, 1)
+	go func() { drained <- gatewayInstance.Drain(ctx) }()
 
 	if err := mainServer.Shutdown(ctx); err != nil {
-		log.Error().Err(err).Msg("Main HTTP server shutdown failed")
+		log.Error().Err(err).Msg("Main HTTP server didn't drain in time, dropping the remaining connections")
+		_ = mainServer.Close()
 	}
+	if err := <-drained; err != nil {
+		log.Error().Err(err).Msg("Subscriptions didn't complete in time")
+	}
+}
+
+func shutdownServers(ctx context.Context, log *logger.Logger, metricsServer, healthServer *http.Server) {
+	log.Info().Msg("Shutting down HTTP servers...")
 
 	if err := metricsServer.Shutdown(ctx); err != nil {
 		log.Error().Err(err).Msg("Metrics HTTP server shutdown failed")
@@ -137,7 +156,7 @@ func shutdownServers(ctx context.Context, log *logger.Logger, mainServer, metric
 	}
 }
 
-func runServers(ctx context.Context, log *logger.Logger, gatewayInstance http.Handler) error {
+func runServers(ctx context.Context, log *logger.Logger, gatewayInstance *manager.Service) error {
 	mainServer, metricsServer, healthServer := createServers(gatewayInstance)
 
 	// Start main server (GraphQL)
@@ -167,15 +186,15 @@ func runServers(ctx context.Context, log *logger.Logger, gatewayInstance http.Ha
 	// Wait for shutdown signal
 	<-ctx.Done()
 
+	drainServer(log, gatewayInstance, mainServer)
+
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), defaultCfg.ShutdownTimeout)
 	defer cancel()
 
-	shutdownServers(shutdownCtx, log, mainServer, metricsServer, healthServer)
+	shutdownServers(shutdownCtx, log, metricsServer, healthServer)
 
-	if closer, ok := gatewayInstance.(interface{ Close() error }); ok {
-		if err := closer.Close(); err != nil {
-			log.Error().Err(err).Msg("Error closing gateway services")
-		}
+	if err := gatewayInstance.Close(); err != nil {
+		log.Error().Err(err).Msg("Error closing gateway services")
 	}
 
 	log.Info().Msg("Server shut down successfully")
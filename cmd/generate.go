@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+var listenerGenerateCmd = &cobra.Command{
+	Use:     "generate <cluster-name>",
+	Short:   "Generate the schema definition for the current cluster once and exit",
+	Example: "KUBECONFIG=<path to kubeconfig file> go run . listener generate my-cluster",
+	Args:    cobra.ExactArgs(1),
+	Run:     runListenerGenerate,
+}
+
+// runListenerGenerate resolves the API schema of the cluster from the current kubeconfig
+// and writes it, together with the cluster metadata, into the definitions directory
+func runListenerGenerate(_ *cobra.Command, args []string) {
+	clusterName := args[0]
+
+	log.Info().Str("cluster", clusterName).Msg("Generating schema definition...")
+
+	restCfg := ctrl.GetConfigOrDie()
+
+	dc, err := discovery.NewDiscoveryClientForConfig(restCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create discovery client")
+	}
+
+	httpClt, err := rest.HTTPClientFor(restCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create HTTP client")
+	}
+
+	rm, err := apiutil.NewDynamicRESTMapper(restCfg, httpClt)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create REST mapper")
+	}
+
+	schemaJSON, err := apischema.NewResolver(log, apischema.ResolverOptionsFromConfig(appCfg)...).Resolve(dc, rm)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to resolve API schema")
+	}
+
+	schemaWithMetadata, err := auth.InjectKCPMetadataFromEnv(schemaJSON, clusterName, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to inject cluster metadata")
+	}
+
+	ioHandler, err := workspacefile.NewIOHandlerFromConfig(appCfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("unable to create IO handler")
+	}
+
+	if err := ioHandler.Write(schemaWithMetadata, clusterName); err != nil {
+		log.Fatal().Err(err).Msg("failed to write schema definition")
+	}
+
+	log.Info().
+		Str("cluster", clusterName).
+		Str("definitionsPath", appCfg.OpenApiDefinitionsPath).
+		Msg("Schema definition generated successfully")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"crypto/tls"
+	"net/http"
 
 	kcpapis "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
@@ -15,6 +16,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -35,110 +37,163 @@ var (
 
 var listenCmd = &cobra.Command{
 	Use:     "listener",
-	Example: "KUBECONFIG=<path to kubeconfig file> go run . listener",
-	PreRun: func(cmd *cobra.Command, args []string) {
-		utilruntime.Must(clientgoscheme.AddToScheme(scheme))
-
-		if appCfg.EnableKcp {
-			utilruntime.Must(kcpapis.AddToScheme(scheme))
-			utilruntime.Must(kcpcore.AddToScheme(scheme))
-			utilruntime.Must(kcptenancy.AddToScheme(scheme))
-		}
+	Short:   "Run the Listener",
+	Example: "KUBECONFIG=<path to kubeconfig file> go run . listener serve",
+	// Running the bare command is kept for backward compatibility and is equivalent to `listener serve`
+	PreRun: setupListener,
+	Run:    runListener,
+}
 
-		utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
-		utilruntime.Must(gatewayv1alpha1.AddToScheme(scheme))
+var listenerServeCmd = &cobra.Command{
+	Use:     "serve",
+	Short:   "Watch the cluster APIs and keep the schema definitions up to date",
+	Example: "KUBECONFIG=<path to kubeconfig file> go run . listener serve",
+	PreRun:  setupListener,
+	Run:     runListener,
+}
 
-		ctrl.SetLogger(log.ComponentLogger("controller-runtime").Logr())
+// setupListener registers the API types and prepares the metrics and webhook servers used by the manager
+func setupListener(_ *cobra.Command, _ []string) {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
-		disableHTTP2 := func(c *tls.Config) {
-			log.Info().Msg("disabling http/2")
-			c.NextProtos = []string{"http/1.1"}
-		}
+	if appCfg.EnableKcp {
+		utilruntime.Must(kcpapis.AddToScheme(scheme))
+		utilruntime.Must(kcpcore.AddToScheme(scheme))
+		utilruntime.Must(kcptenancy.AddToScheme(scheme))
+	}
 
-		var tlsOpts []func(*tls.Config)
-		if !defaultCfg.EnableHTTP2 {
-			tlsOpts = []func(c *tls.Config){disableHTTP2}
-		}
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
+	utilruntime.Must(gatewayv1alpha1.AddToScheme(scheme))
 
-		webhookServer = webhook.NewServer(webhook.Options{
-			TLSOpts: tlsOpts,
-		})
+	ctrl.SetLogger(log.ComponentLogger("controller-runtime").Logr())
 
-		metricsServerOptions = metricsserver.Options{
-			BindAddress:   defaultCfg.Metrics.BindAddress,
-			SecureServing: defaultCfg.Metrics.Secure,
-			TLSOpts:       tlsOpts,
-		}
+	disableHTTP2 := func(c *tls.Config) {
+		log.Info().Msg("disabling http/2")
+		c.NextProtos = []string{"http/1.1"}
+	}
 
-		if defaultCfg.Metrics.Secure {
-			metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
-		}
-	},
-	Run: func(cmd *cobra.Command, args []string) {
-		log.Info().Str("LogLevel", log.GetLevel().String()).Msg("Starting the Listener...")
-
-		ctx := ctrl.SetupSignalHandler()
-		restCfg := ctrl.GetConfigOrDie()
-
-		mgrOpts := ctrl.Options{
-			Scheme:                 scheme,
-			Metrics:                metricsServerOptions,
-			WebhookServer:          webhookServer,
-			HealthProbeBindAddress: defaultCfg.HealthProbeBindAddress,
-			LeaderElection:         defaultCfg.LeaderElection.Enabled,
-			LeaderElectionID:       "72231e1f.openmfp.io",
-		}
+	var tlsOpts []func(*tls.Config)
+	if !defaultCfg.EnableHTTP2 {
+		tlsOpts = []func(c *tls.Config){disableHTTP2}
+	}
 
-		clt, err := client.New(restCfg, client.Options{
-			Scheme: scheme,
-		})
-		if err != nil {
-			log.Fatal().Err(err).Msg("failed to create client from config")
+	webhookServer = webhook.NewServer(webhook.Options{
+		TLSOpts: tlsOpts,
+	})
+
+	metricsServerOptions = metricsserver.Options{
+		BindAddress:   defaultCfg.Metrics.BindAddress,
+		SecureServing: defaultCfg.Metrics.Secure,
+		TLSOpts:       tlsOpts,
+	}
+
+	if defaultCfg.Metrics.Secure {
+		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	}
+}
+
+// runListener starts the reconciler matching the configured mode and blocks until the manager stops
+func runListener(_ *cobra.Command, _ []string) {
+	log.Info().Str("LogLevel", log.GetLevel().String()).Msg("Starting the Listener...")
+
+	ctx := ctrl.SetupSignalHandler()
+	restCfg := ctrl.GetConfigOrDie()
+
+	telemetryProvider, err := initializeTelemetry(ctx, "kubernetes-graphql-gateway-listener")
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to initialize telemetry")
+	}
+	defer func() {
+		if err := telemetryProvider.Shutdown(context.Background()); err != nil {
+			log.Error().Err(err).Msg("failed to shutdown telemetry exporters")
 		}
+	}()
+
+	// All schemas are generated again periodically, on SIGUSR1 and on POST requests to the metrics server, in case
+	// watch events were missed
+	resyncer := reconciler.NewResyncer(log, appCfg.Listener.ResyncPeriod)
+	go resyncer.WatchSignal(ctx)
+
+	metricsOpts := metricsServerOptions
+	metricsOpts.ExtraHandlers = map[string]http.Handler{reconciler.ResyncPath: resyncer}
+
+	mgrOpts := ctrl.Options{
+		Scheme:                 scheme,
+		Metrics:                metricsOpts,
+		WebhookServer:          webhookServer,
+		HealthProbeBindAddress: defaultCfg.HealthProbeBindAddress,
+		// Only the elected replica generates and writes schemas, the others take over if it stops
+		LeaderElection:          defaultCfg.LeaderElection.Enabled,
+		LeaderElectionID:        appCfg.Listener.LeaderElection.ID,
+		LeaderElectionNamespace: appCfg.Listener.LeaderElection.Namespace,
+		// The listener exits once the manager stopped, so the next replica doesn't wait for the lease to expire
+		LeaderElectionReleaseOnCancel: true,
+	}
+
+	clt, err := client.New(restCfg, client.Options{
+		Scheme: scheme,
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create client from config")
+	}
 
-		reconcilerOpts := reconciler.ReconcilerOpts{
-			Scheme:                 scheme,
-			Client:                 clt,
-			Config:                 restCfg,
-			ManagerOpts:            mgrOpts,
-			OpenAPIDefinitionsPath: appCfg.OpenApiDefinitionsPath,
+	reconcilerOpts := reconciler.ReconcilerOpts{
+		Scheme:                 scheme,
+		Client:                 clt,
+		Config:                 restCfg,
+		ManagerOpts:            mgrOpts,
+		OpenAPIDefinitionsPath: appCfg.OpenApiDefinitionsPath,
+	}
+
+	// Create the appropriate reconciler based on configuration
+	var reconcilerInstance reconciler.CustomReconciler
+	if appCfg.EnableKcp {
+		kcpReconciler, err := kcp.NewKCPReconciler(appCfg, reconcilerOpts, log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to create KCP reconciler")
 		}
 
-		// Create the appropriate reconciler based on configuration
-		var reconcilerInstance reconciler.CustomReconciler
-		if appCfg.EnableKcp {
-			kcpReconciler, err := kcp.NewKCPReconciler(appCfg, reconcilerOpts, log)
-			if err != nil {
-				log.Fatal().Err(err).Msg("unable to create KCP reconciler")
+		// Start virtual workspace watching if path is configured, it writes schemas, so it runs on the leader only
+		if appCfg.Listener.VirtualWorkspacesConfigPath != "" {
+			if err := kcpReconciler.GetManager().Add(manager.RunnableFunc(func(ctx context.Context) error {
+				return kcpReconciler.StartVirtualWorkspaceWatching(ctx, appCfg.Listener.VirtualWorkspacesConfigPath)
+			})); err != nil {
+				log.Fatal().Err(err).Msg("failed to start virtual workspace watching")
 			}
+		}
 
-			// Start virtual workspace watching if path is configured
-			if appCfg.Listener.VirtualWorkspacesConfigPath != "" {
-				go func() {
-					if err := kcpReconciler.StartVirtualWorkspaceWatching(ctx, appCfg.Listener.VirtualWorkspacesConfigPath); err != nil {
-						log.Fatal().Err(err).Msg("failed to start virtual workspace watching")
-					}
-				}()
-			}
+		reconcilerInstance = kcpReconciler
+	} else {
+		ioHandler, err := workspacefile.NewIOHandlerFromConfig(appCfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to create IO handler")
+		}
 
-			reconcilerInstance = kcpReconciler
-		} else {
-			ioHandler, err := workspacefile.NewIOHandler(appCfg.OpenApiDefinitionsPath)
-			if err != nil {
-				log.Fatal().Err(err).Msg("unable to create IO handler")
-			}
+		reconcilerInstance, err = clusteraccess.NewClusterAccessReconciler(ctx, appCfg, reconcilerOpts, ioHandler, apischema.NewResolver(log, apischema.ResolverOptionsFromConfig(appCfg)...), log)
+		if err != nil {
+			log.Fatal().Err(err).Msg("unable to create cluster access reconciler")
+		}
 
-			reconcilerInstance, err = clusteraccess.NewClusterAccessReconciler(ctx, appCfg, reconcilerOpts, ioHandler, apischema.NewResolver(log), log)
-			if err != nil {
-				log.Fatal().Err(err).Msg("unable to create cluster access reconciler")
+		// The webhook rejects invalid ClusterAccesses when they are applied, it is served by every replica
+		if appCfg.Listener.WebhookEnabled {
+			mgr := reconcilerInstance.GetManager()
+			if err := clusteraccess.NewValidator(mgr.GetClient(), mgr.GetAPIReader()).SetupWebhookWithManager(mgr); err != nil {
+				log.Fatal().Err(err).Msg("unable to set up the ClusterAccess webhook")
 			}
 		}
+	}
 
-		// Setup reconciler with its own manager and start everything
-		if err := startManagerWithReconciler(ctx, reconcilerInstance); err != nil {
-			log.Fatal().Err(err).Msg("failed to start manager with reconciler")
+	// The resync runs with the manager, so that only the leader regenerates the schemas
+	if resyncable, ok := reconcilerInstance.(reconciler.Resyncable); ok {
+		if err := reconcilerInstance.GetManager().Add(resyncer.Runnable(resyncable)); err != nil {
+			log.Fatal().Err(err).Msg("failed to set up the schema resync")
 		}
-	},
+	}
+
+	// Setup reconciler with its own manager and start everything
+	if err := startManagerWithReconciler(ctx, reconcilerInstance); err != nil {
+		log.Fatal().Err(err).Msg("failed to start manager with reconciler")
+	}
 }
 
 // startManagerWithReconciler handles the common manager setup and start operations
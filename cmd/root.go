@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"context"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	openmfpconfig "github.com/openmfp/golang-commons/config"
 	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
 	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/telemetry"
 )
 
 var (
@@ -17,12 +22,20 @@ var (
 )
 
 var rootCmd = &cobra.Command{
-	Use: "listener or gateway",
+	Use:   "kubernetes-graphql-gateway",
+	Short: "Expose Kubernetes APIs as a GraphQL API",
 }
 
 func init() {
+	gatewayCmd.AddCommand(gatewayServeCmd)
+	listenCmd.AddCommand(listenerServeCmd)
+	listenCmd.AddCommand(listenerGenerateCmd)
+	debugCmd.AddCommand(debugKcpListCmd)
+	debugCmd.AddCommand(debugReplayCmd)
+
 	rootCmd.AddCommand(gatewayCmd)
 	rootCmd.AddCommand(listenCmd)
+	rootCmd.AddCommand(debugCmd)
 
 	var err error
 	v, defaultCfg, err = openmfpconfig.NewDefaultConfig(rootCmd)
@@ -30,55 +43,35 @@ func init() {
 		panic(err)
 	}
 
-	cobra.OnInitialize(func() {
-		initConfig()
+	// The application config is bound once and shared by all subcommands,
+	// so that every binary entrypoint resolves flags and env vars the same way
+	if err := config.BindFlags(v, rootCmd.PersistentFlags()); err != nil {
+		panic(err)
+	}
 
+	cobra.OnInitialize(func() {
 		var err error
 		log, err = setupLogger(defaultCfg.Log.Level)
 		if err != nil {
 			panic("failed to initialize logger: " + err.Error())
 		}
-	})
 
-	err = openmfpconfig.BindConfigToFlags(v, gatewayCmd, &appCfg)
-	if err != nil {
-		panic(err)
-	}
-
-	err = openmfpconfig.BindConfigToFlags(v, listenCmd, &appCfg)
-	if err != nil {
-		panic(err)
-	}
-}
+		if err := v.Unmarshal(&appCfg); err != nil {
+			log.Fatal().Err(err).Msg("failed to unmarshal config")
+		}
+		if err := appCfg.Validate(); err != nil {
+			log.Fatal().Err(err).Msg("invalid configuration")
+		}
 
-func initConfig() {
-	// Top-level defaults
-	v.SetDefault("openapi-definitions-path", "./bin/definitions")
-	v.SetDefault("enable-kcp", true)
-	v.SetDefault("local-development", false)
-	v.SetDefault("introspection-authentication", false)
-
-	// Listener
-	v.SetDefault("listener-apiexport-workspace", ":root")
-	v.SetDefault("listener-apiexport-name", "kcp.io")
-
-	// Gateway
-	v.SetDefault("gateway-port", "8080")
-
-	v.SetDefault("gateway-username-claim", "email")
-	v.SetDefault("gateway-should-impersonate", true)
-	// Gateway Handler config
-	v.SetDefault("gateway-handler-pretty", true)
-	v.SetDefault("gateway-handler-playground", true)
-	v.SetDefault("gateway-handler-graphiql", true)
-	// Gateway CORS
-	v.SetDefault("gateway-cors-enabled", false)
-	v.SetDefault("gateway-cors-allowed-origins", "*")
-	v.SetDefault("gateway-cors-allowed-headers", "*")
-	// Gateway URL
-	v.SetDefault("gateway-url-virtual-workspace-prefix", "virtual-workspace")
-	v.SetDefault("gateway-url-default-kcp-workspace", "root")
-	v.SetDefault("gateway-url-graphql-suffix", "graphql")
+		// Credential plugins of ClusterAccesses, schema files and registered clusters only run the commands allowed here
+		var execCommands []string
+		for _, command := range strings.Split(appCfg.ExecCommands, ",") {
+			if command = strings.TrimSpace(command); command != "" {
+				execCommands = append(execCommands, command)
+			}
+		}
+		auth.SetExecCommands(execCommands)
+	})
 }
 
 // setupLogger initializes the logger with the given log level
@@ -89,6 +82,37 @@ func setupLogger(logLevel string) (*logger.Logger, error) {
 	return logger.New(loggerCfg)
 }
 
+// initializeTelemetry installs the OTel exporters configured by the OTEL_ env vars and the telemetry config file,
+// and keeps them in sync with the file until ctx is cancelled
+func initializeTelemetry(ctx context.Context, serviceName string) (*telemetry.Provider, error) {
+	base := telemetry.ConfigFromEnv()
+	// The collector of the common tracing config is still honored when no OTLP endpoint is set
+	if base.Endpoint == "" && defaultCfg.Tracing.Enabled {
+		base.Endpoint = defaultCfg.Tracing.Collector.CollectorEndpoint
+		base.Insecure = true
+	}
+
+	cfg, err := telemetry.LoadConfig(appCfg.TelemetryConfigPath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := telemetry.NewProvider(serviceName, log)
+	if err := provider.Apply(ctx, cfg); err != nil {
+		return nil, err
+	}
+
+	if appCfg.TelemetryConfigPath != "" {
+		go func() {
+			if err := provider.Watch(ctx, appCfg.TelemetryConfigPath, base); err != nil {
+				log.Error().Err(err).Msg("failed to watch telemetry config")
+			}
+		}()
+	}
+
+	return provider, nil
+}
+
 func Execute() {
 	cobra.CheckErr(rootCmd.Execute())
 }
@@ -6,11 +6,14 @@ import (
 
 // ClusterAccessSpec defines the desired state of ClusterAccess
 type ClusterAccessSpec struct {
-	// Path is an optional field. If not set, the name of the resource is used
+	// Path is an optional field. If not set, the name of the resource is used. It names the schema file of the
+	// cluster, so it must be unique among the ClusterAccesses.
 	// +optional
+	// +kubebuilder:validation:XValidation:rule="!self.contains('/') && !self.startsWith('.')",message="path must not contain / or start with ."
 	Path string `json:"path,omitempty"`
 
 	// Host is the URL for the cluster
+	// +kubebuilder:validation:XValidation:rule="isURL(self) && url(self).getScheme() in ['http', 'https']",message="host must be an http or https URL"
 	Host string `json:"host"`
 
 	// CA configuration for the cluster
@@ -20,9 +23,113 @@ type ClusterAccessSpec struct {
 	// Auth configuration for the cluster
 	// +optional
 	Auth *AuthConfig `json:"auth,omitempty"`
+
+	// Proxy configures an authenticating proxy the cluster is reached through
+	// +optional
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// AuthMode selects how the gateway resolves the user it impersonates from the token of a request, from the claims
+	// of the token or with the TokenReview API of the cluster. The mode configured in the gateway is used if not set.
+	// +optional
+	// +kubebuilder:validation:Enum=claims;tokenReview
+	AuthMode string `json:"authMode,omitempty"`
+
+	// ClaimMappings selects the token claims the impersonated user is read from in the claims auth mode. Mappings that
+	// are set replace the ones configured in the gateway.
+	// +optional
+	ClaimMappings *ClaimMappings `json:"claimMappings,omitempty"`
+
+	// Defaults are exposed to frontends via the clusterDefaults query
+	// +optional
+	Defaults *ClusterDefaults `json:"defaults,omitempty"`
+
+	// Features restrict the GraphQL schema the gateway serves for the cluster
+	// +optional
+	Features *ClusterFeatures `json:"features,omitempty"`
+}
+
+// ProxyConfig defines how the cluster is reached through an authenticating proxy, e.g. kube-oidc-proxy or Teleport.
+// Proxies serving the Kubernetes API themselves are set as host, URL is for proxies tunneling the connections.
+type ProxyConfig struct {
+	// URL of an HTTP, HTTPS or SOCKS5 proxy the connections to the host are tunneled through
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// ServerName is sent as SNI and verified against the certificate of the host instead of the host name
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// ClientCertificateRef points to a secret containing the client certificate and key presented to the proxy
+	// +optional
+	ClientCertificateRef *ClientCertificateRef `json:"clientCertificateRef,omitempty"`
+
+	// Headers are added to every request sent to the cluster, e.g. to route the requests in the proxy
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// ClusterDefaults defines presets that generic frontends use to tailor their initial views
+type ClusterDefaults struct {
+	// DefaultNamespace is the namespace that should be selected initially
+	// +optional
+	DefaultNamespace string `json:"defaultNamespace,omitempty"`
+
+	// CommonKinds lists the kinds that are used most often in the cluster, e.g. Deployment or ConfigMap
+	// +optional
+	CommonKinds []string `json:"commonKinds,omitempty"`
+}
+
+// ClusterFeatures defines the operations the gateway serves for the cluster
+type ClusterFeatures struct {
+	// ReadOnly leaves the mutations out of the schema, so that the cluster can only be queried
+	// +optional
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// DisableSubscriptions leaves the subscriptions out of the schema
+	// +optional
+	DisableSubscriptions bool `json:"disableSubscriptions,omitempty"`
+
+	// Denylist lists the kinds left out of the schema, together with their queries, mutations and subscriptions
+	// +optional
+	Denylist []DeniedKind `json:"denylist,omitempty"`
+}
+
+// DeniedKind selects a kind left out of the schema
+type DeniedKind struct {
+	// Group of the kind, empty for the core group
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version of the kind, all versions are left out if not set
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind is the name of the kind, e.g. Secret
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+}
+
+// ClaimMappings defines the token claims of the impersonated user
+type ClaimMappings struct {
+	// Username is the claim holding the name of the user
+	// +optional
+	Username string `json:"username,omitempty"`
+
+	// Groups is the claim holding the groups of the user, a list or a single string
+	// +optional
+	Groups string `json:"groups,omitempty"`
+
+	// UID is the claim holding the UID of the user
+	// +optional
+	UID string `json:"uid,omitempty"`
+
+	// Extra maps the keys of the impersonated extra attributes to the claims holding their values
+	// +optional
+	Extra map[string]string `json:"extra,omitempty"`
 }
 
 // CAConfig defines CA configuration options
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) != has(self.configMapRef)",message="exactly one of secretRef and configMapRef must be set"
 type CAConfig struct {
 	// SecretRef points to a secret containing CA data
 	// +optional
@@ -34,6 +141,7 @@ type CAConfig struct {
 }
 
 // AuthConfig defines authentication configuration options
+// +kubebuilder:validation:XValidation:rule="[has(self.secretRef), has(self.kubeconfigSecretRef), has(self.serviceAccount), has(self.clientCertificateRef), has(self.exec), has(self.cloud)].exists_one(m, m)",message="exactly one of secretRef, kubeconfigSecretRef, serviceAccount, clientCertificateRef, exec and cloud must be set"
 type AuthConfig struct {
 	// SecretRef points to a secret containing auth token
 	// +optional
@@ -50,20 +158,32 @@ type AuthConfig struct {
 	// ClientCertificateRef points to secrets containing client certificate and key for mTLS
 	// +optional
 	ClientCertificateRef *ClientCertificateRef `json:"clientCertificateRef,omitempty"`
+
+	// Exec runs a client-go credential plugin to get the credentials, like the exec section of a kubeconfig user
+	// +optional
+	Exec *ExecConfig `json:"exec,omitempty"`
+
+	// Cloud gets the credentials with the credential plugin of the cloud provider of a managed cluster
+	// +optional
+	Cloud *CloudAuth `json:"cloud,omitempty"`
 }
 
 // SecretRef defines a reference to a secret
 type SecretRef struct {
+	// +kubebuilder:validation:MinLength=1
 	Name      string `json:"name"`
 	Namespace string `json:"namespace,omitempty"`
-	Key       string `json:"key"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // ConfigMapRef defines a reference to a config map
 type ConfigMapRef struct {
+	// +kubebuilder:validation:MinLength=1
 	Name      string `json:"name"`
 	Namespace string `json:"namespace,omitempty"`
-	Key       string `json:"key"`
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
 }
 
 // KubeconfigSecretRef defines a reference to a kubeconfig secret
@@ -78,6 +198,85 @@ type ClientCertificateRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// ExecConfig defines a client-go credential plugin. The command is run by the listener and the gateway, so it must be
+// available in both images.
+type ExecConfig struct {
+	// Command is the executable of the plugin, one of the commands allowed by the exec-commands option of the
+	// listener and the gateway
+	Command string `json:"command"`
+
+	// Args are passed to the command
+	// +optional
+	Args []string `json:"args,omitempty"`
+
+	// Env adds environment variables to the environment of the listener and the gateway when running the command
+	// +optional
+	Env []ExecEnvVar `json:"env,omitempty"`
+
+	// APIVersion is the version of the ExecCredential the plugin returns, client.authentication.k8s.io/v1beta1 if not set
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// ProvideClusterInfo passes the cluster info to the plugin in the KUBERNETES_EXEC_INFO environment variable
+	// +optional
+	ProvideClusterInfo bool `json:"provideClusterInfo,omitempty"`
+}
+
+// ExecEnvVar defines an environment variable of a credential plugin
+type ExecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CloudAuth defines the credentials of a managed cluster, they are issued by the cloud provider for the identity of the
+// listener and the gateway, e.g. with workload identity, so that no tokens have to be stored
+type CloudAuth struct {
+	// Provider is the cloud provider of the cluster
+	// +kubebuilder:validation:Enum=eks;gke;aks
+	Provider string `json:"provider"`
+
+	// ClusterName is the name of an EKS cluster
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// Region is the AWS region of an EKS cluster
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// RoleARN is the IAM role assumed to get the token of an EKS cluster
+	// +optional
+	RoleARN string `json:"roleArn,omitempty"`
+
+	// ServerID is the application ID of the Entra ID server of an AKS cluster, the ID of the Azure Kubernetes Service AAD
+	// Server is used if not set
+	// +optional
+	ServerID string `json:"serverId,omitempty"`
+}
+
+const (
+	// CloudProviderEKS gets tokens with aws eks get-token
+	CloudProviderEKS = "eks"
+	// CloudProviderGKE gets tokens with the gke-gcloud-auth-plugin
+	CloudProviderGKE = "gke"
+	// CloudProviderAKS gets tokens with kubelogin using workload identity
+	CloudProviderAKS = "aks"
+)
+
+const (
+	// AuthModeClaims impersonates the user named by the username claim of the token
+	AuthModeClaims = "claims"
+	// AuthModeTokenReview impersonates the user and groups the cluster's TokenReview API resolves the token to
+	AuthModeTokenReview = "tokenReview"
+)
+
+const (
+	// ConditionSchemaGenerated reports whether the schema of the cluster was generated and stored for the gateway
+	ConditionSchemaGenerated = "SchemaGenerated"
+	// ConditionAuthError reports whether the listener failed to authenticate against the cluster,
+	// e.g. because a referenced secret is missing or the API server rejected the credentials
+	ConditionAuthError = "AuthError"
+)
+
 // ClusterAccessStatus defines the observed state of ClusterAccess
 type ClusterAccessStatus struct {
 	// Conditions represent the latest available observations of the cluster access state
@@ -6,7 +6,6 @@ import (
 	"errors"
 	"fmt"
 
-	authv1 "k8s.io/api/authentication/v1"
 	corev1 "k8s.io/api/core/v1"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -253,45 +252,23 @@ func ConfigureAuthentication(ctx context.Context, config *rest.Config, auth *gat
 	}
 
 	if auth.ServiceAccount != nil {
-		var expirationSeconds int64
-		if auth.ServiceAccount.TokenExpiration != nil {
-			// If TokenExpiration is provided, use its value
-			expirationSeconds = int64(auth.ServiceAccount.TokenExpiration.Duration.Seconds())
-		} else {
-			// If TokenExpiration is nil, use the desired default (3600 seconds = 1 hour)
-			expirationSeconds = 3600
-			fmt.Println("Warning: auth.ServiceAccount.TokenExpiration is nil, defaulting to 3600 seconds.")
-		}
-
-		// Build the TokenRequest object
-		tokenRequest := &authv1.TokenRequest{
-			Spec: authv1.TokenRequestSpec{
-				Audiences: auth.ServiceAccount.Audience,
-				// Optionally set ExpirationSeconds, BoundObjectRef, etc.
-				ExpirationSeconds: &expirationSeconds,
-			},
-		}
-
-		// Get the service account token using the Kubernetes API
-		sa := &corev1.ServiceAccount{}
-		err := k8sClient.Get(ctx, types.NamespacedName{
-			Name:      auth.ServiceAccount.Name,
-			Namespace: auth.ServiceAccount.Namespace,
-		}, sa)
+		// Tokens are requested from the management cluster and refreshed before they expire
+		token, err := serviceAccountTokens.Token(ctx, k8sClient, auth.ServiceAccount)
 		if err != nil {
-			return errors.Join(errors.New("failed to get service account"), err)
+			return err
 		}
 
-		err = k8sClient.SubResource("token").Create(ctx, sa, tokenRequest)
-		if err != nil {
-			return errors.Join(errors.New("failed to create token request for service account"), err)
-		}
-
-		if tokenRequest.Status.Token == "" {
-			return errors.New("received empty token from TokenRequest API")
-		}
+		config.BearerToken = token.Token
+		return nil
+	}
 
-		config.BearerToken = tokenRequest.Status.Token
+	execConfig, err := ExecConfigFor(auth)
+	if err != nil {
+		return err
+	}
+	if execConfig != nil {
+		// The plugin is run by client-go whenever a new token is needed
+		config.ExecProvider = ExecProvider(execConfig)
 		return nil
 	}
 
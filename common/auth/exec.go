@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+// DefaultExecAPIVersion is the ExecCredential version of credential plugins that don't set one, the version the
+// plugins of the cloud providers return
+const DefaultExecAPIVersion = "client.authentication.k8s.io/v1beta1"
+
+// aksServerID is the application ID of the Azure Kubernetes Service AAD Server, the audience of AKS tokens
+const aksServerID = "6dae42f8-4368-4678-94ff-3960e28e3630"
+
+// DefaultExecCommands are the commands credential plugins may run unless SetExecCommands is called, the plugins of
+// the cloud auth
+var DefaultExecCommands = []string{"aws", "gke-gcloud-auth-plugin", "kubelogin"}
+
+var (
+	ErrInvalidCloudAuth      = errors.New("invalid cloud auth")
+	ErrExecCommandNotAllowed = errors.New("exec command not allowed")
+)
+
+// execCommands are the commands set by SetExecCommands, DefaultExecCommands are used while it is nil
+var execCommands atomic.Pointer[[]string]
+
+// SetExecCommands sets the commands credential plugins may run. The commands are compared as they are written in the
+// ClusterAccess, so that a plugin can't run a binary at another path with the same name.
+func SetExecCommands(commands []string) {
+	commands = slices.Clone(commands)
+	execCommands.Store(&commands)
+}
+
+// ExecCommands returns the commands credential plugins may run
+func ExecCommands() []string {
+	if commands := execCommands.Load(); commands != nil {
+		return slices.Clone(*commands)
+	}
+	return slices.Clone(DefaultExecCommands)
+}
+
+// CheckExecCommand returns ErrExecCommandNotAllowed if credential plugins may not run the command. ClusterAccesses,
+// the schema files and the registrations of the admin API are written by others than the operator of the listener and
+// the gateway, so the commands are checked whenever a plugin is configured.
+func CheckExecCommand(command string) error {
+	allowed := ExecCommands()
+	if !slices.Contains(allowed, command) {
+		return fmt.Errorf("%w: %q, allowed are %q", ErrExecCommandNotAllowed, command, strings.Join(allowed, ","))
+	}
+	return nil
+}
+
+// ExecConfigFor returns the credential plugin of the exec or cloud auth, nil if neither is configured. It fails if
+// the plugin runs a command that isn't allowed, see CheckExecCommand.
+func ExecConfigFor(auth *gatewayv1alpha1.AuthConfig) (*gatewayv1alpha1.ExecConfig, error) {
+	if auth == nil {
+		return nil, nil
+	}
+
+	execConfig := auth.Exec
+	if execConfig != nil && execConfig.Command == "" {
+		return nil, errors.New("exec command is required")
+	}
+	if execConfig == nil && auth.Cloud != nil {
+		var err error
+		if execConfig, err = cloudExecConfig(auth.Cloud); err != nil {
+			return nil, err
+		}
+	}
+	if execConfig == nil {
+		return nil, nil
+	}
+
+	if err := CheckExecCommand(execConfig.Command); err != nil {
+		return nil, err
+	}
+	return execConfig, nil
+}
+
+// cloudExecConfig returns the credential plugin of a cloud provider. The plugins authenticate with the identity of the
+// pod, e.g. IRSA or EKS pod identity, GKE workload identity and Azure workload identity.
+func cloudExecConfig(cloud *gatewayv1alpha1.CloudAuth) (*gatewayv1alpha1.ExecConfig, error) {
+	switch cloud.Provider {
+	case gatewayv1alpha1.CloudProviderEKS:
+		if cloud.ClusterName == "" {
+			return nil, fmt.Errorf("%w: clusterName is required for eks", ErrInvalidCloudAuth)
+		}
+		args := []string{"eks", "get-token", "--cluster-name", cloud.ClusterName, "--output", "json"}
+		if cloud.Region != "" {
+			args = append(args, "--region", cloud.Region)
+		}
+		if cloud.RoleARN != "" {
+			args = append(args, "--role-arn", cloud.RoleARN)
+		}
+		return &gatewayv1alpha1.ExecConfig{Command: "aws", Args: args, APIVersion: DefaultExecAPIVersion}, nil
+	case gatewayv1alpha1.CloudProviderGKE:
+		return &gatewayv1alpha1.ExecConfig{Command: "gke-gcloud-auth-plugin", APIVersion: DefaultExecAPIVersion, ProvideClusterInfo: true}, nil
+	case gatewayv1alpha1.CloudProviderAKS:
+		serverID := cloud.ServerID
+		if serverID == "" {
+			serverID = aksServerID
+		}
+		return &gatewayv1alpha1.ExecConfig{
+			Command:    "kubelogin",
+			Args:       []string{"get-token", "--login", "workloadidentity", "--server-id", serverID},
+			APIVersion: DefaultExecAPIVersion,
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown provider %q", ErrInvalidCloudAuth, cloud.Provider)
+	}
+}
+
+// ExecProvider converts a credential plugin to the exec config of client-go. The plugins run without a terminal, so
+// they are never interactive.
+func ExecProvider(exec *gatewayv1alpha1.ExecConfig) *clientcmdapi.ExecConfig {
+	apiVersion := exec.APIVersion
+	if apiVersion == "" {
+		apiVersion = DefaultExecAPIVersion
+	}
+
+	env := make([]clientcmdapi.ExecEnvVar, 0, len(exec.Env))
+	for _, envVar := range exec.Env {
+		env = append(env, clientcmdapi.ExecEnvVar{Name: envVar.Name, Value: envVar.Value})
+	}
+
+	return &clientcmdapi.ExecConfig{
+		Command:            exec.Command,
+		Args:               append([]string(nil), exec.Args...),
+		Env:                env,
+		APIVersion:         apiVersion,
+		ProvideClusterInfo: exec.ProvideClusterInfo,
+		InteractiveMode:    clientcmdapi.NeverExecInteractiveMode,
+	}
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+func TestExecConfigFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     *gatewayv1alpha1.AuthConfig
+		expected *gatewayv1alpha1.ExecConfig
+		wantErr  error
+	}{
+		{
+			name: "no_plugin",
+			auth: &gatewayv1alpha1.AuthConfig{},
+		},
+		{
+			name:     "exec",
+			auth:     &gatewayv1alpha1.AuthConfig{Exec: &gatewayv1alpha1.ExecConfig{Command: "kubelogin", Args: []string{"get-token", "--login", "azurecli"}}},
+			expected: &gatewayv1alpha1.ExecConfig{Command: "kubelogin", Args: []string{"get-token", "--login", "azurecli"}},
+		},
+		{
+			name:    "exec_command_not_allowed",
+			auth:    &gatewayv1alpha1.AuthConfig{Exec: &gatewayv1alpha1.ExecConfig{Command: "sh", Args: []string{"-c", "cat /var/run/secrets/kubernetes.io/serviceaccount/token"}}},
+			wantErr: ErrExecCommandNotAllowed,
+		},
+		{
+			name:    "exec_command_at_another_path",
+			auth:    &gatewayv1alpha1.AuthConfig{Exec: &gatewayv1alpha1.ExecConfig{Command: "/tmp/aws"}},
+			wantErr: ErrExecCommandNotAllowed,
+		},
+		{
+			name: "eks",
+			auth: &gatewayv1alpha1.AuthConfig{Cloud: &gatewayv1alpha1.CloudAuth{
+				Provider:    gatewayv1alpha1.CloudProviderEKS,
+				ClusterName: "prod",
+				Region:      "eu-central-1",
+				RoleARN:     "arn:aws:iam::123456789012:role/gateway",
+			}},
+			expected: &gatewayv1alpha1.ExecConfig{
+				Command: "aws",
+				Args: []string{
+					"eks", "get-token", "--cluster-name", "prod", "--output", "json",
+					"--region", "eu-central-1", "--role-arn", "arn:aws:iam::123456789012:role/gateway",
+				},
+				APIVersion: DefaultExecAPIVersion,
+			},
+		},
+		{
+			name: "aks_default_server_id",
+			auth: &gatewayv1alpha1.AuthConfig{Cloud: &gatewayv1alpha1.CloudAuth{Provider: gatewayv1alpha1.CloudProviderAKS}},
+			expected: &gatewayv1alpha1.ExecConfig{
+				Command:    "kubelogin",
+				Args:       []string{"get-token", "--login", "workloadidentity", "--server-id", aksServerID},
+				APIVersion: DefaultExecAPIVersion,
+			},
+		},
+		{
+			name:    "eks_without_cluster_name",
+			auth:    &gatewayv1alpha1.AuthConfig{Cloud: &gatewayv1alpha1.CloudAuth{Provider: gatewayv1alpha1.CloudProviderEKS}},
+			wantErr: ErrInvalidCloudAuth,
+		},
+		{
+			name:    "unknown_provider",
+			auth:    &gatewayv1alpha1.AuthConfig{Cloud: &gatewayv1alpha1.CloudAuth{Provider: "openstack"}},
+			wantErr: ErrInvalidCloudAuth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			execConfig, err := ExecConfigFor(tt.auth)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, execConfig)
+		})
+	}
+}
+
+func TestSetExecCommands(t *testing.T) {
+	t.Cleanup(func() { SetExecCommands(DefaultExecCommands) })
+
+	SetExecCommands([]string{"/usr/local/bin/token-plugin"})
+	assert.Equal(t, []string{"/usr/local/bin/token-plugin"}, ExecCommands())
+	assert.NoError(t, CheckExecCommand("/usr/local/bin/token-plugin"))
+
+	// The plugins of the cloud auth have to be allowed as well
+	_, err := ExecConfigFor(&gatewayv1alpha1.AuthConfig{Cloud: &gatewayv1alpha1.CloudAuth{Provider: gatewayv1alpha1.CloudProviderGKE}})
+	assert.ErrorIs(t, err, ErrExecCommandNotAllowed)
+
+	SetExecCommands(nil)
+	assert.ErrorIs(t, CheckExecCommand("aws"), ErrExecCommandNotAllowed)
+}
+
+func TestConfigureAuthentication_Exec(t *testing.T) {
+	config := &rest.Config{Host: "https://test.example.com"}
+	auth := &gatewayv1alpha1.AuthConfig{Exec: &gatewayv1alpha1.ExecConfig{
+		Command: "aws",
+		Env:     []gatewayv1alpha1.ExecEnvVar{{Name: "PROFILE", Value: "gateway"}},
+	}}
+
+	require.NoError(t, ConfigureAuthentication(t.Context(), config, auth, nil))
+	assert.Equal(t, &clientcmdapi.ExecConfig{
+		Command:         "aws",
+		Env:             []clientcmdapi.ExecEnvVar{{Name: "PROFILE", Value: "gateway"}},
+		APIVersion:      DefaultExecAPIVersion,
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}, config.ExecProvider)
+	assert.Empty(t, config.BearerToken)
+}
@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
 )
 
@@ -26,6 +27,17 @@ type MetadataInjectionConfig struct {
 	Auth         *gatewayv1alpha1.AuthConfig
 	CA           *gatewayv1alpha1.CAConfig
 	HostOverride string // For virtual workspaces
+	Defaults     *gatewayv1alpha1.ClusterDefaults
+	// AuthMode is the auth mode of the ClusterAccess, the gateway's configured mode is used if empty
+	AuthMode string
+	// ClaimMappings are the claim mappings of the ClusterAccess, the gateway's configured mappings are used if nil
+	ClaimMappings *gatewayv1alpha1.ClaimMappings
+	// Labels are the labels of the ClusterAccess, the gateway groups clusters by them
+	Labels map[string]string
+	// Proxy is the authenticating proxy the cluster is reached through
+	Proxy *gatewayv1alpha1.ProxyConfig
+	// Features restrict the schema the gateway serves for the cluster
+	Features *gatewayv1alpha1.ClusterFeatures
 }
 
 // MetadataInjector provides metadata injection services with structured logging
@@ -70,6 +82,34 @@ func (m *MetadataInjector) InjectClusterMetadata(ctx context.Context, schemaJSON
 		}
 	}
 
+	if config.Defaults != nil {
+		metadata["defaults"] = config.Defaults
+	}
+
+	if config.AuthMode != "" {
+		metadata["authMode"] = config.AuthMode
+	}
+
+	if config.ClaimMappings != nil {
+		metadata["claimMappings"] = config.ClaimMappings
+	}
+
+	if len(config.Labels) > 0 {
+		metadata["labels"] = config.Labels
+	}
+
+	if config.Features != nil {
+		metadata["features"] = config.Features
+	}
+
+	if config.Proxy != nil {
+		proxyMetadata, err := ExtractProxyMetadata(ctx, config.Proxy, m.client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to extract proxy data for metadata: %w", err)
+		}
+		metadata["proxy"] = proxyMetadata
+	}
+
 	// Add CA data - prefer explicit CA config, fallback to kubeconfig CA
 	if config.CA != nil {
 		caData, err := ExtractCAData(ctx, config.CA, m.client)
@@ -150,6 +190,22 @@ func (m *MetadataInjector) extractAuthDataForMetadata(ctx context.Context, auth
 		return m.extractClientCertAuth(ctx, auth.ClientCertificateRef)
 	}
 
+	if auth.ServiceAccount != nil {
+		return m.extractServiceAccountAuth(ctx, auth.ServiceAccount)
+	}
+
+	// Credential plugins are run by the gateway itself, only their configuration is stored
+	execConfig, err := ExecConfigFor(auth)
+	if err != nil {
+		return nil, err
+	}
+	if execConfig != nil {
+		return map[string]interface{}{
+			"type": "exec",
+			"exec": execConfig,
+		}, nil
+	}
+
 	return nil, nil // No auth configured
 }
 
@@ -210,6 +266,19 @@ func (m *MetadataInjector) extractClientCertAuth(ctx context.Context, certRef *g
 	}, nil
 }
 
+// extractServiceAccountAuth handles token-based authentication with a token issued for ServiceAccount
+func (m *MetadataInjector) extractServiceAccountAuth(ctx context.Context, saRef *gatewayv1alpha1.ServiceAccountRef) (map[string]interface{}, error) {
+	token, err := serviceAccountTokens.Token(ctx, m.client, saRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service account token: %w", err)
+	}
+
+	return map[string]interface{}{
+		"type":  "token",
+		"token": base64.StdEncoding.EncodeToString([]byte(token.Token)),
+	}, nil
+}
+
 // getSecret is a helper function to retrieve secrets with namespace defaulting
 func (m *MetadataInjector) getSecret(ctx context.Context, name, namespace string) (*corev1.Secret, error) {
 	if namespace == "" {
@@ -439,6 +508,8 @@ func (m *MetadataInjector) determineKCPHost(kubeconfigHost, override, clusterPat
 func (m *MetadataInjector) finalizeSchemaInjection(schemaData map[string]interface{}, metadata map[string]interface{}, host, path string, hasCA bool) ([]byte, error) {
 	// Inject the metadata into the schema
 	schemaData["x-cluster-metadata"] = metadata
+	// Allow the gateway to detect schema files it is too old to serve
+	schemaData[common.SchemaRequirementsKey] = common.CurrentSchemaRequirements()
 
 	// Marshal back to JSON
 	modifiedJSON, err := json.Marshal(schemaData)
@@ -312,6 +312,109 @@ func TestInjectClusterMetadata(t *testing.T) {
 	}
 }
 
+func TestInjectClusterMetadata_Defaults(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+
+	config := MetadataInjectionConfig{
+		Host: "https://test-cluster.example.com:6443",
+		Path: "test-cluster",
+		Defaults: &gatewayv1alpha1.ClusterDefaults{
+			DefaultNamespace: "team-a",
+			CommonKinds:      []string{"Deployment", "ConfigMap"},
+		},
+	}
+
+	result, err := InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	var resultData struct {
+		Metadata struct {
+			Defaults gatewayv1alpha1.ClusterDefaults `json:"defaults"`
+		} `json:"x-cluster-metadata"`
+	}
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.Equal(t, *config.Defaults, resultData.Metadata.Defaults)
+}
+
+func TestInjectClusterMetadata_Labels(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+
+	config := MetadataInjectionConfig{
+		Host:   "https://test-cluster.example.com:6443",
+		Path:   "test-cluster",
+		Labels: map[string]string{"region": "eu", "tier": "prod"},
+	}
+
+	result, err := InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	var resultData struct {
+		Metadata map[string]interface{} `json:"x-cluster-metadata"`
+	}
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.Equal(t, map[string]interface{}{"region": "eu", "tier": "prod"}, resultData.Metadata["labels"])
+
+	config.Labels = nil
+	result, err = InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	resultData.Metadata = nil
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.NotContains(t, resultData.Metadata, "labels")
+}
+
+func TestInjectClusterMetadata_Features(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+
+	config := MetadataInjectionConfig{
+		Host: "https://test-cluster.example.com:6443",
+		Path: "test-cluster",
+		Features: &gatewayv1alpha1.ClusterFeatures{
+			ReadOnly:             true,
+			DisableSubscriptions: true,
+			Denylist:             []gatewayv1alpha1.DeniedKind{{Kind: "Secret"}, {Group: "apps", Version: "v1", Kind: "Deployment"}},
+		},
+	}
+
+	result, err := InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	var resultData struct {
+		Metadata struct {
+			Features *gatewayv1alpha1.ClusterFeatures `json:"features"`
+		} `json:"x-cluster-metadata"`
+	}
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.Equal(t, config.Features, resultData.Metadata.Features)
+
+	config.Features = nil
+	result, err = InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	resultData.Metadata.Features = nil
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.Nil(t, resultData.Metadata.Features)
+}
+
+func TestInjectClusterMetadata_AuthMode(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+
+	config := MetadataInjectionConfig{
+		Host:     "https://test-cluster.example.com:6443",
+		Path:     "test-cluster",
+		AuthMode: gatewayv1alpha1.AuthModeTokenReview,
+	}
+
+	result, err := InjectClusterMetadata(t.Context(), []byte(`{"definitions": {}}`), config, nil, log)
+	require.NoError(t, err)
+
+	var resultData struct {
+		Metadata map[string]interface{} `json:"x-cluster-metadata"`
+	}
+	require.NoError(t, json.Unmarshal(result, &resultData))
+	assert.Equal(t, "tokenReview", resultData.Metadata["authMode"])
+}
+
 func TestExtractKubeconfigFromEnv(t *testing.T) {
 	log := testlogger.New().HideLogOutput().Logger
 
@@ -622,4 +725,21 @@ users:
 		assert.Contains(t, err.Error(), "failed to get client certificate secret")
 		assert.Nil(t, result)
 	})
+
+	t.Run("cloud_auth", func(t *testing.T) {
+		auth := &gatewayv1alpha1.AuthConfig{
+			Cloud: &gatewayv1alpha1.CloudAuth{Provider: gatewayv1alpha1.CloudProviderGKE},
+		}
+
+		result, err := extractAuthDataForMetadata(ctx, auth, nil)
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+
+		assert.Equal(t, "exec", result["type"])
+		assert.Equal(t, &gatewayv1alpha1.ExecConfig{
+			Command:            "gke-gcloud-auth-plugin",
+			APIVersion:         DefaultExecAPIVersion,
+			ProvideClusterInfo: true,
+		}, result["exec"])
+	})
 }
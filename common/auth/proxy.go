@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+var (
+	ErrInvalidProxy         = errors.New("invalid proxy config")
+	ErrProxyCertificateUsed = errors.New("the proxy client certificate can't be combined with client certificate auth")
+)
+
+// ProxyMetadata is the proxy config as stored in the schema metadata, with the client certificate read from its
+// secret and base64-encoded like the other metadata
+type ProxyMetadata struct {
+	URL        string            `json:"url,omitempty"`
+	ServerName string            `json:"serverName,omitempty"`
+	CertData   string            `json:"certData,omitempty"`
+	KeyData    string            `json:"keyData,omitempty"`
+	Headers    map[string]string `json:"headers,omitempty"`
+}
+
+// ExtractProxyMetadata resolves the proxy config of a ClusterAccess, nil if none is configured
+func ExtractProxyMetadata(ctx context.Context, proxy *gatewayv1alpha1.ProxyConfig, k8sClient client.Client) (*ProxyMetadata, error) {
+	if proxy == nil {
+		return nil, nil
+	}
+
+	metadata := &ProxyMetadata{
+		URL:        proxy.URL,
+		ServerName: proxy.ServerName,
+		Headers:    proxy.Headers,
+	}
+
+	if proxy.ClientCertificateRef != nil {
+		namespace := proxy.ClientCertificateRef.Namespace
+		if namespace == "" {
+			namespace = "default"
+		}
+
+		secret := &corev1.Secret{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: proxy.ClientCertificateRef.Name, Namespace: namespace}, secret)
+		if err != nil {
+			return nil, errors.Join(errors.New("failed to get proxy client certificate secret"), err)
+		}
+
+		certData, certOk := secret.Data["tls.crt"]
+		keyData, keyOk := secret.Data["tls.key"]
+		if !certOk || !keyOk {
+			return nil, errors.New("proxy client certificate or key not found in secret")
+		}
+
+		metadata.CertData = base64.StdEncoding.EncodeToString(certData)
+		metadata.KeyData = base64.StdEncoding.EncodeToString(keyData)
+	}
+
+	return metadata, nil
+}
+
+// ConfigureProxy applies the proxy config to a rest.Config whose authentication is configured already.
+// The client certificate is presented in the TLS handshakes with the proxy and the host, so it replaces the client
+// certificate of the cluster, which is why both can't be set.
+func ConfigureProxy(config *rest.Config, proxy *ProxyMetadata) error {
+	if proxy == nil {
+		return nil
+	}
+
+	if proxy.URL != "" {
+		proxyURL, err := url.Parse(proxy.URL)
+		if err != nil {
+			return fmt.Errorf("%w: url: %w", ErrInvalidProxy, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("%w: url scheme must be http, https or socks5, got %q", ErrInvalidProxy, proxyURL.Scheme)
+		}
+		config.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if proxy.ServerName != "" {
+		config.TLSClientConfig.ServerName = proxy.ServerName
+	}
+
+	if proxy.CertData != "" || proxy.KeyData != "" {
+		if len(config.TLSClientConfig.CertData) > 0 || config.TLSClientConfig.CertFile != "" {
+			return ErrProxyCertificateUsed
+		}
+		certData, err := base64.StdEncoding.DecodeString(proxy.CertData)
+		if err != nil {
+			return fmt.Errorf("failed to decode proxy cert data: %w", err)
+		}
+		keyData, err := base64.StdEncoding.DecodeString(proxy.KeyData)
+		if err != nil {
+			return fmt.Errorf("failed to decode proxy key data: %w", err)
+		}
+		config.TLSClientConfig.CertData = certData
+		config.TLSClientConfig.KeyData = keyData
+	}
+
+	if len(proxy.Headers) > 0 {
+		headers := proxy.Headers
+		config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &headerRoundTripper{headers: headers, next: rt}
+		})
+	}
+
+	return nil
+}
+
+// ConfigureProxyFromSpec resolves the proxy config of a ClusterAccess and applies it to a rest.Config
+func ConfigureProxyFromSpec(ctx context.Context, config *rest.Config, proxy *gatewayv1alpha1.ProxyConfig, k8sClient client.Client) error {
+	metadata, err := ExtractProxyMetadata(ctx, proxy, k8sClient)
+	if err != nil {
+		return err
+	}
+	return ConfigureProxy(config, metadata)
+}
+
+// headerRoundTripper adds the configured headers to every request
+type headerRoundTripper struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+func (rt *headerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range rt.headers {
+		req.Header.Set(name, value)
+	}
+	return rt.next.RoundTrip(req)
+}
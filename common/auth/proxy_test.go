@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+func TestExtractProxyMetadata(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "proxy-cert", Namespace: "default"},
+		Data:       map[string][]byte{"tls.crt": []byte("cert"), "tls.key": []byte("key")},
+	}
+	k8sClient := fake.NewClientBuilder().WithObjects(secret).Build()
+
+	metadata, err := ExtractProxyMetadata(t.Context(), &gatewayv1alpha1.ProxyConfig{
+		URL:                  "https://proxy.example.com:3128",
+		ServerName:           "kubernetes.example.com",
+		ClientCertificateRef: &gatewayv1alpha1.ClientCertificateRef{Name: "proxy-cert"},
+		Headers:              map[string]string{"X-Cluster": "prod"},
+	}, k8sClient)
+	require.NoError(t, err)
+	assert.Equal(t, &ProxyMetadata{
+		URL:        "https://proxy.example.com:3128",
+		ServerName: "kubernetes.example.com",
+		CertData:   base64.StdEncoding.EncodeToString([]byte("cert")),
+		KeyData:    base64.StdEncoding.EncodeToString([]byte("key")),
+		Headers:    map[string]string{"X-Cluster": "prod"},
+	}, metadata)
+
+	_, err = ExtractProxyMetadata(t.Context(), &gatewayv1alpha1.ProxyConfig{
+		ClientCertificateRef: &gatewayv1alpha1.ClientCertificateRef{Name: "missing"},
+	}, k8sClient)
+	assert.ErrorContains(t, err, "failed to get proxy client certificate secret")
+
+	metadata, err = ExtractProxyMetadata(t.Context(), nil, k8sClient)
+	require.NoError(t, err)
+	assert.Nil(t, metadata)
+}
+
+func TestConfigureProxy(t *testing.T) {
+	encode := func(data string) string {
+		return base64.StdEncoding.EncodeToString([]byte(data))
+	}
+
+	tests := []struct {
+		name      string
+		config    rest.Config
+		proxy     *ProxyMetadata
+		wantErr   error
+		wantCheck func(t *testing.T, config *rest.Config)
+	}{
+		{
+			name:  "proxy_url_and_server_name",
+			proxy: &ProxyMetadata{URL: "socks5://proxy.example.com:1080", ServerName: "kubernetes.example.com"},
+			wantCheck: func(t *testing.T, config *rest.Config) {
+				require.NotNil(t, config.Proxy)
+				proxyURL, err := config.Proxy(httptest.NewRequest(http.MethodGet, "https://cluster.example.com", nil))
+				require.NoError(t, err)
+				assert.Equal(t, "socks5://proxy.example.com:1080", proxyURL.String())
+				assert.Equal(t, "kubernetes.example.com", config.TLSClientConfig.ServerName)
+			},
+		},
+		{
+			name:  "client_certificate",
+			proxy: &ProxyMetadata{CertData: encode("cert"), KeyData: encode("key")},
+			wantCheck: func(t *testing.T, config *rest.Config) {
+				assert.Equal(t, []byte("cert"), config.TLSClientConfig.CertData)
+				assert.Equal(t, []byte("key"), config.TLSClientConfig.KeyData)
+			},
+		},
+		{
+			name:    "client_certificate_auth_ERROR",
+			config:  rest.Config{TLSClientConfig: rest.TLSClientConfig{CertData: []byte("cluster-cert")}},
+			proxy:   &ProxyMetadata{CertData: encode("cert"), KeyData: encode("key")},
+			wantErr: ErrProxyCertificateUsed,
+		},
+		{
+			name:    "unsupported_scheme_ERROR",
+			proxy:   &ProxyMetadata{URL: "ftp://proxy.example.com"},
+			wantErr: ErrInvalidProxy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			err := ConfigureProxy(&config, tt.proxy)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.wantCheck(t, &config)
+		})
+	}
+}
+
+func TestConfigureProxy_Headers(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	config := &rest.Config{Host: server.URL}
+	require.NoError(t, ConfigureProxy(config, &ProxyMetadata{Headers: map[string]string{"X-Cluster": "prod"}}))
+
+	httpClient, err := rest.HTTPClientFor(config)
+	require.NoError(t, err)
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, "prod", received.Get("X-Cluster"))
+}
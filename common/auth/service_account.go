@@ -0,0 +1,160 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+// DefaultServiceAccountTokenExpiration is the lifetime of service account tokens whose ClusterAccess doesn't set one
+const DefaultServiceAccountTokenExpiration = time.Hour
+
+// serviceAccountTokenRefreshRatio is the share of the lifetime after which a token is replaced
+const serviceAccountTokenRefreshRatio = 0.8
+
+// ServiceAccountToken is a token issued by the TokenRequest API
+type ServiceAccountToken struct {
+	Token     string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
+
+// RefreshAt returns when the token should be replaced, which leaves a fifth of its lifetime for the new token to be rolled out
+func (t ServiceAccountToken) RefreshAt() time.Time {
+	lifetime := t.ExpiresAt.Sub(t.IssuedAt)
+	return t.IssuedAt.Add(time.Duration(float64(lifetime) * serviceAccountTokenRefreshRatio))
+}
+
+// ServiceAccountTokenCache reuses the tokens of service accounts until they are due for a refresh,
+// so that reconciling a ClusterAccess again doesn't produce a new schema every time
+type ServiceAccountTokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]ServiceAccountToken
+	now    func() time.Time
+}
+
+// NewServiceAccountTokenCache creates an empty token cache
+func NewServiceAccountTokenCache() *ServiceAccountTokenCache {
+	return &ServiceAccountTokenCache{
+		tokens: make(map[string]ServiceAccountToken),
+		now:    time.Now,
+	}
+}
+
+// serviceAccountTokens is shared by the config builder and the metadata injector, so that both use the same token
+var serviceAccountTokens = NewServiceAccountTokenCache()
+
+// Token returns the cached token of the service account, or requests a new one if there is none or it is due for a refresh
+func (c *ServiceAccountTokenCache) Token(ctx context.Context, k8sClient client.Client, ref *gatewayv1alpha1.ServiceAccountRef) (ServiceAccountToken, error) {
+	key := serviceAccountTokenKey(ref)
+
+	c.mu.Lock()
+	token, ok := c.tokens[key]
+	c.mu.Unlock()
+	if ok && c.now().Before(token.RefreshAt()) {
+		return token, nil
+	}
+
+	token, err := RequestServiceAccountToken(ctx, k8sClient, ref, c.now())
+	if err != nil {
+		return ServiceAccountToken{}, err
+	}
+
+	c.mu.Lock()
+	c.tokens[key] = token
+	c.mu.Unlock()
+
+	return token, nil
+}
+
+// RefreshAt returns when the cached token of the service account is due for a refresh, false if no token is cached
+func (c *ServiceAccountTokenCache) RefreshAt(ref *gatewayv1alpha1.ServiceAccountRef) (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[serviceAccountTokenKey(ref)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return token.RefreshAt(), true
+}
+
+// ServiceAccountTokenRefreshAt returns when the token used for the service account of the auth config is due for a refresh,
+// false if the auth config doesn't use a service account
+func ServiceAccountTokenRefreshAt(auth *gatewayv1alpha1.AuthConfig) (time.Time, bool) {
+	if auth == nil || auth.ServiceAccount == nil {
+		return time.Time{}, false
+	}
+	return serviceAccountTokens.RefreshAt(auth.ServiceAccount)
+}
+
+// RequestServiceAccountToken creates a token for the service account with the TokenRequest API of the cluster of k8sClient
+func RequestServiceAccountToken(ctx context.Context, k8sClient client.Client, ref *gatewayv1alpha1.ServiceAccountRef, now time.Time) (ServiceAccountToken, error) {
+	if ref.Name == "" {
+		return ServiceAccountToken{}, errors.New("service account name is required")
+	}
+
+	expiration := DefaultServiceAccountTokenExpiration
+	if ref.TokenExpiration != nil {
+		expiration = ref.TokenExpiration.Duration
+	}
+	expirationSeconds := int64(expiration.Seconds())
+
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	sa := &corev1.ServiceAccount{}
+	err := k8sClient.Get(ctx, types.NamespacedName{
+		Name:      ref.Name,
+		Namespace: namespace,
+	}, sa)
+	if err != nil {
+		return ServiceAccountToken{}, errors.Join(errors.New("failed to get service account"), err)
+	}
+
+	tokenRequest := &authv1.TokenRequest{
+		Spec: authv1.TokenRequestSpec{
+			Audiences:         ref.Audience,
+			ExpirationSeconds: &expirationSeconds,
+		},
+	}
+	if err := k8sClient.SubResource("token").Create(ctx, sa, tokenRequest); err != nil {
+		return ServiceAccountToken{}, errors.Join(errors.New("failed to create token request for service account"), err)
+	}
+
+	if tokenRequest.Status.Token == "" {
+		return ServiceAccountToken{}, errors.New("received empty token from TokenRequest API")
+	}
+
+	// The API server may shorten the requested lifetime
+	expiresAt := tokenRequest.Status.ExpirationTimestamp.Time
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(expiration)
+	}
+
+	return ServiceAccountToken{
+		Token:     tokenRequest.Status.Token,
+		IssuedAt:  now,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func serviceAccountTokenKey(ref *gatewayv1alpha1.ServiceAccountRef) string {
+	expiration := DefaultServiceAccountTokenExpiration
+	if ref.TokenExpiration != nil {
+		expiration = ref.TokenExpiration.Duration
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", ref.Namespace, ref.Name, strings.Join(ref.Audience, ","), expiration)
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+// newTokenClient returns a client that issues numbered tokens for the service account default/gateway
+func newTokenClient(t *testing.T, requests *int, lifetime time.Duration, now func() time.Time) client.Client {
+	t.Helper()
+
+	sa := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: "gateway", Namespace: "default"}}
+	return fake.NewClientBuilder().
+		WithObjects(sa).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				if subResourceName != "token" {
+					return errors.New("unexpected subresource " + subResourceName)
+				}
+				*requests++
+				tokenRequest := subResource.(*authv1.TokenRequest)
+				tokenRequest.Status.Token = "token-" + strconv.Itoa(*requests)
+				if lifetime > 0 {
+					tokenRequest.Status.ExpirationTimestamp = metav1.NewTime(now().Add(lifetime))
+				}
+				return nil
+			},
+		}).
+		Build()
+}
+
+func TestServiceAccountTokenCache(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		ref          *gatewayv1alpha1.ServiceAccountRef
+		lifetime     time.Duration
+		elapsed      time.Duration
+		wantRequests int
+		wantToken    string
+		wantErr      string
+	}{
+		{
+			name:         "reuses_token_before_refresh",
+			ref:          &gatewayv1alpha1.ServiceAccountRef{Name: "gateway", Namespace: "default"},
+			lifetime:     time.Hour,
+			elapsed:      47 * time.Minute,
+			wantRequests: 1,
+			wantToken:    "token-1",
+		},
+		{
+			name:         "refreshes_token_after_80_percent_of_lifetime",
+			ref:          &gatewayv1alpha1.ServiceAccountRef{Name: "gateway", Namespace: "default"},
+			lifetime:     time.Hour,
+			elapsed:      48 * time.Minute,
+			wantRequests: 2,
+			wantToken:    "token-2",
+		},
+		{
+			name:         "uses_lifetime_shortened_by_api_server",
+			ref:          &gatewayv1alpha1.ServiceAccountRef{Name: "gateway", Namespace: "default", TokenExpiration: &metav1.Duration{Duration: 24 * time.Hour}},
+			lifetime:     10 * time.Minute,
+			elapsed:      9 * time.Minute,
+			wantRequests: 2,
+			wantToken:    "token-2",
+		},
+		{
+			name:         "defaults_to_requested_lifetime_without_expiration_in_response",
+			ref:          &gatewayv1alpha1.ServiceAccountRef{Name: "gateway"},
+			elapsed:      30 * time.Minute,
+			wantRequests: 1,
+			wantToken:    "token-1",
+		},
+		{
+			name:    "missing_service_account",
+			ref:     &gatewayv1alpha1.ServiceAccountRef{Name: "missing", Namespace: "default"},
+			wantErr: "failed to get service account",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start
+			cache := NewServiceAccountTokenCache()
+			cache.now = func() time.Time { return now }
+
+			requests := 0
+			k8sClient := newTokenClient(t, &requests, tt.lifetime, cache.now)
+
+			_, err := cache.Token(context.Background(), k8sClient, tt.ref)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			now = now.Add(tt.elapsed)
+			token, err := cache.Token(context.Background(), k8sClient, tt.ref)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantToken, token.Token)
+			assert.Equal(t, tt.wantRequests, requests)
+
+			refreshAt, ok := cache.RefreshAt(tt.ref)
+			assert.True(t, ok)
+			assert.Equal(t, token.RefreshAt(), refreshAt)
+		})
+	}
+}
+
+func TestServiceAccountToken_RefreshAt(t *testing.T) {
+	issuedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := ServiceAccountToken{IssuedAt: issuedAt, ExpiresAt: issuedAt.Add(time.Hour)}
+
+	assert.Equal(t, issuedAt.Add(48*time.Minute), token.RefreshAt())
+}
@@ -1,36 +1,188 @@
 package config
 
+import "time"
+
+// Config is the configuration shared by the gateway and the listener. Every field is set by a flag named after its
+// mapstructure key and by the matching environment variable, see Options. The default tags are the defaults of both
+// binaries and the description tags are used for the flag usage and the configuration reference in docs/configuration.md.
 type Config struct {
-	OpenApiDefinitionsPath      string `mapstructure:"openapi-definitions-path"`
-	EnableKcp                   bool   `mapstructure:"enable-kcp"`
-	LocalDevelopment            bool   `mapstructure:"local-development"`
-	IntrospectionAuthentication bool   `mapstructure:"introspection-authentication"`
+	OpenApiDefinitionsPath      string `mapstructure:"openapi-definitions-path" default:"./bin/definitions" description:"Directory of the schema definitions written by the listener and served by the gateway"`
+	EnableKcp                   bool   `mapstructure:"enable-kcp" default:"true" description:"Watch the workspaces of a kcp instance instead of the clusters of ClusterAccess resources"`
+	LocalDevelopment            bool   `mapstructure:"local-development" default:"false" description:"Skip the token authentication and send all requests with the credentials of the gateway"`
+	IntrospectionAuthentication bool   `mapstructure:"introspection-authentication" default:"false" description:"Validate the token of introspection queries against the cluster"`
+	TelemetryConfigPath         string `mapstructure:"telemetry-config-path" description:"File with the telemetry exporters, applied again whenever it changes"`
+	ExecCommands                string `mapstructure:"exec-commands" default:"aws,gke-gcloud-auth-plugin,kubelogin" description:"Comma separated commands the credential plugins of the exec and cloud auth may run, empty disables both"`
 
 	Url struct {
-		VirtualWorkspacePrefix string `mapstructure:"gateway-url-virtual-workspace-prefix"`
-		DefaultKcpWorkspace    string `mapstructure:"gateway-url-default-kcp-workspace"`
-		GraphqlSuffix          string `mapstructure:"gateway-url-graphql-suffix"`
+		VirtualWorkspacePrefix string `mapstructure:"gateway-url-virtual-workspace-prefix" default:"virtual-workspace" description:"Path segment of virtual workspace endpoints"`
+		DefaultKcpWorkspace    string `mapstructure:"gateway-url-default-kcp-workspace" default:"root" description:"Workspace the virtual workspaces are served from"`
+		GraphqlSuffix          string `mapstructure:"gateway-url-graphql-suffix" default:"graphql" description:"Last path segment of the GraphQL endpoints"`
+		BasePath               string `mapstructure:"gateway-url-base-path" description:"Path prefix under which the gateway is served, e.g. /api/graphql-gateway"`
+		ExternalURL            string `mapstructure:"gateway-url-external-url" description:"URL under which clients reach the gateway, used for links and reconnect hints"`
+	} `mapstructure:",squash"`
+
+	SchemaStorage struct {
+		Backend    string `mapstructure:"schema-storage-backend" default:"filesystem" description:"Where the listener stores the schemas and the gateway loads them from, filesystem, configmap, s3 or gcs"`
+		Namespace  string `mapstructure:"schema-storage-namespace" default:"default" description:"Namespace of the ConfigMaps of the configmap backend"`
+		Kubeconfig string `mapstructure:"schema-storage-kubeconfig" description:"Kubeconfig of the cluster holding the ConfigMaps, the in-cluster config is used if empty"`
+		Versions   int    `mapstructure:"schema-storage-versions" default:"0" description:"Amount of previous schemas kept per cluster by the filesystem backend, which the gateway falls back to if a new schema doesn't build and can be pinned to, 0 disables versioning"`
+		VersionDir string `mapstructure:"schema-storage-version-dir" description:"Directory of the kept schemas, next to the schema directory with the suffix .versions if empty"`
+
+		S3 struct {
+			Endpoint   string        `mapstructure:"schema-storage-s3-endpoint" description:"URL of the S3 compatible object storage of the s3 backend, the AWS endpoint of the region if empty"`
+			Region     string        `mapstructure:"schema-storage-s3-region" default:"us-east-1" description:"Region the requests to the object storage are signed for"`
+			Bucket     string        `mapstructure:"schema-storage-s3-bucket" description:"Bucket holding the schemas of the s3 backend"`
+			Prefix     string        `mapstructure:"schema-storage-s3-prefix" description:"Prefix of the object names of the schemas, e.g. gateway/"`
+			PollPeriod time.Duration `mapstructure:"schema-storage-s3-poll-period" default:"30s" description:"How often the gateway lists the bucket for changed schemas"`
+		} `mapstructure:",squash"`
+
+		GCS struct {
+			Bucket     string        `mapstructure:"schema-storage-gcs-bucket" description:"Bucket holding the schemas of the gcs backend"`
+			Prefix     string        `mapstructure:"schema-storage-gcs-prefix" description:"Prefix of the object names of the schemas, e.g. gateway/"`
+			PollPeriod time.Duration `mapstructure:"schema-storage-gcs-poll-period" default:"30s" description:"How often the gateway lists the bucket for changed schemas"`
+		} `mapstructure:",squash"`
 	} `mapstructure:",squash"`
 
 	Listener struct {
-		VirtualWorkspacesConfigPath string `mapstructure:"virtual-workspaces-config-path"`
+		VirtualWorkspacesConfigPath string        `mapstructure:"virtual-workspaces-config-path" description:"File with the kcp virtual workspaces whose schemas are generated"`
+		APIExportResyncPeriod       time.Duration `mapstructure:"listener-apiexport-resync-period" default:"1m" description:"How often the URLs of the APIExports referenced by the virtual workspaces config are resolved again, 0 disables it"`
+		ClusterPathCacheTTL         time.Duration `mapstructure:"listener-cluster-path-cache-ttl" default:"5m" description:"How long the workspace path of a logical cluster is cached before it is resolved again"`
+		ClusterAccessResyncPeriod   time.Duration `mapstructure:"listener-cluster-access-resync-period" default:"10m" description:"How often the schemas of ClusterAccess clusters are generated again, 0 disables it"`
+		ResyncPeriod                time.Duration `mapstructure:"listener-resync-period" default:"1h" description:"How often the schemas of all clusters are generated again, in case watch events were missed, 0 only regenerates them on SIGUSR1 or POST /resync"`
+		SchemaGCPeriod              time.Duration `mapstructure:"listener-schema-gc-period" default:"10m" description:"How often the schemas of deleted kcp workspaces are searched and removed, 0 disables it"`
+		DeprecationWarnings         bool          `mapstructure:"listener-deprecation-warnings" default:"false" description:"Probe every resource for deprecation warnings of the API server and add them to the schema"`
+		InputOnlyFieldsPath         string        `mapstructure:"listener-input-only-fields-path" description:"File with rules marking fields of kinds as input-only"`
+		RelationDepth               int           `mapstructure:"listener-relation-depth" default:"1" description:"Longest chain of relationship fields added for *Ref fields, e.g. 2 adds them to the referenced kinds as well, 0 adds none"`
+		WebhookEnabled              bool          `mapstructure:"listener-webhook-enabled" default:"false" description:"Serve the validating admission webhook of ClusterAccesses on the webhook server, which needs a serving certificate"`
+
+		LeaderElection struct {
+			ID        string `mapstructure:"listener-leader-election-id" default:"72231e1f.openmfp.io" description:"Name of the Lease the listener replicas elect the one generating the schemas with, if --leader-elect is set"`
+			Namespace string `mapstructure:"listener-leader-election-namespace" description:"Namespace of the leader election Lease, the namespace of the pod if empty"`
+		} `mapstructure:",squash"`
 	} `mapstructure:",squash"`
 
 	Gateway struct {
-		Port              string `mapstructure:"gateway-port"`
-		UsernameClaim     string `mapstructure:"gateway-username-claim"`
-		ShouldImpersonate bool   `mapstructure:"gateway-should-impersonate"`
+		Port                      string `mapstructure:"gateway-port" default:"8080" description:"Port the gateway listens on"`
+		UsernameClaim             string `mapstructure:"gateway-username-claim" default:"email" description:"Token claim holding the name of the impersonated user"`
+		GroupsClaim               string `mapstructure:"gateway-groups-claim" description:"Token claim holding the groups of the impersonated user, a list or a single string, empty impersonates no groups"`
+		UIDClaim                  string `mapstructure:"gateway-uid-claim" description:"Token claim holding the UID of the impersonated user, empty impersonates no UID"`
+		ExtraClaims               string `mapstructure:"gateway-extra-claims" description:"Comma separated key=claim pairs, e.g. example.com/tenant=tenant_id, impersonating the extra attribute key with the value of the claim"`
+		ShouldImpersonate         bool   `mapstructure:"gateway-should-impersonate" default:"true" description:"Send the requests to the API servers as the user of the token"`
+		AuthMode                  string `mapstructure:"gateway-auth-mode" default:"claims" description:"How the impersonated user is resolved from the token, claims reads the username claim and tokenReview asks the TokenReview API of the cluster, overridden by the authMode of ClusterAccess resources"`
+		NamespaceTemplatesPath    string `mapstructure:"gateway-namespace-templates-path" description:"File with the templates available to the createNamespace mutation"`
+		InputCoercion             string `mapstructure:"gateway-input-coercion" description:"Coercion of mutation inputs to the OpenAPI types, empty (disabled), lenient or strict"`
+		ApiServerProtobuf         bool   `mapstructure:"gateway-apiserver-protobuf" default:"false" description:"Read built-in types from the API servers as protobuf instead of JSON"`
+		SchemaProfilesPath        string `mapstructure:"gateway-schema-profiles-path" description:"File with the schema profiles served next to the full schema"`
+		SchemaTransformationsPath string `mapstructure:"gateway-schema-transformations-path" description:"File with the transformations applied to the schemas of all clusters"`
+		RedactionRulesPath        string `mapstructure:"gateway-redaction-rules-path" description:"File with the rules masking or omitting fields of the returned objects for callers without the unredact verb"`
+		ClusterGroupsPath         string `mapstructure:"gateway-cluster-groups-path" description:"File with the cluster groups served under /groups/{group}/graphql"`
+		AggregatedQueries         bool   `mapstructure:"gateway-aggregated-queries" default:"false" description:"Serve the clusters query under /graphql, which runs its selection against several clusters and merges their results"`
+		NamespaceEndpoints        bool   `mapstructure:"gateway-namespace-endpoints" default:"false" description:"Serve /{cluster}/namespaces/{namespace}/graphql endpoints whose operations can't leave the namespace"`
+		Subresources              string `mapstructure:"gateway-subresources" default:"status,scale" description:"Comma separated subresources exposed as operations of the kinds serving them, out of status, scale, eviction, token and proxy"`
+		SchemaEnums               bool   `mapstructure:"gateway-schema-enums" default:"false" description:"Generate GraphQL enums for string fields restricted to OpenAPI enum values"`
+		SchemaKubernetesScalars   bool   `mapstructure:"gateway-schema-kubernetes-scalars" default:"false" description:"Generate Quantity, Time, MicroTime and IntOrString scalars instead of String"`
+		SchemaRequiredInputs      bool   `mapstructure:"gateway-schema-required-inputs" default:"false" description:"Mark the fields required by the OpenAPI schema as non-null in the mutation inputs, updates then have to repeat the required fields of the objects they contain"`
+		SchemaPermissions         bool   `mapstructure:"gateway-schema-permissions" default:"false" description:"Add the canI query and a permissions field to every type, answered with access reviews of the caller"`
+		SchemaEvents              bool   `mapstructure:"gateway-schema-events" default:"false" description:"Add an events field to every type, listing the events about the object"`
+		SchemaResourceMetrics     bool   `mapstructure:"gateway-schema-resource-metrics" default:"false" description:"Add a metrics field to Pods and Nodes with their CPU and memory usage, read from the metrics.k8s.io API"`
+		SchemaOwnerReferences     bool   `mapstructure:"gateway-schema-owner-references" default:"false" description:"Add owns and ownedBy fields to the built-in kinds owned by their controllers, e.g. Deployments, ReplicaSets and Pods"`
+		SchemaOwnerRelations      string `mapstructure:"gateway-schema-owner-relations" description:"Comma separated owner=child pairs of kinds, e.g. example.com/v1/Database=apps/v1/StatefulSet, getting owns and ownedBy fields in addition to the built-in kinds"`
+		SchemaDescriptionLength   int    `mapstructure:"gateway-schema-description-length" default:"1000" description:"Maximum length of the OpenAPI descriptions added to the schema, 0 leaves them out"`
+		FieldUsageSamplePercent   int    `mapstructure:"gateway-field-usage-sample-percent" default:"0" description:"Percentage of operations, between 0 (disabled) and 100, whose selected fields are recorded"`
+		MetricsOperationNames     int    `mapstructure:"gateway-metrics-operation-names" default:"100" description:"Most distinct operation names reported in the operation metrics, further names are reported as other"`
+		MaxRequestBodySize        int64  `mapstructure:"gateway-max-request-body-size" default:"4194304" description:"Largest body of GraphQL requests in bytes, larger ones are rejected with 413, 0 disables the limit"`
+
+		WebSocketKeepAlive time.Duration `mapstructure:"gateway-websocket-keepalive" default:"15s" description:"Interval of the pings sent to graphql-transport-ws clients, 0 disables them"`
+
+		Subscription struct {
+			Ordering   string `mapstructure:"gateway-subscription-ordering" default:"ordered" description:"Updates sent to slow subscribers, ordered keeps every update and latest only the newest one"`
+			BufferSize int    `mapstructure:"gateway-subscription-buffer-size" default:"100" description:"Amount of pending updates kept per subscription in the ordered mode"`
+			Multiplex  bool   `mapstructure:"gateway-subscription-multiplex" default:"true" description:"Share one API server watch between the subscriptions of a caller watching the same kind, namespace and selectors"`
+		} `mapstructure:",squash"`
+
+		SchemaReload struct {
+			RefuseBreakingChanges bool `mapstructure:"gateway-refuse-breaking-schema-changes" default:"false" description:"Keep serving the previous schema of a cluster if a reloaded schema file removes or changes types, fields or arguments operations may rely on"`
+		} `mapstructure:",squash"`
+
+		List struct {
+			ChunkSize int64 `mapstructure:"gateway-list-chunk-size" default:"500" description:"Amount of objects read per API server request of list queries, 0 reads every list in one request"`
+			MaxItems  int   `mapstructure:"gateway-list-max-items" default:"0" description:"Maximum amount of objects a list query may read, 0 disables the limit"`
+		} `mapstructure:",squash"`
+
+		ReadCache struct {
+			Kinds string `mapstructure:"gateway-read-cache-kinds" description:"Comma separated kinds, e.g. v1/ConfigMap,apps/v1/Deployment, whose list and get queries are served from informers of the gateway, empty disables the cache"`
+		} `mapstructure:",squash"`
+
+		Shutdown struct {
+			DrainTimeout time.Duration `mapstructure:"gateway-shutdown-drain-timeout" default:"25s" description:"How long the gateway waits on termination for running operations to finish and for subscriptions to be completed before the remaining connections are dropped, should be below the termination grace period of the pod"`
+		} `mapstructure:",squash"`
+
+		Clients struct {
+			CacheTTL time.Duration `mapstructure:"gateway-client-cache-ttl" default:"1h" description:"How long the HTTP transport, client and REST mapper of a target cluster are reused by its reloaded schemas and token validations before they are built again, 0 builds them for every schema"`
+		} `mapstructure:",squash"`
+
+		OIDC struct {
+			IssuerURL string        `mapstructure:"gateway-oidc-issuer-url" description:"Issuer whose signature, audience and lifetime the tokens of the callers are verified against before they are forwarded, empty trusts the tokens unverified"`
+			JWKSURL   string        `mapstructure:"gateway-oidc-jwks-url" description:"JWKS endpoint of the issuer, discovered from its OpenID configuration if empty"`
+			Audience  string        `mapstructure:"gateway-oidc-audience" description:"Audience the tokens must be issued for, empty skips the audience check"`
+			ClockSkew time.Duration `mapstructure:"gateway-oidc-clock-skew" default:"1m" description:"Tolerance for the expiry, not-before and issued-at claims of the tokens"`
+		} `mapstructure:",squash"`
+
+		Kubeconfig struct {
+			ServiceAccount string        `mapstructure:"gateway-kubeconfig-service-account" description:"Service account, formatted as namespace/name, whose tokens the issueKubeconfig mutation issues, empty disables the mutation"`
+			MaxTTL         time.Duration `mapstructure:"gateway-kubeconfig-max-ttl" default:"1h" description:"Longest lifetime of the tokens issued by the issueKubeconfig mutation"`
+			Audiences      string        `mapstructure:"gateway-kubeconfig-audiences" default:"https://kubernetes.default.svc.cluster.local" description:"Comma separated audiences the tokens issued by the issueKubeconfig mutation are bound to, one of them must be accepted by the API server"`
+		} `mapstructure:",squash"`
+
+		Anonymous struct {
+			ServiceAccount string `mapstructure:"gateway-anonymous-service-account" description:"Service account, formatted as namespace/name, impersonated for requests without a token, which may then only read, empty rejects them with 401"`
+		} `mapstructure:",squash"`
+
+		Audit struct {
+			Sink string `mapstructure:"gateway-audit-sink" description:"Where the mutations are logged with the identity of the caller and a diff of the objects, stdout, file:<path> or the http(s) URL of a webhook, empty disables the audit log"`
+		} `mapstructure:",squash"`
+
+		Admin struct {
+			TokenPath     string        `mapstructure:"gateway-admin-token-path" description:"File with the bearer token of the admin API registering clusters at runtime under /admin/clusters of the health port, empty disables the API"`
+			Namespace     string        `mapstructure:"gateway-admin-namespace" default:"default" description:"Namespace of the secrets and ConfigMaps the registrations of the admin API may reference"`
+			SchemaTimeout time.Duration `mapstructure:"gateway-admin-schema-timeout" default:"1m" description:"How long the admin API may take to generate the schema of a registered cluster before the registration fails"`
+		} `mapstructure:",squash"`
+
+		QueryLimits struct {
+			MaxDepth      int `mapstructure:"gateway-query-max-depth" default:"0" description:"Deepest nesting of fields of an operation, 0 disables the limit"`
+			MaxAliases    int `mapstructure:"gateway-query-max-aliases" default:"0" description:"Maximum amount of aliased fields of an operation, 0 disables the limit"`
+			MaxComplexity int `mapstructure:"gateway-query-max-complexity" default:"0" description:"Maximum cost of an operation, every field costs 1 and the selections below lists cost 10 times as much, 0 disables the limit"`
+		} `mapstructure:",squash"`
+
+		RateLimit struct {
+			QPS            float64 `mapstructure:"gateway-rate-limit-qps" default:"0" description:"Sustained rate of operations per user across all clusters, 0 disables the limit"`
+			Burst          int     `mapstructure:"gateway-rate-limit-burst" default:"0" description:"Amount of operations a user may send at once, 0 rounds the QPS up"`
+			ApiServerQPS   float32 `mapstructure:"gateway-apiserver-qps" default:"0" description:"Rate of API server requests per cluster shared by all users, 0 keeps the client-go defaults and a negative value disables client-side throttling"`
+			ApiServerBurst int     `mapstructure:"gateway-apiserver-burst" default:"0" description:"Amount of API server requests per cluster sent at once, 0 rounds the API server QPS up"`
+		} `mapstructure:",squash"`
+
+		DebugRecordingDir string `mapstructure:"gateway-debug-recording-dir" description:"Directory of the bundles recorded for operations sent with the X-Debug-Record header, empty disables recording"`
+
+		LoadShedding struct {
+			LatencyThreshold time.Duration `mapstructure:"gateway-load-shedding-latency-threshold" default:"0s" description:"Average API server latency above which introspection and list queries are rejected, 0 disables it"`
+			ErrorRatePercent int           `mapstructure:"gateway-load-shedding-error-rate-percent" default:"0" description:"Percentage of failed API server requests above which introspection and list queries are rejected, 0 disables it"`
+			RetryAfter       time.Duration `mapstructure:"gateway-load-shedding-retry-after" default:"10s" description:"Retry-After sent to clients whose operations are rejected"`
+		} `mapstructure:",squash"`
 
 		HandlerCfg struct {
-			Pretty     bool `mapstructure:"gateway-handler-pretty"`
-			Playground bool `mapstructure:"gateway-handler-playground"`
-			GraphiQL   bool `mapstructure:"gateway-handler-graphiql"`
+			Pretty     bool `mapstructure:"gateway-handler-pretty" default:"true" description:"Indent the JSON responses"`
+			Playground bool `mapstructure:"gateway-handler-playground" default:"true" description:"Serve the GraphQL Playground on GET requests"`
+			GraphiQL   bool `mapstructure:"gateway-handler-graphiql" default:"true" description:"Serve GraphiQL on GET requests"`
+			Federation bool `mapstructure:"gateway-handler-federation" default:"false" description:"Serve the schemas as Apollo Federation v2 subgraphs"`
 		} `mapstructure:",squash"`
 
 		Cors struct {
-			Enabled        bool   `mapstructure:"gateway-cors-enabled"`
-			AllowedOrigins string `mapstructure:"gateway-cors-allowed-origins"`
-			AllowedHeaders string `mapstructure:"gateway-cors-allowed-headers"`
+			Enabled        bool   `mapstructure:"gateway-cors-enabled" default:"false" description:"Send CORS headers"`
+			AllowedOrigins string `mapstructure:"gateway-cors-allowed-origins" default:"*" description:"Comma separated origins allowed by CORS"`
+			AllowedHeaders string `mapstructure:"gateway-cors-allowed-headers" default:"*" description:"Comma separated headers allowed by CORS"`
+			AllowedMethods string `mapstructure:"gateway-cors-allowed-methods" default:"GET,POST,OPTIONS" description:"Comma separated methods allowed by CORS"`
+			Credentials    bool   `mapstructure:"gateway-cors-allow-credentials" default:"false" description:"Allow browsers to send credentials like cookies with cross-origin requests"`
+			MaxAge         int    `mapstructure:"gateway-cors-max-age" default:"0" description:"Seconds browsers may cache the result of a preflight request, 0 doesn't send the header"`
 		} `mapstructure:",squash"`
 	} `mapstructure:",squash"`
 }
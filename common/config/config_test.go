@@ -91,9 +91,10 @@ func TestConfig_NestedStructModification(t *testing.T) {
 
 	// Test direct modification of nested structs
 	cfg.Gateway.HandlerCfg = struct {
-		Pretty     bool `mapstructure:"gateway-handler-pretty"`
-		Playground bool `mapstructure:"gateway-handler-playground"`
-		GraphiQL   bool `mapstructure:"gateway-handler-graphiql"`
+		Pretty     bool `mapstructure:"gateway-handler-pretty" default:"true" description:"Indent the JSON responses"`
+		Playground bool `mapstructure:"gateway-handler-playground" default:"true" description:"Serve the GraphQL Playground on GET requests"`
+		GraphiQL   bool `mapstructure:"gateway-handler-graphiql" default:"true" description:"Serve GraphiQL on GET requests"`
+		Federation bool `mapstructure:"gateway-handler-federation" default:"false" description:"Serve the schemas as Apollo Federation v2 subgraphs"`
 	}{
 		Pretty:     true,
 		Playground: false,
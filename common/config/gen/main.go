@@ -0,0 +1,28 @@
+// Command gen writes the configuration reference to the file given as its only argument
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: gen <output file>")
+		os.Exit(1)
+	}
+
+	var b bytes.Buffer
+	if err := config.WriteReference(&b); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(os.Args[1], b.Bytes(), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Option is a single setting of the Config
+type Option struct {
+	// Key is the mapstructure key, which is also the name of the flag
+	Key string
+	// Env is the environment variable setting the option
+	Env string
+	// Type is the Go type of the option, e.g. string, int or time.Duration
+	Type string
+	// Default is the value used if neither the flag nor the environment variable are set
+	Default string
+	// Description explains the option in the flag usage and the configuration reference
+	Description string
+
+	kind reflect.Type
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Options returns the settings of the Config in the order of their fields
+func Options() ([]Option, error) {
+	return collectOptions(reflect.TypeOf(Config{}), nil)
+}
+
+func collectOptions(typ reflect.Type, options []Option) ([]Option, error) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct {
+			if key != ",squash" {
+				return nil, fmt.Errorf("nested struct %s must be squashed", field.Name)
+			}
+			var err error
+			if options, err = collectOptions(field.Type, options); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		option := Option{
+			Key:         key,
+			Env:         EnvName(key),
+			Type:        field.Type.String(),
+			Default:     field.Tag.Get("default"),
+			Description: field.Tag.Get("description"),
+			kind:        field.Type,
+		}
+		if _, err := option.defaultValue(); err != nil {
+			return nil, fmt.Errorf("invalid default of %s: %w", key, err)
+		}
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// EnvName returns the environment variable of an option key, matching the key replacer of the viper instance
+func EnvName(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// defaultValue parses the default of the option into its type
+func (o Option) defaultValue() (any, error) {
+	if o.kind == durationType {
+		if o.Default == "" {
+			return time.Duration(0), nil
+		}
+		return time.ParseDuration(o.Default)
+	}
+
+	switch o.kind.Kind() {
+	case reflect.String:
+		return o.Default, nil
+	case reflect.Bool:
+		if o.Default == "" {
+			return false, nil
+		}
+		return strconv.ParseBool(o.Default)
+	case reflect.Int, reflect.Int64:
+		if o.Default == "" {
+			return int64(0), nil
+		}
+		return strconv.ParseInt(o.Default, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		if o.Default == "" {
+			return float64(0), nil
+		}
+		return strconv.ParseFloat(o.Default, 64)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", o.Type)
+	}
+}
+
+// BindFlags adds a flag for every option of the Config to flags, binds them to v and sets the defaults of the
+// options in v. The environment variables are resolved by v, which has to replace "-" with "_" in its keys.
+func BindFlags(v *viper.Viper, flags *pflag.FlagSet) error {
+	options, err := Options()
+	if err != nil {
+		return err
+	}
+
+	for _, option := range options {
+		value, err := option.defaultValue()
+		if err != nil {
+			return fmt.Errorf("invalid default of %s: %w", option.Key, err)
+		}
+
+		switch value := value.(type) {
+		case time.Duration:
+			flags.Duration(option.Key, value, option.Description)
+		case bool:
+			flags.Bool(option.Key, value, option.Description)
+		case int64:
+			flags.Int64(option.Key, value, option.Description)
+		case float64:
+			flags.Float64(option.Key, value, option.Description)
+		default:
+			flags.String(option.Key, option.Default, option.Description)
+		}
+
+		v.SetDefault(option.Key, value)
+		if err := v.BindPFlag(option.Key, flags.Lookup(option.Key)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package config_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestOptions(t *testing.T) {
+	options, err := config.Options()
+	require.NoError(t, err)
+
+	keys := map[string]bool{}
+	for _, option := range options {
+		assert.False(t, keys[option.Key], "duplicate option %s", option.Key)
+		keys[option.Key] = true
+		assert.NotEmpty(t, option.Description, "option %s has no description", option.Key)
+	}
+
+	assert.Equal(t, "GATEWAY_RATE_LIMIT_QPS", config.EnvName("gateway-rate-limit-qps"))
+}
+
+func TestBindFlags(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		env    map[string]string
+		verify func(t *testing.T, cfg config.Config)
+	}{
+		{
+			name: "defaults",
+			verify: func(t *testing.T, cfg config.Config) {
+				assert.Equal(t, "./bin/definitions", cfg.OpenApiDefinitionsPath)
+				assert.True(t, cfg.EnableKcp)
+				assert.Equal(t, "8080", cfg.Gateway.Port)
+				assert.Equal(t, 10*time.Minute, cfg.Listener.ClusterAccessResyncPeriod)
+				assert.Equal(t, int64(500), cfg.Gateway.List.ChunkSize)
+				assert.True(t, cfg.Gateway.HandlerCfg.Pretty)
+				assert.NoError(t, cfg.Validate())
+			},
+		},
+		{
+			name: "flags",
+			args: []string{"--enable-kcp=false", "--gateway-rate-limit-qps=2.5", "--gateway-websocket-keepalive=1m"},
+			verify: func(t *testing.T, cfg config.Config) {
+				assert.False(t, cfg.EnableKcp)
+				assert.Equal(t, 2.5, cfg.Gateway.RateLimit.QPS)
+				assert.Equal(t, time.Minute, cfg.Gateway.WebSocketKeepAlive)
+			},
+		},
+		{
+			name: "env",
+			env:  map[string]string{"GATEWAY_PORT": "9000", "GATEWAY_APISERVER_QPS": "-1"},
+			verify: func(t *testing.T, cfg config.Config) {
+				assert.Equal(t, "9000", cfg.Gateway.Port)
+				assert.Equal(t, float32(-1), cfg.Gateway.RateLimit.ApiServerQPS)
+			},
+		},
+		{
+			name: "flag_over_env",
+			args: []string{"--gateway-port=9001"},
+			env:  map[string]string{"GATEWAY_PORT": "9000"},
+			verify: func(t *testing.T, cfg config.Config) {
+				assert.Equal(t, "9001", cfg.Gateway.Port)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			v := viper.NewWithOptions(viper.EnvKeyReplacer(strings.NewReplacer("-", "_")))
+			v.AutomaticEnv()
+			flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			require.NoError(t, config.BindFlags(v, flags))
+			require.NoError(t, flags.Parse(tt.args))
+
+			var cfg config.Config
+			require.NoError(t, v.Unmarshal(&cfg))
+			tt.verify(t, cfg)
+		})
+	}
+}
+
+func TestWriteReference(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, config.WriteReference(&b))
+
+	checkedIn, err := os.ReadFile("../../docs/configuration.md")
+	require.NoError(t, err)
+	assert.Equal(t, string(checkedIn), b.String(), "docs/configuration.md is outdated, run go generate ./common/config")
+}
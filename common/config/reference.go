@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+//go:generate go run ./gen ../../docs/configuration.md
+
+// WriteReference writes the Markdown reference of all options, which is checked in as docs/configuration.md
+func WriteReference(w io.Writer) error {
+	options, err := Options()
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("# Configuration\n\n")
+	b.WriteString("<!-- Generated by `go generate ./common/config`, do not edit. -->\n\n")
+	b.WriteString("The gateway and the listener share one configuration. ")
+	b.WriteString("Every option can be set by its flag or by its environment variable, the flag takes precedence. ")
+	b.WriteString("Both binaries validate the whole configuration at startup and exit listing every invalid option.\n\n")
+	b.WriteString("| Flag | Environment Variable | Type | Default | Description |\n")
+	b.WriteString("|------|----------------------|------|---------|-------------|\n")
+	for _, option := range options {
+		defaultValue := "-"
+		if option.Default != "" {
+			defaultValue = "`" + option.Default + "`"
+		}
+		fmt.Fprintf(&b, "| `--%s` | `%s` | %s | %s | %s |\n",
+			option.Key, option.Env, option.Type, defaultValue, strings.ReplaceAll(option.Description, "|", "\\|"))
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
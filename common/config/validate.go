@@ -0,0 +1,211 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// Validate checks the values and combinations of options that can't work, so that both binaries fail at startup
+// instead of when the option is first used. All findings are returned together.
+func (c Config) Validate() error {
+	var errs []error
+	add := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+	nonNegative := func(key string, value int64) {
+		if value < 0 {
+			add(key, "must not be negative, got %d", value)
+		}
+	}
+	nonNegativeDuration := func(key string, value time.Duration) {
+		if value < 0 {
+			add(key, "must not be negative, got %s", value)
+		}
+	}
+	percent := func(key string, value int) {
+		if value < 0 || value > 100 {
+			add(key, "must be between 0 and 100, got %d", value)
+		}
+	}
+
+	switch c.SchemaStorage.Backend {
+	case "", "filesystem":
+		if c.OpenApiDefinitionsPath == "" {
+			add("openapi-definitions-path", "must be set for the filesystem schema storage")
+		}
+	case "configmap":
+		if c.SchemaStorage.Namespace == "" {
+			add("schema-storage-namespace", "must be set for the configmap schema storage")
+		}
+		if c.SchemaStorage.Versions > 0 {
+			add("schema-storage-versions", "requires the filesystem schema storage")
+		}
+	case "s3":
+		if c.SchemaStorage.S3.Bucket == "" {
+			add("schema-storage-s3-bucket", "must be set for the s3 schema storage")
+		}
+		if c.SchemaStorage.S3.Region == "" {
+			add("schema-storage-s3-region", "must be set for the s3 schema storage")
+		}
+		if c.SchemaStorage.S3.PollPeriod <= 0 {
+			add("schema-storage-s3-poll-period", "must be positive, got %s", c.SchemaStorage.S3.PollPeriod)
+		}
+		if c.SchemaStorage.Versions > 0 {
+			add("schema-storage-versions", "requires the filesystem schema storage")
+		}
+	case "gcs":
+		if c.SchemaStorage.GCS.Bucket == "" {
+			add("schema-storage-gcs-bucket", "must be set for the gcs schema storage")
+		}
+		if c.SchemaStorage.GCS.PollPeriod <= 0 {
+			add("schema-storage-gcs-poll-period", "must be positive, got %s", c.SchemaStorage.GCS.PollPeriod)
+		}
+		if c.SchemaStorage.Versions > 0 {
+			add("schema-storage-versions", "requires the filesystem schema storage")
+		}
+	default:
+		add("schema-storage-backend", "must be filesystem, configmap, s3 or gcs, got %q", c.SchemaStorage.Backend)
+	}
+	nonNegative("schema-storage-versions", int64(c.SchemaStorage.Versions))
+
+	if c.Listener.VirtualWorkspacesConfigPath != "" && !c.EnableKcp {
+		add("virtual-workspaces-config-path", "requires enable-kcp")
+	}
+	if c.Listener.WebhookEnabled && c.EnableKcp {
+		add("listener-webhook-enabled", "is not supported with enable-kcp")
+	}
+	nonNegativeDuration("listener-cluster-path-cache-ttl", c.Listener.ClusterPathCacheTTL)
+	nonNegativeDuration("listener-cluster-access-resync-period", c.Listener.ClusterAccessResyncPeriod)
+	nonNegativeDuration("listener-resync-period", c.Listener.ResyncPeriod)
+	nonNegative("listener-relation-depth", int64(c.Listener.RelationDepth))
+
+	if c.Url.BasePath != "" && !strings.HasPrefix(c.Url.BasePath, "/") {
+		add("gateway-url-base-path", "must start with /, got %q", c.Url.BasePath)
+	}
+	if c.Url.ExternalURL != "" {
+		if u, err := url.Parse(c.Url.ExternalURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("gateway-url-external-url", "must be an absolute URL, got %q", c.Url.ExternalURL)
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Gateway.Port); err != nil || port < 1 || port > 65535 {
+		add("gateway-port", "must be a port number, got %q", c.Gateway.Port)
+	}
+	if c.Gateway.ShouldImpersonate && !c.LocalDevelopment && c.Gateway.AuthMode != "tokenReview" && c.Gateway.UsernameClaim == "" {
+		add("gateway-username-claim", "must be set when gateway-should-impersonate is enabled")
+	}
+
+	for _, entry := range strings.Split(c.Gateway.ExtraClaims, ",") {
+		entry = strings.TrimSpace(entry)
+		if key, claim, ok := strings.Cut(entry, "="); entry != "" && (!ok || key == "" || claim == "") {
+			add("gateway-extra-claims", "entries must be formatted as key=claim, got %q", entry)
+		}
+	}
+
+	switch c.Gateway.AuthMode {
+	case "", "claims", "tokenReview":
+	default:
+		add("gateway-auth-mode", "must be claims or tokenReview, got %q", c.Gateway.AuthMode)
+	}
+
+	switch c.Gateway.InputCoercion {
+	case "", "lenient", "strict":
+	default:
+		add("gateway-input-coercion", "must be empty, lenient or strict, got %q", c.Gateway.InputCoercion)
+	}
+
+	switch c.Gateway.Subscription.Ordering {
+	case "ordered":
+		if c.Gateway.Subscription.BufferSize < 1 {
+			add("gateway-subscription-buffer-size", "must be positive in the ordered mode, got %d", c.Gateway.Subscription.BufferSize)
+		}
+	case "latest":
+	default:
+		add("gateway-subscription-ordering", "must be ordered or latest, got %q", c.Gateway.Subscription.Ordering)
+	}
+
+	if c.Gateway.OIDC.IssuerURL != "" {
+		if u, err := url.Parse(c.Gateway.OIDC.IssuerURL); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			add("gateway-oidc-issuer-url", "must be an absolute URL, got %q", c.Gateway.OIDC.IssuerURL)
+		}
+	} else {
+		if c.Gateway.OIDC.JWKSURL != "" {
+			add("gateway-oidc-jwks-url", "requires gateway-oidc-issuer-url")
+		}
+		if c.Gateway.OIDC.Audience != "" {
+			add("gateway-oidc-audience", "requires gateway-oidc-issuer-url")
+		}
+	}
+	nonNegativeDuration("gateway-oidc-clock-skew", c.Gateway.OIDC.ClockSkew)
+	nonNegative("gateway-metrics-operation-names", int64(c.Gateway.MetricsOperationNames))
+	nonNegative("gateway-max-request-body-size", c.Gateway.MaxRequestBodySize)
+
+	if c.Gateway.Kubeconfig.ServiceAccount != "" {
+		namespace, name, ok := strings.Cut(c.Gateway.Kubeconfig.ServiceAccount, "/")
+		if !ok || namespace == "" || name == "" {
+			add("gateway-kubeconfig-service-account", "must be formatted as namespace/name, got %q", c.Gateway.Kubeconfig.ServiceAccount)
+		}
+		if c.Gateway.Kubeconfig.MaxTTL <= 0 {
+			add("gateway-kubeconfig-max-ttl", "must be positive when gateway-kubeconfig-service-account is set, got %s", c.Gateway.Kubeconfig.MaxTTL)
+		}
+		if strings.Trim(c.Gateway.Kubeconfig.Audiences, ", ") == "" {
+			add("gateway-kubeconfig-audiences", "must be set when gateway-kubeconfig-service-account is set")
+		}
+	}
+
+	if c.Gateway.Admin.TokenPath != "" {
+		if c.Gateway.Admin.Namespace == "" {
+			add("gateway-admin-namespace", "must be set when gateway-admin-token-path is set")
+		}
+		if c.Gateway.Admin.SchemaTimeout <= 0 {
+			add("gateway-admin-schema-timeout", "must be positive when gateway-admin-token-path is set, got %s", c.Gateway.Admin.SchemaTimeout)
+		}
+	}
+
+	if c.Gateway.Anonymous.ServiceAccount != "" {
+		namespace, name, ok := strings.Cut(c.Gateway.Anonymous.ServiceAccount, "/")
+		if !ok || namespace == "" || name == "" {
+			add("gateway-anonymous-service-account", "must be formatted as namespace/name, got %q", c.Gateway.Anonymous.ServiceAccount)
+		}
+	}
+
+	if sink := c.Gateway.Audit.Sink; sink != "" && sink != "stdout" {
+		if path, ok := strings.CutPrefix(sink, "file:"); ok {
+			if path == "" {
+				add("gateway-audit-sink", "must name a file after file:")
+			}
+		} else if u, err := url.Parse(sink); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			add("gateway-audit-sink", "must be stdout, file:<path> or an http(s) URL, got %q", sink)
+		}
+	}
+
+	nonNegative("gateway-schema-description-length", int64(c.Gateway.SchemaDescriptionLength))
+	percent("gateway-field-usage-sample-percent", c.Gateway.FieldUsageSamplePercent)
+	nonNegativeDuration("gateway-websocket-keepalive", c.Gateway.WebSocketKeepAlive)
+	nonNegativeDuration("gateway-client-cache-ttl", c.Gateway.Clients.CacheTTL)
+	nonNegativeDuration("gateway-shutdown-drain-timeout", c.Gateway.Shutdown.DrainTimeout)
+	nonNegative("gateway-list-chunk-size", c.Gateway.List.ChunkSize)
+	nonNegative("gateway-list-max-items", int64(c.Gateway.List.MaxItems))
+	nonNegative("gateway-query-max-depth", int64(c.Gateway.QueryLimits.MaxDepth))
+	nonNegative("gateway-query-max-aliases", int64(c.Gateway.QueryLimits.MaxAliases))
+	nonNegative("gateway-query-max-complexity", int64(c.Gateway.QueryLimits.MaxComplexity))
+	if c.Gateway.RateLimit.QPS < 0 {
+		add("gateway-rate-limit-qps", "must not be negative, got %g", c.Gateway.RateLimit.QPS)
+	}
+	nonNegative("gateway-rate-limit-burst", int64(c.Gateway.RateLimit.Burst))
+	nonNegative("gateway-apiserver-burst", int64(c.Gateway.RateLimit.ApiServerBurst))
+	nonNegativeDuration("gateway-load-shedding-latency-threshold", c.Gateway.LoadShedding.LatencyThreshold)
+	percent("gateway-load-shedding-error-rate-percent", c.Gateway.LoadShedding.ErrorRatePercent)
+	nonNegativeDuration("gateway-load-shedding-retry-after", c.Gateway.LoadShedding.RetryAfter)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func validConfig() config.Config {
+	cfg := config.Config{
+		OpenApiDefinitionsPath: "./bin/definitions",
+		EnableKcp:              true,
+	}
+	cfg.SchemaStorage.Backend = "filesystem"
+	cfg.Gateway.Port = "8080"
+	cfg.Gateway.UsernameClaim = "email"
+	cfg.Gateway.ShouldImpersonate = true
+	cfg.Gateway.Subscription.Ordering = "ordered"
+	cfg.Gateway.Subscription.BufferSize = 100
+	return cfg
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		modify        func(cfg *config.Config)
+		expectedError []string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *config.Config) {},
+		},
+		{
+			name: "unknown_backend",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "azure"
+			},
+			expectedError: []string{`schema-storage-backend: must be filesystem, configmap, s3 or gcs, got "azure"`},
+		},
+		{
+			name: "s3",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "s3"
+				cfg.SchemaStorage.S3.Bucket = "schemas"
+				cfg.SchemaStorage.S3.Region = "eu-central-1"
+				cfg.SchemaStorage.S3.PollPeriod = 30 * time.Second
+			},
+		},
+		{
+			name: "s3_without_bucket",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "s3"
+				cfg.SchemaStorage.S3.Region = "eu-central-1"
+			},
+			expectedError: []string{
+				"schema-storage-s3-bucket: must be set for the s3 schema storage",
+				"schema-storage-s3-poll-period: must be positive, got 0s",
+			},
+		},
+		{
+			name: "gcs",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "gcs"
+				cfg.SchemaStorage.GCS.Bucket = "schemas"
+				cfg.SchemaStorage.GCS.PollPeriod = 30 * time.Second
+			},
+		},
+		{
+			name: "gcs_with_versions",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "gcs"
+				cfg.SchemaStorage.Versions = 5
+			},
+			expectedError: []string{
+				"schema-storage-gcs-bucket: must be set for the gcs schema storage",
+				"schema-storage-gcs-poll-period: must be positive, got 0s",
+				"schema-storage-versions: requires the filesystem schema storage",
+			},
+		},
+		{
+			name: "configmap_with_versions",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "configmap"
+				cfg.SchemaStorage.Namespace = "gateway"
+				cfg.SchemaStorage.Versions = 5
+			},
+			expectedError: []string{"schema-storage-versions: requires the filesystem schema storage"},
+		},
+		{
+			name: "negative_relation_depth",
+			modify: func(cfg *config.Config) {
+				cfg.Listener.RelationDepth = -1
+			},
+			expectedError: []string{"listener-relation-depth: must not be negative, got -1"},
+		},
+		{
+			name: "negative_versions",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Versions = -1
+			},
+			expectedError: []string{"schema-storage-versions: must not be negative, got -1"},
+		},
+		{
+			name: "configmap_without_definitions_path",
+			modify: func(cfg *config.Config) {
+				cfg.SchemaStorage.Backend = "configmap"
+				cfg.SchemaStorage.Namespace = "gateway"
+				cfg.OpenApiDefinitionsPath = ""
+			},
+		},
+		{
+			name: "virtual_workspaces_without_kcp",
+			modify: func(cfg *config.Config) {
+				cfg.EnableKcp = false
+				cfg.Listener.VirtualWorkspacesConfigPath = "/etc/virtual-workspaces.yaml"
+			},
+			expectedError: []string{"virtual-workspaces-config-path: requires enable-kcp"},
+		},
+		{
+			name: "webhook_with_kcp",
+			modify: func(cfg *config.Config) {
+				cfg.Listener.WebhookEnabled = true
+			},
+			expectedError: []string{"listener-webhook-enabled: is not supported with enable-kcp"},
+		},
+		{
+			name: "impersonation_without_claim",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.UsernameClaim = ""
+			},
+			expectedError: []string{"gateway-username-claim: must be set when gateway-should-impersonate is enabled"},
+		},
+		{
+			name: "impersonation_without_claim_in_local_development",
+			modify: func(cfg *config.Config) {
+				cfg.LocalDevelopment = true
+				cfg.Gateway.UsernameClaim = ""
+			},
+		},
+		{
+			name: "service_account_without_ttl",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Kubeconfig.ServiceAccount = "gateway"
+			},
+			expectedError: []string{
+				`gateway-kubeconfig-service-account: must be formatted as namespace/name, got "gateway"`,
+				"gateway-kubeconfig-max-ttl: must be positive when gateway-kubeconfig-service-account is set, got 0s",
+				"gateway-kubeconfig-audiences: must be set when gateway-kubeconfig-service-account is set",
+			},
+		},
+		{
+			name: "anonymous_service_account_without_namespace",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Anonymous.ServiceAccount = "dashboard-reader"
+			},
+			expectedError: []string{`gateway-anonymous-service-account: must be formatted as namespace/name, got "dashboard-reader"`},
+		},
+		{
+			name: "anonymous_service_account",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Anonymous.ServiceAccount = "public/dashboard-reader"
+			},
+		},
+		{
+			name: "audit_sink_unknown",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Audit.Sink = "syslog"
+			},
+			expectedError: []string{`gateway-audit-sink: must be stdout, file:<path> or an http(s) URL, got "syslog"`},
+		},
+		{
+			name: "audit_sink_file_without_path",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Audit.Sink = "file:"
+			},
+			expectedError: []string{"gateway-audit-sink: must name a file after file:"},
+		},
+		{
+			name: "audit_sink_webhook",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Audit.Sink = "https://audit.example.com/events"
+			},
+		},
+		{
+			name: "admin_without_namespace_and_timeout",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Admin.TokenPath = "/etc/gateway/admin-token"
+			},
+			expectedError: []string{
+				"gateway-admin-namespace: must be set when gateway-admin-token-path is set",
+				"gateway-admin-schema-timeout: must be positive when gateway-admin-token-path is set, got 0s",
+			},
+		},
+		{
+			name: "oidc_audience_without_issuer",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.OIDC.Audience = "gateway"
+			},
+			expectedError: []string{"gateway-oidc-audience: requires gateway-oidc-issuer-url"},
+		},
+		{
+			name: "oidc_relative_issuer",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.OIDC.IssuerURL = "issuer.example.com"
+			},
+			expectedError: []string{`gateway-oidc-issuer-url: must be an absolute URL, got "issuer.example.com"`},
+		},
+		{
+			name: "all_findings",
+			modify: func(cfg *config.Config) {
+				cfg.Gateway.Port = "http"
+				cfg.Gateway.AuthMode = "oidc"
+				cfg.Gateway.ExtraClaims = "example.com/tenant=tenant_id,scopes"
+				cfg.Gateway.InputCoercion = "loose"
+				cfg.Gateway.Subscription.Ordering = "newest"
+				cfg.Gateway.List.ChunkSize = -1
+				cfg.Gateway.FieldUsageSamplePercent = 101
+				cfg.Gateway.LoadShedding.RetryAfter = -time.Second
+				cfg.Gateway.Clients.CacheTTL = -time.Minute
+				cfg.Gateway.Shutdown.DrainTimeout = -time.Second
+				cfg.Listener.ResyncPeriod = -time.Hour
+				cfg.Url.BasePath = "api"
+			},
+			expectedError: []string{
+				"listener-resync-period: must not be negative, got -1h0m0s",
+				`gateway-url-base-path: must start with /, got "api"`,
+				`gateway-port: must be a port number, got "http"`,
+				`gateway-extra-claims: entries must be formatted as key=claim, got "scopes"`,
+				`gateway-auth-mode: must be claims or tokenReview, got "oidc"`,
+				`gateway-input-coercion: must be empty, lenient or strict, got "loose"`,
+				`gateway-subscription-ordering: must be ordered or latest, got "newest"`,
+				"gateway-field-usage-sample-percent: must be between 0 and 100, got 101",
+				"gateway-list-chunk-size: must not be negative, got -1",
+				"gateway-load-shedding-retry-after: must not be negative, got -1s",
+				"gateway-client-cache-ttl: must not be negative, got -1m0s",
+				"gateway-shutdown-drain-timeout: must not be negative, got -1s",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if len(tt.expectedError) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, config.ErrInvalidConfig)
+			for _, expected := range tt.expectedError {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
@@ -3,9 +3,19 @@ package common
 import "time"
 
 const (
-	CategoriesExtensionKey = "x-kubernetes-categories"
-	GVKExtensionKey        = "x-kubernetes-group-version-kind"
-	ScopeExtensionKey      = "x-kubernetes-scope"
+	CategoriesExtensionKey      = "x-kubernetes-categories"
+	DeprecationExtensionKey     = "x-kubernetes-deprecation"
+	GenerationErrorsKey         = "x-generation-errors"
+	GVKExtensionKey             = "x-kubernetes-group-version-kind"
+	InputOnlyFieldsExtensionKey = "x-openmfp-input-only-fields"
+	IntOrStringExtensionKey     = "x-kubernetes-int-or-string"
+	PreferredVersionKey         = "x-openmfp-preferred-version"
+	RelationsExtensionKey       = "x-openmfp-relations"
+	ScopeExtensionKey           = "x-kubernetes-scope"
+	SchemaRequirementsKey       = "x-schema-requirements"
+	SubresourcesExtensionKey    = "x-openmfp-subresources"
+	UIHintsExtensionKey         = "x-openmfp-ui-hints"
+	VerbsExtensionKey           = "x-openmfp-verbs"
 
 	// Timeout constants for different test scenarios
 	ShortTimeout = 100 * time.Millisecond // Short timeout for quick operations
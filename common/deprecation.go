@@ -0,0 +1,32 @@
+package common
+
+import "regexp"
+
+var (
+	deprecatedInPattern = regexp.MustCompile(`deprecated in (v\d+\.\d+)\+`)
+	removedInPattern    = regexp.MustCompile(`unavailable in (v\d+\.\d+)\+`)
+)
+
+// Deprecation is the warning an API server returns for requests to a deprecated API
+type Deprecation struct {
+	Warning string `json:"warning"`
+	// DeprecatedIn is the Kubernetes version that deprecated the API, if the warning names it
+	DeprecatedIn string `json:"deprecatedIn,omitempty"`
+	// RemovedIn is the Kubernetes version that stops serving the API, if the warning names it
+	RemovedIn string `json:"removedIn,omitempty"`
+}
+
+// ParseDeprecationWarning reads the versions from warnings of built-in APIs, e.g.
+// "policy/v1beta1 PodSecurityPolicy is deprecated in v1.21+, unavailable in v1.25+".
+// CRDs set their own warnings, in which case only the text is kept.
+func ParseDeprecationWarning(warning string) Deprecation {
+	deprecation := Deprecation{Warning: warning}
+	if match := deprecatedInPattern.FindStringSubmatch(warning); match != nil {
+		deprecation.DeprecatedIn = match[1]
+	}
+	if match := removedInPattern.FindStringSubmatch(warning); match != nil {
+		deprecation.RemovedIn = match[1]
+	}
+
+	return deprecation
+}
@@ -0,0 +1,45 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDeprecationWarning(t *testing.T) {
+	tests := []struct {
+		name     string
+		warning  string
+		expected Deprecation
+	}{
+		{
+			name:    "built_in",
+			warning: "policy/v1beta1 PodSecurityPolicy is deprecated in v1.21+, unavailable in v1.25+",
+			expected: Deprecation{
+				Warning:      "policy/v1beta1 PodSecurityPolicy is deprecated in v1.21+, unavailable in v1.25+",
+				DeprecatedIn: "v1.21",
+				RemovedIn:    "v1.25",
+			},
+		},
+		{
+			name:    "built_in_with_replacement",
+			warning: "autoscaling/v2beta2 HorizontalPodAutoscaler is deprecated in v1.23+, unavailable in v1.26+; use autoscaling/v2 HorizontalPodAutoscaler",
+			expected: Deprecation{
+				Warning:      "autoscaling/v2beta2 HorizontalPodAutoscaler is deprecated in v1.23+, unavailable in v1.26+; use autoscaling/v2 HorizontalPodAutoscaler",
+				DeprecatedIn: "v1.23",
+				RemovedIn:    "v1.26",
+			},
+		},
+		{
+			name:     "custom_resource",
+			warning:  "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget",
+			expected: Deprecation{Warning: "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseDeprecationWarning(tt.warning))
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package common
+
+import (
+	"cmp"
+	"slices"
+)
+
+// GenerationError is a group version or a definition the listener left out of a schema file, because its OpenAPI
+// schema could not be read. The listener writes them into the schema file, so that the gateway can report them.
+type GenerationError struct {
+	GroupVersion string `json:"groupVersion"`
+	// Definition is empty if the whole group version was left out
+	Definition string `json:"definition,omitempty"`
+	Error      string `json:"error"`
+}
+
+// SortGenerationErrors sorts the errors by group version and definition
+func SortGenerationErrors(errs []GenerationError) {
+	slices.SortFunc(errs, func(a, b GenerationError) int {
+		return cmp.Or(cmp.Compare(a.GroupVersion, b.GroupVersion), cmp.Compare(a.Definition, b.Definition))
+	})
+}
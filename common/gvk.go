@@ -0,0 +1,41 @@
+package common
+
+import (
+	"encoding/json"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var ErrInvalidGVKExtension = errors.New("invalid x-kubernetes-group-version-kind extension")
+
+// GroupVersionKinds returns the kinds of the GVK extension of a resource definition, none if the definition has no
+// such extension. The extension may be decoded from the schema JSON or set by the listener, e.g. as slice of maps.
+func GroupVersionKinds(extensions map[string]any) ([]schema.GroupVersionKind, error) {
+	raw, ok := extensions[GVKExtensionKey]
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidGVKExtension, err)
+	}
+	var gvks []schema.GroupVersionKind
+	if err := json.Unmarshal(data, &gvks); err != nil {
+		return nil, errors.Join(ErrInvalidGVKExtension, err)
+	}
+
+	return gvks, nil
+}
+
+// GroupVersionKind returns the first kind of the GVK extension of a resource definition, false if the definition has
+// none or the extension is invalid
+func GroupVersionKind(extensions map[string]any) (schema.GroupVersionKind, bool) {
+	gvks, err := GroupVersionKinds(extensions)
+	if err != nil || len(gvks) == 0 {
+		return schema.GroupVersionKind{}, false
+	}
+
+	return gvks[0], true
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGroupVersionKind(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]any
+		expected   schema.GroupVersionKind
+		expectedOk bool
+	}{
+		{
+			name: "decoded_from_json",
+			extensions: map[string]any{GVKExtensionKey: []any{
+				map[string]any{"group": "apps", "version": "v1", "kind": "Deployment"},
+			}},
+			expected:   schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			expectedOk: true,
+		},
+		{
+			name: "core_group",
+			extensions: map[string]any{GVKExtensionKey: []any{
+				map[string]any{"group": "", "version": "v1", "kind": "Pod"},
+			}},
+			expected:   schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+			expectedOk: true,
+		},
+		{
+			name:       "empty",
+			extensions: map[string]any{GVKExtensionKey: []any{}},
+		},
+		{
+			name: "set_by_listener",
+			extensions: map[string]any{GVKExtensionKey: []map[string]string{
+				{"group": "apps", "version": "v1", "kind": "Deployment"},
+			}},
+			expected:   schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+			expectedOk: true,
+		},
+		{
+			name:       "invalid",
+			extensions: map[string]any{GVKExtensionKey: []any{"apps/v1"}},
+		},
+		{
+			name:       "missing",
+			extensions: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gvk, ok := GroupVersionKind(tt.extensions)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expected, gvk)
+		})
+	}
+}
+
+func TestGroupVersionKinds(t *testing.T) {
+	gvks, err := GroupVersionKinds(map[string]any{GVKExtensionKey: []any{
+		map[string]any{"group": "", "version": "v1", "kind": "DeleteOptions"},
+		map[string]any{"group": "apps", "version": "v1", "kind": "DeleteOptions"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Version: "v1", Kind: "DeleteOptions"},
+		{Group: "apps", Version: "v1", Kind: "DeleteOptions"},
+	}, gvks)
+
+	gvks, err = GroupVersionKinds(map[string]any{})
+	require.NoError(t, err)
+	assert.Empty(t, gvks)
+
+	_, err = GroupVersionKinds(map[string]any{GVKExtensionKey: make(chan int)})
+	assert.ErrorIs(t, err, ErrInvalidGVKExtension)
+
+	_, err = GroupVersionKinds(map[string]any{GVKExtensionKey: "apps/v1, Kind=Deployment"})
+	assert.ErrorIs(t, err, ErrInvalidGVKExtension)
+}
@@ -0,0 +1,98 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InputOnlyFieldsAnnotation is set on CRDs to list the fields that mutations accept but queries never return,
+// as comma-separated dot paths, e.g. spec.bootstrapToken
+const InputOnlyFieldsAnnotation = "gateway.openmfp.org/input-only-fields"
+
+var ErrInvalidInputOnlyFieldRules = errors.New("invalid input-only field rules")
+
+// InputOnlyFieldRule marks fields of a kind as input-only, for kinds whose definition can't be annotated
+type InputOnlyFieldRule struct {
+	// Group is the API group of the kind, empty for the core group
+	Group string `yaml:"group"`
+	Kind  string `yaml:"kind"`
+	// Fields are dot-separated paths, array items are addressed like the array itself
+	Fields []string `yaml:"fields"`
+}
+
+// ParseInputOnlyFieldRules parses a YAML list of rules, unknown keys are rejected to surface typos
+func ParseInputOnlyFieldRules(data []byte) ([]InputOnlyFieldRule, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var rules []InputOnlyFieldRule
+	if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.Join(ErrInvalidInputOnlyFieldRules, err)
+	}
+
+	for _, rule := range rules {
+		if rule.Kind == "" {
+			return nil, errors.Join(ErrInvalidInputOnlyFieldRules, errors.New("rule without kind"))
+		}
+	}
+
+	return rules, nil
+}
+
+// ParseFieldPaths splits the value of the InputOnlyFieldsAnnotation into sorted paths, empty entries are skipped
+func ParseFieldPaths(value string) []string {
+	var paths []string
+	for _, path := range strings.Split(value, ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			paths = append(paths, path)
+		}
+	}
+
+	slices.Sort(paths)
+	return slices.Compact(paths)
+}
+
+// InputOnlyFields returns the paths of the input-only fields stored in the extensions of a definition
+func InputOnlyFields(extensions map[string]any) []string {
+	switch raw := extensions[InputOnlyFieldsExtensionKey].(type) {
+	case []string:
+		return raw
+	case []any:
+		paths := make([]string, 0, len(raw))
+		for _, path := range raw {
+			if path, ok := path.(string); ok {
+				paths = append(paths, path)
+			}
+		}
+		return paths
+	default:
+		return nil
+	}
+}
+
+// RemoveFields deletes the fields at the dot-separated paths from the object in place, descending into arrays
+func RemoveFields(object map[string]any, paths []string) {
+	for _, path := range paths {
+		removeField(object, strings.Split(path, "."))
+	}
+}
+
+func removeField(value any, path []string) {
+	switch value := value.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			delete(value, path[0])
+			return
+		}
+		removeField(value[path[0]], path[1:])
+	case []any:
+		for _, item := range value {
+			removeField(item, path)
+		}
+	}
+}
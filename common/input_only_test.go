@@ -0,0 +1,76 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInputOnlyFieldRules(t *testing.T) {
+	rules, err := ParseInputOnlyFieldRules([]byte(`
+- group: cluster.x-k8s.io
+  kind: Cluster
+  fields: [spec.bootstrapToken]
+- kind: Secret
+  fields: [data, stringData]
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []InputOnlyFieldRule{
+		{Group: "cluster.x-k8s.io", Kind: "Cluster", Fields: []string{"spec.bootstrapToken"}},
+		{Kind: "Secret", Fields: []string{"data", "stringData"}},
+	}, rules)
+
+	rules, err = ParseInputOnlyFieldRules(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestParseInputOnlyFieldRules_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown_key":  "- kind: Secret\n  field: [data]\n",
+		"missing_kind": "- fields: [data]\n",
+		"invalid_yaml": "- kind: [",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseInputOnlyFieldRules([]byte(data))
+			assert.ErrorIs(t, err, ErrInvalidInputOnlyFieldRules)
+		})
+	}
+}
+
+func TestParseFieldPaths(t *testing.T) {
+	assert.Equal(t, []string{"spec.password", "spec.token"}, ParseFieldPaths(" spec.token,,spec.password, spec.token"))
+	assert.Empty(t, ParseFieldPaths(""))
+}
+
+func TestInputOnlyFields(t *testing.T) {
+	assert.Equal(t, []string{"spec.token"}, InputOnlyFields(map[string]any{InputOnlyFieldsExtensionKey: []any{"spec.token"}}))
+	assert.Equal(t, []string{"spec.token"}, InputOnlyFields(map[string]any{InputOnlyFieldsExtensionKey: []string{"spec.token"}}))
+	assert.Nil(t, InputOnlyFields(nil))
+}
+
+func TestRemoveFields(t *testing.T) {
+	object := map[string]any{
+		"spec": map[string]any{
+			"endpoint": "https://example.com",
+			"token":    "secret",
+			"users": []any{
+				map[string]any{"name": "a", "password": "secret"},
+				map[string]any{"name": "b"},
+			},
+		},
+	}
+
+	RemoveFields(object, []string{"spec.token", "spec.users.password", "status.missing"})
+
+	assert.Equal(t, map[string]any{
+		"spec": map[string]any{
+			"endpoint": "https://example.com",
+			"users": []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b"},
+			},
+		},
+	}, object)
+}
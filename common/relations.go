@@ -0,0 +1,82 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// RelationsAnnotation is set on CRDs to declare relationship fields for references that don't follow the *Ref naming,
+// or are nested, as a JSON list, e.g. [{"field":"spec.clusterRef","kind":"Cluster","group":"x.io"}]
+const RelationsAnnotation = "gateway.openmfp.org/relations"
+
+var ErrInvalidRelations = errors.New("invalid relations")
+
+// Relation declares that the object at Field refers to an object of the kind by its name, namespace, apiGroup and kind
+// fields, like the *Ref fields of the built-in kinds
+type Relation struct {
+	// Field is the dot-separated path of the reference, e.g. spec.clusterRef
+	Field string `json:"field"`
+	Kind  string `json:"kind"`
+	// Group is the API group of the kind, empty for the core group
+	Group string `json:"group,omitempty"`
+	// Version defaults to the preferred version of the kind
+	Version string `json:"version,omitempty"`
+	// Name is the relationship field added next to the top-level fields, defaults to the last segment of Field
+	// without the Ref suffix, e.g. cluster
+	Name string `json:"name,omitempty"`
+}
+
+// ParseRelations parses the value of the RelationsAnnotation and defaults the names of the relationship fields.
+// Unknown keys are rejected to surface typos.
+func ParseRelations(data []byte) ([]Relation, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var relations []Relation
+	if err := decoder.Decode(&relations); err != nil {
+		return nil, errors.Join(ErrInvalidRelations, err)
+	}
+
+	names := make(map[string]bool, len(relations))
+	for i, relation := range relations {
+		if relation.Field == "" || relation.Kind == "" {
+			return nil, errors.Join(ErrInvalidRelations, errors.New("relation without field or kind"))
+		}
+		if relation.Name == "" {
+			segments := strings.Split(relation.Field, ".")
+			relation.Name = strings.TrimSuffix(segments[len(segments)-1], "Ref")
+		}
+		if relation.Name == "" || names[relation.Name] {
+			return nil, errors.Join(ErrInvalidRelations, fmt.Errorf("relation of %s needs a unique name", relation.Field))
+		}
+		names[relation.Name] = true
+		relations[i] = relation
+	}
+
+	return relations, nil
+}
+
+// Relations returns the relations stored in the extensions of a definition
+func Relations(extensions map[string]any) []Relation {
+	raw, ok := extensions[RelationsExtensionKey]
+	if !ok {
+		return nil
+	}
+	if relations, ok := raw.([]Relation); ok {
+		return relations
+	}
+
+	// Definitions read from the schema files hold the decoded JSON
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil
+	}
+	var relations []Relation
+	if err := json.Unmarshal(data, &relations); err != nil {
+		return nil
+	}
+	return relations
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelations(t *testing.T) {
+	relations, err := ParseRelations([]byte(`[
+		{"field": "spec.clusterRef", "kind": "Cluster", "group": "x.io"},
+		{"field": "spec.backup.target", "kind": "Bucket", "group": "storage.x.io", "version": "v1", "name": "backupBucket"}
+	]`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []Relation{
+		{Field: "spec.clusterRef", Kind: "Cluster", Group: "x.io", Name: "cluster"},
+		{Field: "spec.backup.target", Kind: "Bucket", Group: "storage.x.io", Version: "v1", Name: "backupBucket"},
+	}, relations)
+}
+
+func TestParseRelations_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown_key":    `[{"field": "spec.clusterRef", "knd": "Cluster"}]`,
+		"invalid_json":   `[{"field": `,
+		"without_kind":   `[{"field": "spec.clusterRef"}]`,
+		"without_field":  `[{"kind": "Cluster"}]`,
+		"duplicate_name": `[{"field": "spec.clusterRef", "kind": "Cluster"}, {"field": "status.clusterRef", "kind": "Cluster"}]`,
+		"empty_name":     `[{"field": "spec.Ref", "kind": "Cluster"}]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseRelations([]byte(data))
+			assert.ErrorIs(t, err, ErrInvalidRelations)
+		})
+	}
+}
+
+func TestRelations(t *testing.T) {
+	relations := []Relation{{Field: "spec.clusterRef", Kind: "Cluster", Group: "x.io", Name: "cluster"}}
+
+	// Definitions read from the schema files hold the decoded JSON instead of the relations
+	data, err := json.Marshal(map[string]any{RelationsExtensionKey: relations})
+	require.NoError(t, err)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, relations, Relations(map[string]any{RelationsExtensionKey: relations}))
+	assert.Equal(t, relations, Relations(decoded))
+	assert.Nil(t, Relations(nil))
+}
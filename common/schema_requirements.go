@@ -0,0 +1,59 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The capability versions of the schema file format:
+//
+//	1 - schema requirements
+//	2 - deprecations as x-kubernetes-deprecation
+//	3 - input-only fields as x-openmfp-input-only-fields, hidden from the output types
+//	4 - status, scale and allow-listed subresources as x-openmfp-subresources
+//	5 - verbs as x-openmfp-verbs
+//	6 - all served versions of a kind, the preferred one named by x-openmfp-preferred-version
+//	7 - isolated invalid group versions as x-generation-errors
+//	8 - relationship fields as x-openmfp-relations
+const (
+	// GatewayCapabilityVersion is the schema capability level this gateway build is able to serve.
+	// Increase it whenever the gateway learns to handle schema file content that older gateways would misinterpret.
+	GatewayCapabilityVersion = 8
+
+	// MinGatewayCapabilityVersion is the lowest gateway capability level that can serve the schema files
+	// written by this listener build. Increase it together with schema file changes older gateways can't handle.
+	MinGatewayCapabilityVersion = 8
+)
+
+var ErrIncompatibleSchema = errors.New("schema file requires a newer gateway")
+
+// SchemaRequirements is written by the listener into every schema file, so that the gateway can detect version skew
+type SchemaRequirements struct {
+	MinGatewayCapabilityVersion int `json:"minGatewayCapabilityVersion"`
+	ListenerCapabilityVersion   int `json:"listenerCapabilityVersion"`
+}
+
+// CurrentSchemaRequirements returns the requirements of the schema files written by this build
+func CurrentSchemaRequirements() SchemaRequirements {
+	return SchemaRequirements{
+		MinGatewayCapabilityVersion: MinGatewayCapabilityVersion,
+		ListenerCapabilityVersion:   GatewayCapabilityVersion,
+	}
+}
+
+// CheckSchemaRequirements returns ErrIncompatibleSchema if the schema file demands a newer gateway than this build.
+// Schema files written before the requirements were introduced are always accepted.
+func CheckSchemaRequirements(requirements *SchemaRequirements) error {
+	return checkSchemaRequirements(requirements, GatewayCapabilityVersion)
+}
+
+func checkSchemaRequirements(requirements *SchemaRequirements, gatewayVersion int) error {
+	if requirements == nil || requirements.MinGatewayCapabilityVersion <= gatewayVersion {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: capability version %d is required, this gateway supports %d",
+		ErrIncompatibleSchema, requirements.MinGatewayCapabilityVersion, gatewayVersion,
+	)
+}
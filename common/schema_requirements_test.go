@@ -0,0 +1,83 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckSchemaRequirements(t *testing.T) {
+	tests := []struct {
+		name         string
+		requirements *SchemaRequirements
+		expectedErr  error
+	}{
+		{
+			name: "written_before_requirements",
+		},
+		{
+			name:         "current_listener",
+			requirements: &SchemaRequirements{MinGatewayCapabilityVersion: MinGatewayCapabilityVersion, ListenerCapabilityVersion: GatewayCapabilityVersion},
+		},
+		{
+			name:         "newer_listener_compatible",
+			requirements: &SchemaRequirements{MinGatewayCapabilityVersion: GatewayCapabilityVersion, ListenerCapabilityVersion: GatewayCapabilityVersion + 1},
+		},
+		{
+			name:         "newer_gateway_required_ERROR",
+			requirements: &SchemaRequirements{MinGatewayCapabilityVersion: GatewayCapabilityVersion + 1, ListenerCapabilityVersion: GatewayCapabilityVersion + 1},
+			expectedErr:  ErrIncompatibleSchema,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSchemaRequirements(tt.requirements)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestOlderGatewayRejectsCurrentSchema(t *testing.T) {
+	requirements := CurrentSchemaRequirements()
+
+	tests := []struct {
+		name           string
+		gatewayVersion int
+		expectedErr    error
+	}{
+		{
+			name:           "first_gateway_ERROR",
+			gatewayVersion: 1,
+			expectedErr:    ErrIncompatibleSchema,
+		},
+		{
+			name:           "previous_gateway_ERROR",
+			gatewayVersion: MinGatewayCapabilityVersion - 1,
+			expectedErr:    ErrIncompatibleSchema,
+		},
+		{
+			name:           "minimal_gateway",
+			gatewayVersion: MinGatewayCapabilityVersion,
+		},
+		{
+			name:           "current_gateway",
+			gatewayVersion: GatewayCapabilityVersion,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSchemaRequirements(&requirements, tt.gatewayVersion)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
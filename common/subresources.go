@@ -0,0 +1,38 @@
+package common
+
+import "slices"
+
+// Subresources the gateway generates operations for, the listener records them from the discovery of the API server
+const (
+	StatusSubresource   = "status"
+	ScaleSubresource    = "scale"
+	EvictionSubresource = "eviction"
+	TokenSubresource    = "token"
+	ProxySubresource    = "proxy"
+)
+
+// GatewaySubresources are the subresources the gateway can generate operations for
+var GatewaySubresources = []string{StatusSubresource, ScaleSubresource, EvictionSubresource, TokenSubresource, ProxySubresource}
+
+// Subresources returns the subresources the listener recorded in the extensions of a resource definition
+func Subresources(extensions map[string]any) []string {
+	switch raw := extensions[SubresourcesExtensionKey].(type) {
+	case []string:
+		return raw
+	case []any:
+		subresources := make([]string, 0, len(raw))
+		for _, subresource := range raw {
+			if subresource, ok := subresource.(string); ok {
+				subresources = append(subresources, subresource)
+			}
+		}
+		return subresources
+	default:
+		return nil
+	}
+}
+
+// HasSubresource reports whether the listener recorded the subresource in the extensions of a resource definition
+func HasSubresource(extensions map[string]any, subresource string) bool {
+	return slices.Contains(Subresources(extensions), subresource)
+}
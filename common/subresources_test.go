@@ -0,0 +1,40 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubresources(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]any
+		expected   []string
+	}{
+		{
+			name:       "strings",
+			extensions: map[string]any{SubresourcesExtensionKey: []string{"scale", "status"}},
+			expected:   []string{"scale", "status"},
+		},
+		{
+			name:       "decoded_from_json",
+			extensions: map[string]any{SubresourcesExtensionKey: []any{"status", 1}},
+			expected:   []string{"status"},
+		},
+		{
+			name:       "missing",
+			extensions: map[string]any{},
+			expected:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Subresources(tt.extensions))
+		})
+	}
+
+	assert.True(t, HasSubresource(map[string]any{SubresourcesExtensionKey: []any{"status"}}, StatusSubresource))
+	assert.False(t, HasSubresource(map[string]any{SubresourcesExtensionKey: []any{"status"}}, ScaleSubresource))
+}
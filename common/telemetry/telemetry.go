@@ -0,0 +1,246 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"gopkg.in/yaml.v3"
+)
+
+// Config selects where the traces and metrics are exported to
+type Config struct {
+	// Endpoint is the OTLP gRPC endpoint, either host:port or a URL, telemetry is disabled if it is empty
+	Endpoint string `yaml:"endpoint"`
+	// Insecure disables TLS for a host:port endpoint, URLs choose it by their scheme
+	Insecure bool `yaml:"insecure"`
+	// DisableTraces and DisableMetrics turn off a single signal
+	DisableTraces  bool `yaml:"disableTraces"`
+	DisableMetrics bool `yaml:"disableMetrics"`
+	// SampleRatio is the ratio of sampled traces between 0 and 1, 0 keeps the sampler of OTEL_TRACES_SAMPLER
+	SampleRatio float64 `yaml:"sampleRatio"`
+	// MetricsInterval is how often metrics are exported, 0 keeps the SDK default of one minute
+	MetricsInterval time.Duration `yaml:"metricsInterval"`
+}
+
+// ConfigFromEnv reads the standard OTEL_ environment variables into a Config
+func ConfigFromEnv() Config {
+	cfg := Config{
+		Endpoint:       os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		DisableTraces:  os.Getenv("OTEL_TRACES_EXPORTER") == "none",
+		DisableMetrics: os.Getenv("OTEL_METRICS_EXPORTER") == "none",
+	}
+
+	if insecure, err := strconv.ParseBool(os.Getenv("OTEL_EXPORTER_OTLP_INSECURE")); err == nil {
+		cfg.Insecure = insecure
+	}
+
+	// OTEL_METRIC_EXPORT_INTERVAL is read by the SDK itself, it is kept here so that the config file can override it
+	if interval, err := strconv.Atoi(os.Getenv("OTEL_METRIC_EXPORT_INTERVAL")); err == nil {
+		cfg.MetricsInterval = time.Duration(interval) * time.Millisecond
+	}
+
+	return cfg
+}
+
+// LoadConfig overlays the settings of the YAML file at path onto base, an empty path returns base
+func LoadConfig(path string, base Config) (Config, error) {
+	if path == "" {
+		return base, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return base, fmt.Errorf("failed to read telemetry config %s: %w", path, err)
+	}
+
+	cfg := base
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return base, fmt.Errorf("failed to parse telemetry config %s: %w", path, err)
+	}
+
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return base, fmt.Errorf("invalid sampleRatio %v in telemetry config %s, must be between 0 and 1", cfg.SampleRatio, path)
+	}
+
+	return cfg, nil
+}
+
+// Provider installs the OTel SDK as the global trace and meter provider and swaps it when the config changes.
+// Resolvers keep calling otel.Tracer, so spans go to the exporters of the latest config.
+type Provider struct {
+	serviceName string
+	log         *logger.Logger
+
+	mu             sync.Mutex
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+
+	// set by Watch
+	ctx        context.Context
+	baseConfig Config
+}
+
+// NewProvider creates a provider reporting telemetry as serviceName, unless OTEL_SERVICE_NAME overrides it
+func NewProvider(serviceName string, log *logger.Logger) *Provider {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return &Provider{
+		serviceName: serviceName,
+		log:         log,
+	}
+}
+
+// Apply replaces the exporters with the ones of cfg and shuts the previous ones down.
+// Without an endpoint, the global providers are no-ops.
+func (p *Provider) Apply(ctx context.Context, cfg Config) error {
+	var (
+		tracerProvider trace.TracerProvider = tracenoop.NewTracerProvider()
+		meterProvider  metric.MeterProvider = metricnoop.NewMeterProvider()
+		sdkTracer      *sdktrace.TracerProvider
+		sdkMeter       *sdkmetric.MeterProvider
+	)
+
+	if cfg.Endpoint != "" {
+		res, err := resource.New(ctx,
+			resource.WithAttributes(attribute.String("service.name", p.serviceName)),
+			resource.WithFromEnv(),
+			resource.WithTelemetrySDK(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create telemetry resource: %w", err)
+		}
+
+		if !cfg.DisableTraces {
+			sdkTracer, err = newTracerProvider(ctx, cfg, res)
+			if err != nil {
+				return err
+			}
+			tracerProvider = sdkTracer
+		}
+
+		if !cfg.DisableMetrics {
+			sdkMeter, err = newMeterProvider(ctx, cfg, res)
+			if err != nil {
+				if sdkTracer != nil {
+					_ = sdkTracer.Shutdown(ctx)
+				}
+				return err
+			}
+			meterProvider = sdkMeter
+		}
+	}
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+
+	p.mu.Lock()
+	oldTracer, oldMeter := p.tracerProvider, p.meterProvider
+	p.tracerProvider, p.meterProvider = sdkTracer, sdkMeter
+	p.mu.Unlock()
+
+	if err := shutdown(ctx, oldTracer, oldMeter); err != nil {
+		p.log.Error().Err(err).Msg("failed to shut down previous telemetry exporters")
+	}
+
+	p.log.Info().
+		Str("endpoint", cfg.Endpoint).
+		Bool("traces", sdkTracer != nil).
+		Bool("metrics", sdkMeter != nil).
+		Msg("configured telemetry exporters")
+
+	return nil
+}
+
+// Shutdown flushes and stops the current exporters
+func (p *Provider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	tracerProvider, meterProvider := p.tracerProvider, p.meterProvider
+	p.tracerProvider, p.meterProvider = nil, nil
+	p.mu.Unlock()
+
+	return shutdown(ctx, tracerProvider, meterProvider)
+}
+
+func newTracerProvider(ctx context.Context, cfg Config, res *resource.Resource) (*sdktrace.TracerProvider, error) {
+	var opts []otlptracegrpc.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlptracegrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlptracegrpc.WithEndpoint(cfg.Endpoint))
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace exporter: %w", err)
+	}
+
+	providerOpts := []sdktrace.TracerProviderOption{
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	}
+	if cfg.SampleRatio > 0 {
+		providerOpts = append(providerOpts, sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))))
+	}
+
+	return sdktrace.NewTracerProvider(providerOpts...), nil
+}
+
+func newMeterProvider(ctx context.Context, cfg Config, res *resource.Resource) (*sdkmetric.MeterProvider, error) {
+	var opts []otlpmetricgrpc.Option
+	if strings.Contains(cfg.Endpoint, "://") {
+		opts = append(opts, otlpmetricgrpc.WithEndpointURL(cfg.Endpoint))
+	} else {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.Endpoint))
+		if cfg.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+	}
+
+	exporter, err := otlpmetricgrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if cfg.MetricsInterval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.MetricsInterval))
+	}
+
+	return sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, readerOpts...)),
+		sdkmetric.WithResource(res),
+	), nil
+}
+
+func shutdown(ctx context.Context, tracerProvider *sdktrace.TracerProvider, meterProvider *sdkmetric.MeterProvider) error {
+	var errs []error
+	if tracerProvider != nil {
+		errs = append(errs, tracerProvider.Shutdown(ctx))
+	}
+	if meterProvider != nil {
+		errs = append(errs, meterProvider.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,125 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://collector:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("OTEL_TRACES_EXPORTER", "otlp")
+	t.Setenv("OTEL_METRICS_EXPORTER", "none")
+	t.Setenv("OTEL_METRIC_EXPORT_INTERVAL", "15000")
+
+	assert.Equal(t, Config{
+		Endpoint:        "http://collector:4317",
+		Insecure:        true,
+		DisableMetrics:  true,
+		MetricsInterval: 15 * time.Second,
+	}, ConfigFromEnv())
+}
+
+func TestLoadConfig(t *testing.T) {
+	base := Config{Endpoint: "collector:4317", Insecure: true}
+
+	tests := []struct {
+		name     string
+		content  string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "overrides_base",
+			content:  "endpoint: other:4317\nsampleRatio: 0.25\nmetricsInterval: 30s\n",
+			expected: Config{Endpoint: "other:4317", Insecure: true, SampleRatio: 0.25, MetricsInterval: 30 * time.Second},
+		},
+		{
+			name:     "empty_file_keeps_base",
+			content:  "",
+			expected: base,
+		},
+		{
+			name:     "invalid_sample_ratio",
+			content:  "sampleRatio: 2\n",
+			expected: base,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid_yaml",
+			content:  "endpoint: [",
+			expected: base,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "telemetry.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			cfg, err := LoadConfig(path, base)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+
+	t.Run("no_path", func(t *testing.T) {
+		cfg, err := LoadConfig("", base)
+		assert.NoError(t, err)
+		assert.Equal(t, base, cfg)
+	})
+}
+
+func TestProvider_Apply(t *testing.T) {
+	ctx := context.Background()
+	provider := NewProvider("test", testlogger.New().HideLogOutput().Logger)
+	t.Cleanup(func() {
+		_ = provider.Shutdown(ctx)
+	})
+
+	require.NoError(t, provider.Apply(ctx, Config{Endpoint: "localhost:4317", Insecure: true, DisableMetrics: true}))
+	assert.IsType(t, &sdktrace.TracerProvider{}, otel.GetTracerProvider())
+	assert.NotNil(t, provider.tracerProvider)
+	assert.Nil(t, provider.meterProvider)
+
+	// Reconfiguring without an endpoint falls back to no-ops and shuts the exporters down
+	require.NoError(t, provider.Apply(ctx, Config{}))
+	assert.IsType(t, tracenoop.TracerProvider{}, otel.GetTracerProvider())
+	assert.Nil(t, provider.tracerProvider)
+	assert.Nil(t, provider.meterProvider)
+}
+
+func TestProvider_OnFileChanged(t *testing.T) {
+	ctx := context.Background()
+	provider := NewProvider("test", testlogger.New().HideLogOutput().Logger)
+	provider.ctx = ctx
+	t.Cleanup(func() {
+		_ = provider.Shutdown(ctx)
+	})
+
+	path := filepath.Join(t.TempDir(), "telemetry.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("endpoint: localhost:4317\ninsecure: true\n"), 0o644))
+	provider.OnFileChanged(path)
+	assert.NotNil(t, provider.tracerProvider)
+	assert.NotNil(t, provider.meterProvider)
+
+	// An invalid file keeps the current exporters
+	require.NoError(t, os.WriteFile(path, []byte("sampleRatio: -1\n"), 0o644))
+	provider.OnFileChanged(path)
+	assert.NotNil(t, provider.tracerProvider)
+}
@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/watcher"
+)
+
+// Watch applies the config file at configPath on top of base whenever it changes and blocks until ctx is cancelled
+func (p *Provider) Watch(ctx context.Context, configPath string, base Config) error {
+	p.mu.Lock()
+	p.ctx, p.baseConfig = ctx, base
+	p.mu.Unlock()
+
+	fileWatcher, err := watcher.NewFileWatcher(p, p.log)
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	return fileWatcher.WatchOptionalFile(ctx, configPath, 500)
+}
+
+// OnFileChanged implements watcher.FileEventHandler
+func (p *Provider) OnFileChanged(filepath string) {
+	p.mu.Lock()
+	ctx, base := p.ctx, p.baseConfig
+	p.mu.Unlock()
+
+	cfg, err := LoadConfig(filepath, base)
+	if err != nil {
+		p.log.Error().Err(err).Msg("failed to reload telemetry config, keeping the current exporters")
+		return
+	}
+
+	if err := p.Apply(ctx, cfg); err != nil {
+		p.log.Error().Err(err).Msg("failed to apply telemetry config, keeping the current exporters")
+	}
+}
+
+// OnFileDeleted implements watcher.FileEventHandler
+func (p *Provider) OnFileDeleted(filepath string) {
+	p.log.Warn().Str("configPath", filepath).Msg("telemetry config file deleted, keeping the current exporters")
+}
@@ -0,0 +1,39 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// UIHintsAnnotation is set on CRDs to pass UI hints for the resource and its fields to the GraphQL schema
+const UIHintsAnnotation = "gateway.openmfp.org/ui-hints"
+
+var ErrInvalidUIHints = errors.New("invalid UI hints")
+
+// UIHint tells generic frontends how to render a resource or one of its fields
+type UIHint struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Widget      string `json:"widget,omitempty"`
+	Order       *int   `json:"order,omitempty"`
+}
+
+// UIHints are the hints of a resource, Fields is keyed by the dot-separated path of a field, e.g. spec.replicas.
+// Array items are addressed like the array itself.
+type UIHints struct {
+	UIHint
+	Fields map[string]UIHint `json:"fields,omitempty"`
+}
+
+// ParseUIHints parses the value of the UIHintsAnnotation, unknown keys are rejected to surface typos
+func ParseUIHints(data []byte) (*UIHints, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var hints UIHints
+	if err := decoder.Decode(&hints); err != nil {
+		return nil, errors.Join(ErrInvalidUIHints, err)
+	}
+
+	return &hints, nil
+}
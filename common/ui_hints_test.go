@@ -0,0 +1,38 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseUIHints(t *testing.T) {
+	hints, err := ParseUIHints([]byte(`{
+		"displayName": "Account",
+		"widget": "card",
+		"fields": {"spec.type": {"displayName": "Type", "widget": "select", "order": 1}}
+	}`))
+	require.NoError(t, err)
+
+	order := 1
+	assert.Equal(t, &UIHints{
+		UIHint: UIHint{DisplayName: "Account", Widget: "card"},
+		Fields: map[string]UIHint{
+			"spec.type": {DisplayName: "Type", Widget: "select", Order: &order},
+		},
+	}, hints)
+}
+
+func TestParseUIHints_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"unknown_key":  `{"widgt": "select"}`,
+		"invalid_json": `{"widget": `,
+		"wrong_type":   `{"order": "first"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseUIHints([]byte(data))
+			assert.ErrorIs(t, err, ErrInvalidUIHints)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package common
+
+// Verbs returns the verbs the listener recorded from the discovery of the API server in the extensions of a resource
+// definition, nil if the listener didn't record them
+func Verbs(extensions map[string]any) []string {
+	switch raw := extensions[VerbsExtensionKey].(type) {
+	case []string:
+		return raw
+	case []any:
+		verbs := make([]string, 0, len(raw))
+		for _, verb := range raw {
+			if verb, ok := verb.(string); ok {
+				verbs = append(verbs, verb)
+			}
+		}
+		return verbs
+	default:
+		return nil
+	}
+}
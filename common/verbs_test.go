@@ -0,0 +1,37 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerbs(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]any
+		expected   []string
+	}{
+		{
+			name:       "strings",
+			extensions: map[string]any{VerbsExtensionKey: []string{"get", "list"}},
+			expected:   []string{"get", "list"},
+		},
+		{
+			name:       "decoded_from_json",
+			extensions: map[string]any{VerbsExtensionKey: []any{"get", 1, "watch"}},
+			expected:   []string{"get", "watch"},
+		},
+		{
+			name:       "missing",
+			extensions: map[string]any{},
+			expected:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Verbs(tt.extensions))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openmfp/golang-commons/logger"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ConfigMapEventHandler handles changes of the watched ConfigMaps
+type ConfigMapEventHandler interface {
+	OnConfigMapChanged(configMap *corev1.ConfigMap)
+	OnConfigMapDeleted(configMap *corev1.ConfigMap)
+}
+
+// ConfigMapWatcher provides the change notifications of the ConfigMaps with a label, like FileWatcher does for files
+type ConfigMapWatcher struct {
+	clientset     kubernetes.Interface
+	namespace     string
+	labelSelector string
+	handler       ConfigMapEventHandler
+	log           *logger.Logger
+}
+
+// NewConfigMapWatcher creates a watcher of the ConfigMaps in the namespace matching the label selector
+func NewConfigMapWatcher(clientset kubernetes.Interface, namespace, labelSelector string, handler ConfigMapEventHandler, log *logger.Logger) *ConfigMapWatcher {
+	return &ConfigMapWatcher{
+		clientset:     clientset,
+		namespace:     namespace,
+		labelSelector: labelSelector,
+		handler:       handler,
+		log:           log,
+	}
+}
+
+// Start watches the ConfigMaps until the context is done. It returns once the handler was called for all existing
+// ConfigMaps, so that they are loaded before the watcher is considered ready.
+func (w *ConfigMapWatcher) Start(ctx context.Context) error {
+	factory := informers.NewSharedInformerFactoryWithOptions(w.clientset, 0,
+		informers.WithNamespace(w.namespace),
+		informers.WithTweakListOptions(func(opts *metav1.ListOptions) {
+			opts.LabelSelector = w.labelSelector
+		}),
+	)
+
+	informer := factory.Core().V1().ConfigMaps().Informer()
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if configMap, ok := obj.(*corev1.ConfigMap); ok {
+				w.handler.OnConfigMapChanged(configMap)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			configMap, ok := newObj.(*corev1.ConfigMap)
+			if !ok || configMap.ResourceVersion == oldObj.(*corev1.ConfigMap).ResourceVersion {
+				return
+			}
+			w.handler.OnConfigMapChanged(configMap)
+		},
+		DeleteFunc: func(obj interface{}) {
+			// The final state of ConfigMaps deleted while the watch was interrupted is wrapped
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if configMap, ok := obj.(*corev1.ConfigMap); ok {
+				w.handler.OnConfigMapDeleted(configMap)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add ConfigMap event handler: %w", err)
+	}
+
+	factory.Start(ctx.Done())
+	go func() {
+		<-ctx.Done()
+		factory.Shutdown()
+		w.log.Info().Msg("stopped ConfigMap watcher gracefully")
+	}()
+
+	if !cache.WaitForCacheSync(ctx.Done(), registration.HasSynced) {
+		return fmt.Errorf("failed to sync ConfigMaps in namespace %s", w.namespace)
+	}
+
+	w.log.Info().Str("namespace", w.namespace).Str("labelSelector", w.labelSelector).Msg("started watching ConfigMaps")
+
+	return nil
+}
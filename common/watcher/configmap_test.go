@@ -0,0 +1,78 @@
+package watcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// configMapEvent is a call of the ConfigMapEventHandler
+type configMapEvent struct {
+	deleted bool
+	name    string
+	data    string
+}
+
+type channelConfigMapEventHandler chan configMapEvent
+
+func (h channelConfigMapEventHandler) OnConfigMapChanged(configMap *corev1.ConfigMap) {
+	h <- configMapEvent{name: configMap.Name, data: configMap.Data["schema"]}
+}
+
+func (h channelConfigMapEventHandler) OnConfigMapDeleted(configMap *corev1.ConfigMap) {
+	h <- configMapEvent{deleted: true, name: configMap.Name}
+}
+
+func TestConfigMapWatcher(t *testing.T) {
+	newConfigMap := func(name, data string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "schemas",
+				Labels:    map[string]string{"schema": "true"},
+			},
+			Data: map[string]string{"schema": data},
+		}
+	}
+
+	clientset := fake.NewClientset(newConfigMap("existing", "v1"))
+	handler := make(channelConfigMapEventHandler, 10)
+	w := NewConfigMapWatcher(clientset, "schemas", "schema=true", handler, testlogger.New().HideLogOutput().Logger)
+
+	require.NoError(t, w.Start(t.Context()))
+
+	// Existing ConfigMaps are handled before Start returns
+	require.Len(t, handler, 1)
+	assert.Equal(t, configMapEvent{name: "existing", data: "v1"}, <-handler)
+
+	receive := func() configMapEvent {
+		select {
+		case event := <-handler:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for ConfigMap event")
+			return configMapEvent{}
+		}
+	}
+
+	configMaps := clientset.CoreV1().ConfigMaps("schemas")
+
+	_, err := configMaps.Create(t.Context(), newConfigMap("created", "v1"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, configMapEvent{name: "created", data: "v1"}, receive())
+
+	updated := newConfigMap("created", "v2")
+	updated.ResourceVersion = "2"
+	_, err = configMaps.Update(t.Context(), updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, configMapEvent{name: "created", data: "v2"}, receive())
+
+	require.NoError(t, configMaps.Delete(t.Context(), "existing", metav1.DeleteOptions{}))
+	assert.Equal(t, configMapEvent{deleted: true, name: "existing"}, receive())
+}
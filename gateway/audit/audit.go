@@ -0,0 +1,108 @@
+package audit
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultError   = "error"
+
+	// redactedValue replaces the values of secrets in the diffs
+	redactedValue = "<redacted>"
+)
+
+// Event records a mutation sent through the gateway. The API server audit log only knows the credentials of the
+// gateway, so the event keeps the identity of the caller and what the mutation changed.
+type Event struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Groups  []string  `json:"groups,omitempty"`
+	Cluster string    `json:"cluster"`
+	// Operation is the mutation, e.g. create, update, apply, delete or scale
+	Operation string `json:"operation"`
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	// LabelSelector and Names are the selector and the deleted objects of deleteMatching mutations
+	LabelSelector string   `json:"labelSelector,omitempty"`
+	Names         []string `json:"names,omitempty"`
+	DryRun        bool     `json:"dryRun"`
+	Result        string   `json:"result"`
+	Error         string   `json:"error,omitempty"`
+	// Diff are the fields the mutation changed, see Diff
+	Diff []Change `json:"diff,omitempty"`
+}
+
+// Sink writes the audit events
+type Sink interface {
+	Write(ctx context.Context, event Event) error
+}
+
+// IdentityFunc returns the user and groups the requests of ctx are sent to the cluster as
+type IdentityFunc func(ctx context.Context) (user string, groups []string)
+
+// Auditor records the mutations of a cluster
+type Auditor struct {
+	log      *logger.Logger
+	sink     Sink
+	cluster  string
+	identity IdentityFunc
+}
+
+// NewAuditor returns an auditor writing the events of the cluster to sink, identity may be nil if the callers are not
+// known, e.g. in local development
+func NewAuditor(log *logger.Logger, sink Sink, cluster string, identity IdentityFunc) *Auditor {
+	return &Auditor{
+		log:      log,
+		sink:     sink,
+		cluster:  cluster,
+		identity: identity,
+	}
+}
+
+// Record completes the event with the time, the cluster and the identity of the caller and writes it to the sink.
+// Events that can't be written are logged, the mutation was sent already.
+func (a *Auditor) Record(ctx context.Context, event Event) {
+	event.Time = time.Now().UTC()
+	event.Cluster = a.cluster
+	if a.identity != nil {
+		event.User, event.Groups = a.identity(ctx)
+	}
+	if event.Group == "" && event.Kind == "Secret" {
+		redactSecretData(event.Diff)
+	}
+
+	if err := a.sink.Write(ctx, event); err != nil {
+		a.log.Error().
+			Err(err).
+			Str("cluster", event.Cluster).
+			Str("operation", event.Operation).
+			Str("kind", event.Kind).
+			Str("namespace", event.Namespace).
+			Str("name", event.Name).
+			Str("user", event.User).
+			Msg("Failed to write audit event")
+	}
+}
+
+// redactSecretData replaces the values of the data and stringData of a secret, so that the audit log doesn't leak them
+func redactSecretData(changes []Change) {
+	for i, change := range changes {
+		if !strings.HasPrefix(change.Path, "/data") && !strings.HasPrefix(change.Path, "/stringData") {
+			continue
+		}
+		if change.Before != nil {
+			changes[i].Before = redactedValue
+		}
+		if change.After != nil {
+			changes[i].After = redactedValue
+		}
+	}
+}
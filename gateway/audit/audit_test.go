@@ -0,0 +1,187 @@
+package audit_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   map[string]any
+		after    map[string]any
+		expected []audit.Change
+	}{
+		{
+			name:   "changed_field",
+			before: map[string]any{"spec": map[string]any{"replicas": int64(1), "paused": false}},
+			after:  map[string]any{"spec": map[string]any{"replicas": int64(3), "paused": false}},
+			expected: []audit.Change{
+				{Path: "/spec/replicas", Before: int64(1), After: int64(3)},
+			},
+		},
+		{
+			name:   "added_and_removed_fields",
+			before: map[string]any{"metadata": map[string]any{"labels": map[string]any{"app": "web"}}},
+			after:  map[string]any{"metadata": map[string]any{"annotations": map[string]any{"example.com/team": "a"}}},
+			expected: []audit.Change{
+				{Path: "/metadata/annotations/example.com~1team", After: "a"},
+				{Path: "/metadata/labels/app", Before: "web"},
+			},
+		},
+		{
+			name:   "lists_are_compared_whole",
+			before: map[string]any{"spec": map[string]any{"ports": []any{int64(80)}}},
+			after:  map[string]any{"spec": map[string]any{"ports": []any{int64(80), int64(443)}}},
+			expected: []audit.Change{
+				{Path: "/spec/ports", Before: []any{int64(80)}, After: []any{int64(80), int64(443)}},
+			},
+		},
+		{
+			name:  "created",
+			after: map[string]any{"metadata": map[string]any{"name": "web"}, "data": map[string]any{"key": "value"}},
+			expected: []audit.Change{
+				{Path: "/data/key", After: "value"},
+				{Path: "/metadata/name", After: "web"},
+			},
+		},
+		{
+			name: "ignored_fields",
+			before: map[string]any{"metadata": map[string]any{
+				"resourceVersion": "1",
+				"managedFields":   []any{map[string]any{"manager": "kubectl"}},
+			}},
+			after: map[string]any{"metadata": map[string]any{
+				"resourceVersion": "2",
+				"managedFields":   []any{map[string]any{"manager": "graphql-gateway"}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, audit.Diff(tt.before, tt.after))
+		})
+	}
+}
+
+type recordingSink struct {
+	events []audit.Event
+	err    error
+}
+
+func (s *recordingSink) Write(_ context.Context, event audit.Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestAuditor_Record(t *testing.T) {
+	sink := &recordingSink{err: errors.New("sink unavailable")}
+	auditor := audit.NewAuditor(testlogger.New().HideLogOutput().Logger, sink, "production", func(context.Context) (string, []string) {
+		return "jane@example.com", []string{"admins"}
+	})
+
+	auditor.Record(context.Background(), audit.Event{
+		Operation: "update",
+		Version:   "v1",
+		Kind:      "Secret",
+		Name:      "credentials",
+		Result:    audit.ResultSuccess,
+		Diff: []audit.Change{
+			{Path: "/data/password", Before: "b2xk", After: "bmV3"},
+			{Path: "/stringData/token", After: "plain"},
+			{Path: "/metadata/labels/app", After: "web"},
+		},
+	})
+
+	require.Len(t, sink.events, 1)
+	event := sink.events[0]
+	assert.Equal(t, "production", event.Cluster)
+	assert.Equal(t, "jane@example.com", event.User)
+	assert.Equal(t, []string{"admins"}, event.Groups)
+	assert.False(t, event.Time.IsZero())
+	assert.Equal(t, []audit.Change{
+		{Path: "/data/password", Before: "<redacted>", After: "<redacted>"},
+		{Path: "/stringData/token", After: "<redacted>"},
+		{Path: "/metadata/labels/app", After: "web"},
+	}, event.Diff)
+}
+
+func TestNewSink(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		sink, err := audit.NewSink("")
+		require.NoError(t, err)
+		assert.Nil(t, sink)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := audit.NewSink("syslog")
+		assert.ErrorIs(t, err, audit.ErrUnknownSink)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := audit.NewSink("file:" + path)
+		require.NoError(t, err)
+
+		require.NoError(t, sink.Write(context.Background(), audit.Event{Operation: "create", Name: "web"}))
+		require.NoError(t, sink.Write(context.Background(), audit.Event{Operation: "delete", Name: "web"}))
+		require.NoError(t, sink.(io.Closer).Close())
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		lines := bytes.Split(bytes.TrimSpace(content), []byte("\n"))
+		require.Len(t, lines, 2)
+		var event audit.Event
+		require.NoError(t, json.Unmarshal(lines[1], &event))
+		assert.Equal(t, "delete", event.Operation)
+	})
+}
+
+func TestWebhookSink(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		expectedErr error
+	}{
+		{name: "accepted", status: http.StatusAccepted},
+		{name: "rejected", status: http.StatusInternalServerError, expectedErr: audit.ErrWebhookEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received audit.Event
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			sink, err := audit.NewSink(server.URL)
+			require.NoError(t, err)
+
+			err = sink.Write(context.Background(), audit.Event{Operation: "scale", Kind: "Deployment", Name: "web"})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "web", received.Name)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a field whose value differs between the object before and after a mutation. Before is missing for added
+// fields and After for removed ones.
+type Change struct {
+	// Path is the JSON pointer of the field, e.g. /spec/replicas
+	Path   string `json:"path"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// ignoredPaths change with every write and are left out of the diffs
+var ignoredPaths = map[string]bool{
+	"/metadata/managedFields":   true,
+	"/metadata/resourceVersion": true,
+}
+
+// Diff returns the changes between the objects, sorted by path. Objects are compared field by field, lists are
+// compared as a whole. A nil before lists every field of a created object, a nil after every field of a deleted one.
+func Diff(before, after map[string]any) []Change {
+	var changes []Change
+	diffValues("", before, after, &changes)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+	return changes
+}
+
+func diffValues(path string, before, after any, changes *[]Change) {
+	if ignoredPaths[path] {
+		return
+	}
+
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) {
+		for key, value := range beforeMap {
+			diffValues(path+"/"+escapePointer(key), value, afterMap[key], changes)
+		}
+		for key, value := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				diffValues(path+"/"+escapePointer(key), nil, value, changes)
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(before, after) {
+		*changes = append(*changes, Change{Path: path, Before: before, After: after})
+	}
+}
+
+// escapePointer escapes a key for a JSON pointer, see RFC 6901
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookTimeout is how long a webhook may take to accept an event
+const WebhookTimeout = 5 * time.Second
+
+var (
+	ErrUnknownSink  = errors.New("audit sink must be stdout, file:<path> or an http(s) URL")
+	ErrOpenFile     = errors.New("failed to open audit file")
+	ErrWebhookEvent = errors.New("audit webhook rejected the event")
+)
+
+// NewSink returns the sink of the target: stdout, file:<path> or the http(s) URL of a webhook. An empty target
+// returns nil, auditing is disabled.
+func NewSink(target string) (Sink, error) {
+	switch {
+	case target == "":
+		return nil, nil
+	case target == "stdout":
+		return NewWriterSink(os.Stdout), nil
+	case strings.HasPrefix(target, "file:"):
+		path := strings.TrimPrefix(target, "file:")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return nil, errors.Join(ErrOpenFile, err)
+		}
+		return &WriterSink{w: f, file: f}, nil
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		return NewWebhookSink(target, WebhookTimeout), nil
+	default:
+		return nil, fmt.Errorf("%w, got %q", ErrUnknownSink, target)
+	}
+}
+
+// WriterSink writes every event as a line of JSON
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+	// file is the file the sink opened, which it closes, nil for the writers passed to NewWriterSink
+	file *os.File
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Write(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// Events of concurrent mutations must not be interleaved
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// Close flushes the events to the file the sink opened and closes it, the writers passed to NewWriterSink are left open
+func (s *WriterSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	return errors.Join(s.file.Sync(), s.file.Close())
+}
+
+// WebhookSink posts every event as JSON to a URL, which has to answer with a 2xx status
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, timeout time.Duration) *WebhookSink {
+	return &WebhookSink{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *WebhookSink) Write(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	// The event is sent after the mutation, so it is not canceled with the operation
+	req, err := http.NewRequestWithContext(context.WithoutCancel(ctx), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: status %d", ErrWebhookEvent, resp.StatusCode)
+	}
+	return nil
+}
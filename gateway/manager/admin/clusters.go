@@ -0,0 +1,361 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+// DefaultSchemaTimeout bounds the schema generation of a registration unless WithSchemaTimeout is used
+const DefaultSchemaTimeout = time.Minute
+
+// ClusterPrefix is prepended to the names of the registered clusters, so that they are stored and served apart from
+// the clusters of the listener, e.g. a cluster registered as staging is served under /adhoc/staging/graphql
+const ClusterPrefix = "adhoc"
+
+var (
+	ErrReadAdminToken      = errors.New("failed to read admin token")
+	ErrEmptyAdminToken     = errors.New("admin token is empty")
+	ErrInvalidRegistration = errors.New("invalid cluster registration")
+)
+
+// ClusterRegistration registers a target cluster without a ClusterAccess resource and the listener. The fields are a
+// subset of the ClusterAccess spec: the cluster is only reached with a bearer token or a kubeconfig, and the secrets and
+// ConfigMaps referenced by Auth and CA are read from the namespace of the admin API in the cluster the gateway runs in.
+// Credential plugins and proxies aren't supported, since the admin token would otherwise allow running commands in the
+// gateway pod and reading any secret the gateway can read.
+type ClusterRegistration struct {
+	// Name of the cluster, served under /adhoc/{name}/graphql
+	Name string `json:"name"`
+	// Labels group the cluster like the labels of a ClusterAccess resource
+	Labels map[string]string `json:"labels,omitempty"`
+	// Host is the URL of the API server of the cluster
+	Host string `json:"host"`
+	// CA references the CA certificate of the API server
+	CA *gatewayv1alpha1.CAConfig `json:"ca,omitempty"`
+	// Auth references the bearer token or the kubeconfig the cluster is reached with
+	Auth *gatewayv1alpha1.AuthConfig `json:"auth,omitempty"`
+	// AuthMode, ClaimMappings and Defaults are the fields of the ClusterAccess spec
+	AuthMode      string                           `json:"authMode,omitempty"`
+	ClaimMappings *gatewayv1alpha1.ClaimMappings   `json:"claimMappings,omitempty"`
+	Defaults      *gatewayv1alpha1.ClusterDefaults `json:"defaults,omitempty"`
+}
+
+// RegisteredCluster is a cluster registered through the admin API
+type RegisteredCluster struct {
+	Name string `json:"name"`
+	// Cluster is the name the schema is stored and served under
+	Cluster string `json:"cluster"`
+}
+
+// SchemaGenerator resolves the OpenAPI schema of the cluster reached with cfg
+type SchemaGenerator func(ctx context.Context, cfg *rest.Config) ([]byte, error)
+
+// ClustersHandler registers and deregisters target clusters at runtime. The schema of a registered cluster is
+// generated by the gateway and written to the schema storage like the schemas of the listener, the schema watcher of
+// the gateway loads it from there, also after restarts.
+//
+//	GET    /admin/clusters         lists the registered clusters
+//	POST   /admin/clusters         registers the cluster of the ClusterRegistration in the body, or updates it
+//	DELETE /admin/clusters/{name}  deregisters a cluster
+//
+// Every request needs the admin token as bearer token.
+type ClustersHandler struct {
+	log             *logger.Logger
+	token           []byte
+	ioHandler       workspacefile.IOHandler
+	namespace       string
+	k8sClient       client.Client
+	schemaGenerator SchemaGenerator
+	schemaTimeout   time.Duration
+	mux             *http.ServeMux
+	// versions and reload serve the schema versions, nil if they are not kept, see WithSchemaVersions
+	versions workspacefile.SchemaVersions
+	reload   ReloadFunc
+}
+
+// LoadToken reads the admin token from a file, surrounding whitespace is ignored
+func LoadToken(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadAdminToken, err)
+	}
+
+	token := []byte(strings.TrimSpace(string(data)))
+	if len(token) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyAdminToken, path)
+	}
+	return token, nil
+}
+
+// NewClustersHandler creates the handler of the admin API. k8sClient reads the secrets and ConfigMaps referenced by
+// the registrations, only in namespace.
+func NewClustersHandler(log *logger.Logger, token []byte, ioHandler workspacefile.IOHandler, namespace string, k8sClient client.Client, schemaGenerator SchemaGenerator) *ClustersHandler {
+	h := &ClustersHandler{
+		log:             log,
+		token:           token,
+		ioHandler:       ioHandler,
+		namespace:       namespace,
+		k8sClient:       client.NewNamespacedClient(k8sClient, namespace),
+		schemaGenerator: schemaGenerator,
+		schemaTimeout:   DefaultSchemaTimeout,
+		mux:             http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /admin/clusters", h.list)
+	h.mux.HandleFunc("POST /admin/clusters", h.register)
+	h.mux.HandleFunc("DELETE /admin/clusters/{name}", h.deregister)
+
+	return h
+}
+
+// WithSchemaTimeout bounds the time a registration may take to read the references and generate the schema, the
+// request fails with 504 Gateway Timeout afterwards
+func (h *ClustersHandler) WithSchemaTimeout(timeout time.Duration) *ClustersHandler {
+	h.schemaTimeout = timeout
+	return h
+}
+
+// NewSchemaGenerator returns a generator resolving the schemas like the listener does. The requests to the cluster are
+// cancelled once ctx is done.
+func NewSchemaGenerator(log *logger.Logger, opts ...apischema.ResolverOption) SchemaGenerator {
+	resolver := apischema.NewResolver(log, opts...)
+
+	return func(ctx context.Context, cfg *rest.Config) ([]byte, error) {
+		cfg = rest.CopyConfig(cfg)
+		cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			return &contextRoundTripper{ctx: ctx, next: rt}
+		})
+
+		dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create discovery client: %w", err)
+		}
+
+		httpClt, err := rest.HTTPClientFor(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+		}
+		rm, err := apiutil.NewDynamicRESTMapper(cfg, httpClt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+		}
+
+		return resolver.Resolve(dc, rm)
+	}
+}
+
+// contextRoundTripper sends the requests with its context, since discovery doesn't pass one
+type contextRoundTripper struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
+
+func (rt *contextRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return rt.next.RoundTrip(req.WithContext(rt.ctx))
+}
+
+func (h *ClustersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), h.token) != 1 {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *ClustersHandler) list(w http.ResponseWriter, _ *http.Request) {
+	clusterNames, err := h.ioHandler.List()
+	if err != nil {
+		h.log.Error().Err(err).Msg("Failed to list registered clusters")
+		http.Error(w, "Failed to list registered clusters", http.StatusInternalServerError)
+		return
+	}
+
+	clusters := []RegisteredCluster{}
+	for _, clusterName := range clusterNames {
+		if name, ok := strings.CutPrefix(clusterName, ClusterPrefix+"/"); ok {
+			clusters = append(clusters, RegisteredCluster{Name: name, Cluster: clusterName})
+		}
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, clusters)
+}
+
+func (h *ClustersHandler) register(w http.ResponseWriter, r *http.Request) {
+	var registration ClusterRegistration
+	decoder := json.NewDecoder(r.Body)
+	// Fields of the ClusterAccess spec that registrations don't support, e.g. proxy, are rejected instead of ignored
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&registration); err != nil {
+		http.Error(w, "Invalid cluster registration: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateRegistration(registration, h.namespace); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// References without a namespace are read from the namespace of the admin API instead of the default namespace
+	for _, refNamespace := range referenceNamespaces(registration) {
+		if *refNamespace == "" {
+			*refNamespace = h.namespace
+		}
+	}
+
+	clusterName := ClusterPrefix + "/" + registration.Name
+	log := h.log.With().Str("cluster", clusterName).Str("host", registration.Host).Logger()
+
+	// The schema is generated while the client waits, so a slow or unresponsive cluster must not block the request
+	ctx, cancel := context.WithTimeout(r.Context(), h.schemaTimeout)
+	defer cancel()
+
+	cfg, err := auth.BuildConfig(ctx, registration.Host, registration.Auth, registration.CA, h.k8sClient)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to build config of registered cluster")
+		http.Error(w, "Failed to build the cluster config: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	schema, err := h.schemaGenerator(ctx, cfg)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to generate schema of registered cluster")
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			http.Error(w, fmt.Sprintf("Generating the schema of the cluster took longer than %s", h.schemaTimeout), http.StatusGatewayTimeout)
+			return
+		}
+		http.Error(w, "Failed to generate the schema of the cluster: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	schema, err = auth.NewMetadataInjector(h.log, h.k8sClient).InjectClusterMetadata(ctx, schema, auth.MetadataInjectionConfig{
+		Host:          registration.Host,
+		Path:          clusterName,
+		Auth:          registration.Auth,
+		CA:            registration.CA,
+		Defaults:      registration.Defaults,
+		AuthMode:      registration.AuthMode,
+		ClaimMappings: registration.ClaimMappings,
+		Labels:        registration.Labels,
+	})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to inject metadata of registered cluster")
+		http.Error(w, "Failed to add the cluster metadata to the schema: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.ioHandler.Write(schema, clusterName); err != nil {
+		log.Error().Err(err).Msg("Failed to store schema of registered cluster")
+		http.Error(w, "Failed to store the schema of the cluster", http.StatusInternalServerError)
+		return
+	}
+
+	log.Info().Msg("Registered cluster")
+	writeJSON(w, http.StatusCreated, RegisteredCluster{Name: registration.Name, Cluster: clusterName})
+}
+
+func (h *ClustersHandler) deregister(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		http.Error(w, fmt.Sprintf("%s: name %q: %s", ErrInvalidRegistration, name, strings.Join(errs, ", ")), http.StatusBadRequest)
+		return
+	}
+
+	clusterName := ClusterPrefix + "/" + name
+	if err := h.ioHandler.Delete(clusterName); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			http.Error(w, fmt.Sprintf("Cluster %s is not registered", name), http.StatusNotFound)
+			return
+		}
+		h.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to delete schema of registered cluster")
+		http.Error(w, "Failed to delete the schema of the cluster", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info().Str("cluster", clusterName).Msg("Deregistered cluster")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateRegistration checks the fields the gateway doesn't validate when building the cluster config, and that the
+// registration only uses a bearer token or kubeconfig and references objects in namespace
+func validateRegistration(registration ClusterRegistration, namespace string) error {
+	if errs := validation.IsDNS1123Subdomain(registration.Name); len(errs) > 0 {
+		return fmt.Errorf("%w: name %q: %s", ErrInvalidRegistration, registration.Name, strings.Join(errs, ", "))
+	}
+	if registration.Host == "" {
+		return fmt.Errorf("%w: host is required", ErrInvalidRegistration)
+	}
+	if a := registration.Auth; a != nil {
+		if a.Exec != nil || a.Cloud != nil || a.ServiceAccount != nil || a.ClientCertificateRef != nil {
+			return fmt.Errorf("%w: auth only supports secretRef and kubeconfigSecretRef", ErrInvalidRegistration)
+		}
+		if a.SecretRef != nil && a.KubeconfigSecretRef != nil {
+			return fmt.Errorf("%w: auth must set only one of secretRef and kubeconfigSecretRef", ErrInvalidRegistration)
+		}
+	}
+	refNamespaces := referenceNamespaces(registration)
+	for _, field := range slices.Sorted(maps.Keys(refNamespaces)) {
+		if refNamespace := *refNamespaces[field]; refNamespace != "" && refNamespace != namespace {
+			return fmt.Errorf("%w: %s.namespace %q, registrations may only reference objects in namespace %q", ErrInvalidRegistration, field, refNamespace, namespace)
+		}
+	}
+	if registration.AuthMode != "" && registration.AuthMode != gatewayv1alpha1.AuthModeClaims && registration.AuthMode != gatewayv1alpha1.AuthModeTokenReview {
+		return fmt.Errorf("%w: unknown authMode %q", ErrInvalidRegistration, registration.AuthMode)
+	}
+	return nil
+}
+
+// referenceNamespaces returns the namespaces of the secrets and ConfigMaps referenced by the registration by field
+func referenceNamespaces(registration ClusterRegistration) map[string]*string {
+	namespaces := map[string]*string{}
+	if a := registration.Auth; a != nil {
+		if a.SecretRef != nil {
+			namespaces["auth.secretRef"] = &a.SecretRef.Namespace
+		}
+		if a.KubeconfigSecretRef != nil {
+			namespaces["auth.kubeconfigSecretRef"] = &a.KubeconfigSecretRef.Namespace
+		}
+	}
+	if ca := registration.CA; ca != nil {
+		if ca.SecretRef != nil {
+			namespaces["ca.secretRef"] = &ca.SecretRef.Namespace
+		}
+		if ca.ConfigMapRef != nil {
+			namespaces["ca.configMapRef"] = &ca.ConfigMapRef.Namespace
+		}
+	}
+	return namespaces
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
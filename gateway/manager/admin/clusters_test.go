@@ -0,0 +1,134 @@
+package admin_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/admin"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+func TestLoadToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("secret\n"), 0o600))
+	token, err := admin.LoadToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("secret"), token)
+
+	require.NoError(t, os.WriteFile(path, []byte(" \n"), 0o600))
+	_, err = admin.LoadToken(path)
+	assert.ErrorIs(t, err, admin.ErrEmptyAdminToken)
+
+	_, err = admin.LoadToken(filepath.Join(t.TempDir(), "missing"))
+	assert.ErrorIs(t, err, admin.ErrReadAdminToken)
+}
+
+func TestClustersHandler(t *testing.T) {
+	ioHandler, err := workspacefile.NewIOHandler(t.TempDir())
+	require.NoError(t, err)
+	// Clusters of the listener are neither listed nor deregistered
+	require.NoError(t, ioHandler.Write([]byte(`{}`), "production"))
+
+	// The secrets are read through a client restricted to the namespace of the gateway, which looks up their scope
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(corev1.SchemeGroupVersion.WithKind("Secret"), meta.RESTScopeNamespace)
+	k8sClient := fake.NewClientBuilder().WithRESTMapper(mapper).WithObjects(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "staging-token", Namespace: "gateway"}, Data: map[string][]byte{"token": []byte("staging-token")}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "gateway-token", Namespace: "kube-system"}, Data: map[string][]byte{"token": []byte("gateway-token")}},
+	).Build()
+
+	var generatedFor []string
+	bearerTokens := map[string]string{}
+	handler := admin.NewClustersHandler(testlogger.New().HideLogOutput().Logger, []byte("secret"), ioHandler, "gateway", k8sClient,
+		func(ctx context.Context, cfg *rest.Config) ([]byte, error) {
+			generatedFor = append(generatedFor, cfg.Host)
+			switch cfg.Host {
+			case "https://unreachable":
+				return nil, errors.New("connection refused")
+			case "https://slow":
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			bearerTokens[cfg.Host] = cfg.BearerToken
+			return []byte(`{"components":{"schemas":{}}}`), nil
+		}).WithSchemaTimeout(50 * time.Millisecond)
+
+	serve := func(method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		token          string
+		body           string
+		expectedStatus int
+	}{
+		{name: "missing_token", method: http.MethodGet, target: "/admin/clusters", expectedStatus: http.StatusUnauthorized},
+		{name: "wrong_token", method: http.MethodGet, target: "/admin/clusters", token: "guess", expectedStatus: http.StatusUnauthorized},
+		{name: "invalid_name", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"Staging/1","host":"https://staging"}`, expectedStatus: http.StatusBadRequest},
+		{name: "missing_host", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging"}`, expectedStatus: http.StatusBadRequest},
+		{name: "path_set", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://staging","path":"root"}`, expectedStatus: http.StatusBadRequest},
+		{name: "proxy_set", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://staging","proxy":{"url":"https://proxy"}}`, expectedStatus: http.StatusBadRequest},
+		{name: "exec_auth", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://staging","auth":{"exec":{"command":"aws"}}}`, expectedStatus: http.StatusBadRequest},
+		{name: "service_account_auth", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://staging","auth":{"serviceAccount":{"name":"gateway"}}}`, expectedStatus: http.StatusBadRequest},
+		{name: "cross_namespace_ref", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://attacker","auth":{"secretRef":{"name":"gateway-token","namespace":"kube-system","key":"token"}}}`, expectedStatus: http.StatusBadRequest},
+		{name: "unreachable_cluster", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"unreachable","host":"https://unreachable"}`, expectedStatus: http.StatusBadGateway},
+		{name: "slow_cluster", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"slow","host":"https://slow"}`, expectedStatus: http.StatusGatewayTimeout},
+		{name: "register_with_token", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"token","host":"https://token","auth":{"secretRef":{"name":"staging-token","key":"token"}}}`, expectedStatus: http.StatusCreated},
+		{name: "register", method: http.MethodPost, target: "/admin/clusters", token: "secret", body: `{"name":"staging","host":"https://staging","labels":{"env":"test"}}`, expectedStatus: http.StatusCreated},
+		{name: "deregister_unknown", method: http.MethodDelete, target: "/admin/clusters/production", token: "secret", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.method, tt.target, tt.token, tt.body)
+			assert.Equal(t, tt.expectedStatus, rec.Code, rec.Body.String())
+		})
+	}
+	assert.Equal(t, []string{"https://unreachable", "https://slow", "https://token", "https://staging"}, generatedFor)
+	// References without a namespace are read from the namespace of the admin API
+	assert.Equal(t, "staging-token", bearerTokens["https://token"])
+
+	schema, err := ioHandler.Read(admin.ClusterPrefix + "/staging")
+	require.NoError(t, err)
+	var stored map[string]any
+	require.NoError(t, json.Unmarshal(schema, &stored))
+	assert.Equal(t, map[string]any{"host": "https://staging", "path": "adhoc/staging", "labels": map[string]any{"env": "test"}}, stored["x-cluster-metadata"])
+
+	rec := serve(http.MethodGet, "/admin/clusters", "secret", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var clusters []admin.RegisteredCluster
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&clusters))
+	assert.Equal(t, []admin.RegisteredCluster{{Name: "staging", Cluster: "adhoc/staging"}, {Name: "token", Cluster: "adhoc/token"}}, clusters)
+
+	assert.Equal(t, http.StatusNoContent, serve(http.MethodDelete, "/admin/clusters/staging", "secret", "").Code)
+	_, err = ioHandler.Read(admin.ClusterPrefix + "/staging")
+	assert.Error(t, err)
+	_, err = ioHandler.Read("production")
+	assert.NoError(t, err)
+}
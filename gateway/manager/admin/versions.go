@@ -0,0 +1,110 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+// SchemaPin pins a cluster to one of its schema versions
+type SchemaPin struct {
+	Cluster string `json:"cluster"`
+	Version string `json:"version"`
+}
+
+// ReloadFunc loads the schema of a cluster again, e.g. after it was pinned to another version
+type ReloadFunc func(clusterName string) error
+
+// WithSchemaVersions serves the kept schema versions of all clusters, including the ones of the listener, and lets them
+// be pinned to a version until they are unpinned again:
+//
+//	GET    /admin/schemas/versions?cluster={cluster}  lists the versions of the cluster, newest first
+//	PUT    /admin/schemas/pin                        pins the cluster of the SchemaPin in the body to its version
+//	DELETE /admin/schemas/pin?cluster={cluster}      serves the current schema of the cluster again
+//
+// The cluster is reloaded with reload after it was pinned or unpinned.
+func (h *ClustersHandler) WithSchemaVersions(versions workspacefile.SchemaVersions, reload ReloadFunc) *ClustersHandler {
+	h.versions = versions
+	h.reload = reload
+
+	h.mux.HandleFunc("GET /admin/schemas/versions", h.listVersions)
+	h.mux.HandleFunc("PUT /admin/schemas/pin", h.pin)
+	h.mux.HandleFunc("DELETE /admin/schemas/pin", h.unpin)
+
+	return h
+}
+
+func (h *ClustersHandler) listVersions(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	if clusterName == "" {
+		http.Error(w, "The cluster query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	versions, err := h.versions.Versions(clusterName)
+	if err != nil {
+		h.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to list schema versions")
+		http.Error(w, "Failed to list the schema versions of the cluster", http.StatusInternalServerError)
+		return
+	}
+	if versions == nil {
+		versions = []workspacefile.SchemaVersion{}
+	}
+
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func (h *ClustersHandler) pin(w http.ResponseWriter, r *http.Request) {
+	var pin SchemaPin
+	if err := json.NewDecoder(r.Body).Decode(&pin); err != nil {
+		http.Error(w, "Invalid schema pin: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if pin.Cluster == "" || pin.Version == "" {
+		http.Error(w, "Invalid schema pin: cluster and version are required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.versions.Pin(pin.Cluster, pin.Version); err != nil {
+		if errors.Is(err, workspacefile.ErrUnknownVersion) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.log.Error().Err(err).Str("cluster", pin.Cluster).Msg("Failed to pin schema version")
+		http.Error(w, "Failed to pin the schema version", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info().Str("cluster", pin.Cluster).Str("version", pin.Version).Msg("Pinned schema version")
+	h.reloadCluster(w, pin.Cluster)
+}
+
+func (h *ClustersHandler) unpin(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+	if clusterName == "" {
+		http.Error(w, "The cluster query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.versions.Unpin(clusterName); err != nil {
+		h.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to unpin schema version")
+		http.Error(w, "Failed to unpin the schema version", http.StatusInternalServerError)
+		return
+	}
+
+	h.log.Info().Str("cluster", clusterName).Msg("Unpinned schema version")
+	h.reloadCluster(w, clusterName)
+}
+
+// reloadCluster serves the pinned or current schema of the cluster, the pin is kept if the schema doesn't build
+func (h *ClustersHandler) reloadCluster(w http.ResponseWriter, clusterName string) {
+	if err := h.reload(clusterName); err != nil {
+		h.log.Warn().Err(err).Str("cluster", clusterName).Msg("Failed to reload cluster")
+		http.Error(w, "Failed to reload the cluster: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
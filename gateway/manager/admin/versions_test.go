@@ -0,0 +1,63 @@
+package admin_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/admin"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+func TestClustersHandler_SchemaVersions(t *testing.T) {
+	schemasDir := filepath.Join(t.TempDir(), "definitions")
+	ioHandler, err := workspacefile.NewIOHandler(schemasDir, workspacefile.WithVersions(workspacefile.VersionsDir(schemasDir, ""), 5))
+	require.NoError(t, err)
+	require.NoError(t, ioHandler.Write([]byte(`{"v":1}`), "root:orgs"))
+	require.NoError(t, ioHandler.Write([]byte(`{"v":2}`), "root:orgs"))
+
+	var reloaded []string
+	handler := admin.NewClustersHandler(testlogger.New().HideLogOutput().Logger, []byte("secret"), ioHandler, "gateway", fake.NewClientBuilder().Build(), nil).
+		WithSchemaVersions(ioHandler, func(clusterName string) error {
+			reloaded = append(reloaded, clusterName)
+			return nil
+		})
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(http.MethodGet, "/admin/schemas/versions?cluster=root:orgs", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var versions []workspacefile.SchemaVersion
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &versions))
+	require.Len(t, versions, 2)
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/admin/schemas/versions", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPut, "/admin/schemas/pin", `{"cluster":"root:orgs","version":"20000101T000000.000000000Z"}`).Code)
+	assert.Empty(t, reloaded)
+
+	rec = serve(http.MethodPut, "/admin/schemas/pin", `{"cluster":"root:orgs","version":"`+versions[1].Version+`"}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	pinned, ok := ioHandler.PinnedVersion("root:orgs")
+	assert.True(t, ok)
+	assert.Equal(t, versions[1].Version, pinned)
+
+	rec = serve(http.MethodDelete, "/admin/schemas/pin?cluster=root:orgs", "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	_, ok = ioHandler.PinnedVersion("root:orgs")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"root:orgs", "root:orgs"}, reloaded)
+}
@@ -13,7 +13,11 @@ type ClusterManager interface {
 	UpdateCluster(schemaFilePath string) error
 	RemoveCluster(schemaFilePath string) error
 	GetCluster(name string) (*targetcluster.TargetCluster, bool)
+	SchemaStatus() []targetcluster.SchemaStatus
+	FieldUsage() []targetcluster.FieldUsage
 	ServeHTTP(w http.ResponseWriter, r *http.Request)
+	Drain(ctx context.Context) error
+	Draining() bool
 	Close() error
 }
 
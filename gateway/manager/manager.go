@@ -2,17 +2,29 @@ package manager
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/openmfp/golang-commons/logger"
 	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/admin"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/watcher"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
 )
 
 // Service orchestrates the domain-driven architecture with target clusters
@@ -20,26 +32,70 @@ type Service struct {
 	log             *logger.Logger
 	clusterRegistry ClusterManager
 	schemaWatcher   SchemaWatcher
+	// adminHandler registers clusters at runtime, nil if the admin API is disabled
+	adminHandler *admin.ClustersHandler
 }
 
 // NewGateway creates a new domain-driven Gateway instance
 func NewGateway(ctx context.Context, log *logger.Logger, appCfg appConfig.Config) (*Service, error) {
+	tokenVerifier := newTokenVerifier(appCfg)
+
+	rtOpts := []roundtripper.Option{roundtripper.WithTokenVerifier(tokenVerifier)}
+	if appCfg.Gateway.Anonymous.ServiceAccount != "" {
+		serviceAccount, err := resolver.ParseServiceAccount(appCfg.Gateway.Anonymous.ServiceAccount)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid anonymous service account")
+		}
+		rtOpts = append(rtOpts, roundtripper.WithAnonymousServiceAccount(serviceAccount))
+	}
+
 	// Create round tripper factory
-	roundTripperFactory := targetcluster.RoundTripperFactory(func(adminRT http.RoundTripper, tlsConfig rest.TLSClientConfig) http.RoundTripper {
-		return roundtripper.New(log, appCfg, adminRT, roundtripper.NewUnauthorizedRoundTripper())
+	roundTripperFactory := targetcluster.RoundTripperFactory(func(adminRT http.RoundTripper, tlsConfig rest.TLSClientConfig, opts ...roundtripper.Option) http.RoundTripper {
+		opts = slices.Concat(rtOpts, opts)
+		return roundtripper.New(log, appCfg, adminRT, roundtripper.NewUnauthorizedRoundTripper(), opts...)
 	})
 
-	clusterRegistry := targetcluster.NewClusterRegistry(log, appCfg, roundTripperFactory)
+	clusterGroups, err := targetcluster.LoadClusterGroups(appCfg.Gateway.ClusterGroupsPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load cluster groups")
+	}
+
+	auditSink, err := audit.NewSink(appCfg.Gateway.Audit.Sink)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create audit sink")
+	}
+
+	schemaVersions, err := newSchemaVersions(appCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open schema versions")
+	}
+
+	clusterRegistry := targetcluster.NewClusterRegistry(log, appCfg, roundTripperFactory).
+		WithClusterGroups(clusterGroups).
+		WithTokenVerifier(tokenVerifier).
+		WithAuditSink(auditSink).
+		WithSchemaVersions(schemaVersions)
 
-	schemaWatcher, err := watcher.NewFileWatcher(log, clusterRegistry)
+	schemaWatcher, err := newSchemaWatcher(log, appCfg, clusterRegistry)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create schema watcher")
 	}
 
+	adminHandler, err := newAdminHandler(log, appCfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create admin API")
+	}
+	if adminHandler != nil && schemaVersions != nil {
+		adminHandler.WithSchemaVersions(schemaVersions, func(clusterName string) error {
+			return clusterRegistry.UpdateCluster(filepath.Join(appCfg.OpenApiDefinitionsPath, clusterName))
+		})
+	}
+
 	gateway := &Service{
 		log:             log,
 		clusterRegistry: clusterRegistry,
 		schemaWatcher:   schemaWatcher,
+		adminHandler:    adminHandler,
 	}
 
 	// Initialize schema watcher with context
@@ -49,17 +105,168 @@ func NewGateway(ctx context.Context, log *logger.Logger, appCfg appConfig.Config
 
 	log.Info().
 		Str("definitions_path", appCfg.OpenApiDefinitionsPath).
+		Str("schema_storage", appCfg.SchemaStorage.Backend).
 		Str("port", appCfg.Gateway.Port).
 		Msg("Gateway initialized successfully")
 
 	return gateway, nil
 }
 
+// newTokenVerifier returns the verifier of the configured OIDC issuer, nil if the tokens are not verified
+func newTokenVerifier(appCfg appConfig.Config) *roundtripper.TokenVerifier {
+	if appCfg.Gateway.OIDC.IssuerURL == "" {
+		return nil
+	}
+
+	return roundtripper.NewTokenVerifier(roundtripper.TokenVerifierConfig{
+		IssuerURL: appCfg.Gateway.OIDC.IssuerURL,
+		JWKSURL:   appCfg.Gateway.OIDC.JWKSURL,
+		Audience:  appCfg.Gateway.OIDC.Audience,
+		ClockSkew: appCfg.Gateway.OIDC.ClockSkew,
+	}, &http.Client{Timeout: 10 * time.Second})
+}
+
+// newSchemaWatcher returns the watcher of the configured schema storage backend
+func newSchemaWatcher(log *logger.Logger, appCfg appConfig.Config, clusterRegistry *targetcluster.ClusterRegistry) (SchemaWatcher, error) {
+	switch appCfg.SchemaStorage.Backend {
+	case "", workspacefile.FilesystemBackend:
+		fileWatcher, err := watcher.NewFileWatcher(log, clusterRegistry)
+		if err != nil {
+			return nil, err
+		}
+		if appCfg.SchemaStorage.Versions > 0 {
+			fileWatcher.WithVersionsDir(workspacefile.VersionsDir(appCfg.OpenApiDefinitionsPath, appCfg.SchemaStorage.VersionDir))
+		}
+
+		return fileWatcher, nil
+	case workspacefile.ConfigMapBackend:
+		restCfg, err := workspacefile.StorageRestConfig(appCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		clientset, err := kubernetes.NewForConfig(restCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create schema storage client: %w", err)
+		}
+
+		return watcher.NewConfigMapWatcher(log, clusterRegistry, clientset, appCfg.SchemaStorage.Namespace), nil
+	case workspacefile.S3Backend:
+		storage, err := workspacefile.S3IOHandlerFromConfig(appCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return watcher.NewS3Watcher(log, clusterRegistry, storage, appCfg.SchemaStorage.S3.PollPeriod), nil
+	case workspacefile.GCSBackend:
+		storage, err := workspacefile.GCSIOHandlerFromConfig(appCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		return watcher.NewS3Watcher(log, clusterRegistry, storage, appCfg.SchemaStorage.GCS.PollPeriod), nil
+	default:
+		return nil, fmt.Errorf("%w %q", workspacefile.ErrUnknownStorageBackend, appCfg.SchemaStorage.Backend)
+	}
+}
+
+// newSchemaVersions returns the schema versions kept by the filesystem backend, nil if versioning is disabled
+func newSchemaVersions(appCfg appConfig.Config) (workspacefile.SchemaVersions, error) {
+	if appCfg.SchemaStorage.Versions <= 0 {
+		return nil, nil
+	}
+
+	return workspacefile.NewIOHandler(appCfg.OpenApiDefinitionsPath, workspacefile.VersionsFromConfig(appCfg))
+}
+
+// newAdminHandler returns the admin API writing the schemas of registered clusters to the configured schema storage,
+// nil if no admin token is configured
+func newAdminHandler(log *logger.Logger, appCfg appConfig.Config) (*admin.ClustersHandler, error) {
+	if appCfg.Gateway.Admin.TokenPath == "" {
+		return nil, nil
+	}
+
+	token, err := admin.LoadToken(appCfg.Gateway.Admin.TokenPath)
+	if err != nil {
+		return nil, err
+	}
+
+	ioHandler, err := workspacefile.NewIOHandlerFromConfig(appCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// The secrets and ConfigMaps referenced by the registrations are read from the admin namespace of the cluster the
+	// gateway runs in
+	restCfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the config of the gateway's cluster: %w", err)
+	}
+	k8sClient, err := client.New(restCfg, client.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client of the gateway's cluster: %w", err)
+	}
+
+	schemaGenerator := admin.NewSchemaGenerator(log, apischema.ResolverOptionsFromConfig(appCfg)...)
+	return admin.NewClustersHandler(log, token, ioHandler, appCfg.Gateway.Admin.Namespace, k8sClient, schemaGenerator).
+		WithSchemaTimeout(appCfg.Gateway.Admin.SchemaTimeout), nil
+}
+
 // ServeHTTP delegates HTTP requests to the cluster registry
 func (g *Service) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	g.clusterRegistry.ServeHTTP(w, r)
 }
 
+// ServeSchemaStatus reports which schema files are served and why the others were rejected,
+// e.g. because they were written by a listener that requires a newer gateway
+func (g *Service) ServeSchemaStatus(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(g.clusterRegistry.SchemaStatus()); err != nil {
+		g.log.Error().Err(err).Msg("Failed to write schema status")
+	}
+}
+
+// ServeFieldUsage reports how often the fields of every cluster were selected by the sampled operations
+func (g *Service) ServeFieldUsage(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(g.clusterRegistry.FieldUsage()); err != nil {
+		g.log.Error().Err(err).Msg("Failed to write field usage")
+	}
+}
+
+// FaultInjectionHandler controls the faults injected into the API server requests, nil unless the gateway was built
+// with the faultinjection tag
+func (g *Service) FaultInjectionHandler() http.Handler {
+	return targetcluster.FaultInjectionHandler()
+}
+
+// AdminHandler serves the admin API registering clusters at runtime, nil if it is disabled
+func (g *Service) AdminHandler() http.Handler {
+	if g.adminHandler == nil {
+		return nil
+	}
+	return g.adminHandler
+}
+
+// Drain rejects new operations and ends the open subscriptions with a complete event, it returns once they ended or
+// ctx is done
+func (g *Service) Drain(ctx context.Context) error {
+	if g.clusterRegistry == nil {
+		return nil
+	}
+	return g.clusterRegistry.Drain(ctx)
+}
+
+// ServeReadiness answers the readiness probe, which fails while the gateway drains so that no new requests are routed
+// to it
+func (g *Service) ServeReadiness(w http.ResponseWriter, _ *http.Request) {
+	if g.clusterRegistry != nil && g.clusterRegistry.Draining() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 // Close gracefully shuts down the gateway and all its services
 func (g *Service) Close() error {
 	if g.clusterRegistry != nil {
@@ -2,6 +2,8 @@ package manager
 
 import (
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
@@ -131,3 +133,22 @@ func TestService_Close(t *testing.T) {
 		})
 	}
 }
+
+func TestService_ServeReadiness(t *testing.T) {
+	for name, draining := range map[string]bool{"ready": false, "draining": true} {
+		t.Run(name, func(t *testing.T) {
+			mockCluster := mocks.NewMockClusterManager(t)
+			mockCluster.EXPECT().Draining().Return(draining)
+			service := &Service{log: testlogger.New().Logger, clusterRegistry: mockCluster}
+
+			rec := httptest.NewRecorder()
+			service.ServeReadiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if draining {
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+			} else {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	http "net/http"
 
 	mock "github.com/stretchr/testify/mock"
@@ -68,6 +70,144 @@ func (_c *MockClusterManager_Close_Call) RunAndReturn(run func() error) *MockClu
 	return _c
 }
 
+// Drain provides a mock function with given fields: ctx
+func (_m *MockClusterManager) Drain(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Drain")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockClusterManager_Drain_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Drain'
+type MockClusterManager_Drain_Call struct {
+	*mock.Call
+}
+
+// Drain is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockClusterManager_Expecter) Drain(ctx interface{}) *MockClusterManager_Drain_Call {
+	return &MockClusterManager_Drain_Call{Call: _e.mock.On("Drain", ctx)}
+}
+
+func (_c *MockClusterManager_Drain_Call) Run(run func(ctx context.Context)) *MockClusterManager_Drain_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *MockClusterManager_Drain_Call) Return(_a0 error) *MockClusterManager_Drain_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClusterManager_Drain_Call) RunAndReturn(run func(context.Context) error) *MockClusterManager_Drain_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Draining provides a mock function with no fields
+func (_m *MockClusterManager) Draining() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Draining")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockClusterManager_Draining_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Draining'
+type MockClusterManager_Draining_Call struct {
+	*mock.Call
+}
+
+// Draining is a helper method to define mock.On call
+func (_e *MockClusterManager_Expecter) Draining() *MockClusterManager_Draining_Call {
+	return &MockClusterManager_Draining_Call{Call: _e.mock.On("Draining")}
+}
+
+func (_c *MockClusterManager_Draining_Call) Run(run func()) *MockClusterManager_Draining_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockClusterManager_Draining_Call) Return(_a0 bool) *MockClusterManager_Draining_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClusterManager_Draining_Call) RunAndReturn(run func() bool) *MockClusterManager_Draining_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FieldUsage provides a mock function with no fields
+func (_m *MockClusterManager) FieldUsage() []targetcluster.FieldUsage {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for FieldUsage")
+	}
+
+	var r0 []targetcluster.FieldUsage
+	if rf, ok := ret.Get(0).(func() []targetcluster.FieldUsage); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]targetcluster.FieldUsage)
+		}
+	}
+
+	return r0
+}
+
+// MockClusterManager_FieldUsage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FieldUsage'
+type MockClusterManager_FieldUsage_Call struct {
+	*mock.Call
+}
+
+// FieldUsage is a helper method to define mock.On call
+func (_e *MockClusterManager_Expecter) FieldUsage() *MockClusterManager_FieldUsage_Call {
+	return &MockClusterManager_FieldUsage_Call{Call: _e.mock.On("FieldUsage")}
+}
+
+func (_c *MockClusterManager_FieldUsage_Call) Run(run func()) *MockClusterManager_FieldUsage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockClusterManager_FieldUsage_Call) Return(_a0 []targetcluster.FieldUsage) *MockClusterManager_FieldUsage_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClusterManager_FieldUsage_Call) RunAndReturn(run func() []targetcluster.FieldUsage) *MockClusterManager_FieldUsage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetCluster provides a mock function with given fields: name
 func (_m *MockClusterManager) GetCluster(name string) (*targetcluster.TargetCluster, bool) {
 	ret := _m.Called(name)
@@ -218,6 +358,53 @@ func (_c *MockClusterManager_RemoveCluster_Call) RunAndReturn(run func(string) e
 	return _c
 }
 
+// SchemaStatus provides a mock function with no fields
+func (_m *MockClusterManager) SchemaStatus() []targetcluster.SchemaStatus {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for SchemaStatus")
+	}
+
+	var r0 []targetcluster.SchemaStatus
+	if rf, ok := ret.Get(0).(func() []targetcluster.SchemaStatus); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]targetcluster.SchemaStatus)
+		}
+	}
+
+	return r0
+}
+
+// MockClusterManager_SchemaStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SchemaStatus'
+type MockClusterManager_SchemaStatus_Call struct {
+	*mock.Call
+}
+
+// SchemaStatus is a helper method to define mock.On call
+func (_e *MockClusterManager_Expecter) SchemaStatus() *MockClusterManager_SchemaStatus_Call {
+	return &MockClusterManager_SchemaStatus_Call{Call: _e.mock.On("SchemaStatus")}
+}
+
+func (_c *MockClusterManager_SchemaStatus_Call) Run(run func()) *MockClusterManager_SchemaStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockClusterManager_SchemaStatus_Call) Return(_a0 []targetcluster.SchemaStatus) *MockClusterManager_SchemaStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockClusterManager_SchemaStatus_Call) RunAndReturn(run func() []targetcluster.SchemaStatus) *MockClusterManager_SchemaStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ServeHTTP provides a mock function with given fields: w, r
 func (_m *MockClusterManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	_m.Called(w, r)
@@ -0,0 +1,55 @@
+package roundtripper
+
+import (
+	"fmt"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/transport"
+)
+
+// WithAnonymousServiceAccount sends the requests without a token as the service account, instead of denying them.
+// These requests may only read, the access of the service account is further limited by its RBAC rules.
+func WithAnonymousServiceAccount(serviceAccount types.NamespacedName) Option {
+	return func(rt *roundTripper) {
+		// The user and groups the API server authenticates the tokens of the service account as
+		rt.anonymous = &transport.ImpersonationConfig{
+			UserName: "system:serviceaccount:" + serviceAccount.Namespace + ":" + serviceAccount.Name,
+			Groups:   []string{"system:serviceaccounts", "system:serviceaccounts:" + serviceAccount.Namespace, "system:authenticated"},
+		}
+	}
+}
+
+// anonymousReviewResources are the resources requests without a token may create, they only review the access of the
+// fallback identity
+var anonymousReviewResources = map[string]bool{
+	"selfsubjectaccessreviews": true,
+	"selfsubjectrulesreviews":  true,
+}
+
+// isReadRequest reports whether req only reads objects or reviews the access of the caller
+func isReadRequest(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+
+	parts := apiPathParts(req.URL.Path)
+	return req.Method == http.MethodPost && len(parts) == 4 && parts[0] == "apis" && parts[1] == "authorization.k8s.io" &&
+		anonymousReviewResources[parts[3]]
+}
+
+// impersonateAnonymous sends a request without a token as the anonymous service account, denying writes
+func (rt *roundTripper) impersonateAnonymous(req *http.Request) (*http.Response, error) {
+	if !isReadRequest(req) {
+		rt.log.Debug().Str("path", req.URL.Path).Str("method", req.Method).Msg("Request without token writes, denying")
+		return forbiddenResponse(req, fmt.Sprintf("requests without a token are read-only, %s is not allowed", req.Method)), nil
+	}
+
+	rt.log.Debug().Str("path", req.URL.Path).Str("impersonateUser", rt.anonymous.UserName).Msg("No token found, impersonating anonymous service account")
+
+	req = req.Clone(req.Context())
+	req.Header.Del("Authorization")
+	impersonatingRT := transport.NewImpersonatingRoundTripper(*rt.anonymous, rt.adminRT)
+
+	return impersonatingRT.RoundTrip(req)
+}
@@ -0,0 +1,104 @@
+package roundtripper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestRoundTripper_AnonymousServiceAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		token          string
+		expectedStatus int
+		expectedUser   string
+		expectedGroups []string
+	}{
+		{
+			name:           "list",
+			method:         http.MethodGet,
+			path:           "/api/v1/namespaces/default/pods",
+			expectedStatus: http.StatusOK,
+			expectedUser:   "system:serviceaccount:public:dashboard-reader",
+			expectedGroups: []string{"system:serviceaccounts", "system:serviceaccounts:public", "system:authenticated"},
+		},
+		{
+			name:           "access_review",
+			method:         http.MethodPost,
+			path:           "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+			expectedStatus: http.StatusOK,
+			expectedUser:   "system:serviceaccount:public:dashboard-reader",
+			expectedGroups: []string{"system:serviceaccounts", "system:serviceaccounts:public", "system:authenticated"},
+		},
+		{name: "create", method: http.MethodPost, path: "/api/v1/namespaces/default/configmaps", expectedStatus: http.StatusForbidden},
+		{name: "delete", method: http.MethodDelete, path: "/api/v1/namespaces/default/pods/web", expectedStatus: http.StatusForbidden},
+		{name: "patch", method: http.MethodPatch, path: "/apis/apps/v1/namespaces/default/deployments/web", expectedStatus: http.StatusForbidden},
+		{
+			name:           "token_is_forwarded",
+			method:         http.MethodPost,
+			path:           "/api/v1/namespaces/default/configmaps",
+			token:          "user-token",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captured *http.Request
+			mockAdmin := &mocks.MockRoundTripper{}
+			if tt.expectedStatus == http.StatusOK {
+				mockAdmin.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil).Run(func(req *http.Request) {
+					captured = req
+				})
+			}
+
+			appCfg := appConfig.Config{}
+			rt := roundtripper.New(testlogger.New().HideLogOutput().Logger, appCfg, mockAdmin, roundtripper.NewUnauthorizedRoundTripper(),
+				roundtripper.WithAnonymousServiceAccount(types.NamespacedName{Namespace: "public", Name: "dashboard-reader"}))
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = req.WithContext(context.WithValue(req.Context(), roundtripper.TokenKey{}, tt.token))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode)
+			mockAdmin.AssertExpectations(t)
+
+			if tt.expectedStatus != http.StatusOK {
+				return
+			}
+			require.NotNil(t, captured)
+			if tt.token != "" {
+				assert.Equal(t, "Bearer "+tt.token, captured.Header.Get("Authorization"))
+				assert.Empty(t, captured.Header.Get("Impersonate-User"))
+				return
+			}
+			assert.Empty(t, captured.Header.Get("Authorization"))
+			assert.Equal(t, tt.expectedUser, captured.Header.Get("Impersonate-User"))
+			assert.Equal(t, tt.expectedGroups, captured.Header.Values("Impersonate-Group"))
+		})
+	}
+}
+
+func TestRoundTripper_NoAnonymousServiceAccount(t *testing.T) {
+	mockAdmin := &mocks.MockRoundTripper{}
+	rt := roundtripper.New(testlogger.New().HideLogOutput().Logger, appConfig.Config{}, mockAdmin, roundtripper.NewUnauthorizedRoundTripper())
+
+	resp, err := rt.RoundTrip(httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", nil))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	mockAdmin.AssertExpectations(t)
+}
@@ -0,0 +1,116 @@
+package roundtripper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"k8s.io/client-go/transport"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+var ErrUsernameClaim = errors.New("username claim is missing or not a string")
+
+// ClaimMappings selects the claims of a token the impersonated user is read from
+type ClaimMappings struct {
+	// Username is the claim holding the name of the user, it is required
+	Username string `json:"username,omitempty"`
+	// Groups is the claim holding the groups of the user, a list or a single string
+	Groups string `json:"groups,omitempty"`
+	// UID is the claim holding the UID of the user
+	UID string `json:"uid,omitempty"`
+	// Extra maps the keys of the impersonated extra attributes to the claims holding their values
+	Extra map[string]string `json:"extra,omitempty"`
+}
+
+// ClaimMappingsFromConfig returns the claim mappings configured for all clusters. The extra claims are formatted as
+// key=claim pairs separated by commas, entries without a claim are skipped, see config.Config.Validate.
+func ClaimMappingsFromConfig(appCfg config.Config) ClaimMappings {
+	mappings := ClaimMappings{
+		Username: appCfg.Gateway.UsernameClaim,
+		Groups:   appCfg.Gateway.GroupsClaim,
+		UID:      appCfg.Gateway.UIDClaim,
+	}
+
+	for _, entry := range strings.Split(appCfg.Gateway.ExtraClaims, ",") {
+		key, claim, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || key == "" || claim == "" {
+			continue
+		}
+		if mappings.Extra == nil {
+			mappings.Extra = make(map[string]string)
+		}
+		mappings.Extra[key] = claim
+	}
+
+	return mappings
+}
+
+// WithClaimMappings reads the impersonated user from the claims of mappings, e.g. the ones of a ClusterAccess. The
+// mappings that are set replace the ones configured before, the extra claims replace all configured extra claims.
+func WithClaimMappings(mappings ClaimMappings) Option {
+	return func(rt *roundTripper) {
+		if mappings.Username != "" {
+			rt.claims.Username = mappings.Username
+		}
+		if mappings.Groups != "" {
+			rt.claims.Groups = mappings.Groups
+		}
+		if mappings.UID != "" {
+			rt.claims.UID = mappings.UID
+		}
+		if len(mappings.Extra) > 0 {
+			rt.claims.Extra = mappings.Extra
+		}
+	}
+}
+
+// impersonationConfig returns the user to impersonate for the claims of a token. Only the username claim is required,
+// the other claims are left out if the token doesn't carry them.
+func (m ClaimMappings) impersonationConfig(claims jwt.MapClaims) (transport.ImpersonationConfig, error) {
+	userName, _ := claims[m.Username].(string)
+	if userName == "" {
+		return transport.ImpersonationConfig{}, fmt.Errorf("%w: %q", ErrUsernameClaim, m.Username)
+	}
+
+	impersonation := transport.ImpersonationConfig{UserName: userName}
+	if m.UID != "" {
+		impersonation.UID, _ = claims[m.UID].(string)
+	}
+	if m.Groups != "" {
+		impersonation.Groups = claimStrings(claims[m.Groups])
+	}
+	for key, claim := range m.Extra {
+		if values := claimStrings(claims[claim]); len(values) > 0 {
+			if impersonation.Extra == nil {
+				impersonation.Extra = make(map[string][]string)
+			}
+			impersonation.Extra[key] = values
+		}
+	}
+
+	return impersonation, nil
+}
+
+// claimStrings returns the strings of a claim that is a string or a list of strings
+func claimStrings(value any) []string {
+	switch value := value.(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	default:
+		return nil
+	}
+}
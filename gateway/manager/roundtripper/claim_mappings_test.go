@@ -0,0 +1,124 @@
+package roundtripper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestRoundTripper_ClaimMappings(t *testing.T) {
+	claims := jwt.MapClaims{
+		"email":     "user@example.com",
+		"sub":       "1234",
+		"groups":    []any{"admins", "developers"},
+		"role":      "viewer",
+		"tenant_id": "acme",
+	}
+
+	tests := []struct {
+		name               string
+		configure          func(cfg *appConfig.Config)
+		opts               []roundtripper.Option
+		expectedStatusCode int
+		expectedHeaders    http.Header
+	}{
+		{
+			name:               "username_only",
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: http.Header{
+				"Impersonate-User": {"user@example.com"},
+			},
+		},
+		{
+			name: "gateway_mappings",
+			configure: func(cfg *appConfig.Config) {
+				cfg.Gateway.GroupsClaim = "groups"
+				cfg.Gateway.UIDClaim = "sub"
+				cfg.Gateway.ExtraClaims = "example.com/tenant=tenant_id, example.com/missing=missing"
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: http.Header{
+				"Impersonate-User":                       {"user@example.com"},
+				"Impersonate-Group":                      {"admins", "developers"},
+				"Impersonate-Uid":                        {"1234"},
+				"Impersonate-Extra-Example.com%2ftenant": {"acme"},
+			},
+		},
+		{
+			name: "cluster_mappings_replace_gateway_mappings",
+			configure: func(cfg *appConfig.Config) {
+				cfg.Gateway.GroupsClaim = "groups"
+			},
+			opts: []roundtripper.Option{
+				roundtripper.WithClaimMappings(roundtripper.ClaimMappings{Username: "sub", Groups: "role"}),
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedHeaders: http.Header{
+				"Impersonate-User":  {"1234"},
+				"Impersonate-Group": {"viewer"},
+			},
+		},
+		{
+			name: "missing_username_claim",
+			opts: []roundtripper.Option{
+				roundtripper.WithClaimMappings(roundtripper.ClaimMappings{Username: "preferred_username"}),
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdmin := &mocks.MockRoundTripper{}
+			mockUnauthorized := &mocks.MockRoundTripper{}
+
+			var captured *http.Request
+			if tt.expectedStatusCode == http.StatusOK {
+				mockAdmin.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil).Run(func(req *http.Request) {
+					captured = req
+				})
+			} else {
+				mockUnauthorized.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusUnauthorized}, nil)
+			}
+
+			appCfg := appConfig.Config{}
+			appCfg.Gateway.ShouldImpersonate = true
+			appCfg.Gateway.UsernameClaim = "email"
+			if tt.configure != nil {
+				tt.configure(&appCfg)
+			}
+
+			rt := roundtripper.New(testlogger.New().HideLogOutput().Logger, appCfg, mockAdmin, mockUnauthorized, tt.opts...)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", nil)
+			req = req.WithContext(context.WithValue(req.Context(), roundtripper.TokenKey{}, createTestToken(t, claims)))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+			if tt.expectedHeaders == nil {
+				return
+			}
+
+			require.NotNil(t, captured)
+			impersonationHeaders := http.Header{}
+			for key, values := range captured.Header {
+				if key != "Authorization" {
+					impersonationHeaders[key] = values
+				}
+			}
+			assert.Equal(t, tt.expectedHeaders, impersonationHeaders)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package roundtripper
+
+import "time"
+
+// SetMinRefreshInterval lets tests rotate the keys of the issuer without waiting for the refresh interval
+func (v *TokenVerifier) SetMinRefreshInterval(interval time.Duration) {
+	v.keysMu.Lock()
+	defer v.keysMu.Unlock()
+	v.minRefreshInterval = interval
+}
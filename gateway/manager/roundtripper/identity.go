@@ -0,0 +1,49 @@
+package roundtripper
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// IdentityResolver returns the user and groups the requests of a context are sent to the cluster as, e.g. for the
+// audit log of the gateway
+type IdentityResolver interface {
+	Identity(ctx context.Context) (user string, groups []string)
+}
+
+// Identity returns the user and groups the requests of ctx are sent as, following RoundTrip. The user is empty if the
+// requests are sent with the admin credentials or are denied. Without impersonation, the user is read from the
+// unverified username claim of the token and must not be used for authorization decisions.
+func (rt *roundTripper) Identity(ctx context.Context) (string, []string) {
+	if rt.appCfg.LocalDevelopment {
+		return "", nil
+	}
+
+	token, ok := ctx.Value(TokenKey{}).(string)
+	if !ok || token == "" {
+		if rt.anonymous != nil {
+			return rt.anonymous.UserName, rt.anonymous.Groups
+		}
+		return "", nil
+	}
+
+	if rt.appCfg.Gateway.ShouldImpersonate && rt.reviewer != nil {
+		// Reviews are cached, this doesn't send another token review for the request
+		user, err := rt.reviewer.Review(ctx, token)
+		if err != nil {
+			return "", nil
+		}
+		return user.Username, user.Groups
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return "", nil
+	}
+	impersonation, err := rt.claims.impersonationConfig(claims)
+	if err != nil {
+		return "", nil
+	}
+	return impersonation.UserName, impersonation.Groups
+}
@@ -0,0 +1,71 @@
+package roundtripper_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/types"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestRoundTripper_Identity(t *testing.T) {
+	tests := []struct {
+		name             string
+		token            string
+		localDevelopment bool
+		opts             []roundtripper.Option
+		expectedUser     string
+		expectedGroups   []string
+	}{
+		{
+			name:           "claims",
+			token:          createTestToken(t, jwt.MapClaims{"email": "jane@example.com", "groups": []any{"admins"}}),
+			expectedUser:   "jane@example.com",
+			expectedGroups: []string{"admins"},
+		},
+		{
+			name:             "local_development",
+			token:            createTestToken(t, jwt.MapClaims{"email": "jane@example.com"}),
+			localDevelopment: true,
+		},
+		{
+			name:         "missing_username_claim",
+			token:        createTestToken(t, jwt.MapClaims{"sub": "jane"}),
+			expectedUser: "",
+		},
+		{
+			name:           "anonymous",
+			opts:           []roundtripper.Option{roundtripper.WithAnonymousServiceAccount(types.NamespacedName{Namespace: "public", Name: "reader"})},
+			expectedUser:   "system:serviceaccount:public:reader",
+			expectedGroups: []string{"system:serviceaccounts", "system:serviceaccounts:public", "system:authenticated"},
+		},
+		{
+			name: "no_token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCfg := appConfig.Config{LocalDevelopment: tt.localDevelopment}
+			appCfg.Gateway.ShouldImpersonate = true
+			appCfg.Gateway.UsernameClaim = "email"
+			appCfg.Gateway.GroupsClaim = "groups"
+
+			rt := roundtripper.New(testlogger.New().HideLogOutput().Logger, appCfg, &mocks.MockRoundTripper{},
+				roundtripper.NewUnauthorizedRoundTripper(), tt.opts...)
+			resolver, ok := rt.(roundtripper.IdentityResolver)
+			require.True(t, ok)
+
+			user, groups := resolver.Identity(context.WithValue(context.Background(), roundtripper.TokenKey{}, tt.token))
+			assert.Equal(t, tt.expectedUser, user)
+			assert.Equal(t, tt.expectedGroups, groups)
+		})
+	}
+}
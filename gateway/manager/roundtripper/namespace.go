@@ -0,0 +1,70 @@
+package roundtripper
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// NamespaceKey is the context key of the namespace a request is bound to, e.g. by a namespace endpoint. Requests
+// bound to a namespace may only access the objects of the namespace.
+type NamespaceKey struct{}
+
+// namespaceIndependentResources are the cluster-scoped resources that are allowed for requests bound to a namespace,
+// they only review the caller's own access
+var namespaceIndependentResources = map[string]bool{
+	"selfsubjectaccessreviews": true,
+	"selfsubjectrulesreviews":  true,
+	"selfsubjectreviews":       true,
+}
+
+// isNamespaceRequest reports whether req only accesses the objects of namespace, the namespace itself or the version
+// of the cluster with GET, or reviews the access of the caller
+func isNamespaceRequest(req *http.Request, namespace string) bool {
+	parts := apiPathParts(req.URL.Path)
+	if len(parts) == 1 && parts[0] == "version" {
+		return req.Method == http.MethodGet
+	}
+
+	// Skip /api/<version> and /apis/<group>/<version>
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		parts = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		parts = parts[3:]
+	default:
+		return false
+	}
+
+	switch {
+	case len(parts) == 1:
+		return req.Method == http.MethodPost && namespaceIndependentResources[parts[0]]
+	case len(parts) == 2 && parts[0] == "namespaces":
+		return parts[1] == namespace && req.Method == http.MethodGet
+	case len(parts) > 2 && parts[0] == "namespaces":
+		return parts[1] == namespace
+	default:
+		return false
+	}
+}
+
+// forbiddenResponse returns the Status the API server would return for a forbidden request, so that clients report
+// it like one
+func forbiddenResponse(req *http.Request, message string) *http.Response {
+	body, _ := json.Marshal(map[string]interface{}{
+		"kind":       "Status",
+		"apiVersion": "v1",
+		"status":     "Failure",
+		"message":    message,
+		"reason":     "Forbidden",
+		"code":       http.StatusForbidden,
+	})
+
+	return &http.Response{
+		StatusCode: http.StatusForbidden,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Request:    req,
+		Body:       io.NopCloser(bytes.NewReader(body)),
+	}
+}
@@ -0,0 +1,63 @@
+package roundtripper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestRoundTripper_NamespaceBinding(t *testing.T) {
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedAllowed bool
+	}{
+		{name: "namespaced_resource", method: http.MethodGet, path: "/api/v1/namespaces/team-a/pods", expectedAllowed: true},
+		{name: "namespaced_group_resource", method: http.MethodPost, path: "/apis/apps/v1/namespaces/team-a/deployments", expectedAllowed: true},
+		{name: "kcp_workspace", method: http.MethodDelete, path: "/clusters/root:org/api/v1/namespaces/team-a/configmaps/a", expectedAllowed: true},
+		{name: "own_namespace", method: http.MethodGet, path: "/api/v1/namespaces/team-a", expectedAllowed: true},
+		{name: "access_review", method: http.MethodPost, path: "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", expectedAllowed: true},
+		{name: "discovery", method: http.MethodGet, path: "/apis/apps/v1", expectedAllowed: true},
+		{name: "version", method: http.MethodGet, path: "/version", expectedAllowed: true},
+		{name: "other_namespace", method: http.MethodGet, path: "/api/v1/namespaces/team-b/pods"},
+		{name: "all_namespaces", method: http.MethodGet, path: "/api/v1/pods"},
+		{name: "cluster_scoped_resource", method: http.MethodGet, path: "/apis/rbac.authorization.k8s.io/v1/clusterroles"},
+		{name: "delete_own_namespace", method: http.MethodDelete, path: "/api/v1/namespaces/team-a"},
+		{name: "list_namespaces", method: http.MethodGet, path: "/api/v1/namespaces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdmin := &mocks.MockRoundTripper{}
+			if tt.expectedAllowed {
+				mockAdmin.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil)
+			}
+
+			appCfg := appConfig.Config{LocalDevelopment: true}
+			rt := roundtripper.New(testlogger.New().HideLogOutput().Logger, appCfg, mockAdmin, roundtripper.NewUnauthorizedRoundTripper())
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req = req.WithContext(context.WithValue(req.Context(), roundtripper.NamespaceKey{}, "team-a"))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			if tt.expectedAllowed {
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+			} else {
+				assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+			}
+			mockAdmin.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package roundtripper
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -17,17 +19,64 @@ type roundTripper struct {
 	log                     *logger.Logger
 	adminRT, unauthorizedRT http.RoundTripper
 	appCfg                  config.Config
+	verifier                *TokenVerifier
+	reviewer                *TokenReviewer
+	claims                  ClaimMappings
+	// anonymous is the identity of the requests without a token, nil denies them
+	anonymous *transport.ImpersonationConfig
 }
 
 type unauthorizedRoundTripper struct{}
 
-func New(log *logger.Logger, appCfg config.Config, adminRoundTripper, unauthorizedRT http.RoundTripper) http.RoundTripper {
-	return &roundTripper{
+// Option configures the RoundTripper returned by New
+type Option func(*roundTripper)
+
+// WithTokenVerifier verifies the tokens with verifier before they are forwarded, denying the requests of invalid tokens
+// before any authorization or impersonation header is set. A nil verifier forwards the tokens unverified.
+func WithTokenVerifier(verifier *TokenVerifier) Option {
+	return func(rt *roundTripper) {
+		rt.verifier = verifier
+	}
+}
+
+// WithTokenReviewer impersonates the user and groups reviewer resolves the tokens to, instead of the user named by
+// the username claim of the tokens. A nil reviewer keeps reading the claims.
+func WithTokenReviewer(reviewer *TokenReviewer) Option {
+	return func(rt *roundTripper) {
+		rt.reviewer = reviewer
+	}
+}
+
+func New(log *logger.Logger, appCfg config.Config, adminRoundTripper, unauthorizedRT http.RoundTripper, opts ...Option) http.RoundTripper {
+	rt := &roundTripper{
 		log:            log,
 		adminRT:        adminRoundTripper,
 		unauthorizedRT: unauthorizedRT,
 		appCfg:         appCfg,
+		claims:         ClaimMappingsFromConfig(appCfg),
+	}
+	for _, opt := range opts {
+		opt(rt)
 	}
+	return rt
+}
+
+// GetUserFromContext returns the user whose token is stored in ctx, read from its usernameClaim like in impersonation mode.
+// The token is not verified here, this is left to the API server or the TokenVerifier of the RoundTripper, so the user
+// must not be used for authorization decisions.
+func GetUserFromContext(ctx context.Context, usernameClaim string) (string, bool) {
+	token, ok := ctx.Value(TokenKey{}).(string)
+	if !ok || token == "" {
+		return "", false
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return "", false
+	}
+
+	userName, ok := claims[usernameClaim].(string)
+	return userName, ok && userName != ""
 }
 
 // NewUnauthorizedRoundTripper returns a RoundTripper that always returns 401 Unauthorized
@@ -42,9 +91,15 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		Str("path", req.URL.Path).
 		Str("method", req.Method).
 		Bool("shouldImpersonate", rt.appCfg.Gateway.ShouldImpersonate).
-		Str("usernameClaim", rt.appCfg.Gateway.UsernameClaim).
+		Str("usernameClaim", rt.claims.Username).
 		Msg("RoundTripper processing request")
 
+	if namespace, ok := req.Context().Value(NamespaceKey{}).(string); ok && namespace != "" && !isDiscoveryRequest(req) &&
+		!isNamespaceRequest(req, namespace) {
+		rt.log.Debug().Str("path", req.URL.Path).Str("namespace", namespace).Msg("Request leaves the namespace of the endpoint, denying")
+		return forbiddenResponse(req, fmt.Sprintf("the endpoint is bound to the namespace %q", namespace)), nil
+	}
+
 	if rt.appCfg.LocalDevelopment {
 		rt.log.Debug().Str("path", req.URL.Path).Msg("Local development mode, using admin credentials")
 		return rt.adminRT.RoundTrip(req)
@@ -59,11 +114,23 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	token, ok := req.Context().Value(TokenKey{}).(string)
+	if (!ok || token == "") && rt.anonymous != nil {
+		return rt.impersonateAnonymous(req)
+	}
 	if !ok || token == "" {
 		rt.log.Error().Str("path", req.URL.Path).Msg("No token found for resource request, denying")
 		return rt.unauthorizedRT.RoundTrip(req)
 	}
 
+	var claims jwt.MapClaims
+	if rt.verifier != nil {
+		var err error
+		if claims, err = rt.verifier.Verify(req.Context(), token); err != nil {
+			rt.log.Error().Err(err).Str("path", req.URL.Path).Msg("Token verification failed, denying request")
+			return rt.unauthorizedRT.RoundTrip(req)
+		}
+	}
+
 	// No we are going to use token based auth only, so we are reassigning the headers
 	req.Header.Del("Authorization")
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -74,31 +141,56 @@ func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 		return rt.adminRT.RoundTrip(req)
 	}
 
+	if rt.reviewer != nil {
+		return rt.impersonateReviewedUser(req, token)
+	}
+
 	// Impersonation mode: extract user from token and impersonate
 	rt.log.Debug().Str("path", req.URL.Path).Msg("Using impersonation mode")
-	claims := jwt.MapClaims{}
-	_, _, err := jwt.NewParser().ParseUnverified(token, claims)
+	if claims == nil {
+		claims = jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+			rt.log.Error().Err(err).Str("path", req.URL.Path).Msg("Failed to parse token for impersonation, denying request")
+			return rt.unauthorizedRT.RoundTrip(req)
+		}
+	}
+
+	impersonation, err := rt.claims.impersonationConfig(claims)
 	if err != nil {
-		rt.log.Error().Err(err).Str("path", req.URL.Path).Msg("Failed to parse token for impersonation, denying request")
+		rt.log.Error().Err(err).Str("path", req.URL.Path).Msg("No valid user claim found in token for impersonation, denying request")
 		return rt.unauthorizedRT.RoundTrip(req)
 	}
 
-	userNameRaw, ok := claims[rt.appCfg.Gateway.UsernameClaim]
-	if !ok {
-		rt.log.Error().Str("path", req.URL.Path).Str("usernameClaim", rt.appCfg.Gateway.UsernameClaim).Msg("No user claim found in token for impersonation, denying request")
+	rt.log.Debug().Str("path", req.URL.Path).Str("impersonateUser", impersonation.UserName).Msg("Impersonating user")
+
+	impersonatingRT := transport.NewImpersonatingRoundTripper(impersonation, rt.adminRT)
+
+	return impersonatingRT.RoundTrip(req)
+}
+
+// impersonateReviewedUser impersonates the user the cluster authenticates token as, denying tokens it rejects
+func (rt *roundTripper) impersonateReviewedUser(req *http.Request, token string) (*http.Response, error) {
+	user, err := rt.reviewer.Review(req.Context(), token)
+	if err != nil {
+		rt.log.Error().Err(err).Str("path", req.URL.Path).Msg("Token review failed, denying request")
 		return rt.unauthorizedRT.RoundTrip(req)
 	}
-
-	userName, ok := userNameRaw.(string)
-	if !ok || userName == "" {
-		rt.log.Error().Str("path", req.URL.Path).Str("usernameClaim", rt.appCfg.Gateway.UsernameClaim).Msg("User claim is not a valid string for impersonation, denying request")
+	if user.Username == "" {
+		rt.log.Error().Str("path", req.URL.Path).Msg("Token review returned no username, denying request")
 		return rt.unauthorizedRT.RoundTrip(req)
 	}
 
-	rt.log.Debug().Str("path", req.URL.Path).Str("impersonateUser", userName).Msg("Impersonating user")
+	rt.log.Debug().Str("path", req.URL.Path).Str("impersonateUser", user.Username).Msg("Impersonating reviewed user")
 
+	extra := make(map[string][]string, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = []string(values)
+	}
 	impersonatingRT := transport.NewImpersonatingRoundTripper(transport.ImpersonationConfig{
-		UserName: userName,
+		UserName: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Extra:    extra,
 	}, rt.adminRT)
 
 	return impersonatingRT.RoundTrip(req)
@@ -118,22 +210,10 @@ func isDiscoveryRequest(req *http.Request) bool {
 		return false
 	}
 
-	// Parse and clean the URL path
-	path := req.URL.Path
-	path = strings.Trim(path, "/") // remove leading and trailing slashes
-	if path == "" {
+	parts := apiPathParts(req.URL.Path)
+	if len(parts) == 0 {
 		return false
 	}
-	parts := strings.Split(path, "/")
-
-	// Remove workspace prefixes to get the actual API path
-	if len(parts) >= 5 && parts[0] == "services" && parts[2] == "clusters" {
-		// Handle virtual workspace prefixes first: /services/<service>/clusters/<workspace>/api
-		parts = parts[4:] // Remove /services/<service>/clusters/<workspace> prefix
-	} else if len(parts) >= 3 && parts[0] == "clusters" {
-		// Handle KCP workspace prefixes: /clusters/<workspace>/api
-		parts = parts[2:] // Remove /clusters/<workspace> prefix
-	}
 
 	// Check if the remaining path matches Kubernetes discovery API patterns
 	switch {
@@ -149,3 +229,23 @@ func isDiscoveryRequest(req *http.Request) bool {
 		return false
 	}
 }
+
+// apiPathParts returns the segments of the path of a Kubernetes API request without the workspace prefixes of KCP
+func apiPathParts(path string) []string {
+	path = strings.Trim(path, "/") // remove leading and trailing slashes
+	if path == "" {
+		return nil
+	}
+	parts := strings.Split(path, "/")
+
+	// Remove workspace prefixes to get the actual API path
+	if len(parts) >= 5 && parts[0] == "services" && parts[2] == "clusters" {
+		// Handle virtual workspace prefixes first: /services/<service>/clusters/<workspace>/api
+		parts = parts[4:] // Remove /services/<service>/clusters/<workspace> prefix
+	} else if len(parts) >= 3 && parts[0] == "clusters" {
+		// Handle KCP workspace prefixes: /clusters/<workspace>/api
+		parts = parts[2:] // Remove /clusters/<workspace> prefix
+	}
+
+	return parts
+}
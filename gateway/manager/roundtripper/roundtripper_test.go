@@ -600,3 +600,37 @@ func TestRoundTripper_ExistingAuthHeadersAreCleanedBeforeImpersonation(t *testin
 	impersonateHeader := capturedRequest.Header.Get("Impersonate-User")
 	assert.Equal(t, "test-user", impersonateHeader)
 }
+
+func TestGetUserFromContext(t *testing.T) {
+	signed := func(claims jwt.MapClaims) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		return token
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantUser string
+		wantOk   bool
+	}{
+		{name: "user_claim", token: signed(jwt.MapClaims{"email": "user@example.com"}), wantUser: "user@example.com", wantOk: true},
+		{name: "missing_claim", token: signed(jwt.MapClaims{"sub": "user"})},
+		{name: "claim_is_not_a_string", token: signed(jwt.MapClaims{"email": 42})},
+		{name: "not_a_jwt", token: "opaque-token"},
+		{name: "no_token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = context.WithValue(ctx, roundtripper.TokenKey{}, tt.token)
+			}
+
+			user, ok := roundtripper.GetUserFromContext(ctx, "email")
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.wantUser, user)
+		})
+	}
+}
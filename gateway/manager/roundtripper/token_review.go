@@ -0,0 +1,106 @@
+package roundtripper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	authenticationv1client "k8s.io/client-go/kubernetes/typed/authentication/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// TokenReviewCacheTTL is how long the user a token was reviewed to is impersonated before the token is reviewed again
+	TokenReviewCacheTTL = time.Minute
+	// maxReviewedTokens bounds the reviewed tokens that are cached
+	maxReviewedTokens = 10000
+)
+
+var ErrTokenNotAuthenticated = errors.New("token is not authenticated by the cluster")
+
+// TokenReviewer resolves the user and groups of tokens with the TokenReview API of a cluster, for clusters whose
+// tokens don't carry the user in their claims, e.g. without OIDC. Authenticated reviews are cached per token.
+type TokenReviewer struct {
+	client authenticationv1client.TokenReviewInterface
+	ttl    time.Duration
+
+	mu      sync.Mutex
+	reviews map[string]tokenReviewEntry
+}
+
+type tokenReviewEntry struct {
+	user      authenticationv1.UserInfo
+	expiresAt time.Time
+}
+
+// NewTokenReviewer returns a reviewer sending the TokenReviews with cfg, which has to be allowed to create them
+func NewTokenReviewer(cfg *rest.Config) (*TokenReviewer, error) {
+	client, err := authenticationv1client.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokenReviewerForClient(client.TokenReviews()), nil
+}
+
+// NewTokenReviewerForClient returns a reviewer sending the TokenReviews with client
+func NewTokenReviewerForClient(client authenticationv1client.TokenReviewInterface) *TokenReviewer {
+	return &TokenReviewer{
+		client:  client,
+		ttl:     TokenReviewCacheTTL,
+		reviews: make(map[string]tokenReviewEntry),
+	}
+}
+
+// Review returns the user the cluster authenticates token as
+func (r *TokenReviewer) Review(ctx context.Context, token string) (authenticationv1.UserInfo, error) {
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+	now := time.Now()
+
+	r.mu.Lock()
+	entry, ok := r.reviews[key]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.user, nil
+	}
+
+	review, err := r.client.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return authenticationv1.UserInfo{}, fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		if review.Status.Error != "" {
+			return authenticationv1.UserInfo{}, fmt.Errorf("%w: %s", ErrTokenNotAuthenticated, review.Status.Error)
+		}
+		return authenticationv1.UserInfo{}, ErrTokenNotAuthenticated
+	}
+
+	r.store(key, review.Status.User, now)
+	return review.Status.User, nil
+}
+
+func (r *TokenReviewer) store(key string, user authenticationv1.UserInfo, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.reviews) >= maxReviewedTokens {
+		for k, entry := range r.reviews {
+			if now.After(entry.expiresAt) {
+				delete(r.reviews, k)
+			}
+		}
+		if len(r.reviews) >= maxReviewedTokens {
+			return
+		}
+	}
+	r.reviews[key] = tokenReviewEntry{user: user, expiresAt: now.Add(r.ttl)}
+}
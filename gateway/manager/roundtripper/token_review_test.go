@@ -0,0 +1,99 @@
+package roundtripper_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestRoundTripper_WithTokenReviewer(t *testing.T) {
+	tests := []struct {
+		name               string
+		token              string
+		expectedStatusCode int
+		expectedUser       string
+		expectedGroups     []string
+	}{
+		{
+			name:               "reviewed_user_is_impersonated",
+			token:              "service-account-token",
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "system:serviceaccount:default:reader",
+			expectedGroups:     []string{"system:serviceaccounts", "system:authenticated"},
+		},
+		{
+			name:               "rejected_token_is_denied",
+			token:              "unknown-token",
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviews int
+			clientset := fake.NewClientset()
+			clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				reviews++
+				review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+				if review.Spec.Token == "service-account-token" {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{
+						Username: "system:serviceaccount:default:reader",
+						Groups:   []string{"system:serviceaccounts", "system:authenticated"},
+					}
+				} else {
+					review.Status.Error = "invalid bearer token"
+				}
+				return true, review, nil
+			})
+			reviewer := roundtripper.NewTokenReviewerForClient(clientset.AuthenticationV1().TokenReviews())
+
+			mockAdmin := &mocks.MockRoundTripper{}
+			mockUnauthorized := &mocks.MockRoundTripper{}
+			if tt.expectedUser != "" {
+				mockAdmin.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil).Run(func(req *http.Request) {
+					assert.Equal(t, tt.expectedUser, req.Header.Get("Impersonate-User"))
+					assert.Equal(t, tt.expectedGroups, req.Header.Values("Impersonate-Group"))
+				})
+			} else {
+				mockUnauthorized.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusUnauthorized}, nil)
+			}
+
+			appCfg := appConfig.Config{}
+			appCfg.Gateway.ShouldImpersonate = true
+			appCfg.Gateway.UsernameClaim = "email"
+
+			rt := roundtripper.New(testlogger.New().Logger, appCfg, mockAdmin, mockUnauthorized, roundtripper.WithTokenReviewer(reviewer))
+
+			for range 2 {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", nil)
+				req = req.WithContext(context.WithValue(req.Context(), roundtripper.TokenKey{}, tt.token))
+
+				resp, err := rt.RoundTrip(req)
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+			}
+
+			// Authenticated reviews are cached, rejected tokens are reviewed again
+			if tt.expectedUser != "" {
+				assert.Equal(t, 1, reviews)
+			} else {
+				assert.Equal(t, 2, reviews)
+			}
+		})
+	}
+}
@@ -0,0 +1,360 @@
+package roundtripper
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// JWKSRefreshInterval is how long the keys of the issuer are used before they are fetched again
+	JWKSRefreshInterval = time.Hour
+	// jwksMinRefreshInterval bounds how often tokens signed by unknown keys trigger fetching the keys
+	jwksMinRefreshInterval = time.Minute
+	// jwksFetchTimeout bounds a fetch of the keys, which isn't canceled with the request that started it
+	jwksFetchTimeout = 30 * time.Second
+	// maxVerifiedTokens bounds the verified tokens that are cached until they expire
+	maxVerifiedTokens = 10000
+)
+
+var (
+	ErrTokenVerification = errors.New("token verification failed")
+	ErrUnknownSigningKey = errors.New("unknown signing key")
+)
+
+// TokenVerifierConfig configures the verification of the tokens of the callers against an OIDC issuer
+type TokenVerifierConfig struct {
+	// IssuerURL is the issuer the tokens must be issued by, its discovery document provides the JWKS endpoint
+	IssuerURL string
+	// JWKSURL overrides the JWKS endpoint of the discovery document
+	JWKSURL string
+	// Audience must be contained in the aud claim of the tokens
+	Audience string
+	// ClockSkew is the tolerance for the exp, nbf and iat claims
+	ClockSkew time.Duration
+}
+
+// TokenVerifier verifies the signature, issuer, audience and lifetime of tokens with the keys of the issuer. The keys
+// are cached and fetched again periodically, or when a token is signed by an unknown key after a key rotation.
+type TokenVerifier struct {
+	cfg        TokenVerifierConfig
+	httpClient *http.Client
+	parser     *jwt.Parser
+	// minRefreshInterval bounds how often the keys are fetched, so that tokens of unknown keys can't flood the issuer
+	minRefreshInterval time.Duration
+
+	// keysMu guards the keys, it is not held while they are fetched
+	keysMu      sync.Mutex
+	jwksURL     string
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+	// fetching is the running fetch of the keys, which concurrent verifications of unknown keys wait for
+	fetching *keyFetch
+
+	// mu guards verified
+	mu sync.Mutex
+	// verified stores the claims of verified tokens by token hash until the tokens expire
+	verified map[string]verifiedToken
+}
+
+// keyFetch is a fetch of the keys, done is closed once the keys are replaced or the fetch failed with err
+type keyFetch struct {
+	done chan struct{}
+	err  error
+}
+
+type verifiedToken struct {
+	claims    jwt.MapClaims
+	expiresAt time.Time
+}
+
+// NewTokenVerifier returns a verifier for cfg, the keys are fetched with httpClient on first use
+func NewTokenVerifier(cfg TokenVerifierConfig, httpClient *http.Client) *TokenVerifier {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(cfg.IssuerURL),
+		jwt.WithLeeway(cfg.ClockSkew),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+
+	return &TokenVerifier{
+		cfg:        cfg,
+		httpClient: httpClient,
+		parser:     jwt.NewParser(opts...),
+		jwksURL:    cfg.JWKSURL,
+		verified:   make(map[string]verifiedToken),
+
+		minRefreshInterval: jwksMinRefreshInterval,
+	}
+}
+
+// Verify returns the claims of token if it is valid
+func (v *TokenVerifier) Verify(ctx context.Context, token string) (jwt.MapClaims, error) {
+	hash := sha256.Sum256([]byte(token))
+	key := hex.EncodeToString(hash[:])
+	if claims, ok := v.cachedClaims(key, time.Now()); ok {
+		return claims, nil
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.signingKey(ctx, kid)
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTokenVerification, err)
+	}
+
+	expiresAt, err := claims.GetExpirationTime()
+	if err == nil && expiresAt != nil {
+		v.cacheClaims(key, claims, expiresAt.Add(v.cfg.ClockSkew))
+	}
+	return claims, nil
+}
+
+func (v *TokenVerifier) cachedClaims(key string, now time.Time) (jwt.MapClaims, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	entry, ok := v.verified[key]
+	if !ok || now.After(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.claims, true
+}
+
+func (v *TokenVerifier) cacheClaims(key string, claims jwt.MapClaims, expiresAt time.Time) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if len(v.verified) >= maxVerifiedTokens {
+		now := time.Now()
+		for k, entry := range v.verified {
+			if now.After(entry.expiresAt) {
+				delete(v.verified, k)
+			}
+		}
+		if len(v.verified) >= maxVerifiedTokens {
+			return
+		}
+	}
+	v.verified[key] = verifiedToken{claims: claims, expiresAt: expiresAt}
+}
+
+// signingKey returns the key with the given id, fetching the keys if they are outdated or the id is unknown.
+// Only one fetch runs at a time, verifications of unknown keys wait for it while known keys are used right away.
+func (v *TokenVerifier) signingKey(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.keysMu.Lock()
+	now := time.Now()
+	key, known := v.lookupKey(kid)
+	outdated := now.Sub(v.fetchedAt) > JWKSRefreshInterval
+	fetch := v.fetching
+	if fetch == nil && (!known || outdated) && now.Sub(v.lastAttempt) > v.minRefreshInterval {
+		v.lastAttempt = now
+		fetch = &keyFetch{done: make(chan struct{})}
+		v.fetching = fetch
+		go v.refreshKeys(fetch, v.jwksURL)
+	}
+	v.keysMu.Unlock()
+
+	// Outdated keys are still used while they are fetched again or the issuer is unreachable
+	if known {
+		return key, nil
+	}
+	if fetch == nil {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSigningKey, kid)
+	}
+
+	select {
+	case <-fetch.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+
+	v.keysMu.Lock()
+	defer v.keysMu.Unlock()
+	if key, known = v.lookupKey(kid); !known {
+		return nil, fmt.Errorf("%w %q", ErrUnknownSigningKey, kid)
+	}
+	return key, nil
+}
+
+// refreshKeys fetches the keys without holding the lock and replaces them on success. The fetch is not bound to
+// the request that started it, since other verifications may wait for it.
+func (v *TokenVerifier) refreshKeys(fetch *keyFetch, jwksURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+
+	keys, jwksURL, err := v.fetchKeys(ctx, jwksURL)
+
+	v.keysMu.Lock()
+	defer v.keysMu.Unlock()
+	if err == nil {
+		v.keys, v.jwksURL, v.fetchedAt = keys, jwksURL, time.Now()
+	}
+	v.fetching = nil
+	fetch.err = err
+	close(fetch.done)
+}
+
+// lookupKey returns the key with the given id, or the only key if the token doesn't name one
+func (v *TokenVerifier) lookupKey(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	key, ok := v.keys[kid]
+	return key, ok
+}
+
+// fetchKeys returns the keys of the JWKS endpoint and the endpoint, which is looked up in the discovery document of
+// the issuer unless it is known
+func (v *TokenVerifier) fetchKeys(ctx context.Context, jwksURL string) (map[string]crypto.PublicKey, string, error) {
+	if jwksURL == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, strings.TrimSuffix(v.cfg.IssuerURL, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, "", fmt.Errorf("failed to discover the JWKS endpoint: %w", err)
+		}
+		if discovery.Issuer != v.cfg.IssuerURL {
+			return nil, "", fmt.Errorf("discovery document is for issuer %q instead of %q", discovery.Issuer, v.cfg.IssuerURL)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, "", errors.New("discovery document has no jwks_uri")
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := v.getJSON(ctx, jwksURL, &jwks); err != nil {
+		return nil, "", fmt.Errorf("failed to fetch the JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Keys of unsupported types are skipped, the tokens signed by them fail with an unknown key
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, jwksURL, nil
+}
+
+func (v *TokenVerifier) getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jsonWebKey is a public key of a JWKS, see RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// N and E are the modulus and exponent of RSA keys
+	N string `json:"n"`
+	E string `json:"e"`
+	// Crv, X and Y are the curve and coordinates of EC keys
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,336 @@
+package roundtripper_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+// testIssuer serves the discovery document and the JWKS of an OIDC issuer whose keys can be rotated
+type testIssuer struct {
+	server      *httptest.Server
+	jwksFetches atomic.Int32
+	// jwksGate blocks the JWKS responses until it is closed, if set
+	jwksGate atomic.Pointer[chan struct{}]
+
+	mu  sync.Mutex
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	issuer := &testIssuer{}
+	issuer.rotate(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   issuer.server.URL,
+			"jwks_uri": issuer.server.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		issuer.jwksFetches.Add(1)
+		if gate := issuer.jwksGate.Load(); gate != nil {
+			<-*gate
+		}
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": issuer.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+			}},
+		})
+	})
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+
+	return issuer
+}
+
+func (i *testIssuer) rotate(t *testing.T, kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.kid, i.key = kid, key
+}
+
+func (i *testIssuer) sign(t *testing.T, claims jwt.MapClaims) string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.kid
+	signed, err := token.SignedString(i.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (i *testIssuer) claims(modify func(jwt.MapClaims)) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":   i.server.URL,
+		"aud":   "gateway",
+		"sub":   "user",
+		"email": "user@example.com",
+		"iat":   time.Now().Unix(),
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+	if modify != nil {
+		modify(claims)
+	}
+	return claims
+}
+
+func TestTokenVerifier_Verify(t *testing.T) {
+	issuer := newTestIssuer(t)
+
+	tests := []struct {
+		name        string
+		token       func(t *testing.T) string
+		expectedErr bool
+	}{
+		{
+			name: "valid",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(nil))
+			},
+		},
+		{
+			name: "expired_within_clock_skew",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(func(c jwt.MapClaims) {
+					c["exp"] = time.Now().Add(-30 * time.Second).Unix()
+				}))
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(func(c jwt.MapClaims) {
+					c["exp"] = time.Now().Add(-time.Hour).Unix()
+				}))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "without_expiry",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(func(c jwt.MapClaims) {
+					delete(c, "exp")
+				}))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "other_issuer",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(func(c jwt.MapClaims) {
+					c["iss"] = "https://other.example.com"
+				}))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "other_audience",
+			token: func(t *testing.T) string {
+				return issuer.sign(t, issuer.claims(func(c jwt.MapClaims) {
+					c["aud"] = "other"
+				}))
+			},
+			expectedErr: true,
+		},
+		{
+			name: "symmetric_signature",
+			token: func(t *testing.T) string {
+				signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, issuer.claims(nil)).SignedString([]byte("secret"))
+				require.NoError(t, err)
+				return signed
+			},
+			expectedErr: true,
+		},
+		{
+			name: "unverified",
+			token: func(t *testing.T) string {
+				return createTestToken(t, issuer.claims(nil))
+			},
+			expectedErr: true,
+		},
+	}
+
+	verifier := roundtripper.NewTokenVerifier(roundtripper.TokenVerifierConfig{
+		IssuerURL: issuer.server.URL,
+		Audience:  "gateway",
+		ClockSkew: time.Minute,
+	}, issuer.server.Client())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(t.Context(), tt.token(t))
+			if tt.expectedErr {
+				assert.ErrorIs(t, err, roundtripper.ErrTokenVerification)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user@example.com", claims["email"])
+		})
+	}
+}
+
+func TestTokenVerifier_KeyRotation(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier := roundtripper.NewTokenVerifier(roundtripper.TokenVerifierConfig{
+		IssuerURL: issuer.server.URL,
+		JWKSURL:   issuer.server.URL + "/keys",
+	}, issuer.server.Client())
+
+	token := issuer.sign(t, issuer.claims(nil))
+	_, err := verifier.Verify(t.Context(), token)
+	require.NoError(t, err)
+
+	// Verified tokens are cached until they expire
+	_, err = verifier.Verify(t.Context(), token)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), issuer.jwksFetches.Load())
+
+	issuer.rotate(t, "key-2")
+	rotated := issuer.sign(t, issuer.claims(nil))
+
+	// Unknown keys are fetched at most once per refresh interval
+	_, err = verifier.Verify(t.Context(), rotated)
+	assert.ErrorIs(t, err, roundtripper.ErrUnknownSigningKey)
+	assert.Equal(t, int32(1), issuer.jwksFetches.Load())
+
+	verifier.SetMinRefreshInterval(0)
+	_, err = verifier.Verify(t.Context(), rotated)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), issuer.jwksFetches.Load())
+}
+
+func TestTokenVerifier_SlowKeyFetch(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier := roundtripper.NewTokenVerifier(roundtripper.TokenVerifierConfig{
+		IssuerURL: issuer.server.URL,
+		JWKSURL:   issuer.server.URL + "/keys",
+	}, issuer.server.Client())
+
+	cached := issuer.sign(t, issuer.claims(nil))
+	_, err := verifier.Verify(t.Context(), cached)
+	require.NoError(t, err)
+	uncached := issuer.sign(t, issuer.claims(func(claims jwt.MapClaims) { claims["sub"] = "other" }))
+
+	gate := make(chan struct{})
+	issuer.jwksGate.Store(&gate)
+	issuer.rotate(t, "key-2")
+	rotated := issuer.sign(t, issuer.claims(nil))
+	verifier.SetMinRefreshInterval(0)
+
+	// Tokens of the unknown key wait for a single fetch of the keys
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := verifier.Verify(t.Context(), rotated)
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return issuer.jwksFetches.Load() == 2 }, time.Second, time.Millisecond)
+
+	// Cached tokens and tokens of known keys are verified while the keys are fetched
+	_, err = verifier.Verify(t.Context(), cached)
+	require.NoError(t, err)
+	_, err = verifier.Verify(t.Context(), uncached)
+	require.NoError(t, err)
+
+	close(gate)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), issuer.jwksFetches.Load())
+}
+
+func TestRoundTripper_WithTokenVerifier(t *testing.T) {
+	issuer := newTestIssuer(t)
+	verifier := roundtripper.NewTokenVerifier(roundtripper.TokenVerifierConfig{
+		IssuerURL: issuer.server.URL,
+		Audience:  "gateway",
+	}, issuer.server.Client())
+
+	tests := []struct {
+		name               string
+		token              string
+		expectedStatusCode int
+		expectedUser       string
+	}{
+		{
+			name:               "verified_token_is_impersonated",
+			token:              issuer.sign(t, issuer.claims(nil)),
+			expectedStatusCode: http.StatusOK,
+			expectedUser:       "user@example.com",
+		},
+		{
+			name:               "unverified_token_is_denied",
+			token:              createTestToken(t, issuer.claims(nil)),
+			expectedStatusCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockAdmin := &mocks.MockRoundTripper{}
+			mockUnauthorized := &mocks.MockRoundTripper{}
+			if tt.expectedUser != "" {
+				mockAdmin.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusOK}, nil).Run(func(req *http.Request) {
+					assert.Equal(t, tt.expectedUser, req.Header.Get("Impersonate-User"))
+				})
+			} else {
+				mockUnauthorized.EXPECT().RoundTrip(mock.Anything).Return(&http.Response{StatusCode: http.StatusUnauthorized}, nil).Run(func(req *http.Request) {
+					assert.Empty(t, req.Header.Get("Authorization"))
+				})
+			}
+
+			appCfg := appConfig.Config{}
+			appCfg.Gateway.ShouldImpersonate = true
+			appCfg.Gateway.UsernameClaim = "email"
+
+			rt := roundtripper.New(testlogger.New().Logger, appCfg, mockAdmin, mockUnauthorized, roundtripper.WithTokenVerifier(verifier))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/namespaces/default/pods", nil)
+			req = req.WithContext(context.WithValue(req.Context(), roundtripper.TokenKey{}, tt.token))
+
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedStatusCode, resp.StatusCode)
+			mockAdmin.AssertExpectations(t)
+			mockUnauthorized.AssertExpectations(t)
+		})
+	}
+}
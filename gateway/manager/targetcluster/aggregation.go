@@ -0,0 +1,464 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/visitor"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+const (
+	// ClustersField is the root field of the aggregated endpoint, its selection is run against every selected cluster
+	ClustersField = "clusters"
+	// ClustersNameArg selects the clusters of the clusters field, all loaded clusters if it is omitted
+	ClustersNameArg = "name"
+	// ClusterDiscriminatorField is the field of the results of the clusters field holding the name of their cluster
+	ClusterDiscriminatorField = "cluster"
+	// ClusterResultTypeName is the __typename of the results of the clusters field
+	ClusterResultTypeName = "ClusterResult"
+)
+
+// aggregationRequest is the body of a request to the aggregated endpoint, and of the requests sent to the clusters
+type aggregationRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// aggregationPlan describes how an aggregated query is sent to the clusters and how their results are merged
+type aggregationPlan struct {
+	// responseKey is the key of the clusters field in the result, its alias if it has one
+	responseKey string
+	// clusterNames are the clusters selected by the name argument, nil selects all loaded clusters
+	clusterNames []string
+	// discriminatorKeys and typenameKeys are the keys of the cluster and __typename fields, which the gateway resolves
+	discriminatorKeys []string
+	typenameKeys      []string
+	// forwardedKeys are the keys of the fields resolved by the clusters
+	forwardedKeys []string
+	// body is the request sent to every cluster, nil if no field is resolved by the clusters
+	body []byte
+}
+
+// matchAggregationURL matches /graphql, the endpoint of the aggregated queries
+func matchAggregationURL(path string, appCfg appConfig.Config) bool {
+	return strings.Trim(path, "/") == appCfg.Url.GraphqlSuffix
+}
+
+// serveAggregation runs the selection of the clusters field against the selected clusters concurrently and returns
+// one result per cluster, in the order of the name argument or sorted by name, e.g.
+//
+//	{ clusters(name: ["cluster-a", "cluster-b"]) { cluster v1 { Pods { items { metadata { name } } } } } }
+//
+// returns
+//
+//	{"data": {"clusters": [{"cluster": "cluster-a", "v1": {...}}, {"cluster": "cluster-b", "v1": null}]}, "errors": [{"message": "...", "path": ["clusters", 1, "v1"], "extensions": {"cluster": "cluster-b"}}]}
+//
+// Every cluster validates the selection against its own schema, and authenticates and limits the operation like a
+// request sent to the cluster directly. Mutations and subscriptions are rejected like for cluster groups.
+func (cr *ClusterRegistry) serveAggregation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Accept") == "text/event-stream" {
+		writeGroupError(w, http.StatusBadRequest, "subscriptions are not supported by aggregated queries")
+		return
+	}
+
+	// The clusters authenticate the forwarded operation, the list of clusters requires a valid token as well, unless
+	// requests without a token are served as the anonymous service account
+	if token := GetToken(r); !cr.appCfg.LocalDevelopment && (token != "" || !cr.allowsAnonymous()) {
+		if token == "" {
+			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
+			return
+		}
+		if err := cr.verifyToken(r.Context(), token); err != nil {
+			cr.log.Debug().Err(err).Msg("Rejecting invalid token of aggregated query")
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		writeGroupError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	var req aggregationRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		writeGroupError(w, http.StatusBadRequest, "failed to parse request body")
+		return
+	}
+
+	plan, err := planAggregation(req)
+	if err != nil {
+		writeGroupError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	clusterNames := plan.clusterNames
+	if clusterNames == nil {
+		clusterNames = cr.loadedClusterNames()
+	}
+	missing := make(map[string]bool)
+	for _, name := range clusterNames {
+		if _, exists := cr.GetCluster(name); !exists {
+			missing[name] = true
+		}
+	}
+
+	var responses []*bufferedResponse
+	if plan.body != nil {
+		cr.log.Debug().
+			Strs("clusters", clusterNames).
+			Msg("Sending aggregated query to the clusters")
+
+		responses = cr.fanOut(r, plan.body, clusterNames)
+	}
+
+	result, err := aggregateClusterResponses(plan, clusterNames, missing, responses)
+	if err != nil {
+		cr.log.Error().Err(err).Msg("Failed to merge aggregated query results")
+		writeGroupError(w, http.StatusInternalServerError, "failed to merge the results of the clusters")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		cr.log.Error().Err(err).Msg("Failed to write aggregated query response")
+	}
+}
+
+// loadedClusterNames returns the names of the loaded clusters, sorted by name
+func (cr *ClusterRegistry) loadedClusterNames() []string {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	names := make([]string, 0, len(cr.clusters))
+	for name := range cr.clusters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// planAggregation validates an aggregated query and builds the query sent to the clusters from the selection of the
+// clusters field, with the fragments and variables it uses
+func planAggregation(req aggregationRequest) (*aggregationPlan, error) {
+	if req.Query == "" {
+		return nil, errors.New("aggregated queries require the query document, persisted queries are not supported")
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: req.Query})
+	if err != nil {
+		return nil, err
+	}
+
+	var operation *ast.OperationDefinition
+	operations := 0
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, definition := range doc.Definitions {
+		switch definition := definition.(type) {
+		case *ast.OperationDefinition:
+			operations++
+			if req.OperationName == "" || (definition.Name != nil && definition.Name.Value == req.OperationName) {
+				operation = definition
+			}
+		case *ast.FragmentDefinition:
+			fragments[definition.Name.Value] = definition
+		}
+	}
+	switch {
+	case operation == nil:
+		return nil, fmt.Errorf("operation %q not found", req.OperationName)
+	case req.OperationName == "" && operations > 1:
+		return nil, errors.New("operationName is required for documents with several operations")
+	case operation.Operation != ast.OperationTypeQuery:
+		return nil, fmt.Errorf("%s operations are not supported by aggregated queries", operation.Operation)
+	}
+
+	var clustersField *ast.Field
+	if selections := operation.SelectionSet.Selections; len(selections) == 1 {
+		clustersField, _ = selections[0].(*ast.Field)
+	}
+	if clustersField == nil || clustersField.Name.Value != ClustersField {
+		return nil, fmt.Errorf("aggregated queries must select the %s field only", ClustersField)
+	}
+	if clustersField.SelectionSet == nil {
+		return nil, fmt.Errorf("the %s field requires a selection", ClustersField)
+	}
+
+	plan := &aggregationPlan{responseKey: responseKey(clustersField)}
+	for _, arg := range clustersField.Arguments {
+		if arg.Name.Value != ClustersNameArg {
+			return nil, fmt.Errorf("unknown argument %q of the %s field", arg.Name.Value, ClustersField)
+		}
+		if plan.clusterNames, err = clusterNamesArg(arg.Value, req.Variables); err != nil {
+			return nil, err
+		}
+	}
+
+	var forwarded []ast.Selection
+	for _, selection := range clustersField.SelectionSet.Selections {
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			return nil, fmt.Errorf("fragments are not supported in the selection of the %s field", ClustersField)
+		}
+
+		switch field.Name.Value {
+		case ClusterDiscriminatorField:
+			plan.discriminatorKeys = append(plan.discriminatorKeys, responseKey(field))
+		case "__typename":
+			plan.typenameKeys = append(plan.typenameKeys, responseKey(field))
+		default:
+			plan.forwardedKeys = append(plan.forwardedKeys, responseKey(field))
+			forwarded = append(forwarded, field)
+		}
+	}
+	if len(forwarded) == 0 {
+		return plan, nil
+	}
+
+	usedFragments, usedVariables := collectReferences(forwarded, fragments)
+
+	forwardedOperation := ast.NewOperationDefinition(&ast.OperationDefinition{
+		Operation:    ast.OperationTypeQuery,
+		Name:         operation.Name,
+		Directives:   operation.Directives,
+		SelectionSet: ast.NewSelectionSet(&ast.SelectionSet{Selections: forwarded}),
+	})
+	variables := make(map[string]interface{})
+	for _, definition := range operation.VariableDefinitions {
+		name := definition.Variable.Name.Value
+		if !usedVariables[name] {
+			continue
+		}
+		forwardedOperation.VariableDefinitions = append(forwardedOperation.VariableDefinitions, definition)
+		if value, ok := req.Variables[name]; ok {
+			variables[name] = value
+		}
+	}
+
+	// The fragments keep their order in the document
+	definitions := []ast.Node{forwardedOperation}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok && usedFragments[fragment.Name.Value] {
+			definitions = append(definitions, fragment)
+		}
+	}
+
+	query, _ := printer.Print(ast.NewDocument(&ast.Document{Definitions: definitions})).(string)
+	plan.body, err = json.Marshal(aggregationRequest{
+		Query:         query,
+		OperationName: req.OperationName,
+		Variables:     variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// clusterNamesArg returns the names of the name argument, a list of strings or a single string. A variable that is
+// not set selects all clusters.
+func clusterNamesArg(value ast.Value, variables map[string]interface{}) ([]string, error) {
+	invalid := fmt.Errorf("the %s argument of the %s field must be a list of strings", ClustersNameArg, ClustersField)
+
+	switch value := value.(type) {
+	case *ast.StringValue:
+		return []string{value.Value}, nil
+	case *ast.ListValue:
+		names := make([]string, 0, len(value.Values))
+		for _, item := range value.Values {
+			switch item := item.(type) {
+			case *ast.StringValue:
+				names = append(names, item.Value)
+			case *ast.Variable:
+				name, ok := variables[item.Name.Value].(string)
+				if !ok {
+					return nil, invalid
+				}
+				names = append(names, name)
+			default:
+				return nil, invalid
+			}
+		}
+		return uniqueNames(names), nil
+	case *ast.Variable:
+		switch variable := variables[value.Name.Value].(type) {
+		case nil:
+			return nil, nil
+		case string:
+			return []string{variable}, nil
+		case []interface{}:
+			names := make([]string, 0, len(variable))
+			for _, item := range variable {
+				name, ok := item.(string)
+				if !ok {
+					return nil, invalid
+				}
+				names = append(names, name)
+			}
+			return uniqueNames(names), nil
+		}
+	}
+
+	return nil, invalid
+}
+
+// uniqueNames removes repeated names, keeping the first occurrence
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := names[:0]
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+
+	return unique
+}
+
+// collectReferences returns the names of the fragments the selections spread, directly or through other fragments,
+// and of the variables they and the fragments use
+func collectReferences(selections []ast.Selection, fragments map[string]*ast.FragmentDefinition) (map[string]bool, map[string]bool) {
+	usedFragments := make(map[string]bool)
+	usedVariables := make(map[string]bool)
+
+	pending := make([]ast.Node, 0, len(selections))
+	for _, selection := range selections {
+		if node, ok := selection.(ast.Node); ok {
+			pending = append(pending, node)
+		}
+	}
+
+	for len(pending) > 0 {
+		node := pending[0]
+		pending = pending[1:]
+
+		visitor.Visit(node, &visitor.VisitorOptions{
+			KindFuncMap: map[string]visitor.NamedVisitFuncs{
+				kinds.Variable: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						if variable, ok := p.Node.(*ast.Variable); ok {
+							usedVariables[variable.Name.Value] = true
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+				kinds.FragmentSpread: {
+					Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+						spread, ok := p.Node.(*ast.FragmentSpread)
+						if !ok || usedFragments[spread.Name.Value] {
+							return visitor.ActionNoChange, nil
+						}
+						if fragment, exists := fragments[spread.Name.Value]; exists {
+							usedFragments[spread.Name.Value] = true
+							pending = append(pending, fragment)
+						}
+						return visitor.ActionNoChange, nil
+					},
+				},
+			},
+		}, nil)
+	}
+
+	return usedFragments, usedVariables
+}
+
+// responseKey returns the key of a field in the result, its alias if it has one
+func responseKey(field *ast.Field) string {
+	if field.Alias != nil && field.Alias.Value != "" {
+		return field.Alias.Value
+	}
+	return field.Name.Value
+}
+
+// aggregateClusterResponses merges the responses of the clusters into one result per cluster. Clusters that are
+// missing and responses that are not GraphQL results are reported as errors, their fields are null.
+func aggregateClusterResponses(plan *aggregationPlan, clusterNames []string, missing map[string]bool, responses []*bufferedResponse) (groupResult, error) {
+	result := groupResult{Data: make(map[string]json.RawMessage, 1)}
+	items := make([]map[string]json.RawMessage, 0, len(clusterNames))
+
+	for i, name := range clusterNames {
+		item := make(map[string]json.RawMessage, len(plan.discriminatorKeys)+len(plan.typenameKeys)+len(plan.forwardedKeys))
+		encodedName, err := json.Marshal(name)
+		if err != nil {
+			return groupResult{}, err
+		}
+		for _, key := range plan.discriminatorKeys {
+			item[key] = encodedName
+		}
+		for _, key := range plan.typenameKeys {
+			item[key] = json.RawMessage(`"` + ClusterResultTypeName + `"`)
+		}
+		for _, key := range plan.forwardedKeys {
+			item[key] = json.RawMessage("null")
+		}
+		items = append(items, item)
+
+		var response *bufferedResponse
+		if i < len(responses) {
+			response = responses[i]
+		}
+
+		switch {
+		case missing[name] || (plan.body != nil && response == nil):
+			result.Errors = append(result.Errors, map[string]interface{}{
+				"message": fmt.Sprintf("cluster %q not found", name),
+				"path":    []interface{}{plan.responseKey, i},
+				"extensions": map[string]interface{}{
+					"cluster": name,
+					"status":  http.StatusNotFound,
+				},
+			})
+			continue
+		case response == nil:
+			continue
+		}
+
+		var clusterResult struct {
+			Data   map[string]json.RawMessage `json:"data"`
+			Errors []map[string]interface{}   `json:"errors"`
+		}
+		if err := json.Unmarshal(response.body.Bytes(), &clusterResult); err != nil {
+			clusterErr := clusterResponseError(name, response)
+			clusterErr["path"] = []interface{}{plan.responseKey, i}
+			result.Errors = append(result.Errors, clusterErr)
+			continue
+		}
+
+		for _, key := range plan.forwardedKeys {
+			if data, ok := clusterResult.Data[key]; ok {
+				item[key] = data
+			}
+		}
+		result.Errors = append(result.Errors, clusterErrors(name, response.statusCode, clusterResult.Errors, plan.responseKey, i)...)
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return groupResult{}, err
+	}
+	result.Data[plan.responseKey] = data
+
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestPlanAggregation(t *testing.T) {
+	tests := []struct {
+		name                 string
+		req                  aggregationRequest
+		expectedClusterNames []string
+		expectedKeys         []string
+		expectedForwarded    *aggregationRequest
+		expectedErr          string
+	}{
+		{
+			name:                 "names_and_discriminator",
+			req:                  aggregationRequest{Query: `{ clusters(name: ["b", "a", "b"]) { cluster v1 { Pods { items { metadata { name } } } } } }`},
+			expectedClusterNames: []string{"b", "a"},
+			expectedKeys:         []string{"v1"},
+			expectedForwarded: &aggregationRequest{
+				Query: "{\n  v1 {\n    Pods {\n      items {\n        metadata {\n          name\n        }\n      }\n    }\n  }\n}\n",
+			},
+		},
+		{
+			name: "variables_and_fragments",
+			req: aggregationRequest{
+				Query: `query Fleet($names: [String], $ns: String) {
+  fleet: clusters(name: $names) { name: cluster core: v1 { Pods(namespace: $ns) { ...pods } } }
+}
+fragment unused on Pod { kind }
+fragment pods on PodList { items { ...meta } }
+fragment meta on Pod { metadata { name } }`,
+				OperationName: "Fleet",
+				Variables:     map[string]interface{}{"names": []interface{}{"a"}, "ns": "default"},
+			},
+			expectedClusterNames: []string{"a"},
+			expectedKeys:         []string{"core"},
+			expectedForwarded: &aggregationRequest{
+				Query:         "query Fleet($ns: String) {\n  core: v1 {\n    Pods(namespace: $ns) {\n      ...pods\n    }\n  }\n}\n\nfragment pods on PodList {\n  items {\n    ...meta\n  }\n}\n\nfragment meta on Pod {\n  metadata {\n    name\n  }\n}\n",
+				OperationName: "Fleet",
+				Variables:     map[string]interface{}{"ns": "default"},
+			},
+		},
+		{
+			name: "unset_variable_selects_all_clusters",
+			req:  aggregationRequest{Query: `query($names: [String]) { clusters(name: $names) { cluster } }`},
+		},
+		{
+			name:        "other_root_fields",
+			req:         aggregationRequest{Query: `{ clusters { cluster } v1 { Pods { items { kind } } } }`},
+			expectedErr: "aggregated queries must select the clusters field only",
+		},
+		{
+			name:        "mutation",
+			req:         aggregationRequest{Query: `mutation { clusters { cluster } }`},
+			expectedErr: "mutation operations are not supported by aggregated queries",
+		},
+		{
+			name:        "invalid_names",
+			req:         aggregationRequest{Query: `{ clusters(name: [1]) { cluster } }`},
+			expectedErr: "the name argument of the clusters field must be a list of strings",
+		},
+		{
+			name:        "fragment_on_results",
+			req:         aggregationRequest{Query: `{ clusters { ... on ClusterResult { cluster } } }`},
+			expectedErr: "fragments are not supported in the selection of the clusters field",
+		},
+		{
+			name:        "persisted_query",
+			req:         aggregationRequest{},
+			expectedErr: "aggregated queries require the query document",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := planAggregation(tt.req)
+			if tt.expectedErr != "" {
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedClusterNames, plan.clusterNames)
+			assert.Equal(t, tt.expectedKeys, plan.forwardedKeys)
+			if tt.expectedForwarded == nil {
+				assert.Nil(t, plan.body)
+				return
+			}
+
+			var forwarded aggregationRequest
+			require.NoError(t, json.Unmarshal(plan.body, &forwarded))
+			assert.Equal(t, *tt.expectedForwarded, forwarded)
+		})
+	}
+}
+
+func TestClusterRegistry_ServeAggregation(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+	appCfg.Gateway.AggregatedQueries = true
+
+	newCluster := func(name string, response string) *TargetCluster {
+		return &TargetCluster{
+			appCfg: appCfg,
+			name:   name,
+			handler: &GraphQLHandler{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/"+name+"/graphql", r.URL.Path)
+				body, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				assert.NotContains(t, string(body), "clusters")
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(response))
+			})},
+		}
+	}
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	registry.clusters["eu"] = newCluster("eu", `{"data": {"v1": {"ConfigMaps": {"items": [{"metadata": {"name": "a"}}]}}}}`)
+	registry.clusters["us"] = newCluster("us", `{"data": {"v1": null}, "errors": [{"message": "forbidden", "path": ["v1", "ConfigMaps"]}]}`)
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(method, "/graphql", strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("merges_results", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"query": "{ clusters(name: [\"us\", \"eu\", \"ap\"]) { cluster __typename v1 { ConfigMaps { items { metadata { name } } } } } }"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result struct {
+			Data struct {
+				Clusters json.RawMessage `json:"clusters"`
+			} `json:"data"`
+			Errors []struct {
+				Message    string                 `json:"message"`
+				Path       []interface{}          `json:"path"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+
+		assert.JSONEq(t, `[
+			{"cluster": "us", "__typename": "ClusterResult", "v1": null},
+			{"cluster": "eu", "__typename": "ClusterResult", "v1": {"ConfigMaps": {"items": [{"metadata": {"name": "a"}}]}}},
+			{"cluster": "ap", "__typename": "ClusterResult", "v1": null}
+		]`, string(result.Data.Clusters))
+
+		require.Len(t, result.Errors, 2)
+		assert.Equal(t, "forbidden", result.Errors[0].Message)
+		assert.Equal(t, []interface{}{"clusters", float64(0), "v1", "ConfigMaps"}, result.Errors[0].Path)
+		assert.Equal(t, "us", result.Errors[0].Extensions["cluster"])
+		assert.Equal(t, `cluster "ap" not found`, result.Errors[1].Message)
+		assert.Equal(t, []interface{}{"clusters", float64(2)}, result.Errors[1].Path)
+	})
+
+	t.Run("lists_all_clusters", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"query": "{ clusters { name: cluster } }"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {"clusters": [{"name": "eu"}, {"name": "us"}]}}`, rec.Body.String())
+	})
+
+	t.Run("rejects_invalid_queries", func(t *testing.T) {
+		rec := serve(http.MethodPost, `{"query": "{ v1 { ConfigMaps { items { metadata { name } } } } }"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "aggregated queries must select the clusters field only")
+	})
+
+	t.Run("rejects_get", func(t *testing.T) {
+		rec := serve(http.MethodGet, "")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,204 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gobuffalo/flect"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	gatewaySchema "github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+// CapabilitiesSuffix is the last path segment of the capabilities endpoint, e.g. /{clusterName}/capabilities
+const CapabilitiesSuffix = "capabilities"
+
+// operationVerbs are the Kubernetes verbs the resolver needs for every generated operation
+var operationVerbs = map[string][]string{
+	"list":           {"list"},
+	"get":            {"get"},
+	"create":         {"create"},
+	"update":         {"get", "patch"},    // updates are applied as merge patches to the existing object
+	"apply":          {"create", "patch"}, // Server-Side Apply creates missing objects
+	"delete":         {"delete"},
+	"deleteMatching": {"list", "deletecollection"},
+	"subscribe":      {"watch"},
+}
+
+// subresourceOperations are the operations generated for the subresources of a kind and the verb they need on the
+// subresource
+var subresourceOperations = map[string]struct{ operation, verb string }{
+	common.StatusSubresource:   {operation: "updateStatus", verb: "patch"},
+	common.ScaleSubresource:    {operation: "scale", verb: "patch"},
+	common.EvictionSubresource: {operation: "evict", verb: "create"},
+	common.TokenSubresource:    {operation: "createToken", verb: "create"},
+	common.ProxySubresource:    {operation: "proxy", verb: "get"},
+}
+
+// Capabilities describes the operations a caller can run against the resources of a cluster
+type Capabilities struct {
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	// RBACIncomplete is set if the API server could not evaluate every rule of the caller, e.g. because a webhook
+	// authorizer is used. Operations reported as not allowed may be allowed in that case.
+	RBACIncomplete bool                   `json:"rbacIncomplete"`
+	Resources      []ResourceCapabilities `json:"resources"`
+}
+
+// ResourceCapabilities describes the operations of a single kind
+type ResourceCapabilities struct {
+	Group    string `json:"group"`
+	Version  string `json:"version"`
+	Kind     string `json:"kind"`
+	Resource string `json:"resource"`
+	Scope    string `json:"scope"`
+	// GraphQL names the fields generated for the kind
+	GraphQL GraphQLNames `json:"graphql"`
+	// Operations maps every generated operation to whether the caller is allowed to run it
+	Operations   map[string]bool `json:"operations"`
+	Subresources []string        `json:"subresources"`
+	DryRun       bool            `json:"dryRun"`
+	Pagination   bool            `json:"pagination"`
+}
+
+// GraphQLNames are the names of the fields generated for a kind
+type GraphQLNames struct {
+	Group    string `json:"group"`
+	Singular string `json:"singular"`
+	Plural   string `json:"plural"`
+}
+
+// matchCapabilitiesURL matches /{clusterName}/capabilities and its virtual workspace variant
+func matchCapabilitiesURL(path string, appCfg appConfig.Config) (string, string, bool) {
+	capabilitiesCfg := appCfg
+	capabilitiesCfg.Url.GraphqlSuffix = CapabilitiesSuffix
+	return MatchURL(path, capabilitiesCfg)
+}
+
+// serveCapabilities describes the resources of the cluster and which operations the caller may run on them.
+// The permissions are evaluated with a SelfSubjectRulesReview in the namespace passed as query parameter,
+// falling back to the default namespace of the cluster.
+func (cr *ClusterRegistry) serveCapabilities(w http.ResponseWriter, r *http.Request, clusterName string) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cluster, exists := cr.GetCluster(clusterName)
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	token := GetToken(r)
+	if !cr.handleAuth(w, r, token, cluster) {
+		return
+	}
+	r = SetContexts(r, clusterName, token, cr.appCfg.EnableKcp)
+
+	namespace := r.URL.Query().Get("namespace")
+	if namespace == "" {
+		namespace = cluster.defaultNamespace
+	}
+
+	review := &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}
+	if err := cluster.client.Create(r.Context(), review); err != nil {
+		cr.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to review the rules of the caller")
+		http.Error(w, "Failed to review the permissions of the caller", http.StatusBadGateway)
+		return
+	}
+
+	capabilities := Capabilities{
+		Cluster:        clusterName,
+		Namespace:      namespace,
+		RBACIncomplete: review.Status.Incomplete,
+		Resources:      make([]ResourceCapabilities, 0, len(cluster.resources)),
+	}
+	for _, resource := range cluster.resources {
+		capabilities.Resources = append(capabilities.Resources, cluster.resourceCapabilities(resource, review.Status.ResourceRules))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(capabilities); err != nil {
+		cr.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to write capabilities")
+	}
+}
+
+// resourceCapabilities evaluates the rules of the caller for every operation generated for a kind
+func (tc *TargetCluster) resourceCapabilities(resource gatewaySchema.Resource, rules []authorizationv1.ResourceRule) ResourceCapabilities {
+	restResource := tc.restResource(resource)
+
+	operations := make(map[string]bool, len(operationVerbs))
+	for operation, verbs := range operationVerbs {
+		operations[operation] = rulesAllow(rules, resource.Group, restResource, verbs)
+	}
+	for _, subresource := range resource.Subresources {
+		if op, ok := subresourceOperations[subresource]; ok {
+			operations[op.operation] = rulesAllow(rules, resource.Group, restResource+"/"+subresource, []string{op.verb})
+		}
+	}
+
+	subresources := resource.Subresources
+	if subresources == nil {
+		subresources = []string{}
+	}
+
+	return ResourceCapabilities{
+		Group:    resource.Group,
+		Version:  resource.Version,
+		Kind:     resource.Kind,
+		Resource: restResource,
+		Scope:    string(resource.Scope),
+		GraphQL: GraphQLNames{
+			Group:    resource.GraphQLGroup,
+			Singular: resource.Singular,
+			Plural:   resource.Plural,
+		},
+		Operations:   operations,
+		Subresources: subresources,
+		DryRun:       true,
+		Pagination:   true,
+	}
+}
+
+// restResource returns the plural resource name of a kind, guessing it if the kind is unknown to the REST mapper
+func (tc *TargetCluster) restResource(resource gatewaySchema.Resource) string {
+	gk := schema.GroupKind{Group: resource.Group, Kind: resource.Kind}
+	if tc.client != nil {
+		if mapping, err := tc.client.RESTMapper().RESTMapping(gk, resource.Version); err == nil {
+			return mapping.Resource.Resource
+		}
+	}
+
+	return strings.ToLower(flect.Pluralize(resource.Kind))
+}
+
+// rulesAllow reports whether the rules grant every verb on the resource.
+// Rules restricted to resource names are ignored, since the generated operations are not limited to single objects.
+func rulesAllow(rules []authorizationv1.ResourceRule, group, resource string, verbs []string) bool {
+	for _, verb := range verbs {
+		allowed := slices.ContainsFunc(rules, func(rule authorizationv1.ResourceRule) bool {
+			return len(rule.ResourceNames) == 0 &&
+				matchesRule(rule.APIGroups, group) &&
+				matchesRule(rule.Resources, resource) &&
+				matchesRule(rule.Verbs, verb)
+		})
+		if !allowed {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesRule(values []string, value string) bool {
+	return slices.Contains(values, "*") || slices.Contains(values, value)
+}
@@ -0,0 +1,163 @@
+package targetcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestRulesAllow(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list"}},
+		{APIGroups: []string{"apps"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+		{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}, ResourceNames: []string{"only-this-one"}},
+	}
+
+	tests := []struct {
+		name     string
+		group    string
+		resource string
+		verbs    []string
+		expected bool
+	}{
+		{name: "allowed_verb", group: "", resource: "configmaps", verbs: []string{"list"}, expected: true},
+		{name: "missing_verb", group: "", resource: "configmaps", verbs: []string{"get", "patch"}, expected: false},
+		{name: "wildcards", group: "apps", resource: "deployments", verbs: []string{"list", "deletecollection"}, expected: true},
+		{name: "other_group", group: "batch", resource: "jobs", verbs: []string{"get"}, expected: false},
+		{name: "resource_names_ignored", group: "", resource: "secrets", verbs: []string{"get"}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, rulesAllow(rules, tt.group, tt.resource, tt.verbs))
+		})
+	}
+}
+
+func TestMatchCapabilitiesURL(t *testing.T) {
+	appCfg := appConfig.Config{}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+
+	clusterName, kcpWorkspace, ok := matchCapabilitiesURL("/root/capabilities", appCfg)
+	assert.True(t, ok)
+	assert.Equal(t, "root", clusterName)
+	assert.Empty(t, kcpWorkspace)
+
+	clusterName, kcpWorkspace, ok = matchCapabilitiesURL("/virtual-workspace/custom-ws/root:orgs/capabilities", appCfg)
+	assert.True(t, ok)
+	assert.Equal(t, "virtual-workspace/custom-ws", clusterName)
+	assert.Equal(t, "root:orgs", kcpWorkspace)
+
+	_, _, ok = matchCapabilitiesURL("/root/graphql", appCfg)
+	assert.False(t, ok)
+}
+
+func TestClusterRegistry_ServeCapabilities(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+
+	var reviewedNamespace string
+	runtimeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			review := obj.(*authorizationv1.SelfSubjectRulesReview)
+			reviewedNamespace = review.Spec.Namespace
+			review.Status.ResourceRules = []authorizationv1.ResourceRule{
+				{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get", "list", "watch"}},
+				{APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}, Verbs: []string{"patch"}},
+			}
+			return nil
+		},
+	}).Build()
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	registry.clusters["root"] = &TargetCluster{
+		name:             "root",
+		client:           runtimeClient,
+		defaultNamespace: "team-a",
+		resources: []schema.Resource{{
+			Version:      "v1",
+			Kind:         "ConfigMap",
+			Scope:        apiextensionsv1.NamespaceScoped,
+			GraphQLGroup: "core",
+			Singular:     "ConfigMap",
+			Plural:       "ConfigMaps",
+		}, {
+			Group:        "apps",
+			Version:      "v1",
+			Kind:         "Deployment",
+			Scope:        apiextensionsv1.NamespaceScoped,
+			GraphQLGroup: "apps",
+			Singular:     "Deployment",
+			Plural:       "Deployments",
+			Subresources: []string{"status", "scale"},
+		}},
+	}
+
+	t.Run("capabilities", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/root/capabilities", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var capabilities Capabilities
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &capabilities))
+		assert.Equal(t, "team-a", reviewedNamespace)
+		assert.Equal(t, "team-a", capabilities.Namespace)
+		require.Len(t, capabilities.Resources, 2)
+
+		configMaps := capabilities.Resources[0]
+		assert.Equal(t, "configmaps", configMaps.Resource)
+		assert.Equal(t, GraphQLNames{Group: "core", Singular: "ConfigMap", Plural: "ConfigMaps"}, configMaps.GraphQL)
+		assert.Equal(t, map[string]bool{
+			"list":           true,
+			"get":            true,
+			"create":         false,
+			"update":         false,
+			"apply":          false,
+			"delete":         false,
+			"deleteMatching": false,
+			"subscribe":      true,
+		}, configMaps.Operations)
+		assert.Empty(t, configMaps.Subresources)
+		assert.True(t, configMaps.DryRun)
+
+		deployments := capabilities.Resources[1]
+		assert.Equal(t, []string{"status", "scale"}, deployments.Subresources)
+		assert.False(t, deployments.Operations["updateStatus"])
+		assert.True(t, deployments.Operations["scale"])
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/root/capabilities?namespace=team-b", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "team-b", reviewedNamespace)
+	})
+
+	t.Run("method_not_allowed", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/root/capabilities", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("unknown_cluster", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/other/capabilities", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
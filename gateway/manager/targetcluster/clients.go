@@ -0,0 +1,218 @@
+package targetcluster
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/kcp"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+// Reasons the clients of a cluster are built, reported by the clientBuilds metric
+const (
+	clientBuildNew         = "new"
+	clientBuildCredentials = "credentials"
+	clientBuildExpired     = "expired"
+	clientBuildSchema      = "schema"
+)
+
+var clientBuilds = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "graphql_gateway",
+	Name:      "cluster_client_builds_total",
+	Help:      "Number of times the clients of a target cluster were built, by reason: new, credentials, expired or schema, which only rebuilds the client and REST mapper",
+}, []string{"cluster", "reason"})
+
+// clusterClients are the clients of a target cluster built from the same connection settings
+type clusterClients struct {
+	// config sends the requests of the callers, wrapped by the round trippers of the gateway
+	config *rest.Config
+	// readCacheConfig is the config before the callers are authenticated, the read cache uses the cluster's credentials
+	readCacheConfig *rest.Config
+	// httpClient holds the transport and the open connections shared by the other clients
+	httpClient *http.Client
+	// mapper is nil with kcp, where the client discovers the REST mappings of the workspace of each request
+	mapper    meta.RESTMapper
+	client    client.WithWatch
+	discovery rest.Interface
+	// upstream and identity are set by the round trippers of config, so they are kept with the transport
+	upstream *upstreamHealth
+	identity roundtripper.IdentityResolver
+
+	connectionKey string
+	schemaKey     string
+	created       time.Time
+}
+
+// ClusterClientManager caches the clients of the target clusters. The reloaded schemas of a cluster and the token
+// validations of its callers reuse the HTTP transport, its connections and the discovered REST mappings, instead of
+// doing new TLS handshakes and discovery requests. The clients of a cluster are built again when its connection
+// settings change or after the TTL, the client and REST mapper also when its schema changes.
+type ClusterClientManager struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	enableKcp bool
+	clients   map[string]*clusterClients
+	now       func() time.Time
+}
+
+// NewClusterClientManager keeps the clients of a cluster for ttl, 0 builds them for every schema. With enableKcp the
+// clients are cluster-aware.
+func NewClusterClientManager(ttl time.Duration, enableKcp bool) *ClusterClientManager {
+	return &ClusterClientManager{
+		ttl:       ttl,
+		enableKcp: enableKcp,
+		clients:   make(map[string]*clusterClients),
+		now:       time.Now,
+	}
+}
+
+// get returns the clients of the cluster for the connection and schema keys, connect builds the transport if the cached
+// one was built for other connection settings or expired. The returned clients are a copy, so that rebuilding them
+// doesn't change the clients of the previous schema, which may still serve requests.
+func (m *ClusterClientManager) get(cluster, connectionKey, schemaKey string, connect func() (*clusterClients, error)) (clusterClients, error) {
+	if m.ttl == 0 {
+		clients, err := m.build(cluster, clientBuildNew, connect)
+		if err != nil {
+			return clusterClients{}, err
+		}
+		return *clients, nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clients, ok := m.clients[cluster]
+	reason := ""
+	switch {
+	case !ok:
+		reason = clientBuildNew
+	case clients.connectionKey != connectionKey:
+		reason = clientBuildCredentials
+	case m.now().Sub(clients.created) > m.ttl:
+		reason = clientBuildExpired
+	}
+
+	if reason != "" {
+		built, err := m.build(cluster, reason, connect)
+		if err != nil {
+			return clusterClients{}, err
+		}
+		if ok {
+			// Requests of the previous schema still running keep their connections, only the idle ones are closed
+			clients.httpClient.CloseIdleConnections()
+		}
+		built.connectionKey, built.schemaKey = connectionKey, schemaKey
+		m.clients[cluster] = built
+		return *built, nil
+	}
+
+	if clients.schemaKey != schemaKey {
+		// The kinds of the cluster changed, the REST mappings are discovered again on the same transport
+		rebuilt := *clients
+		if err := m.newClients(&rebuilt); err != nil {
+			return clusterClients{}, err
+		}
+		clientBuilds.WithLabelValues(cluster, clientBuildSchema).Inc()
+		rebuilt.schemaKey = schemaKey
+		m.clients[cluster] = &rebuilt
+		return rebuilt, nil
+	}
+
+	return *clients, nil
+}
+
+// build connects to the cluster and creates the clients sharing the transport of the connection
+func (m *ClusterClientManager) build(cluster, reason string, connect func() (*clusterClients, error)) (*clusterClients, error) {
+	clients, err := connect()
+	if err != nil {
+		return nil, err
+	}
+
+	if m.enableKcp {
+		clients.httpClient, err = kcp.NewClusterAwareHTTPClient(clients.config)
+	} else {
+		clients.httpClient, err = rest.HTTPClientFor(clients.config)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfigAndClient(clients.config, clients.httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+	clients.discovery = discoveryClient.RESTClient()
+
+	if err := m.newClients(clients); err != nil {
+		return nil, err
+	}
+
+	clients.created = m.now()
+	clientBuilds.WithLabelValues(cluster, reason).Inc()
+	return clients, nil
+}
+
+// newClients creates the REST mapper and the client on the HTTP client of the clients
+func (m *ClusterClientManager) newClients(clients *clusterClients) error {
+	opts := client.Options{HTTPClient: clients.httpClient}
+
+	var err error
+	if m.enableKcp {
+		// The workspace of a request is only known from its context, so the REST mappings are discovered per workspace
+		opts.MapperWithContext = kcp.NewClusterAwareMapperProvider(clients.config, clients.httpClient)
+		clients.client, err = kcp.NewClusterAwareClientWithWatch(clients.config, opts)
+	} else {
+		clients.mapper, err = apiutil.NewDynamicRESTMapper(clients.config, clients.httpClient)
+		if err != nil {
+			return fmt.Errorf("failed to create REST mapper: %w", err)
+		}
+		opts.Mapper = clients.mapper
+		clients.client, err = client.NewWithWatch(clients.config, opts)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create cluster client: %w", err)
+	}
+	return nil
+}
+
+// Invalidate drops the clients of the cluster, e.g. because it was removed, and closes their idle connections
+func (m *ClusterClientManager) Invalidate(cluster string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if clients, ok := m.clients[cluster]; ok {
+		clients.httpClient.CloseIdleConnections()
+		delete(m.clients, cluster)
+	}
+}
+
+// connectionKey identifies the settings the clients of a cluster connect with, the defaults and labels of the cluster
+// don't change the connection
+func connectionKey(metadata *ClusterMetadata) string {
+	data, _ := json.Marshal(struct {
+		Host          string                      `json:"host"`
+		Path          string                      `json:"path"`
+		Auth          *AuthMetadata               `json:"auth"`
+		CA            *CAMetadata                 `json:"ca"`
+		AuthMode      string                      `json:"authMode"`
+		ClaimMappings *roundtripper.ClaimMappings `json:"claimMappings"`
+		Proxy         *auth.ProxyMetadata         `json:"proxy"`
+	}{metadata.Host, metadata.Path, metadata.Auth, metadata.CA, metadata.AuthMode, metadata.ClaimMappings, metadata.Proxy})
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+package targetcluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestClusterClientManager_Get(t *testing.T) {
+	type step struct {
+		connectionKey string
+		schemaKey     string
+		// elapsed is the time passed since the previous step
+		elapsed time.Duration
+		// invalidate drops the clients before the step
+		invalidate bool
+		// expectedConnect is set if the transport is built, expectedClient if the client and REST mapper are
+		expectedConnect bool
+		expectedClient  bool
+	}
+
+	tests := []struct {
+		name  string
+		ttl   time.Duration
+		kcp   bool
+		steps []step
+	}{
+		{
+			name: "reuse",
+			ttl:  time.Hour,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "1", elapsed: time.Minute},
+			},
+		},
+		{
+			name: "schema_change",
+			ttl:  time.Hour,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "2", expectedClient: true},
+				{connectionKey: "a", schemaKey: "2"},
+			},
+		},
+		{
+			name: "credentials_change",
+			ttl:  time.Hour,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "b", schemaKey: "1", expectedConnect: true, expectedClient: true},
+			},
+		},
+		{
+			name: "expired",
+			ttl:  time.Hour,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "1", elapsed: 2 * time.Hour, expectedConnect: true, expectedClient: true},
+			},
+		},
+		{
+			name: "invalidated",
+			ttl:  time.Hour,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "1", invalidate: true, expectedConnect: true, expectedClient: true},
+			},
+		},
+		{
+			name: "kcp",
+			ttl:  time.Hour,
+			kcp:  true,
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "2", expectedClient: true},
+				{connectionKey: "a", schemaKey: "2"},
+			},
+		},
+		{
+			name: "disabled",
+			steps: []step{
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+				{connectionKey: "a", schemaKey: "1", expectedConnect: true, expectedClient: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewClusterClientManager(tt.ttl, tt.kcp)
+			now := time.Now()
+			manager.now = func() time.Time { return now }
+
+			var previous clusterClients
+			for i, s := range tt.steps {
+				now = now.Add(s.elapsed)
+				if s.invalidate {
+					manager.Invalidate("cluster")
+				}
+
+				connected := false
+				clients, err := manager.get("cluster", s.connectionKey, s.schemaKey, func() (*clusterClients, error) {
+					connected = true
+					// Without TLS settings the HTTP client is http.DefaultClient, which the cluster-aware HTTP client of
+					// kcp would modify
+					return &clusterClients{config: &rest.Config{
+						Host:            "https://cluster.example.com",
+						TLSClientConfig: rest.TLSClientConfig{Insecure: true},
+					}}, nil
+				})
+				require.NoError(t, err)
+				require.NotNil(t, clients.client)
+
+				assert.Equal(t, s.expectedConnect, connected, "step %d builds the transport", i)
+				if i > 0 {
+					assert.Equal(t, s.expectedConnect, clients.httpClient != previous.httpClient, "step %d has a new HTTP client", i)
+					assert.Equal(t, s.expectedClient, clients.client != previous.client, "step %d has a new client", i)
+				}
+				previous = clients
+			}
+		})
+	}
+}
+
+func TestConnectionKey(t *testing.T) {
+	metadata := &ClusterMetadata{Host: "https://cluster.example.com", Auth: &AuthMetadata{Type: "token", Token: "a"}}
+	key := connectionKey(metadata)
+
+	// Defaults and labels don't change the connection
+	metadata.Labels = map[string]string{"env": "prod"}
+	assert.Equal(t, key, connectionKey(metadata))
+
+	metadata.Auth.Token = "b"
+	assert.NotEqual(t, key, connectionKey(metadata))
+}
@@ -1,20 +1,33 @@
 package targetcluster
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/go-openapi/spec"
 	"github.com/openmfp/golang-commons/logger"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	runtimeSchema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/kcp"
 
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
 	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
 	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/recording"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
 )
@@ -23,6 +36,13 @@ import (
 type FileData struct {
 	Definitions     map[string]any   `json:"definitions"`
 	ClusterMetadata *ClusterMetadata `json:"x-cluster-metadata,omitempty"`
+	// Requirements is missing in schema files written by listeners that predate the compatibility check
+	Requirements *common.SchemaRequirements `json:"x-schema-requirements,omitempty"`
+	// GenerationErrors are the definitions the listener left out of the schema, e.g. of CRDs with an invalid schema
+	GenerationErrors []common.GenerationError `json:"x-generation-errors,omitempty"`
+
+	// digest identifies the content of the schema file, the REST mappings of a cluster are discovered again if it changes
+	digest string
 }
 
 // ClusterMetadata represents the cluster connection metadata stored in schema files
@@ -31,6 +51,18 @@ type ClusterMetadata struct {
 	Path string        `json:"path,omitempty"`
 	Auth *AuthMetadata `json:"auth,omitempty"`
 	CA   *CAMetadata   `json:"ca,omitempty"`
+
+	Defaults *resolver.ClusterDefaults `json:"defaults,omitempty"`
+	// AuthMode is the auth mode of the ClusterAccess, see roundtripper.WithTokenReviewer
+	AuthMode string `json:"authMode,omitempty"`
+	// ClaimMappings are the claim mappings of the ClusterAccess, see roundtripper.WithClaimMappings
+	ClaimMappings *roundtripper.ClaimMappings `json:"claimMappings,omitempty"`
+	// Labels are the labels of the ClusterAccess, used to select the members of cluster groups
+	Labels map[string]string `json:"labels,omitempty"`
+	// Proxy is the authenticating proxy the cluster is reached through
+	Proxy *auth.ProxyMetadata `json:"proxy,omitempty"`
+	// Features restrict the schema served for the cluster, see schemaFeatureOptions
+	Features *gatewayv1alpha1.ClusterFeatures `json:"features,omitempty"`
 }
 
 // AuthMetadata represents authentication information
@@ -40,6 +72,8 @@ type AuthMetadata struct {
 	Kubeconfig string `json:"kubeconfig,omitempty"`
 	CertData   string `json:"certData,omitempty"`
 	KeyData    string `json:"keyData,omitempty"`
+	// Exec is the credential plugin of the exec type
+	Exec *gatewayv1alpha1.ExecConfig `json:"exec,omitempty"`
 }
 
 // CAMetadata represents CA certificate information
@@ -55,7 +89,33 @@ type TargetCluster struct {
 	restCfg       *rest.Config
 	handler       *GraphQLHandler
 	graphqlServer *GraphQLServer
-	log           *logger.Logger
+	// profileHandlers serve the trimmed schemas of the configured schema profiles, keyed by profile name
+	profileHandlers map[string]*GraphQLHandler
+	// resources are the kinds of the full schema, described by the capabilities endpoint
+	resources []schema.Resource
+	// defaultNamespace is used for the RBAC checks of the capabilities endpoint if no namespace is requested
+	defaultNamespace string
+	// definitions and defaults are stored in the bundles of recorded operations
+	definitions map[string]any
+	defaults    *resolver.ClusterDefaults
+	// discovery sends the discovery requests of the clusterInfo query
+	discovery rest.Interface
+	// httpClient is shared by the clients of the cluster and validates the tokens, see ClusterClientManager
+	httpClient *http.Client
+	// labels select the cluster groups the cluster is a member of
+	labels map[string]string
+	// upstream tracks the latency and error rate of the API server for load shedding
+	upstream *upstreamHealth
+	// readCache serves the reads of the kinds in readCacheKinds, it is stopped by Close
+	readCache      cache.Cache
+	readCacheKinds []runtimeSchema.GroupVersionKind
+	stopReadCache  context.CancelFunc
+	// auditSink receives the audit events of the mutations, identity resolves the callers they are recorded for
+	auditSink audit.Sink
+	identity  roundtripper.IdentityResolver
+	// generationErrors are reported by the schemaDiagnostics query, see FileData.GenerationErrors
+	generationErrors []common.GenerationError
+	log              *logger.Logger
 }
 
 // NewTargetCluster creates a new TargetCluster from a schema file
@@ -64,28 +124,50 @@ func NewTargetCluster(
 	schemaFilePath string,
 	log *logger.Logger,
 	appCfg appConfig.Config,
-	roundTripperFactory func(http.RoundTripper, rest.TLSClientConfig) http.RoundTripper,
+	roundTripperFactory RoundTripperFactory,
+	auditSink audit.Sink,
+	clientManager *ClusterClientManager,
 ) (*TargetCluster, error) {
 	fileData, err := readSchemaFile(schemaFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read schema file: %w", err)
 	}
 
+	recordSchemaVersionSkew(name, fileData.Requirements)
+	if err := common.CheckSchemaRequirements(fileData.Requirements); err != nil {
+		return nil, err
+	}
+
 	cluster := &TargetCluster{
-		appCfg: appCfg,
-		name:   name,
-		log:    log,
+		appCfg:           appCfg,
+		name:             name,
+		auditSink:        auditSink,
+		generationErrors: fileData.GenerationErrors,
+		log:              log,
+	}
+	for _, generationError := range fileData.GenerationErrors {
+		log.Warn().
+			Str("cluster", name).
+			Str("groupVersion", generationError.GroupVersion).
+			Str("definition", generationError.Definition).
+			Str("error", generationError.Error).
+			Msg("Definition was left out of the schema by the listener")
 	}
 
 	// Connect to cluster - use metadata if available, otherwise fall back to standard config
-	if err := cluster.connect(appCfg, fileData.ClusterMetadata, roundTripperFactory); err != nil {
+	if err := cluster.connect(appCfg, fileData.ClusterMetadata, fileData.digest, roundTripperFactory, clientManager); err != nil {
 		return nil, fmt.Errorf("failed to connect to cluster: %w", err)
 	}
 
 	// Create GraphQL schema and handler
-	if err := cluster.createHandler(fileData.Definitions, appCfg); err != nil {
+	if err := cluster.createHandler(fileData.Definitions, fileData.ClusterMetadata.Defaults, fileData.ClusterMetadata.Features, appCfg); err != nil {
 		return nil, fmt.Errorf("failed to create GraphQL handler: %w", err)
 	}
+	cluster.labels = fileData.ClusterMetadata.Labels
+
+	if err := cluster.validate(); err != nil {
+		return nil, err
+	}
 
 	log.Info().
 		Str("cluster", name).
@@ -95,8 +177,9 @@ func NewTargetCluster(
 	return cluster, nil
 }
 
-// connect establishes connection to the target cluster
-func (tc *TargetCluster) connect(appCfg appConfig.Config, metadata *ClusterMetadata, roundTripperFactory func(http.RoundTripper, rest.TLSClientConfig) http.RoundTripper) error {
+// connect establishes connection to the target cluster, reusing the clients of its previous schema if its connection
+// settings didn't change, see ClusterClientManager
+func (tc *TargetCluster) connect(appCfg appConfig.Config, metadata *ClusterMetadata, schemaKey string, roundTripperFactory RoundTripperFactory, clientManager *ClusterClientManager) error {
 	// All clusters now use metadata from schema files to get kubeconfig
 	if metadata == nil {
 		return fmt.Errorf("cluster %s requires cluster metadata in schema file", tc.name)
@@ -108,31 +191,150 @@ func (tc *TargetCluster) connect(appCfg appConfig.Config, metadata *ClusterMetad
 		Bool("isVirtualWorkspace", strings.HasPrefix(tc.name, tc.appCfg.Url.VirtualWorkspacePrefix)).
 		Msg("Using cluster metadata from schema file for connection")
 
-	var err error
-	tc.restCfg, err = buildConfigFromMetadata(metadata, tc.log)
+	clients, err := clientManager.get(tc.name, connectionKey(metadata), schemaKey, func() (*clusterClients, error) {
+		return tc.newClusterClients(appCfg, metadata, roundTripperFactory)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to build config from metadata: %w", err)
+		return err
+	}
+	tc.restCfg = clients.config
+	tc.httpClient = clients.httpClient
+	tc.client = clients.client
+	tc.discovery = clients.discovery
+	tc.upstream = clients.upstream
+	tc.identity = clients.identity
+
+	// The read cache is filled with the credentials of the cluster, not with the ones of the callers
+	if err := tc.startReadCache(rest.CopyConfig(clients.readCacheConfig), appCfg); err != nil {
+		return fmt.Errorf("failed to start read cache: %w", err)
 	}
 
+	return nil
+}
+
+// newClusterClients builds the config of the cluster with the round trippers of the gateway, the clients are created
+// on it by the ClusterClientManager
+func (tc *TargetCluster) newClusterClients(appCfg appConfig.Config, metadata *ClusterMetadata, roundTripperFactory RoundTripperFactory) (*clusterClients, error) {
+	restCfg, err := buildConfigFromMetadata(metadata, tc.log)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config from metadata: %w", err)
+	}
+	throttleAPIServer(restCfg, appCfg)
+	clients := &clusterClients{config: restCfg, upstream: &upstreamHealth{}}
+
+	// Propagates the trace context of the resolvers to the API server, innermost so that every request gets its span
+	restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return otelhttp.NewTransport(rt)
+	})
+
+	restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newFaultInjectionRoundTripper(rt, tc.name)
+	})
+
+	restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newFormatMetricsRoundTripper(rt, tc.name)
+	})
+
+	restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return newUpstreamHealthRoundTripper(rt, clients.upstream)
+	})
+
+	if appCfg.Gateway.DebugRecordingDir != "" {
+		restCfg.Wrap(recording.NewRoundTripper)
+	}
+
+	clients.readCacheConfig = rest.CopyConfig(restCfg)
+
 	if roundTripperFactory != nil {
-		tc.restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
-			return roundTripperFactory(rt, tc.restCfg.TLSClientConfig)
+		opts, err := tc.roundTripperOptions(appCfg, metadata, restCfg)
+		if err != nil {
+			return nil, err
+		}
+		restCfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+			wrapped := roundTripperFactory(rt, restCfg.TLSClientConfig, opts...)
+			if identity, ok := wrapped.(roundtripper.IdentityResolver); ok {
+				clients.identity = identity
+			}
+			return wrapped
 		})
 	}
 
-	// Create client - use KCP-aware client only for KCP mode, standard client otherwise
+	return clients, nil
+}
+
+// roundTripperOptions returns the settings of the round tripper authenticating the callers of the cluster. The auth
+// mode of the ClusterAccess takes precedence over the one of the gateway.
+func (tc *TargetCluster) roundTripperOptions(appCfg appConfig.Config, metadata *ClusterMetadata, restCfg *rest.Config) ([]roundtripper.Option, error) {
+	authMode := metadata.AuthMode
+	if authMode == "" {
+		authMode = appCfg.Gateway.AuthMode
+	}
+
+	switch authMode {
+	case "", gatewayv1alpha1.AuthModeClaims:
+		if metadata.ClaimMappings != nil {
+			return []roundtripper.Option{roundtripper.WithClaimMappings(*metadata.ClaimMappings)}, nil
+		}
+		return nil, nil
+	case gatewayv1alpha1.AuthModeTokenReview:
+		// The tokens are reviewed with the credentials of the cluster, not with the ones of the callers
+		reviewer, err := roundtripper.NewTokenReviewer(rest.CopyConfig(restCfg))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create token reviewer: %w", err)
+		}
+		return []roundtripper.Option{roundtripper.WithTokenReviewer(reviewer)}, nil
+	default:
+		return nil, fmt.Errorf("unknown auth mode %q of cluster %s", authMode, tc.name)
+	}
+}
+
+// startReadCache starts the informers of the kinds configured for the read cache, see resolver.WithReadCache
+func (tc *TargetCluster) startReadCache(cfg *rest.Config, appCfg appConfig.Config) error {
+	kinds, err := resolver.ParseReadCacheKinds(appCfg.Gateway.ReadCache.Kinds)
+	if err != nil || len(kinds) == 0 {
+		return err
+	}
+
+	var readCache cache.Cache
 	if appCfg.EnableKcp {
-		tc.client, err = kcp.NewClusterAwareClientWithWatch(tc.restCfg, client.Options{})
+		readCache, err = kcp.NewClusterAwareCache(cfg, cache.Options{})
 	} else {
-		tc.client, err = client.NewWithWatch(tc.restCfg, client.Options{})
+		readCache, err = cache.New(cfg, cache.Options{})
 	}
 	if err != nil {
-		return fmt.Errorf("failed to create cluster client: %w", err)
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	// The informers are created upfront, so that the first queries don't wait for them to sync
+	for _, gvk := range kinds {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if _, err := readCache.GetInformer(ctx, obj, cache.BlockUntilSynced(false)); err != nil {
+			cancel()
+			return fmt.Errorf("failed to create informer for %s: %w", gvk, err)
+		}
 	}
 
+	go func() {
+		if err := readCache.Start(ctx); err != nil {
+			tc.log.Error().Err(err).Str("cluster", tc.name).Msg("Read cache stopped")
+		}
+	}()
+
+	tc.readCache = readCache
+	tc.readCacheKinds = kinds
+	tc.stopReadCache = cancel
 	return nil
 }
 
+// Close stops the informers of the read cache. The cluster keeps serving, but reads no longer use the cache.
+func (tc *TargetCluster) Close() {
+	if tc.stopReadCache != nil {
+		tc.stopReadCache()
+	}
+}
+
 // buildConfigFromMetadata creates rest.Config from cluster metadata
 func buildConfigFromMetadata(metadata *ClusterMetadata, log *logger.Logger) (*rest.Config, error) {
 	var authType, token, kubeconfig, certData, keyData, caData string
@@ -154,6 +356,16 @@ func buildConfigFromMetadata(metadata *ClusterMetadata, log *logger.Logger) (*re
 	if err != nil {
 		return nil, err
 	}
+	if authType == "exec" && metadata.Auth.Exec != nil {
+		// The schema files are written by others than the operator of the gateway, so the command is checked again
+		if err := auth.CheckExecCommand(metadata.Auth.Exec.Command); err != nil {
+			return nil, err
+		}
+		config.ExecProvider = auth.ExecProvider(metadata.Auth.Exec)
+	}
+	if err := auth.ConfigureProxy(config, metadata.Proxy); err != nil {
+		return nil, err
+	}
 
 	log.Debug().
 		Str("host", metadata.Host).
@@ -165,29 +377,233 @@ func buildConfigFromMetadata(metadata *ClusterMetadata, log *logger.Logger) (*re
 }
 
 // createHandler creates the GraphQL schema and handler
-func (tc *TargetCluster) createHandler(definitions map[string]interface{}, appCfg appConfig.Config) error {
+func (tc *TargetCluster) createHandler(definitions map[string]interface{}, defaults *resolver.ClusterDefaults, features *gatewayv1alpha1.ClusterFeatures, appCfg appConfig.Config) error {
 	// Convert definitions to spec format
 	specDefs, err := convertToSpecDefinitions(definitions)
 	if err != nil {
 		return fmt.Errorf("failed to convert definitions: %w", err)
 	}
 
+	namespaceTemplates, err := resolver.LoadNamespaceTemplates(appCfg.Gateway.NamespaceTemplatesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load namespace templates: %w", err)
+	}
+
+	redaction, err := resolver.LoadRedactionRules(appCfg.Gateway.RedactionRulesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load redaction rules: %w", err)
+	}
+
+	inputCoercion, err := resolver.ParseInputCoercion(appCfg.Gateway.InputCoercion)
+	if err != nil {
+		return err
+	}
+
+	kubeconfigIssuance, err := tc.kubeconfigIssuance(appCfg)
+	if err != nil {
+		return err
+	}
+
+	subscriptionOrdering, err := resolver.ParseSubscriptionOrdering(appCfg.Gateway.Subscription.Ordering)
+	if err != nil {
+		return err
+	}
+
+	ownerRelations, err := resolver.ParseOwnerRelations(appCfg.Gateway.SchemaOwnerRelations)
+	if err != nil {
+		return err
+	}
+
+	subresources, err := schema.ParseSubresources(appCfg.Gateway.Subresources)
+	if err != nil {
+		return err
+	}
+
 	// Create resolver
-	resolverProvider := resolver.New(tc.log, tc.client)
+	resolverProvider := resolver.New(tc.log, tc.client).
+		WithNamespaceTemplates(namespaceTemplates).
+		WithInputCoercion(inputCoercion, specDefs).
+		WithClusterDefaults(defaults).
+		WithProtobuf(appCfg.Gateway.ApiServerProtobuf).
+		WithKubeconfigIssuance(kubeconfigIssuance).
+		WithSubscriptionBuffer(subscriptionOrdering, appCfg.Gateway.Subscription.BufferSize).
+		WithWatchMultiplexing(appCfg.Gateway.Subscription.Multiplex).
+		WithListChunking(appCfg.Gateway.List.ChunkSize, appCfg.Gateway.List.MaxItems).
+		WithDiscovery(tc.discovery).
+		WithReadCache(tc.readCache, tc.readCacheKinds).
+		WithWorkspaceAccessFilter(appCfg.EnableKcp).
+		WithPermissions(appCfg.Gateway.SchemaPermissions).
+		WithRedaction(redaction).
+		WithAudit(tc.auditor())
+
+	profiles, err := schema.LoadSchemaProfiles(appCfg.Gateway.SchemaProfilesPath)
+	if err != nil {
+		return fmt.Errorf("failed to load schema profiles: %w", err)
+	}
+
+	transformations, err := schema.LoadSchemaTransformations(appCfg.Gateway.SchemaTransformationsPath)
+	if err != nil {
+		return fmt.Errorf("failed to load schema transformations: %w", err)
+	}
+
+	schemaOpts := []schema.Option{
+		schema.WithDescriptionLength(appCfg.Gateway.SchemaDescriptionLength),
+		schema.WithTransformations(transformations),
+		schema.WithSubresources(subresources),
+		schema.WithGenerationErrors(tc.generationErrors),
+	}
+	if appCfg.Gateway.SchemaEnums {
+		schemaOpts = append(schemaOpts, schema.WithEnums())
+	}
+	if appCfg.Gateway.SchemaKubernetesScalars {
+		schemaOpts = append(schemaOpts, schema.WithKubernetesScalars())
+	}
+	if appCfg.Gateway.SchemaRequiredInputs {
+		schemaOpts = append(schemaOpts, schema.WithRequiredInputFields())
+	}
+	if appCfg.Gateway.SchemaPermissions {
+		schemaOpts = append(schemaOpts, schema.WithPermissions())
+	}
+	if appCfg.Gateway.SchemaEvents {
+		schemaOpts = append(schemaOpts, schema.WithEvents())
+	}
+	if appCfg.Gateway.SchemaResourceMetrics {
+		schemaOpts = append(schemaOpts, schema.WithResourceMetrics())
+	}
+	if appCfg.Gateway.SchemaOwnerReferences {
+		ownerRelations = append(slices.Clone(resolver.DefaultOwnerRelations), ownerRelations...)
+	}
+	if len(ownerRelations) > 0 {
+		schemaOpts = append(schemaOpts, schema.WithOwnerReferences(ownerRelations))
+	}
+	if appCfg.Gateway.HandlerCfg.Federation {
+		schemaOpts = append(schemaOpts, schema.WithFederation())
+	}
+	if kubeconfigIssuance != nil {
+		schemaOpts = append(schemaOpts, schema.WithKubeconfigIssuance())
+	}
+	if tc.readCache != nil {
+		schemaOpts = append(schemaOpts, schema.WithReadCache(tc.readCacheKinds))
+	}
+	schemaOpts = append(schemaOpts, schemaFeatureOptions(features)...)
 
 	// Create schema gateway
-	schemaGateway, err := schema.New(tc.log, specDefs, resolverProvider)
+	schemaGateway, err := schema.New(tc.log, specDefs, resolverProvider, schemaOpts...)
 	if err != nil {
 		return fmt.Errorf("failed to create GraphQL schema: %w", err)
 	}
 
 	// Create and store GraphQL server and handler
 	tc.graphqlServer = NewGraphQLServer(tc.log, appCfg)
-	tc.handler = tc.graphqlServer.CreateHandler(schemaGateway.GetSchema())
+	tc.handler = tc.graphqlServer.CreateHandler(schemaGateway.GetSchema()).WithVariableCoercion(resolverProvider)
+	tc.resources = schemaGateway.Resources()
+	tc.definitions = definitions
+	tc.defaults = defaults
+
+	tc.defaultNamespace = "default"
+	if defaults != nil && defaults.DefaultNamespace != "" {
+		tc.defaultNamespace = defaults.DefaultNamespace
+	}
+
+	// Every profile is generated from the same definitions and shares the resolver of the full schema
+	tc.profileHandlers = make(map[string]*GraphQLHandler, len(profiles))
+	for _, profile := range profiles {
+		profileGateway, err := schema.New(tc.log, profile.FilterDefinitions(specDefs), resolverProvider, schemaOpts...)
+		if err != nil {
+			return fmt.Errorf("failed to create GraphQL schema for profile %s: %w", profile.Name, err)
+		}
+		tc.profileHandlers[profile.Name] = tc.graphqlServer.CreateHandler(profileGateway.GetSchema()).WithVariableCoercion(resolverProvider)
+	}
 
 	return nil
 }
 
+// schemaFeatureOptions returns the schema options for the features of the ClusterAccess, they apply to the schema
+// profiles as well
+func schemaFeatureOptions(features *gatewayv1alpha1.ClusterFeatures) []schema.Option {
+	if features == nil {
+		return nil
+	}
+
+	var opts []schema.Option
+	if features.ReadOnly {
+		opts = append(opts, schema.WithoutMutations())
+	}
+	if features.DisableSubscriptions {
+		opts = append(opts, schema.WithoutSubscriptions())
+	}
+	if len(features.Denylist) > 0 {
+		denied := make([]runtimeSchema.GroupVersionKind, 0, len(features.Denylist))
+		for _, kind := range features.Denylist {
+			denied = append(denied, runtimeSchema.GroupVersionKind{Group: kind.Group, Version: kind.Version, Kind: kind.Kind})
+		}
+		opts = append(opts, schema.WithDeniedKinds(denied))
+	}
+	return opts
+}
+
+// kubeconfigIssuance returns the configuration of the issueKubeconfig mutation, nil if no service account is configured.
+// The issued kubeconfigs point to the same API server the cluster is connected to.
+func (tc *TargetCluster) kubeconfigIssuance(appCfg appConfig.Config) (*resolver.KubeconfigIssuance, error) {
+	if appCfg.Gateway.Kubeconfig.ServiceAccount == "" {
+		return nil, nil
+	}
+
+	serviceAccount, err := resolver.ParseServiceAccount(appCfg.Gateway.Kubeconfig.ServiceAccount)
+	if err != nil {
+		return nil, err
+	}
+
+	if appCfg.Gateway.Kubeconfig.MaxTTL < resolver.MinKubeconfigTTL {
+		return nil, fmt.Errorf("%w: the maximum %s is shorter than %s", resolver.ErrInvalidKubeconfigTTL, appCfg.Gateway.Kubeconfig.MaxTTL, resolver.MinKubeconfigTTL)
+	}
+
+	var audiences []string
+	for _, audience := range strings.Split(appCfg.Gateway.Kubeconfig.Audiences, ",") {
+		if audience = strings.TrimSpace(audience); audience != "" {
+			audiences = append(audiences, audience)
+		}
+	}
+	if len(audiences) == 0 {
+		return nil, resolver.ErrMissingKubeconfigAudiences
+	}
+
+	return &resolver.KubeconfigIssuance{
+		Cluster:               tc.name,
+		Server:                tc.restCfg.Host,
+		CAData:                tc.restCfg.CAData,
+		InsecureSkipTLSVerify: tc.restCfg.Insecure,
+		ServiceAccount:        serviceAccount,
+		MaxTTL:                appCfg.Gateway.Kubeconfig.MaxTTL,
+		Audiences:             audiences,
+	}, nil
+}
+
+// auditor returns the auditor recording the mutations of the cluster, nil if the audit log is disabled
+func (tc *TargetCluster) auditor() *audit.Auditor {
+	if tc.auditSink == nil {
+		return nil
+	}
+
+	return audit.NewAuditor(tc.log, tc.auditSink, tc.name, func(ctx context.Context) (string, []string) {
+		// The identity is resolved by the round tripper of the cluster, which is missing without authentication
+		if tc.identity == nil {
+			return "", nil
+		}
+		return tc.identity.Identity(ctx)
+	})
+}
+
+// handlerFor returns the handler serving the given schema profile, the full schema is served if no profile is requested
+func (tc *TargetCluster) handlerFor(profile string) (*GraphQLHandler, bool) {
+	if profile == "" || profile == schema.FullSchemaProfile {
+		return tc.handler, true
+	}
+
+	handler, ok := tc.profileHandlers[profile]
+	return handler, ok
+}
+
 // GetName returns the cluster name
 func (tc *TargetCluster) GetName() string {
 	return tc.name
@@ -209,11 +625,12 @@ func (tc *TargetCluster) GetEndpoint(appCfg appConfig.Config) string {
 		path = fmt.Sprintf("%s/%s", path, appCfg.Url.DefaultKcpWorkspace)
 	}
 
-	if appCfg.LocalDevelopment {
-		return fmt.Sprintf("http://localhost:%s/%s/%s", appCfg.Gateway.Port, path, appCfg.Url.GraphqlSuffix)
+	endpoint := ExternalURL(fmt.Sprintf("/%s/%s", path, appCfg.Url.GraphqlSuffix), appCfg)
+	if appCfg.LocalDevelopment && appCfg.Url.ExternalURL == "" {
+		return fmt.Sprintf("http://localhost:%s%s", appCfg.Gateway.Port, endpoint)
 	}
 
-	return fmt.Sprintf("/%s/%s", path, appCfg.Url.GraphqlSuffix)
+	return endpoint
 }
 
 // ServeHTTP handles HTTP requests for this cluster
@@ -223,13 +640,25 @@ func (tc *TargetCluster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	profile := GetSchemaProfile(r)
+	handler, ok := tc.handlerFor(profile)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown schema profile %q", profile), http.StatusNotFound)
+		return
+	}
+
 	// Handle subscription requests using Server-Sent Events
 	if r.Header.Get("Accept") == "text/event-stream" {
-		tc.graphqlServer.HandleSubscription(w, r, tc.handler.Schema)
+		tc.graphqlServer.HandleSubscription(w, r, handler.Schema)
 		return
 	}
 
-	tc.handler.Handler.ServeHTTP(w, r)
+	// The playground sends its queries to the path it was loaded from, which clients reach under the external path
+	if r.Method == http.MethodGet {
+		r = withPath(r, ExternalPath(r.URL.Path, tc.appCfg))
+	}
+
+	handler.Handler.ServeHTTP(w, r)
 }
 
 // readSchemaFile reads and parses a schema file
@@ -243,6 +672,8 @@ func readSchemaFile(filePath string) (*FileData, error) {
 	if err := json.Unmarshal(data, &fileData); err != nil {
 		return nil, fmt.Errorf("failed to parse JSON: %w", err)
 	}
+	sum := sha256.Sum256(data)
+	fileData.digest = hex.EncodeToString(sum[:])
 
 	return &fileData, nil
 }
@@ -0,0 +1,347 @@
+package targetcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"k8s.io/apimachinery/pkg/labels"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+// GroupsPrefix is the first path segment of the cluster group endpoints, e.g. /groups/{group}/graphql
+const GroupsPrefix = "groups"
+
+// groupFanOutConcurrency is the maximum amount of member clusters a group operation is sent to at once
+const groupFanOutConcurrency = 10
+
+var (
+	ErrReadClusterGroups     = errors.New("failed to read cluster groups file")
+	ErrParseClusterGroups    = errors.New("failed to parse cluster groups file")
+	ErrInvalidClusterGroup   = errors.New("invalid cluster group")
+	ErrDuplicateClusterGroup = errors.New("duplicate cluster group name")
+)
+
+var clusterGroupNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ClusterGroup is a named set of clusters whose labels match the selector, e.g. tier=prod,region in (eu,us).
+// The members are evaluated for every operation, so clusters join and leave the group as their labels change.
+type ClusterGroup struct {
+	Name     string `json:"name"`
+	Selector string `json:"selector"`
+
+	selector labels.Selector
+}
+
+// ClusterGroupsConfig represents the cluster groups file structure
+type ClusterGroupsConfig struct {
+	Groups []ClusterGroup `json:"groups"`
+}
+
+// LoadClusterGroups reads the cluster groups from a YAML or JSON file.
+// An empty path results in no group endpoints being served.
+func LoadClusterGroups(path string) ([]ClusterGroup, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadClusterGroups, err)
+	}
+	defer f.Close()
+
+	var cfg ClusterGroupsConfig
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&cfg); err != nil {
+		return nil, errors.Join(ErrParseClusterGroups, err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Groups))
+	for i, group := range cfg.Groups {
+		if !clusterGroupNameRegex.MatchString(group.Name) {
+			return nil, fmt.Errorf("%w: %q is not a valid group name", ErrInvalidClusterGroup, group.Name)
+		}
+		if seen[group.Name] {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateClusterGroup, group.Name)
+		}
+		seen[group.Name] = true
+
+		if strings.TrimSpace(group.Selector) == "" {
+			return nil, fmt.Errorf("%w: %s: selector is required", ErrInvalidClusterGroup, group.Name)
+		}
+		selector, err := labels.Parse(group.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidClusterGroup, group.Name, err)
+		}
+		cfg.Groups[i].selector = selector
+	}
+
+	return cfg.Groups, nil
+}
+
+// WithClusterGroups serves the groups under /groups/{group}/graphql
+func (cr *ClusterRegistry) WithClusterGroups(groups []ClusterGroup) *ClusterRegistry {
+	cr.clusterGroups = groups
+	return cr
+}
+
+// GroupMembers returns the names of the loaded clusters that are members of the group, sorted by name.
+// Only clusters with labels, i.e. those of ClusterAccess objects, are members of groups.
+func (cr *ClusterRegistry) GroupMembers(groupName string) ([]string, bool) {
+	idx := -1
+	for i, group := range cr.clusterGroups {
+		if group.Name == groupName {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, false
+	}
+	selector := cr.clusterGroups[idx].selector
+
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	members := []string{}
+	for name, cluster := range cr.clusters {
+		if len(cluster.labels) > 0 && selector.Matches(labels.Set(cluster.labels)) {
+			members = append(members, name)
+		}
+	}
+	sort.Strings(members)
+
+	return members, true
+}
+
+// matchGroupURL matches /groups/{group}/graphql
+func matchGroupURL(path string, appCfg appConfig.Config) (string, bool) {
+	vars := matchPattern(fmt.Sprintf("/%s/{group}/%s", GroupsPrefix, appCfg.Url.GraphqlSuffix), path)
+	if vars == nil || vars["group"] == "" {
+		return "", false
+	}
+
+	return vars["group"], true
+}
+
+// serveGroup sends a query to every member cluster of the group and aggregates the results. The data of every
+// cluster is returned under its name and the errors carry the cluster in their extensions and path, e.g.
+//
+//	{"data": {"cluster-a": {...}, "cluster-b": null}, "errors": [{"message": "...", "path": ["cluster-b", ...], "extensions": {"cluster": "cluster-b"}}]}
+//
+// Every member authenticates and limits the operation like a request sent to the cluster directly.
+// Mutations and subscriptions are rejected, group endpoints are meant for fleet-wide reads.
+func (cr *ClusterRegistry) serveGroup(w http.ResponseWriter, r *http.Request, groupName string) {
+	members, ok := cr.GroupMembers(groupName)
+	if !ok {
+		cr.log.Error().Str("group", groupName).Str("path", r.URL.Path).Msg("Cluster group not found")
+		http.NotFound(w, r)
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.Header.Get("Accept") == "text/event-stream" {
+		writeGroupError(w, http.StatusBadRequest, "subscriptions are not supported by cluster groups")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		writeGroupError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := checkGroupQuery(getQuery(r)); err != nil {
+		writeGroupError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	cr.log.Debug().
+		Str("group", groupName).
+		Strs("members", members).
+		Msg("Sending operation to the members of the cluster group")
+
+	responses := cr.fanOut(r, body, members)
+
+	result := aggregateGroupResponses(members, responses)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		cr.log.Error().Err(err).Str("group", groupName).Msg("Failed to write cluster group response")
+	}
+}
+
+// fanOut sends the operation in body to every named cluster, at most groupFanOutConcurrency at once, as if it was
+// sent to the cluster directly. The responses are returned in the order of the names, the responses of clusters that
+// are not loaded are nil.
+func (cr *ClusterRegistry) fanOut(r *http.Request, body []byte, clusterNames []string) []*bufferedResponse {
+	responses := make([]*bufferedResponse, len(clusterNames))
+	semaphore := make(chan struct{}, groupFanOutConcurrency)
+	var wg sync.WaitGroup
+	for i, name := range clusterNames {
+		cluster, exists := cr.GetCluster(name)
+		if !exists {
+			// The cluster was removed since the names were evaluated
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			memberReq := r.Clone(r.Context())
+			memberReq.Body = io.NopCloser(bytes.NewReader(body))
+			memberReq.ContentLength = int64(len(body))
+			memberReq = withPath(memberReq, fmt.Sprintf("/%s/%s", name, cr.appCfg.Url.GraphqlSuffix))
+
+			response := newBufferedResponse()
+			cr.serveOperation(response, memberReq, name, cluster)
+			responses[i] = response
+		}()
+	}
+	wg.Wait()
+
+	return responses
+}
+
+// checkGroupQuery rejects operations that are not queries, group endpoints don't change the member clusters
+func checkGroupQuery(query string) error {
+	if query == "" {
+		return errors.New("cluster groups require the query document, persisted queries are not supported")
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return err
+	}
+
+	for _, definition := range doc.Definitions {
+		if operation, ok := definition.(*ast.OperationDefinition); ok && operation.Operation != ast.OperationTypeQuery {
+			return fmt.Errorf("%s operations are not supported by cluster groups", operation.Operation)
+		}
+	}
+
+	return nil
+}
+
+// groupResult is the response of a cluster group endpoint and of the aggregated endpoint
+type groupResult struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []map[string]interface{}   `json:"errors,omitempty"`
+}
+
+// aggregateGroupResponses merges the responses of the members into one result. Responses that are not GraphQL
+// results, e.g. rejected authentication, are reported as an error of the cluster with its HTTP status.
+func aggregateGroupResponses(members []string, responses []*bufferedResponse) groupResult {
+	result := groupResult{Data: make(map[string]json.RawMessage, len(members))}
+
+	for i, name := range members {
+		response := responses[i]
+		if response == nil {
+			continue
+		}
+
+		var memberResult struct {
+			Data   json.RawMessage          `json:"data"`
+			Errors []map[string]interface{} `json:"errors"`
+		}
+		if err := json.Unmarshal(response.body.Bytes(), &memberResult); err != nil {
+			result.Data[name] = json.RawMessage("null")
+			result.Errors = append(result.Errors, clusterResponseError(name, response))
+			continue
+		}
+
+		result.Data[name] = memberResult.Data
+		if len(memberResult.Data) == 0 {
+			result.Data[name] = json.RawMessage("null")
+		}
+
+		result.Errors = append(result.Errors, clusterErrors(name, response.statusCode, memberResult.Errors, name)...)
+	}
+
+	return result
+}
+
+// clusterResponseError reports a response of a cluster that is not a GraphQL result with its HTTP status
+func clusterResponseError(name string, response *bufferedResponse) map[string]interface{} {
+	return map[string]interface{}{
+		"message": strings.TrimSpace(response.body.String()),
+		"extensions": map[string]interface{}{
+			"cluster": name,
+			"status":  response.statusCode,
+		},
+	}
+}
+
+// clusterErrors adds the cluster to the extensions of the errors of its result and prepends pathPrefix to their path
+func clusterErrors(name string, statusCode int, errs []map[string]interface{}, pathPrefix ...interface{}) []map[string]interface{} {
+	for _, clusterErr := range errs {
+		extensions, _ := clusterErr["extensions"].(map[string]interface{})
+		if extensions == nil {
+			extensions = make(map[string]interface{})
+		}
+		extensions["cluster"] = name
+		if statusCode != http.StatusOK {
+			extensions["status"] = statusCode
+		}
+		clusterErr["extensions"] = extensions
+
+		if path, ok := clusterErr["path"].([]interface{}); ok {
+			clusterErr["path"] = append(append([]interface{}{}, pathPrefix...), path...)
+		}
+	}
+
+	return errs
+}
+
+// writeGroupError writes a GraphQL error response for an operation that is not sent to the members
+func writeGroupError(w http.ResponseWriter, statusCode int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []gqlerrors.FormattedError{{Message: message}},
+	})
+}
+
+// bufferedResponse collects the response of a member cluster, so that it can be merged into the group result
+type bufferedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func newBufferedResponse() *bufferedResponse {
+	return &bufferedResponse{header: make(http.Header), statusCode: http.StatusOK}
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	b.statusCode = statusCode
+}
@@ -0,0 +1,200 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestLoadClusterGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			content: `
+groups:
+  - name: prod
+    selector: tier=prod
+  - name: eu-prod
+    selector: tier=prod,region in (eu-west,eu-central)
+`,
+			expected: []string{"prod", "eu-prod"},
+		},
+		{
+			name:        "invalid_name",
+			content:     `groups: [{name: Prod, selector: tier=prod}]`,
+			expectedErr: ErrInvalidClusterGroup,
+		},
+		{
+			name:        "duplicate_name",
+			content:     `groups: [{name: prod, selector: tier=prod}, {name: prod, selector: tier=production}]`,
+			expectedErr: ErrDuplicateClusterGroup,
+		},
+		{
+			name:        "missing_selector",
+			content:     `groups: [{name: prod}]`,
+			expectedErr: ErrInvalidClusterGroup,
+		},
+		{
+			name:        "invalid_selector",
+			content:     `groups: [{name: prod, selector: "tier in prod"}]`,
+			expectedErr: ErrInvalidClusterGroup,
+		},
+		{
+			name:        "unparsable",
+			content:     `groups: {name: prod}`,
+			expectedErr: ErrParseClusterGroups,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "groups.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0644))
+
+			groups, err := LoadClusterGroups(path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(groups))
+			for _, group := range groups {
+				names = append(names, group.Name)
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("empty_path", func(t *testing.T) {
+		groups, err := LoadClusterGroups("")
+		require.NoError(t, err)
+		assert.Empty(t, groups)
+	})
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := LoadClusterGroups(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, ErrReadClusterGroups)
+	})
+}
+
+func TestClusterRegistry_ServeGroup(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+
+	groupsPath := filepath.Join(t.TempDir(), "groups.yaml")
+	require.NoError(t, os.WriteFile(groupsPath, []byte(`groups: [{name: prod, selector: tier=prod}, {name: empty, selector: tier=staging}]`), 0644))
+	groups, err := LoadClusterGroups(groupsPath)
+	require.NoError(t, err)
+
+	newCluster := func(name string, labels map[string]string, response string) *TargetCluster {
+		return &TargetCluster{
+			appCfg: appCfg,
+			name:   name,
+			labels: labels,
+			handler: &GraphQLHandler{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/"+name+"/graphql", r.URL.Path)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(response))
+			})},
+		}
+	}
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil).WithClusterGroups(groups)
+	registry.clusters["eu"] = newCluster("eu", map[string]string{"tier": "prod", "region": "eu"},
+		`{"data": {"v1": {"ConfigMaps": {"items": [{"metadata": {"name": "a"}}]}}}}`)
+	registry.clusters["us"] = newCluster("us", map[string]string{"tier": "prod", "region": "us"},
+		`{"data": {"v1": null}, "errors": [{"message": "forbidden", "path": ["v1", "ConfigMaps"]}]}`)
+	registry.clusters["dev"] = newCluster("dev", map[string]string{"tier": "dev"}, `{"data": {}}`)
+	registry.clusters["root"] = newCluster("root", nil, `{"data": {}}`)
+
+	members, ok := registry.GroupMembers("prod")
+	require.True(t, ok)
+	assert.Equal(t, []string{"eu", "us"}, members)
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("aggregates_members", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/groups/prod/graphql", `{"query": "{ v1 { ConfigMaps { items { metadata { name } } } } }"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var result struct {
+			Data   map[string]json.RawMessage `json:"data"`
+			Errors []struct {
+				Message    string                 `json:"message"`
+				Path       []interface{}          `json:"path"`
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+
+		assert.JSONEq(t, `{"v1": {"ConfigMaps": {"items": [{"metadata": {"name": "a"}}]}}}`, string(result.Data["eu"]))
+		assert.JSONEq(t, `{"v1": null}`, string(result.Data["us"]))
+		assert.NotContains(t, result.Data, "dev")
+		assert.NotContains(t, result.Data, "root")
+
+		require.Len(t, result.Errors, 1)
+		assert.Equal(t, "forbidden", result.Errors[0].Message)
+		assert.Equal(t, []interface{}{"us", "v1", "ConfigMaps"}, result.Errors[0].Path)
+		assert.Equal(t, "us", result.Errors[0].Extensions["cluster"])
+	})
+
+	t.Run("empty_group", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/groups/empty/graphql", `{"query": "{ v1 { ConfigMaps { items { metadata { name } } } } }"}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"data": {}}`, rec.Body.String())
+	})
+
+	t.Run("unknown_group", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/groups/staging/graphql", `{"query": "{ v1 { ConfigMaps { items { metadata { name } } } } }"}`)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("rejects_mutations", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/groups/prod/graphql", `{"query": "mutation { v1 { deleteConfigMap(name: \"a\", namespace: \"default\") } }"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "mutation operations are not supported")
+	})
+
+	t.Run("rejects_persisted_queries", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/groups/prod/graphql", `{"extensions": {"persistedQuery": {"sha256Hash": "abc"}}}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("rejects_get", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/groups/prod/graphql", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestAggregateGroupResponses_NonGraphQLResponse(t *testing.T) {
+	response := newBufferedResponse()
+	http.Error(response, "Invalid token", http.StatusUnauthorized)
+
+	result := aggregateGroupResponses([]string{"eu"}, []*bufferedResponse{response})
+
+	assert.JSONEq(t, `null`, string(result.Data["eu"]))
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "Invalid token", result.Errors[0]["message"])
+	assert.Equal(t, map[string]interface{}{"cluster": "eu", "status": http.StatusUnauthorized}, result.Errors[0]["extensions"])
+}
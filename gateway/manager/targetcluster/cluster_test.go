@@ -5,9 +5,11 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/rest"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
 )
 
@@ -291,6 +293,33 @@ users:
 				assert.Empty(t, config.BearerToken)
 			},
 		},
+		{
+			name: "with_exec_auth",
+			metadata: &targetcluster.ClusterMetadata{
+				Host: "https://k8s.example.com",
+				Auth: &targetcluster.AuthMetadata{
+					Type: "exec",
+					Exec: &gatewayv1alpha1.ExecConfig{Command: "gke-gcloud-auth-plugin"},
+				},
+			},
+			expectError: false,
+			validateConfig: func(t *testing.T, config *rest.Config) {
+				require.NotNil(t, config.ExecProvider)
+				assert.Equal(t, "gke-gcloud-auth-plugin", config.ExecProvider.Command)
+			},
+		},
+		{
+			name: "with_exec_auth_not_allowed",
+			metadata: &targetcluster.ClusterMetadata{
+				Host: "https://k8s.example.com",
+				Auth: &targetcluster.AuthMetadata{
+					Type: "exec",
+					Exec: &gatewayv1alpha1.ExecConfig{Command: "sh", Args: []string{"-c", "id"}},
+				},
+			},
+			expectError:   true,
+			errorContains: "exec command not allowed",
+		},
 		{
 			name: "with_ca_and_token_auth",
 			metadata: &targetcluster.ClusterMetadata{
@@ -362,6 +391,8 @@ func TestTargetCluster_GetEndpoint(t *testing.T) {
 		clusterName    string
 		localDev       bool
 		gatewayPort    string
+		basePath       string
+		externalURL    string
 		expectedResult string
 	}{
 		{
@@ -455,6 +486,31 @@ func TestTargetCluster_GetEndpoint(t *testing.T) {
 			gatewayPort:    "",
 			expectedResult: "http://localhost:/test/graphql",
 		},
+		{
+			name:           "base_path_local_dev",
+			clusterName:    "production",
+			localDev:       true,
+			gatewayPort:    "8080",
+			basePath:       "/api/graphql-gateway",
+			expectedResult: "http://localhost:8080/api/graphql-gateway/production/graphql",
+		},
+		{
+			name:           "base_path_non_local_dev",
+			clusterName:    "production",
+			localDev:       false,
+			gatewayPort:    "8080",
+			basePath:       "/api/graphql-gateway",
+			expectedResult: "/api/graphql-gateway/production/graphql",
+		},
+		{
+			name:           "external_url",
+			clusterName:    "production",
+			localDev:       true,
+			gatewayPort:    "8080",
+			basePath:       "/api/graphql-gateway",
+			externalURL:    "https://portal.example.com/api/graphql-gateway",
+			expectedResult: "https://portal.example.com/api/graphql-gateway/production/graphql",
+		},
 	}
 
 	for _, tt := range tests {
@@ -464,6 +520,8 @@ func TestTargetCluster_GetEndpoint(t *testing.T) {
 
 			// Create app config
 			appCfg := targetcluster.CreateTestConfig(tt.localDev, tt.gatewayPort)
+			appCfg.Url.BasePath = tt.basePath
+			appCfg.Url.ExternalURL = tt.externalURL
 
 			// Test GetEndpoint
 			result := tc.GetEndpoint(appCfg)
@@ -0,0 +1,108 @@
+package targetcluster
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+// corsPolicy answers the CORS requests of browsers. It applies to queries, mutations and subscriptions over Server-Sent
+// Events, and the origin of WebSocket upgrades is checked against the allowed origins, as browsers don't apply CORS
+// to WebSockets.
+type corsPolicy struct {
+	allowAllOrigins bool
+	origins         []string
+	headers         string
+	methods         string
+	credentials     bool
+	maxAge          string
+}
+
+// newCORSPolicy returns the CORS policy of the configuration, nil if CORS is disabled
+func newCORSPolicy(appCfg appConfig.Config) *corsPolicy {
+	cfg := appCfg.Gateway.Cors
+	if !cfg.Enabled {
+		return nil
+	}
+
+	policy := &corsPolicy{
+		headers:     strings.Join(splitList(cfg.AllowedHeaders), ", "),
+		methods:     strings.Join(splitList(cfg.AllowedMethods), ", "),
+		credentials: cfg.Credentials,
+	}
+	for _, origin := range splitList(cfg.AllowedOrigins) {
+		if origin == "*" {
+			policy.allowAllOrigins = true
+			continue
+		}
+		policy.origins = append(policy.origins, strings.TrimSuffix(origin, "/"))
+	}
+	if cfg.MaxAge > 0 {
+		policy.maxAge = strconv.Itoa(cfg.MaxAge)
+	}
+
+	return policy
+}
+
+// allowsOrigin reports whether requests of the origin are allowed
+func (p *corsPolicy) allowsOrigin(origin string) bool {
+	return p.allowAllOrigins || slices.Contains(p.origins, origin)
+}
+
+// handle sets the CORS headers of the request and answers preflight requests, true means the request was answered
+func (p *corsPolicy) handle(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+	// The allowed origin depends on the request unless all origins are allowed without credentials
+	if !p.allowAllOrigins || p.credentials {
+		w.Header().Add("Vary", "Origin")
+	}
+
+	if origin != "" && p.allowsOrigin(origin) {
+		// Browsers reject the wildcard for requests with credentials, so the origin is echoed instead
+		if p.allowAllOrigins && !p.credentials {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if p.credentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			w.Header().Set("Access-Control-Allow-Methods", p.methods)
+			headers := p.headers
+			if headers == "*" && p.credentials {
+				headers = r.Header.Get("Access-Control-Request-Headers")
+			}
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			}
+			if p.maxAge != "" {
+				w.Header().Set("Access-Control-Max-Age", p.maxAge)
+			}
+		}
+	}
+
+	// Preflight requests of disallowed origins are answered as well, browsers reject them without the CORS headers
+	if r.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+		return true
+	}
+	return false
+}
+
+// splitList splits a comma separated configuration value, empty entries are dropped
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,119 @@
+package targetcluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	corsConfig := func(origins, headers string, credentials bool) appConfig.Config {
+		appCfg := appConfig.Config{}
+		appCfg.Gateway.Cors.Enabled = true
+		appCfg.Gateway.Cors.AllowedOrigins = origins
+		appCfg.Gateway.Cors.AllowedHeaders = headers
+		appCfg.Gateway.Cors.AllowedMethods = "GET,POST,OPTIONS"
+		appCfg.Gateway.Cors.Credentials = credentials
+		appCfg.Gateway.Cors.MaxAge = 600
+		return appCfg
+	}
+
+	tests := []struct {
+		name            string
+		appCfg          appConfig.Config
+		method          string
+		origin          string
+		expectedHandled bool
+		expectedStatus  int
+		expectedHeaders map[string]string
+	}{
+		{
+			name:            "wildcard_origin",
+			appCfg:          corsConfig("*", "*", false),
+			method:          http.MethodPost,
+			origin:          "https://app.example.com",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "*", "Vary": ""},
+		},
+		{
+			name:            "wildcard_origin_with_credentials",
+			appCfg:          corsConfig("*", "*", true),
+			method:          http.MethodPost,
+			origin:          "https://app.example.com",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://app.example.com", "Access-Control-Allow-Credentials": "true", "Vary": "Origin"},
+		},
+		{
+			name:            "listed_origin",
+			appCfg:          corsConfig("https://app.example.com, https://admin.example.com/", "*", false),
+			method:          http.MethodPost,
+			origin:          "https://admin.example.com",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "https://admin.example.com", "Vary": "Origin"},
+		},
+		{
+			name:            "unlisted_origin",
+			appCfg:          corsConfig("https://app.example.com", "*", false),
+			method:          http.MethodPost,
+			origin:          "https://evil.example.com",
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Vary": "Origin"},
+		},
+		{
+			name:            "preflight",
+			appCfg:          corsConfig("https://app.example.com", "Authorization,Content-Type", false),
+			method:          http.MethodOptions,
+			origin:          "https://app.example.com",
+			expectedHandled: true,
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Origin":  "https://app.example.com",
+				"Access-Control-Allow-Methods": "GET, POST, OPTIONS",
+				"Access-Control-Allow-Headers": "Authorization, Content-Type",
+				"Access-Control-Max-Age":       "600",
+			},
+		},
+		{
+			name:            "preflight_with_credentials_echoes_requested_headers",
+			appCfg:          corsConfig("https://app.example.com", "*", true),
+			method:          http.MethodOptions,
+			origin:          "https://app.example.com",
+			expectedHandled: true,
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Headers": "authorization,x-schema-profile"},
+		},
+		{
+			name:            "preflight_of_unlisted_origin",
+			appCfg:          corsConfig("https://app.example.com", "*", false),
+			method:          http.MethodOptions,
+			origin:          "https://evil.example.com",
+			expectedHandled: true,
+			expectedStatus:  http.StatusNoContent,
+			expectedHeaders: map[string]string{"Access-Control-Allow-Origin": "", "Access-Control-Allow-Methods": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/cluster/graphql", nil)
+			req.Header.Set("Origin", tt.origin)
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "authorization,x-schema-profile")
+			}
+			rec := httptest.NewRecorder()
+
+			handled := newCORSPolicy(tt.appCfg).handle(rec, req)
+
+			assert.Equal(t, tt.expectedHandled, handled)
+			if tt.expectedHandled {
+				assert.Equal(t, tt.expectedStatus, rec.Code)
+			}
+			for header, expected := range tt.expectedHeaders {
+				assert.Equal(t, expected, rec.Header().Get(header), header)
+			}
+		})
+	}
+
+	assert.Nil(t, newCORSPolicy(appConfig.Config{}))
+}
@@ -0,0 +1,121 @@
+package targetcluster
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+)
+
+// wsCloseGoingAway is the close code of WebSocket connections closed because the gateway shuts down
+const wsCloseGoingAway = 1001
+
+var ErrDrainTimeout = errors.New("subscriptions still open after the drain timeout")
+
+// drainer tracks the subscriptions served over Server-Sent Events and WebSockets. The HTTP server doesn't wait for
+// WebSocket connections, and open subscriptions would hold up its shutdown until the drain timeout, so they are ended
+// by cancelling their context when the gateway drains.
+type drainer struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	draining bool
+	streams  int
+	// idle is closed once the gateway drains and no subscription is open anymore
+	idle chan struct{}
+}
+
+func newDrainer() *drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &drainer{ctx: ctx, cancel: cancel, idle: make(chan struct{})}
+}
+
+// track registers a subscription, it returns false if the gateway drains already. done must be called once the
+// subscription ended.
+func (d *drainer) track() (done func(), ok bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return nil, false
+	}
+	d.streams++
+
+	return sync.OnceFunc(func() {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		d.streams--
+		if d.draining && d.streams == 0 {
+			close(d.idle)
+		}
+	}), true
+}
+
+// isDraining reports whether the gateway shuts down
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// drain ends the open subscriptions and waits until they sent their complete events or ctx is done
+func (d *drainer) drain(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	if !d.draining {
+		d.draining = true
+		if d.streams == 0 {
+			close(d.idle)
+		}
+	}
+	open := d.streams
+	d.mu.Unlock()
+
+	d.cancel()
+
+	select {
+	case <-d.idle:
+		return open, nil
+	case <-ctx.Done():
+		return open, errors.Join(ErrDrainTimeout, ctx.Err())
+	}
+}
+
+// Drain stops the registry from accepting operations and ends the subscriptions with a complete event, it returns once
+// they are ended or ctx is done. Operations arriving while the gateway drains are rejected with 503, so that the
+// clients retry against another replica.
+func (cr *ClusterRegistry) Drain(ctx context.Context) error {
+	open, err := cr.drainer.drain(ctx)
+	cr.log.Info().Int("subscriptions", open).Msg("Drained cluster registry")
+	return err
+}
+
+// Draining reports whether the registry drains, the gateway is then not ready to serve operations anymore
+func (cr *ClusterRegistry) Draining() bool {
+	return cr.drainer.isDraining()
+}
+
+// rejectDraining answers requests arriving while the gateway drains with 503, it returns true if the request was
+// rejected
+func (cr *ClusterRegistry) rejectDraining(w http.ResponseWriter) bool {
+	if !cr.drainer.isDraining() {
+		return false
+	}
+
+	// The client shouldn't reuse the connection of a gateway that shuts down
+	w.Header().Set("Connection", "close")
+	w.Header().Set("Retry-After", "1")
+	http.Error(w, "The gateway is shutting down, retry later", http.StatusServiceUnavailable)
+	return true
+}
+
+// trackSubscription registers a subscription served over Server-Sent Events, whose request context is additionally
+// cancelled when the gateway drains. It returns false if the gateway drains already.
+func (cr *ClusterRegistry) trackSubscription(r *http.Request) (*http.Request, func(), bool) {
+	done, ok := cr.drainer.track()
+	if !ok {
+		return r, nil, false
+	}
+	return r.WithContext(withParentCancel(r.Context(), cr.drainer.ctx)), done, true
+}
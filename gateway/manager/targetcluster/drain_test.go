@@ -0,0 +1,94 @@
+package targetcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestClusterRegistry_Drain(t *testing.T) {
+	registry := newWebSocketTestRegistry(t, appConfig.Config{LocalDevelopment: true})
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+
+	conn, err := dialWebSocket(t, server, GraphQLTransportWSProtocol)
+	require.NoError(t, err)
+	sendMessage(t, conn, `{"type": "connection_init"}`)
+	assert.Equal(t, map[string]any{"type": "connection_ack"}, receiveMessage(t, conn))
+
+	sendMessage(t, conn, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { events }"}}`)
+	// The ping is answered after the subscription was started
+	sendMessage(t, conn, `{"type": "ping"}`)
+	assert.Equal(t, map[string]any{"type": "pong"}, receiveMessage(t, conn))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	drained := make(chan error, 1)
+	go func() { drained <- registry.Drain(ctx) }()
+
+	assert.Equal(t, map[string]any{"id": "1", "type": "complete"}, receiveMessage(t, conn))
+	var msg json.RawMessage
+	assert.Error(t, websocket.JSON.Receive(conn, &msg), "connection should be closed")
+	require.NoError(t, <-drained)
+	assert.True(t, registry.Draining())
+
+	t.Run("new_operations_rejected", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/test-cluster/graphql", "application/json", nil)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, "1", resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("new_connections_rejected", func(t *testing.T) {
+		conn, err := dialWebSocket(t, server, GraphQLTransportWSProtocol)
+		if err != nil {
+			return
+		}
+		assert.Error(t, websocket.JSON.Receive(conn, &msg), "connection should be closed")
+	})
+}
+
+func TestDrainer(t *testing.T) {
+	t.Run("without_streams", func(t *testing.T) {
+		d := newDrainer()
+		open, err := d.drain(context.Background())
+		require.NoError(t, err)
+		assert.Zero(t, open)
+
+		_, ok := d.track()
+		assert.False(t, ok)
+	})
+
+	t.Run("waits_for_streams", func(t *testing.T) {
+		d := newDrainer()
+		done, ok := d.track()
+		require.True(t, ok)
+		context.AfterFunc(d.ctx, done)
+
+		open, err := d.drain(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, open)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		d := newDrainer()
+		_, ok := d.track()
+		require.True(t, ok)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := d.drain(ctx)
+		assert.ErrorIs(t, err, ErrDrainTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -12,6 +12,11 @@ func BuildConfigFromMetadata(metadata *ClusterMetadata, log *logger.Logger) (*re
 	return buildConfigFromMetadata(metadata, log)
 }
 
+// NewOperationLabels exposes the bounded metric labels of operation names for testing
+func NewOperationLabels(maxNames int) func(operationName string) string {
+	return newOperationLabels(maxNames).label
+}
+
 // NewTestTargetCluster creates a TargetCluster with the specified name for testing
 func NewTestTargetCluster(name string) *TargetCluster {
 	return &TargetCluster{
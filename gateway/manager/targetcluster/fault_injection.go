@@ -0,0 +1,184 @@
+//go:build faultinjection
+
+package targetcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// allClusters is the cluster name of the faults injected into every cluster without faults of its own
+const allClusters = "*"
+
+// FaultConfig describes the faults injected into the API server requests of a cluster
+type FaultConfig struct {
+	// Latency is added to every request
+	Latency metav1.Duration `json:"latency,omitempty"`
+	// ErrorPercent is the percentage of requests, between 0 and 100, answered with StatusCode instead of being sent
+	ErrorPercent int `json:"errorPercent,omitempty"`
+	// StatusCode of the failed requests, 503 if not set
+	StatusCode int `json:"statusCode,omitempty"`
+	// WatchDropAfter closes watches after this duration, as if the connection to the API server was lost
+	WatchDropAfter metav1.Duration `json:"watchDropAfter,omitempty"`
+}
+
+// faultInjector stores the faults per cluster, they are changed at runtime through FaultInjectionHandler
+type faultInjector struct {
+	mu     sync.RWMutex
+	faults map[string]FaultConfig
+}
+
+var faults = &faultInjector{faults: make(map[string]FaultConfig)}
+
+func (f *faultInjector) get(clusterName string) (FaultConfig, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if config, ok := f.faults[clusterName]; ok {
+		return config, true
+	}
+	config, ok := f.faults[allClusters]
+	return config, ok
+}
+
+// FaultInjectionHandler controls the faults injected into the API server requests of the clusters:
+//
+//	GET                     returns the faults per cluster
+//	PUT ?cluster=<name>     sets the faults of a cluster from the FaultConfig in the body, * applies to all clusters
+//	DELETE ?cluster=<name>  removes the faults of a cluster, all faults are removed without cluster
+//
+// It is only available in builds with the faultinjection tag, which must never be deployed to production.
+func FaultInjectionHandler() http.Handler {
+	return http.HandlerFunc(faults.serveHTTP)
+}
+
+func (f *faultInjector) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	clusterName := r.URL.Query().Get("cluster")
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		if clusterName == "" {
+			http.Error(w, "cluster is required", http.StatusBadRequest)
+			return
+		}
+
+		var config FaultConfig
+		if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+			http.Error(w, "Invalid fault config: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if config.ErrorPercent < 0 || config.ErrorPercent > 100 {
+			http.Error(w, "errorPercent must be between 0 and 100", http.StatusBadRequest)
+			return
+		}
+
+		f.mu.Lock()
+		f.faults[clusterName] = config
+		f.mu.Unlock()
+	case http.MethodDelete:
+		f.mu.Lock()
+		if clusterName == "" {
+			f.faults = make(map[string]FaultConfig)
+		} else {
+			delete(f.faults, clusterName)
+		}
+		f.mu.Unlock()
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(f.faults)
+}
+
+// faultInjectionRoundTripper injects the faults configured for its cluster into the requests to the API server
+type faultInjectionRoundTripper struct {
+	next        http.RoundTripper
+	clusterName string
+}
+
+func newFaultInjectionRoundTripper(next http.RoundTripper, clusterName string) http.RoundTripper {
+	return &faultInjectionRoundTripper{
+		next:        next,
+		clusterName: clusterName,
+	}
+}
+
+func (rt *faultInjectionRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	config, ok := faults.get(rt.clusterName)
+	if !ok {
+		return rt.next.RoundTrip(req)
+	}
+
+	if config.Latency.Duration > 0 {
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(config.Latency.Duration):
+		}
+	}
+
+	if config.ErrorPercent > 0 && rand.IntN(100) < config.ErrorPercent {
+		return injectedErrorResponse(req, config.StatusCode), nil
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil || config.WatchDropAfter.Duration <= 0 || req.URL.Query().Get("watch") != "true" {
+		return resp, err
+	}
+
+	body := resp.Body
+	timer := time.AfterFunc(config.WatchDropAfter.Duration, func() { body.Close() })
+	resp.Body = &droppingBody{ReadCloser: body, timer: timer}
+	return resp, nil
+}
+
+// droppingBody is the body of a watch that is closed by its timer
+type droppingBody struct {
+	io.ReadCloser
+	timer *time.Timer
+}
+
+func (b *droppingBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
+
+// injectedErrorResponse is a failed response of the API server, with a Status body client-go can decode
+func injectedErrorResponse(req *http.Request, statusCode int) *http.Response {
+	if statusCode == 0 {
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	status, _ := json.Marshal(metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  "fault injected by the gateway",
+		Code:     int32(statusCode),
+	})
+
+	return &http.Response{
+		StatusCode:    statusCode,
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(status)),
+		ContentLength: int64(len(status)),
+		Request:       req,
+	}
+}
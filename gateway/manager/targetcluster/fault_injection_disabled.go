@@ -0,0 +1,15 @@
+//go:build !faultinjection
+
+package targetcluster
+
+import "net/http"
+
+// newFaultInjectionRoundTripper only injects faults in builds with the faultinjection tag, see fault_injection.go
+func newFaultInjectionRoundTripper(next http.RoundTripper, _ string) http.RoundTripper {
+	return next
+}
+
+// FaultInjectionHandler is nil in builds without the faultinjection tag
+func FaultInjectionHandler() http.Handler {
+	return nil
+}
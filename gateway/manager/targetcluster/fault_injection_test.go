@@ -0,0 +1,116 @@
+//go:build faultinjection
+
+package targetcluster
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setFaults(t *testing.T, cluster, config string) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	FaultInjectionHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/faultz?cluster="+cluster, strings.NewReader(config)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	t.Cleanup(func() {
+		FaultInjectionHandler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, "/faultz", nil))
+	})
+}
+
+func TestFaultInjectionRoundTripper(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("watch") == "true" {
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: newFaultInjectionRoundTripper(http.DefaultTransport, "faults-test")}
+
+	t.Run("no_faults", func(t *testing.T) {
+		resp, err := client.Get(upstream.URL + "/api/v1/pods")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		setFaults(t, "faults-test", `{"errorPercent": 100, "statusCode": 429}`)
+
+		resp, err := client.Get(upstream.URL + "/api/v1/pods")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"kind":"Status"`)
+	})
+
+	t.Run("latency_of_all_clusters", func(t *testing.T) {
+		setFaults(t, allClusters, `{"latency": "50ms"}`)
+
+		start := time.Now()
+		resp, err := client.Get(upstream.URL + "/api/v1/pods")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("watch_drop", func(t *testing.T) {
+		setFaults(t, "faults-test", `{"watchDropAfter": "50ms"}`)
+
+		resp, err := client.Get(upstream.URL + "/api/v1/pods?watch=true")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := io.ReadAll(resp.Body)
+			done <- err
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the watch was not dropped")
+		}
+	})
+}
+
+func TestFaultInjectionHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		body         string
+		expectedCode int
+	}{
+		{name: "get", method: http.MethodGet, target: "/faultz", expectedCode: http.StatusOK},
+		{name: "put_without_cluster", method: http.MethodPut, target: "/faultz", body: `{}`, expectedCode: http.StatusBadRequest},
+		{name: "put_invalid_percent", method: http.MethodPut, target: "/faultz?cluster=a", body: `{"errorPercent": 101}`, expectedCode: http.StatusBadRequest},
+		{name: "put_invalid_body", method: http.MethodPut, target: "/faultz?cluster=a", body: `{`, expectedCode: http.StatusBadRequest},
+		{name: "post", method: http.MethodPost, target: "/faultz", expectedCode: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			FaultInjectionHandler().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package targetcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/visitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var fieldUsageTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "graphql_gateway",
+	Name:      "field_usage_total",
+	Help:      "Number of sampled GraphQL operations selecting a field, per cluster, type and field",
+}, []string{"cluster", "type", "field"})
+
+// FieldUsage is the number of sampled operations that selected a field of a type
+type FieldUsage struct {
+	Cluster string `json:"cluster"`
+	Type    string `json:"type"`
+	Field   string `json:"field"`
+	Count   uint64 `json:"count"`
+}
+
+type fieldCoordinate struct {
+	typeName  string
+	fieldName string
+}
+
+// fieldUsageTracker aggregates the fields selected by a sample of the operations of every cluster
+type fieldUsageTracker struct {
+	mu         sync.Mutex
+	sampleRate float64
+	counts     map[string]map[fieldCoordinate]uint64
+}
+
+func newFieldUsageTracker(sampleRate float64) *fieldUsageTracker {
+	return &fieldUsageTracker{
+		sampleRate: sampleRate,
+		counts:     make(map[string]map[fieldCoordinate]uint64),
+	}
+}
+
+// sampled decides whether the fields of an operation are recorded
+func (t *fieldUsageTracker) sampled() bool {
+	return t.sampleRate > 0 && (t.sampleRate >= 1 || rand.Float64() < t.sampleRate)
+}
+
+// record counts every field selected by the query once
+func (t *fieldUsageTracker) record(cluster string, schema *graphql.Schema, query string) {
+	fields := collectFields(schema, query)
+	if len(fields) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts, ok := t.counts[cluster]
+	if !ok {
+		counts = make(map[fieldCoordinate]uint64)
+		t.counts[cluster] = counts
+	}
+
+	for field := range fields {
+		counts[field]++
+		fieldUsageTotal.WithLabelValues(cluster, field.typeName, field.fieldName).Inc()
+	}
+}
+
+// remove drops the statistics of a cluster, e.g. because its schema file was deleted
+func (t *fieldUsageTracker) remove(cluster string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.counts, cluster)
+}
+
+// usage returns the recorded statistics, sorted by cluster and by descending count
+func (t *fieldUsageTracker) usage() []FieldUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := make([]FieldUsage, 0)
+	for cluster, counts := range t.counts {
+		for field, count := range counts {
+			usage = append(usage, FieldUsage{
+				Cluster: cluster,
+				Type:    field.typeName,
+				Field:   field.fieldName,
+				Count:   count,
+			})
+		}
+	}
+
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Cluster != usage[j].Cluster {
+			return usage[i].Cluster < usage[j].Cluster
+		}
+		if usage[i].Count != usage[j].Count {
+			return usage[i].Count > usage[j].Count
+		}
+		if usage[i].Type != usage[j].Type {
+			return usage[i].Type < usage[j].Type
+		}
+		return usage[i].Field < usage[j].Field
+	})
+
+	return usage
+}
+
+// collectFields returns the fields of the schema selected by the query, introspection fields are skipped.
+// Queries that can't be parsed are ignored, the GraphQL handler reports them to the client.
+func collectFields(schema *graphql.Schema, query string) map[fieldCoordinate]struct{} {
+	if query == "" {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	fields := make(map[fieldCoordinate]struct{})
+	typeInfo := graphql.NewTypeInfo(&graphql.TypeInfoConfig{Schema: schema})
+	visitor.Visit(doc, visitor.VisitWithTypeInfo(typeInfo, &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.Field: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					parentType, fieldDef := typeInfo.ParentType(), typeInfo.FieldDef()
+					if parentType == nil || fieldDef == nil || isIntrospectionField(parentType.Name(), fieldDef) {
+						return visitor.ActionNoChange, nil
+					}
+
+					fields[fieldCoordinate{typeName: parentType.Name(), fieldName: fieldDef.Name}] = struct{}{}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}), nil)
+
+	return fields
+}
+
+func isIntrospectionField(typeName string, fieldDef *graphql.FieldDefinition) bool {
+	return strings.HasPrefix(typeName, "__") ||
+		fieldDef == graphql.SchemaMetaFieldDef ||
+		fieldDef == graphql.TypeMetaFieldDef ||
+		fieldDef == graphql.TypeNameMetaFieldDef
+}
+
+// getQuery returns the query document sent with the request, it is empty for persisted queries
+func getQuery(r *http.Request) string {
+	if r.Method == http.MethodGet {
+		return r.URL.Query().Get("query")
+	}
+
+	if r.Body == nil {
+		return ""
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return ""
+	}
+
+	var params struct {
+		Query string `json:"query"`
+	}
+	_ = json.Unmarshal(bodyBytes, &params)
+
+	return params.Query
+}
+
+// recordFieldUsage records the fields selected by a sample of the requests to a cluster
+func (cr *ClusterRegistry) recordFieldUsage(clusterName string, cluster *TargetCluster, r *http.Request) {
+	if !cr.fieldUsage.sampled() {
+		return
+	}
+
+	handler, ok := cluster.handlerFor(GetSchemaProfile(r))
+	if !ok || handler == nil || handler.Schema == nil {
+		return
+	}
+
+	cr.fieldUsage.record(clusterName, handler.Schema, getQuery(r))
+}
+
+// FieldUsage returns how often the fields of every cluster were selected by the sampled operations
+func (cr *ClusterRegistry) FieldUsage() []FieldUsage {
+	return cr.fieldUsage.usage()
+}
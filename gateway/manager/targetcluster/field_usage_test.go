@@ -0,0 +1,75 @@
+package targetcluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFieldUsageTestSchema(t *testing.T) *graphql.Schema {
+	configMap := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ConfigMap",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+			"data": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"configMaps": &graphql.Field{Type: graphql.NewList(configMap)},
+				"configMap":  &graphql.Field{Type: configMap},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	return &schema
+}
+
+func TestFieldUsageTracker(t *testing.T) {
+	schema := newFieldUsageTestSchema(t)
+	tracker := newFieldUsageTracker(1)
+
+	tracker.record("cluster-a", schema, `{ configMaps { name data } }`)
+	tracker.record("cluster-a", schema, `query { configMaps { name name } __typename __schema { types { name } } }`)
+	tracker.record("cluster-a", schema, `{ configMaps {`)
+	tracker.record("cluster-b", schema, `{ configMap { data } }`)
+
+	assert.Equal(t, []FieldUsage{
+		{Cluster: "cluster-a", Type: "ConfigMap", Field: "name", Count: 2},
+		{Cluster: "cluster-a", Type: "Query", Field: "configMaps", Count: 2},
+		{Cluster: "cluster-a", Type: "ConfigMap", Field: "data", Count: 1},
+		{Cluster: "cluster-b", Type: "ConfigMap", Field: "data", Count: 1},
+		{Cluster: "cluster-b", Type: "Query", Field: "configMap", Count: 1},
+	}, tracker.usage())
+
+	tracker.remove("cluster-b")
+	assert.Len(t, tracker.usage(), 3)
+}
+
+func TestFieldUsageTracker_Sampled(t *testing.T) {
+	assert.False(t, newFieldUsageTracker(0).sampled())
+	assert.True(t, newFieldUsageTracker(1).sampled())
+	assert.Empty(t, newFieldUsageTracker(0).usage())
+}
+
+func TestGetQuery(t *testing.T) {
+	get := httptest.NewRequest(http.MethodGet, "/cluster/graphql?query=%7B+configMaps+%7B+name+%7D+%7D", nil)
+	assert.Equal(t, "{ configMaps { name } }", getQuery(get))
+
+	post := httptest.NewRequest(http.MethodPost, "/cluster/graphql", strings.NewReader(`{"query": "{ configMap { data } }"}`))
+	assert.Equal(t, "{ configMap { data } }", getQuery(post))
+	// The body must still be readable by the GraphQL handler
+	assert.Equal(t, "{ configMap { data } }", getQuery(post))
+
+	persisted := httptest.NewRequest(http.MethodPost, "/cluster/graphql", strings.NewReader(`{"extensions": {"persistedQuery": {"sha256Hash": "abc"}}}`))
+	assert.Empty(t, getQuery(persisted))
+}
@@ -0,0 +1,103 @@
+package targetcluster
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	formatProtobuf = "protobuf"
+	formatJSON     = "json"
+	formatOther    = "other"
+)
+
+var (
+	apiServerRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "graphql_gateway",
+		Name:      "apiserver_request_duration_seconds",
+		Help:      "Duration until the API server response headers are received per cluster and response format",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"cluster", "format"})
+
+	apiServerResponseSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "graphql_gateway",
+		Name:      "apiserver_response_size_bytes",
+		Help:      "Size of the API server response bodies per cluster and response format",
+		Buckets:   prometheus.ExponentialBuckets(512, 4, 8),
+	}, []string{"cluster", "format"})
+)
+
+// formatMetricsRoundTripper records the API server responses per serialization format,
+// so that the effect of enabling protobuf can be compared with JSON
+type formatMetricsRoundTripper struct {
+	next    http.RoundTripper
+	cluster string
+}
+
+func newFormatMetricsRoundTripper(next http.RoundTripper, cluster string) http.RoundTripper {
+	return &formatMetricsRoundTripper{
+		next:    next,
+		cluster: cluster,
+	}
+}
+
+func (rt *formatMetricsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+
+	format := responseFormat(resp.Header.Get("Content-Type"))
+	apiServerRequestDuration.WithLabelValues(rt.cluster, format).Observe(time.Since(start).Seconds())
+
+	// Watches keep the body open, their size is reported once the stream is closed
+	resp.Body = &countingBody{
+		ReadCloser: resp.Body,
+		observe: func(size int64) {
+			apiServerResponseSize.WithLabelValues(rt.cluster, format).Observe(float64(size))
+		},
+	}
+
+	return resp, nil
+}
+
+// responseFormat maps the Content-Type of a response to the format label
+func responseFormat(contentType string) string {
+	switch {
+	case strings.Contains(contentType, "protobuf"):
+		return formatProtobuf
+	case strings.Contains(contentType, "json"):
+		return formatJSON
+	default:
+		return formatOther
+	}
+}
+
+// countingBody counts the bytes read from the wrapped body and reports them once on Close
+type countingBody struct {
+	io.ReadCloser
+	size    int64
+	closed  bool
+	observe func(size int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	if !b.closed {
+		b.closed = true
+		b.observe(b.size)
+	}
+	return b.ReadCloser.Close()
+}
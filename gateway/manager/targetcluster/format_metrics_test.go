@@ -0,0 +1,52 @@
+package targetcluster
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestFormatMetricsRoundTripper(t *testing.T) {
+	rt := newFormatMetricsRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/vnd.kubernetes.protobuf"}},
+			Body:       io.NopCloser(strings.NewReader("0123456789")),
+		}, nil
+	}), "format-test-cluster")
+
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/api/v1/pods", nil)
+	require.NoError(t, err)
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	require.NoError(t, resp.Body.Close())
+
+	// The second Close must not report the response twice
+	metric := &dto.Metric{}
+	require.NoError(t, apiServerResponseSize.WithLabelValues("format-test-cluster", formatProtobuf).(prometheus.Histogram).Write(metric))
+	assert.Equal(t, uint64(1), metric.GetHistogram().GetSampleCount())
+	assert.Equal(t, float64(10), metric.GetHistogram().GetSampleSum())
+}
+
+func TestResponseFormat(t *testing.T) {
+	assert.Equal(t, formatProtobuf, responseFormat("application/vnd.kubernetes.protobuf"))
+	assert.Equal(t, formatJSON, responseFormat("application/json"))
+	assert.Equal(t, formatOther, responseFormat("text/plain"))
+}
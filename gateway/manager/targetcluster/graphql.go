@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 	"github.com/graphql-go/handler"
 	"github.com/kcp-dev/logicalcluster/v3"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
@@ -18,12 +19,21 @@ import (
 
 	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
 )
 
 // GraphQLHandler wraps a GraphQL schema and HTTP handler
 type GraphQLHandler struct {
 	Schema  *graphql.Schema
 	Handler http.Handler
+
+	coercer VariableCoercer
+}
+
+// VariableCoercer adjusts the variables of an operation in place before it is executed, see
+// resolver.Service.CoerceVariables
+type VariableCoercer interface {
+	CoerceVariables(schema *graphql.Schema, query, operationName string, variables map[string]any) error
 }
 
 // GraphQLServer provides utility methods for creating GraphQL handlers
@@ -54,6 +64,76 @@ func (s *GraphQLServer) CreateHandler(schema *graphql.Schema) *GraphQLHandler {
 	}
 }
 
+// WithVariableCoercion coerces the variables of the operations before the handler executes them
+func (h *GraphQLHandler) WithVariableCoercion(coercer VariableCoercer) *GraphQLHandler {
+	h.coercer = coercer
+	h.Handler = h.coerceVariables(h.Handler)
+	return h
+}
+
+// CoerceVariables coerces the variables of an operation in place, they are kept as they are without a coercer
+func (h *GraphQLHandler) CoerceVariables(query, operationName string, variables map[string]any) error {
+	if h.coercer == nil {
+		return nil
+	}
+	return h.coercer.CoerceVariables(h.Schema, query, operationName, variables)
+}
+
+// coerceVariables passes the requests on to next with their coerced variables. Operations whose variables don't match
+// their types are answered with a GraphQL error without being executed.
+func (h *GraphQLHandler) coerceVariables(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				http.Error(w, "Failed to read the request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		opts := handler.NewRequestOptions(r)
+		if r.Body != nil {
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		if len(opts.Variables) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if err := h.CoerceVariables(opts.Query, opts.OperationName, opts.Variables); err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			_ = json.NewEncoder(w).Encode(&graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+			return
+		}
+
+		r = r.Clone(r.Context())
+		if query := r.URL.Query(); query.Get("query") != "" {
+			variables, err := json.Marshal(opts.Variables)
+			if err != nil {
+				http.Error(w, "Failed to encode the variables", http.StatusInternalServerError)
+				return
+			}
+			query.Set("variables", string(variables))
+			r.URL.RawQuery = query.Encode()
+		} else {
+			// The handler reads the operation from the body, which is sent on as JSON whatever its original encoding
+			body, err := json.Marshal(opts)
+			if err != nil {
+				http.Error(w, "Failed to encode the variables", http.StatusInternalServerError)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r.ContentLength = int64(len(body))
+			r.Header.Set("Content-Type", handler.ContentTypeJSON)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // SetContexts sets the required contexts for KCP and authentication
 func SetContexts(r *http.Request, workspace, token string, enableKcp bool) *http.Request {
 	if enableKcp {
@@ -66,9 +146,20 @@ func SetContexts(r *http.Request, workspace, token string, enableKcp bool) *http
 		}
 		r = r.WithContext(kontext.WithCluster(r.Context(), logicalcluster.Name(kcpWorkspaceName)))
 	}
+	if IsValidationOnly(r) {
+		r = r.WithContext(resolver.WithValidationOnly(r.Context()))
+	}
 	return r.WithContext(context.WithValue(r.Context(), roundtripper.TokenKey{}, token))
 }
 
+// DryRunHeader set to All makes a request validation-only, like the dryRun parameter of the Kubernetes API
+const DryRunHeader = "X-Dry-Run"
+
+// IsValidationOnly returns whether the request asks to perform all its mutations in dry-run mode
+func IsValidationOnly(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get(DryRunHeader), "All")
+}
+
 // GetToken extracts the token from the request Authorization header
 func GetToken(r *http.Request) string {
 	token := r.Header.Get("Authorization")
@@ -77,6 +168,17 @@ func GetToken(r *http.Request) string {
 	return token
 }
 
+// SchemaProfileHeader selects the schema profile if the profile is not part of the request path
+const SchemaProfileHeader = "X-Schema-Profile"
+
+// GetSchemaProfile returns the schema profile requested via the path, falling back to the SchemaProfileHeader
+func GetSchemaProfile(r *http.Request) string {
+	if profile, ok := r.Context().Value(schemaProfileKey).(string); ok && profile != "" {
+		return profile
+	}
+	return r.Header.Get(SchemaProfileHeader)
+}
+
 // IsIntrospectionQuery checks if the request contains a GraphQL introspection query
 func IsIntrospectionQuery(r *http.Request) bool {
 	var params struct {
@@ -96,12 +198,17 @@ func IsIntrospectionQuery(r *http.Request) bool {
 	return false
 }
 
+// LastEventIDHeader is sent by reconnecting Server-Sent Events clients with the ID of the last event they received
+const LastEventIDHeader = "Last-Event-ID"
+
 // HandleSubscription handles GraphQL subscription requests using Server-Sent Events
 func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Request, schema *graphql.Schema) {
 	// Set SSE headers
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
+	// Tell clients where to reconnect to, which differs from the request path behind a shared ingress path
+	w.Header().Set("Content-Location", ExternalURL(r.URL.Path, s.AppCfg))
 
 	var params struct {
 		Query         string         `json:"query"`
@@ -117,12 +224,15 @@ func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Reques
 	flusher := http.NewResponseController(w)
 	r.Body.Close()
 
+	// Reconnecting clients send the ID of the last event they received, the subscription continues after it
+	cursor := resolver.NewSubscriptionCursor(r.Header.Get(LastEventIDHeader))
+
 	subscriptionParams := graphql.Params{
 		Schema:         *schema,
 		RequestString:  params.Query,
 		VariableValues: params.Variables,
 		OperationName:  params.OperationName,
-		Context:        r.Context(),
+		Context:        resolver.WithSubscriptionCursor(r.Context(), cursor),
 	}
 
 	subscriptionChannel := graphql.Subscribe(subscriptionParams)
@@ -131,13 +241,19 @@ func (s *GraphQLServer) HandleSubscription(w http.ResponseWriter, r *http.Reques
 			continue
 		}
 
+		id := cursor.Next()
+
 		data, err := json.Marshal(res)
 		if err != nil {
 			s.log.Error().Err(err).Msg("Error marshalling subscription response")
 			continue
 		}
 
-		fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+		if id != "" {
+			fmt.Fprintf(w, "event: next\nid: %s\ndata: %s\n\n", id, data)
+		} else {
+			fmt.Fprintf(w, "event: next\ndata: %s\n\n", data)
+		}
 		flusher.Flush()
 	}
 
@@ -3,14 +3,18 @@ package targetcluster_test
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/graphql-go/graphql"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"sigs.k8s.io/controller-runtime/pkg/kontext"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
@@ -18,6 +22,7 @@ import (
 	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
 )
 
 func TestGetToken(t *testing.T) {
@@ -159,6 +164,114 @@ func TestCreateHandler(t *testing.T) {
 	}
 }
 
+// coercerFunc adapts a function to a targetcluster.VariableCoercer
+type coercerFunc func(query, operationName string, variables map[string]any) error
+
+func (f coercerFunc) CoerceVariables(_ *graphql.Schema, query, operationName string, variables map[string]any) error {
+	return f(query, operationName, variables)
+}
+
+func TestWithVariableCoercion(t *testing.T) {
+	var executed bool
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"hello": &graphql.Field{Type: graphql.String}},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"scale": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{"replicas": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.Int)}},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						executed = true
+						return p.Args["replicas"], nil
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	// The coercer converts the strings given for the replicas, like resolver.Service.CoerceVariables in strict mode
+	coercer := coercerFunc(func(_, _ string, variables map[string]any) error {
+		switch replicas := variables["replicas"]; replicas {
+		case "three":
+			return fmt.Errorf("%w: $replicas expects integer, got %q", resolver.ErrInputTypeMismatch, replicas)
+		case "3":
+			variables["replicas"] = 3
+		}
+		return nil
+	})
+
+	const mutation = `mutation ($replicas: Int!) { scale(replicas: $replicas) }`
+
+	tests := []struct {
+		name             string
+		request          func() *http.Request
+		expectedBody     string
+		expectedExecuted bool
+	}{
+		{
+			name: "post_coerced",
+			request: func() *http.Request {
+				body := `{"query":"` + mutation + `","variables":{"replicas":"3"}}`
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			expectedBody:     `{"data":{"scale":3}}`,
+			expectedExecuted: true,
+		},
+		{
+			name: "form_post_coerced",
+			request: func() *http.Request {
+				form := url.Values{"query": {mutation}, "variables": {`{"replicas":"3"}`}}
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(form.Encode()))
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				return req
+			},
+			expectedBody:     `{"data":{"scale":3}}`,
+			expectedExecuted: true,
+		},
+		{
+			name: "get_coerced",
+			request: func() *http.Request {
+				query := url.Values{"query": {mutation}, "variables": {`{"replicas":"3"}`}}
+				return httptest.NewRequest(http.MethodGet, "/graphql?"+query.Encode(), nil)
+			},
+			expectedBody:     `{"data":{"scale":3}}`,
+			expectedExecuted: true,
+		},
+		{
+			name: "mismatch_is_a_graphql_error",
+			request: func() *http.Request {
+				body := `{"query":"` + mutation + `","variables":{"replicas":"three"}}`
+				req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			},
+			expectedBody: `{"data":null,"errors":[{"message":"input value does not match the schema type: $replicas expects integer, got \"three\"","locations":[]}]}`,
+		},
+	}
+
+	server := targetcluster.NewGraphQLServer(testlogger.New().HideLogOutput().Logger, appConfig.Config{})
+	handler := server.CreateHandler(&schema).WithVariableCoercion(coercer)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed = false
+			rec := httptest.NewRecorder()
+			handler.Handler.ServeHTTP(rec, tt.request())
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			assert.Equal(t, tt.expectedExecuted, executed)
+		})
+	}
+}
+
 func TestSetContexts(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -263,6 +376,7 @@ func TestHandleSubscription_ErrorCases(t *testing.T) {
 
 func TestHandleSubscription_Headers(t *testing.T) {
 	appCfg := appConfig.Config{}
+	appCfg.Url.BasePath = "/api/graphql-gateway"
 	server := targetcluster.NewGraphQLServer(testlogger.New().Logger, appCfg)
 
 	// Create a simple test schema
@@ -294,7 +408,7 @@ func TestHandleSubscription_Headers(t *testing.T) {
 		t.Fatalf("failed to create schema: %v", err)
 	}
 
-	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader([]byte(`{"query": "subscription { hello }"}`)))
+	req := httptest.NewRequest(http.MethodPost, "/test-cluster/graphql", bytes.NewReader([]byte(`{"query": "subscription { hello }"}`)))
 	req.Header.Set("Content-Type", "application/json")
 
 	// Use context with timeout to prevent hanging
@@ -319,6 +433,9 @@ func TestHandleSubscription_Headers(t *testing.T) {
 	if w.Header().Get("Connection") != "keep-alive" {
 		t.Errorf("expected Connection keep-alive, got %s", w.Header().Get("Connection"))
 	}
+	if w.Header().Get("Content-Location") != "/api/graphql-gateway/test-cluster/graphql" {
+		t.Errorf("expected Content-Location /api/graphql-gateway/test-cluster/graphql, got %s", w.Header().Get("Content-Location"))
+	}
 }
 
 func TestHandleSubscription_SubscriptionLoop(t *testing.T) {
@@ -374,3 +491,30 @@ func TestHandleSubscription_SubscriptionLoop(t *testing.T) {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
 }
+
+func TestSetContexts_ValidationOnly(t *testing.T) {
+	tests := []struct {
+		name                   string
+		header                 string
+		expectedValidationOnly bool
+	}{
+		{name: "all", header: "All", expectedValidationOnly: true},
+		{name: "case_insensitive", header: "all", expectedValidationOnly: true},
+		{name: "missing"},
+		{name: "unknown_value", header: "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(targetcluster.DryRunHeader, tt.header)
+			}
+
+			result := targetcluster.SetContexts(req, "test-workspace", "test-token", false)
+			if validationOnly := resolver.IsValidationOnly(result.Context()); validationOnly != tt.expectedValidationOnly {
+				t.Errorf("expected validation-only %t, got %t", tt.expectedValidationOnly, validationOnly)
+			}
+		})
+	}
+}
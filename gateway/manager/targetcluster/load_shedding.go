@@ -0,0 +1,197 @@
+package targetcluster
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/visitor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+const (
+	shedReasonIntrospection = "introspection"
+	shedReasonList          = "list"
+
+	// upstreamHealthWeight is the weight of the latest API server response in the moving averages
+	upstreamHealthWeight = 0.1
+	// upstreamHealthMinSamples avoids shedding based on the first few responses after a cluster was loaded
+	upstreamHealthMinSamples = 20
+)
+
+var shedOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "graphql_gateway",
+	Name:      "shed_operations_total",
+	Help:      "Number of low-priority GraphQL operations rejected because the API server of the cluster is overloaded",
+}, []string{"cluster", "reason"})
+
+// upstreamHealth tracks moving averages of the latency and the error rate of the API server of a cluster
+type upstreamHealth struct {
+	mu        sync.Mutex
+	samples   int
+	latency   time.Duration
+	errorRate float64
+}
+
+func (h *upstreamHealth) observe(latency time.Duration, failed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var failure float64
+	if failed {
+		failure = 1
+	}
+
+	if h.samples == 0 {
+		h.latency, h.errorRate = latency, failure
+	} else {
+		h.latency += time.Duration(upstreamHealthWeight * float64(latency-h.latency))
+		h.errorRate += upstreamHealthWeight * (failure - h.errorRate)
+	}
+	h.samples++
+}
+
+// overloaded reports whether the averages cross one of the configured thresholds, a zero threshold is disabled
+func (h *upstreamHealth) overloaded(latencyThreshold time.Duration, errorRatePercent int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.samples < upstreamHealthMinSamples {
+		return false
+	}
+
+	return (latencyThreshold > 0 && h.latency > latencyThreshold) ||
+		(errorRatePercent > 0 && h.errorRate*100 > float64(errorRatePercent))
+}
+
+// upstreamHealthRoundTripper feeds the responses of the API server into the upstreamHealth of the cluster.
+// Watches are skipped, since their duration says nothing about the load of the API server.
+type upstreamHealthRoundTripper struct {
+	next   http.RoundTripper
+	health *upstreamHealth
+}
+
+func newUpstreamHealthRoundTripper(next http.RoundTripper, health *upstreamHealth) http.RoundTripper {
+	return &upstreamHealthRoundTripper{
+		next:   next,
+		health: health,
+	}
+}
+
+func (rt *upstreamHealthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("watch") == "true" {
+		return rt.next.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+	failed := err != nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+	rt.health.observe(time.Since(start), failed)
+
+	return resp, err
+}
+
+// shedReason returns why the operation is rejected while the API server of the cluster is overloaded.
+// Only introspection and list queries are shed, gets and mutations keep flowing. Operations that can't be
+// classified, e.g. persisted queries sent without their query document, are never shed.
+func shedReason(r *http.Request, schema *graphql.Schema) (string, bool) {
+	if IsIntrospectionQuery(r) {
+		return shedReasonIntrospection, true
+	}
+
+	if schema != nil && selectsList(schema, getQuery(r)) {
+		return shedReasonList, true
+	}
+
+	return "", false
+}
+
+// selectsList reports whether a query operation selects a list of resources, i.e. a list field of a group query type
+func selectsList(schema *graphql.Schema, query string) bool {
+	if query == "" {
+		return false
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+
+	var found bool
+	typeInfo := graphql.NewTypeInfo(&graphql.TypeInfoConfig{Schema: schema})
+	visitor.Visit(doc, visitor.VisitWithTypeInfo(typeInfo, &visitor.VisitorOptions{
+		KindFuncMap: map[string]visitor.NamedVisitFuncs{
+			kinds.OperationDefinition: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					if op, ok := p.Node.(*ast.OperationDefinition); ok && op.Operation != ast.OperationTypeQuery {
+						return visitor.ActionSkip, nil
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+			kinds.Field: {
+				Kind: func(p visitor.VisitFuncParams) (string, interface{}) {
+					parentType, fieldDef := typeInfo.ParentType(), typeInfo.FieldDef()
+					if parentType == nil || fieldDef == nil || parentType == schema.QueryType() ||
+						!strings.HasSuffix(parentType.Name(), "Query") {
+						return visitor.ActionNoChange, nil
+					}
+
+					fieldType := fieldDef.Type
+					if nonNull, ok := fieldType.(*graphql.NonNull); ok {
+						fieldType = nonNull.OfType
+					}
+					if _, ok := fieldType.(*graphql.List); ok {
+						found = true
+						return visitor.ActionBreak, nil
+					}
+					return visitor.ActionNoChange, nil
+				},
+			},
+		},
+	}), nil)
+
+	return found
+}
+
+// shedLoad rejects low-priority operations with 503 while the API server of the cluster is overloaded
+func (cr *ClusterRegistry) shedLoad(w http.ResponseWriter, r *http.Request, clusterName string, cluster *TargetCluster) bool {
+	cfg := cr.appCfg.Gateway.LoadShedding
+	if cluster.upstream == nil || !cluster.upstream.overloaded(cfg.LatencyThreshold, cfg.ErrorRatePercent) {
+		return false
+	}
+
+	handler, ok := cluster.handlerFor(GetSchemaProfile(r))
+	if !ok || handler == nil {
+		return false
+	}
+
+	reason, shed := shedReason(r, handler.Schema)
+	if !shed {
+		return false
+	}
+
+	shedOperationsTotal.WithLabelValues(clusterName, reason).Inc()
+	cr.log.Debug().
+		Str("cluster", clusterName).
+		Str("reason", reason).
+		Msg("Shedding operation, the API server is overloaded")
+
+	w.Header().Set("Retry-After", retryAfterSeconds(cr.appCfg))
+	http.Error(w, "The cluster is overloaded, retry later", http.StatusServiceUnavailable)
+	return true
+}
+
+func retryAfterSeconds(appCfg appConfig.Config) string {
+	return strconv.Itoa(int(math.Ceil(appCfg.Gateway.LoadShedding.RetryAfter.Seconds())))
+}
@@ -0,0 +1,156 @@
+package targetcluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func newLoadSheddingTestSchema(t *testing.T) *graphql.Schema {
+	configMap := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ConfigMap",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{Type: graphql.String},
+			"keys": &graphql.Field{Type: graphql.NewList(graphql.String)},
+		},
+	})
+	coreQuery := graphql.NewObject(graphql.ObjectConfig{
+		Name: "coreQuery",
+		Fields: graphql.Fields{
+			"ConfigMaps": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(configMap)))},
+			"ConfigMap":  &graphql.Field{Type: graphql.NewNonNull(configMap)},
+		},
+	})
+	coreMutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "coreMutation",
+		Fields: graphql.Fields{
+			"createConfigMap": &graphql.Field{Type: configMap},
+		},
+	})
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Query",
+			Fields: graphql.Fields{"core": &graphql.Field{Type: coreQuery}},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name:   "Mutation",
+			Fields: graphql.Fields{"core": &graphql.Field{Type: coreMutation}},
+		}),
+	})
+	require.NoError(t, err)
+
+	return &schema
+}
+
+func TestUpstreamHealth(t *testing.T) {
+	health := &upstreamHealth{}
+	for range upstreamHealthMinSamples - 1 {
+		health.observe(2*time.Second, true)
+	}
+	assert.False(t, health.overloaded(time.Second, 50), "too few samples")
+
+	health.observe(2*time.Second, true)
+	assert.True(t, health.overloaded(time.Second, 0))
+	assert.True(t, health.overloaded(0, 50))
+	assert.False(t, health.overloaded(0, 0), "thresholds disabled")
+
+	for range 100 {
+		health.observe(10*time.Millisecond, false)
+	}
+	assert.False(t, health.overloaded(time.Second, 50), "recovered")
+}
+
+func TestUpstreamHealthRoundTripper(t *testing.T) {
+	health := &upstreamHealth{}
+	rt := newUpstreamHealthRoundTripper(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusServiceUnavailable}, nil
+	}), health)
+
+	for _, url := range []string{"https://example.com/api/v1/configmaps", "https://example.com/api/v1/configmaps?watch=true"} {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+		_, err = rt.RoundTrip(req)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, health.samples, "watches must be skipped")
+	assert.Equal(t, float64(1), health.errorRate)
+}
+
+func TestSelectsList(t *testing.T) {
+	schema := newLoadSheddingTestSchema(t)
+
+	assert.True(t, selectsList(schema, `{ core { ConfigMaps { name } } }`))
+	assert.True(t, selectsList(schema, `query A { core { ConfigMap { name } } } query B { core { ConfigMaps { name } } }`))
+	assert.False(t, selectsList(schema, `{ core { ConfigMap { name keys } } }`), "nested lists are not resource lists")
+	assert.False(t, selectsList(schema, `mutation { core { createConfigMap { keys } } }`))
+	assert.False(t, selectsList(schema, `{ core { ConfigMaps {`))
+	assert.False(t, selectsList(schema, ""))
+}
+
+func TestClusterRegistry_ShedLoad(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+	appCfg.Gateway.LoadShedding.ErrorRatePercent = 50
+	appCfg.Gateway.LoadShedding.RetryAfter = 1500 * time.Millisecond
+
+	health := &upstreamHealth{}
+	for range upstreamHealthMinSamples {
+		health.observe(time.Millisecond, true)
+	}
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	server := NewGraphQLServer(registry.log, appCfg)
+	registry.clusters["shed-test"] = &TargetCluster{
+		name:          "shed-test",
+		graphqlServer: server,
+		handler:       server.CreateHandler(newLoadSheddingTestSchema(t)),
+		upstream:      health,
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		expectedShed bool
+	}{
+		{name: "list", body: `{"query": "{ core { ConfigMaps { name } } }"}`, expectedShed: true},
+		{name: "introspection", body: `{"query": "{ __schema { types { name } } }"}`, expectedShed: true},
+		{name: "get", body: `{"query": "{ core { ConfigMap { name } } }"}`},
+		{name: "mutation", body: `{"query": "mutation { core { createConfigMap { name } } }"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/shed-test/graphql", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			registry.ServeHTTP(rec, req)
+
+			if tt.expectedShed {
+				assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+				assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+			} else {
+				assert.Equal(t, http.StatusOK, rec.Code)
+			}
+		})
+	}
+
+	for _, reason := range []string{shedReasonList, shedReasonIntrospection} {
+		metric := &dto.Metric{}
+		require.NoError(t, shedOperationsTotal.WithLabelValues("shed-test", reason).Write(metric))
+		assert.Equal(t, float64(1), metric.GetCounter().GetValue(), reason)
+	}
+}
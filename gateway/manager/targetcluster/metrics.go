@@ -0,0 +1,170 @@
+package targetcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+const (
+	// anonymousOperation is reported for requests that neither name the operation nor reference a persisted query
+	anonymousOperation = "anonymous"
+	// invalidOperation is reported for operation names that are not valid GraphQL names
+	invalidOperation = "invalid"
+	// otherOperation is reported in the metrics for operation names beyond the limit of distinct names
+	otherOperation = "other"
+	// persistedOperationPrefix is prepended to the hash of unnamed persisted queries
+	persistedOperationPrefix = "persisted:"
+	// persistedHashLength is the amount of hash characters kept to bound metric cardinality
+	persistedHashLength = 16
+)
+
+// graphqlNameRegex matches valid GraphQL operation names, see https://spec.graphql.org/October2021/#Name
+var graphqlNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]{0,127}$`)
+
+var (
+	operationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "graphql_gateway",
+		Name:      "operations_total",
+		Help:      "Total number of GraphQL operations handled per cluster and operation name",
+	}, []string{"cluster", "operation_name", "status_code"})
+
+	operationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "graphql_gateway",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of GraphQL operations per cluster and operation name",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"cluster", "operation_name"})
+
+	schemaVersionSkew = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "graphql_gateway",
+		Name:      "schema_version_skew",
+		Help:      "Capability version of the listener that wrote the schema file minus the capability version of the gateway, 0 means no skew",
+	}, []string{"cluster"})
+)
+
+// recordSchemaVersionSkew reports the version difference between the listener that wrote a schema file and this gateway.
+// Schema files without requirements were written by listeners that predate capability versions, i.e. version 0.
+func recordSchemaVersionSkew(cluster string, requirements *common.SchemaRequirements) {
+	var listenerVersion int
+	if requirements != nil {
+		listenerVersion = requirements.ListenerCapabilityVersion
+	}
+
+	schemaVersionSkew.WithLabelValues(cluster).Set(float64(listenerVersion - common.GatewayCapabilityVersion))
+}
+
+// statusRecorder captures the status code written by the wrapped handler
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (s *statusRecorder) WriteHeader(statusCode int) {
+	s.statusCode = statusCode
+	s.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Unwrap allows http.ResponseController to reach the underlying writer, e.g. for flushing SSE responses
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// GetOperationName returns the name under which the request is reported in metrics and logs.
+// It prefers the operationName sent by the client and falls back to the persisted query hash.
+// Bodies larger than maxBodySize are not read and return an *http.MaxBytesError, 0 disables the limit.
+func GetOperationName(w http.ResponseWriter, r *http.Request, maxBodySize int64) (string, error) {
+	var params struct {
+		OperationName string `json:"operationName"`
+		Extensions    struct {
+			PersistedQuery struct {
+				Sha256Hash string `json:"sha256Hash"`
+			} `json:"persistedQuery"`
+		} `json:"extensions"`
+	}
+
+	if r.Method == http.MethodGet {
+		params.OperationName = r.URL.Query().Get("operationName")
+	} else if r.Body != nil {
+		body := r.Body
+		if maxBodySize > 0 {
+			body = http.MaxBytesReader(w, r.Body, maxBodySize)
+		}
+		bodyBytes, err := io.ReadAll(body)
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		if err != nil {
+			return anonymousOperation, err
+		}
+		// The body may be an unsupported content type, in that case the operation stays anonymous
+		_ = json.Unmarshal(bodyBytes, &params)
+	}
+
+	return operationName(params.OperationName, params.Extensions.PersistedQuery.Sha256Hash), nil
+}
+
+// operationName validates the operation name sent by a client, and names unnamed persisted queries after their hash
+func operationName(name, persistedHash string) string {
+	if name != "" {
+		if !graphqlNameRegex.MatchString(name) {
+			return invalidOperation
+		}
+		return name
+	}
+
+	if persistedHash != "" {
+		if len(persistedHash) > persistedHashLength {
+			persistedHash = persistedHash[:persistedHashLength]
+		}
+		if !graphqlNameRegex.MatchString("_" + persistedHash) {
+			return invalidOperation
+		}
+		return persistedOperationPrefix + persistedHash
+	}
+
+	return anonymousOperation
+}
+
+// operationLabels bounds the operation names reported as metric labels, since clients choose them freely.
+// The first maxNames distinct names are reported as they are, all further ones as other.
+type operationLabels struct {
+	mu       sync.Mutex
+	maxNames int
+	names    map[string]struct{}
+}
+
+func newOperationLabels(maxNames int) *operationLabels {
+	return &operationLabels{
+		maxNames: maxNames,
+		names:    make(map[string]struct{}),
+	}
+}
+
+// label returns the metric label of the operation name
+func (l *operationLabels) label(operationName string) string {
+	switch operationName {
+	case anonymousOperation, invalidOperation, otherOperation:
+		return operationName
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.names[operationName]; ok {
+		return operationName
+	}
+	if len(l.names) >= l.maxNames {
+		return otherOperation
+	}
+	l.names[operationName] = struct{}{}
+
+	return operationName
+}
@@ -0,0 +1,115 @@
+package targetcluster_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
+)
+
+func TestGetOperationName(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		expected string
+	}{
+		{
+			name:     "named_operation",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `{"query":"query ListPods { core { Pods { metadata { name } } } }","operationName":"ListPods"}`,
+			expected: "ListPods",
+		},
+		{
+			name:     "persisted_query_hash",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `{"extensions":{"persistedQuery":{"version":1,"sha256Hash":"ecf4edb46db40b5132295c0291d62fb65d6759a9eedfa4d5d612dd5ec54a6b38"}}}`,
+			expected: "persisted:ecf4edb46db40b51",
+		},
+		{
+			name:     "operation_name_wins_over_hash",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `{"operationName":"Widget","extensions":{"persistedQuery":{"sha256Hash":"abc"}}}`,
+			expected: "Widget",
+		},
+		{
+			name:     "anonymous_operation",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `{"query":"{ core { Pods { metadata { name } } } }"}`,
+			expected: "anonymous",
+		},
+		{
+			name:     "invalid_operation_name",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `{"operationName":"drop table; --"}`,
+			expected: "invalid",
+		},
+		{
+			name:     "invalid_json",
+			method:   http.MethodPost,
+			target:   "/",
+			body:     `not json`,
+			expected: "anonymous",
+		},
+		{
+			name:     "get_request_query_parameter",
+			method:   http.MethodGet,
+			target:   "/?operationName=FromQuery",
+			expected: "FromQuery",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+
+			operationName, err := targetcluster.GetOperationName(httptest.NewRecorder(), req, 1024)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if operationName != tt.expected {
+				t.Errorf("expected operation name %q, got %q", tt.expected, operationName)
+			}
+
+			// The body must still be readable by the GraphQL handler
+			body, err := io.ReadAll(req.Body)
+			if err != nil {
+				t.Fatalf("failed to read body: %v", err)
+			}
+			if tt.method != http.MethodGet && string(body) != tt.body {
+				t.Errorf("expected body to be preserved, got %q", string(body))
+			}
+		})
+	}
+}
+
+func TestGetOperationName_BodyTooLarge(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"operationName":"`+strings.Repeat("a", 100)+`"}`))
+
+	_, err := targetcluster.GetOperationName(httptest.NewRecorder(), req, 64)
+	var maxBytesErr *http.MaxBytesError
+	assert.True(t, errors.As(err, &maxBytesErr), "expected a MaxBytesError, got %v", err)
+}
+
+func TestOperationLabels(t *testing.T) {
+	label := targetcluster.NewOperationLabels(2)
+
+	assert.Equal(t, "ListPods", label("ListPods"))
+	assert.Equal(t, "persisted:ecf4edb46db40b51", label("persisted:ecf4edb46db40b51"))
+	assert.Equal(t, "other", label("GetPod"), "names beyond the limit should be reported as other")
+	assert.Equal(t, "ListPods", label("ListPods"), "known names should still be reported")
+	assert.Equal(t, "anonymous", label("anonymous"))
+	assert.Equal(t, "invalid", label("invalid"))
+}
@@ -0,0 +1,172 @@
+package targetcluster
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// PlaygroundTokenStorageKey is the localStorage key GraphiQL keeps the token of the user under
+const PlaygroundTokenStorageKey = "kubernetes-graphql-gateway-token"
+
+// playgroundEndpoint is a cluster endpoint listed on the landing page and in the cluster selector of GraphiQL
+type playgroundEndpoint struct {
+	Cluster  string
+	Endpoint string
+	Error    string
+	Selected bool
+}
+
+var landingTemplate = template.Must(template.New("landing").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Kubernetes GraphQL Gateway</title>
+  <style>
+    body { font-family: sans-serif; margin: 2rem; color: #1b1f23; }
+    table { border-collapse: collapse; }
+    th, td { text-align: left; padding: 0.4rem 1rem 0.4rem 0; }
+    .error { color: #b31d28; }
+  </style>
+</head>
+<body>
+  <h1>Kubernetes GraphQL Gateway</h1>
+  {{- if .}}
+  <table>
+    <tr><th>Cluster</th><th>Endpoint</th><th>Status</th></tr>
+    {{- range .}}
+    <tr>
+      <td>{{.Cluster}}</td>
+      <td>{{if .Endpoint}}<a href="{{.Endpoint}}">{{.Endpoint}}</a>{{end}}</td>
+      <td>{{if .Error}}<span class="error">{{.Error}}</span>{{else}}ready{{end}}</td>
+    </tr>
+    {{- end}}
+  </table>
+  {{- else}}
+  <p>No clusters are served yet.</p>
+  {{- end}}
+</body>
+</html>
+`))
+
+var graphiQLTemplate = template.Must(template.New("graphiql").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>{{.Cluster}} - Kubernetes GraphQL Gateway</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+  <style>
+    body { margin: 0; height: 100vh; display: flex; flex-direction: column; font-family: sans-serif; }
+    #toolbar { display: flex; gap: 1rem; align-items: center; padding: 0.5rem 1rem; border-bottom: 1px solid #ddd; }
+    #toolbar input { width: 24rem; }
+    #graphiql { flex: 1; }
+  </style>
+</head>
+<body>
+  <div id="toolbar">
+    <label>Cluster
+      <select id="cluster">
+        {{- range .Endpoints}}
+        <option value="{{.Endpoint}}"{{if .Selected}} selected{{end}}>{{.Cluster}}</option>
+        {{- end}}
+      </select>
+    </label>
+    <label>Token <input id="token" type="password" autocomplete="off" placeholder="Bearer token, kept in this browser"></label>
+  </div>
+  <div id="graphiql"></div>
+  <script src="https://unpkg.com/react@18/umd/react.production.min.js" crossorigin></script>
+  <script src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js" crossorigin></script>
+  <script src="https://unpkg.com/graphiql@3/graphiql.min.js" crossorigin></script>
+  <script>
+    const tokenKey = {{.TokenStorageKey}};
+    const tokenInput = document.getElementById('token');
+    tokenInput.value = localStorage.getItem(tokenKey) || '';
+    tokenInput.addEventListener('change', () => {
+      const token = tokenInput.value.trim().replace(/^bearer\s+/i, '');
+      if (token) {
+        localStorage.setItem(tokenKey, token);
+      } else {
+        localStorage.removeItem(tokenKey);
+      }
+    });
+
+    document.getElementById('cluster').addEventListener('change', (event) => {
+      window.location.href = event.target.value;
+    });
+
+    function fetcher(params) {
+      const headers = { 'Content-Type': 'application/json', 'Accept': 'application/json' };
+      const token = localStorage.getItem(tokenKey);
+      if (token) {
+        headers['Authorization'] = 'Bearer ' + token;
+      }
+      return fetch(window.location.pathname, { method: 'POST', headers: headers, body: JSON.stringify(params) })
+        .then((response) => response.json());
+    }
+
+    ReactDOM.createRoot(document.getElementById('graphiql')).render(React.createElement(GraphiQL, { fetcher: fetcher }));
+  </script>
+</body>
+</html>
+`))
+
+// wantsHTML reports whether a GET request was sent by a browser, like the GraphQL handler decides whether to render
+// GraphiQL. Requests asking for JSON or with the raw parameter are answered with JSON.
+func wantsHTML(r *http.Request) bool {
+	accept := r.Header.Get("Accept")
+	_, raw := r.URL.Query()["raw"]
+	return !raw && !strings.Contains(accept, "application/json") && strings.Contains(accept, "text/html")
+}
+
+// playgroundEnabled reports whether the gateway serves a UI to browsers
+func (cr *ClusterRegistry) playgroundEnabled() bool {
+	return cr.appCfg.Gateway.HandlerCfg.GraphiQL || cr.appCfg.Gateway.HandlerCfg.Playground
+}
+
+// playgroundEndpoints returns the endpoints of all schemas, including the ones that failed to load
+func (cr *ClusterRegistry) playgroundEndpoints(selected string) []playgroundEndpoint {
+	statuses := cr.SchemaStatus()
+	endpoints := make([]playgroundEndpoint, 0, len(statuses))
+	for _, status := range statuses {
+		endpoints = append(endpoints, playgroundEndpoint{
+			Cluster:  status.Cluster,
+			Endpoint: status.Endpoint,
+			Error:    status.Error,
+			Selected: status.Cluster == selected,
+		})
+	}
+	return endpoints
+}
+
+// serveLanding lists the endpoints of all clusters for operators opening the gateway in a browser
+func (cr *ClusterRegistry) serveLanding(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := landingTemplate.Execute(w, cr.playgroundEndpoints("")); err != nil {
+		cr.log.Error().Err(err).Msg("Failed to render landing page")
+	}
+}
+
+// serveGraphiQL renders GraphiQL with a selector of the other clusters and a token input. The token is kept in the
+// localStorage of the browser and sent as Authorization header with every operation.
+func (cr *ClusterRegistry) serveGraphiQL(w http.ResponseWriter, _ *http.Request, clusterName string) {
+	endpoints := []playgroundEndpoint{}
+	for _, endpoint := range cr.playgroundEndpoints(clusterName) {
+		if endpoint.Endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err := graphiQLTemplate.Execute(w, struct {
+		Cluster         string
+		Endpoints       []playgroundEndpoint
+		TokenStorageKey string
+	}{
+		Cluster:         clusterName,
+		Endpoints:       endpoints,
+		TokenStorageKey: PlaygroundTokenStorageKey,
+	})
+	if err != nil {
+		cr.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to render GraphiQL")
+	}
+}
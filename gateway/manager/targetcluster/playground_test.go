@@ -0,0 +1,79 @@
+package targetcluster_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
+)
+
+func TestClusterRegistry_Playground(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"alpha", "beta"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(`{
+			"definitions": {},
+			"x-cluster-metadata": {"host": "https://example.com"}
+		}`), 0o600))
+	}
+
+	appCfg := targetcluster.CreateTestConfig(false, "8080")
+	appCfg.Gateway.HandlerCfg.GraphiQL = true
+	registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	require.NoError(t, registry.LoadCluster(filepath.Join(dir, "alpha")))
+	require.NoError(t, registry.LoadCluster(filepath.Join(dir, "beta")))
+
+	tests := []struct {
+		name           string
+		path           string
+		accept         string
+		expectedStatus int
+		expectedBody   []string
+	}{
+		{
+			name:           "landing_page",
+			path:           "/",
+			accept:         "text/html",
+			expectedStatus: http.StatusOK,
+			expectedBody:   []string{`<a href="/alpha/graphql">`, `<a href="/beta/graphql">`},
+		},
+		{
+			name:           "landing_page_without_browser",
+			path:           "/",
+			accept:         "application/json",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "graphiql_with_cluster_selector",
+			path:           "/beta/graphql",
+			accept:         "text/html",
+			expectedStatus: http.StatusOK,
+			expectedBody: []string{
+				`<option value="/alpha/graphql">alpha</option>`,
+				`<option value="/beta/graphql" selected>beta</option>`,
+				targetcluster.PlaygroundTokenStorageKey,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept", tt.accept)
+			rec := httptest.NewRecorder()
+
+			registry.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			for _, expected := range tt.expectedBody {
+				assert.Contains(t, rec.Body.String(), expected)
+			}
+		})
+	}
+}
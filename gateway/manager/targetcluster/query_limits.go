@@ -0,0 +1,301 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+const (
+	queryLimitDepth      = "depth"
+	queryLimitAliases    = "aliases"
+	queryLimitComplexity = "complexity"
+
+	// QueryLimitExceededCode is the code in the extensions of the error returned for queries exceeding a limit
+	QueryLimitExceededCode = "QUERY_LIMIT_EXCEEDED"
+
+	// listCostFactor is the assumed amount of items of a list, the cost of the selections below a list is multiplied by it
+	listCostFactor = 10
+	// maxQueryCost caps the cost of deeply nested lists, so that it doesn't overflow
+	maxQueryCost = 1 << 40
+)
+
+var rejectedOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "graphql_gateway",
+	Name:      "query_limit_rejections_total",
+	Help:      "Number of GraphQL operations rejected because they exceed the depth, alias or complexity limit",
+}, []string{"cluster", "limit"})
+
+// queryLimits bounds the size of the operations of clients before they are executed, a zero limit is disabled
+type queryLimits struct {
+	maxDepth      int
+	maxAliases    int
+	maxComplexity int
+}
+
+func queryLimitsFromConfig(appCfg appConfig.Config) queryLimits {
+	return queryLimits{
+		maxDepth:      appCfg.Gateway.QueryLimits.MaxDepth,
+		maxAliases:    appCfg.Gateway.QueryLimits.MaxAliases,
+		maxComplexity: appCfg.Gateway.QueryLimits.MaxComplexity,
+	}
+}
+
+func (l queryLimits) enabled() bool {
+	return l.maxDepth > 0 || l.maxAliases > 0 || l.maxComplexity > 0
+}
+
+// queryLimitError reports the limit exceeded by a query
+type queryLimitError struct {
+	limit string
+	value int
+	max   int
+}
+
+func (e *queryLimitError) Error() string {
+	return fmt.Sprintf("query %s of %d exceeds the maximum of %d", e.limit, e.value, e.max)
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *queryLimitError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":  QueryLimitExceededCode,
+		"limit": e.limit,
+		"value": e.value,
+		"max":   e.max,
+	}
+}
+
+func (e *queryLimitError) formatted() gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    e.Error(),
+		Extensions: e.Extensions(),
+	}
+}
+
+// check returns the first limit exceeded by the query. Queries that can't be parsed are left to the GraphQL handler,
+// which reports them to the client.
+func (l queryLimits) check(schema *graphql.Schema, query string) *queryLimitError {
+	if !l.enabled() || query == "" {
+		return nil
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	cost := analyzeQuery(schema, doc)
+	switch {
+	case l.maxDepth > 0 && cost.depth > l.maxDepth:
+		return &queryLimitError{limit: queryLimitDepth, value: cost.depth, max: l.maxDepth}
+	case l.maxAliases > 0 && cost.aliases > l.maxAliases:
+		return &queryLimitError{limit: queryLimitAliases, value: cost.aliases, max: l.maxAliases}
+	case l.maxComplexity > 0 && cost.complexity > l.maxComplexity:
+		return &queryLimitError{limit: queryLimitComplexity, value: cost.complexity, max: l.maxComplexity}
+	}
+
+	return nil
+}
+
+// queryCost is the size of the largest operation of a query document
+type queryCost struct {
+	depth      int
+	aliases    int
+	complexity int
+}
+
+// queryAnalyzer measures the operations of a query document. Every field costs 1, and the cost of the selections
+// below a list field is multiplied by listCostFactor. Introspection fields are not counted.
+type queryAnalyzer struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	// visiting guards against cyclic fragment spreads, which are rejected by the validation of the GraphQL handler
+	visiting map[string]bool
+
+	depth   int
+	aliases int
+}
+
+func analyzeQuery(schema *graphql.Schema, doc *ast.Document) queryCost {
+	a := &queryAnalyzer{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		visiting:  make(map[string]bool),
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			a.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	var cost queryCost
+	for _, definition := range doc.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		a.depth, a.aliases = 0, 0
+		complexity := a.selectionSet(a.rootType(operation.Operation), operation.SelectionSet, 0)
+
+		cost.depth = max(cost.depth, a.depth)
+		cost.aliases = max(cost.aliases, a.aliases)
+		cost.complexity = max(cost.complexity, complexity)
+	}
+
+	return cost
+}
+
+func (a *queryAnalyzer) rootType(operation string) graphql.Type {
+	if a.schema == nil {
+		return nil
+	}
+
+	var root *graphql.Object
+	switch operation {
+	case ast.OperationTypeMutation:
+		root = a.schema.MutationType()
+	case ast.OperationTypeSubscription:
+		root = a.schema.SubscriptionType()
+	default:
+		root = a.schema.QueryType()
+	}
+	if root == nil {
+		return nil
+	}
+	return root
+}
+
+// selectionSet returns the cost of the selections on parentType at the given depth
+func (a *queryAnalyzer) selectionSet(parentType graphql.Type, set *ast.SelectionSet, depth int) int {
+	if set == nil {
+		return 0
+	}
+
+	var cost int
+	for _, selection := range set.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name.Value, "__") {
+				continue
+			}
+			if sel.Alias != nil {
+				a.aliases++
+			}
+			a.depth = max(a.depth, depth+1)
+
+			fieldType := fieldTypeOf(parentType, sel.Name.Value)
+			childCost := a.selectionSet(namedTypeOf(fieldType), sel.SelectionSet, depth+1)
+			if isListType(fieldType) {
+				childCost = min(childCost*listCostFactor, maxQueryCost)
+			}
+			cost = min(cost+1+childCost, maxQueryCost)
+		case *ast.InlineFragment:
+			fragmentType := parentType
+			if sel.TypeCondition != nil && a.schema != nil {
+				fragmentType = a.schema.Type(sel.TypeCondition.Name.Value)
+			}
+			cost = min(cost+a.selectionSet(fragmentType, sel.SelectionSet, depth), maxQueryCost)
+		case *ast.FragmentSpread:
+			name := sel.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || a.visiting[name] {
+				continue
+			}
+
+			var fragmentType graphql.Type
+			if a.schema != nil && fragment.TypeCondition != nil {
+				fragmentType = a.schema.Type(fragment.TypeCondition.Name.Value)
+			}
+
+			a.visiting[name] = true
+			cost = min(cost+a.selectionSet(fragmentType, fragment.SelectionSet, depth), maxQueryCost)
+			delete(a.visiting, name)
+		}
+	}
+
+	return cost
+}
+
+// fieldTypeOf returns the type of the field of parentType, nil if it is unknown
+func fieldTypeOf(parentType graphql.Type, fieldName string) graphql.Type {
+	var fields graphql.FieldDefinitionMap
+	switch t := parentType.(type) {
+	case *graphql.Object:
+		fields = t.Fields()
+	case *graphql.Interface:
+		fields = t.Fields()
+	default:
+		return nil
+	}
+
+	field, ok := fields[fieldName]
+	if !ok {
+		return nil
+	}
+	return field.Type
+}
+
+// namedTypeOf unwraps the lists and non-null wrappers of a type
+func namedTypeOf(t graphql.Type) graphql.Type {
+	for {
+		switch wrapper := t.(type) {
+		case *graphql.NonNull:
+			t = wrapper.OfType
+		case *graphql.List:
+			t = wrapper.OfType
+		default:
+			return t
+		}
+	}
+}
+
+func isListType(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
+
+// rejectQueryOverLimits rejects operations that exceed one of the configured limits with 400 before they are executed
+func (cr *ClusterRegistry) rejectQueryOverLimits(w http.ResponseWriter, r *http.Request, clusterName string, cluster *TargetCluster) bool {
+	if !cr.queryLimits.enabled() {
+		return false
+	}
+
+	handler, ok := cluster.handlerFor(GetSchemaProfile(r))
+	if !ok || handler == nil {
+		return false
+	}
+
+	limitErr := cr.queryLimits.check(handler.Schema, getQuery(r))
+	if limitErr == nil {
+		return false
+	}
+
+	rejectedOperationsTotal.WithLabelValues(clusterName, limitErr.limit).Inc()
+	cr.log.Debug().
+		Str("cluster", clusterName).
+		Str("limit", limitErr.limit).
+		Int("value", limitErr.value).
+		Msg("Rejecting operation, it exceeds a query limit")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []gqlerrors.FormattedError{limitErr.formatted()},
+	})
+	return true
+}
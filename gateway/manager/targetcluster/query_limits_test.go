@@ -0,0 +1,148 @@
+package targetcluster
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+func TestAnalyzeQuery(t *testing.T) {
+	schema := newLoadSheddingTestSchema(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected queryCost
+	}{
+		{
+			name:     "get",
+			query:    `{ core { ConfigMap { name } } }`,
+			expected: queryCost{depth: 3, complexity: 3},
+		},
+		{
+			name:     "list_multiplies_nested_cost",
+			query:    `{ core { ConfigMaps { name keys } } }`,
+			expected: queryCost{depth: 3, complexity: 22},
+		},
+		{
+			name:     "aliases",
+			query:    `{ a: core { ConfigMap { name } } b: core { ConfigMap { name } } }`,
+			expected: queryCost{depth: 3, aliases: 2, complexity: 6},
+		},
+		{
+			name:     "fragments",
+			query:    `query { core { ...maps } } fragment maps on coreQuery { ConfigMaps { name } }`,
+			expected: queryCost{depth: 3, complexity: 12},
+		},
+		{
+			name:     "cyclic_fragments",
+			query:    `query { core { ...a } } fragment a on coreQuery { ConfigMap { name } ...a }`,
+			expected: queryCost{depth: 3, complexity: 3},
+		},
+		{
+			name:     "introspection_is_not_counted",
+			query:    `{ __schema { types { name fields { name } } } }`,
+			expected: queryCost{},
+		},
+		{
+			name:     "mutation",
+			query:    `mutation { core { createConfigMap { name } } }`,
+			expected: queryCost{depth: 3, complexity: 3},
+		},
+		{
+			name:     "largest_operation",
+			query:    `query Small { core { ConfigMap { name } } } query Large { core { ConfigMaps { name } } }`,
+			expected: queryCost{depth: 3, complexity: 12},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, analyzeQuery(schema, doc))
+		})
+	}
+}
+
+func TestQueryLimits_Check(t *testing.T) {
+	schema := newLoadSheddingTestSchema(t)
+
+	tests := []struct {
+		name          string
+		limits        queryLimits
+		query         string
+		expectedLimit string
+	}{
+		{name: "disabled", query: `{ core { ConfigMaps { name } } }`},
+		{name: "within_limits", limits: queryLimits{maxDepth: 3, maxAliases: 1, maxComplexity: 12}, query: `{ core { ConfigMaps { name } } }`},
+		{name: "depth", limits: queryLimits{maxDepth: 2}, query: `{ core { ConfigMap { name } } }`, expectedLimit: queryLimitDepth},
+		{name: "aliases", limits: queryLimits{maxAliases: 1}, query: `{ a: core { ConfigMap { name } } b: core { ConfigMap { name } } }`, expectedLimit: queryLimitAliases},
+		{name: "complexity", limits: queryLimits{maxComplexity: 10}, query: `{ core { ConfigMaps { name } } }`, expectedLimit: queryLimitComplexity},
+		{name: "unparsable_query_is_left_to_handler", limits: queryLimits{maxDepth: 1}, query: `{ core {`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limitErr := tt.limits.check(schema, tt.query)
+			if tt.expectedLimit == "" {
+				assert.Nil(t, limitErr)
+				return
+			}
+			require.NotNil(t, limitErr)
+			assert.Equal(t, tt.expectedLimit, limitErr.limit)
+		})
+	}
+}
+
+func TestClusterRegistry_QueryLimits(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+	appCfg.Gateway.QueryLimits.MaxComplexity = 10
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	server := NewGraphQLServer(registry.log, appCfg)
+	registry.clusters["limits-test"] = &TargetCluster{
+		name:          "limits-test",
+		graphqlServer: server,
+		handler:       server.CreateHandler(newLoadSheddingTestSchema(t)),
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/limits-test/graphql", strings.NewReader(`{"query": "{ core { ConfigMaps { name } } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+
+	registry.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body struct {
+		Errors []struct {
+			Message    string                 `json:"message"`
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, "query complexity of 12 exceeds the maximum of 10", body.Errors[0].Message)
+	assert.Equal(t, QueryLimitExceededCode, body.Errors[0].Extensions["code"])
+
+	req = httptest.NewRequest(http.MethodPost, "/limits-test/graphql", strings.NewReader(`{"query": "{ core { ConfigMap { name } } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+
+	registry.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
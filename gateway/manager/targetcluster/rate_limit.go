@@ -0,0 +1,211 @@
+package targetcluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+const (
+	// RateLimitedCode is the code in the extensions of the error returned for operations over the rate limit of the user
+	RateLimitedCode = "RATE_LIMITED"
+
+	// rateLimiterIdleTimeout is how long the limiter of a user is kept after its last operation
+	rateLimiterIdleTimeout = 10 * time.Minute
+	// tokenKeyPrefix marks the keys of tokens without the username claim, e.g. opaque tokens
+	tokenKeyPrefix = "token:"
+)
+
+var rateLimitedOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "graphql_gateway",
+	Name:      "rate_limited_operations_total",
+	Help:      "Number of GraphQL operations rejected because their user exceeded the rate limit",
+}, []string{"cluster"})
+
+// userRateLimiter limits the operations per user across all clusters, so that a single user can't starve the others.
+// A nil userRateLimiter is disabled.
+type userRateLimiter struct {
+	mu            sync.Mutex
+	limit         rate.Limit
+	burst         int
+	usernameClaim string
+	limiters      map[string]*userLimiter
+	lastSweep     time.Time
+}
+
+type userLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newUserRateLimiter returns the limiter configured in appCfg, nil if the rate limit is disabled
+func newUserRateLimiter(appCfg appConfig.Config) *userRateLimiter {
+	cfg := appCfg.Gateway.RateLimit
+	if cfg.QPS <= 0 {
+		return nil
+	}
+
+	burst := cfg.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(cfg.QPS))
+	}
+
+	return &userRateLimiter{
+		limit:         rate.Limit(cfg.QPS),
+		burst:         burst,
+		usernameClaim: appCfg.Gateway.UsernameClaim,
+		limiters:      make(map[string]*userLimiter),
+	}
+}
+
+// userKey identifies the user of ctx by the username claim of its token. Tokens without the claim are told apart by
+// their hash, requests without a token, which are only accepted in local development or as the anonymous service
+// account, share the empty key.
+func (l *userRateLimiter) userKey(ctx context.Context) string {
+	if user, ok := roundtripper.GetUserFromContext(ctx, l.usernameClaim); ok {
+		return user
+	}
+
+	token, _ := ctx.Value(roundtripper.TokenKey{}).(string)
+	if token == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	return tokenKeyPrefix + hex.EncodeToString(hash[:])
+}
+
+// allow takes a token from the bucket of the user of ctx. If the bucket is empty, it returns how long the user has to wait.
+func (l *userRateLimiter) allow(ctx context.Context, now time.Time) (time.Duration, bool) {
+	if l == nil {
+		return 0, true
+	}
+
+	key := l.userKey(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	user, ok := l.limiters[key]
+	if !ok {
+		user = &userLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[key] = user
+	}
+	user.lastSeen = now
+
+	reservation := user.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay, false
+	}
+	return 0, true
+}
+
+// sweep forgets the users that were idle for rateLimiterIdleTimeout, their buckets are full again by then
+func (l *userRateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterIdleTimeout {
+		return
+	}
+	l.lastSweep = now
+
+	for key, user := range l.limiters {
+		if now.Sub(user.lastSeen) >= rateLimiterIdleTimeout {
+			delete(l.limiters, key)
+		}
+	}
+}
+
+// rateLimitError tells the client how long to wait before its next operation
+type rateLimitError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return "rate limit exceeded, retry in " + e.retryAfterSeconds() + "s"
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (e *rateLimitError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":       RateLimitedCode,
+		"retryAfter": e.retryAfter.Seconds(),
+	}
+}
+
+func (e *rateLimitError) formatted() gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    e.Error(),
+		Extensions: e.Extensions(),
+	}
+}
+
+func (e *rateLimitError) retryAfterSeconds() string {
+	return strconv.Itoa(max(1, int(math.Ceil(e.retryAfter.Seconds()))))
+}
+
+// checkRateLimit returns an error if the user of ctx exceeded the rate limit
+func (cr *ClusterRegistry) checkRateLimit(ctx context.Context, clusterName string) *rateLimitError {
+	retryAfter, ok := cr.rateLimiter.allow(ctx, time.Now())
+	if ok {
+		return nil
+	}
+
+	rateLimitedOperationsTotal.WithLabelValues(clusterName).Inc()
+	cr.log.Debug().
+		Str("cluster", clusterName).
+		Dur("retryAfter", retryAfter).
+		Msg("Rejecting operation, the user exceeded the rate limit")
+
+	return &rateLimitError{retryAfter: retryAfter}
+}
+
+// rejectRateLimited rejects operations of users over the rate limit with 429
+func (cr *ClusterRegistry) rejectRateLimited(w http.ResponseWriter, r *http.Request, clusterName string) bool {
+	limitErr := cr.checkRateLimit(r.Context(), clusterName)
+	if limitErr == nil {
+		return false
+	}
+
+	w.Header().Set("Retry-After", limitErr.retryAfterSeconds())
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []gqlerrors.FormattedError{limitErr.formatted()},
+	})
+	return true
+}
+
+// throttleAPIServer sets the budget shared by all API server requests of the cluster. A zero QPS keeps the client-go
+// defaults, which apply to each client separately, and a negative QPS disables client-side throttling.
+func throttleAPIServer(restCfg *rest.Config, appCfg appConfig.Config) {
+	qps, burst := appCfg.Gateway.RateLimit.ApiServerQPS, appCfg.Gateway.RateLimit.ApiServerBurst
+	switch {
+	case qps < 0:
+		restCfg.QPS = -1
+	case qps > 0:
+		if burst <= 0 {
+			burst = int(math.Ceil(float64(qps)))
+		}
+		restCfg.QPS, restCfg.Burst = qps, burst
+		// A shared limiter, as controller-runtime creates a REST client with its own limiter per resource
+		restCfg.RateLimiter = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+	}
+}
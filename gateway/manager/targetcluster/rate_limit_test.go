@@ -0,0 +1,156 @@
+package targetcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func newRateLimitTestConfig(qps float64, burst int) appConfig.Config {
+	appCfg := appConfig.Config{}
+	appCfg.Gateway.UsernameClaim = "email"
+	appCfg.Gateway.RateLimit.QPS = qps
+	appCfg.Gateway.RateLimit.Burst = burst
+	return appCfg
+}
+
+func contextWithUser(t *testing.T, email string) context.Context {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": email}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+	return context.WithValue(context.Background(), roundtripper.TokenKey{}, token)
+}
+
+func TestUserRateLimiter_Allow(t *testing.T) {
+	limiter := newUserRateLimiter(newRateLimitTestConfig(1, 2))
+	require.NotNil(t, limiter)
+
+	alice, bob := contextWithUser(t, "alice@example.com"), contextWithUser(t, "bob@example.com")
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		_, ok := limiter.allow(alice, now)
+		assert.True(t, ok, "operation %d within the burst", i)
+	}
+
+	retryAfter, ok := limiter.allow(alice, now)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, retryAfter)
+
+	_, ok = limiter.allow(bob, now)
+	assert.True(t, ok, "other users have their own limit")
+
+	_, ok = limiter.allow(alice, now.Add(time.Second))
+	assert.True(t, ok, "the bucket refills over time")
+}
+
+func TestUserRateLimiter_UserKey(t *testing.T) {
+	limiter := newUserRateLimiter(newRateLimitTestConfig(1, 1))
+
+	assert.Equal(t, "alice@example.com", limiter.userKey(contextWithUser(t, "alice@example.com")))
+	assert.Equal(t, "", limiter.userKey(context.Background()))
+
+	opaque := limiter.userKey(context.WithValue(context.Background(), roundtripper.TokenKey{}, "opaque-token"))
+	assert.True(t, strings.HasPrefix(opaque, tokenKeyPrefix))
+	assert.NotContains(t, opaque, "opaque-token")
+}
+
+func TestUserRateLimiter_SweepsIdleUsers(t *testing.T) {
+	limiter := newUserRateLimiter(newRateLimitTestConfig(1, 1))
+	now := time.Now()
+
+	_, _ = limiter.allow(contextWithUser(t, "alice@example.com"), now)
+	_, _ = limiter.allow(contextWithUser(t, "bob@example.com"), now.Add(rateLimiterIdleTimeout))
+
+	assert.Len(t, limiter.limiters, 1)
+	assert.Contains(t, limiter.limiters, "bob@example.com")
+}
+
+func TestUserRateLimiter_Disabled(t *testing.T) {
+	limiter := newUserRateLimiter(newRateLimitTestConfig(0, 10))
+	assert.Nil(t, limiter)
+
+	_, ok := limiter.allow(context.Background(), time.Now())
+	assert.True(t, ok)
+}
+
+func TestThrottleAPIServer(t *testing.T) {
+	tests := []struct {
+		name          string
+		qps           float32
+		burst         int
+		expectedQPS   float32
+		expectedBurst int
+		sharedLimiter bool
+	}{
+		{name: "client_go_defaults"},
+		{name: "configured", qps: 50, burst: 100, expectedQPS: 50, expectedBurst: 100, sharedLimiter: true},
+		{name: "burst_defaults_to_qps", qps: 2.5, expectedQPS: 2.5, expectedBurst: 3, sharedLimiter: true},
+		{name: "disabled", qps: -1, expectedQPS: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCfg := appConfig.Config{}
+			appCfg.Gateway.RateLimit.ApiServerQPS = tt.qps
+			appCfg.Gateway.RateLimit.ApiServerBurst = tt.burst
+
+			restCfg := &rest.Config{}
+			throttleAPIServer(restCfg, appCfg)
+
+			assert.Equal(t, tt.expectedQPS, restCfg.QPS)
+			assert.Equal(t, tt.expectedBurst, restCfg.Burst)
+			assert.Equal(t, tt.sharedLimiter, restCfg.RateLimiter != nil)
+		})
+	}
+}
+
+func TestClusterRegistry_RateLimit(t *testing.T) {
+	appCfg := newRateLimitTestConfig(1, 1)
+	appCfg.LocalDevelopment = true
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	server := NewGraphQLServer(registry.log, appCfg)
+	registry.clusters["rate-limit-test"] = &TargetCluster{
+		name:          "rate-limit-test",
+		graphqlServer: server,
+		handler:       server.CreateHandler(newLoadSheddingTestSchema(t)),
+	}
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/rate-limit-test/graphql", strings.NewReader(`{"query": "{ core { ConfigMap { name } } }"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusOK, send().Code)
+
+	rec := send()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	var body struct {
+		Errors []struct {
+			Extensions map[string]interface{} `json:"extensions"`
+		} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Errors, 1)
+	assert.Equal(t, RateLimitedCode, body.Errors[0].Extensions["code"])
+}
@@ -0,0 +1,120 @@
+package targetcluster
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/recording"
+)
+
+const (
+	// DebugRecordHeader requests that the operation is recorded, if recording is enabled
+	DebugRecordHeader = "X-Debug-Record"
+	// DebugRecordingHeader returns the file name of the bundle of a recorded operation
+	DebugRecordingHeader = "X-Debug-Recording"
+)
+
+// unsafeFileNameChars matches the characters of cluster and operation names that are replaced in bundle file names
+var unsafeFileNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// startRecording records the API server exchanges of the operation if recording is enabled and requested.
+// The returned function writes the bundle once the operation was executed, it does nothing if nothing is recorded.
+func (cr *ClusterRegistry) startRecording(
+	r *http.Request,
+	w http.ResponseWriter,
+	clusterName string,
+	cluster *TargetCluster,
+	operationName string,
+) (*http.Request, func()) {
+	dir := cr.appCfg.Gateway.DebugRecordingDir
+	if dir == "" || r.Header.Get(DebugRecordHeader) != "true" {
+		return r, func() {}
+	}
+
+	recordedAt := time.Now()
+	fileName := fmt.Sprintf("%s-%s-%d.json",
+		unsafeFileNameChars.ReplaceAllString(clusterName, "_"),
+		unsafeFileNameChars.ReplaceAllString(operationName, "_"),
+		recordedAt.UnixNano(),
+	)
+	w.Header().Set(DebugRecordingHeader, fileName)
+
+	recorder := &recording.Recorder{}
+	r = r.WithContext(recording.WithRecorder(r.Context(), recorder))
+	operation := getOperation(r)
+
+	return r, func() {
+		bundle := &recording.Bundle{
+			Cluster:       clusterName,
+			RecordedAt:    recordedAt,
+			Operation:     operation,
+			Definitions:   cluster.definitions,
+			Defaults:      cluster.defaults,
+			InputCoercion: cr.appCfg.Gateway.InputCoercion,
+			Protobuf:      cr.appCfg.Gateway.ApiServerProtobuf,
+			Mappings:      cluster.restMappings(),
+			Exchanges:     recorder.Exchanges(),
+		}
+
+		if err := recording.WriteBundle(filepath.Join(dir, fileName), bundle); err != nil {
+			cr.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to write recording")
+			return
+		}
+
+		cr.log.Info().
+			Str("cluster", clusterName).
+			Str("operationName", operationName).
+			Str("file", fileName).
+			Int("exchanges", len(bundle.Exchanges)).
+			Msg("Recorded operation")
+	}
+}
+
+// restMappings returns the REST mappings of the resources of the cluster, so that replays don't need discovery
+func (tc *TargetCluster) restMappings() []recording.Mapping {
+	mappings := make([]recording.Mapping, 0, len(tc.resources))
+	for _, resource := range tc.resources {
+		mappings = append(mappings, recording.Mapping{
+			Group:      resource.Group,
+			Version:    resource.Version,
+			Kind:       resource.Kind,
+			Resource:   tc.restResource(resource),
+			Namespaced: resource.Scope == apiextensionsv1.NamespaceScoped,
+		})
+	}
+
+	return mappings
+}
+
+// getOperation returns the GraphQL request sent by the client
+func getOperation(r *http.Request) recording.Operation {
+	var operation recording.Operation
+	if r.Method == http.MethodGet {
+		operation.Query = r.URL.Query().Get("query")
+		operation.OperationName = r.URL.Query().Get("operationName")
+		_ = json.Unmarshal([]byte(r.URL.Query().Get("variables")), &operation.Variables)
+		return operation
+	}
+
+	if r.Body == nil {
+		return operation
+	}
+
+	bodyBytes, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return operation
+	}
+	_ = json.Unmarshal(bodyBytes, &operation)
+
+	return operation
+}
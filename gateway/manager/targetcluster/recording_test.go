@@ -0,0 +1,54 @@
+package targetcluster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/recording"
+)
+
+func TestClusterRegistry_StartRecording(t *testing.T) {
+	appCfg := appConfig.Config{}
+	appCfg.Gateway.DebugRecordingDir = t.TempDir()
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	cluster := &TargetCluster{name: "root:orgs", definitions: map[string]any{}}
+
+	t.Run("not_requested", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/root:orgs/graphql", strings.NewReader(`{"query": "{ core { ConfigMaps { name } } }"}`))
+		rec := httptest.NewRecorder()
+
+		r, finish := registry.startRecording(req, rec, "root:orgs", cluster, "Dashboard")
+		finish()
+
+		assert.Same(t, req, r)
+		assert.Empty(t, rec.Header().Get(DebugRecordingHeader))
+	})
+
+	t.Run("requested", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/root:orgs/graphql", strings.NewReader(
+			`{"query": "query Dashboard($ns: String) { core { ConfigMaps(namespace: $ns) { name } } }", "operationName": "Dashboard", "variables": {"ns": "default"}}`,
+		))
+		req.Header.Set(DebugRecordHeader, "true")
+		rec := httptest.NewRecorder()
+
+		_, finish := registry.startRecording(req, rec, "root:orgs", cluster, "Dashboard")
+		finish()
+
+		fileName := rec.Header().Get(DebugRecordingHeader)
+		assert.True(t, strings.HasPrefix(fileName, "root_orgs-Dashboard-"), fileName)
+
+		bundle, err := recording.ReadBundle(filepath.Join(appCfg.Gateway.DebugRecordingDir, fileName))
+		require.NoError(t, err)
+		assert.Equal(t, "root:orgs", bundle.Cluster)
+		assert.Equal(t, "Dashboard", bundle.Operation.OperationName)
+		assert.Equal(t, map[string]any{"ns": "default"}, bundle.Operation.Variables)
+	})
+}
@@ -1,18 +1,26 @@
 package targetcluster
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/openmfp/golang-commons/logger"
 	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
 	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
 	"k8s.io/client-go/rest"
 )
 
@@ -22,8 +30,11 @@ type contextKey string
 // kcpWorkspaceKey is the context key for storing KCP workspace information
 const kcpWorkspaceKey contextKey = "kcpWorkspace"
 
-// RoundTripperFactory creates HTTP round trippers for authentication
-type RoundTripperFactory func(http.RoundTripper, rest.TLSClientConfig) http.RoundTripper
+// schemaProfileKey is the context key for storing the schema profile requested via the path
+const schemaProfileKey contextKey = "schemaProfile"
+
+// RoundTripperFactory creates HTTP round trippers for authentication, opts are the settings of the target cluster
+type RoundTripperFactory func(http.RoundTripper, rest.TLSClientConfig, ...roundtripper.Option) http.RoundTripper
 
 // ClusterRegistry manages multiple target clusters and handles HTTP routing to them
 type ClusterRegistry struct {
@@ -32,6 +43,38 @@ type ClusterRegistry struct {
 	log                 *logger.Logger
 	appCfg              appConfig.Config
 	roundTripperFactory RoundTripperFactory
+	// loadErrors stores why the schema file of a cluster could not be loaded, map[clusterName]error
+	loadErrors map[string]string
+	fieldUsage *fieldUsageTracker
+	// queryLimits rejects operations that are too large before they are executed
+	queryLimits queryLimits
+	// rateLimiter rejects operations of users sending too many of them, nil if disabled
+	rateLimiter *userRateLimiter
+	// clusterGroups are served under /groups/{group}/graphql, see WithClusterGroups
+	clusterGroups []ClusterGroup
+	// tokenVerifier rejects invalid tokens before the operations are executed, nil if the tokens are not verified
+	tokenVerifier *roundtripper.TokenVerifier
+	// cors answers the CORS requests of browsers, nil if CORS is disabled
+	cors *corsPolicy
+	// auditSink receives the audit events of the mutations of all clusters, nil if the audit log is disabled
+	auditSink audit.Sink
+	// schemaVersions are the previous schemas of the clusters, nil if they are not kept, see WithSchemaVersions
+	schemaVersions workspacefile.SchemaVersions
+	// clientManager shares the clients of a cluster between its reloaded schemas
+	clientManager *ClusterClientManager
+	// drainer ends the subscriptions when the gateway shuts down
+	drainer *drainer
+	// operationLabels bounds the operation names reported in the operation metrics
+	operationLabels *operationLabels
+}
+
+// SchemaStatus describes whether the schema file of a cluster is served
+type SchemaStatus struct {
+	Cluster string `json:"cluster"`
+	Loaded  bool   `json:"loaded"`
+	// Endpoint is the URL under which clients reach the GraphQL API of a loaded cluster
+	Endpoint string `json:"endpoint,omitempty"`
+	Error    string `json:"error,omitempty"`
 }
 
 // NewClusterRegistry creates a new cluster registry
@@ -42,45 +85,141 @@ func NewClusterRegistry(
 ) *ClusterRegistry {
 	return &ClusterRegistry{
 		clusters:            make(map[string]*TargetCluster),
+		loadErrors:          make(map[string]string),
+		fieldUsage:          newFieldUsageTracker(float64(appCfg.Gateway.FieldUsageSamplePercent) / 100),
+		queryLimits:         queryLimitsFromConfig(appCfg),
+		rateLimiter:         newUserRateLimiter(appCfg),
+		cors:                newCORSPolicy(appCfg),
+		clientManager:       NewClusterClientManager(appCfg.Gateway.Clients.CacheTTL, appCfg.EnableKcp),
+		drainer:             newDrainer(),
+		operationLabels:     newOperationLabels(appCfg.Gateway.MetricsOperationNames),
 		log:                 log,
 		appCfg:              appCfg,
 		roundTripperFactory: roundTripperFactory,
 	}
 }
 
-// LoadCluster loads a target cluster from a schema file
+// LoadCluster loads a target cluster from a schema file.
+// The cluster is built and validated without holding the lock, an already loaded cluster of the same name keeps
+// serving requests until it is replaced atomically. If the schema file can't be loaded, the previous cluster is kept.
+// The changes of a reloaded schema are reported, with RefuseBreakingChanges a reload breaking existing
+// operations keeps the previous cluster as well. With schema versions, a cluster pinned to a version is loaded from
+// that version, and a cluster without previous cluster falls back to the newest version that builds.
 func (cr *ClusterRegistry) LoadCluster(schemaFilePath string) error {
-	cr.mu.Lock()
-	defer cr.mu.Unlock()
-
 	// Extract cluster name from file path, preserving subdirectory structure
 	name := cr.extractClusterNameFromPath(schemaFilePath)
 
+	loadPath := schemaFilePath
+	if cr.schemaVersions != nil {
+		if version, ok := cr.schemaVersions.PinnedVersion(name); ok {
+			loadPath = cr.schemaVersions.VersionPath(name, version)
+			cr.log.Info().Str("cluster", name).Str("version", version).Msg("Target cluster is pinned to a schema version")
+		}
+	}
+
 	cr.log.Info().
 		Str("cluster", name).
-		Str("file", schemaFilePath).
+		Str("file", loadPath).
 		Msg("Loading target cluster")
 
-	// Create or update cluster
-	cluster, err := NewTargetCluster(name, schemaFilePath, cr.log, cr.appCfg, cr.roundTripperFactory)
+	cluster, err := NewTargetCluster(name, loadPath, cr.log, cr.appCfg, cr.roundTripperFactory, cr.auditSink, cr.clientManager)
+
+	// A cluster that is served already keeps serving its schema, so only new clusters fall back to a version
+	var fallback *TargetCluster
+	if _, exists := cr.GetCluster(name); err != nil && !exists && loadPath == schemaFilePath {
+		fallback = cr.loadPreviousVersion(name, schemaFilePath)
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
 	if err != nil {
+		cr.loadErrors[name] = err.Error()
+		if _, exists := cr.clusters[name]; exists {
+			if fallback != nil {
+				fallback.Close()
+			}
+			cr.log.Warn().
+				Err(err).
+				Str("cluster", name).
+				Msg("Keeping previous schema of target cluster")
+		} else if fallback != nil {
+			// The error stays reported in the schema status, since the current schema isn't served
+			cr.clusters[name] = fallback
+		}
 		return fmt.Errorf("failed to create target cluster %s: %w", name, err)
 	}
 
-	// Store cluster
+	// The replaced cluster stops its read cache, operations still running on it read from the API server
+	if previous, exists := cr.clusters[name]; exists {
+		changes := diffSchemas(previous.graphqlSchema(), cluster.graphqlSchema())
+		reportSchemaChanges(cr.log, name, changes)
+
+		if err := breakingChangesError(changes); err != nil && cr.appCfg.Gateway.SchemaReload.RefuseBreakingChanges {
+			cluster.Close()
+			schemaReloadsRefused.WithLabelValues(name).Inc()
+			cr.loadErrors[name] = err.Error()
+			cr.log.Warn().
+				Err(err).
+				Str("cluster", name).
+				Msg("Keeping previous schema of target cluster")
+			return fmt.Errorf("failed to reload target cluster %s: %w", name, err)
+		}
+
+		previous.Close()
+	}
 	cr.clusters[name] = cluster
+	delete(cr.loadErrors, name)
 
 	return nil
 }
 
-// UpdateCluster updates an existing cluster from a schema file
-func (cr *ClusterRegistry) UpdateCluster(schemaFilePath string) error {
-	// For simplified implementation, just reload the cluster
-	err := cr.RemoveCluster(schemaFilePath)
+// loadPreviousVersion builds the cluster from the newest schema version that builds, skipping the versions equal to the
+// current schema, which failed already
+func (cr *ClusterRegistry) loadPreviousVersion(name, schemaFilePath string) *TargetCluster {
+	if cr.schemaVersions == nil {
+		return nil
+	}
+
+	versions, err := cr.schemaVersions.Versions(name)
 	if err != nil {
-		return err
+		cr.log.Error().Err(err).Str("cluster", name).Msg("Failed to list schema versions")
+		return nil
+	}
+
+	current, _ := os.ReadFile(schemaFilePath)
+	for _, version := range versions {
+		versionPath := cr.schemaVersions.VersionPath(name, version.Version)
+		if content, err := os.ReadFile(versionPath); err != nil || bytes.Equal(content, current) {
+			continue
+		}
+
+		cluster, err := NewTargetCluster(name, versionPath, cr.log, cr.appCfg, cr.roundTripperFactory, cr.auditSink, cr.clientManager)
+		if err != nil {
+			cr.log.Warn().Err(err).Str("cluster", name).Str("version", version.Version).Msg("Schema version doesn't build either")
+			continue
+		}
+
+		schemaVersionFallbacks.WithLabelValues(name).Inc()
+		cr.log.Warn().
+			Str("cluster", name).
+			Str("version", version.Version).
+			Msg("Current schema doesn't build, serving previous schema version of target cluster")
+		return cluster
 	}
 
+	return nil
+}
+
+// WithSchemaVersions loads clusters pinned to a schema version from that version, and falls back to the previous
+// versions of clusters whose current schema doesn't build. nil disables both.
+func (cr *ClusterRegistry) WithSchemaVersions(versions workspacefile.SchemaVersions) *ClusterRegistry {
+	cr.schemaVersions = versions
+	return cr
+}
+
+// UpdateCluster replaces an existing cluster with the one built from the schema file, see LoadCluster
+func (cr *ClusterRegistry) UpdateCluster(schemaFilePath string) error {
 	return cr.LoadCluster(schemaFilePath)
 }
 
@@ -97,7 +236,11 @@ func (cr *ClusterRegistry) RemoveCluster(schemaFilePath string) error {
 		Str("file", schemaFilePath).
 		Msg("Removing target cluster")
 
-	_, exists := cr.clusters[name]
+	delete(cr.loadErrors, name)
+	cr.fieldUsage.remove(name)
+	cr.clientManager.Invalidate(name)
+
+	cluster, exists := cr.clusters[name]
 	if !exists {
 		cr.log.Warn().
 			Str("cluster", name).
@@ -105,7 +248,7 @@ func (cr *ClusterRegistry) RemoveCluster(schemaFilePath string) error {
 		return nil
 	}
 
-	// Remove cluster (no cleanup needed in simplified version)
+	cluster.Close()
 	delete(cr.clusters, name)
 
 	cr.log.Info().
@@ -123,16 +266,48 @@ func (cr *ClusterRegistry) GetCluster(name string) (*TargetCluster, bool) {
 	return cluster, exists
 }
 
+// SchemaStatus returns the loaded clusters and the schema files that could not be loaded, sorted by cluster name.
+// A loaded cluster with an error still serves its previous schema.
+func (cr *ClusterRegistry) SchemaStatus() []SchemaStatus {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	statuses := make([]SchemaStatus, 0, len(cr.clusters)+len(cr.loadErrors))
+	for name, cluster := range cr.clusters {
+		statuses = append(statuses, SchemaStatus{Cluster: name, Loaded: true, Endpoint: cluster.GetEndpoint(cr.appCfg), Error: cr.loadErrors[name]})
+	}
+	for name, loadErr := range cr.loadErrors {
+		if _, loaded := cr.clusters[name]; !loaded {
+			statuses = append(statuses, SchemaStatus{Cluster: name, Error: loadErr})
+		}
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Cluster < statuses[j].Cluster
+	})
+
+	return statuses
+}
+
 // Close closes all clusters and cleans up the registry
 func (cr *ClusterRegistry) Close() error {
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
 
-	for name := range cr.clusters {
+	for name, cluster := range cr.clusters {
+		cluster.Close()
 		cr.log.Info().Str("cluster", name).Msg("Closed cluster during registry shutdown")
 	}
 
 	cr.clusters = make(map[string]*TargetCluster)
+
+	// The mutations are done, the audit events written to a file are flushed
+	if closer, ok := cr.auditSink.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			cr.log.Error().Err(err).Msg("Failed to close audit sink")
+		}
+	}
+
 	cr.log.Info().Msg("Closed cluster registry")
 	return nil
 }
@@ -144,6 +319,42 @@ func (cr *ClusterRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if cr.rejectDraining(w) {
+		return
+	}
+
+	// Route requests behind a shared ingress path as if they were served from the root
+	path, ok := TrimBasePath(r.URL.Path, cr.appCfg)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	r = withPath(r, path)
+
+	// Operators opening the gateway in a browser get the endpoints of all clusters
+	if r.Method == http.MethodGet && r.URL.Path == "/" && cr.playgroundEnabled() && wantsHTML(r) {
+		cr.serveLanding(w, r)
+		return
+	}
+
+	if clusterName, kcpWorkspace, ok := matchCapabilitiesURL(r.URL.Path, cr.appCfg); ok {
+		if kcpWorkspace != "" {
+			r = r.WithContext(context.WithValue(r.Context(), kcpWorkspaceKey, kcpWorkspace))
+		}
+		cr.serveCapabilities(w, r, clusterName)
+		return
+	}
+
+	if cr.appCfg.Gateway.AggregatedQueries && matchAggregationURL(r.URL.Path, cr.appCfg) {
+		cr.serveAggregation(w, r)
+		return
+	}
+
+	if groupName, ok := matchGroupURL(r.URL.Path, cr.appCfg); ok && len(cr.clusterGroups) > 0 {
+		cr.serveGroup(w, r, groupName)
+		return
+	}
+
 	// Extract cluster name from path
 	clusterName, r, ok := cr.extractClusterName(w, r)
 	if !ok {
@@ -163,12 +374,27 @@ func (cr *ClusterRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// No health checking in simplified version - clusters are either working or not loaded
 
+	// WebSocket connections authenticate with their first message instead of the upgrade request
+	if IsWebSocketUpgrade(r) {
+		cr.serveWebSocket(w, r, clusterName, cluster)
+		return
+	}
+
 	// Handle GET requests (GraphiQL/Playground) directly
 	if r.Method == http.MethodGet {
+		if cr.appCfg.Gateway.HandlerCfg.GraphiQL && wantsHTML(r) {
+			cr.serveGraphiQL(w, r, clusterName)
+			return
+		}
 		cluster.ServeHTTP(w, r)
 		return
 	}
 
+	cr.serveOperation(w, r, clusterName, cluster)
+}
+
+// serveOperation authenticates, limits and executes a GraphQL operation sent to a cluster and records its metrics
+func (cr *ClusterRegistry) serveOperation(w http.ResponseWriter, r *http.Request, clusterName string, cluster *TargetCluster) {
 	// Extract and validate token for non-GET requests
 	token := GetToken(r)
 	if !cr.handleAuth(w, r, token, cluster) {
@@ -178,31 +404,100 @@ func (cr *ClusterRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Set contexts for KCP and authentication
 	r = SetContexts(r, clusterName, token, cr.appCfg.EnableKcp)
 
+	operationName, err := GetOperationName(w, r, cr.appCfg.Gateway.MaxRequestBodySize)
+	operationLabel := cr.operationLabels.label(operationName)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("The request body exceeds %d bytes", maxBytesErr.Limit), http.StatusRequestEntityTooLarge)
+			operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusRequestEntityTooLarge)).Inc()
+			return
+		}
+		http.Error(w, "Failed to read the request body", http.StatusBadRequest)
+		operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusBadRequest)).Inc()
+		return
+	}
+	if cr.rejectRateLimited(w, r, clusterName) {
+		operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusTooManyRequests)).Inc()
+		return
+	}
+	if cr.rejectQueryOverLimits(w, r, clusterName, cluster) {
+		operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusBadRequest)).Inc()
+		return
+	}
+	if cr.shedLoad(w, r, clusterName, cluster) {
+		operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusServiceUnavailable)).Inc()
+		return
+	}
+	cr.recordFieldUsage(clusterName, cluster, r)
+
 	// Handle subscription requests
 	if r.Header.Get("Accept") == "text/event-stream" {
+		cr.log.Debug().
+			Str("cluster", clusterName).
+			Str("operationName", operationName).
+			Msg("Starting subscription")
+
+		r, done, ok := cr.trackSubscription(r)
+		if !ok {
+			cr.rejectDraining(w)
+			return
+		}
+		defer done()
+
+		// Subscriptions are long-lived, so only the amount of started subscriptions is recorded
+		operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusOK)).Inc()
+
 		// Subscriptions will be handled by the cluster's ServeHTTP method
 		cluster.ServeHTTP(w, r)
 		return
 	}
 
+	r, finishRecording := cr.startRecording(r, w, clusterName, cluster, operationName)
+
 	// Route to target cluster
 	cr.log.Debug().
 		Str("cluster", clusterName).
+		Str("operationName", operationName).
 		Str("method", r.Method).
 		Str("path", r.URL.Path).
 		Msg("Routing request to target cluster")
 
-	cluster.ServeHTTP(w, r)
+	start := time.Now()
+	recorder := &statusRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	cluster.ServeHTTP(recorder, r)
+	duration := time.Since(start)
+	finishRecording()
+
+	operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(recorder.statusCode)).Inc()
+	operationDuration.WithLabelValues(clusterName, operationLabel).Observe(duration.Seconds())
+
+	cr.log.Debug().
+		Str("cluster", clusterName).
+		Str("operationName", operationName).
+		Int("status", recorder.statusCode).
+		Dur("duration", duration).
+		Msg("Completed request to target cluster")
 }
 
 // handleAuth handles authentication for non-GET requests
 func (cr *ClusterRegistry) handleAuth(w http.ResponseWriter, r *http.Request, token string, cluster *TargetCluster) bool {
 	if !cr.appCfg.LocalDevelopment {
+		// Requests without a token are sent as the anonymous service account by the RoundTripper
+		if token == "" && cr.allowsAnonymous() {
+			return true
+		}
 		if token == "" {
 			http.Error(w, "Authorization header is required", http.StatusUnauthorized)
 			return false
 		}
 
+		if err := cr.verifyToken(r.Context(), token); err != nil {
+			cr.log.Debug().Err(err).Str("cluster", cluster.name).Msg("Rejecting invalid token")
+			http.Error(w, "Invalid token", http.StatusUnauthorized)
+			return false
+		}
+
 		if cr.appCfg.IntrospectionAuthentication {
 			if IsIntrospectionQuery(r) {
 				valid, err := cr.validateToken(r.Context(), token, cluster)
@@ -222,18 +517,36 @@ func (cr *ClusterRegistry) handleAuth(w http.ResponseWriter, r *http.Request, to
 	return true
 }
 
-// handleCORS handles CORS preflight requests and headers
-func (cr *ClusterRegistry) handleCORS(w http.ResponseWriter, r *http.Request) bool {
-	if cr.appCfg.Gateway.Cors.Enabled {
-		w.Header().Set("Access-Control-Allow-Origin", cr.appCfg.Gateway.Cors.AllowedOrigins)
-		w.Header().Set("Access-Control-Allow-Headers", cr.appCfg.Gateway.Cors.AllowedHeaders)
+// allowsAnonymous reports whether requests without a token are served as the anonymous service account
+func (cr *ClusterRegistry) allowsAnonymous() bool {
+	return cr.appCfg.Gateway.Anonymous.ServiceAccount != ""
+}
 
-		if r.Method == http.MethodOptions {
-			w.WriteHeader(http.StatusOK)
-			return true
-		}
+// WithTokenVerifier rejects requests whose token fails the verification of verifier with 401 before the operation is
+// executed, see roundtripper.WithTokenVerifier
+func (cr *ClusterRegistry) WithTokenVerifier(verifier *roundtripper.TokenVerifier) *ClusterRegistry {
+	cr.tokenVerifier = verifier
+	return cr
+}
+
+// WithAuditSink records the mutations of the clusters loaded afterwards to sink, nil disables the audit log
+func (cr *ClusterRegistry) WithAuditSink(sink audit.Sink) *ClusterRegistry {
+	cr.auditSink = sink
+	return cr
+}
+
+// verifyToken verifies token if a verifier is configured
+func (cr *ClusterRegistry) verifyToken(ctx context.Context, token string) error {
+	if cr.tokenVerifier == nil {
+		return nil
 	}
-	return false
+	_, err := cr.tokenVerifier.Verify(ctx, token)
+	return err
+}
+
+// handleCORS sets the CORS headers and answers preflight requests, true means the request was answered
+func (cr *ClusterRegistry) handleCORS(w http.ResponseWriter, r *http.Request) bool {
+	return cr.cors != nil && cr.cors.handle(w, r)
 }
 
 func (cr *ClusterRegistry) validateToken(ctx context.Context, token string, cluster *TargetCluster) (bool, error) {
@@ -258,11 +571,14 @@ func (cr *ClusterRegistry) validateToken(ctx context.Context, token string, clus
 		Str("provided_token", token).
 		Msg("Cluster configuration for token validation")
 
-	// Create HTTP client using the cluster's existing config and roundtripper
+	// Use the HTTP client of the cluster with its existing roundtripper and connections
 	// This ensures we use the same authentication flow as normal requests
-	httpClient, err := rest.HTTPClientFor(clusterConfig)
-	if err != nil {
-		return false, fmt.Errorf("failed to create HTTP client: %w", err)
+	httpClient := cluster.httpClient
+	if httpClient == nil {
+		var err error
+		if httpClient, err = rest.HTTPClientFor(clusterConfig); err != nil {
+			return false, fmt.Errorf("failed to create HTTP client: %w", err)
+		}
 	}
 
 	// Use namespaces endpoint for token validation - it's a resource endpoint (not discovery)
@@ -313,8 +629,20 @@ func (cr *ClusterRegistry) validateToken(ctx context.Context, token string, clus
 // Expected formats:
 //   - Regular workspace: /{clusterName}/graphql
 //   - Virtual workspace: /virtual-workspace/{virtualWorkspaceName}/{kcpWorkspace}/graphql
+//   - Namespace endpoint: /{clusterName}/namespaces/{namespace}/graphql, if namespace endpoints are enabled
+//
+// All formats may be followed by /{schemaProfile} if schema profiles are configured.
 func (cr *ClusterRegistry) extractClusterName(w http.ResponseWriter, r *http.Request) (string, *http.Request, bool) {
-	clusterName, kcpWorkspace, valid := MatchURL(r.URL.Path, cr.appCfg)
+	path, schemaProfile := r.URL.Path, ""
+	if cr.appCfg.Gateway.SchemaProfilesPath != "" {
+		path, schemaProfile = SplitSchemaProfile(path, cr.appCfg)
+	}
+	clusterName, kcpWorkspace, valid := MatchURL(path, cr.appCfg)
+
+	var namespace string
+	if !valid && cr.appCfg.Gateway.NamespaceEndpoints {
+		clusterName, namespace, valid = MatchNamespaceURL(path, cr.appCfg)
+	}
 
 	if !valid {
 		cr.log.Error().
@@ -334,9 +662,34 @@ func (cr *ClusterRegistry) extractClusterName(w http.ResponseWriter, r *http.Req
 		r = r.WithContext(context.WithValue(r.Context(), kcpWorkspaceKey, kcpWorkspace))
 	}
 
+	if schemaProfile != "" {
+		r = r.WithContext(context.WithValue(r.Context(), schemaProfileKey, schemaProfile))
+	}
+
+	// The operations of namespace endpoints are bound to the namespace by the resolvers and the round tripper
+	if namespace != "" {
+		r = r.WithContext(context.WithValue(r.Context(), roundtripper.NamespaceKey{}, namespace))
+	}
+
 	return clusterName, r, true
 }
 
+// withPath returns a shallow copy of the request with the given URL path
+func withPath(r *http.Request, path string) *http.Request {
+	if r.URL.Path == path {
+		return r
+	}
+
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+
+	return r2
+}
+
 // extractClusterNameFromPath extracts cluster name from schema file path, preserving subdirectory structure
 func (cr *ClusterRegistry) extractClusterNameFromPath(schemaFilePath string) string {
 	// First try to find relative path from definitions directory
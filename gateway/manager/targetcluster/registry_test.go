@@ -2,7 +2,9 @@ package targetcluster
 
 import (
 	"context"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
@@ -223,6 +225,40 @@ func TestExtractClusterNameWithKCPWorkspace(t *testing.T) {
 	}
 }
 
+func TestExtractClusterNameWithSchemaProfile(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+	appCfg := appConfig.Config{}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.DefaultKcpWorkspace = "root"
+	appCfg.Url.GraphqlSuffix = "graphql"
+	appCfg.Gateway.SchemaProfilesPath = "profiles.yaml"
+
+	registry := NewClusterRegistry(log, appCfg, nil)
+
+	req := httptest.NewRequest("GET", "/virtual-workspace/custom-ws/root/graphql/workloads-only", nil)
+	clusterName, modifiedReq, success := registry.extractClusterName(httptest.NewRecorder(), req)
+	if !success {
+		t.Fatal("extractClusterName() success = false, want true")
+	}
+
+	if clusterName != "virtual-workspace/custom-ws" {
+		t.Errorf("extractClusterName() clusterName = %v, want virtual-workspace/custom-ws", clusterName)
+	}
+
+	if profile := GetSchemaProfile(modifiedReq); profile != "workloads-only" {
+		t.Errorf("GetSchemaProfile() = %v, want workloads-only", profile)
+	}
+}
+
+func TestGetSchemaProfile_Header(t *testing.T) {
+	req := httptest.NewRequest("POST", "/test-cluster/graphql", nil)
+	req.Header.Set(SchemaProfileHeader, "platform")
+
+	if profile := GetSchemaProfile(req); profile != "platform" {
+		t.Errorf("GetSchemaProfile() = %v, want platform", profile)
+	}
+}
+
 func TestSetContextsWithKCPWorkspace(t *testing.T) {
 	tests := []struct {
 		name                     string
@@ -289,3 +325,86 @@ func TestSetContextsWithKCPWorkspace(t *testing.T) {
 		})
 	}
 }
+
+func TestClusterRegistry_ServeHTTPWithBasePath(t *testing.T) {
+	appCfg := appConfig.Config{}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+	appCfg.Url.BasePath = "/api/graphql-gateway"
+
+	var servedPath string
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	registry.clusters["test-cluster"] = &TargetCluster{
+		appCfg: appCfg,
+		name:   "test-cluster",
+		handler: &GraphQLHandler{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			servedPath = r.URL.Path
+		})},
+	}
+
+	t.Run("playground_links_external_path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/graphql-gateway/test-cluster/graphql", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP() status = %v, want %v", rec.Code, http.StatusOK)
+		}
+		if servedPath != "/api/graphql-gateway/test-cluster/graphql" {
+			t.Errorf("playground path = %v, want /api/graphql-gateway/test-cluster/graphql", servedPath)
+		}
+	})
+
+	t.Run("outside_base_path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test-cluster/graphql", nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("ServeHTTP() status = %v, want %v", rec.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestClusterRegistry_AnonymousServiceAccount(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceAccount string
+		token          string
+		expectedStatus int
+	}{
+		{name: "no_token_rejected", expectedStatus: http.StatusUnauthorized},
+		{name: "no_token_anonymous", serviceAccount: "public/dashboard-reader", expectedStatus: http.StatusOK},
+		{name: "token_anonymous", serviceAccount: "public/dashboard-reader", token: "user-token", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			appCfg := appConfig.Config{}
+			appCfg.Url.GraphqlSuffix = "graphql"
+			appCfg.Gateway.Anonymous.ServiceAccount = tt.serviceAccount
+
+			var servedToken any
+			registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+			registry.clusters["test-cluster"] = &TargetCluster{
+				appCfg: appCfg,
+				name:   "test-cluster",
+				handler: &GraphQLHandler{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					servedToken = r.Context().Value(roundtripper.TokenKey{})
+				})},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/test-cluster/graphql", strings.NewReader(`{"query":"{ __typename }"}`))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			rec := httptest.NewRecorder()
+			registry.ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Fatalf("ServeHTTP() status = %v, want %v", rec.Code, tt.expectedStatus)
+			}
+			if tt.expectedStatus == http.StatusOK && servedToken != tt.token {
+				t.Errorf("token in context = %v, want %q", servedToken, tt.token)
+			}
+		})
+	}
+}
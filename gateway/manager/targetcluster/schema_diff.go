@@ -0,0 +1,337 @@
+package targetcluster
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	schemaElementType  = "type"
+	schemaElementField = "field"
+
+	schemaChangeAdded   = "added"
+	schemaChangeRemoved = "removed"
+	schemaChangeChanged = "changed"
+
+	// maxReportedBreakingChanges bounds the breaking changes listed in the error of a refused reload
+	maxReportedBreakingChanges = 5
+)
+
+var ErrBreakingSchemaChange = errors.New("the reloaded schema breaks existing operations")
+
+var (
+	schemaChangesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "graphql_gateway",
+		Name:      "schema_changes_total",
+		Help:      "Types and fields added, removed or changed by schema reloads per cluster",
+	}, []string{"cluster", "element", "change", "breaking"})
+
+	schemaReloadsRefused = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "graphql_gateway",
+		Name:      "schema_reloads_refused_total",
+		Help:      "Schema reloads refused because of breaking changes per cluster",
+	}, []string{"cluster"})
+
+	schemaVersionFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "graphql_gateway",
+		Name:      "schema_version_fallbacks_total",
+		Help:      "Clusters served from a previous schema version because their current schema doesn't build, per cluster",
+	}, []string{"cluster"})
+)
+
+// SchemaChange is a difference between the schema served before a reload and the reloaded schema.
+// Changes of arguments, enum values and union members are reported as changes of their field or type.
+type SchemaChange struct {
+	Element  string
+	Change   string
+	Path     string
+	Detail   string
+	Breaking bool
+}
+
+func (c SchemaChange) String() string {
+	s := c.Element + " " + c.Path + " " + c.Change
+	if c.Detail != "" {
+		s += ": " + c.Detail
+	}
+	return s
+}
+
+// graphqlSchema returns the full schema of the cluster, nil if no handler was created
+func (tc *TargetCluster) graphqlSchema() *graphql.Schema {
+	if tc.handler == nil {
+		return nil
+	}
+	return tc.handler.Schema
+}
+
+// diffSchemas compares the types and fields of two schemas. Removing or changing something operations may rely on is
+// breaking, e.g. removing a field, changing its type or adding a required argument.
+func diffSchemas(previous, next *graphql.Schema) []SchemaChange {
+	if previous == nil || next == nil {
+		return nil
+	}
+
+	var changes []SchemaChange
+	previousTypes, nextTypes := previous.TypeMap(), next.TypeMap()
+
+	for name, previousType := range previousTypes {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		nextType, ok := nextTypes[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Element: schemaElementType, Change: schemaChangeRemoved, Path: name, Breaking: true})
+			continue
+		}
+		if previousKind, nextKind := typeKind(previousType), typeKind(nextType); previousKind != nextKind {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementType, Change: schemaChangeChanged, Path: name, Breaking: true,
+				Detail: fmt.Sprintf("kind changed from %s to %s", previousKind, nextKind),
+			})
+			continue
+		}
+
+		switch previousType := previousType.(type) {
+		case *graphql.Object:
+			changes = append(changes, diffOutputFields(name, previousType.Fields(), nextType.(*graphql.Object).Fields())...)
+		case *graphql.Interface:
+			changes = append(changes, diffOutputFields(name, previousType.Fields(), nextType.(*graphql.Interface).Fields())...)
+		case *graphql.InputObject:
+			changes = append(changes, diffInputFields(name, previousType.Fields(), nextType.(*graphql.InputObject).Fields())...)
+		case *graphql.Enum:
+			changes = append(changes, diffMembers(name, "value", enumValues(previousType), enumValues(nextType.(*graphql.Enum)))...)
+		case *graphql.Union:
+			changes = append(changes, diffMembers(name, "member", unionMembers(previousType), unionMembers(nextType.(*graphql.Union)))...)
+		}
+	}
+
+	for name := range nextTypes {
+		if _, ok := previousTypes[name]; !ok && !strings.HasPrefix(name, "__") {
+			changes = append(changes, SchemaChange{Element: schemaElementType, Change: schemaChangeAdded, Path: name})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Detail < changes[j].Detail
+	})
+	return changes
+}
+
+func diffOutputFields(typeName string, previous, next graphql.FieldDefinitionMap) []SchemaChange {
+	var changes []SchemaChange
+
+	for name, previousField := range previous {
+		path := typeName + "." + name
+		nextField, ok := next[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Element: schemaElementField, Change: schemaChangeRemoved, Path: path, Breaking: true})
+			continue
+		}
+
+		// Making an output field non-null doesn't break clients, they only receive less nulls
+		if previousType, nextType := previousField.Type.String(), nextField.Type.String(); previousType != nextType {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementField, Change: schemaChangeChanged, Path: path, Breaking: nextType != previousType+"!",
+				Detail: fmt.Sprintf("type changed from %s to %s", previousType, nextType),
+			})
+		}
+
+		previousArgs := make(map[string]*graphql.Argument, len(previousField.Args))
+		for _, arg := range previousField.Args {
+			previousArgs[arg.Name()] = arg
+		}
+		nextArgs := make(map[string]*graphql.Argument, len(nextField.Args))
+		for _, arg := range nextField.Args {
+			nextArgs[arg.Name()] = arg
+		}
+		changes = append(changes, diffInputs(path, "argument", previousArgs, nextArgs)...)
+	}
+
+	for name := range next {
+		if _, ok := previous[name]; !ok {
+			changes = append(changes, SchemaChange{Element: schemaElementField, Change: schemaChangeAdded, Path: typeName + "." + name})
+		}
+	}
+
+	return changes
+}
+
+func diffInputFields(typeName string, previous, next graphql.InputObjectFieldMap) []SchemaChange {
+	var changes []SchemaChange
+
+	toArguments := func(fields graphql.InputObjectFieldMap) map[string]*graphql.Argument {
+		args := make(map[string]*graphql.Argument, len(fields))
+		for name, field := range fields {
+			args[name] = &graphql.Argument{PrivateName: name, Type: field.Type, DefaultValue: field.DefaultValue}
+		}
+		return args
+	}
+	previousFields, nextFields := toArguments(previous), toArguments(next)
+
+	for name, previousField := range previousFields {
+		path := typeName + "." + name
+		nextField, ok := nextFields[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Element: schemaElementField, Change: schemaChangeRemoved, Path: path, Breaking: true})
+			continue
+		}
+		changes = append(changes, diffInputs(path, "", map[string]*graphql.Argument{name: previousField}, map[string]*graphql.Argument{name: nextField})...)
+	}
+
+	for name, nextField := range nextFields {
+		if _, ok := previousFields[name]; !ok {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementField, Change: schemaChangeAdded, Path: typeName + "." + name, Breaking: isRequiredInput(nextField),
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffInputs compares the arguments of a field, or a single input field if kind is empty. Making an input optional
+// doesn't break clients, making it required or adding a required input does.
+func diffInputs(path, kind string, previous, next map[string]*graphql.Argument) []SchemaChange {
+	var changes []SchemaChange
+	describe := func(name, detail string) string {
+		if kind == "" {
+			return detail
+		}
+		return kind + " " + name + " " + detail
+	}
+
+	for name, previousArg := range previous {
+		nextArg, ok := next[name]
+		if !ok {
+			changes = append(changes, SchemaChange{Element: schemaElementField, Change: schemaChangeChanged, Path: path, Breaking: true, Detail: describe(name, "removed")})
+			continue
+		}
+		if previousType, nextType := previousArg.Type.String(), nextArg.Type.String(); previousType != nextType {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementField, Change: schemaChangeChanged, Path: path, Breaking: previousType != nextType+"!",
+				Detail: describe(name, fmt.Sprintf("type changed from %s to %s", previousType, nextType)),
+			})
+		}
+	}
+
+	for name, nextArg := range next {
+		if _, ok := previous[name]; !ok {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementField, Change: schemaChangeChanged, Path: path, Breaking: isRequiredInput(nextArg),
+				Detail: describe(name, "added"),
+			})
+		}
+	}
+
+	return changes
+}
+
+// diffMembers compares the values of an enum or the members of a union, removing one is breaking
+func diffMembers(typeName, kind string, previous, next []string) []SchemaChange {
+	var changes []SchemaChange
+
+	for _, name := range previous {
+		if !slices.Contains(next, name) {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementType, Change: schemaChangeChanged, Path: typeName, Breaking: true, Detail: kind + " " + name + " removed",
+			})
+		}
+	}
+	for _, name := range next {
+		if !slices.Contains(previous, name) {
+			changes = append(changes, SchemaChange{
+				Element: schemaElementType, Change: schemaChangeChanged, Path: typeName, Detail: kind + " " + name + " added",
+			})
+		}
+	}
+
+	return changes
+}
+
+func isRequiredInput(arg *graphql.Argument) bool {
+	_, nonNull := arg.Type.(*graphql.NonNull)
+	return nonNull && arg.DefaultValue == nil
+}
+
+func typeKind(t graphql.Type) string {
+	switch t.(type) {
+	case *graphql.Object:
+		return "OBJECT"
+	case *graphql.Interface:
+		return "INTERFACE"
+	case *graphql.InputObject:
+		return "INPUT_OBJECT"
+	case *graphql.Enum:
+		return "ENUM"
+	case *graphql.Union:
+		return "UNION"
+	default:
+		return "SCALAR"
+	}
+}
+
+func enumValues(enum *graphql.Enum) []string {
+	values := make([]string, 0, len(enum.Values()))
+	for _, value := range enum.Values() {
+		values = append(values, value.Name)
+	}
+	return values
+}
+
+func unionMembers(union *graphql.Union) []string {
+	members := make([]string, 0, len(union.Types()))
+	for _, member := range union.Types() {
+		members = append(members, member.Name())
+	}
+	return members
+}
+
+// reportSchemaChanges logs and counts the changes of a schema reload, breaking changes are logged as warnings
+func reportSchemaChanges(log *logger.Logger, cluster string, changes []SchemaChange) {
+	breaking := 0
+	for _, change := range changes {
+		schemaChangesTotal.WithLabelValues(cluster, change.Element, change.Change, strconv.FormatBool(change.Breaking)).Inc()
+		if change.Breaking {
+			breaking++
+			log.Warn().Str("cluster", cluster).Str("change", change.String()).Msg("Breaking schema change")
+		} else {
+			log.Debug().Str("cluster", cluster).Str("change", change.String()).Msg("Schema change")
+		}
+	}
+
+	if len(changes) > 0 {
+		log.Info().Str("cluster", cluster).Int("changes", len(changes)).Int("breaking", breaking).Msg("Schema of target cluster changed")
+	}
+}
+
+// breakingChangesError returns an error listing the first breaking changes, or nil if no change is breaking
+func breakingChangesError(changes []SchemaChange) error {
+	var breaking []string
+	for _, change := range changes {
+		if change.Breaking {
+			breaking = append(breaking, change.String())
+		}
+	}
+	if len(breaking) == 0 {
+		return nil
+	}
+
+	if len(breaking) > maxReportedBreakingChanges {
+		more := len(breaking) - maxReportedBreakingChanges
+		breaking = append(breaking[:maxReportedBreakingChanges], fmt.Sprintf("and %d more", more))
+	}
+	return fmt.Errorf("%w: %s", ErrBreakingSchemaChange, strings.Join(breaking, ", "))
+}
@@ -0,0 +1,119 @@
+package targetcluster
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSchemaDiffTestSchema builds a schema with a Pod type, a pods query taking args and a PodInput input type
+func newSchemaDiffTestSchema(t *testing.T, podFields graphql.Fields, args graphql.FieldConfigArgument, inputFields graphql.InputObjectConfigFieldMap) *graphql.Schema {
+	t.Helper()
+
+	pod := graphql.NewObject(graphql.ObjectConfig{Name: "Pod", Fields: podFields})
+	input := graphql.NewInputObject(graphql.InputObjectConfig{Name: "PodInput", Fields: inputFields})
+	args["object"] = &graphql.ArgumentConfig{Type: input}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+			"pods": &graphql.Field{Type: graphql.NewList(pod), Args: args},
+		}}),
+	})
+	require.NoError(t, err)
+	return &schema
+}
+
+func TestDiffSchemas(t *testing.T) {
+	previous := newSchemaDiffTestSchema(t,
+		graphql.Fields{"name": {Type: graphql.String}, "node": {Type: graphql.String}},
+		graphql.FieldConfigArgument{"namespace": {Type: graphql.NewNonNull(graphql.String)}},
+		graphql.InputObjectConfigFieldMap{"name": {Type: graphql.String}},
+	)
+
+	tests := []struct {
+		name     string
+		next     *graphql.Schema
+		expected []SchemaChange
+	}{
+		{
+			name: "unchanged",
+			next: newSchemaDiffTestSchema(t,
+				graphql.Fields{"name": {Type: graphql.String}, "node": {Type: graphql.String}},
+				graphql.FieldConfigArgument{"namespace": {Type: graphql.NewNonNull(graphql.String)}},
+				graphql.InputObjectConfigFieldMap{"name": {Type: graphql.String}},
+			),
+		},
+		{
+			name: "compatible_changes",
+			next: newSchemaDiffTestSchema(t,
+				graphql.Fields{"name": {Type: graphql.NewNonNull(graphql.String)}, "node": {Type: graphql.String}, "phase": {Type: graphql.String}},
+				graphql.FieldConfigArgument{"namespace": {Type: graphql.String}, "watch": {Type: graphql.Boolean}},
+				graphql.InputObjectConfigFieldMap{"name": {Type: graphql.String}, "labels": {Type: graphql.String}},
+			),
+			expected: []SchemaChange{
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "Pod.name", Detail: "type changed from String to String!"},
+				{Element: schemaElementField, Change: schemaChangeAdded, Path: "Pod.phase"},
+				{Element: schemaElementField, Change: schemaChangeAdded, Path: "PodInput.labels"},
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "Query.pods", Detail: "argument namespace type changed from String! to String"},
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "Query.pods", Detail: "argument watch added"},
+			},
+		},
+		{
+			name: "breaking_changes",
+			next: newSchemaDiffTestSchema(t,
+				graphql.Fields{"name": {Type: graphql.Boolean}},
+				graphql.FieldConfigArgument{"namespace": {Type: graphql.NewNonNull(graphql.String)}, "cluster": {Type: graphql.NewNonNull(graphql.String)}},
+				graphql.InputObjectConfigFieldMap{"name": {Type: graphql.NewNonNull(graphql.String)}},
+			),
+			expected: []SchemaChange{
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "Pod.name", Breaking: true, Detail: "type changed from String to Boolean"},
+				{Element: schemaElementField, Change: schemaChangeRemoved, Path: "Pod.node", Breaking: true},
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "PodInput.name", Breaking: true, Detail: "type changed from String to String!"},
+				{Element: schemaElementField, Change: schemaChangeChanged, Path: "Query.pods", Breaking: true, Detail: "argument cluster added"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, diffSchemas(previous, tt.next))
+		})
+	}
+}
+
+func TestDiffSchemas_Types(t *testing.T) {
+	newSchema := func(types ...graphql.Type) *graphql.Schema {
+		schema, err := graphql.NewSchema(graphql.SchemaConfig{
+			Query: graphql.NewObject(graphql.ObjectConfig{Name: "Query", Fields: graphql.Fields{
+				"version": &graphql.Field{Type: graphql.String},
+			}}),
+			Types: types,
+		})
+		require.NoError(t, err)
+		return &schema
+	}
+	phase := func(values ...string) *graphql.Enum {
+		config := graphql.EnumValueConfigMap{}
+		for _, value := range values {
+			config[value] = &graphql.EnumValueConfig{Value: value}
+		}
+		return graphql.NewEnum(graphql.EnumConfig{Name: "Phase", Values: config})
+	}
+
+	changes := diffSchemas(newSchema(phase("Running", "Failed")), newSchema(phase("Running", "Pending")))
+	assert.Equal(t, []SchemaChange{
+		{Element: schemaElementType, Change: schemaChangeChanged, Path: "Phase", Breaking: true, Detail: "value Failed removed"},
+		{Element: schemaElementType, Change: schemaChangeChanged, Path: "Phase", Detail: "value Pending added"},
+	}, changes)
+
+	changes = diffSchemas(newSchema(phase("Running")), newSchema())
+	assert.Equal(t, []SchemaChange{{Element: schemaElementType, Change: schemaChangeRemoved, Path: "Phase", Breaking: true}}, changes)
+	assert.ErrorIs(t, breakingChangesError(changes), ErrBreakingSchemaChange)
+	assert.EqualError(t, breakingChangesError(changes), "the reloaded schema breaks existing operations: type Phase removed")
+
+	changes = diffSchemas(newSchema(), newSchema(phase("Running")))
+	assert.Equal(t, []SchemaChange{{Element: schemaElementType, Change: schemaChangeAdded, Path: "Phase"}}, changes)
+	assert.NoError(t, breakingChangesError(changes))
+}
@@ -0,0 +1,145 @@
+package targetcluster_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/targetcluster"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+func TestClusterRegistry_RejectsIncompatibleSchema(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "future-cluster")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{
+		"definitions": {},
+		"x-cluster-metadata": {"host": "https://example.com"},
+		"x-schema-requirements": {"minGatewayCapabilityVersion": 999, "listenerCapabilityVersion": 999}
+	}`), 0o600))
+
+	registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appConfig.Config{}, nil)
+
+	err := registry.LoadCluster(schemaFile)
+	assert.ErrorIs(t, err, common.ErrIncompatibleSchema)
+
+	statuses := registry.SchemaStatus()
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "future-cluster", statuses[0].Cluster)
+	assert.False(t, statuses[0].Loaded)
+	assert.Contains(t, statuses[0].Error, common.ErrIncompatibleSchema.Error())
+
+	require.NoError(t, registry.RemoveCluster(schemaFile))
+	assert.Empty(t, registry.SchemaStatus())
+}
+
+func TestClusterRegistry_UpdateKeepsPreviousSchema(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "cluster")
+	validSchema := []byte(`{
+		"definitions": {
+			"io.k8s.api.core.v1.ConfigMap": {
+				"type": "object",
+				"properties": {"data": {"type": "object", "additionalProperties": {"type": "string"}}},
+				"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "ConfigMap"}],
+				"x-kubernetes-scope": "Namespaced"
+			}
+		},
+		"x-cluster-metadata": {"host": "https://example.com"}
+	}`)
+	require.NoError(t, os.WriteFile(schemaFile, validSchema, 0o600))
+
+	appCfg := targetcluster.CreateTestConfig(false, "8080")
+	appCfg.Url.ExternalURL = "https://portal.example.com/api/graphql-gateway"
+	registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	require.NoError(t, registry.LoadCluster(schemaFile))
+
+	previous, ok := registry.GetCluster("cluster")
+	require.True(t, ok)
+
+	require.NoError(t, os.WriteFile(schemaFile, []byte(`{"definitions": `), 0o600))
+	assert.Error(t, registry.UpdateCluster(schemaFile))
+
+	current, ok := registry.GetCluster("cluster")
+	require.True(t, ok)
+	assert.Same(t, previous, current)
+
+	statuses := registry.SchemaStatus()
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Loaded)
+	assert.NotEmpty(t, statuses[0].Error)
+
+	require.NoError(t, os.WriteFile(schemaFile, validSchema, 0o600))
+	require.NoError(t, registry.UpdateCluster(schemaFile))
+
+	current, ok = registry.GetCluster("cluster")
+	require.True(t, ok)
+	assert.NotSame(t, previous, current)
+	assert.Equal(t, []targetcluster.SchemaStatus{{
+		Cluster:  "cluster",
+		Loaded:   true,
+		Endpoint: "https://portal.example.com/api/graphql-gateway/cluster/graphql",
+	}}, registry.SchemaStatus())
+}
+
+func TestClusterRegistry_SchemaVersions(t *testing.T) {
+	schemasDir := filepath.Join(t.TempDir(), "definitions")
+	versions, err := workspacefile.NewIOHandler(schemasDir, workspacefile.WithVersions(workspacefile.VersionsDir(schemasDir, ""), 3))
+	require.NoError(t, err)
+
+	validSchema := []byte(`{
+		"definitions": {
+			"io.k8s.api.core.v1.ConfigMap": {
+				"type": "object",
+				"properties": {"data": {"type": "object", "additionalProperties": {"type": "string"}}},
+				"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "ConfigMap"}],
+				"x-kubernetes-scope": "Namespaced"
+			}
+		},
+		"x-cluster-metadata": {"host": "https://example.com"}
+	}`)
+	require.NoError(t, versions.Write(validSchema, "cluster"))
+	require.NoError(t, versions.Write([]byte(`{"definitions": `), "cluster"))
+	schemaFile := filepath.Join(schemasDir, "cluster")
+
+	t.Run("falls_back_to_previous_version", func(t *testing.T) {
+		registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, targetcluster.CreateTestConfig(false, "8080"), nil).
+			WithSchemaVersions(versions)
+
+		assert.Error(t, registry.LoadCluster(schemaFile), "the current schema doesn't build")
+		_, ok := registry.GetCluster("cluster")
+		assert.True(t, ok)
+
+		statuses := registry.SchemaStatus()
+		require.Len(t, statuses, 1)
+		assert.True(t, statuses[0].Loaded)
+		assert.NotEmpty(t, statuses[0].Error)
+	})
+
+	t.Run("pinned_version", func(t *testing.T) {
+		stored, err := versions.Versions("cluster")
+		require.NoError(t, err)
+		require.Len(t, stored, 2)
+		require.NoError(t, versions.Pin("cluster", stored[1].Version))
+		t.Cleanup(func() { require.NoError(t, versions.Unpin("cluster")) })
+
+		registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, targetcluster.CreateTestConfig(false, "8080"), nil).
+			WithSchemaVersions(versions)
+
+		require.NoError(t, registry.LoadCluster(schemaFile))
+		_, ok := registry.GetCluster("cluster")
+		assert.True(t, ok)
+	})
+
+	t.Run("without_versions", func(t *testing.T) {
+		registry := targetcluster.NewClusterRegistry(testlogger.New().HideLogOutput().Logger, targetcluster.CreateTestConfig(false, "8080"), nil)
+
+		assert.Error(t, registry.LoadCluster(schemaFile))
+		_, ok := registry.GetCluster("cluster")
+		assert.False(t, ok)
+	})
+}
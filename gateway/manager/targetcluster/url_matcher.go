@@ -2,6 +2,8 @@ package targetcluster
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
 	"strings"
 
 	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
@@ -33,6 +35,89 @@ func MatchURL(path string, appCfg config.Config) (clusterName string, kcpWorkspa
 	return "", "", false
 }
 
+// NamespacesSegment separates the cluster and the namespace of namespace endpoints, e.g. /{clusterName}/namespaces/{namespace}/graphql
+const NamespacesSegment = "namespaces"
+
+var namespaceNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// MatchNamespaceURL matches the endpoints bound to a namespace: /{clusterName}/namespaces/{namespace}/graphql
+func MatchNamespaceURL(path string, appCfg config.Config) (clusterName string, namespace string, valid bool) {
+	pattern := fmt.Sprintf("/{clusterName}/%s/{namespace}/%s", NamespacesSegment, appCfg.Url.GraphqlSuffix)
+	vars := matchPattern(pattern, path)
+	if vars == nil || vars["clusterName"] == "" || len(vars["namespace"]) > 63 || !namespaceNameRegex.MatchString(vars["namespace"]) {
+		return "", "", false
+	}
+
+	return vars["clusterName"], vars["namespace"], true
+}
+
+// SplitSchemaProfile splits a trailing schema profile off the path, e.g. /{clusterName}/graphql/{schemaProfile}.
+// The path is returned unchanged if it does not end with a schema profile.
+func SplitSchemaProfile(path string, appCfg config.Config) (string, string) {
+	trimmed := strings.TrimSuffix(path, "/")
+	idx := strings.LastIndex(trimmed, "/")
+	if idx <= 0 {
+		return path, ""
+	}
+
+	prefix, profile := trimmed[:idx], trimmed[idx+1:]
+	if profile == "" || profile == appCfg.Url.GraphqlSuffix || !strings.HasSuffix(prefix, "/"+appCfg.Url.GraphqlSuffix) {
+		return path, ""
+	}
+
+	return prefix, profile
+}
+
+// TrimBasePath removes the configured base path from the path of an incoming request.
+// ok is false if the path is not below the base path.
+func TrimBasePath(path string, appCfg config.Config) (trimmed string, ok bool) {
+	basePath := normalizeBasePath(appCfg.Url.BasePath)
+	if basePath == "" {
+		return path, true
+	}
+	if path == basePath {
+		return "/", true
+	}
+
+	trimmed, ok = strings.CutPrefix(path, basePath+"/")
+	if !ok {
+		return "", false
+	}
+
+	return "/" + trimmed, true
+}
+
+// ExternalURL returns the URL under which clients reach the given gateway path.
+// It is relative to the host unless an external URL is configured.
+func ExternalURL(path string, appCfg config.Config) string {
+	if appCfg.Url.ExternalURL != "" {
+		return strings.TrimSuffix(appCfg.Url.ExternalURL, "/") + path
+	}
+
+	return normalizeBasePath(appCfg.Url.BasePath) + path
+}
+
+// ExternalPath returns the path under which clients reach the given gateway path, without scheme and host
+func ExternalPath(path string, appCfg config.Config) string {
+	if appCfg.Url.ExternalURL != "" {
+		if u, err := url.Parse(appCfg.Url.ExternalURL); err == nil {
+			return strings.TrimSuffix(u.Path, "/") + path
+		}
+	}
+
+	return normalizeBasePath(appCfg.Url.BasePath) + path
+}
+
+// normalizeBasePath returns the base path with a leading and without a trailing slash, or "" if there is none
+func normalizeBasePath(basePath string) string {
+	basePath = strings.Trim(basePath, "/")
+	if basePath == "" {
+		return ""
+	}
+
+	return "/" + basePath
+}
+
 // matchPattern matches a path against a pattern and extracts variables
 func matchPattern(pattern, path string) map[string]string {
 	patternParts := strings.Split(strings.Trim(pattern, "/"), "/")
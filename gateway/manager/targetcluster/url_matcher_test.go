@@ -86,3 +86,232 @@ func TestMatchURL(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchNamespaceURL(t *testing.T) {
+	tests := []struct {
+		name              string
+		path              string
+		expectedCluster   string
+		expectedNamespace string
+		expectedValid     bool
+	}{
+		{
+			name:              "namespace_endpoint",
+			path:              "/test-cluster/namespaces/team-a/graphql",
+			expectedCluster:   "test-cluster",
+			expectedNamespace: "team-a",
+			expectedValid:     true,
+		},
+		{
+			name: "cluster_endpoint",
+			path: "/test-cluster/graphql",
+		},
+		{
+			name: "invalid_namespace",
+			path: "/test-cluster/namespaces/Team_A/graphql",
+		},
+		{
+			name: "other_segment",
+			path: "/test-cluster/tenants/team-a/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{}
+			cfg.Url.GraphqlSuffix = "graphql"
+
+			clusterName, namespace, valid := targetcluster.MatchNamespaceURL(tt.path, cfg)
+
+			if valid != tt.expectedValid {
+				t.Errorf("MatchNamespaceURL() valid = %v, want %v", valid, tt.expectedValid)
+				return
+			}
+
+			if clusterName != tt.expectedCluster {
+				t.Errorf("MatchNamespaceURL() clusterName = %v, want %v", clusterName, tt.expectedCluster)
+			}
+
+			if namespace != tt.expectedNamespace {
+				t.Errorf("MatchNamespaceURL() namespace = %v, want %v", namespace, tt.expectedNamespace)
+			}
+		})
+	}
+}
+
+func TestSplitSchemaProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		path            string
+		expectedPath    string
+		expectedProfile string
+	}{
+		{
+			name:            "regular_workspace_without_profile",
+			path:            "/test-cluster/graphql",
+			expectedPath:    "/test-cluster/graphql",
+			expectedProfile: "",
+		},
+		{
+			name:            "regular_workspace_with_profile",
+			path:            "/test-cluster/graphql/workloads-only",
+			expectedPath:    "/test-cluster/graphql",
+			expectedProfile: "workloads-only",
+		},
+		{
+			name:            "virtual_workspace_with_profile",
+			path:            "/virtual-workspace/my-workspace/root/graphql/platform",
+			expectedPath:    "/virtual-workspace/my-workspace/root/graphql",
+			expectedProfile: "platform",
+		},
+		{
+			name:            "trailing_slash",
+			path:            "/test-cluster/graphql/platform/",
+			expectedPath:    "/test-cluster/graphql",
+			expectedProfile: "platform",
+		},
+		{
+			name:            "cluster_named_like_suffix",
+			path:            "/graphql/graphql",
+			expectedPath:    "/graphql/graphql",
+			expectedProfile: "",
+		},
+		{
+			name:            "unrelated_path",
+			path:            "/test-cluster/api/platform",
+			expectedPath:    "/test-cluster/api/platform",
+			expectedProfile: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{}
+			cfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+			cfg.Url.GraphqlSuffix = "graphql"
+
+			path, profile := targetcluster.SplitSchemaProfile(tt.path, cfg)
+
+			if path != tt.expectedPath {
+				t.Errorf("SplitSchemaProfile() path = %v, want %v", path, tt.expectedPath)
+			}
+
+			if profile != tt.expectedProfile {
+				t.Errorf("SplitSchemaProfile() profile = %v, want %v", profile, tt.expectedProfile)
+			}
+		})
+	}
+}
+
+func TestTrimBasePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		basePath     string
+		path         string
+		expectedPath string
+		expectedOk   bool
+	}{
+		{
+			name:         "no_base_path",
+			basePath:     "",
+			path:         "/test-cluster/graphql",
+			expectedPath: "/test-cluster/graphql",
+			expectedOk:   true,
+		},
+		{
+			name:         "below_base_path",
+			basePath:     "/api/graphql-gateway",
+			path:         "/api/graphql-gateway/test-cluster/graphql",
+			expectedPath: "/test-cluster/graphql",
+			expectedOk:   true,
+		},
+		{
+			name:         "base_path_without_slashes",
+			basePath:     "api/graphql-gateway/",
+			path:         "/api/graphql-gateway/test-cluster/graphql",
+			expectedPath: "/test-cluster/graphql",
+			expectedOk:   true,
+		},
+		{
+			name:         "base_path_itself",
+			basePath:     "/api/graphql-gateway",
+			path:         "/api/graphql-gateway",
+			expectedPath: "/",
+			expectedOk:   true,
+		},
+		{
+			name:       "outside_base_path",
+			basePath:   "/api/graphql-gateway",
+			path:       "/test-cluster/graphql",
+			expectedOk: false,
+		},
+		{
+			name:       "base_path_as_name_prefix",
+			basePath:   "/api/graphql-gateway",
+			path:       "/api/graphql-gateway-v2/test-cluster/graphql",
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{}
+			cfg.Url.BasePath = tt.basePath
+
+			path, ok := targetcluster.TrimBasePath(tt.path, cfg)
+
+			if ok != tt.expectedOk {
+				t.Errorf("TrimBasePath() ok = %v, want %v", ok, tt.expectedOk)
+			}
+
+			if path != tt.expectedPath {
+				t.Errorf("TrimBasePath() path = %v, want %v", path, tt.expectedPath)
+			}
+		})
+	}
+}
+
+func TestExternalURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		basePath     string
+		externalURL  string
+		expectedURL  string
+		expectedPath string
+	}{
+		{
+			name:         "default",
+			expectedURL:  "/test-cluster/graphql",
+			expectedPath: "/test-cluster/graphql",
+		},
+		{
+			name:         "base_path",
+			basePath:     "/api/graphql-gateway",
+			expectedURL:  "/api/graphql-gateway/test-cluster/graphql",
+			expectedPath: "/api/graphql-gateway/test-cluster/graphql",
+		},
+		{
+			name:         "external_url",
+			basePath:     "/graphql-gateway",
+			externalURL:  "https://portal.example.com/api/graphql-gateway/",
+			expectedURL:  "https://portal.example.com/api/graphql-gateway/test-cluster/graphql",
+			expectedPath: "/api/graphql-gateway/test-cluster/graphql",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Config{}
+			cfg.Url.BasePath = tt.basePath
+			cfg.Url.ExternalURL = tt.externalURL
+
+			if got := targetcluster.ExternalURL("/test-cluster/graphql", cfg); got != tt.expectedURL {
+				t.Errorf("ExternalURL() = %v, want %v", got, tt.expectedURL)
+			}
+
+			if got := targetcluster.ExternalPath("/test-cluster/graphql", cfg); got != tt.expectedPath {
+				t.Errorf("ExternalPath() = %v, want %v", got, tt.expectedPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package targetcluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+var ErrSchemaValidation = errors.New("schema validation failed")
+
+const (
+	// canaryIntrospectionQuery resolves the type system, like the introspection queries of GraphQL clients
+	canaryIntrospectionQuery = `{ __schema { queryType { name } mutationType { name } subscriptionType { name } types { name kind fields { name } } } }`
+	// canaryResolverQuery runs a resolver that does not send requests to the cluster
+	canaryResolverQuery = `{ clusterDefaults { defaultNamespace commonKinds } }`
+)
+
+// validate runs the canary queries against every schema of the cluster,
+// so that a schema that can't be served never replaces a working one
+func (tc *TargetCluster) validate() error {
+	if err := validateSchema(tc.handler.Schema); err != nil {
+		return err
+	}
+
+	for profile, handler := range tc.profileHandlers {
+		if err := validateSchema(handler.Schema); err != nil {
+			return fmt.Errorf("profile %s: %w", profile, err)
+		}
+	}
+
+	return nil
+}
+
+func validateSchema(schema *graphql.Schema) error {
+	for _, query := range []string{canaryIntrospectionQuery, canaryResolverQuery} {
+		result := graphql.Do(graphql.Params{
+			Schema:        *schema,
+			RequestString: query,
+			Context:       context.Background(),
+		})
+		if result.HasErrors() {
+			return fmt.Errorf("%w: %v", ErrSchemaValidation, result.Errors)
+		}
+	}
+
+	return nil
+}
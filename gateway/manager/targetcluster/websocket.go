@@ -0,0 +1,492 @@
+package targetcluster
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"golang.org/x/net/websocket"
+
+	"github.com/openmfp/golang-commons/logger"
+)
+
+// GraphQLTransportWSProtocol is the WebSocket subprotocol of https://github.com/enisdenjo/graphql-ws/blob/master/PROTOCOL.md
+const GraphQLTransportWSProtocol = "graphql-transport-ws"
+
+// connectionInitTimeout is how long a client may take to send connection_init after the connection was opened
+const connectionInitTimeout = 10 * time.Second
+
+// Message types of the graphql-transport-ws protocol
+const (
+	wsConnectionInit = "connection_init"
+	wsConnectionAck  = "connection_ack"
+	wsPing           = "ping"
+	wsPong           = "pong"
+	wsSubscribe      = "subscribe"
+	wsNext           = "next"
+	wsError          = "error"
+	wsComplete       = "complete"
+)
+
+// Close codes of the graphql-transport-ws protocol
+const (
+	wsCloseInvalidMessage      = 4400
+	wsCloseUnauthorized        = 4401
+	wsCloseForbidden           = 4403
+	wsCloseInitTimeout         = 4408
+	wsCloseSubscriberExists    = 4409
+	wsCloseTooManyInitRequests = 4429
+)
+
+var (
+	ErrUnsupportedSubprotocol = errors.New("unsupported WebSocket subprotocol, only " + GraphQLTransportWSProtocol + " is supported")
+	ErrOriginNotAllowed       = errors.New("origin not allowed by CORS")
+)
+
+// wsMessage is a message of the graphql-transport-ws protocol
+type wsMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+type wsSubscribePayload struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// IsWebSocketUpgrade reports whether the request opens a WebSocket connection
+func IsWebSocketUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// serveWebSocket serves the operations of a WebSocket connection with the graphql-transport-ws protocol.
+// Browsers can't set headers on WebSocket connections, so the token is read from the Authorization key of the
+// connection_init payload, falling back to the Authorization header of the upgrade request.
+func (cr *ClusterRegistry) serveWebSocket(w http.ResponseWriter, r *http.Request, clusterName string, cluster *TargetCluster) {
+	if cluster.handler == nil {
+		http.Error(w, "Cluster not ready", http.StatusServiceUnavailable)
+		return
+	}
+
+	profile := GetSchemaProfile(r)
+	handler, ok := cluster.handlerFor(profile)
+	if !ok {
+		http.Error(w, fmt.Sprintf("Unknown schema profile %q", profile), http.StatusNotFound)
+		return
+	}
+
+	server := websocket.Server{
+		// Browsers don't apply CORS to WebSockets, so with CORS enabled the origin of browsers must be allowed.
+		// Clients that are no browsers don't send an origin.
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			if origin := req.Header.Get("Origin"); cr.cors != nil && origin != "" && !cr.cors.allowsOrigin(origin) {
+				return fmt.Errorf("%w: %s", ErrOriginNotAllowed, origin)
+			}
+			if !slices.Contains(config.Protocol, GraphQLTransportWSProtocol) {
+				return ErrUnsupportedSubprotocol
+			}
+			config.Protocol = []string{GraphQLTransportWSProtocol}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			done, ok := cr.drainer.track()
+			if !ok {
+				closeWebSocket(conn, cr.log, wsCloseGoingAway, "Gateway is shutting down")
+				return
+			}
+			defer done()
+
+			session := &wsSession{
+				conn:          conn,
+				log:           cr.log,
+				schema:        handler.Schema,
+				coerce:        handler.CoerceVariables,
+				keepAlive:     cr.appCfg.Gateway.WebSocketKeepAlive,
+				shutdown:      cr.drainer.ctx,
+				queryLimits:   cr.queryLimits,
+				subscriptions: make(map[string]context.CancelFunc),
+				authorize: func(token string) (context.Context, error) {
+					if token == "" {
+						token = GetToken(r)
+					}
+					if !cr.appCfg.LocalDevelopment && token == "" && !cr.allowsAnonymous() {
+						return nil, errors.New("authorization is required")
+					}
+					if !cr.appCfg.LocalDevelopment && token != "" {
+						if err := cr.verifyToken(r.Context(), token); err != nil {
+							return nil, err
+						}
+					}
+					return SetContexts(r, clusterName, token, cr.appCfg.EnableKcp).Context(), nil
+				},
+				rateLimit: func(ctx context.Context) *rateLimitError {
+					return cr.checkRateLimit(ctx, clusterName)
+				},
+				onOperation: func(name string) {
+					// Subscriptions are long-lived, so only the amount of started operations is recorded
+					operationLabel := cr.operationLabels.label(operationName(name, ""))
+					operationsTotal.WithLabelValues(clusterName, operationLabel, strconv.Itoa(http.StatusOK)).Inc()
+				},
+			}
+			session.serve()
+		},
+	}
+
+	cr.log.Debug().Str("cluster", clusterName).Msg("Opening WebSocket connection")
+	server.ServeHTTP(w, r)
+}
+
+// wsSession is a WebSocket connection of a client
+type wsSession struct {
+	conn        *websocket.Conn
+	log         *logger.Logger
+	schema      *graphql.Schema
+	coerce      func(query, operationName string, variables map[string]any) error
+	keepAlive   time.Duration
+	queryLimits queryLimits
+	authorize   func(token string) (context.Context, error)
+	rateLimit   func(ctx context.Context) *rateLimitError
+	onOperation func(operationName string)
+	// shutdown is cancelled when the gateway drains, the subscriptions are then completed and the connection closed once
+	// the running operations finished
+	shutdown context.Context
+
+	// ctx carries the token of the connection, it is set once the connection was acknowledged
+	ctx context.Context
+
+	writeMu sync.Mutex
+	closed  bool
+
+	mu            sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	draining      bool
+	wg            sync.WaitGroup
+}
+
+func (s *wsSession) serve() {
+	defer s.conn.Close()
+
+	ctx, cancel := context.WithCancel(s.conn.Request().Context())
+	defer func() {
+		cancel()
+		s.wg.Wait()
+	}()
+
+	initTimer := time.AfterFunc(connectionInitTimeout, func() {
+		s.close(wsCloseInitTimeout, "Connection initialisation timeout")
+	})
+	defer initTimer.Stop()
+
+	stopDrain := context.AfterFunc(s.shutdown, s.drain)
+	defer stopDrain()
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(s.conn, &data); err != nil {
+			return
+		}
+
+		var msg wsMessage
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			s.close(wsCloseInvalidMessage, "Invalid message received")
+			return
+		}
+
+		switch msg.Type {
+		case wsConnectionInit:
+			if s.ctx != nil {
+				s.close(wsCloseTooManyInitRequests, "Too many initialisation requests")
+				return
+			}
+			initTimer.Stop()
+
+			connCtx, err := s.authorize(tokenFromPayload(msg.Payload))
+			if err != nil {
+				s.close(wsCloseForbidden, "Forbidden")
+				return
+			}
+			s.ctx = withParentCancel(connCtx, ctx)
+
+			s.send(wsMessage{Type: wsConnectionAck})
+			if s.keepAlive > 0 {
+				go s.ping(ctx)
+			}
+		case wsPing:
+			s.send(wsMessage{Type: wsPong, Payload: msg.Payload})
+		case wsPong:
+		case wsSubscribe:
+			if s.ctx == nil {
+				s.close(wsCloseUnauthorized, "Unauthorized")
+				return
+			}
+
+			var payload wsSubscribePayload
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &payload) != nil {
+				s.close(wsCloseInvalidMessage, "Invalid subscribe message")
+				return
+			}
+
+			if !s.subscribe(msg.ID, payload) {
+				s.close(wsCloseSubscriberExists, "Subscriber for "+msg.ID+" already exists")
+				return
+			}
+		case wsComplete:
+			s.unsubscribe(msg.ID)
+		default:
+			s.close(wsCloseInvalidMessage, "Unknown message type "+msg.Type)
+			return
+		}
+	}
+}
+
+// subscribe starts the operation, it returns false if an operation with the same id is running
+func (s *wsSession) subscribe(id string, payload wsSubscribePayload) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.subscriptions[id]; exists {
+		return false
+	}
+	// The connection is closed once the running operations completed
+	if s.draining {
+		s.sendErrors(id, []gqlerrors.FormattedError{{Message: "The gateway is shutting down"}})
+		return true
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.subscriptions[id] = cancel
+	s.onOperation(payload.OperationName)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.unsubscribe(id)
+		s.execute(ctx, id, payload)
+	}()
+
+	return true
+}
+
+// drain completes the running operations and closes the connection, because the gateway shuts down
+func (s *wsSession) drain() {
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	// The subscriptions are cancelled with the shutdown context and send their complete messages
+	s.wg.Wait()
+	s.close(wsCloseGoingAway, "Gateway is shutting down")
+}
+
+func (s *wsSession) unsubscribe(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cancel, ok := s.subscriptions[id]; ok {
+		cancel()
+		delete(s.subscriptions, id)
+	}
+}
+
+// execute streams the results of subscriptions, queries and mutations return a single result
+func (s *wsSession) execute(ctx context.Context, id string, payload wsSubscribePayload) {
+	params := graphql.Params{
+		Schema:         *s.schema,
+		RequestString:  payload.Query,
+		VariableValues: payload.Variables,
+		OperationName:  payload.OperationName,
+		Context:        ctx,
+	}
+
+	subscription, err := isSubscription(payload.Query, payload.OperationName)
+	if err != nil {
+		s.sendErrors(id, gqlerrors.FormatErrors(err))
+		return
+	}
+
+	if limitErr := s.queryLimits.check(s.schema, payload.Query); limitErr != nil {
+		s.sendErrors(id, []gqlerrors.FormattedError{limitErr.formatted()})
+		return
+	}
+
+	if s.rateLimit != nil {
+		if limitErr := s.rateLimit(ctx); limitErr != nil {
+			s.sendErrors(id, []gqlerrors.FormattedError{limitErr.formatted()})
+			return
+		}
+	}
+
+	if !subscription {
+		if err := s.coerce(payload.Query, payload.OperationName, payload.Variables); err != nil {
+			s.sendErrors(id, gqlerrors.FormatErrors(err))
+			return
+		}
+		s.sendResult(ctx, id, graphql.Do(params))
+		return
+	}
+
+	// Queries and mutations run to completion while the gateway drains, subscriptions are ended
+	params.Context = withParentCancel(ctx, s.shutdown)
+	for res := range graphql.Subscribe(params) {
+		if res == nil {
+			continue
+		}
+		// Errors before the subscription started are reported as error message, which also ends the operation
+		if res.Data == nil && res.HasErrors() {
+			s.sendErrors(id, res.Errors)
+			return
+		}
+		s.sendNext(ctx, id, res)
+	}
+
+	s.sendComplete(ctx, id)
+}
+
+func (s *wsSession) sendResult(ctx context.Context, id string, res *graphql.Result) {
+	if res.Data == nil && res.HasErrors() {
+		s.sendErrors(id, res.Errors)
+		return
+	}
+	s.sendNext(ctx, id, res)
+	s.sendComplete(ctx, id)
+}
+
+func (s *wsSession) sendNext(ctx context.Context, id string, res *graphql.Result) {
+	// Results of completed operations must not be sent anymore
+	if ctx.Err() != nil {
+		return
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Error marshalling subscription response")
+		return
+	}
+	s.send(wsMessage{ID: id, Type: wsNext, Payload: data})
+}
+
+func (s *wsSession) sendComplete(ctx context.Context, id string) {
+	// Operations completed by the client are not confirmed
+	if ctx.Err() != nil {
+		return
+	}
+	s.send(wsMessage{ID: id, Type: wsComplete})
+}
+
+func (s *wsSession) sendErrors(id string, errs []gqlerrors.FormattedError) {
+	data, err := json.Marshal(errs)
+	if err != nil {
+		s.log.Error().Err(err).Msg("Error marshalling subscription errors")
+		return
+	}
+	s.send(wsMessage{ID: id, Type: wsError, Payload: data})
+}
+
+// ping keeps the connection alive through proxies that close idle connections
+func (s *wsSession) ping(ctx context.Context) {
+	ticker := time.NewTicker(s.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.send(wsMessage{Type: wsPing})
+		}
+	}
+}
+
+func (s *wsSession) send(msg wsMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if s.closed {
+		return
+	}
+	if err := websocket.JSON.Send(s.conn, msg); err != nil {
+		s.log.Debug().Err(err).Str("type", msg.Type).Msg("Failed to send WebSocket message")
+	}
+}
+
+// close sends a close frame with one of the close codes of the protocol, which the websocket package doesn't support
+func (s *wsSession) close(code uint16, reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if s.closed {
+		return
+	}
+	s.closed = true
+	closeWebSocket(s.conn, s.log, code, reason)
+}
+
+// closeWebSocket sends a close frame with the code and closes the connection
+func closeWebSocket(conn *websocket.Conn, log *logger.Logger, code uint16, reason string) {
+	frame := binary.BigEndian.AppendUint16(nil, code)
+	frame = append(frame, reason...)
+	conn.PayloadType = websocket.CloseFrame
+	if _, err := conn.Write(frame); err != nil {
+		log.Debug().Err(err).Msg("Failed to send WebSocket close frame")
+	}
+	conn.Close()
+}
+
+// tokenFromPayload reads the token from the Authorization key of the connection_init payload, ignoring its case
+func tokenFromPayload(payload json.RawMessage) string {
+	var values map[string]any
+	if len(payload) == 0 || json.Unmarshal(payload, &values) != nil {
+		return ""
+	}
+
+	for key, value := range values {
+		if token, ok := value.(string); ok && strings.EqualFold(key, "authorization") {
+			token = strings.TrimPrefix(token, "Bearer ")
+			return strings.TrimPrefix(token, "bearer ")
+		}
+	}
+
+	return ""
+}
+
+// isSubscription reports whether the operation of the query is a subscription
+func isSubscription(query, operationName string) (bool, error) {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false, err
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+		return operation.Operation == ast.OperationTypeSubscription, nil
+	}
+
+	return false, nil
+}
+
+// withParentCancel returns ctx, which is additionally cancelled with parent
+func withParentCancel(ctx, parent context.Context) context.Context {
+	ctx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(parent, cancel)
+	return ctx
+}
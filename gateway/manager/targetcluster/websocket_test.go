@@ -0,0 +1,211 @@
+package targetcluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	appConfig "github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func newWebSocketTestServer(t *testing.T, appCfg appConfig.Config) *httptest.Server {
+	server := httptest.NewServer(newWebSocketTestRegistry(t, appCfg))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newWebSocketTestRegistry(t *testing.T, appCfg appConfig.Config) *ClusterRegistry {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"token": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Context.Value(roundtripper.TokenKey{}), nil
+					},
+				},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"counter": &graphql.Field{
+					Type: graphql.Int,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						values := make(chan interface{}, 2)
+						values <- 1
+						values <- 2
+						close(values)
+						return values, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+				// events sends no updates until the subscription is ended
+				"events": &graphql.Field{
+					Type: graphql.Int,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						values := make(chan interface{})
+						context.AfterFunc(p.Context, func() { close(values) })
+						return values, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+			},
+		}),
+	})
+	require.NoError(t, err)
+
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+	appCfg.Url.GraphqlSuffix = "graphql"
+
+	registry := NewClusterRegistry(testlogger.New().HideLogOutput().Logger, appCfg, nil)
+	registry.clusters["test-cluster"] = &TargetCluster{
+		appCfg:  appCfg,
+		name:    "test-cluster",
+		handler: &GraphQLHandler{Schema: &schema},
+	}
+	return registry
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server, protocol string) (*websocket.Conn, error) {
+	config, err := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+"/test-cluster/graphql", server.URL)
+	require.NoError(t, err)
+	config.Protocol = []string{protocol}
+
+	conn, err := websocket.DialConfig(config)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, err
+}
+
+func sendMessage(t *testing.T, conn *websocket.Conn, msg string) {
+	require.NoError(t, websocket.Message.Send(conn, msg))
+}
+
+func receiveMessage(t *testing.T, conn *websocket.Conn) map[string]any {
+	var msg map[string]any
+	require.NoError(t, websocket.JSON.Receive(conn, &msg))
+	return msg
+}
+
+func TestServeWebSocket(t *testing.T) {
+	server := newWebSocketTestServer(t, appConfig.Config{})
+
+	conn, err := dialWebSocket(t, server, GraphQLTransportWSProtocol)
+	require.NoError(t, err)
+	assert.Equal(t, []string{GraphQLTransportWSProtocol}, conn.Config().Protocol)
+
+	sendMessage(t, conn, `{"type": "connection_init", "payload": {"Authorization": "Bearer secret"}}`)
+	assert.Equal(t, map[string]any{"type": "connection_ack"}, receiveMessage(t, conn))
+
+	t.Run("ping", func(t *testing.T) {
+		sendMessage(t, conn, `{"type": "ping"}`)
+		assert.Equal(t, map[string]any{"type": "pong"}, receiveMessage(t, conn))
+	})
+
+	t.Run("subscription", func(t *testing.T) {
+		sendMessage(t, conn, `{"id": "1", "type": "subscribe", "payload": {"query": "subscription { counter }"}}`)
+		assert.Equal(t, map[string]any{"id": "1", "type": "next", "payload": map[string]any{"data": map[string]any{"counter": float64(1)}}}, receiveMessage(t, conn))
+		assert.Equal(t, map[string]any{"id": "1", "type": "next", "payload": map[string]any{"data": map[string]any{"counter": float64(2)}}}, receiveMessage(t, conn))
+		assert.Equal(t, map[string]any{"id": "1", "type": "complete"}, receiveMessage(t, conn))
+	})
+
+	t.Run("query_with_connection_token", func(t *testing.T) {
+		sendMessage(t, conn, `{"id": "2", "type": "subscribe", "payload": {"query": "query Token { token }", "operationName": "Token"}}`)
+		assert.Equal(t, map[string]any{"id": "2", "type": "next", "payload": map[string]any{"data": map[string]any{"token": "secret"}}}, receiveMessage(t, conn))
+		assert.Equal(t, map[string]any{"id": "2", "type": "complete"}, receiveMessage(t, conn))
+	})
+
+	t.Run("invalid_query_ERROR", func(t *testing.T) {
+		sendMessage(t, conn, `{"id": "3", "type": "subscribe", "payload": {"query": "subscription { unknown }"}}`)
+		msg := receiveMessage(t, conn)
+		assert.Equal(t, "3", msg["id"])
+		assert.Equal(t, "error", msg["type"])
+		assert.NotEmpty(t, msg["payload"])
+	})
+}
+
+func TestServeWebSocket_Closed(t *testing.T) {
+	tests := []struct {
+		name     string
+		appCfg   appConfig.Config
+		messages []string
+	}{
+		{
+			name:     "subscribe_before_init",
+			appCfg:   appConfig.Config{LocalDevelopment: true},
+			messages: []string{`{"id": "1", "type": "subscribe", "payload": {"query": "subscription { counter }"}}`},
+		},
+		{
+			name:     "missing_token",
+			messages: []string{`{"type": "connection_init"}`},
+		},
+		{
+			name:     "invalid_message",
+			appCfg:   appConfig.Config{LocalDevelopment: true},
+			messages: []string{`not json`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newWebSocketTestServer(t, tt.appCfg)
+
+			conn, err := dialWebSocket(t, server, GraphQLTransportWSProtocol)
+			require.NoError(t, err)
+
+			for _, msg := range tt.messages {
+				sendMessage(t, conn, msg)
+			}
+
+			var msg json.RawMessage
+			assert.Error(t, websocket.JSON.Receive(conn, &msg), "connection should be closed")
+		})
+	}
+}
+
+func TestServeWebSocket_UnsupportedProtocol(t *testing.T) {
+	server := newWebSocketTestServer(t, appConfig.Config{LocalDevelopment: true})
+
+	_, err := dialWebSocket(t, server, "graphql-ws")
+	assert.Error(t, err)
+}
+
+func TestServeWebSocket_CORSOrigin(t *testing.T) {
+	appCfg := appConfig.Config{LocalDevelopment: true}
+	appCfg.Gateway.Cors.Enabled = true
+	appCfg.Gateway.Cors.AllowedOrigins = "https://app.example.com"
+	server := newWebSocketTestServer(t, appCfg)
+
+	_, err := dialWebSocket(t, server, GraphQLTransportWSProtocol)
+	assert.Error(t, err, "the origin of the test server is not allowed")
+
+	config, err := websocket.NewConfig(strings.Replace(server.URL, "http", "ws", 1)+"/test-cluster/graphql", "https://app.example.com")
+	require.NoError(t, err)
+	config.Protocol = []string{GraphQLTransportWSProtocol}
+	conn, err := websocket.DialConfig(config)
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestTokenFromPayload(t *testing.T) {
+	assert.Equal(t, "secret", tokenFromPayload(json.RawMessage(`{"Authorization": "Bearer secret"}`)))
+	assert.Equal(t, "secret", tokenFromPayload(json.RawMessage(`{"authorization": "secret"}`)))
+	assert.Empty(t, tokenFromPayload(json.RawMessage(`{"token": "secret"}`)))
+	assert.Empty(t, tokenFromPayload(nil))
+}
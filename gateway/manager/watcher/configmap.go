@@ -0,0 +1,99 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/golang-commons/sentry"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/watcher"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+// ConfigMapWatcher loads the clusters from the schemas the listener stores in ConfigMaps.
+// The schemas are mirrored into the watch path, from where the cluster registry loads them like schema files.
+type ConfigMapWatcher struct {
+	log              *logger.Logger
+	configMapWatcher *watcher.ConfigMapWatcher
+	clusterRegistry  ClusterRegistryInterface
+	mirror           *workspacefile.IOHandlerProvider
+	watchPath        string
+}
+
+// NewConfigMapWatcher creates a watcher of the schema ConfigMaps in the namespace
+func NewConfigMapWatcher(
+	log *logger.Logger,
+	clusterRegistry ClusterRegistryInterface,
+	clientset kubernetes.Interface,
+	namespace string,
+) *ConfigMapWatcher {
+	cw := &ConfigMapWatcher{
+		log:             log,
+		clusterRegistry: clusterRegistry,
+	}
+	cw.configMapWatcher = watcher.NewConfigMapWatcher(clientset, namespace, workspacefile.SchemaLabelKey+"=true", cw, log)
+
+	return cw
+}
+
+// Initialize loads the clusters of the existing ConfigMaps and keeps watching them in the background
+func (s *ConfigMapWatcher) Initialize(ctx context.Context, watchPath string) error {
+	s.watchPath = watchPath
+
+	mirror, err := workspacefile.NewIOHandler(watchPath)
+	if err != nil {
+		return fmt.Errorf("failed to create schema directory: %w", err)
+	}
+	s.mirror = mirror
+
+	return s.configMapWatcher.Start(ctx)
+}
+
+// OnConfigMapChanged implements watcher.ConfigMapEventHandler
+func (s *ConfigMapWatcher) OnConfigMapChanged(configMap *corev1.ConfigMap) {
+	clusterName, JSON, err := workspacefile.SchemaFromConfigMap(configMap)
+	if err != nil {
+		s.log.Error().Err(err).Str("configMap", configMap.Name).Msg("Failed to read schema from ConfigMap")
+		return
+	}
+
+	if err := s.mirror.Write(JSON, clusterName); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to write schema file")
+		return
+	}
+
+	schemaFilePath := filepath.Join(s.watchPath, clusterName)
+	if err := s.clusterRegistry.UpdateCluster(schemaFilePath); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to update cluster")
+		sentry.CaptureError(err, sentry.Tags{"configmap": configMap.Name})
+		return
+	}
+
+	s.log.Info().Str("cluster", clusterName).Str("configMap", configMap.Name).Msg("Successfully updated cluster from ConfigMap change")
+}
+
+// OnConfigMapDeleted implements watcher.ConfigMapEventHandler
+func (s *ConfigMapWatcher) OnConfigMapDeleted(configMap *corev1.ConfigMap) {
+	clusterName := configMap.Annotations[workspacefile.ClusterAnnotationKey]
+	if clusterName == "" {
+		return
+	}
+
+	if err := s.mirror.Delete(clusterName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to delete schema file")
+	}
+
+	if err := s.clusterRegistry.RemoveCluster(filepath.Join(s.watchPath, clusterName)); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to remove cluster")
+		sentry.CaptureError(err, sentry.Tags{"configmap": configMap.Name})
+		return
+	}
+
+	s.log.Info().Str("cluster", clusterName).Str("configMap", configMap.Name).Msg("Successfully removed cluster from ConfigMap deletion")
+}
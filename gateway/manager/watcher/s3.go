@@ -0,0 +1,142 @@
+package watcher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/golang-commons/sentry"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+// S3Watcher loads the clusters from the schemas the listener stores in an S3 or Cloud Storage bucket. Object storages
+// offer no watch, so the bucket is listed periodically and the schemas whose ETag changed are loaded again.
+// The schemas are mirrored into the watch path, from where the cluster registry loads them like schema files.
+type S3Watcher struct {
+	log             *logger.Logger
+	storage         *workspacefile.S3IOHandler
+	clusterRegistry ClusterRegistryInterface
+	pollPeriod      time.Duration
+	mirror          *workspacefile.IOHandlerProvider
+	watchPath       string
+	// etags of the loaded schemas by cluster name
+	etags map[string]string
+}
+
+// NewS3Watcher creates a watcher of the schemas in the bucket of the storage
+func NewS3Watcher(
+	log *logger.Logger,
+	clusterRegistry ClusterRegistryInterface,
+	storage *workspacefile.S3IOHandler,
+	pollPeriod time.Duration,
+) *S3Watcher {
+	return &S3Watcher{
+		log:             log,
+		storage:         storage,
+		clusterRegistry: clusterRegistry,
+		pollPeriod:      pollPeriod,
+		etags:           map[string]string{},
+	}
+}
+
+// Initialize loads the clusters of the stored schemas and keeps polling the bucket in the background
+func (s *S3Watcher) Initialize(ctx context.Context, watchPath string) error {
+	s.watchPath = watchPath
+
+	mirror, err := workspacefile.NewIOHandler(watchPath)
+	if err != nil {
+		return fmt.Errorf("failed to create schema directory: %w", err)
+	}
+	s.mirror = mirror
+
+	if err := s.sync(); err != nil {
+		return fmt.Errorf("failed to load schemas from bucket: %w", err)
+	}
+
+	go func() {
+		ticker := time.NewTicker(s.pollPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.sync(); err != nil {
+					s.log.Error().Err(err).Msg("Failed to list schemas in bucket")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// sync loads the new and changed schemas and removes the clusters whose schemas were deleted.
+// Schemas that fail to load keep their previous ETag, so that they are retried with the next poll.
+func (s *S3Watcher) sync() error {
+	objects, err := s.storage.Objects()
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]bool, len(objects))
+	for _, object := range objects {
+		stored[object.ClusterName] = true
+		if s.etags[object.ClusterName] == object.ETag {
+			continue
+		}
+		if s.updateCluster(object.ClusterName) {
+			s.etags[object.ClusterName] = object.ETag
+		}
+	}
+
+	for clusterName := range s.etags {
+		if !stored[clusterName] {
+			s.removeCluster(clusterName)
+			delete(s.etags, clusterName)
+		}
+	}
+
+	return nil
+}
+
+func (s *S3Watcher) updateCluster(clusterName string) bool {
+	JSON, err := s.storage.Read(clusterName)
+	if err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to read schema from bucket")
+		return false
+	}
+
+	if err := s.mirror.Write(JSON, clusterName); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to write schema file")
+		return false
+	}
+
+	if err := s.clusterRegistry.UpdateCluster(filepath.Join(s.watchPath, clusterName)); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to update cluster")
+		sentry.CaptureError(err, sentry.Tags{"cluster": clusterName})
+		return false
+	}
+
+	s.log.Info().Str("cluster", clusterName).Msg("Successfully updated cluster from bucket change")
+	return true
+}
+
+func (s *S3Watcher) removeCluster(clusterName string) {
+	if err := s.mirror.Delete(clusterName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to delete schema file")
+	}
+
+	if err := s.clusterRegistry.RemoveCluster(filepath.Join(s.watchPath, clusterName)); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to remove cluster")
+		sentry.CaptureError(err, sentry.Tags{"cluster": clusterName})
+		return
+	}
+
+	s.log.Info().Str("cluster", clusterName).Msg("Successfully removed cluster from bucket deletion")
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/openmfp/golang-commons/logger"
 	"github.com/openmfp/golang-commons/sentry"
 	"github.com/openmfp/kubernetes-graphql-gateway/common/watcher"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
 )
 
 // ClusterRegistryInterface defines the minimal interface needed from ClusterRegistry
@@ -25,6 +26,8 @@ type FileWatcher struct {
 	fileWatcher     *watcher.FileWatcher
 	clusterRegistry ClusterRegistryInterface
 	watchPath       string
+	// versionsDir holds the schema versions whose pins are watched, empty if versioning is disabled
+	versionsDir string
 }
 
 // NewFileWatcher creates a new watcher service
@@ -46,6 +49,13 @@ func NewFileWatcher(
 	return fw, nil
 }
 
+// WithVersionsDir reloads the clusters whose pinned schema version changes in versionsDir, so that a version pinned
+// through the admin API of one gateway replica is served by all of them
+func (s *FileWatcher) WithVersionsDir(versionsDir string) *FileWatcher {
+	s.versionsDir = versionsDir
+	return s
+}
+
 // Initialize sets up the watcher with the given context and path and processes existing files
 func (s *FileWatcher) Initialize(ctx context.Context, watchPath string) error {
 	s.watchPath = watchPath
@@ -62,6 +72,12 @@ func (s *FileWatcher) Initialize(ctx context.Context, watchPath string) error {
 		}
 	}()
 
+	if s.versionsDir != "" {
+		if err := s.watchPins(ctx); err != nil {
+			return fmt.Errorf("failed to watch pinned schema versions: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -71,6 +87,10 @@ func (s *FileWatcher) OnFileChanged(filePath string) {
 	if info, err := os.Stat(filePath); err != nil || info.IsDir() {
 		return
 	}
+	// Schemas are written to temporary files first, which are renamed once they are complete
+	if workspacefile.IsTemporaryFile(filePath) {
+		return
+	}
 
 	// Delegate to cluster registry
 	if err := s.clusterRegistry.UpdateCluster(filePath); err != nil {
@@ -84,6 +104,10 @@ func (s *FileWatcher) OnFileChanged(filePath string) {
 
 // OnFileDeleted implements watcher.FileEventHandler
 func (s *FileWatcher) OnFileDeleted(filePath string) {
+	if workspacefile.IsTemporaryFile(filePath) {
+		return
+	}
+
 	// Delegate to cluster registry
 	if err := s.clusterRegistry.RemoveCluster(filePath); err != nil {
 		s.log.Error().Err(err).Str("path", filePath).Msg("Failed to remove cluster")
@@ -101,8 +125,8 @@ func (s *FileWatcher) loadAllFiles(dir string) error {
 			return err
 		}
 
-		// Skip directories
-		if d.IsDir() {
+		// Skip directories and schemas that are still being written
+		if d.IsDir() || workspacefile.IsTemporaryFile(path) {
 			return nil
 		}
 
@@ -115,3 +139,55 @@ func (s *FileWatcher) loadAllFiles(dir string) error {
 		return nil
 	})
 }
+
+// watchPins reloads a cluster when its pinned version file in the versions directory is written or removed
+func (s *FileWatcher) watchPins(ctx context.Context) error {
+	// The listener creates the directory with the first version, it is watched before
+	if err := os.MkdirAll(s.versionsDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	pinWatcher, err := watcher.NewFileWatcher(pinHandler{s}, s.log)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := pinWatcher.WatchDirectory(ctx, s.versionsDir); err != nil {
+			s.log.Error().Err(err).Msg("schema versions watcher stopped")
+		}
+	}()
+	return nil
+}
+
+// reloadPinned reloads the cluster of a changed pinned version file, which is served from the pinned version or again
+// from its current schema
+func (s *FileWatcher) reloadPinned(pinPath string) {
+	if filepath.Base(pinPath) != workspacefile.PinnedFileName {
+		return
+	}
+
+	clusterName, err := filepath.Rel(s.versionsDir, filepath.Dir(pinPath))
+	if err != nil {
+		return
+	}
+	schemaPath := filepath.Join(s.watchPath, clusterName)
+	if _, err := os.Stat(schemaPath); err != nil {
+		return
+	}
+
+	if err := s.clusterRegistry.UpdateCluster(schemaPath); err != nil {
+		s.log.Error().Err(err).Str("cluster", clusterName).Msg("Failed to reload cluster after its pinned schema version changed")
+		return
+	}
+	s.log.Info().Str("cluster", clusterName).Msg("Reloaded cluster after its pinned schema version changed")
+}
+
+// pinHandler passes the events of the versions directory to reloadPinned
+type pinHandler struct {
+	s *FileWatcher
+}
+
+func (h pinHandler) OnFileChanged(filePath string) { h.s.reloadPinned(filePath) }
+
+func (h pinHandler) OnFileDeleted(filePath string) { h.s.reloadPinned(filePath) }
@@ -0,0 +1,91 @@
+package recording
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+var (
+	ErrReadBundle  = errors.New("failed to read recording bundle")
+	ErrParseBundle = errors.New("failed to parse recording bundle")
+	ErrWriteBundle = errors.New("failed to write recording bundle")
+)
+
+// Bundle holds everything needed to re-execute a GraphQL operation without access to the cluster
+type Bundle struct {
+	Cluster    string    `json:"cluster"`
+	RecordedAt time.Time `json:"recordedAt"`
+	Operation  Operation `json:"operation"`
+	// Definitions are the OpenAPI definitions of the schema file the operation was executed against
+	Definitions map[string]any            `json:"definitions"`
+	Defaults    *resolver.ClusterDefaults `json:"defaults,omitempty"`
+	// InputCoercion and Protobuf are the resolver settings of the gateway, see config.Config
+	InputCoercion string `json:"inputCoercion,omitempty"`
+	Protobuf      bool   `json:"protobuf,omitempty"`
+	// Mappings are the REST mappings of the resources, discovery requests are not part of the exchanges
+	Mappings  []Mapping  `json:"mappings"`
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// Operation is the GraphQL request sent by the client
+type Operation struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Mapping maps a kind to its REST resource
+type Mapping struct {
+	Group      string `json:"group"`
+	Version    string `json:"version"`
+	Kind       string `json:"kind"`
+	Resource   string `json:"resource"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+// Exchange is a request to the API server and its response.
+// Request headers are not recorded since they carry the credentials of the caller.
+type Exchange struct {
+	Method string `json:"method"`
+	// URL is the path and query of the request, without the kcp cluster prefix
+	URL          string `json:"url"`
+	RequestBody  []byte `json:"requestBody,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody []byte `json:"responseBody,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// WriteBundle stores the bundle as JSON
+func WriteBundle(path string, bundle *Bundle) error {
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err != nil {
+		return errors.Join(ErrWriteBundle, err)
+	}
+
+	// Bundles contain cluster data, they are only readable by the gateway user
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return errors.Join(ErrWriteBundle, err)
+	}
+
+	return nil
+}
+
+// ReadBundle reads a bundle written by WriteBundle
+func ReadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadBundle, err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, errors.Join(ErrParseBundle, err)
+	}
+
+	return &bundle, nil
+}
@@ -0,0 +1,164 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redactedValue replaces sensitive values, it is valid base64 so that Secret data can still be serialized on replay
+var redactedValue = base64.StdEncoding.EncodeToString([]byte("redacted"))
+
+// kcpClusterPrefix matches the path prefix added by the kcp cluster-aware client
+var kcpClusterPrefix = regexp.MustCompile(`^/clusters/[^/]+`)
+
+type recorderKey struct{}
+
+// Recorder collects the API server exchanges of a single GraphQL operation
+type Recorder struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+}
+
+// WithRecorder returns a context whose API server requests are recorded by the recorder
+func WithRecorder(ctx context.Context, recorder *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, recorder)
+}
+
+// Exchanges returns the recorded exchanges in the order in which the requests were sent
+func (r *Recorder) Exchanges() []Exchange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Exchange(nil), r.exchanges...)
+}
+
+func (r *Recorder) add(exchange Exchange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges = append(r.exchanges, exchange)
+}
+
+type roundTripper struct {
+	next http.RoundTripper
+}
+
+// NewRoundTripper records the requests whose context carries a Recorder, all other requests are passed through.
+// Watches are passed through as well, since their responses don't end.
+func NewRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &roundTripper{next: next}
+}
+
+func (rt *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder, ok := req.Context().Value(recorderKey{}).(*Recorder)
+	if !ok || req.URL.Query().Get("watch") == "true" {
+		return rt.next.RoundTrip(req)
+	}
+
+	exchange := Exchange{
+		Method: req.Method,
+		URL:    requestURL(req),
+	}
+
+	if req.Body != nil && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			requestBody, _ := io.ReadAll(body)
+			body.Close()
+			exchange.RequestBody = sanitizeBody(req.URL.Path, req.Header.Get("Content-Type"), requestBody)
+		}
+	}
+
+	resp, err := rt.next.RoundTrip(req)
+	if err != nil {
+		exchange.Error = err.Error()
+		recorder.add(exchange)
+		return resp, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		exchange.Error = err.Error()
+		recorder.add(exchange)
+		return resp, err
+	}
+
+	exchange.StatusCode = resp.StatusCode
+	exchange.ContentType = resp.Header.Get("Content-Type")
+	exchange.ResponseBody = sanitizeBody(req.URL.Path, exchange.ContentType, body)
+	recorder.add(exchange)
+
+	return resp, nil
+}
+
+// requestURL returns the path and query of the request without the kcp cluster prefix, so that the exchange can be
+// matched by a client that isn't cluster-aware on replay
+func requestURL(req *http.Request) string {
+	u := *req.URL
+	u.Scheme, u.Host = "", ""
+	u.Path = kcpClusterPrefix.ReplaceAllString(u.Path, "")
+	u.RawPath = ""
+
+	return u.String()
+}
+
+// sanitizeBody redacts the values of Secrets in JSON request and response bodies.
+// Protobuf bodies of Secrets are dropped entirely, since their values can't be redacted without decoding them.
+func sanitizeBody(path, contentType string, body []byte) []byte {
+	if len(body) == 0 || !strings.Contains(path+"/", "/secrets/") {
+		return body
+	}
+
+	if !strings.Contains(contentType, "json") {
+		return nil
+	}
+
+	var obj map[string]any
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil
+	}
+
+	if items, ok := obj["items"].([]any); ok {
+		for _, item := range items {
+			if secret, ok := item.(map[string]any); ok {
+				redactSecret(secret)
+			}
+		}
+	} else {
+		redactSecret(obj)
+	}
+
+	sanitized, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+
+	return sanitized
+}
+
+func redactSecret(secret map[string]any) {
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := secret[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = redactedValue
+		}
+	}
+
+	// The last applied configuration contains the values as well
+	if metadata, ok := secret["metadata"].(map[string]any); ok {
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package recording_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/recording"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func jsonResponse(body string) roundTripperFunc {
+	return func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+func TestRoundTripper(t *testing.T) {
+	rt := recording.NewRoundTripper(jsonResponse(`{"kind":"Secret","data":{"password":"c2VjcmV0"}}`))
+	recorder := &recording.Recorder{}
+	ctx := recording.WithRecorder(context.Background(), recorder)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/clusters/root/api/v1/namespaces/default/secrets/db?timeout=10s", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	// The client still receives the original response
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"Secret","data":{"password":"c2VjcmV0"}}`, string(body))
+
+	exchanges := recorder.Exchanges()
+	require.Len(t, exchanges, 1)
+	assert.Equal(t, http.MethodGet, exchanges[0].Method)
+	assert.Equal(t, "/api/v1/namespaces/default/secrets/db?timeout=10s", exchanges[0].URL)
+	assert.Equal(t, http.StatusOK, exchanges[0].StatusCode)
+	assert.JSONEq(t, `{"kind":"Secret","data":{"password":"cmVkYWN0ZWQ="}}`, string(exchanges[0].ResponseBody))
+}
+
+func TestRoundTripper_PassThrough(t *testing.T) {
+	rt := recording.NewRoundTripper(jsonResponse(`{}`))
+	recorder := &recording.Recorder{}
+
+	watch, err := http.NewRequestWithContext(recording.WithRecorder(context.Background(), recorder), http.MethodGet, "https://example.com/api/v1/configmaps?watch=true", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(watch)
+	require.NoError(t, err)
+
+	unrecorded, err := http.NewRequest(http.MethodGet, "https://example.com/api/v1/configmaps", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(unrecorded)
+	require.NoError(t, err)
+
+	assert.Empty(t, recorder.Exchanges())
+}
+
+func TestReplayTransport(t *testing.T) {
+	rt := recording.NewReplayTransport([]recording.Exchange{
+		{Method: http.MethodGet, URL: "/api/v1/namespaces", StatusCode: http.StatusOK, ResponseBody: []byte("first")},
+		{Method: http.MethodGet, URL: "/api/v1/namespaces", StatusCode: http.StatusOK, ResponseBody: []byte("second")},
+	})
+
+	for _, expected := range []string{"first", "second"} {
+		req, err := http.NewRequest(http.MethodGet, "http://replay.invalid/api/v1/namespaces", nil)
+		require.NoError(t, err)
+
+		resp, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "http://replay.invalid/api/v1/namespaces", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	assert.ErrorIs(t, err, recording.ErrNoRecordedExchange)
+}
+
+func TestReplay(t *testing.T) {
+	var definitions map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"io.k8s.api.core.v1.ConfigMap": {
+			"type": "object",
+			"properties": {"data": {"type": "object", "additionalProperties": {"type": "string"}}},
+			"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "ConfigMap"}],
+			"x-kubernetes-scope": "Namespaced"
+		}
+	}`), &definitions))
+
+	path := filepath.Join(t.TempDir(), "bundle.json")
+	require.NoError(t, recording.WriteBundle(path, &recording.Bundle{
+		Cluster: "root",
+		Operation: recording.Operation{
+			Query:     `query Get($name: String!) { core { ConfigMap(name: $name, namespace: "default") { data } } }`,
+			Variables: map[string]any{"name": "settings"},
+		},
+		Definitions: definitions,
+		Mappings:    []recording.Mapping{{Version: "v1", Kind: "ConfigMap", Resource: "configmaps", Namespaced: true}},
+		Exchanges: []recording.Exchange{{
+			Method:       http.MethodGet,
+			URL:          "/api/v1/namespaces/default/configmaps/settings",
+			StatusCode:   http.StatusOK,
+			ContentType:  "application/json",
+			ResponseBody: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"default"},"data":{"theme":"dark"}}`),
+		}},
+	}))
+
+	bundle, err := recording.ReadBundle(path)
+	require.NoError(t, err)
+
+	result, err := recording.Replay(t.Context(), testlogger.New().HideLogOutput().Logger, bundle)
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+
+	data, err := json.Marshal(result.Data)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"core":{"ConfigMap":{"data":{"theme":"dark"}}}}`, string(data))
+}
+
+func TestReadBundle_Missing(t *testing.T) {
+	_, err := recording.ReadBundle(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorIs(t, err, recording.ErrReadBundle)
+}
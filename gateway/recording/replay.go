@@ -0,0 +1,145 @@
+package recording
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	gatewaySchema "github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+// replayHost is the API server the replay client talks to, requests never leave the process
+const replayHost = "http://replay.invalid"
+
+var ErrNoRecordedExchange = errors.New("no recorded exchange for request")
+
+type replayTransport struct {
+	mu        sync.Mutex
+	exchanges []Exchange
+	used      []bool
+}
+
+// NewReplayTransport serves the recorded exchanges instead of sending requests to an API server.
+// Every exchange is served once, requests are matched by method and URL in the order in which they were recorded.
+func NewReplayTransport(exchanges []Exchange) http.RoundTripper {
+	return &replayTransport{
+		exchanges: exchanges,
+		used:      make([]bool, len(exchanges)),
+	}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	url := requestURL(req)
+	for i, exchange := range t.exchanges {
+		if t.used[i] || exchange.Method != req.Method || exchange.URL != url {
+			continue
+		}
+		t.used[i] = true
+
+		if exchange.Error != "" {
+			return nil, errors.New(exchange.Error)
+		}
+
+		return &http.Response{
+			StatusCode: exchange.StatusCode,
+			Status:     fmt.Sprintf("%d %s", exchange.StatusCode, http.StatusText(exchange.StatusCode)),
+			Header:     http.Header{"Content-Type": []string{exchange.ContentType}},
+			Body:       io.NopCloser(bytes.NewReader(exchange.ResponseBody)),
+			Request:    req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrNoRecordedExchange, req.Method, url)
+}
+
+// Replay re-executes the operation of the bundle against the recorded exchanges
+func Replay(ctx context.Context, log *logger.Logger, bundle *Bundle) (*graphql.Result, error) {
+	definitions, err := toSpecDefinitions(bundle.Definitions)
+	if err != nil {
+		return nil, err
+	}
+
+	inputCoercion, err := resolver.ParseInputCoercion(bundle.InputCoercion)
+	if err != nil {
+		return nil, err
+	}
+
+	runtimeClient, err := client.NewWithWatch(&rest.Config{
+		Host:      replayHost,
+		Transport: NewReplayTransport(bundle.Exchanges),
+	}, client.Options{Mapper: restMapper(bundle.Mappings)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay client: %w", err)
+	}
+
+	resolverProvider := resolver.New(log, runtimeClient).
+		WithInputCoercion(inputCoercion, definitions).
+		WithClusterDefaults(bundle.Defaults).
+		WithProtobuf(bundle.Protobuf)
+
+	schemaGateway, err := gatewaySchema.New(log, definitions, resolverProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GraphQL schema: %w", err)
+	}
+
+	return graphql.Do(graphql.Params{
+		Schema:         *schemaGateway.GetSchema(),
+		RequestString:  bundle.Operation.Query,
+		OperationName:  bundle.Operation.OperationName,
+		VariableValues: bundle.Operation.Variables,
+		Context:        ctx,
+	}), nil
+}
+
+// restMapper replaces discovery, which isn't recorded, with the mappings of the bundle
+func restMapper(mappings []Mapping) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, mapping := range mappings {
+		scope := meta.RESTScopeRoot
+		if mapping.Namespaced {
+			scope = meta.RESTScopeNamespace
+		}
+
+		gv := schema.GroupVersion{Group: mapping.Group, Version: mapping.Version}
+		mapper.AddSpecific(
+			gv.WithKind(mapping.Kind),
+			gv.WithResource(mapping.Resource),
+			gv.WithResource(strings.ToLower(mapping.Kind)),
+			scope,
+		)
+	}
+
+	return mapper
+}
+
+func toSpecDefinitions(definitions map[string]any) (spec.Definitions, error) {
+	data, err := json.Marshal(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal definitions: %w", err)
+	}
+
+	var specDefs spec.Definitions
+	if err := json.Unmarshal(data, &specDefs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal to spec definitions: %w", err)
+	}
+
+	return specDefs, nil
+}
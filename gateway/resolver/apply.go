@@ -0,0 +1,99 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	FieldManagerArg = "fieldManager"
+	ForceArg        = "force"
+
+	// DefaultFieldManager owns the fields applied without a fieldManager argument
+	DefaultFieldManager = "graphql-gateway"
+	// maxFieldManagerLength is the longest field manager the API server accepts
+	maxFieldManagerLength = 128
+)
+
+// ApplyItem returns a resolver that creates or updates an object via Server-Side Apply.
+// Unlike the merge patch of UpdateItem, the fields of the object are owned by the field manager,
+// so fields owned by controllers are not overwritten unless force is set.
+func (r *Service) ApplyItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(APPLY_ITEM, gvk, r.audited("apply", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, APPLY_ITEM, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "apply").Str("kind", gvk.Kind).Logger()
+
+		objectInput := p.Args[ObjectArg].(map[string]interface{})
+		if err := r.coerceObjectInput(gvk, objectInput); err != nil {
+			return nil, err
+		}
+
+		obj := &unstructured.Unstructured{Object: objectInput}
+		obj.SetGroupVersionKind(gvk)
+
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
+		if isResourceNamespaceScoped(scope) {
+			obj.SetNamespace(namespace)
+		}
+
+		if obj.GetName() == "" {
+			return nil, errors.New("object metadata.name is required")
+		}
+
+		// The API server rejects applied objects with managed fields
+		obj.SetManagedFields(nil)
+
+		fieldManager, err := getStringArg(p.Args, FieldManagerArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if fieldManager == "" {
+			fieldManager = DefaultFieldManager
+		}
+		if len(fieldManager) > maxFieldManagerLength {
+			return nil, fmt.Errorf("fieldManager must not be longer than %d characters", maxFieldManagerLength)
+		}
+
+		opts := []client.PatchOption{client.FieldOwner(fieldManager)}
+
+		force, err := getBoolArg(p.Args, ForceArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if force {
+			opts = append(opts, client.ForceOwnership)
+		}
+
+		dryRun, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if dryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+
+		if err := r.runtimeClient.Patch(ctx, obj, client.Apply, opts...); err != nil {
+			log.Error().Err(err).Str(FieldManagerArg, fieldManager).Msg("Failed to apply object")
+			return nil, err
+		}
+
+		r.redact(ctx, gvk, obj.Object)
+		return obj.Object, nil
+	}))
+}
@@ -0,0 +1,104 @@
+package resolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestApplyItem(t *testing.T) {
+	tests := []struct {
+		name                 string
+		args                 map[string]any
+		expectedFieldManager string
+		expectedForce        bool
+		expectedDryRun       bool
+		expectedErr          string
+	}{
+		{
+			name:                 "default_field_manager",
+			args:                 map[string]any{},
+			expectedFieldManager: resolver.DefaultFieldManager,
+		},
+		{
+			name:                 "field_manager_and_force",
+			args:                 map[string]any{resolver.FieldManagerArg: "settings-ui", resolver.ForceArg: true, resolver.DryRunArg: true},
+			expectedFieldManager: "settings-ui",
+			expectedForce:        true,
+			expectedDryRun:       true,
+		},
+		{
+			name:        "field_manager_too_long_ERROR",
+			args:        map[string]any{resolver.FieldManagerArg: strings.Repeat("a", 129)},
+			expectedErr: "fieldManager must not be longer than 128 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patchType types.PatchType
+			var patchOpts client.PatchOptions
+			var applied map[string]any
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, clt client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						patchType = patch.Type()
+						patchOpts.ApplyOptions(opts)
+
+						data, err := patch.Data(obj)
+						require.NoError(t, err)
+						return json.Unmarshal(data, &applied)
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			args := map[string]any{
+				resolver.NamespaceArg: "default",
+				resolver.ObjectArg: map[string]any{
+					"metadata": map[string]any{"name": "settings"},
+					"data":     map[string]any{"theme": "dark"},
+				},
+			}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+
+			result, err := r.ApplyItem(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    args,
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Empty(t, patchType, "nothing is applied")
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			assert.Equal(t, types.ApplyPatchType, patchType)
+			assert.Equal(t, tt.expectedFieldManager, patchOpts.FieldManager)
+			assert.Equal(t, tt.expectedForce, patchOpts.Force != nil && *patchOpts.Force)
+			assert.Equal(t, tt.expectedDryRun, len(patchOpts.DryRun) > 0)
+
+			assert.Equal(t, "v1", applied["apiVersion"])
+			assert.Equal(t, "ConfigMap", applied["kind"])
+			assert.Equal(t, map[string]any{"name": "settings", "namespace": "default"}, applied["metadata"])
+			assert.Equal(t, map[string]any{"theme": "dark"}, applied["data"])
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package resolver
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"maps"
+	"slices"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -10,16 +13,29 @@ import (
 	"github.com/graphql-go/graphql"
 	"github.com/rs/zerolog/log"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
 )
 
 const (
-	LabelSelectorArg  = "labelselector"
-	NameArg           = "name"
-	NamespaceArg      = "namespace"
-	ObjectArg         = "object"
-	SubscribeToAllArg = "subscribeToAll"
-	SortByArg         = "sortBy"
-	DryRunArg         = "dryRun"
+	LabelSelectorArg   = "labelselector"
+	NameArg            = "name"
+	NamespaceArg       = "namespace"
+	ObjectArg          = "object"
+	SubscribeToAllArg  = "subscribeToAll"
+	SortByArg          = "sortBy"
+	DryRunArg          = "dryRun"
+	LabelsArg          = "labels"
+	TemplateArg        = "template"
+	ConfirmationArg    = "confirmation"
+	PreviewArg         = "preview"
+	ConsistencyArg     = "consistency"
+	ResourceVersionArg = "resourceVersion"
+)
+
+var (
+	ErrNamespaceBound        = errors.New("the endpoint is bound to the namespace")
+	ErrClusterScopedResource = errors.New("cluster-scoped resources are not available on endpoints bound to a namespace")
 )
 
 // FieldConfigArgumentsBuilder helps construct GraphQL field config arguments
@@ -67,10 +83,26 @@ func (b *FieldConfigArgumentsBuilder) WithObject(resourceInputType *graphql.Inpu
 	return b
 }
 
+func (b *FieldConfigArgumentsBuilder) WithYAML() *FieldConfigArgumentsBuilder {
+	b.arguments[YAMLArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "The object as YAML manifest, apiVersion and kind may be left out",
+	}
+	return b
+}
+
 func (b *FieldConfigArgumentsBuilder) WithDryRun() *FieldConfigArgumentsBuilder {
 	b.arguments[DryRunArg] = &graphql.ArgumentConfig{
 		Type:        graphql.Boolean,
-		Description: "If true, the operation will be performed in dry-run mode",
+		Description: "If true, the API server validates and defaults the change without persisting it, the result shows the object as it would be stored",
+	}
+	return b
+}
+
+func (b *FieldConfigArgumentsBuilder) WithPreview() *FieldConfigArgumentsBuilder {
+	b.arguments[PreviewArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Boolean,
+		Description: "If true, the affected objects are only listed and nothing is changed",
 	}
 	return b
 }
@@ -84,12 +116,176 @@ func (b *FieldConfigArgumentsBuilder) WithSubscribeToAll() *FieldConfigArguments
 	return b
 }
 
+// WithEventBatching adds the arguments coalescing rapid changes of a subscription into fewer updates
+func (b *FieldConfigArgumentsBuilder) WithEventBatching() *FieldConfigArgumentsBuilder {
+	b.arguments[DebounceMsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Emit an update only once no further change happened for this many milliseconds",
+	}
+	b.arguments[BatchWindowMsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Emit the changes within this many milliseconds after the first one as a single update",
+	}
+	b.arguments[MaxBatchSizeArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Emit an update as soon as this many changes are pending, regardless of debounceMs and batchWindowMs",
+	}
+	return b
+}
+
 func (b *FieldConfigArgumentsBuilder) WithSortBy() *FieldConfigArgumentsBuilder {
 	b.arguments[SortByArg] = &graphql.ArgumentConfig{
 		Type:         graphql.String,
-		Description:  "The field to sort the results by",
+		Description:  "The dot separated path of the field to sort the results by, e.g. metadata.creationTimestamp or status.phase",
 		DefaultValue: "metadata.name",
 	}
+	b.arguments[SortDirectionArg] = &graphql.ArgumentConfig{
+		Type:         sortDirectionEnum,
+		Description:  "The order in which the results are sorted by the sortBy field",
+		DefaultValue: SortDirectionAsc,
+	}
+	return b
+}
+
+// WithApply adds the field manager and force flag of Server-Side Apply
+func (b *FieldConfigArgumentsBuilder) WithApply() *FieldConfigArgumentsBuilder {
+	b.arguments[FieldManagerArg] = &graphql.ArgumentConfig{
+		Type:         graphql.String,
+		DefaultValue: DefaultFieldManager,
+		Description:  "The field manager that owns the applied fields",
+	}
+	b.arguments[ForceArg] = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "If true, fields owned by other field managers are taken over instead of failing with a conflict",
+	}
+	return b
+}
+
+// WithReplicas adds the desired replicas of the scale mutations
+func (b *FieldConfigArgumentsBuilder) WithReplicas() *FieldConfigArgumentsBuilder {
+	b.arguments[ReplicasArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: "The desired amount of replicas",
+	}
+	return b
+}
+
+// WithGracePeriodSeconds adds the grace period of an eviction
+func (b *FieldConfigArgumentsBuilder) WithGracePeriodSeconds() *FieldConfigArgumentsBuilder {
+	b.arguments[GracePeriodSecondsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Seconds the object is given to terminate, the default grace period of the object if not set",
+	}
+	return b
+}
+
+// WithTokenRequest adds the expiration and the audiences of a token request
+func (b *FieldConfigArgumentsBuilder) WithTokenRequest() *FieldConfigArgumentsBuilder {
+	b.arguments[ExpirationSecondsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Requested lifetime of the token in seconds, the API server may shorten it",
+	}
+	b.arguments[AudiencesArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+		Description: "Audiences the token is issued for, the audiences of the API server if not set",
+	}
+	return b
+}
+
+// WithProxy adds the path and the port of a proxy request
+func (b *FieldConfigArgumentsBuilder) WithProxy() *FieldConfigArgumentsBuilder {
+	b.arguments[PathArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Path requested from the object, e.g. /healthz",
+	}
+	b.arguments[PortArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Name or number of the port the request is sent to, the first port of the object if not set",
+	}
+	return b
+}
+
+// WithFilter adds the filters on the fields of the objects, see ListFilter
+func (b *FieldConfigArgumentsBuilder) WithFilter() *FieldConfigArgumentsBuilder {
+	b.arguments[FilterArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(listFilterInput)),
+		Description: "Filters on the fields of the objects, all of them must match",
+	}
+	return b
+}
+
+// WithPagination adds the page size and the cursor of the page to continue after
+func (b *FieldConfigArgumentsBuilder) WithPagination() *FieldConfigArgumentsBuilder {
+	b.arguments[FirstArg] = &graphql.ArgumentConfig{
+		Type:         graphql.Int,
+		DefaultValue: DefaultPageSize,
+		Description:  "The maximum amount of objects of the page",
+	}
+	b.arguments[AfterArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The endCursor of the previous page, the first page is returned if empty",
+	}
+	return b
+}
+
+// WithConsistency adds the consistency of list reads and the resource version it refers to
+func (b *FieldConfigArgumentsBuilder) WithConsistency() *FieldConfigArgumentsBuilder {
+	b.arguments[ConsistencyArg] = &graphql.ArgumentConfig{
+		Type:        consistencyEnum,
+		Description: "How up to date the objects must be, the most recent objects are read if not set",
+	}
+	b.arguments[ResourceVersionArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The resource version the consistency refers to, required for EXACT",
+	}
+	return b
+}
+
+// WithFresh adds the bypass of the read cache, see WithReadCache
+func (b *FieldConfigArgumentsBuilder) WithFresh() *FieldConfigArgumentsBuilder {
+	b.arguments[FreshArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Boolean,
+		Description: "Read the objects from the API server instead of the cache of the gateway",
+	}
+	return b
+}
+
+// WithEventFilters adds the arguments events can be selected by, see EventGVK
+func (b *FieldConfigArgumentsBuilder) WithEventFilters() *FieldConfigArgumentsBuilder {
+	b.arguments[SinceTimeArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Only events that occurred at or after this RFC 3339 timestamp",
+	}
+	b.arguments[ReasonArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The reason of the events, e.g. BackOff",
+	}
+	b.arguments[EventTypeArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The type of the events, Normal or Warning",
+	}
+	b.arguments[InvolvedObjectKindArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The kind of the object the events are about, e.g. Pod",
+	}
+	b.arguments[InvolvedObjectNameArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The name of the object the events are about",
+	}
+	return b
+}
+
+// WithObjectEvents adds the arguments of the events field of the resource types, see ObjectEvents
+func (b *FieldConfigArgumentsBuilder) WithObjectEvents() *FieldConfigArgumentsBuilder {
+	b.arguments[LimitArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "The maximum amount of events, the most recent are kept",
+	}
+	b.arguments[SinceSecondsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.Int,
+		Description: "Only events that occurred within this amount of seconds",
+	}
 	return b
 }
 
@@ -148,25 +344,74 @@ func getBoolArg(args map[string]interface{}, key string, required bool) (bool, e
 	return res, nil
 }
 
+// getStringMapArg returns a map argument as map[string]string, skipping non-string values
+func getStringMapArg(args map[string]interface{}, key string) map[string]string {
+	switch val := args[key].(type) {
+	case map[string]string:
+		return val
+	case map[string]interface{}:
+		result := make(map[string]string, len(val))
+		for k, v := range val {
+			if str, ok := v.(string); ok {
+				result[k] = str
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+// getNamespaceArg returns the namespace argument of an operation on resources of the given scope, "" for cluster-scoped
+// resources. Operations of endpoints bound to a namespace, see roundtripper.NamespaceKey, are run in that namespace if
+// the argument is omitted, other namespaces and cluster-scoped resources are rejected.
+func getNamespaceArg(ctx context.Context, args map[string]interface{}, scope apiextensionsv1.ResourceScope, required bool) (string, error) {
+	bound, _ := ctx.Value(roundtripper.NamespaceKey{}).(string)
+	if !isResourceNamespaceScoped(scope) {
+		if bound != "" {
+			return "", ErrClusterScopedResource
+		}
+		return "", nil
+	}
+	if bound == "" {
+		return getStringArg(args, NamespaceArg, required)
+	}
+
+	namespace, err := getStringArg(args, NamespaceArg, false)
+	if err != nil {
+		return "", err
+	}
+	if namespace != "" && namespace != bound {
+		return "", fmt.Errorf("%w %q", ErrNamespaceBound, bound)
+	}
+
+	return bound, nil
+}
+
 func isResourceNamespaceScoped(resourceScope apiextensionsv1.ResourceScope) bool {
 	return resourceScope == apiextensionsv1.NamespaceScoped
 }
 
 func validateSortBy(items []unstructured.Unstructured, fieldPath string) error {
+	segments := strings.Split(fieldPath, ".")
+	if slices.Contains(segments, "") {
+		return errors.New("specified sortBy field is not a dot separated path")
+	}
+
 	if len(items) == 0 {
 		return nil // No items to validate against, assume valid
 	}
 
-	sample := items[0]
-	segments := strings.Split(fieldPath, ".")
-
-	_, found, err := unstructured.NestedFieldNoCopy(sample.Object, segments...)
-	if !found {
-		return errors.New("specified sortBy field does not exist")
-	}
-	if err != nil {
-		return errors.Join(errors.New("error accessing specified sortBy field"), err)
+	// objects may omit optional fields like status.phase, so the field only has to exist in one of them
+	for _, item := range items {
+		_, found, err := unstructured.NestedFieldNoCopy(item.Object, segments...)
+		if err != nil {
+			return errors.Join(errors.New("error accessing specified sortBy field"), err)
+		}
+		if found {
+			return nil
+		}
 	}
 
-	return nil
+	return errors.New("specified sortBy field does not exist")
 }
@@ -1,10 +1,15 @@
 package resolver_test
 
 import (
+	"context"
 	"errors"
-	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
-	"github.com/stretchr/testify/assert"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
 )
 
 func TestGetStrArg(t *testing.T) {
@@ -68,3 +73,73 @@ func TestGetBoolArg(t *testing.T) {
 		})
 	}
 }
+
+func TestGetNamespaceArg(t *testing.T) {
+	tests := []struct {
+		name        string
+		bound       string
+		args        map[string]interface{}
+		scope       v1.ResourceScope
+		required    bool
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:     "unbound",
+			args:     map[string]interface{}{resolver.NamespaceArg: "default"},
+			scope:    v1.NamespaceScoped,
+			required: true,
+			expected: "default",
+		},
+		{
+			name:     "unbound_cluster_scoped",
+			scope:    v1.ClusterScoped,
+			required: true,
+		},
+		{
+			name:     "bound_namespace_is_injected",
+			bound:    "team-a",
+			args:     map[string]interface{}{},
+			scope:    v1.NamespaceScoped,
+			required: true,
+			expected: "team-a",
+		},
+		{
+			name:     "bound_namespace_is_accepted",
+			bound:    "team-a",
+			args:     map[string]interface{}{resolver.NamespaceArg: "team-a"},
+			scope:    v1.NamespaceScoped,
+			expected: "team-a",
+		},
+		{
+			name:        "other_namespace_is_rejected",
+			bound:       "team-a",
+			args:        map[string]interface{}{resolver.NamespaceArg: "team-b"},
+			scope:       v1.NamespaceScoped,
+			expectedErr: resolver.ErrNamespaceBound,
+		},
+		{
+			name:        "cluster_scoped_is_rejected",
+			bound:       "team-a",
+			scope:       v1.ClusterScoped,
+			expectedErr: resolver.ErrClusterScopedResource,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.bound != "" {
+				ctx = context.WithValue(ctx, roundtripper.NamespaceKey{}, tt.bound)
+			}
+
+			namespace, err := resolver.GetNamespaceArg(ctx, tt.args, tt.scope, tt.required)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, namespace)
+		})
+	}
+}
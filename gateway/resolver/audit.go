@@ -0,0 +1,107 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+)
+
+// auditedWithoutBefore are the operations whose object doesn't exist before or isn't changed by them, so it isn't
+// read for the diff
+var auditedWithoutBefore = map[string]bool{
+	"create":          true,
+	"createToken":     true,
+	"createNamespace": true,
+	"deleteMatching":  true,
+}
+
+// WithAudit records every mutation with the identity of the caller and the diff of the object, nil disables the audit log
+func (r *Service) WithAudit(auditor *audit.Auditor) *Service {
+	r.auditor = auditor
+	return r
+}
+
+// audited records the mutation fn with the auditor, the operation is named like in the logs of the resolvers. The object
+// is read before the mutation, so that the event carries the diff to the object returned by fn.
+func (r *Service) audited(operation string, gvk schema.GroupVersionKind, scope v1.ResourceScope, fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if r.auditor == nil {
+			return fn(p)
+		}
+
+		gvk := gvk
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+		event := audit.Event{
+			Operation: operation,
+			Group:     gvk.Group,
+			Version:   gvk.Version,
+			Kind:      gvk.Kind,
+		}
+		// The arguments are read before fn, which may change the object input
+		event.Name, _ = getStringArg(p.Args, NameArg, false)
+		if object, ok := p.Args[ObjectArg].(map[string]interface{}); ok && event.Name == "" {
+			event.Name = (&unstructured.Unstructured{Object: object}).GetName()
+		}
+		event.Namespace, _ = getNamespaceArg(p.Context, p.Args, scope, false)
+		event.LabelSelector, _ = getStringArg(p.Args, LabelSelectorArg, false)
+		event.DryRun, _ = getDryRunArg(p.Context, p.Args)
+		if preview, _ := getBoolArg(p.Args, PreviewArg, false); preview {
+			return fn(p)
+		}
+
+		var before map[string]interface{}
+		if !auditedWithoutBefore[operation] && event.Name != "" {
+			obj := &unstructured.Unstructured{}
+			obj.SetGroupVersionKind(gvk)
+			if err := r.getObject(p.Context, client.ObjectKey{Namespace: event.Namespace, Name: event.Name}, obj); err == nil {
+				// The returned objects are redacted, so the object before has to be as well to not show masked fields as changed
+				r.redact(p.Context, gvk, obj.Object)
+				before = obj.Object
+			}
+		}
+
+		result, err := fn(p)
+
+		var after map[string]interface{}
+		switch value := result.(type) {
+		case map[string]interface{}:
+			if _, ok := value["metadata"]; ok {
+				after = value
+			}
+		case ScaleResult:
+			replicas, _, _ := unstructured.NestedFieldNoCopy(before, "spec", "replicas")
+			event.Diff = []audit.Change{{Path: "/spec/replicas", Before: replicas, After: value.Replicas}}
+			before = nil
+		case DeleteMatchingResult:
+			event.Names = value.Names
+		}
+
+		r.recordAudit(p.Context, event, before, after, err)
+		return result, err
+	}
+}
+
+// recordAudit completes the event with the result of the mutation and the diff of the object before and after it, and
+// records it if the audit log is enabled
+func (r *Service) recordAudit(ctx context.Context, event audit.Event, before, after map[string]interface{}, err error) {
+	if r.auditor == nil {
+		return
+	}
+
+	event.Result = audit.ResultSuccess
+	switch {
+	case err != nil:
+		event.Result = audit.ResultError
+		event.Error = err.Error()
+		event.Diff = nil
+	case before != nil || after != nil:
+		event.Diff = audit.Diff(before, after)
+	}
+	r.auditor.Record(ctx, event)
+}
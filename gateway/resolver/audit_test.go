@@ -0,0 +1,146 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+type auditEvents []audit.Event
+
+func (e *auditEvents) Write(_ context.Context, event audit.Event) error {
+	*e = append(*e, event)
+	return nil
+}
+
+func TestAudit(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	tests := []struct {
+		name           string
+		resolve        func(r *resolver.Service) graphql.FieldResolveFn
+		args           map[string]any
+		expectedOp     string
+		expectedName   string
+		expectedResult string
+		expectedDryRun bool
+		expectedChange *audit.Change
+	}{
+		{
+			name:       "create",
+			expectedOp: "create",
+			resolve: func(r *resolver.Service) graphql.FieldResolveFn {
+				return r.CreateItem(configMapGVK, apiextensionsv1.NamespaceScoped)
+			},
+			args: map[string]any{
+				resolver.NamespaceArg: "default",
+				resolver.ObjectArg: map[string]any{
+					"metadata": map[string]any{"name": "created"},
+					"data":     map[string]any{"mode": "a"},
+				},
+			},
+			expectedName:   "created",
+			expectedResult: audit.ResultSuccess,
+			expectedChange: &audit.Change{Path: "/data/mode", After: "a"},
+		},
+		{
+			name:       "update",
+			expectedOp: "update",
+			resolve: func(r *resolver.Service) graphql.FieldResolveFn {
+				return r.UpdateItem(configMapGVK, apiextensionsv1.NamespaceScoped)
+			},
+			args: map[string]any{
+				resolver.NameArg:      "settings",
+				resolver.NamespaceArg: "default",
+				resolver.ObjectArg:    map[string]any{"data": map[string]any{"mode": "b"}},
+				resolver.DryRunArg:    true,
+			},
+			expectedName:   "settings",
+			expectedResult: audit.ResultSuccess,
+			expectedDryRun: true,
+			expectedChange: &audit.Change{Path: "/data/mode", Before: "a", After: "b"},
+		},
+		{
+			name:       "delete",
+			expectedOp: "delete",
+			resolve: func(r *resolver.Service) graphql.FieldResolveFn {
+				return r.DeleteItem(configMapGVK, apiextensionsv1.NamespaceScoped)
+			},
+			args: map[string]any{
+				resolver.NameArg:      "settings",
+				resolver.NamespaceArg: "default",
+			},
+			expectedName:   "settings",
+			expectedResult: audit.ResultSuccess,
+			expectedChange: &audit.Change{Path: "/data/mode", Before: "a"},
+		},
+		{
+			name:       "update_missing_object",
+			expectedOp: "update",
+			resolve: func(r *resolver.Service) graphql.FieldResolveFn {
+				return r.UpdateItem(configMapGVK, apiextensionsv1.NamespaceScoped)
+			},
+			args: map[string]any{
+				resolver.NameArg:      "missing",
+				resolver.NamespaceArg: "default",
+				resolver.ObjectArg:    map[string]any{"data": map[string]any{"mode": "b"}},
+			},
+			expectedName:   "missing",
+			expectedResult: audit.ResultError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The fake client ignores dry-run patches, the API server returns the patched object without persisting it.
+			// Every case has its own client, so the patch is simply applied.
+			runtimeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+				Data:       map[string]string{"mode": "a"},
+			}).WithInterceptorFuncs(interceptor.Funcs{
+				Patch: func(ctx context.Context, clt client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					return clt.Patch(ctx, obj, patch)
+				},
+			}).Build()
+
+			var events auditEvents
+			auditor := audit.NewAuditor(testlogger.New().HideLogOutput().Logger, &events, "production", func(context.Context) (string, []string) {
+				return "jane@example.com", []string{"admins"}
+			})
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithAudit(auditor)
+
+			_, err := tt.resolve(r)(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			assert.Equal(t, tt.expectedResult == audit.ResultError, err != nil)
+
+			require.Len(t, events, 1)
+			event := events[0]
+			assert.Equal(t, tt.expectedOp, event.Operation)
+			assert.Equal(t, "jane@example.com", event.User)
+			assert.Equal(t, "production", event.Cluster)
+			assert.Equal(t, "ConfigMap", event.Kind)
+			assert.Equal(t, "default", event.Namespace)
+			assert.Equal(t, tt.expectedName, event.Name)
+			assert.Equal(t, tt.expectedResult, event.Result)
+			assert.Equal(t, tt.expectedDryRun, event.DryRun)
+			if tt.expectedChange == nil {
+				assert.NotEmpty(t, event.Error)
+				assert.Empty(t, event.Diff)
+				return
+			}
+			assert.Contains(t, event.Diff, *tt.expectedChange)
+		})
+	}
+}
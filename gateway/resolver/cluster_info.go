@@ -0,0 +1,166 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+)
+
+// ClusterInfoCacheTTL is how long the version and the API groups of a cluster are cached
+const ClusterInfoCacheTTL = 5 * time.Minute
+
+var ErrClusterInfoUnavailable = errors.New("cluster info is not available for this cluster")
+
+// ClusterInfo describes the version and the API groups served by a cluster, so that frontends can detect its features
+type ClusterInfo struct {
+	Version   string         `json:"version"`
+	Platform  string         `json:"platform"`
+	APIGroups []APIGroupInfo `json:"apiGroups"`
+}
+
+// APIGroupInfo is an API group served by a cluster, the core group has an empty name
+type APIGroupInfo struct {
+	Name             string   `json:"name"`
+	Versions         []string `json:"versions"`
+	PreferredVersion string   `json:"preferredVersion"`
+}
+
+// clusterInfoCache stores the ClusterInfo per kcp workspace, the key of other clusters is empty
+type clusterInfoCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]clusterInfoEntry
+}
+
+type clusterInfoEntry struct {
+	info      ClusterInfo
+	fetchedAt time.Time
+}
+
+func (c *clusterInfoCache) get(key string, now time.Time) (ClusterInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.Sub(entry.fetchedAt) > c.ttl {
+		return ClusterInfo{}, false
+	}
+	return entry.info, true
+}
+
+func (c *clusterInfoCache) set(key string, info ClusterInfo, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = clusterInfoEntry{info: info, fetchedAt: now}
+}
+
+// WithDiscovery enables the clusterInfo query. discoveryClient must send its requests to the root of the API server,
+// like the REST client of a discovery.DiscoveryClient.
+func (r *Service) WithDiscovery(discoveryClient rest.Interface) *Service {
+	r.discoveryClient = discoveryClient
+	r.clusterInfo = &clusterInfoCache{
+		ttl:     ClusterInfoCacheTTL,
+		entries: make(map[string]clusterInfoEntry),
+	}
+	return r
+}
+
+// ClusterInfo returns the version and the API groups of the cluster from /version and the discovery endpoints.
+// The requests are sent with the credentials of the caller, the result is cached for ClusterInfoCacheTTL, as
+// the discovery endpoints are readable by every authenticated user.
+func (r *Service) ClusterInfo() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "ClusterInfo")
+		defer span.End()
+
+		if r.discoveryClient == nil {
+			return nil, ErrClusterInfoUnavailable
+		}
+
+		var key, pathPrefix string
+		if workspace, ok := kontext.ClusterFrom(ctx); ok && !workspace.Empty() {
+			// kcp serves the workspaces below /clusters/<workspace> of its host
+			key, pathPrefix = workspace.String(), workspace.Path().RequestPath()
+		}
+
+		if info, ok := r.clusterInfo.get(key, time.Now()); ok {
+			return info, nil
+		}
+
+		info, err := r.fetchClusterInfo(ctx, pathPrefix)
+		if err != nil {
+			r.log.Error().Err(err).Str("workspace", key).Msg("Unable to discover cluster info")
+			return nil, err
+		}
+
+		r.clusterInfo.set(key, info, time.Now())
+		return info, nil
+	}
+}
+
+func (r *Service) fetchClusterInfo(ctx context.Context, pathPrefix string) (ClusterInfo, error) {
+	var serverVersion version.Info
+	if err := r.getDiscovery(ctx, pathPrefix+"/version", &serverVersion); err != nil {
+		return ClusterInfo{}, err
+	}
+
+	var coreVersions metav1.APIVersions
+	if err := r.getDiscovery(ctx, pathPrefix+"/api", &coreVersions); err != nil {
+		return ClusterInfo{}, err
+	}
+
+	var groups metav1.APIGroupList
+	if err := r.getDiscovery(ctx, pathPrefix+"/apis", &groups); err != nil {
+		return ClusterInfo{}, err
+	}
+
+	info := ClusterInfo{
+		Version:   serverVersion.GitVersion,
+		Platform:  serverVersion.Platform,
+		APIGroups: make([]APIGroupInfo, 0, len(groups.Groups)+1),
+	}
+
+	if len(coreVersions.Versions) > 0 {
+		info.APIGroups = append(info.APIGroups, APIGroupInfo{
+			Versions:         coreVersions.Versions,
+			PreferredVersion: coreVersions.Versions[0],
+		})
+	}
+
+	for _, group := range groups.Groups {
+		versions := make([]string, 0, len(group.Versions))
+		for _, groupVersion := range group.Versions {
+			versions = append(versions, groupVersion.Version)
+		}
+		info.APIGroups = append(info.APIGroups, APIGroupInfo{
+			Name:             group.Name,
+			Versions:         versions,
+			PreferredVersion: group.PreferredVersion.Version,
+		})
+	}
+
+	return info, nil
+}
+
+func (r *Service) getDiscovery(ctx context.Context, path string, into interface{}) error {
+	body, err := r.discoveryClient.Get().AbsPath(path).Do(ctx).Raw()
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(body, into); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// InputCoercion controls how mutation inputs that do not match the OpenAPI types of a resource are handled
+type InputCoercion string
+
+const (
+	// InputCoercionDisabled passes the input to the API server as is
+	InputCoercionDisabled InputCoercion = ""
+	// InputCoercionLenient converts strings to integers, numbers and booleans where the OpenAPI schema expects them
+	InputCoercionLenient InputCoercion = "lenient"
+	// InputCoercionStrict rejects strings where the OpenAPI schema expects integers, numbers or booleans
+	InputCoercionStrict InputCoercion = "strict"
+)
+
+var (
+	ErrUnknownInputCoercion = errors.New("unknown input coercion mode")
+	ErrInputTypeMismatch    = errors.New("input value does not match the schema type")
+)
+
+// ParseInputCoercion validates the configured input coercion mode
+func ParseInputCoercion(mode string) (InputCoercion, error) {
+	switch InputCoercion(mode) {
+	case InputCoercionDisabled, InputCoercionLenient, InputCoercionStrict:
+		return InputCoercion(mode), nil
+	default:
+		return InputCoercionDisabled, fmt.Errorf("%w: %s", ErrUnknownInputCoercion, mode)
+	}
+}
+
+// WithInputCoercion enables the schema aware coercion of create and update inputs. The typed fields of the inputs are
+// coerced in the variables of the mutations by CoerceVariables, the untyped ones, e.g. of JSON scalars and YAML
+// inputs, by the resolvers. The definitions are also used to default the inputs of creations and to validate YAML
+// inputs, regardless of the mode.
+func (r *Service) WithInputCoercion(mode InputCoercion, definitions spec.Definitions) *Service {
+	r.inputCoercion = mode
+	r.definitions = definitions
+
+	r.definitionsByGVK = make(map[schema.GroupVersionKind]string, len(definitions))
+	for key, definition := range definitions {
+		if gvk, ok := common.GroupVersionKind(definition.Extensions); ok {
+			r.definitionsByGVK[gvk] = key
+		}
+	}
+
+	return r
+}
+
+// coerceObjectInput adjusts the object input of a mutation in place according to the OpenAPI schema of the resource.
+// gvk must contain the original group name. Only the values graphql-go passes through as given, those of JSON scalars
+// and YAML inputs, can still be strings here, see CoerceVariables for the typed fields.
+func (r *Service) coerceObjectInput(gvk schema.GroupVersionKind, object map[string]any) error {
+	if r.inputCoercion == InputCoercionDisabled {
+		return nil
+	}
+
+	key, ok := r.definitionsByGVK[gvk]
+	if !ok {
+		return nil
+	}
+
+	_, err := r.coerceValue(r.definitions[key], object, "object", map[string]bool{key: true})
+	return err
+}
+
+// coerceValue returns the value converted to the type of the schema, visitedRefs prevents endless recursion
+func (r *Service) coerceValue(s spec.Schema, value any, path string, visitedRefs map[string]bool) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	if refKey := strings.TrimPrefix(s.Ref.String(), "#/definitions/"); refKey != "" {
+		refSchema, ok := r.definitions[refKey]
+		if !ok || visitedRefs[refKey] {
+			return value, nil
+		}
+
+		visitedRefs[refKey] = true
+		defer delete(visitedRefs, refKey)
+
+		return r.coerceValue(refSchema, value, path, visitedRefs)
+	}
+
+	if intOrString, _ := s.Extensions[common.IntOrStringExtensionKey].(bool); intOrString {
+		return r.coerceScalar("integer", value, path, true)
+	}
+
+	if len(s.Type) == 0 {
+		return value, nil
+	}
+
+	switch s.Type[0] {
+	case "integer", "number", "boolean":
+		return r.coerceScalar(s.Type[0], value, path, false)
+	case "array":
+		items, ok := value.([]any)
+		if !ok || s.Items == nil || s.Items.Schema == nil {
+			return value, nil
+		}
+
+		for i, item := range items {
+			coerced, err := r.coerceValue(*s.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i), visitedRefs)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = coerced
+		}
+
+		return items, nil
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+
+		for name, fieldValue := range object {
+			fieldSchema, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil {
+					continue
+				}
+				fieldSchema = *s.AdditionalProperties.Schema
+			}
+
+			coerced, err := r.coerceValue(fieldSchema, fieldValue, path+"."+name, visitedRefs)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = coerced
+		}
+
+		return object, nil
+	default:
+		return value, nil
+	}
+}
+
+// coerceScalar converts string values to the scalar type expected by the schema.
+// For int-or-string fields only strings holding an integer are converted, any other string is kept.
+func (r *Service) coerceScalar(schemaType string, value any, path string, intOrString bool) (any, error) {
+	str, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	if intOrString && r.inputCoercion == InputCoercionStrict {
+		return value, nil
+	}
+
+	var coerced any
+	var err error
+	switch schemaType {
+	case "integer":
+		coerced, err = strconv.ParseInt(strings.TrimSpace(str), 10, 64)
+	case "number":
+		coerced, err = strconv.ParseFloat(strings.TrimSpace(str), 64)
+	case "boolean":
+		coerced, err = strconv.ParseBool(strings.TrimSpace(str))
+	}
+
+	if intOrString {
+		if err != nil {
+			return value, nil
+		}
+		return coerced, nil
+	}
+
+	if r.inputCoercion == InputCoercionStrict || err != nil {
+		return nil, fmt.Errorf("%w: %s expects %s, got %q", ErrInputTypeMismatch, path, schemaType, str)
+	}
+
+	return coerced, nil
+}
+
+// CoerceVariables adjusts the variables of a mutation in place according to the input types of their definitions, it
+// must be called before the operation is executed. graphql-go coerces variables on its own before the resolvers run, it
+// turns the string "3" into the Int 3 and every string except "false" into the Boolean true, so the Int, Float and
+// Boolean fields of the inputs are coerced before. Queries, subscriptions and operations that don't parse or can't be
+// found are left to graphql-go.
+func (r *Service) CoerceVariables(gqlSchema *graphql.Schema, query, operationName string, variables map[string]any) error {
+	if r.inputCoercion == InputCoercionDisabled || len(variables) == 0 {
+		return nil
+	}
+
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return nil
+	}
+
+	for _, definition := range document.Definitions {
+		operation, ok := definition.(*ast.OperationDefinition)
+		if !ok || operationName != "" && (operation.Name == nil || operation.Name.Value != operationName) {
+			continue
+		}
+		if operation.Operation != ast.OperationTypeMutation {
+			return nil
+		}
+
+		for _, variable := range operation.VariableDefinitions {
+			name := variable.Variable.Name.Value
+			value, ok := variables[name]
+			if !ok {
+				continue
+			}
+
+			coerced, err := r.coerceVariable(gqlSchema, variable.Type, value, "$"+name)
+			if err != nil {
+				return err
+			}
+			variables[name] = coerced
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// coerceVariable resolves the type of a variable definition and coerces its value
+func (r *Service) coerceVariable(gqlSchema *graphql.Schema, typ ast.Type, value any, path string) (any, error) {
+	switch typ := typ.(type) {
+	case *ast.NonNull:
+		return r.coerceVariable(gqlSchema, typ.Type, value, path)
+	case *ast.List:
+		items, ok := value.([]any)
+		if !ok {
+			// A single value is accepted for a list of one item
+			return r.coerceVariable(gqlSchema, typ.Type, value, path)
+		}
+		for i, item := range items {
+			coerced, err := r.coerceVariable(gqlSchema, typ.Type, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = coerced
+		}
+		return items, nil
+	case *ast.Named:
+		namedType := gqlSchema.Type(typ.Name.Value)
+		if namedType == nil {
+			return value, nil
+		}
+		return r.coerceInputValue(namedType, value, path)
+	default:
+		return value, nil
+	}
+}
+
+// coerceInputValue converts the strings given for Int, Float and Boolean fields of input objects
+func (r *Service) coerceInputValue(typ graphql.Type, value any, path string) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch typ := typ.(type) {
+	case *graphql.NonNull:
+		return r.coerceInputValue(typ.OfType, value, path)
+	case *graphql.List:
+		items, ok := value.([]any)
+		if !ok {
+			return r.coerceInputValue(typ.OfType, value, path)
+		}
+		for i, item := range items {
+			coerced, err := r.coerceInputValue(typ.OfType, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = coerced
+		}
+		return items, nil
+	case *graphql.InputObject:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+		fields := typ.Fields()
+		for name, fieldValue := range object {
+			field, ok := fields[name]
+			if !ok {
+				continue
+			}
+			coerced, err := r.coerceInputValue(field.Type, fieldValue, path+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			object[name] = coerced
+		}
+		return object, nil
+	case *graphql.Scalar:
+		switch typ {
+		case graphql.Int:
+			return r.coerceScalar("integer", value, path, false)
+		case graphql.Float:
+			return r.coerceScalar("number", value, path, false)
+		case graphql.Boolean:
+			return r.coerceScalar("boolean", value, path, false)
+		}
+		return value, nil
+	default:
+		return value, nil
+	}
+}
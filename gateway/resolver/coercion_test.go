@@ -0,0 +1,264 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	gatewaySchema "github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+var deploymentGVK = schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+
+func deploymentDefinitions() spec.Definitions {
+	intOrString := spec.Schema{}
+	intOrString.AddExtension("x-kubernetes-int-or-string", true)
+
+	deployment := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/io.k8s.api.apps.v1.DeploymentSpec")}},
+			},
+		},
+	}
+	deployment.AddExtension(common.GVKExtensionKey, []any{
+		map[string]any{"group": "apps", "version": "v1", "kind": "Deployment"},
+	})
+
+	return spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment": deployment,
+		"io.k8s.api.apps.v1.DeploymentSpec": {
+			SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"replicas": *spec.Int32Property(),
+					"paused":   *spec.BoolProperty(),
+					"strategy": {
+						SchemaProps: spec.SchemaProps{
+							Type: spec.StringOrArray{"object"},
+							Properties: map[string]spec.Schema{
+								"maxUnavailable": intOrString,
+							},
+						},
+					},
+					"weights": *spec.ArrayProperty(spec.Float64Property()),
+				},
+			},
+		},
+	}
+}
+
+func TestCoerceObjectInput(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        resolver.InputCoercion
+		gvk         schema.GroupVersionKind
+		input       map[string]any
+		expected    map[string]any
+		expectedErr error
+	}{
+		{
+			name: "lenient_converts_strings",
+			mode: resolver.InputCoercionLenient,
+			gvk:  deploymentGVK,
+			input: map[string]any{"spec": map[string]any{
+				"replicas": "3",
+				"paused":   "true",
+				"strategy": map[string]any{"maxUnavailable": "1"},
+				"weights":  []any{"0.5", 1.5},
+			}},
+			expected: map[string]any{"spec": map[string]any{
+				"replicas": int64(3),
+				"paused":   true,
+				"strategy": map[string]any{"maxUnavailable": int64(1)},
+				"weights":  []any{0.5, 1.5},
+			}},
+		},
+		{
+			name:     "lenient_keeps_int_or_string_percentages",
+			mode:     resolver.InputCoercionLenient,
+			gvk:      deploymentGVK,
+			input:    map[string]any{"spec": map[string]any{"strategy": map[string]any{"maxUnavailable": "25%"}}},
+			expected: map[string]any{"spec": map[string]any{"strategy": map[string]any{"maxUnavailable": "25%"}}},
+		},
+		{
+			name:        "lenient_rejects_unparsable_values_ERROR",
+			mode:        resolver.InputCoercionLenient,
+			gvk:         deploymentGVK,
+			input:       map[string]any{"spec": map[string]any{"replicas": "three"}},
+			expectedErr: resolver.ErrInputTypeMismatch,
+		},
+		{
+			name:        "strict_rejects_strings_ERROR",
+			mode:        resolver.InputCoercionStrict,
+			gvk:         deploymentGVK,
+			input:       map[string]any{"spec": map[string]any{"replicas": "3"}},
+			expectedErr: resolver.ErrInputTypeMismatch,
+		},
+		{
+			name:     "strict_accepts_typed_values",
+			mode:     resolver.InputCoercionStrict,
+			gvk:      deploymentGVK,
+			input:    map[string]any{"spec": map[string]any{"replicas": 3, "strategy": map[string]any{"maxUnavailable": "1"}}},
+			expected: map[string]any{"spec": map[string]any{"replicas": 3, "strategy": map[string]any{"maxUnavailable": "1"}}},
+		},
+		{
+			name:     "disabled_keeps_input",
+			mode:     resolver.InputCoercionDisabled,
+			gvk:      deploymentGVK,
+			input:    map[string]any{"spec": map[string]any{"replicas": "3"}},
+			expected: map[string]any{"spec": map[string]any{"replicas": "3"}},
+		},
+		{
+			name:     "unknown_kind_keeps_input",
+			mode:     resolver.InputCoercionLenient,
+			gvk:      schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+			input:    map[string]any{"data": map[string]any{"replicas": "3"}},
+			expected: map[string]any{"data": map[string]any{"replicas": "3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.New(testlogger.New().Logger, nil).WithInputCoercion(tt.mode, deploymentDefinitions())
+
+			err := r.CoerceObjectInput(tt.gvk, tt.input)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tt.input)
+		})
+	}
+}
+
+func TestCoerceVariables(t *testing.T) {
+	definitions := deploymentDefinitions()
+	deployment := definitions["io.k8s.api.apps.v1.Deployment"]
+	deployment.Properties["metadata"] = spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:       spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{"name": *spec.StringProperty()},
+	}}
+	deployment.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions["io.k8s.api.apps.v1.Deployment"] = deployment
+
+	const mutation = `mutation ($object: DeploymentInput!) {
+		apps { createDeployment(namespace: "default", object: $object) { metadata { name } } }
+	}`
+
+	tests := []struct {
+		name          string
+		mode          resolver.InputCoercion
+		query         string
+		spec          map[string]any
+		expectedSpec  map[string]any
+		expectedError string
+	}{
+		{
+			// graphql-go coerces the variables on its own, any string except "false" is true
+			name:         "disabled_coerced_by_graphql",
+			mode:         resolver.InputCoercionDisabled,
+			spec:         map[string]any{"replicas": "3", "paused": "no"},
+			expectedSpec: map[string]any{"replicas": 3, "paused": true},
+		},
+		{
+			name:         "lenient_converts_strings",
+			mode:         resolver.InputCoercionLenient,
+			spec:         map[string]any{"replicas": "3", "paused": "false", "weights": []any{"0.5", 1.5}},
+			expectedSpec: map[string]any{"replicas": 3, "paused": false, "weights": []any{0.5, 1.5}},
+		},
+		{
+			name:          "lenient_rejects_unparsable_values_ERROR",
+			mode:          resolver.InputCoercionLenient,
+			spec:          map[string]any{"replicas": 3, "paused": "no"},
+			expectedError: `$object.spec.paused expects boolean, got "no"`,
+		},
+		{
+			name:          "strict_rejects_strings_ERROR",
+			mode:          resolver.InputCoercionStrict,
+			spec:          map[string]any{"replicas": "3"},
+			expectedError: `$object.spec.replicas expects integer, got "3"`,
+		},
+		{
+			name:         "strict_accepts_typed_values",
+			mode:         resolver.InputCoercionStrict,
+			spec:         map[string]any{"replicas": 3, "paused": true},
+			expectedSpec: map[string]any{"replicas": 3, "paused": true},
+		},
+		{
+			// Only the variables of mutations are coerced, those of queries are left to graphql-go
+			name:  "query_is_not_coerced",
+			mode:  resolver.InputCoercionStrict,
+			query: `query ($object: DeploymentInput!) { __typename }`,
+			spec:  map[string]any{"replicas": "3"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]any
+			runtimeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					created = obj.(*unstructured.Unstructured).Object
+					return nil
+				},
+			}).Build()
+
+			log := testlogger.New().HideLogOutput().Logger
+			r := resolver.New(log, runtimeClient).WithInputCoercion(tt.mode, definitions)
+			g, err := gatewaySchema.New(log, definitions, r)
+			require.NoError(t, err)
+
+			variables := map[string]any{"object": map[string]any{
+				"metadata": map[string]any{"name": "web"},
+				"spec":     tt.spec,
+			}}
+			if tt.query != "" {
+				require.NoError(t, r.CoerceVariables(g.GetSchema(), tt.query, "", variables))
+				assert.Equal(t, tt.spec, variables["object"].(map[string]any)["spec"])
+				return
+			}
+
+			err = r.CoerceVariables(g.GetSchema(), mutation, "", variables)
+			if tt.expectedError != "" {
+				assert.ErrorIs(t, err, resolver.ErrInputTypeMismatch)
+				assert.ErrorContains(t, err, tt.expectedError)
+				return
+			}
+			require.NoError(t, err)
+
+			result := graphql.Do(graphql.Params{
+				Schema:         *g.GetSchema(),
+				RequestString:  mutation,
+				VariableValues: variables,
+				Context:        t.Context(),
+			})
+			require.Empty(t, result.Errors)
+			assert.Equal(t, tt.expectedSpec, created["spec"])
+		})
+	}
+}
+
+func TestParseInputCoercion(t *testing.T) {
+	mode, err := resolver.ParseInputCoercion("lenient")
+	require.NoError(t, err)
+	assert.Equal(t, resolver.InputCoercionLenient, mode)
+
+	_, err = resolver.ParseInputCoercion("loose")
+	assert.ErrorIs(t, err, resolver.ErrUnknownInputCoercion)
+}
@@ -0,0 +1,71 @@
+package resolver
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Consistency selects how up to date the objects returned by a list query must be
+type Consistency string
+
+const (
+	// ConsistencyQuorum reads the most recent objects from etcd, which is the default of the API server
+	ConsistencyQuorum Consistency = "Quorum"
+	// ConsistencyNotOlderThan allows objects at least as recent as the resource version, served from the watch cache
+	ConsistencyNotOlderThan Consistency = "NotOlderThan"
+	// ConsistencyExact reads the objects exactly at the resource version
+	ConsistencyExact Consistency = "Exact"
+)
+
+var ErrResourceVersionRequired = errors.New("resourceVersion is required for consistency EXACT")
+
+var consistencyEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "ListConsistency",
+	Description: "How up to date the objects of a list must be",
+	Values: graphql.EnumValueConfigMap{
+		"QUORUM": &graphql.EnumValueConfig{
+			Value:       ConsistencyQuorum,
+			Description: "The most recent objects, read from etcd",
+		},
+		"NOT_OLDER_THAN": &graphql.EnumValueConfig{
+			Value:       ConsistencyNotOlderThan,
+			Description: "Objects at least as recent as the resource version or any version if not set, served from the cache of the API server",
+		},
+		"EXACT": &graphql.EnumValueConfig{
+			Value:       ConsistencyExact,
+			Description: "Objects exactly at the resource version",
+		},
+	},
+})
+
+// consistencyOptions maps the consistency and resource version arguments to the resourceVersion and
+// resourceVersionMatch list options
+func consistencyOptions(args map[string]interface{}) ([]client.ListOption, error) {
+	consistency, _ := args[ConsistencyArg].(Consistency)
+	resourceVersion, _ := args[ResourceVersionArg].(string)
+
+	switch consistency {
+	case ConsistencyNotOlderThan:
+		// "0" is the oldest resource version, so any state of the cache is accepted
+		if resourceVersion == "" {
+			resourceVersion = "0"
+		}
+		return []client.ListOption{&client.ListOptions{Raw: &metav1.ListOptions{
+			ResourceVersion:      resourceVersion,
+			ResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		}}}, nil
+	case ConsistencyExact:
+		if resourceVersion == "" {
+			return nil, ErrResourceVersionRequired
+		}
+		return []client.ListOption{&client.ListOptions{Raw: &metav1.ListOptions{
+			ResourceVersion:      resourceVersion,
+			ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+		}}}, nil
+	default:
+		return nil, nil
+	}
+}
@@ -4,11 +4,26 @@ import (
 	"github.com/graphql-go/graphql"
 )
 
+// TypeByCategory describes a resource of a category and where its operations are found in the schema, so that
+// generic frontends can build their navigation without knowing how kinds map to fields
 type TypeByCategory struct {
-	Group   string
-	Version string
-	Kind    string
-	Scope   string
+	// Group is the field of the root query and mutation types holding the operations of the kind
+	Group string
+	// APIGroup is the group the kind is served under by the API server
+	APIGroup string
+	Version  string
+	Kind     string
+	Scope    string
+	// Singular and Plural are the fields of the item and list queries below Group
+	Singular string
+	Plural   string
+	// Verbs are the verbs the API server supports for the resource
+	Verbs []string
+	// Subscriptions reports whether the resource can be watched, the subscription fields are empty otherwise
+	Subscriptions        bool
+	SingularSubscription string
+	PluralSubscription   string
+	Description          string
 }
 
 func (r *Service) TypeByCategory(m map[string][]TypeByCategory) graphql.FieldResolveFn {
@@ -21,3 +36,30 @@ func (r *Service) TypeByCategory(m map[string][]TypeByCategory) graphql.FieldRes
 		return m[name], nil
 	}
 }
+
+// ClusterDefaults are presets of a cluster that generic frontends use to tailor their initial views
+type ClusterDefaults struct {
+	DefaultNamespace string   `json:"defaultNamespace,omitempty"`
+	CommonKinds      []string `json:"commonKinds,omitempty"`
+}
+
+// WithClusterDefaults sets the presets returned by the clusterDefaults query
+func (r *Service) WithClusterDefaults(defaults *ClusterDefaults) *Service {
+	r.clusterDefaults = defaults
+	return r
+}
+
+func (r *Service) ClusterDefaults() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if r.clusterDefaults == nil {
+			return ClusterDefaults{CommonKinds: []string{}}, nil
+		}
+
+		defaults := *r.clusterDefaults
+		if defaults.CommonKinds == nil {
+			defaults.CommonKinds = []string{}
+		}
+
+		return defaults, nil
+	}
+}
@@ -0,0 +1,124 @@
+package resolver_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestClusterDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *resolver.ClusterDefaults
+		expected resolver.ClusterDefaults
+	}{
+		{
+			name:     "not_configured",
+			expected: resolver.ClusterDefaults{CommonKinds: []string{}},
+		},
+		{
+			name: "configured",
+			defaults: &resolver.ClusterDefaults{
+				DefaultNamespace: "team-a",
+				CommonKinds:      []string{"Deployment", "ConfigMap"},
+			},
+			expected: resolver.ClusterDefaults{
+				DefaultNamespace: "team-a",
+				CommonKinds:      []string{"Deployment", "ConfigMap"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := resolver.New(testlogger.New().Logger, nil).WithClusterDefaults(tt.defaults)
+
+			result, err := r.ClusterDefaults()(graphql.ResolveParams{})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestClusterInfo(t *testing.T) {
+	responses := map[string]string{
+		"/version": `{"gitVersion": "v1.33.1", "platform": "linux/amd64"}`,
+		"/api":     `{"kind": "APIVersions", "versions": ["v1"]}`,
+		"/apis": `{"kind": "APIGroupList", "groups": [{
+			"name": "gateway.networking.k8s.io",
+			"versions": [{"groupVersion": "gateway.networking.k8s.io/v1", "version": "v1"}, {"groupVersion": "gateway.networking.k8s.io/v1beta1", "version": "v1beta1"}],
+			"preferredVersion": {"groupVersion": "gateway.networking.k8s.io/v1", "version": "v1"}
+		}]}`,
+	}
+
+	var requests, workspaceRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		path, inWorkspace := strings.CutPrefix(r.URL.Path, "/clusters/root:orgs")
+		if inWorkspace {
+			workspaceRequests.Add(1)
+		}
+		response, ok := responses[path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, nil).WithDiscovery(discoveryClient.RESTClient())
+
+	expected := resolver.ClusterInfo{
+		Version:  "v1.33.1",
+		Platform: "linux/amd64",
+		APIGroups: []resolver.APIGroupInfo{
+			{Versions: []string{"v1"}, PreferredVersion: "v1"},
+			{Name: "gateway.networking.k8s.io", Versions: []string{"v1", "v1beta1"}, PreferredVersion: "v1"},
+		},
+	}
+
+	result, err := r.ClusterInfo()(graphql.ResolveParams{Context: context.Background()})
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// The result is cached
+	result, err = r.ClusterInfo()(graphql.ResolveParams{Context: context.Background()})
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// kcp workspaces are discovered below their path and cached separately
+	ctx := kontext.WithCluster(context.Background(), logicalcluster.Name("root:orgs"))
+	result, err = r.ClusterInfo()(graphql.ResolveParams{Context: ctx})
+	require.NoError(t, err)
+	assert.Equal(t, expected, result)
+	assert.Equal(t, int32(6), requests.Load())
+	assert.Equal(t, int32(3), workspaceRequests.Load())
+}
+
+func TestClusterInfo_Unavailable(t *testing.T) {
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, nil)
+
+	_, err := r.ClusterInfo()(graphql.ResolveParams{Context: context.Background()})
+	assert.ErrorIs(t, err, resolver.ErrClusterInfoUnavailable)
+}
@@ -0,0 +1,94 @@
+package resolver
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// applyDefaults sets the fields missing in the object input of a creation to their OpenAPI default, like the API server
+// does for custom resources. Only objects present in the input are defaulted, missing objects are not created for their
+// defaults. gvk must contain the original group name.
+func (r *Service) applyDefaults(gvk schema.GroupVersionKind, object map[string]any) {
+	key, ok := r.definitionsByGVK[gvk]
+	if !ok {
+		return
+	}
+
+	r.defaultValue(r.definitions[key], object, map[string]bool{key: true})
+}
+
+// defaultValue sets the defaults inside of the value, visitedRefs prevents endless recursion
+func (r *Service) defaultValue(s spec.Schema, value any, visitedRefs map[string]bool) {
+	if refKey := strings.TrimPrefix(s.Ref.String(), "#/definitions/"); refKey != "" {
+		refSchema, ok := r.definitions[refKey]
+		if !ok || visitedRefs[refKey] {
+			return
+		}
+
+		visitedRefs[refKey] = true
+		defer delete(visitedRefs, refKey)
+
+		r.defaultValue(refSchema, value, visitedRefs)
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for name, property := range s.Properties {
+			if fieldValue, ok := v[name]; ok {
+				r.defaultValue(property, fieldValue, visitedRefs)
+				continue
+			}
+			if defaultValue, ok := r.propertyDefault(property); ok {
+				v[name] = defaultValue
+			}
+		}
+
+		if s.AdditionalProperties == nil || s.AdditionalProperties.Schema == nil {
+			return
+		}
+		for name, fieldValue := range v {
+			if _, ok := s.Properties[name]; !ok {
+				r.defaultValue(*s.AdditionalProperties.Schema, fieldValue, visitedRefs)
+			}
+		}
+	case []any:
+		if s.Items == nil || s.Items.Schema == nil {
+			return
+		}
+		for _, item := range v {
+			r.defaultValue(*s.Items.Schema, item, visitedRefs)
+		}
+	}
+}
+
+// propertyDefault returns a copy of the default of a property. A default next to a reference takes precedence over the
+// default of the definition.
+func (r *Service) propertyDefault(property spec.Schema) (any, bool) {
+	defaultValue := property.Default
+	if defaultValue == nil {
+		if refKey := strings.TrimPrefix(property.Ref.String(), "#/definitions/"); refKey != "" {
+			defaultValue = r.definitions[refKey].Default
+		}
+	}
+	if defaultValue == nil {
+		return nil, false
+	}
+
+	// The round trip copies the default, so that objects don't share it, and turns whole numbers into integers
+	data, err := json.Marshal(defaultValue)
+	if err != nil {
+		r.log.Debug().Err(err).Msg("Failed to marshal default value")
+		return nil, false
+	}
+	var copied any
+	if err := utiljson.Unmarshal(data, &copied); err != nil {
+		r.log.Debug().Err(err).Msg("Failed to unmarshal default value")
+		return nil, false
+	}
+	return copied, true
+}
@@ -0,0 +1,81 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestCreateItem_Defaults(t *testing.T) {
+	definitions := deploymentDefinitions()
+	deploymentSpec := definitions["io.k8s.api.apps.v1.DeploymentSpec"]
+	replicas := deploymentSpec.Properties["replicas"]
+	replicas.Default = float64(1)
+	deploymentSpec.Properties["replicas"] = replicas
+	strategy := deploymentSpec.Properties["strategy"]
+	strategy.Properties["type"] = spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Default: "RollingUpdate"}}
+	deploymentSpec.Properties["strategy"] = strategy
+	definitions["io.k8s.api.apps.v1.DeploymentSpec"] = deploymentSpec
+
+	tests := []struct {
+		name         string
+		spec         map[string]interface{}
+		expectedSpec map[string]interface{}
+	}{
+		{
+			name:         "missing_fields_defaulted",
+			spec:         map[string]interface{}{"strategy": map[string]interface{}{}},
+			expectedSpec: map[string]interface{}{"replicas": int64(1), "strategy": map[string]interface{}{"type": "RollingUpdate"}},
+		},
+		{
+			name:         "given_fields_kept",
+			spec:         map[string]interface{}{"replicas": int64(3), "strategy": map[string]interface{}{"type": "Recreate"}},
+			expectedSpec: map[string]interface{}{"replicas": int64(3), "strategy": map[string]interface{}{"type": "Recreate"}},
+		},
+		{
+			name:         "missing_objects_not_created",
+			spec:         map[string]interface{}{},
+			expectedSpec: map[string]interface{}{"replicas": int64(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created map[string]interface{}
+			runtimeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					created = obj.(*unstructured.Unstructured).Object
+					return nil
+				},
+			}).Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).
+				WithInputCoercion(resolver.InputCoercionDisabled, definitions)
+
+			_, err := r.CreateItem(deploymentGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args: map[string]interface{}{
+					resolver.NamespaceArg: "default",
+					resolver.ObjectArg: map[string]interface{}{
+						"metadata": map[string]interface{}{"name": "web"},
+						"spec":     tt.spec,
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedSpec, created["spec"])
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package resolver
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrLabelSelectorRequired = errors.New("a non-empty label selector is required to delete matching objects")
+
+// DeleteMatchingResult describes the objects affected by a deleteMatching mutation
+type DeleteMatchingResult struct {
+	Count int      `json:"count"`
+	Names []string `json:"names"`
+}
+
+// DeleteMatchingItems returns a resolver that deletes all objects matching a label selector via DeleteCollection.
+// The matching objects are listed beforehand, so that the result contains their names. In preview mode only the list
+// is returned and nothing is deleted.
+func (r *Service) DeleteMatchingItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(DELETE_MATCHING_ITEMS, gvk, r.audited("deleteMatching", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, DELETE_MATCHING_ITEMS, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "deleteMatching").Str("kind", gvk.Kind).Logger()
+
+		// An empty selector would match every object, so it is never allowed
+		labelSelector, err := getStringArg(p.Args, LabelSelectorArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if labelSelector == "" {
+			return nil, ErrLabelSelectorRequired
+		}
+
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			log.Error().Err(err).Str(LabelSelectorArg, labelSelector).Msg("Unable to parse given label selector")
+			return nil, err
+		}
+		if selector.Empty() {
+			return nil, ErrLabelSelectorRequired
+		}
+
+		listOpts := []client.ListOption{client.MatchingLabelsSelector{Selector: selector}}
+		deleteOpts := []client.DeleteAllOfOption{client.MatchingLabelsSelector{Selector: selector}}
+
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
+		if isResourceNamespaceScoped(scope) {
+			listOpts = append(listOpts, client.InNamespace(namespace))
+			deleteOpts = append(deleteOpts, client.InNamespace(namespace))
+		}
+
+		preview, err := getBoolArg(p.Args, PreviewArg, false)
+		if err != nil {
+			return nil, err
+		}
+
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if dryRunBool {
+			deleteOpts = append(deleteOpts, client.DryRunAll)
+		}
+
+		// Only the metadata is needed to report the affected objects
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := r.runtimeClient.List(ctx, list, listOpts...); err != nil {
+			log.Error().Err(err).Msg("Unable to list matching objects")
+			return nil, err
+		}
+
+		result := DeleteMatchingResult{
+			Count: len(list.Items),
+			Names: make([]string, 0, len(list.Items)),
+		}
+		for _, item := range list.Items {
+			result.Names = append(result.Names, item.GetName())
+		}
+
+		if preview || result.Count == 0 {
+			return result, nil
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(gvk)
+		if err := r.runtimeClient.DeleteAllOf(ctx, obj, deleteOpts...); err != nil {
+			log.Error().Err(err).Msg("Failed to delete matching objects")
+			return nil, err
+		}
+
+		return result, nil
+	}))
+}
@@ -0,0 +1,82 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestDeleteMatchingItems(t *testing.T) {
+	configMap := func(name string, labels map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels}}
+	}
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	tests := []struct {
+		name              string
+		args              map[string]any
+		expectedResult    resolver.DeleteMatchingResult
+		expectedRemaining int
+		expectedErr       error
+	}{
+		{
+			name:              "delete",
+			args:              map[string]any{resolver.LabelSelectorArg: "app=old", resolver.NamespaceArg: "default"},
+			expectedResult:    resolver.DeleteMatchingResult{Count: 2, Names: []string{"old-1", "old-2"}},
+			expectedRemaining: 1,
+		},
+		{
+			name:              "preview",
+			args:              map[string]any{resolver.LabelSelectorArg: "app=old", resolver.NamespaceArg: "default", resolver.PreviewArg: true},
+			expectedResult:    resolver.DeleteMatchingResult{Count: 2, Names: []string{"old-1", "old-2"}},
+			expectedRemaining: 3,
+		},
+		{
+			name:              "nothing_matches",
+			args:              map[string]any{resolver.LabelSelectorArg: "app=none", resolver.NamespaceArg: "default"},
+			expectedResult:    resolver.DeleteMatchingResult{Names: []string{}},
+			expectedRemaining: 3,
+		},
+		{
+			name:              "missing_label_selector_ERROR",
+			args:              map[string]any{resolver.NamespaceArg: "default"},
+			expectedErr:       resolver.ErrLabelSelectorRequired,
+			expectedRemaining: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().WithObjects(
+				configMap("old-1", map[string]string{"app": "old"}),
+				configMap("old-2", map[string]string{"app": "old"}),
+				configMap("new", map[string]string{"app": "new"}),
+			).Build()
+			r := resolver.New(testlogger.New().Logger, runtimeClient)
+
+			result, err := r.DeleteMatchingItems(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expectedResult, result)
+			}
+
+			remaining := &corev1.ConfigMapList{}
+			require.NoError(t, runtimeClient.List(t.Context(), remaining))
+			assert.Len(t, remaining.Items, tt.expectedRemaining)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrValidationOnly rejects mutations that can't be performed in dry-run mode, e.g. issuing tokens, in validation-only
+// requests
+var ErrValidationOnly = errors.New("the mutation has no dry-run mode and is not allowed in validation-only requests")
+
+type validationOnlyKey struct{}
+
+// WithValidationOnly marks the requests of ctx as validation-only, their mutations are performed in dry-run mode
+// regardless of their dryRun argument
+func WithValidationOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, validationOnlyKey{}, true)
+}
+
+// IsValidationOnly returns whether the request of ctx is validation-only
+func IsValidationOnly(ctx context.Context) bool {
+	validationOnly, _ := ctx.Value(validationOnlyKey{}).(bool)
+	return validationOnly
+}
+
+// getDryRunArg returns whether the mutation is performed in dry-run mode, either because of its dryRun argument or
+// because the request is validation-only
+func getDryRunArg(ctx context.Context, args map[string]interface{}) (bool, error) {
+	dryRun, err := getBoolArg(args, DryRunArg, false)
+	if err != nil {
+		return false, err
+	}
+
+	return dryRun || IsValidationOnly(ctx), nil
+}
@@ -0,0 +1,114 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestValidationOnly(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	existing := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"}}
+
+	tests := []struct {
+		name           string
+		validationOnly bool
+		dryRunArg      bool
+		expectedDryRun []string
+	}{
+		{name: "persisted"},
+		{name: "dry_run_argument", dryRunArg: true, expectedDryRun: []string{metav1.DryRunAll}},
+		{name: "validation_only_request", validationOnly: true, expectedDryRun: []string{metav1.DryRunAll}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createDryRun, patchDryRun, deleteDryRun []string
+			runtimeClient := fake.NewClientBuilder().WithObjects(existing.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					createOpts := &client.CreateOptions{}
+					createOpts.ApplyOptions(opts)
+					createDryRun = createOpts.DryRun
+					return nil
+				},
+				Patch: func(ctx context.Context, clt client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					patchOpts := &client.PatchOptions{}
+					patchOpts.ApplyOptions(opts)
+					patchDryRun = patchOpts.DryRun
+					return nil
+				},
+				Delete: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					deleteOpts := &client.DeleteOptions{}
+					deleteOpts.ApplyOptions(opts)
+					deleteDryRun = deleteOpts.DryRun
+					return nil
+				},
+			}).Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			ctx := t.Context()
+			if tt.validationOnly {
+				ctx = resolver.WithValidationOnly(ctx)
+			}
+			args := func(extra map[string]interface{}) map[string]interface{} {
+				args := map[string]interface{}{resolver.NamespaceArg: "default", resolver.DryRunArg: tt.dryRunArg}
+				for key, value := range extra {
+					args[key] = value
+				}
+				return args
+			}
+			object := map[string]interface{}{"metadata": map[string]interface{}{"name": "settings"}, "data": map[string]interface{}{"mode": "strict"}}
+
+			_, err := r.CreateItem(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    args(map[string]interface{}{resolver.ObjectArg: object}),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDryRun, nilIfEmpty(createDryRun))
+
+			_, err = r.UpdateItem(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    args(map[string]interface{}{resolver.NameArg: "settings", resolver.ObjectArg: object}),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDryRun, nilIfEmpty(patchDryRun))
+
+			_, err = r.DeleteItem(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    args(map[string]interface{}{resolver.NameArg: "settings"}),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedDryRun, nilIfEmpty(deleteDryRun))
+		})
+	}
+
+	t.Run("token_rejected", func(t *testing.T) {
+		r := resolver.New(testlogger.New().HideLogOutput().Logger, fake.NewClientBuilder().Build())
+
+		_, err := r.CreateItemToken(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: resolver.WithValidationOnly(t.Context()),
+			Args:    map[string]interface{}{resolver.NameArg: "builder", resolver.NamespaceArg: "default"},
+		})
+		assert.ErrorIs(t, err, resolver.ErrValidationOnly)
+	})
+}
+
+// nilIfEmpty normalizes the empty dry-run options some resolvers pass to nil
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
@@ -0,0 +1,190 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SinceTimeArg          = "sinceTime"
+	ReasonArg             = "reason"
+	EventTypeArg          = "type"
+	InvolvedObjectKindArg = "involvedObjectKind"
+	InvolvedObjectNameArg = "involvedObjectName"
+	LimitArg              = "limit"
+	SinceSecondsArg       = "sinceSeconds"
+
+	// EventListLimit is the maximum amount of events returned by the list query, larger results need the paginated list
+	EventListLimit = 1000
+)
+
+// EventGVK is the kind of the events that get dedicated list arguments
+var EventGVK = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Event"}
+
+// EventsV1GVK is the kind of the events of the events.k8s.io API, which refer to the object as regarding
+var EventsV1GVK = schema.GroupVersionKind{Group: "events.k8s.io", Version: "v1", Kind: "Event"}
+
+var (
+	ErrInvalidSinceTime = errors.New("sinceTime must be an RFC 3339 timestamp")
+	ErrInvalidLimit     = errors.New("limit must be positive")
+	ErrInvalidSince     = errors.New("sinceSeconds must be positive")
+	ErrTooManyEvents    = fmt.Errorf("more than %d events match, narrow down the filters or use the paginated list", EventListLimit)
+)
+
+// eventFieldSelectors maps the event arguments to the fields the API server can select events by
+var eventFieldSelectors = map[string]string{
+	ReasonArg:             "reason",
+	EventTypeArg:          "type",
+	InvolvedObjectKindArg: "involvedObject.kind",
+	InvolvedObjectNameArg: "involvedObject.name",
+}
+
+// eventSelectors returns the field selectors of the event arguments, events are the only kind with these arguments
+func eventSelectors(args map[string]interface{}) []fields.Selector {
+	var selectors []fields.Selector
+	for arg, field := range eventFieldSelectors {
+		if value, ok := args[arg].(string); ok && value != "" {
+			selectors = append(selectors, fields.OneTermEqualSelector(field, value))
+		}
+	}
+
+	return selectors
+}
+
+// eventsSince returns the time of the sinceTime argument, or the zero time if it is not set
+func eventsSince(args map[string]interface{}) (time.Time, error) {
+	value, ok := args[SinceTimeArg].(string)
+	if !ok || value == "" {
+		return time.Time{}, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %w", ErrInvalidSinceTime, err)
+	}
+
+	return since, nil
+}
+
+// filterEventsSince drops the events that didn't occur since the given time, which the API server can't select by
+func filterEventsSince(items []unstructured.Unstructured, since time.Time) []unstructured.Unstructured {
+	if since.IsZero() {
+		return items
+	}
+
+	filtered := items[:0]
+	for _, item := range items {
+		if !lastEventTime(item.Object).Before(since) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+// lastEventTime returns when the event occurred the last time. Depending on the reporting client, events carry
+// lastTimestamp, the series of the events.k8s.io API or only eventTime, the creation time is the fallback.
+func lastEventTime(event map[string]interface{}) time.Time {
+	var last time.Time
+	for _, path := range [][]string{
+		{"lastTimestamp"},
+		{"series", "lastObservedTime"},
+		{"eventTime"},
+		{"metadata", "creationTimestamp"},
+	} {
+		value, _, _ := unstructured.NestedString(event, path...)
+		if t, err := time.Parse(time.RFC3339, value); err == nil && t.After(last) {
+			last = t
+		}
+	}
+
+	return last
+}
+
+// ObjectEvents returns a resolver for the events field of the objects of gvk, listing the events of eventGVK about the
+// source object, the most recent first. The events are selected by the UID, kind, name and namespace of the object.
+func (r *Service) ObjectEvents(gvk, eventGVK schema.GroupVersionKind) graphql.FieldResolveFn {
+	// The events.k8s.io API calls the object the event is about regarding instead of involvedObject
+	objectField := "involvedObject"
+	if eventGVK == EventsV1GVK {
+		objectField = "regarding"
+	}
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "ObjectEvents", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		object, ok := p.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		limit := 0
+		if value, ok := p.Args[LimitArg].(int); ok {
+			if value <= 0 {
+				return nil, ErrInvalidLimit
+			}
+			limit = value
+		}
+		var since time.Time
+		if value, ok := p.Args[SinceSecondsArg].(int); ok {
+			if value <= 0 {
+				return nil, ErrInvalidSince
+			}
+			since = time.Now().Add(-time.Duration(value) * time.Second)
+		}
+
+		name, _, _ := unstructured.NestedString(object, "metadata", "name")
+		namespace, _, _ := unstructured.NestedString(object, "metadata", "namespace")
+		uid, _, _ := unstructured.NestedString(object, "metadata", "uid")
+		selectors := []fields.Selector{
+			fields.OneTermEqualSelector(objectField+".kind", gvk.Kind),
+			fields.OneTermEqualSelector(objectField+".name", name),
+		}
+		if uid != "" {
+			selectors = append(selectors, fields.OneTermEqualSelector(objectField+".uid", uid))
+		}
+		if namespace != "" {
+			selectors = append(selectors, fields.OneTermEqualSelector(objectField+".namespace", namespace))
+		}
+
+		// Events of cluster-scoped objects are recorded in any namespace, usually default
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(eventGVK.GroupVersion().WithKind(eventGVK.Kind + "List"))
+		opts := append(fieldSelectorOptions(selectors...), client.InNamespace(namespace), client.Limit(EventListLimit))
+		if err := r.listObjects(ctx, list, opts...); err != nil {
+			r.log.Error().Err(err).Str("kind", gvk.Kind).Str("name", name).Msg("Unable to list events of object")
+			return nil, err
+		}
+		if list.GetContinue() != "" {
+			return nil, ErrTooManyEvents
+		}
+		r.redactItems(ctx, eventGVK, list.Items)
+
+		items := filterEventsSince(list.Items, since)
+		sort.SliceStable(items, func(i, j int) bool {
+			return lastEventTime(items[i].Object).After(lastEventTime(items[j].Object))
+		})
+		if limit > 0 && len(items) > limit {
+			items = items[:limit]
+		}
+
+		result := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			result = append(result, item.Object)
+		}
+
+		return result, nil
+	}
+}
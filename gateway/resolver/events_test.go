@@ -0,0 +1,284 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestListItems_Events(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	event := func(name string, lastTimestamp time.Time) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+			LastTimestamp: metav1.Time{Time: lastTimestamp},
+		}
+	}
+	events := []corev1.Event{
+		event("old", now.Add(-time.Hour)),
+		event("recent", now.Add(-time.Minute)),
+	}
+
+	// The fake client can't select events by field, so the options are recorded and the events are returned as they are
+	var listOpts client.ListOptions
+	var continueToken string
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts = client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+
+				items := make([]unstructured.Unstructured, len(events))
+				for i := range events {
+					content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&events[i])
+					require.NoError(t, err)
+					items[i] = unstructured.Unstructured{Object: content}
+				}
+				list.(*unstructured.UnstructuredList).Items = items
+				list.(*unstructured.UnstructuredList).SetContinue(continueToken)
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	names := func(items []map[string]any) []string {
+		result := make([]string, len(items))
+		for i, item := range items {
+			result[i] = item["metadata"].(map[string]any)["name"].(string)
+		}
+		return result
+	}
+
+	list := func(args map[string]any) (any, error) {
+		return r.ListItems(resolver.EventGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: args})
+	}
+
+	t.Run("field_selector", func(t *testing.T) {
+		result, err := list(map[string]any{
+			resolver.ReasonArg:             "BackOff",
+			resolver.EventTypeArg:          "Warning",
+			resolver.InvolvedObjectKindArg: "Pod",
+			resolver.InvolvedObjectNameArg: "gateway-7d9f8b6c5-x2x7k",
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"old", "recent"}, names(result.([]map[string]any)))
+		require.NotNil(t, listOpts.FieldSelector)
+		requirements := map[string]string{}
+		for _, requirement := range listOpts.FieldSelector.Requirements() {
+			requirements[requirement.Field] = requirement.Value
+		}
+		assert.Equal(t, map[string]string{
+			"reason":              "BackOff",
+			"type":                "Warning",
+			"involvedObject.kind": "Pod",
+			"involvedObject.name": "gateway-7d9f8b6c5-x2x7k",
+		}, requirements)
+		assert.Equal(t, int64(resolver.EventListLimit), listOpts.Limit)
+	})
+
+	t.Run("since_time", func(t *testing.T) {
+		result, err := list(map[string]any{resolver.SinceTimeArg: now.Add(-10 * time.Minute).Format(time.RFC3339)})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"recent"}, names(result.([]map[string]any)))
+		assert.Nil(t, listOpts.FieldSelector)
+	})
+
+	t.Run("since_time_connection", func(t *testing.T) {
+		result, err := r.ListItemsConnection(resolver.EventGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.SinceTimeArg: now.Add(-10 * time.Minute).Format(time.RFC3339), resolver.ReasonArg: "BackOff"},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"recent"}, names(result.(resolver.ListConnection).Items))
+		assert.Equal(t, "reason=BackOff", listOpts.FieldSelector.String())
+	})
+
+	t.Run("invalid_since_time_ERROR", func(t *testing.T) {
+		_, err := list(map[string]any{resolver.SinceTimeArg: "yesterday"})
+		assert.ErrorIs(t, err, resolver.ErrInvalidSinceTime)
+	})
+
+	t.Run("too_many_events_ERROR", func(t *testing.T) {
+		continueToken = "next-page"
+		defer func() { continueToken = "" }()
+
+		_, err := list(map[string]any{})
+		assert.ErrorIs(t, err, resolver.ErrTooManyEvents)
+	})
+}
+
+func TestObjectEvents(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	event := func(name string, lastTimestamp time.Time) corev1.Event {
+		return corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: name, Namespace: "default"},
+			LastTimestamp: metav1.Time{Time: lastTimestamp},
+		}
+	}
+	events := []corev1.Event{
+		event("scheduled", now.Add(-time.Hour)),
+		event("backoff", now.Add(-time.Minute)),
+		event("pulled", now.Add(-10*time.Minute)),
+	}
+
+	// The fake client can't select events by field, so the options are recorded and the events are returned as they are
+	var listOpts client.ListOptions
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts = client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+
+				items := make([]unstructured.Unstructured, len(events))
+				for i := range events {
+					content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&events[i])
+					require.NoError(t, err)
+					items[i] = unstructured.Unstructured{Object: content}
+				}
+				list.(*unstructured.UnstructuredList).Items = items
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	pod := map[string]any{
+		"metadata": map[string]any{"name": "gateway-7d9f8b6c5-x2x7k", "namespace": "default", "uid": "pod-uid"},
+	}
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	tests := []struct {
+		name             string
+		eventGVK         schema.GroupVersionKind
+		args             map[string]any
+		expectedNames    []string
+		expectedSelector map[string]string
+		expectedErr      error
+	}{
+		{
+			name:          "most_recent_first",
+			eventGVK:      resolver.EventGVK,
+			args:          map[string]any{},
+			expectedNames: []string{"backoff", "pulled", "scheduled"},
+			expectedSelector: map[string]string{
+				"involvedObject.kind":      "Pod",
+				"involvedObject.name":      "gateway-7d9f8b6c5-x2x7k",
+				"involvedObject.namespace": "default",
+				"involvedObject.uid":       "pod-uid",
+			},
+		},
+		{
+			name:          "limit_and_since_seconds",
+			eventGVK:      resolver.EventGVK,
+			args:          map[string]any{resolver.LimitArg: 1, resolver.SinceSecondsArg: 1800},
+			expectedNames: []string{"backoff"},
+		},
+		{
+			name:          "events_api",
+			eventGVK:      resolver.EventsV1GVK,
+			args:          map[string]any{resolver.SinceSecondsArg: 300},
+			expectedNames: []string{"backoff"},
+			expectedSelector: map[string]string{
+				"regarding.kind":      "Pod",
+				"regarding.name":      "gateway-7d9f8b6c5-x2x7k",
+				"regarding.namespace": "default",
+				"regarding.uid":       "pod-uid",
+			},
+		},
+		{
+			name:        "invalid_limit_ERROR",
+			eventGVK:    resolver.EventGVK,
+			args:        map[string]any{resolver.LimitArg: 0},
+			expectedErr: resolver.ErrInvalidLimit,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.ObjectEvents(podGVK, tt.eventGVK)(graphql.ResolveParams{Context: t.Context(), Source: pod, Args: tt.args})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			names := make([]string, 0)
+			for _, item := range result.([]map[string]any) {
+				names = append(names, item["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.expectedNames, names)
+			assert.Equal(t, "default", listOpts.Namespace)
+
+			if tt.expectedSelector != nil {
+				requirements := map[string]string{}
+				for _, requirement := range listOpts.FieldSelector.Requirements() {
+					requirements[requirement.Field] = requirement.Value
+				}
+				assert.Equal(t, tt.expectedSelector, requirements)
+			}
+		})
+	}
+}
+
+func TestObjectEvents_Redaction(t *testing.T) {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(resolver.EventGVK, meta.RESTScopeNamespace)
+
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "failed", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "gateway"},
+		Message:        "password=secret",
+	}
+	// The fake client can't select events by field, so the event is returned as it is
+	runtimeClient := fake.NewClientBuilder().
+		WithRESTMapper(mapper).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(event)
+				require.NoError(t, err)
+				list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{{Object: content}}
+				return nil
+			},
+			Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				obj.(*authorizationv1.SelfSubjectAccessReview).Status.Allowed = false
+				return nil
+			},
+		}).
+		Build()
+
+	policy, err := resolver.NewRedactionPolicy([]resolver.RedactionRule{{Kind: "Event", Fields: []string{"message"}}})
+	require.NoError(t, err)
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithRedaction(policy)
+
+	pod := map[string]any{"metadata": map[string]any{"name": "gateway", "namespace": "default"}}
+	ctx := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token")
+	result, err := r.ObjectEvents(corev1.SchemeGroupVersion.WithKind("Pod"), resolver.EventGVK)(graphql.ResolveParams{Context: ctx, Source: pod})
+	require.NoError(t, err)
+
+	items := result.([]map[string]any)
+	require.Len(t, items, 1)
+	assert.Equal(t, resolver.RedactedValue, items[0]["message"])
+}
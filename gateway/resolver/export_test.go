@@ -1,6 +1,13 @@
 package resolver
 
-import "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+import (
+	"context"
+
+	dto "github.com/prometheus/client_model/go"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
 
 func (r *Service) GetOriginalGroupName(key string) string {
 	return r.getOriginalGroupName(key)
@@ -18,6 +25,10 @@ func GetStringArg(args map[string]interface{}, key string, required bool) (strin
 	return getStringArg(args, key, required)
 }
 
+func GetNamespaceArg(ctx context.Context, args map[string]interface{}, scope v1.ResourceScope, required bool) (string, error) {
+	return getNamespaceArg(ctx, args, scope, required)
+}
+
 func GetBoolArg(args map[string]interface{}, key string, required bool) (bool, error) {
 	return getBoolArg(args, key, required)
 }
@@ -25,3 +36,17 @@ func GetBoolArg(args map[string]interface{}, key string, required bool) (bool, e
 func CompareUnstructured(a, b unstructured.Unstructured, fieldPath string) int {
 	return compareUnstructured(a, b, fieldPath)
 }
+
+func (r *Service) CoerceObjectInput(gvk schema.GroupVersionKind, object map[string]any) error {
+	return r.coerceObjectInput(gvk, object)
+}
+
+func ProxyRequestPath(gv schema.GroupVersion, resource string, obj *unstructured.Unstructured, port, proxyPath string) (string, error) {
+	return proxyRequestPath(gv, resource, obj, port, proxyPath)
+}
+
+func ResolverOperationsCount(group, version, kind, operation, result string) (float64, error) {
+	metric := &dto.Metric{}
+	err := resolverOperationsTotal.WithLabelValues(group, version, kind, operation, result).Write(metric)
+	return metric.GetCounter().GetValue(), err
+}
@@ -0,0 +1,274 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const FilterArg = "filter"
+
+// FilterOperator compares a field of an object with the value of a filter
+type FilterOperator string
+
+const (
+	FilterOperatorEq       FilterOperator = "eq"
+	FilterOperatorNe       FilterOperator = "ne"
+	FilterOperatorIn       FilterOperator = "in"
+	FilterOperatorContains FilterOperator = "contains"
+	FilterOperatorGt       FilterOperator = "gt"
+	FilterOperatorLt       FilterOperator = "lt"
+)
+
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ListFilter selects the objects of a list query by one of their fields
+type ListFilter struct {
+	// Field is the dot separated path of the field, e.g. status.phase
+	Field    string
+	Operator FilterOperator
+	Value    string
+	// Values are the accepted values of the in operator
+	Values []string
+}
+
+var filterOperatorEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "FilterOperator",
+	Description: "How the field of an object is compared with the value of a filter",
+	Values: graphql.EnumValueConfigMap{
+		"EQ":       &graphql.EnumValueConfig{Value: FilterOperatorEq, Description: "The field equals the value"},
+		"NE":       &graphql.EnumValueConfig{Value: FilterOperatorNe, Description: "The field is missing or doesn't equal the value"},
+		"IN":       &graphql.EnumValueConfig{Value: FilterOperatorIn, Description: "The field equals one of the values"},
+		"CONTAINS": &graphql.EnumValueConfig{Value: FilterOperatorContains, Description: "The string field contains the value, or the list field contains an item equal to it"},
+		"GT":       &graphql.EnumValueConfig{Value: FilterOperatorGt, Description: "The numeric field is greater than the value"},
+		"LT":       &graphql.EnumValueConfig{Value: FilterOperatorLt, Description: "The numeric field is less than the value"},
+	},
+})
+
+var listFilterInput = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "ListFilter",
+	Description: "Selects objects by one of their fields",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"field": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The dot separated path of the field, e.g. status.phase",
+		},
+		"operator": &graphql.InputObjectFieldConfig{
+			Type:         filterOperatorEnum,
+			DefaultValue: FilterOperatorEq,
+			Description:  "How the field is compared with the value",
+		},
+		"value": &graphql.InputObjectFieldConfig{
+			Type:        graphql.String,
+			Description: "The value to compare with, required for all operators but IN",
+		},
+		"values": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "The accepted values of IN",
+		},
+	},
+})
+
+// selectableFields are the fields the API server can select objects by, next to metadata.name and metadata.namespace
+var selectableFields = map[schema.GroupVersionKind][]string{
+	corev1.SchemeGroupVersion.WithKind("Pod"): {
+		"spec.nodeName", "spec.restartPolicy", "spec.schedulerName", "spec.serviceAccountName",
+		"status.phase", "status.podIP", "status.nominatedNodeName",
+	},
+	corev1.SchemeGroupVersion.WithKind("Secret"):    {"type"},
+	corev1.SchemeGroupVersion.WithKind("Namespace"): {"status.phase"},
+	corev1.SchemeGroupVersion.WithKind("Node"):      {"spec.unschedulable"},
+}
+
+// listFilters returns the filters of the filter argument
+func listFilters(args map[string]interface{}) ([]ListFilter, error) {
+	rawFilters, _ := args[FilterArg].([]interface{})
+
+	filters := make([]ListFilter, 0, len(rawFilters))
+	for _, raw := range rawFilters {
+		values, _ := raw.(map[string]interface{})
+
+		filter := ListFilter{Operator: FilterOperatorEq}
+		filter.Field, _ = values["field"].(string)
+		if operator, ok := values["operator"].(FilterOperator); ok {
+			filter.Operator = operator
+		}
+		filter.Value, _ = values["value"].(string)
+		for _, value := range asSlice(values["values"]) {
+			if str, ok := value.(string); ok {
+				filter.Values = append(filter.Values, str)
+			}
+		}
+
+		if err := filter.validate(); err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+func asSlice(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		return v
+	case []string:
+		result := make([]interface{}, len(v))
+		for i, str := range v {
+			result[i] = str
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func (f ListFilter) validate() error {
+	if f.Field == "" || slices.Contains(strings.Split(f.Field, "."), "") {
+		return fmt.Errorf("%w: field %q is not a dot separated path", ErrInvalidFilter, f.Field)
+	}
+
+	switch f.Operator {
+	case FilterOperatorIn:
+		if len(f.Values) == 0 {
+			return fmt.Errorf("%w: %s needs values", ErrInvalidFilter, f.Field)
+		}
+	case FilterOperatorGt, FilterOperatorLt:
+		if _, err := strconv.ParseFloat(f.Value, 64); err != nil {
+			return fmt.Errorf("%w: %s needs a numeric value", ErrInvalidFilter, f.Field)
+		}
+	case FilterOperatorEq, FilterOperatorNe, FilterOperatorContains:
+	default:
+		return fmt.Errorf("%w: unknown operator %q", ErrInvalidFilter, f.Operator)
+	}
+
+	return nil
+}
+
+// matches evaluates the filter against an object
+func (f ListFilter) matches(object map[string]interface{}) bool {
+	value, found, err := unstructured.NestedFieldNoCopy(object, strings.Split(f.Field, ".")...)
+	if err != nil || value == nil {
+		found = false
+	}
+
+	switch f.Operator {
+	case FilterOperatorEq:
+		return found && filterString(value) == f.Value
+	case FilterOperatorNe:
+		return !found || filterString(value) != f.Value
+	case FilterOperatorIn:
+		return found && slices.Contains(f.Values, filterString(value))
+	case FilterOperatorContains:
+		switch v := value.(type) {
+		case string:
+			return strings.Contains(v, f.Value)
+		case []interface{}:
+			return slices.ContainsFunc(v, func(item interface{}) bool { return filterString(item) == f.Value })
+		default:
+			return false
+		}
+	case FilterOperatorGt, FilterOperatorLt:
+		number, ok := filterNumber(value)
+		if !found || !ok {
+			return false
+		}
+		threshold, _ := strconv.ParseFloat(f.Value, 64)
+		if f.Operator == FilterOperatorGt {
+			return number > threshold
+		}
+		return number < threshold
+	default:
+		return false
+	}
+}
+
+// filterString formats scalar fields like they are written in field selectors
+func filterString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func filterNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// applyListFilters drops the objects that don't match all filters
+func applyListFilters(items []unstructured.Unstructured, filters []ListFilter) []unstructured.Unstructured {
+	if len(filters) == 0 {
+		return items
+	}
+
+	filtered := items[:0]
+	for _, item := range items {
+		if !slices.ContainsFunc(filters, func(f ListFilter) bool { return !f.matches(item.Object) }) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+// filtersMetadataOnly reports whether the filters only need the metadata of the objects, see listMetadata
+func filtersMetadataOnly(filters []ListFilter) bool {
+	return !slices.ContainsFunc(filters, func(f ListFilter) bool { return !strings.HasPrefix(f.Field, "metadata.") })
+}
+
+// filterSelectors returns the field selectors of the eq and ne filters on fields the API server can select the kind by.
+// The filters are still evaluated after listing, so the selectors only reduce the amount of listed objects.
+func filterSelectors(gvk schema.GroupVersionKind, filters []ListFilter) []fields.Selector {
+	var selectors []fields.Selector
+	for _, filter := range filters {
+		if filter.Field != "metadata.name" && filter.Field != "metadata.namespace" && !slices.Contains(selectableFields[gvk], filter.Field) {
+			continue
+		}
+
+		switch filter.Operator {
+		case FilterOperatorEq:
+			selectors = append(selectors, fields.OneTermEqualSelector(filter.Field, filter.Value))
+		case FilterOperatorNe:
+			selectors = append(selectors, fields.OneTermNotEqualSelector(filter.Field, filter.Value))
+		}
+	}
+
+	return selectors
+}
+
+// fieldSelectorOptions combines the field selectors into one list option, as a later field selector replaces an earlier one
+func fieldSelectorOptions(selectors ...fields.Selector) []client.ListOption {
+	if len(selectors) == 0 {
+		return nil
+	}
+
+	return []client.ListOption{client.MatchingFieldsSelector{Selector: fields.AndSelectors(selectors...)}}
+}
@@ -0,0 +1,176 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestListItems_Filter(t *testing.T) {
+	pod := func(name string, phase corev1.PodPhase, priority int32, finalizers ...string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Finalizers: finalizers},
+			Spec:       corev1.PodSpec{Priority: &priority, NodeName: "node-" + name},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	pods := []corev1.Pod{
+		pod("api", corev1.PodRunning, 100, "example.com/protect"),
+		pod("worker", corev1.PodPending, 10),
+		pod("job", corev1.PodSucceeded, 0),
+	}
+
+	// The fake client can't select pods by field, so the options are recorded and the pods are returned as they are
+	var listOpts client.ListOptions
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts = client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+
+				items := make([]unstructured.Unstructured, len(pods))
+				for i := range pods {
+					content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&pods[i])
+					require.NoError(t, err)
+					items[i] = unstructured.Unstructured{Object: content}
+				}
+				list.(*unstructured.UnstructuredList).Items = items
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	names := func(items []map[string]any) []string {
+		result := make([]string, len(items))
+		for i, item := range items {
+			result[i] = item["metadata"].(map[string]any)["name"].(string)
+		}
+		return result
+	}
+
+	filter := func(field string, operator resolver.FilterOperator, value string, values ...string) map[string]any {
+		return map[string]any{"field": field, "operator": operator, "value": value, "values": values}
+	}
+
+	tests := []struct {
+		name             string
+		filters          []any
+		expected         []string
+		expectedSelector string
+	}{
+		{
+			name:             "eq_pushed_down",
+			filters:          []any{filter("status.phase", resolver.FilterOperatorEq, "Running")},
+			expected:         []string{"api"},
+			expectedSelector: "status.phase=Running",
+		},
+		{
+			name:             "ne_pushed_down",
+			filters:          []any{filter("spec.nodeName", resolver.FilterOperatorNe, "node-api")},
+			expected:         []string{"worker", "job"},
+			expectedSelector: "spec.nodeName!=node-api",
+		},
+		{
+			name:     "in",
+			filters:  []any{filter("status.phase", resolver.FilterOperatorIn, "", "Pending", "Succeeded")},
+			expected: []string{"worker", "job"},
+		},
+		{
+			name:     "contains_list_item",
+			filters:  []any{filter("metadata.finalizers", resolver.FilterOperatorContains, "example.com/protect")},
+			expected: []string{"api"},
+		},
+		{
+			name:     "contains_substring",
+			filters:  []any{filter("metadata.name", resolver.FilterOperatorContains, "o")},
+			expected: []string{"worker", "job"},
+		},
+		{
+			name:     "gt",
+			filters:  []any{filter("spec.priority", resolver.FilterOperatorGt, "5")},
+			expected: []string{"api", "worker"},
+		},
+		{
+			name: "all_filters_must_match",
+			filters: []any{
+				filter("spec.priority", resolver.FilterOperatorLt, "50"),
+				filter("status.phase", resolver.FilterOperatorNe, "Succeeded"),
+			},
+			expected:         []string{"worker"},
+			expectedSelector: "status.phase!=Succeeded",
+		},
+		{
+			name:             "missing_field",
+			filters:          []any{filter("status.podIP", resolver.FilterOperatorEq, "10.0.0.1")},
+			expected:         []string{},
+			expectedSelector: "status.podIP=10.0.0.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.ListItems(podGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{resolver.FilterArg: tt.filters},
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, names(result.([]map[string]any)))
+			if tt.expectedSelector == "" {
+				assert.Nil(t, listOpts.FieldSelector)
+			} else {
+				require.NotNil(t, listOpts.FieldSelector)
+				assert.Equal(t, tt.expectedSelector, listOpts.FieldSelector.String())
+			}
+		})
+	}
+
+	t.Run("connection", func(t *testing.T) {
+		result, err := r.ListItemsConnection(podGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.FilterArg: []any{filter("spec.priority", resolver.FilterOperatorGt, "50")}},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"api"}, names(result.(resolver.ListConnection).Items))
+	})
+}
+
+func TestListItems_InvalidFilter(t *testing.T) {
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, fake.NewClientBuilder().Build())
+
+	tests := []struct {
+		name   string
+		filter map[string]any
+	}{
+		{name: "empty_path_segment", filter: map[string]any{"field": "status..phase", "value": "Running"}},
+		{name: "in_without_values", filter: map[string]any{"field": "status.phase", "operator": resolver.FilterOperatorIn}},
+		{name: "gt_without_number", filter: map[string]any{"field": "spec.priority", "operator": resolver.FilterOperatorGt, "value": "high"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.ListItems(corev1.SchemeGroupVersion.WithKind("Pod"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{resolver.FilterArg: []any{tt.filter}},
+			})
+			assert.ErrorIs(t, err, resolver.ErrInvalidFilter)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package resolver
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// removeInputOnlyFields deletes the input-only fields of the resource from an object returned as a whole, like the YAML of getItemAsYAML.
+// gvk must contain the original group name.
+func (r *Service) removeInputOnlyFields(gvk schema.GroupVersionKind, object map[string]any) {
+	key, ok := r.definitionsByGVK[gvk]
+	if !ok {
+		return
+	}
+
+	common.RemoveFields(object, common.InputOnlyFields(r.definitions[key].Extensions))
+}
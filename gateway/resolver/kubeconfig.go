@@ -0,0 +1,194 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+)
+
+const (
+	ISSUE_KUBECONFIG = "IssueKubeconfig"
+
+	TTLArg = "ttl"
+
+	// MinKubeconfigTTL is the shortest lifetime the API server accepts for requested service account tokens
+	MinKubeconfigTTL = 10 * time.Minute
+)
+
+var (
+	ErrKubeconfigIssuanceDisabled = errors.New("kubeconfig issuance is not enabled")
+	ErrInvalidServiceAccount      = errors.New("service account must be formatted as namespace/name")
+	ErrInvalidKubeconfigTTL       = errors.New("invalid kubeconfig ttl")
+	ErrMissingKubeconfigAudiences = errors.New("kubeconfig tokens must be bound to an audience")
+)
+
+// KubeconfigIssuance configures the issueKubeconfig mutation of a cluster
+type KubeconfigIssuance struct {
+	// Cluster is the name of the cluster and context in the issued kubeconfigs, kcp workspaces are named after the workspace
+	Cluster string
+	// Server and CAData point kubectl to the same API server the gateway talks to
+	Server                string
+	CAData                []byte
+	InsecureSkipTLSVerify bool
+	// ServiceAccount is the service account the tokens are requested for
+	ServiceAccount types.NamespacedName
+	// MaxTTL is the longest lifetime of the issued tokens, it is also used if no ttl is requested
+	MaxTTL time.Duration
+	// Audiences bind the issued tokens, so that they are only accepted by the API server and not by other services
+	// trusting the tokens of the service account
+	Audiences []string
+}
+
+// IssuedKubeconfig is the result of the issueKubeconfig mutation
+type IssuedKubeconfig struct {
+	Kubeconfig          string `json:"kubeconfig"`
+	ExpirationTimestamp string `json:"expirationTimestamp"`
+	ServiceAccount      string `json:"serviceAccount"`
+}
+
+// ParseServiceAccount parses a service account formatted as namespace/name
+func ParseServiceAccount(value string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, fmt.Errorf("%w: %q", ErrInvalidServiceAccount, value)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// WithKubeconfigIssuance enables the issueKubeconfig mutation, nil disables it
+func (r *Service) WithKubeconfigIssuance(issuance *KubeconfigIssuance) *Service {
+	r.kubeconfigIssuance = issuance
+	return r
+}
+
+// IssueKubeconfig returns a resolver that issues a kubeconfig with a short-lived token of the configured service account,
+// bound to the configured audiences. The token is requested with the credentials of the caller, so only users allowed
+// to create tokens for the service account get a kubeconfig.
+func (r *Service) IssueKubeconfig() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, ISSUE_KUBECONFIG)
+		defer span.End()
+
+		issuance := r.kubeconfigIssuance
+		if issuance == nil {
+			return nil, ErrKubeconfigIssuanceDisabled
+		}
+		if IsValidationOnly(ctx) {
+			return nil, ErrValidationOnly
+		}
+
+		log := r.log.With().Str("operation", "issueKubeconfig").Str("serviceAccount", issuance.ServiceAccount.String()).Logger()
+
+		if len(issuance.Audiences) == 0 {
+			return nil, ErrMissingKubeconfigAudiences
+		}
+
+		// the kubeconfig always points to the endpoint the mutation was sent to
+		cluster, server := issuance.Cluster, issuance.Server
+		if workspace, ok := kontext.ClusterFrom(ctx); ok && !workspace.Empty() {
+			// kcp serves the workspaces below /clusters/<workspace> of its host
+			cluster = workspace.String()
+			server = strings.TrimSuffix(server, "/") + workspace.Path().RequestPath()
+		}
+
+		ttl, err := kubeconfigTTL(p.Args, issuance.MaxTTL)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("serviceAccount", issuance.ServiceAccount.String()), attribute.String("ttl", ttl.String()))
+
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      issuance.ServiceAccount.Name,
+				Namespace: issuance.ServiceAccount.Namespace,
+			},
+		}
+		expirationSeconds := int64(ttl.Seconds())
+		tokenRequest := &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         issuance.Audiences,
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}
+		// The token and the kubeconfig are not recorded, only that they were issued
+		event := audit.Event{
+			Operation: "issueKubeconfig",
+			Version:   "v1",
+			Kind:      "ServiceAccount",
+			Namespace: issuance.ServiceAccount.Namespace,
+			Name:      issuance.ServiceAccount.Name,
+		}
+		err = r.runtimeClient.SubResource("token").Create(ctx, serviceAccount, tokenRequest)
+		r.recordAudit(ctx, event, nil, nil, err)
+		if err != nil {
+			log.Error().Err(err).Msg("Unable to request service account token")
+			return nil, err
+		}
+
+		kubeconfig, err := issuance.kubeconfig(cluster, server, tokenRequest.Status.Token)
+		if err != nil {
+			log.Error().Err(err).Msg("Unable to write kubeconfig")
+			return nil, err
+		}
+
+		log.Info().Str("cluster", cluster).Str("ttl", ttl.String()).Msg("Issued kubeconfig")
+
+		return IssuedKubeconfig{
+			Kubeconfig:          string(kubeconfig),
+			ExpirationTimestamp: tokenRequest.Status.ExpirationTimestamp.UTC().Format(time.RFC3339),
+			ServiceAccount:      issuance.ServiceAccount.String(),
+		}, nil
+	}
+}
+
+// kubeconfigTTL returns the requested lifetime of the token, which must be between MinKubeconfigTTL and maxTTL
+func kubeconfigTTL(args map[string]interface{}, maxTTL time.Duration) (time.Duration, error) {
+	value, _ := args[TTLArg].(string)
+	if value == "" {
+		return maxTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrInvalidKubeconfigTTL, err)
+	}
+	if ttl < MinKubeconfigTTL || ttl > maxTTL {
+		return 0, fmt.Errorf("%w: %s must be between %s and %s", ErrInvalidKubeconfigTTL, ttl, MinKubeconfigTTL, maxTTL)
+	}
+
+	return ttl, nil
+}
+
+// kubeconfig writes a kubeconfig with a single cluster, user and context named after the cluster
+func (k *KubeconfigIssuance) kubeconfig(cluster, server, token string) ([]byte, error) {
+	config := clientcmdapi.NewConfig()
+	config.Clusters[cluster] = &clientcmdapi.Cluster{
+		Server:                   server,
+		CertificateAuthorityData: k.CAData,
+		InsecureSkipTLSVerify:    k.InsecureSkipTLSVerify,
+	}
+	config.AuthInfos[cluster] = &clientcmdapi.AuthInfo{Token: token}
+	config.Contexts[cluster] = &clientcmdapi.Context{
+		Cluster:   cluster,
+		AuthInfo:  cluster,
+		Namespace: k.ServiceAccount.Namespace,
+	}
+	config.CurrentContext = cluster
+
+	return clientcmd.Write(*config)
+}
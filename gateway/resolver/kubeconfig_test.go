@@ -0,0 +1,138 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestIssueKubeconfig(t *testing.T) {
+	expiration := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// The fake client doesn't serve the token subresource, the requests are recorded and answered with a static token
+	var requested struct {
+		serviceAccount    types.NamespacedName
+		expirationSeconds int64
+		audiences         []string
+	}
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, clt client.Client, subResourceName string, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+				require.Equal(t, "token", subResourceName)
+				requested.serviceAccount = client.ObjectKeyFromObject(obj)
+
+				tokenRequest := subResource.(*authenticationv1.TokenRequest)
+				requested.expirationSeconds = *tokenRequest.Spec.ExpirationSeconds
+				requested.audiences = tokenRequest.Spec.Audiences
+				tokenRequest.Status = authenticationv1.TokenRequestStatus{
+					Token:               "issued-token",
+					ExpirationTimestamp: metav1.Time{Time: expiration},
+				}
+				return nil
+			},
+		}).
+		Build()
+
+	issuance := &resolver.KubeconfigIssuance{
+		Cluster:        "production",
+		Server:         "https://api.example.com:6443",
+		CAData:         []byte("ca-data"),
+		ServiceAccount: types.NamespacedName{Namespace: "power-users", Name: "kubectl"},
+		MaxTTL:         time.Hour,
+		Audiences:      []string{"https://kubernetes.default.svc.cluster.local"},
+	}
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithKubeconfigIssuance(issuance)
+
+	issue := func(ctx context.Context, args map[string]any) (resolver.IssuedKubeconfig, error) {
+		result, err := r.IssueKubeconfig()(graphql.ResolveParams{Context: ctx, Args: args})
+		if err != nil {
+			return resolver.IssuedKubeconfig{}, err
+		}
+		return result.(resolver.IssuedKubeconfig), nil
+	}
+
+	t.Run("default_ttl", func(t *testing.T) {
+		issued, err := issue(t.Context(), map[string]any{})
+		require.NoError(t, err)
+
+		assert.Equal(t, types.NamespacedName{Namespace: "power-users", Name: "kubectl"}, requested.serviceAccount)
+		assert.Equal(t, int64(3600), requested.expirationSeconds)
+		assert.Equal(t, []string{"https://kubernetes.default.svc.cluster.local"}, requested.audiences)
+		assert.Equal(t, "2025-01-01T12:00:00Z", issued.ExpirationTimestamp)
+		assert.Equal(t, "power-users/kubectl", issued.ServiceAccount)
+
+		kubeconfig, err := clientcmd.Load([]byte(issued.Kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, "production", kubeconfig.CurrentContext)
+		assert.Equal(t, "https://api.example.com:6443", kubeconfig.Clusters["production"].Server)
+		assert.Equal(t, []byte("ca-data"), kubeconfig.Clusters["production"].CertificateAuthorityData)
+		assert.Equal(t, "issued-token", kubeconfig.AuthInfos["production"].Token)
+		assert.Equal(t, "power-users", kubeconfig.Contexts["production"].Namespace)
+	})
+
+	t.Run("requested_ttl", func(t *testing.T) {
+		_, err := issue(t.Context(), map[string]any{resolver.TTLArg: "15m"})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(900), requested.expirationSeconds)
+	})
+
+	t.Run("kcp_workspace", func(t *testing.T) {
+		ctx := kontext.WithCluster(t.Context(), logicalcluster.Name("root:orgs:team"))
+		issued, err := issue(ctx, map[string]any{})
+		require.NoError(t, err)
+
+		kubeconfig, err := clientcmd.Load([]byte(issued.Kubeconfig))
+		require.NoError(t, err)
+		assert.Equal(t, "root:orgs:team", kubeconfig.CurrentContext)
+		assert.Equal(t, "https://api.example.com:6443/clusters/root:orgs:team", kubeconfig.Clusters["root:orgs:team"].Server)
+	})
+
+	for _, ttl := range []string{"5m", "2h", "soon"} {
+		t.Run("invalid_ttl_"+ttl+"_ERROR", func(t *testing.T) {
+			_, err := issue(t.Context(), map[string]any{resolver.TTLArg: ttl})
+			assert.ErrorIs(t, err, resolver.ErrInvalidKubeconfigTTL)
+		})
+	}
+
+	t.Run("without_audiences_ERROR", func(t *testing.T) {
+		unbound := *issuance
+		unbound.Audiences = nil
+		_, err := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).
+			WithKubeconfigIssuance(&unbound).
+			IssueKubeconfig()(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+		assert.ErrorIs(t, err, resolver.ErrMissingKubeconfigAudiences)
+	})
+
+	t.Run("disabled_ERROR", func(t *testing.T) {
+		_, err := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).IssueKubeconfig()(graphql.ResolveParams{Context: t.Context()})
+		assert.ErrorIs(t, err, resolver.ErrKubeconfigIssuanceDisabled)
+	})
+}
+
+func TestParseServiceAccount(t *testing.T) {
+	serviceAccount, err := resolver.ParseServiceAccount("power-users/kubectl")
+	require.NoError(t, err)
+	assert.Equal(t, types.NamespacedName{Namespace: "power-users", Name: "kubectl"}, serviceAccount)
+
+	for _, value := range []string{"kubectl", "/kubectl", "power-users/", "a/b/c"} {
+		_, err := resolver.ParseServiceAccount(value)
+		assert.ErrorIs(t, err, resolver.ErrInvalidServiceAccount, value)
+	}
+}
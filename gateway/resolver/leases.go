@@ -0,0 +1,184 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const DeploymentArg = "deployment"
+
+// LeaseGVK is the kind of the leases used for leader election
+var LeaseGVK = schema.GroupVersionKind{Group: "coordination.k8s.io", Version: "v1", Kind: "Lease"}
+
+// LeaseHolderResolver returns the identity of the current holder of a lease
+func LeaseHolderResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		holder := leaseHolder(p.Source)
+		if holder == "" {
+			return nil, nil
+		}
+
+		return holder, nil
+	}
+}
+
+// LeaseRenewedSecondsAgoResolver returns the seconds since a lease was renewed the last time
+func LeaseRenewedSecondsAgoResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		renewTime, ok := leaseRenewTime(p.Source)
+		if !ok {
+			return nil, nil
+		}
+
+		return int64(time.Since(renewTime).Seconds()), nil
+	}
+}
+
+// LeaseExpiredResolver returns whether a lease is not held anymore, i.e. it has no holder or was not renewed within its duration
+func LeaseExpiredResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		return leaseExpired(p.Source, time.Now()), nil
+	}
+}
+
+// LeaderOf returns the leader election lease held by a pod of the given deployment.
+// Without such a lease, the lease named like the deployment is returned, as it is common for controllers to use their name.
+func (r *Service) LeaderOf() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "LeaderOf", trace.WithAttributes(attribute.String("kind", LeaseGVK.Kind)))
+		defer span.End()
+
+		deployment, err := getStringArg(p.Args, DeploymentArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		namespace, err := getNamespaceArg(ctx, p.Args, v1.NamespaceScoped, true)
+		if err != nil {
+			return nil, err
+		}
+
+		leases, err := r.listLeases(ctx, namespace)
+		if err != nil {
+			r.log.Error().Err(err).Str("namespace", namespace).Msg("Unable to list leases")
+			return nil, err
+		}
+
+		if lease := leaderLease(leases, deployment); lease != nil {
+			r.redact(ctx, LeaseGVK, lease.Object)
+			return lease.Object, nil
+		}
+
+		return nil, nil
+	}
+}
+
+func (r *Service) listLeases(ctx context.Context, namespace string) ([]unstructured.Unstructured, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(LeaseGVK.GroupVersion().WithKind(LeaseGVK.Kind + "List"))
+
+	if err := r.listObjects(ctx, list, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	return list.Items, nil
+}
+
+// leaderLease picks the most recently renewed lease held by a pod of the deployment, or the lease named like the deployment
+func leaderLease(leases []unstructured.Unstructured, deployment string) *unstructured.Unstructured {
+	var leader, named *unstructured.Unstructured
+	var leaderRenewTime time.Time
+	for i := range leases {
+		lease := &leases[i]
+		if lease.GetName() == deployment {
+			named = lease
+		}
+
+		if !heldByDeploymentPod(leaseHolder(lease.Object), deployment) {
+			continue
+		}
+
+		renewTime, _ := leaseRenewTime(lease.Object)
+		if leader == nil || renewTime.After(leaderRenewTime) {
+			leader = lease
+			leaderRenewTime = renewTime
+		}
+	}
+
+	if leader != nil {
+		return leader
+	}
+
+	return named
+}
+
+// heldByDeploymentPod reports whether the holder identity starts with the name of a pod of the deployment.
+// Pods of a deployment are named <deployment>-<pod-template-hash>-<suffix>, leader election libraries usually append
+// a random suffix to the pod name separated by "_", e.g. controller-runtime and client-go.
+func heldByDeploymentPod(holder, deployment string) bool {
+	podName, _, _ := strings.Cut(holder, "_")
+	rest, ok := strings.CutPrefix(podName, deployment+"-")
+	if !ok {
+		return false
+	}
+
+	hash, suffix, ok := strings.Cut(rest, "-")
+	return ok && hash != "" && suffix != "" && !strings.Contains(suffix, "-")
+}
+
+func leaseHolder(source any) string {
+	lease, ok := source.(map[string]any)
+	if !ok {
+		return ""
+	}
+
+	holder, _, _ := unstructured.NestedString(lease, "spec", "holderIdentity")
+	return holder
+}
+
+func leaseRenewTime(source any) (time.Time, bool) {
+	lease, ok := source.(map[string]any)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	value, _, _ := unstructured.NestedString(lease, "spec", "renewTime")
+	renewTime, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return renewTime, true
+}
+
+// leaseExpired reports whether the lease has no holder or was not renewed within its duration.
+// Leases without a duration can't expire while they have a holder.
+func leaseExpired(source any, now time.Time) bool {
+	if leaseHolder(source) == "" {
+		return true
+	}
+
+	renewTime, ok := leaseRenewTime(source)
+	if !ok {
+		return true
+	}
+
+	lease, _ := source.(map[string]any)
+	duration, _, _ := unstructured.NestedFieldNoCopy(lease, "spec", "leaseDurationSeconds")
+	seconds := toInt64(duration)
+	if seconds <= 0 {
+		return false
+	}
+
+	return now.After(renewTime.Add(time.Duration(seconds) * time.Second))
+}
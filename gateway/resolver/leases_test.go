@@ -0,0 +1,145 @@
+package resolver_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func leaseObject(holder string, renewedAgo time.Duration, durationSeconds int64) map[string]any {
+	spec := map[string]any{
+		"renewTime":            time.Now().Add(-renewedAgo).UTC().Format("2006-01-02T15:04:05.000000Z07:00"),
+		"leaseDurationSeconds": durationSeconds,
+	}
+	if holder != "" {
+		spec["holderIdentity"] = holder
+	}
+
+	return map[string]any{"spec": spec}
+}
+
+func TestLeaseResolvers(t *testing.T) {
+	tests := []struct {
+		name                      string
+		lease                     map[string]any
+		expectedHolder            any
+		expectedRenewedSecondsAgo any
+		expectedExpired           bool
+	}{
+		{
+			name:                      "held",
+			lease:                     leaseObject("operator-7d9f8b6c5-x2x7k_1b2c", 5*time.Second, 15),
+			expectedHolder:            "operator-7d9f8b6c5-x2x7k_1b2c",
+			expectedRenewedSecondsAgo: int64(5),
+			expectedExpired:           false,
+		},
+		{
+			name:                      "not_renewed_within_duration",
+			lease:                     leaseObject("operator-7d9f8b6c5-x2x7k_1b2c", time.Minute, 15),
+			expectedHolder:            "operator-7d9f8b6c5-x2x7k_1b2c",
+			expectedRenewedSecondsAgo: int64(60),
+			expectedExpired:           true,
+		},
+		{
+			name:                      "released",
+			lease:                     leaseObject("", 5*time.Second, 15),
+			expectedHolder:            nil,
+			expectedRenewedSecondsAgo: int64(5),
+			expectedExpired:           true,
+		},
+		{
+			name:                      "never_renewed",
+			lease:                     map[string]any{"spec": map[string]any{"holderIdentity": "operator"}},
+			expectedHolder:            "operator",
+			expectedRenewedSecondsAgo: nil,
+			expectedExpired:           true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := graphql.ResolveParams{Source: tt.lease}
+
+			holder, err := resolver.LeaseHolderResolver()(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedHolder, holder)
+
+			renewedSecondsAgo, err := resolver.LeaseRenewedSecondsAgoResolver()(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRenewedSecondsAgo, renewedSecondsAgo)
+
+			expired, err := resolver.LeaseExpiredResolver()(p)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedExpired, expired)
+		})
+	}
+}
+
+func TestLeaderOf(t *testing.T) {
+	lease := func(name, holder string, renewedAgo time.Duration) *coordinationv1.Lease {
+		duration := int32(15)
+		return &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "operators"},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &holder,
+				LeaseDurationSeconds: &duration,
+				RenewTime:            &metav1.MicroTime{Time: time.Now().Add(-renewedAgo)},
+			},
+		}
+	}
+
+	runtimeClient := fake.NewClientBuilder().WithObjects(
+		lease("3f2a1b.example.com", "operator-7d9f8b6c5-x2x7k_1b2c", time.Second),
+		lease("stale.example.com", "operator-5c4b3a2d1-q8w9e_9f8e", time.Hour),
+		lease("operator-extension", "operator-extension-6b5a4c3d2-z1y2x_7a6b", time.Second),
+		lease("scheduler", "scheduler", time.Second),
+	).Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	tests := []struct {
+		name         string
+		deployment   string
+		expectedName any
+	}{
+		{name: "held_by_pod_of_deployment", deployment: "operator", expectedName: "3f2a1b.example.com"},
+		{name: "deployment_with_common_prefix", deployment: "operator-extension", expectedName: "operator-extension"},
+		{name: "named_like_deployment", deployment: "scheduler", expectedName: "scheduler"},
+		{name: "no_lease", deployment: "webhook", expectedName: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := r.LeaderOf()(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{resolver.DeploymentArg: tt.deployment, resolver.NamespaceArg: "operators"},
+			})
+			require.NoError(t, err)
+
+			if tt.expectedName == nil {
+				assert.Nil(t, result)
+				return
+			}
+
+			require.NotNil(t, result)
+			metadata := result.(map[string]any)["metadata"].(map[string]any)
+			assert.Equal(t, tt.expectedName, metadata["name"])
+		})
+	}
+
+	t.Run("missing_namespace_ERROR", func(t *testing.T) {
+		_, err := r.LeaderOf()(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.DeploymentArg: "operator"},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var ErrTooManyItems = errors.New("too many objects match")
+
+// WithListChunking configures how list queries read their objects. chunkSize objects are read per request, so that
+// the API server and the Gateway never hold a large list response at once, 0 reads the whole list in one request.
+// List queries reading more than maxItems objects fail without reading the remaining chunks, 0 disables the limit.
+func (r *Service) WithListChunking(chunkSize int64, maxItems int) *Service {
+	r.listChunkSize = chunkSize
+	r.listMaxItems = maxItems
+	return r
+}
+
+// listInChunks reads the list with listFn in chunks of the configured size by following the continue token.
+// consistencyOpts only apply to the first chunk, the following chunks are served from the snapshot of the continue
+// token and the API server rejects a resource version next to it.
+func (r *Service) listInChunks(
+	ctx context.Context,
+	listFn func(ctx context.Context, list *unstructured.UnstructuredList, opts ...client.ListOption) error,
+	list *unstructured.UnstructuredList,
+	opts []client.ListOption,
+	consistencyOpts []client.ListOption,
+) error {
+	if r.listChunkSize <= 0 {
+		if err := listFn(ctx, list, append(opts, consistencyOpts...)...); err != nil {
+			return err
+		}
+		return r.checkMaxItems(len(list.Items))
+	}
+
+	var items []unstructured.Unstructured
+	var continueToken string
+	for {
+		chunk := &unstructured.UnstructuredList{}
+		chunk.SetGroupVersionKind(list.GroupVersionKind())
+
+		chunkOpts := append([]client.ListOption{client.Limit(r.listChunkSize)}, opts...)
+		if continueToken == "" {
+			chunkOpts = append(chunkOpts, consistencyOpts...)
+		} else {
+			chunkOpts = append(chunkOpts, client.Continue(continueToken))
+		}
+
+		if err := listFn(ctx, chunk, chunkOpts...); err != nil {
+			return err
+		}
+
+		items = append(items, chunk.Items...)
+		if err := r.checkMaxItems(len(items)); err != nil {
+			return err
+		}
+
+		continueToken = chunk.GetContinue()
+		if continueToken == "" {
+			// The list keeps the metadata of the last chunk, e.g. the resource version of the snapshot
+			list.Object = chunk.Object
+			list.Items = items
+			return nil
+		}
+	}
+}
+
+func (r *Service) checkMaxItems(count int) error {
+	if r.listMaxItems > 0 && count > r.listMaxItems {
+		return fmt.Errorf("%w: more than %d objects, narrow down the namespace, label selector or filters or use the paginated list", ErrTooManyItems, r.listMaxItems)
+	}
+	return nil
+}
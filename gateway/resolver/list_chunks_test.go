@@ -0,0 +1,121 @@
+package resolver_test
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestListItems_Chunks(t *testing.T) {
+	tests := []struct {
+		name             string
+		chunkSize        int64
+		maxItems         int
+		args             map[string]any
+		expectedRequests []string
+		expectedItems    int
+		expectedErr      error
+	}{
+		{
+			name:      "follows_continue_token",
+			chunkSize: 2,
+			args:      map[string]any{resolver.ConsistencyArg: resolver.ConsistencyExact, resolver.ResourceVersionArg: "42"},
+			expectedRequests: []string{
+				"limit=2 continue= resourceVersion=42",
+				"limit=2 continue=2 resourceVersion=",
+				"limit=2 continue=4 resourceVersion=",
+			},
+			expectedItems: 5,
+		},
+		{
+			name:             "disabled",
+			args:             map[string]any{},
+			expectedRequests: []string{"limit=0 continue= resourceVersion="},
+			expectedItems:    5,
+		},
+		{
+			name:      "stops_at_max_items",
+			chunkSize: 2,
+			maxItems:  3,
+			args:      map[string]any{},
+			expectedRequests: []string{
+				"limit=2 continue= resourceVersion=",
+				"limit=2 continue=2 resourceVersion=",
+			},
+			expectedErr: resolver.ErrTooManyItems,
+		},
+		{
+			name:             "max_items_without_chunks",
+			maxItems:         3,
+			args:             map[string]any{},
+			expectedRequests: []string{"limit=0 continue= resourceVersion="},
+			expectedErr:      resolver.ErrTooManyItems,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const total = 5
+
+			var requests []string
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						listOpts := client.ListOptions{}
+						listOpts.ApplyOptions(opts)
+						resourceVersion := ""
+						if listOpts.Raw != nil {
+							resourceVersion = listOpts.Raw.ResourceVersion
+						}
+						requests = append(requests, fmt.Sprintf("limit=%d continue=%s resourceVersion=%s", listOpts.Limit, listOpts.Continue, resourceVersion))
+
+						// The continue token of the fake is the index of the next object
+						start, _ := strconv.Atoi(listOpts.Continue)
+						end := total
+						if listOpts.Limit > 0 {
+							end = min(start+int(listOpts.Limit), total)
+						}
+
+						unstructuredList := list.(*unstructured.UnstructuredList)
+						for i := start; i < end; i++ {
+							item := unstructured.Unstructured{}
+							item.SetName(fmt.Sprintf("cm-%d", i))
+							unstructuredList.Items = append(unstructuredList.Items, item)
+						}
+						if end < total {
+							unstructuredList.SetContinue(strconv.Itoa(end))
+						}
+						return nil
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithListChunking(tt.chunkSize, tt.maxItems)
+
+			result, err := r.ListItems(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    tt.args,
+			})
+			assert.Equal(t, tt.expectedRequests, requests)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, result, tt.expectedItems)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
+)
+
+const (
+	APPLY_MANIFESTS = "ApplyManifests"
+
+	ObjectsArg     = "objects"
+	YAMLArg        = "yaml"
+	StopOnErrorArg = "stopOnError"
+	RollbackArg    = "rollback"
+)
+
+// Actions reported per object by the applyManifests mutation
+const (
+	ManifestCreated    = "created"
+	ManifestUpdated    = "updated"
+	ManifestFailed     = "failed"
+	ManifestSkipped    = "skipped"
+	ManifestRolledBack = "rolledBack"
+)
+
+var (
+	ErrNoManifests           = errors.New("either objects or yaml is required")
+	ErrParseManifests        = errors.New("failed to parse manifests")
+	ErrInvalidManifest       = errors.New("invalid manifest")
+	ErrRollbackDryRun        = errors.New("rollback can not be combined with dryRun, nothing is created in a dry run")
+	ErrRollbackNeedsStopping = errors.New("rollback requires stopOnError")
+)
+
+// ManifestResult is the outcome of a single object of the applyManifests mutation
+type ManifestResult struct {
+	Index      int    `json:"index"`
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	Action     string `json:"action"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ApplyManifests returns a resolver that creates the given objects in order, objects that exist already are updated
+// with a merge patch like the update mutations do. Every object gets a result, by default the remaining objects are
+// applied after a failure. With stopOnError the remaining objects are skipped, and with rollback the objects created
+// by the same mutation are deleted again in reverse order. Updated objects are not restored.
+func (r *Service) ApplyManifests() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, APPLY_MANIFESTS)
+		defer span.End()
+
+		log := r.log.With().Str("operation", "applyManifests").Logger()
+
+		objects, err := manifestsFromArgs(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.Int("objects", len(objects)))
+
+		defaultNamespace, err := getStringArg(p.Args, NamespaceArg, false)
+		if err != nil {
+			return nil, err
+		}
+		stopOnError, err := getBoolArg(p.Args, StopOnErrorArg, false)
+		if err != nil {
+			return nil, err
+		}
+		rollback, err := getBoolArg(p.Args, RollbackArg, false)
+		if err != nil {
+			return nil, err
+		}
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		if rollback && !stopOnError {
+			return nil, ErrRollbackNeedsStopping
+		}
+		if rollback && dryRunBool {
+			return nil, ErrRollbackDryRun
+		}
+		dryRun := []string{}
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		results := make([]ManifestResult, len(objects))
+		var created []int
+		failed := false
+		for i, obj := range objects {
+			results[i] = ManifestResult{Index: i, APIVersion: obj.GetAPIVersion(), Kind: obj.GetKind(), Name: obj.GetName()}
+			if failed && stopOnError {
+				results[i].Action = ManifestSkipped
+				continue
+			}
+
+			action, err := r.applyManifest(ctx, obj, defaultNamespace, dryRun)
+			results[i].Namespace = obj.GetNamespace()
+			if err != nil {
+				log.Error().Err(err).Int("index", i).Str("kind", obj.GetKind()).Str("name", obj.GetName()).Msg("Failed to apply manifest")
+				results[i].Action = ManifestFailed
+				results[i].Error = err.Error()
+				failed = true
+				continue
+			}
+
+			results[i].Action = action
+			if action == ManifestCreated {
+				created = append(created, i)
+			}
+		}
+
+		if failed && rollback {
+			for j := len(created) - 1; j >= 0; j-- {
+				i := created[j]
+				if err := r.runtimeClient.Delete(ctx, objects[i]); err != nil && !apierrors.IsNotFound(err) {
+					log.Error().Err(err).Int("index", i).Str("kind", objects[i].GetKind()).Str("name", objects[i].GetName()).Msg("Failed to roll back manifest")
+					results[i].Error = "rollback failed: " + err.Error()
+					continue
+				}
+				results[i].Action = ManifestRolledBack
+			}
+		}
+
+		return results, nil
+	}
+}
+
+// applyManifest creates the object, or updates it with a merge patch if it exists already
+func (r *Service) applyManifest(ctx context.Context, obj *unstructured.Unstructured, defaultNamespace string, dryRun []string) (string, error) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return "", fmt.Errorf("%w: apiVersion and kind are required", ErrInvalidManifest)
+	}
+	if obj.GetName() == "" {
+		return "", fmt.Errorf("%w: metadata.name is required", ErrInvalidManifest)
+	}
+
+	namespaced, err := r.runtimeClient.IsObjectNamespaced(obj)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case namespaced && obj.GetNamespace() == "":
+		if defaultNamespace == "" {
+			return "", fmt.Errorf("%w: %s %s is namespaced, set metadata.namespace or the namespace argument", ErrInvalidManifest, gvk.Kind, obj.GetName())
+		}
+		obj.SetNamespace(defaultNamespace)
+	case !namespaced:
+		obj.SetNamespace("")
+	}
+
+	if err := r.coerceObjectInput(gvk, obj.Object); err != nil {
+		return "", err
+	}
+
+	event := audit.Event{
+		Group:     gvk.Group,
+		Version:   gvk.Version,
+		Kind:      gvk.Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		DryRun:    len(dryRun) > 0,
+	}
+
+	event.Operation = "create"
+	err = r.runtimeClient.Create(ctx, obj, &client.CreateOptions{DryRun: dryRun})
+	if err == nil {
+		r.recordAudit(ctx, event, nil, obj.Object, nil)
+		return ManifestCreated, nil
+	}
+	if !apierrors.IsAlreadyExists(err) {
+		r.recordAudit(ctx, event, nil, nil, err)
+		return "", err
+	}
+
+	patchData, err := json.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(gvk)
+	existing.SetNamespace(obj.GetNamespace())
+	existing.SetName(obj.GetName())
+
+	var before map[string]interface{}
+	if r.auditor != nil {
+		if err := r.getObject(ctx, client.ObjectKeyFromObject(existing), existing); err == nil {
+			before = runtime.DeepCopyJSON(existing.Object)
+		}
+	}
+
+	event.Operation = "update"
+	if err := r.runtimeClient.Patch(ctx, existing, client.RawPatch(types.MergePatchType, patchData), &client.PatchOptions{DryRun: dryRun}); err != nil {
+		r.recordAudit(ctx, event, nil, nil, err)
+		return "", err
+	}
+	r.recordAudit(ctx, event, before, existing.Object, nil)
+	return ManifestUpdated, nil
+}
+
+// manifestsFromArgs returns the objects of the objects argument followed by the documents of the yaml argument
+func manifestsFromArgs(args map[string]interface{}) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+
+	if list, ok := args[ObjectsArg].([]interface{}); ok {
+		for i, item := range list {
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: object %d is not a JSON object", ErrParseManifests, i)
+			}
+			objects = append(objects, &unstructured.Unstructured{Object: object})
+		}
+	}
+
+	if manifests, ok := args[YAMLArg].(string); ok && strings.TrimSpace(manifests) != "" {
+		decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(manifests), 4096)
+		for {
+			var object map[string]interface{}
+			if err := decoder.Decode(&object); err != nil {
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				return nil, errors.Join(ErrParseManifests, err)
+			}
+			// Empty documents, e.g. after a trailing ---, are skipped
+			if len(object) == 0 {
+				continue
+			}
+			objects = append(objects, &unstructured.Unstructured{Object: object})
+		}
+	}
+
+	if len(objects) == 0 {
+		return nil, ErrNoManifests
+	}
+	return objects, nil
+}
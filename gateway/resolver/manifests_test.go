@@ -0,0 +1,160 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestApplyManifests(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion})
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("Namespace"), meta.RESTScopeRoot)
+
+	manifests := `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: settings
+data:
+  mode: strict
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: broken
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: last
+---
+`
+
+	tests := []struct {
+		name            string
+		args            map[string]interface{}
+		expectedActions []string
+		expectedErr     error
+	}{
+		{
+			name:            "continue_after_error",
+			args:            map[string]interface{}{resolver.YAMLArg: manifests, resolver.NamespaceArg: "team-a"},
+			expectedActions: []string{resolver.ManifestCreated, resolver.ManifestUpdated, resolver.ManifestFailed, resolver.ManifestCreated},
+		},
+		{
+			name:            "stop_on_error",
+			args:            map[string]interface{}{resolver.YAMLArg: manifests, resolver.NamespaceArg: "team-a", resolver.StopOnErrorArg: true},
+			expectedActions: []string{resolver.ManifestCreated, resolver.ManifestUpdated, resolver.ManifestFailed, resolver.ManifestSkipped},
+		},
+		{
+			name:            "rollback",
+			args:            map[string]interface{}{resolver.YAMLArg: manifests, resolver.NamespaceArg: "team-a", resolver.StopOnErrorArg: true, resolver.RollbackArg: true},
+			expectedActions: []string{resolver.ManifestRolledBack, resolver.ManifestUpdated, resolver.ManifestFailed, resolver.ManifestSkipped},
+		},
+		{
+			name: "objects_before_yaml",
+			args: map[string]interface{}{
+				resolver.ObjectsArg: []interface{}{map[string]interface{}{
+					"apiVersion": "v1",
+					"kind":       "ConfigMap",
+					"metadata":   map[string]interface{}{"name": "extra", "namespace": "team-a"},
+				}},
+				resolver.YAMLArg: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: team-b\n",
+			},
+			expectedActions: []string{resolver.ManifestCreated, resolver.ManifestCreated},
+		},
+		{
+			name:            "namespace_missing",
+			args:            map[string]interface{}{resolver.YAMLArg: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n"},
+			expectedActions: []string{resolver.ManifestFailed},
+		},
+		{
+			name:        "no_manifests_ERROR",
+			args:        map[string]interface{}{resolver.YAMLArg: "---\n"},
+			expectedErr: resolver.ErrNoManifests,
+		},
+		{
+			name:        "invalid_yaml_ERROR",
+			args:        map[string]interface{}{resolver.YAMLArg: "kind: [ConfigMap"},
+			expectedErr: resolver.ErrParseManifests,
+		},
+		{
+			name:        "rollback_without_stop_on_error_ERROR",
+			args:        map[string]interface{}{resolver.YAMLArg: manifests, resolver.RollbackArg: true},
+			expectedErr: resolver.ErrRollbackNeedsStopping,
+		},
+		{
+			name:        "rollback_dry_run_ERROR",
+			args:        map[string]interface{}{resolver.YAMLArg: manifests, resolver.StopOnErrorArg: true, resolver.RollbackArg: true, resolver.DryRunArg: true},
+			expectedErr: resolver.ErrRollbackDryRun,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().
+				WithRESTMapper(restMapper).
+				WithObjects(&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "team-a"}}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						if obj.GetName() == "broken" {
+							return errors.New("admission webhook denied the request")
+						}
+						return clt.Create(ctx, obj, opts...)
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			result, err := r.ApplyManifests()(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			results, ok := result.([]resolver.ManifestResult)
+			require.True(t, ok)
+			actions := make([]string, len(results))
+			for i, res := range results {
+				assert.Equal(t, i, res.Index)
+				actions[i] = res.Action
+				if res.Action == resolver.ManifestFailed {
+					assert.NotEmpty(t, res.Error)
+				}
+			}
+			assert.Equal(t, tt.expectedActions, actions)
+
+			if tt.name == "rollback" {
+				err := runtimeClient.Get(t.Context(), client.ObjectKey{Name: "team-a"}, &corev1.Namespace{})
+				assert.True(t, apierrors.IsNotFound(err), "namespace created by the batch should be rolled back")
+			}
+			if tt.name == "continue_after_error" {
+				configMap := &corev1.ConfigMap{}
+				require.NoError(t, runtimeClient.Get(t.Context(), client.ObjectKey{Namespace: "team-a", Name: "settings"}, configMap))
+				assert.Equal(t, map[string]string{"mode": "strict"}, configMap.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// metadataOnlyFields are the fields of an object that are served by a PartialObjectMetadata list
+var metadataOnlyFields = map[string]bool{
+	"metadata":   true,
+	"apiVersion": true,
+	"kind":       true,
+	"__typename": true,
+}
+
+// listsMetadataOnly reports whether the objects of a list query can be fetched as PartialObjectMetadata, i.e. the
+// query selects nothing but their metadata and type. itemsPath are the fields leading from the list field to the
+// objects, e.g. items for the paginated lists.
+func listsMetadataOnly(info graphql.ResolveInfo, itemsPath ...string) bool {
+	if len(info.FieldASTs) == 0 {
+		return false
+	}
+
+	for _, fieldAST := range info.FieldASTs {
+		if !selectsOnlyMetadata(fieldAST.SelectionSet, itemsPath) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func selectsOnlyMetadata(selectionSet *ast.SelectionSet, itemsPath []string) bool {
+	if selectionSet == nil {
+		return false
+	}
+
+	for _, selection := range selectionSet.Selections {
+		// Fragments may select any field, they are not resolved here to keep the planner simple
+		field, ok := selection.(*ast.Field)
+		if !ok {
+			return false
+		}
+
+		if len(itemsPath) > 0 {
+			if field.Name.Value == itemsPath[0] && !selectsOnlyMetadata(field.SelectionSet, itemsPath[1:]) {
+				return false
+			}
+			continue
+		}
+
+		if !metadataOnlyFields[field.Name.Value] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// listMetadata lists the items of list as PartialObjectMetadata, which the API server serves without spec and status
+func (r *Service) listMetadata(ctx context.Context, list *unstructured.UnstructuredList, opts ...client.ListOption) error {
+	itemGVK := list.GroupVersionKind()
+	itemGVK.Kind = strings.TrimSuffix(itemGVK.Kind, "List")
+	listGVK := itemGVK.GroupVersion().WithKind(itemGVK.Kind + "List")
+
+	metadataList := &metav1.PartialObjectMetadataList{}
+	metadataList.SetGroupVersionKind(listGVK)
+	if err := r.runtimeClient.List(ctx, metadataList, opts...); err != nil {
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(metadataList)
+	if err != nil {
+		return err
+	}
+
+	list.SetUnstructuredContent(content)
+	list.SetGroupVersionKind(listGVK)
+	// The items are typed as PartialObjectMetadata by the API server
+	for i := range list.Items {
+		list.Items[i].SetGroupVersionKind(itemGVK)
+	}
+
+	return nil
+}
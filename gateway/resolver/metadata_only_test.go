@@ -0,0 +1,132 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+// resolveInfo returns the info of the first field of the query, as passed to its resolver
+func resolveInfo(t *testing.T, query string) graphql.ResolveInfo {
+	doc, err := parser.Parse(parser.ParseParams{Source: query})
+	require.NoError(t, err)
+
+	operation := doc.Definitions[0].(*ast.OperationDefinition)
+	return graphql.ResolveInfo{FieldASTs: []*ast.Field{operation.SelectionSet.Selections[0].(*ast.Field)}}
+}
+
+func TestListItems_MetadataOnly(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	var metadataOnly bool
+	runtimeClient := fake.NewClientBuilder().
+		WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default", Labels: map[string]string{"app": "gateway"}},
+			Data:       map[string]string{"theme": "dark"},
+		}).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				_, metadataOnly = list.(*metav1.PartialObjectMetadataList)
+				return clt.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	tests := []struct {
+		name                 string
+		query                string
+		args                 map[string]any
+		connection           bool
+		expectedMetadataOnly bool
+	}{
+		{
+			name:                 "metadata",
+			query:                `{ ConfigMaps { metadata { name labels } } }`,
+			expectedMetadataOnly: true,
+		},
+		{
+			name:                 "metadata_and_type",
+			query:                `{ ConfigMaps { __typename apiVersion kind metadata { name } } }`,
+			expectedMetadataOnly: true,
+		},
+		{
+			name:                 "sorted_by_metadata",
+			query:                `{ ConfigMaps { metadata { name } } }`,
+			args:                 map[string]any{resolver.SortByArg: "metadata.name"},
+			expectedMetadataOnly: true,
+		},
+		{
+			name:  "sorted_by_other_field",
+			query: `{ ConfigMaps { metadata { name } } }`,
+			args:  map[string]any{resolver.SortByArg: "data.theme"},
+		},
+		{
+			name:  "data",
+			query: `{ ConfigMaps { metadata { name } data } }`,
+		},
+		{
+			name:  "fragment",
+			query: `{ ConfigMaps { metadata { name } ... on ConfigMap { data } } }`,
+		},
+		{
+			name:                 "connection_metadata",
+			query:                `{ ConfigMapsConnection { items { metadata { name } } pageInfo { hasNextPage } } }`,
+			connection:           true,
+			expectedMetadataOnly: true,
+		},
+		{
+			name:       "connection_data",
+			query:      `{ ConfigMapsConnection { items { metadata { name } data } } }`,
+			connection: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := graphql.ResolveParams{Context: t.Context(), Args: tt.args, Info: resolveInfo(t, tt.query)}
+			if params.Args == nil {
+				params.Args = map[string]any{}
+			}
+
+			var items []map[string]any
+			if tt.connection {
+				result, err := r.ListItemsConnection(configMapGVK, apiextensionsv1.NamespaceScoped)(params)
+				require.NoError(t, err)
+				items = result.(resolver.ListConnection).Items
+			} else {
+				result, err := r.ListItems(configMapGVK, apiextensionsv1.NamespaceScoped)(params)
+				require.NoError(t, err)
+				items = result.([]map[string]any)
+			}
+
+			assert.Equal(t, tt.expectedMetadataOnly, metadataOnly)
+			require.Len(t, items, 1)
+			assert.Equal(t, "v1", items[0]["apiVersion"])
+			assert.Equal(t, "ConfigMap", items[0]["kind"])
+
+			metadata := items[0]["metadata"].(map[string]any)
+			assert.Equal(t, "settings", metadata["name"])
+			assert.Equal(t, map[string]any{"app": "gateway"}, metadata["labels"])
+			if tt.expectedMetadataOnly {
+				assert.NotContains(t, items[0], "data")
+			} else {
+				assert.Contains(t, items[0], "data")
+			}
+		})
+	}
+}
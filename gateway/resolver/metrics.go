@@ -0,0 +1,50 @@
+package resolver
+
+import (
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	resultSuccess = "success"
+	resultError   = "error"
+)
+
+var (
+	resolverOperationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "graphql_gateway",
+		Name:      "resolver_operations_total",
+		Help:      "Total number of resolved operations per kind, operation and result",
+	}, []string{"group", "version", "kind", "operation", "result"})
+
+	resolverDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "graphql_gateway",
+		Name:      "resolver_duration_seconds",
+		Help:      "Duration of resolved operations per kind and operation",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"group", "version", "kind", "operation"})
+)
+
+// instrument records the count, the result and the duration of the resolver fn in the resolver metrics.
+// The group is reported with its original name, not the one sanitized for the schema. Failed API server requests are
+// returned as StatusError, so that clients get their status in the error extensions.
+func (r *Service) instrument(operation string, gvk schema.GroupVersionKind, fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		start := time.Now()
+		result, err := fn(p)
+
+		group := r.getOriginalGroupName(gvk.Group)
+		resolverDuration.WithLabelValues(group, gvk.Version, gvk.Kind, operation).Observe(time.Since(start).Seconds())
+		status := resultSuccess
+		if err != nil {
+			status = resultError
+		}
+		resolverOperationsTotal.WithLabelValues(group, gvk.Version, gvk.Kind, operation, status).Inc()
+
+		return result, withStatus(err)
+	}
+}
@@ -0,0 +1,40 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestResolverMetrics(t *testing.T) {
+	// The sanitized group of the schema is reported with its original name
+	gvk := schema.GroupVersionKind{Group: "metrics_example_com", Version: "v1", Kind: "ConfigMap"}
+
+	runtimeClient := fake.NewClientBuilder().Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+	r.SetGroupNames(map[string]string{"metrics_example_com": "metrics.example.com"})
+
+	count := func(t *testing.T, result string) float64 {
+		value, err := resolver.ResolverOperationsCount("metrics.example.com", "v1", "ConfigMap", resolver.GET_ITEM, result)
+		require.NoError(t, err)
+		return value
+	}
+	successes, failures := count(t, "success"), count(t, "error")
+
+	_, err := r.GetItem(gvk, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]any{resolver.NameArg: "missing", resolver.NamespaceArg: "default"},
+	})
+	require.Error(t, err)
+
+	assert.Equal(t, successes, count(t, "success"))
+	assert.Equal(t, failures+1, count(t, "error"))
+}
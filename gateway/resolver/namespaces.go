@@ -0,0 +1,208 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	CREATE_NAMESPACE = "CreateNamespace"
+	DELETE_NAMESPACE = "DeleteNamespace"
+)
+
+var (
+	ErrUnknownNamespaceTemplate   = errors.New("unknown namespace template")
+	ErrConfirmationMismatch       = errors.New("confirmation must match the name of the namespace")
+	ErrReadNamespaceTemplates     = errors.New("failed to read namespace templates file")
+	ErrParseNamespaceTemplates    = errors.New("failed to parse namespace templates file")
+	ErrDuplicateNamespaceTemplate = errors.New("duplicate namespace template name")
+)
+
+var namespaceGVK = schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+// NamespaceTemplate is a named set of namespaced resources (e.g. ResourceQuota, LimitRange, NetworkPolicy)
+// that is created together with a namespace
+type NamespaceTemplate struct {
+	Name      string           `json:"name"`
+	Resources []map[string]any `json:"resources"`
+}
+
+// NamespaceTemplatesConfig represents the namespace templates file structure
+type NamespaceTemplatesConfig struct {
+	Templates []NamespaceTemplate `json:"templates"`
+}
+
+// LoadNamespaceTemplates reads the namespace templates from a YAML or JSON file.
+// An empty path results in no templates being available.
+func LoadNamespaceTemplates(path string) (map[string]NamespaceTemplate, error) {
+	templates := make(map[string]NamespaceTemplate)
+	if path == "" {
+		return templates, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadNamespaceTemplates, err)
+	}
+	defer f.Close()
+
+	var cfg NamespaceTemplatesConfig
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&cfg); err != nil {
+		return nil, errors.Join(ErrParseNamespaceTemplates, err)
+	}
+
+	for _, template := range cfg.Templates {
+		if _, exists := templates[template.Name]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateNamespaceTemplate, template.Name)
+		}
+		templates[template.Name] = template
+	}
+
+	return templates, nil
+}
+
+// WithNamespaceTemplates sets the templates that can be referenced by the createNamespace mutation
+func (r *Service) WithNamespaceTemplates(templates map[string]NamespaceTemplate) *Service {
+	r.namespaceTemplates = templates
+	return r
+}
+
+// CreateNamespace returns a resolver that creates a namespace and applies the resources of the requested template to it.
+// If any of the template resources can not be created, the namespace is deleted again so that no partially
+// initialized namespace is left behind.
+func (r *Service) CreateNamespace() graphql.FieldResolveFn {
+	return r.audited("createNamespace", namespaceGVK, v1.ClusterScoped, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, CREATE_NAMESPACE)
+		defer span.End()
+
+		log := r.log.With().Str("operation", "createNamespace").Logger()
+
+		name, err := getStringArg(p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		templateName, err := getStringArg(p.Args, TemplateArg, false)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("namespace", name), attribute.String("template", templateName))
+
+		var template NamespaceTemplate
+		if templateName != "" {
+			var ok bool
+			if template, ok = r.namespaceTemplates[templateName]; !ok {
+				return nil, fmt.Errorf("%w: %s", ErrUnknownNamespaceTemplate, templateName)
+			}
+		}
+
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		dryRun := []string{}
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		namespace := &unstructured.Unstructured{}
+		namespace.SetGroupVersionKind(namespaceGVK)
+		namespace.SetName(name)
+		if labels := getStringMapArg(p.Args, LabelsArg); len(labels) > 0 {
+			namespace.SetLabels(labels)
+		}
+
+		if err := r.runtimeClient.Create(ctx, namespace, &client.CreateOptions{DryRun: dryRun}); err != nil {
+			log.Error().Err(err).Str("namespace", name).Msg("Failed to create namespace")
+			return nil, err
+		}
+
+		resources := make([]string, 0, len(template.Resources))
+		for _, resource := range template.Resources {
+			obj := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(resource)}
+			obj.SetNamespace(name)
+			resources = append(resources, obj.GetKind()+"/"+obj.GetName())
+
+			// The namespace does not exist in dry-run mode, so its resources can not be validated by the server
+			if dryRunBool {
+				continue
+			}
+
+			if err := r.runtimeClient.Create(ctx, obj); err != nil {
+				log.Error().Err(err).
+					Str("namespace", name).
+					Str("kind", obj.GetKind()).
+					Str("name", obj.GetName()).
+					Msg("Failed to create namespace template resource, rolling back")
+
+				if deleteErr := r.runtimeClient.Delete(ctx, namespace); deleteErr != nil {
+					log.Error().Err(deleteErr).Str("namespace", name).Msg("Failed to roll back namespace")
+					return nil, errors.Join(err, deleteErr)
+				}
+
+				return nil, fmt.Errorf("failed to create %s/%s from template %s: %w", obj.GetKind(), obj.GetName(), templateName, err)
+			}
+		}
+
+		return map[string]any{
+			"name":      name,
+			"template":  templateName,
+			"resources": resources,
+		}, nil
+	})
+}
+
+// DeleteNamespace returns a resolver that deletes a namespace once the confirmation repeats its name
+func (r *Service) DeleteNamespace() graphql.FieldResolveFn {
+	return r.audited("deleteNamespace", namespaceGVK, v1.ClusterScoped, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, DELETE_NAMESPACE)
+		defer span.End()
+
+		log := r.log.With().Str("operation", "deleteNamespace").Logger()
+
+		name, err := getStringArg(p.Args, NameArg, true)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("namespace", name))
+
+		confirmation, err := getStringArg(p.Args, ConfirmationArg, true)
+		if err != nil {
+			return nil, err
+		}
+		if confirmation != name {
+			return nil, ErrConfirmationMismatch
+		}
+
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+		dryRun := []string{}
+		if dryRunBool {
+			dryRun = []string{"All"}
+		}
+
+		namespace := &unstructured.Unstructured{}
+		namespace.SetGroupVersionKind(namespaceGVK)
+		namespace.SetName(name)
+
+		if err := r.runtimeClient.Delete(ctx, namespace, &client.DeleteOptions{DryRun: dryRun}); err != nil {
+			log.Error().Err(err).Str("namespace", name).Msg("Failed to delete namespace")
+			return nil, err
+		}
+
+		return true, nil
+	})
+}
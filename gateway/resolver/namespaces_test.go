@@ -0,0 +1,240 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestLoadNamespaceTemplates(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		expectedNames []string
+		expectedErr   error
+	}{
+		{
+			name: "valid_templates",
+			content: `
+templates:
+  - name: team
+    resources:
+      - apiVersion: v1
+        kind: ResourceQuota
+        metadata:
+          name: default-quota
+        spec:
+          hard:
+            pods: "10"
+  - name: empty
+`,
+			expectedNames: []string{"team", "empty"},
+		},
+		{
+			name: "duplicate_template_ERROR",
+			content: `
+templates:
+  - name: team
+  - name: team
+`,
+			expectedErr: resolver.ErrDuplicateNamespaceTemplate,
+		},
+		{
+			name:        "invalid_content_ERROR",
+			content:     "templates: [",
+			expectedErr: resolver.ErrParseNamespaceTemplates,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "templates.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			templates, err := resolver.LoadNamespaceTemplates(path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, templates, len(tt.expectedNames))
+			for _, name := range tt.expectedNames {
+				assert.Contains(t, templates, name)
+			}
+		})
+	}
+}
+
+func TestLoadNamespaceTemplates_EmptyPath(t *testing.T) {
+	templates, err := resolver.LoadNamespaceTemplates("")
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
+
+func TestLoadNamespaceTemplates_MissingFile(t *testing.T) {
+	_, err := resolver.LoadNamespaceTemplates(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorIs(t, err, resolver.ErrReadNamespaceTemplates)
+}
+
+func namespaceTemplates() map[string]resolver.NamespaceTemplate {
+	return map[string]resolver.NamespaceTemplate{
+		"team": {
+			Name: "team",
+			Resources: []map[string]any{
+				{
+					"apiVersion": "v1",
+					"kind":       "ResourceQuota",
+					"metadata":   map[string]any{"name": "default-quota"},
+					"spec":       map[string]any{"hard": map[string]any{"pods": "10"}},
+				},
+				{
+					"apiVersion": "v1",
+					"kind":       "LimitRange",
+					"metadata":   map[string]any{"name": "default-limits"},
+				},
+			},
+		},
+	}
+}
+
+func TestCreateNamespace(t *testing.T) {
+	errCreate := errors.New("limit ranges are not allowed")
+
+	tests := []struct {
+		name              string
+		args              map[string]any
+		failLimitRange    bool
+		expectedResult    map[string]any
+		expectedErr       error
+		expectedNamespace bool
+		expectedQuotas    int
+	}{
+		{
+			name: "with_template",
+			args: map[string]any{resolver.NameArg: "team-a", resolver.TemplateArg: "team"},
+			expectedResult: map[string]any{
+				"name":      "team-a",
+				"template":  "team",
+				"resources": []string{"ResourceQuota/default-quota", "LimitRange/default-limits"},
+			},
+			expectedNamespace: true,
+			expectedQuotas:    1,
+		},
+		{
+			name:              "without_template",
+			args:              map[string]any{resolver.NameArg: "team-a"},
+			expectedResult:    map[string]any{"name": "team-a", "template": "", "resources": []string{}},
+			expectedNamespace: true,
+		},
+		{
+			name:        "unknown_template_ERROR",
+			args:        map[string]any{resolver.NameArg: "team-a", resolver.TemplateArg: "unknown"},
+			expectedErr: resolver.ErrUnknownNamespaceTemplate,
+		},
+		{
+			// the second resource of the template fails, the namespace created before is deleted again
+			name:           "rollback_ERROR",
+			args:           map[string]any{resolver.NameArg: "team-a", resolver.TemplateArg: "team"},
+			failLimitRange: true,
+			expectedErr:    errCreate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if tt.failLimitRange && obj.GetObjectKind().GroupVersionKind().Kind == "LimitRange" {
+						return errCreate
+					}
+					return clt.Create(ctx, obj, opts...)
+				},
+			}).Build()
+
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithNamespaceTemplates(namespaceTemplates())
+			result, err := r.CreateNamespace()(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+
+			namespaceErr := runtimeClient.Get(t.Context(), client.ObjectKey{Name: "team-a"}, &corev1.Namespace{})
+			if tt.expectedNamespace {
+				assert.NoError(t, namespaceErr)
+			} else {
+				assert.True(t, apierrors.IsNotFound(namespaceErr), "the namespace should not exist, got %v", namespaceErr)
+			}
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedResult, result)
+
+			quotas := &corev1.ResourceQuotaList{}
+			require.NoError(t, runtimeClient.List(t.Context(), quotas, client.InNamespace("team-a")))
+			assert.Len(t, quotas.Items, tt.expectedQuotas)
+		})
+	}
+}
+
+func TestDeleteNamespace(t *testing.T) {
+	tests := []struct {
+		name              string
+		confirmation      string
+		expectedErr       error
+		expectedNamespace bool
+	}{
+		{
+			name:         "confirmed",
+			confirmation: "team-a",
+		},
+		{
+			name:              "confirmation_mismatch_ERROR",
+			confirmation:      "team-b",
+			expectedErr:       resolver.ErrConfirmationMismatch,
+			expectedNamespace: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().
+				WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}).
+				Build()
+
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+			result, err := r.DeleteNamespace()(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]any{resolver.NameArg: "team-a", resolver.ConfirmationArg: tt.confirmation},
+			})
+
+			namespaceErr := runtimeClient.Get(t.Context(), client.ObjectKey{Name: "team-a"}, &corev1.Namespace{})
+			if tt.expectedNamespace {
+				assert.NoError(t, namespaceErr)
+			} else {
+				assert.True(t, apierrors.IsNotFound(namespaceErr), "the namespace should be deleted, got %v", namespaceErr)
+			}
+
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, true, result)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package resolver
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OwnerRelation is a kind whose objects are commonly owned by the objects of another kind through ownerReferences
+type OwnerRelation struct {
+	Owner schema.GroupVersionKind
+	Child schema.GroupVersionKind
+}
+
+// DefaultOwnerRelations are the ownerships of the built-in controllers
+var DefaultOwnerRelations = []OwnerRelation{
+	{Owner: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, Child: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}},
+	{Owner: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, Child: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+	{Owner: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"}, Child: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+	{Owner: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "DaemonSet"}, Child: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+	{Owner: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "CronJob"}, Child: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}},
+	{Owner: schema.GroupVersionKind{Group: "batch", Version: "v1", Kind: "Job"}, Child: schema.GroupVersionKind{Version: "v1", Kind: "Pod"}},
+}
+
+// ParseOwnerRelations parses a comma separated list of owner=child pairs of kinds formatted like the read cache kinds,
+// e.g. "example.com/v1/Database=apps/v1/StatefulSet"
+func ParseOwnerRelations(relations string) ([]OwnerRelation, error) {
+	var parsed []OwnerRelation
+	for _, relation := range strings.Split(relations, ",") {
+		relation = strings.TrimSpace(relation)
+		if relation == "" {
+			continue
+		}
+
+		owner, child, ok := strings.Cut(relation, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid owner relation %q, expected owner=child", relation)
+		}
+		ownerGVK, err := parseGroupVersionKind(strings.TrimSpace(owner))
+		if err != nil {
+			return nil, fmt.Errorf("invalid owner of relation %q: %w", relation, err)
+		}
+		childGVK, err := parseGroupVersionKind(strings.TrimSpace(child))
+		if err != nil {
+			return nil, fmt.Errorf("invalid child of relation %q: %w", relation, err)
+		}
+		parsed = append(parsed, OwnerRelation{Owner: ownerGVK, Child: childGVK})
+	}
+
+	return parsed, nil
+}
+
+// Owns returns a resolver listing the objects of the child kind owned by the source object. The API server can't
+// filter by ownerReferences, so the children are listed in the namespace of the owner and matched by the owner's UID.
+func (r *Service) Owns(child schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "Owns", trace.WithAttributes(attribute.String("kind", child.Kind)))
+		defer span.End()
+
+		owner, ok := p.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		uid, _, _ := unstructured.NestedString(owner, "metadata", "uid")
+		if uid == "" {
+			return []map[string]interface{}{}, nil
+		}
+		namespace, _, _ := unstructured.NestedString(owner, "metadata", "namespace")
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(child.GroupVersion().WithKind(child.Kind + "List"))
+		if err := r.listObjects(ctx, list, client.InNamespace(namespace)); err != nil {
+			r.log.Error().Err(err).Str("kind", child.Kind).Str("namespace", namespace).Msg("Unable to list owned objects")
+			return nil, err
+		}
+
+		items := make([]map[string]interface{}, 0)
+		for _, item := range list.Items {
+			for _, ref := range item.GetOwnerReferences() {
+				if string(ref.UID) == uid {
+					r.redact(ctx, child, item.Object)
+					items = append(items, item.Object)
+					break
+				}
+			}
+		}
+
+		return items, nil
+	}
+}
+
+// OwnedBy returns a resolver getting the owners of the source object whose kind is one of owners. Owners that don't
+// exist anymore are left out, the garbage collector deletes their children eventually.
+func (r *Service) OwnedBy(owners []schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "OwnedBy")
+		defer span.End()
+
+		child, ok := p.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		object := &unstructured.Unstructured{Object: child}
+
+		items := make([]map[string]interface{}, 0)
+		for _, ref := range object.GetOwnerReferences() {
+			gv, err := schema.ParseGroupVersion(ref.APIVersion)
+			if err != nil || !slices.Contains(owners, gv.WithKind(ref.Kind)) {
+				continue
+			}
+
+			owner := &unstructured.Unstructured{}
+			owner.SetGroupVersionKind(gv.WithKind(ref.Kind))
+			err = r.getObject(ctx, client.ObjectKey{Namespace: object.GetNamespace(), Name: ref.Name}, owner)
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				r.log.Error().Err(err).Str("kind", ref.Kind).Str("name", ref.Name).Msg("Unable to get owner")
+				return nil, err
+			}
+			if owner.GetUID() != ref.UID {
+				// The owner was replaced by an object of the same name
+				continue
+			}
+			r.redact(ctx, owner.GroupVersionKind(), owner.Object)
+			items = append(items, owner.Object)
+		}
+
+		return items, nil
+	}
+}
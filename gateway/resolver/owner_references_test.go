@@ -0,0 +1,127 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestOwnerReferences(t *testing.T) {
+	replicaSetGVK := appsv1.SchemeGroupVersion.WithKind("ReplicaSet")
+	podGVK := corev1.SchemeGroupVersion.WithKind("Pod")
+
+	replicaSet := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-7d9f8b6c5", Namespace: "default", UID: "rs-uid"}}
+	ownerReference := func(kind, name string, uid types.UID) metav1.OwnerReference {
+		return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, UID: uid}
+	}
+	pod := func(name, namespace string, owners ...metav1.OwnerReference) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, OwnerReferences: owners}}
+	}
+
+	runtimeClient := fake.NewClientBuilder().WithObjects(
+		replicaSet,
+		pod("web-7d9f8b6c5-a", "default", ownerReference("ReplicaSet", "web-7d9f8b6c5", "rs-uid")),
+		pod("web-7d9f8b6c5-b", "default", ownerReference("ReplicaSet", "web-7d9f8b6c5", "rs-uid")),
+		pod("web-old-c", "default", ownerReference("ReplicaSet", "web-old", "old-uid")),
+		pod("web-7d9f8b6c5-d", "other", ownerReference("ReplicaSet", "web-7d9f8b6c5", "rs-uid")),
+	).Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	names := func(t *testing.T, result interface{}) []string {
+		items, ok := result.([]map[string]interface{})
+		require.True(t, ok)
+
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			name, _, _ := unstructured.NestedString(item, "metadata", "name")
+			names = append(names, name)
+		}
+		return names
+	}
+
+	t.Run("owns", func(t *testing.T) {
+		source, err := runtime.DefaultUnstructuredConverter.ToUnstructured(replicaSet)
+		require.NoError(t, err)
+
+		result, err := r.Owns(podGVK)(graphql.ResolveParams{Context: t.Context(), Source: source})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"web-7d9f8b6c5-a", "web-7d9f8b6c5-b"}, names(t, result))
+	})
+
+	tests := []struct {
+		name          string
+		owners        []metav1.OwnerReference
+		ownerKinds    []schema.GroupVersionKind
+		expectedNames []string
+	}{
+		{
+			name:          "owner",
+			owners:        []metav1.OwnerReference{ownerReference("ReplicaSet", "web-7d9f8b6c5", "rs-uid")},
+			ownerKinds:    []schema.GroupVersionKind{replicaSetGVK},
+			expectedNames: []string{"web-7d9f8b6c5"},
+		},
+		{
+			name:          "owner_of_other_kind",
+			owners:        []metav1.OwnerReference{ownerReference("ReplicaSet", "web-7d9f8b6c5", "rs-uid")},
+			ownerKinds:    []schema.GroupVersionKind{appsv1.SchemeGroupVersion.WithKind("StatefulSet")},
+			expectedNames: []string{},
+		},
+		{
+			name:          "deleted_owner",
+			owners:        []metav1.OwnerReference{ownerReference("ReplicaSet", "web-old", "old-uid")},
+			ownerKinds:    []schema.GroupVersionKind{replicaSetGVK},
+			expectedNames: []string{},
+		},
+		{
+			name:          "replaced_owner",
+			owners:        []metav1.OwnerReference{ownerReference("ReplicaSet", "web-7d9f8b6c5", "previous-uid")},
+			ownerKinds:    []schema.GroupVersionKind{replicaSetGVK},
+			expectedNames: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("ownedBy_"+tt.name, func(t *testing.T) {
+			source, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod("web-7d9f8b6c5-a", "default", tt.owners...))
+			require.NoError(t, err)
+
+			result, err := r.OwnedBy(tt.ownerKinds)(graphql.ResolveParams{Context: t.Context(), Source: source})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedNames, names(t, result))
+		})
+	}
+}
+
+func TestParseOwnerRelations(t *testing.T) {
+	relations, err := resolver.ParseOwnerRelations("example.com/v1/Database = apps/v1/StatefulSet, apps/v1/StatefulSet=v1/Pod,")
+	require.NoError(t, err)
+	assert.Equal(t, []resolver.OwnerRelation{
+		{
+			Owner: schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Database"},
+			Child: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		},
+		{
+			Owner: schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+			Child: schema.GroupVersionKind{Version: "v1", Kind: "Pod"},
+		},
+	}, relations)
+
+	_, err = resolver.ParseOwnerRelations("apps/v1/Deployment")
+	assert.Error(t, err)
+
+	_, err = resolver.ParseOwnerRelations("Deployment=ReplicaSet")
+	assert.Error(t, err)
+}
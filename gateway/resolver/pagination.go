@@ -0,0 +1,158 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	pkgErrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	FirstArg = "first"
+	AfterArg = "after"
+
+	// DefaultPageSize is the amount of objects of a page if first is not set
+	DefaultPageSize = 100
+)
+
+var (
+	ErrInvalidPageSize = errors.New("first must be greater than 0")
+	ErrCursorExpired   = errors.New("the cursor expired, start again from the first page")
+)
+
+// ListConnection is a page of objects returned by a paginated list query
+type ListConnection struct {
+	Items    []map[string]any `json:"items"`
+	PageInfo PageInfo         `json:"pageInfo"`
+}
+
+// PageInfo describes how to get the next page of a paginated list query
+type PageInfo struct {
+	HasNextPage bool `json:"hasNextPage"`
+	// EndCursor is the continue token of the Kubernetes list, which is passed as after to get the next page
+	EndCursor *string `json:"endCursor"`
+	// RemainingItemCount is an estimate of the objects after this page, the API server only returns it without label selector
+	RemainingItemCount *int64 `json:"remainingItemCount"`
+	// ResourceVersion is the resource version of the list, which can be passed with consistency EXACT or NOT_OLDER_THAN
+	ResourceVersion string `json:"resourceVersion"`
+}
+
+// ListItemsConnection returns a GraphQL CommonResolver function that lists a page of Kubernetes resources of the given
+// GroupVersionKind. The pages are backed by the limit and continue token of the Kubernetes list.
+func (r *Service) ListItemsConnection(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(LIST_ITEMS_CONNECTION, gvk, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, LIST_ITEMS_CONNECTION, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log, err := r.log.ChildLoggerWithAttributes(
+			"operation", "listConnection",
+			"group", gvk.Group,
+			"version", gvk.Version,
+			"kind", gvk.Kind,
+		)
+		if err != nil {
+			r.log.Error().Err(err).Msg("Failed to create child logger")
+			// Proceed with parent logger if child logger creation fails
+			log = r.log
+		}
+
+		opts, err := listOptions(ctx, p.Args, scope, log)
+		if err != nil {
+			return nil, err
+		}
+		filters, err := listFilters(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, fieldSelectorOptions(append(eventSelectors(p.Args), filterSelectors(gvk, filters)...)...)...)
+
+		since, err := eventsSince(p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		first := DefaultPageSize
+		if value, ok := p.Args[FirstArg].(int); ok {
+			first = value
+		}
+		if first <= 0 {
+			return nil, ErrInvalidPageSize
+		}
+		filterOpts := opts
+		opts = append(opts, client.Limit(int64(first)))
+
+		// An empty cursor is accepted, so that clients can request the first page with the same query
+		after, _ := p.Args[AfterArg].(string)
+		if after != "" {
+			opts = append(opts, client.Continue(after))
+		} else {
+			// Later pages are served from the snapshot of the continue token, the API server rejects a resource version with it
+			consistencyOpts, err := consistencyOptions(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, consistencyOpts...)
+		}
+
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvk)
+
+		listFn := r.listObjects
+		if listsMetadataOnly(p.Info, "items") && filtersMetadataOnly(filters) && !r.filtersWorkspaces(gvk) {
+			log.Debug().Msg("Listing metadata only")
+			listFn = r.listMetadata
+		}
+
+		if err = listFn(ctx, list, opts...); err != nil {
+			if apierrors.IsResourceExpired(err) {
+				return nil, fmt.Errorf("%w: %w", ErrCursorExpired, err)
+			}
+			log.Error().Err(err).Msg("Unable to list objects")
+			return nil, pkgErrors.Wrap(err, "unable to list objects")
+		}
+
+		// The page may be smaller than first, the cursor still continues after the last listed event
+		list.Items = filterEventsSince(list.Items, since)
+
+		if r.filtersWorkspaces(gvk) {
+			if err := r.fillWorkspacePage(ctx, list, first, listFn, filterOpts); err != nil {
+				if apierrors.IsResourceExpired(err) {
+					return nil, fmt.Errorf("%w: %w", ErrCursorExpired, err)
+				}
+				log.Error().Err(err).Msg("Unable to list workspaces")
+				return nil, pkgErrors.Wrap(err, "unable to list objects")
+			}
+		}
+		r.redactItems(ctx, gvk, list.Items)
+		list.Items = applyListFilters(list.Items, filters)
+
+		connection := ListConnection{
+			Items: make([]map[string]any, len(list.Items)),
+			PageInfo: PageInfo{
+				RemainingItemCount: list.GetRemainingItemCount(),
+				ResourceVersion:    list.GetResourceVersion(),
+			},
+		}
+		for i, item := range list.Items {
+			connection.Items[i] = item.Object
+		}
+
+		if token := list.GetContinue(); token != "" {
+			connection.PageInfo.HasNextPage = true
+			connection.PageInfo.EndCursor = &token
+		}
+
+		return connection, nil
+	})
+}
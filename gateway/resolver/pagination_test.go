@@ -0,0 +1,183 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestListItemsConnection(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	// The fake client doesn't paginate, so the list options are recorded and the continue token is set by the interceptor
+	var listOpts client.ListOptions
+	remaining := int64(41)
+	runtimeClient := fake.NewClientBuilder().
+		WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts = client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				if listOpts.Continue == "expired" {
+					return apierrors.NewResourceExpired("too old resource version")
+				}
+				if err := clt.List(ctx, list, opts...); err != nil {
+					return err
+				}
+				if listOpts.Continue == "" {
+					list.(*unstructured.UnstructuredList).SetContinue("next-page")
+					list.(*unstructured.UnstructuredList).SetRemainingItemCount(&remaining)
+				}
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	list := func(args map[string]any) (any, error) {
+		return r.ListItemsConnection(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: t.Context(), Args: args})
+	}
+
+	t.Run("first_page", func(t *testing.T) {
+		result, err := list(map[string]any{resolver.NamespaceArg: "default", resolver.FirstArg: 2, resolver.AfterArg: ""})
+		require.NoError(t, err)
+
+		connection := result.(resolver.ListConnection)
+		assert.Len(t, connection.Items, 2)
+		assert.True(t, connection.PageInfo.HasNextPage)
+		require.NotNil(t, connection.PageInfo.EndCursor)
+		assert.Equal(t, "next-page", *connection.PageInfo.EndCursor)
+		assert.Equal(t, &remaining, connection.PageInfo.RemainingItemCount)
+		assert.Equal(t, int64(2), listOpts.Limit)
+		assert.Equal(t, "default", listOpts.Namespace)
+	})
+
+	t.Run("last_page", func(t *testing.T) {
+		result, err := list(map[string]any{resolver.AfterArg: "next-page"})
+		require.NoError(t, err)
+
+		connection := result.(resolver.ListConnection)
+		assert.False(t, connection.PageInfo.HasNextPage)
+		assert.Nil(t, connection.PageInfo.EndCursor)
+		assert.Equal(t, "next-page", listOpts.Continue)
+		assert.Equal(t, int64(resolver.DefaultPageSize), listOpts.Limit)
+	})
+
+	t.Run("expired_cursor_ERROR", func(t *testing.T) {
+		_, err := list(map[string]any{resolver.AfterArg: "expired"})
+		assert.ErrorIs(t, err, resolver.ErrCursorExpired)
+	})
+
+	t.Run("invalid_page_size_ERROR", func(t *testing.T) {
+		_, err := list(map[string]any{resolver.FirstArg: 0})
+		assert.ErrorIs(t, err, resolver.ErrInvalidPageSize)
+	})
+}
+
+func TestListItems_Consistency(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	var listOpts client.ListOptions
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				listOpts = client.ListOptions{}
+				listOpts.ApplyOptions(opts)
+				return clt.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	tests := []struct {
+		name                         string
+		args                         map[string]any
+		expectedResourceVersion      string
+		expectedResourceVersionMatch metav1.ResourceVersionMatch
+		expectedErr                  error
+	}{
+		{
+			name: "quorum_by_default",
+			args: map[string]any{},
+		},
+		{
+			name: "quorum",
+			args: map[string]any{resolver.ConsistencyArg: resolver.ConsistencyQuorum},
+		},
+		{
+			name:                         "not_older_than_any_version",
+			args:                         map[string]any{resolver.ConsistencyArg: resolver.ConsistencyNotOlderThan},
+			expectedResourceVersion:      "0",
+			expectedResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		},
+		{
+			name:                         "not_older_than_version",
+			args:                         map[string]any{resolver.ConsistencyArg: resolver.ConsistencyNotOlderThan, resolver.ResourceVersionArg: "42"},
+			expectedResourceVersion:      "42",
+			expectedResourceVersionMatch: metav1.ResourceVersionMatchNotOlderThan,
+		},
+		{
+			name:                         "exact",
+			args:                         map[string]any{resolver.ConsistencyArg: resolver.ConsistencyExact, resolver.ResourceVersionArg: "42"},
+			expectedResourceVersion:      "42",
+			expectedResourceVersionMatch: metav1.ResourceVersionMatchExact,
+		},
+		{
+			name:        "exact_without_resource_version_ERROR",
+			args:        map[string]any{resolver.ConsistencyArg: resolver.ConsistencyExact},
+			expectedErr: resolver.ErrResourceVersionRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, list := range map[string]graphql.FieldResolveFn{
+				"list":       r.ListItems(configMapGVK, apiextensionsv1.NamespaceScoped),
+				"connection": r.ListItemsConnection(configMapGVK, apiextensionsv1.NamespaceScoped),
+			} {
+				listOpts = client.ListOptions{}
+				_, err := list(graphql.ResolveParams{Context: t.Context(), Args: tt.args})
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr, name)
+					continue
+				}
+				require.NoError(t, err, name)
+
+				if tt.expectedResourceVersionMatch == "" {
+					assert.Nil(t, listOpts.Raw, name)
+					continue
+				}
+				require.NotNil(t, listOpts.Raw, name)
+				assert.Equal(t, tt.expectedResourceVersion, listOpts.Raw.ResourceVersion, name)
+				assert.Equal(t, tt.expectedResourceVersionMatch, listOpts.Raw.ResourceVersionMatch, name)
+			}
+		})
+	}
+
+	t.Run("ignored_after_first_page", func(t *testing.T) {
+		_, err := r.ListItemsConnection(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.ConsistencyArg: resolver.ConsistencyExact, resolver.AfterArg: "next-page"},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, listOpts.Raw)
+		assert.Equal(t, "next-page", listOpts.Continue)
+	})
+}
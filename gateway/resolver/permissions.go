@@ -0,0 +1,165 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	VerbArg        = "verb"
+	ResourceArg    = "resource"
+	GroupArg       = "group"
+	SubresourceArg = "subresource"
+
+	// PermissionsCacheTTL is how long the result of an access review of the canI query and the permissions fields is
+	// kept per token
+	PermissionsCacheTTL = 30 * time.Second
+)
+
+// PermissionVerbs are the verbs reported by the permissions field of the resource types
+var PermissionVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete"}
+
+var ErrPermissionsDisabled = errors.New("permission checks are not enabled for this cluster")
+
+// ObjectPermissions is the source of the permissions field of an object, the verbs are reviewed when they are selected
+type ObjectPermissions struct {
+	attributes authorizationv1.ResourceAttributes
+	review     func(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error)
+}
+
+// WithPermissions enables the canI query and the permissions fields, which review the access of the caller with
+// SelfSubjectAccessReviews sent with the caller's credentials, so that UIs can hide the actions the caller may not
+// perform instead of failing them
+func (r *Service) WithPermissions(enabled bool) *Service {
+	if enabled {
+		r.permissions = &accessReviewCache{
+			ttl:     PermissionsCacheTTL,
+			entries: make(map[string]accessReviewEntry),
+		}
+	}
+	return r
+}
+
+// CanI returns a resolver reporting whether the caller may perform the verb on the resource, like kubectl auth can-i
+func (r *Service) CanI() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "CanI")
+		defer span.End()
+
+		if r.permissions == nil {
+			return nil, ErrPermissionsDisabled
+		}
+
+		verb, err := getStringArg(p.Args, VerbArg, true)
+		if err != nil {
+			return nil, err
+		}
+		resource, err := getStringArg(p.Args, ResourceArg, true)
+		if err != nil {
+			return nil, err
+		}
+
+		// The optional arguments may be empty, e.g. the core group
+		attributes := authorizationv1.ResourceAttributes{Verb: verb, Resource: resource}
+		attributes.Group, _ = p.Args[GroupArg].(string)
+		attributes.Subresource, _ = p.Args[SubresourceArg].(string)
+		attributes.Namespace, _ = p.Args[NamespaceArg].(string)
+		attributes.Name, _ = p.Args[NameArg].(string)
+
+		return r.reviewAccess(ctx, attributes)
+	}
+}
+
+// Permissions returns a resolver for the permissions field of the objects of gvk, whose verbs are resolved by
+// PermissionResolver
+func (r *Service) Permissions(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		_, span := otel.Tracer("").Start(p.Context, "Permissions", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		if r.permissions == nil {
+			return nil, ErrPermissionsDisabled
+		}
+
+		object, ok := p.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+		mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map kind %s: %w", gvk.Kind, err)
+		}
+
+		attributes := authorizationv1.ResourceAttributes{
+			Group:    gvk.Group,
+			Version:  gvk.Version,
+			Resource: mapping.Resource.Resource,
+		}
+		metadata, _ := object["metadata"].(map[string]interface{})
+		attributes.Name, _ = metadata["name"].(string)
+		if scope == v1.NamespaceScoped {
+			attributes.Namespace, _ = metadata["namespace"].(string)
+		}
+
+		return &ObjectPermissions{attributes: attributes, review: r.reviewAccess}, nil
+	}
+}
+
+// PermissionResolver returns a resolver reporting whether the caller may perform verb on the object of the
+// permissions field. The list and watch verbs are reviewed for the namespace of the object, since they aren't limited
+// to single objects.
+func PermissionResolver(verb string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		permissions, ok := p.Source.(*ObjectPermissions)
+		if !ok {
+			return false, nil
+		}
+
+		attributes := permissions.attributes
+		attributes.Verb = verb
+		if verb == "list" || verb == "watch" {
+			attributes.Name = ""
+		}
+		return permissions.review(p.Context, attributes)
+	}
+}
+
+// reviewAccess reviews whether the caller may access the resource described by attributes, the results are cached
+// per token
+func (r *Service) reviewAccess(ctx context.Context, attributes authorizationv1.ResourceAttributes) (bool, error) {
+	key := strings.Join([]string{
+		tokenHash(ctx),
+		attributes.Group,
+		attributes.Resource,
+		attributes.Subresource,
+		attributes.Namespace,
+		attributes.Name,
+		attributes.Verb,
+	}, "/")
+	if allowed, ok := r.permissions.get(key, time.Now()); ok {
+		return allowed, nil
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+	}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		return false, fmt.Errorf("failed to review access: %w", err)
+	}
+
+	r.permissions.set(key, review.Status.Allowed, time.Now())
+	return review.Status.Allowed, nil
+}
@@ -0,0 +1,95 @@
+package resolver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestPermissions(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+
+	// The caller may read the ConfigMaps of the namespace and delete the one named settings
+	var reviewed []authorizationv1.ResourceAttributes
+	runtimeClient := fake.NewClientBuilder().
+		WithRESTMapper(mapper).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review := obj.(*authorizationv1.SelfSubjectAccessReview)
+				attributes := *review.Spec.ResourceAttributes
+				reviewed = append(reviewed, attributes)
+				switch attributes.Verb {
+				case "get", "list":
+					review.Status.Allowed = true
+				case "delete":
+					review.Status.Allowed = attributes.Name == "settings"
+				}
+				return nil
+			},
+		}).
+		Build()
+
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithPermissions(true)
+	ctx := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token")
+
+	t.Run("permissions_field", func(t *testing.T) {
+		reviewed = nil
+		object := map[string]interface{}{
+			"metadata": map[string]interface{}{"name": "settings", "namespace": "default"},
+		}
+
+		permissions, err := r.Permissions(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: ctx, Source: object})
+		require.NoError(t, err)
+
+		expected := map[string]bool{"get": true, "list": true, "delete": true, "update": false}
+		for verb, allowed := range expected {
+			got, err := resolver.PermissionResolver(verb)(graphql.ResolveParams{Context: ctx, Source: permissions})
+			require.NoError(t, err)
+			assert.Equal(t, allowed, got, verb)
+		}
+		assert.Contains(t, reviewed, authorizationv1.ResourceAttributes{
+			Namespace: "default", Verb: "list", Version: "v1", Resource: "configmaps",
+		})
+
+		// The reviews are cached per token
+		_, err = resolver.PermissionResolver("get")(graphql.ResolveParams{Context: ctx, Source: permissions})
+		require.NoError(t, err)
+		assert.Len(t, reviewed, len(expected))
+	})
+
+	t.Run("canI", func(t *testing.T) {
+		allowed, err := r.CanI()(graphql.ResolveParams{Context: ctx, Args: map[string]interface{}{
+			resolver.VerbArg:      "delete",
+			resolver.ResourceArg:  "configmaps",
+			resolver.GroupArg:     "",
+			resolver.NamespaceArg: "default",
+			resolver.NameArg:      "other",
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, false, allowed)
+
+		_, err = r.CanI()(graphql.ResolveParams{Context: ctx, Args: map[string]interface{}{resolver.VerbArg: "get"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		_, err := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).CanI()(graphql.ResolveParams{Context: ctx})
+		assert.ErrorIs(t, err, resolver.ErrPermissionsDisabled)
+	})
+}
@@ -0,0 +1,182 @@
+package resolver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PodProblem describes a container that is not running as expected
+type PodProblem struct {
+	Container    string `json:"container"`
+	Reason       string `json:"reason"`
+	Message      string `json:"message,omitempty"`
+	RestartCount int64  `json:"restartCount"`
+}
+
+// benignWaitingReasons are waiting reasons that are part of a normal pod startup
+var benignWaitingReasons = map[string]bool{
+	"ContainerCreating": true,
+	"PodInitializing":   true,
+}
+
+// PodRestartCountResolver returns the sum of the restarts of all init and regular containers of a pod
+func PodRestartCountResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		var restarts int64
+		for _, status := range podContainerStatuses(p.Source) {
+			restarts += toInt64(status["restartCount"])
+		}
+
+		return restarts, nil
+	}
+}
+
+// PodLastTerminationReasonResolver returns the reason of the most recent container termination of a pod
+func PodLastTerminationReasonResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		var reason string
+		var finishedAt time.Time
+		for _, status := range podContainerStatuses(p.Source) {
+			terminated, found := nestedMapNoCopy(status, "lastState", "terminated")
+			if !found {
+				continue
+			}
+
+			terminatedReason, _ := terminated["reason"].(string)
+			if terminatedReason == "" {
+				continue
+			}
+
+			// finishedAt is optional, so a termination without it only wins if no other one is known
+			finished, _ := time.Parse(time.RFC3339, fmt.Sprint(terminated["finishedAt"]))
+			if reason == "" || finished.After(finishedAt) {
+				reason = terminatedReason
+				finishedAt = finished
+			}
+		}
+
+		if reason == "" {
+			return nil, nil
+		}
+
+		return reason, nil
+	}
+}
+
+// PodWaitingReasonResolver returns the waiting reason of the first container of a pod that is not running yet
+func PodWaitingReasonResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		for _, status := range podContainerStatuses(p.Source) {
+			reason, _, _ := unstructured.NestedString(status, "state", "waiting", "reason")
+			if reason != "" {
+				return reason, nil
+			}
+		}
+
+		return nil, nil
+	}
+}
+
+// PodProblemsResolver summarizes the containers of a pod that are waiting on an error, failed or restarted
+func PodProblemsResolver() graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		problems := []PodProblem{}
+		for _, status := range podContainerStatuses(p.Source) {
+			if problem, ok := containerProblem(status); ok {
+				problems = append(problems, problem)
+			}
+		}
+
+		return problems, nil
+	}
+}
+
+// containerProblem derives a problem from a single container status, the current state takes precedence over past restarts
+func containerProblem(status map[string]any) (PodProblem, bool) {
+	problem := PodProblem{
+		RestartCount: toInt64(status["restartCount"]),
+	}
+	problem.Container, _ = status["name"].(string)
+
+	if waiting, found := nestedMapNoCopy(status, "state", "waiting"); found {
+		reason, _ := waiting["reason"].(string)
+		if reason != "" && !benignWaitingReasons[reason] {
+			problem.Reason = reason
+			problem.Message, _ = waiting["message"].(string)
+			return problem, true
+		}
+	}
+
+	if terminated, found := nestedMapNoCopy(status, "state", "terminated"); found {
+		if exitCode := toInt64(terminated["exitCode"]); exitCode != 0 {
+			problem.Reason, _ = terminated["reason"].(string)
+			if problem.Reason == "" {
+				problem.Reason = "Error"
+			}
+			problem.Message = fmt.Sprintf("exited with code %d", exitCode)
+			if message, _ := terminated["message"].(string); message != "" {
+				problem.Message = message
+			}
+			return problem, true
+		}
+	}
+
+	if problem.RestartCount > 0 {
+		problem.Reason = "Restarted"
+		problem.Message = fmt.Sprintf("restarted %d times", problem.RestartCount)
+		if reason, _, _ := unstructured.NestedString(status, "lastState", "terminated", "reason"); reason != "" {
+			problem.Message = fmt.Sprintf("%s, last termination reason: %s", problem.Message, reason)
+		}
+		return problem, true
+	}
+
+	return PodProblem{}, false
+}
+
+// podContainerStatuses returns the init and regular container statuses of a pod object
+func podContainerStatuses(source any) []map[string]any {
+	pod, ok := source.(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	var statuses []map[string]any
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		list, _, _ := unstructured.NestedFieldNoCopy(pod, "status", field)
+		items, _ := list.([]any)
+		for _, item := range items {
+			if status, ok := item.(map[string]any); ok {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	return statuses
+}
+
+// nestedMapNoCopy returns a nested map without deep copying it, so that objects holding plain int values are supported
+func nestedMapNoCopy(obj map[string]any, fields ...string) (map[string]any, bool) {
+	value, found, _ := unstructured.NestedFieldNoCopy(obj, fields...)
+	if !found {
+		return nil, false
+	}
+
+	m, ok := value.(map[string]any)
+	return m, ok
+}
+
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
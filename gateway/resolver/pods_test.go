@@ -0,0 +1,127 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func crashingPod() map[string]any {
+	return map[string]any{
+		"status": map[string]any{
+			"initContainerStatuses": []any{
+				map[string]any{
+					"name":         "init",
+					"restartCount": int64(1),
+					"state": map[string]any{
+						"terminated": map[string]any{"exitCode": int64(0), "reason": "Completed"},
+					},
+					"lastState": map[string]any{
+						"terminated": map[string]any{"reason": "Error", "finishedAt": "2024-01-01T10:00:00Z"},
+					},
+				},
+			},
+			"containerStatuses": []any{
+				map[string]any{
+					"name":         "app",
+					"restartCount": int64(4),
+					"state": map[string]any{
+						"waiting": map[string]any{"reason": "CrashLoopBackOff", "message": "back-off restarting failed container"},
+					},
+					"lastState": map[string]any{
+						"terminated": map[string]any{"reason": "OOMKilled", "finishedAt": "2024-01-01T12:00:00Z"},
+					},
+				},
+				map[string]any{
+					"name":         "sidecar",
+					"restartCount": int64(0),
+					"state": map[string]any{
+						"running": map[string]any{"startedAt": "2024-01-01T09:00:00Z"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resolvePodField(t *testing.T, fn graphql.FieldResolveFn, pod map[string]any) any {
+	result, err := fn(graphql.ResolveParams{Source: pod})
+	require.NoError(t, err)
+	return result
+}
+
+func TestPodRestartCountResolver(t *testing.T) {
+	assert.Equal(t, int64(5), resolvePodField(t, resolver.PodRestartCountResolver(), crashingPod()))
+	assert.Equal(t, int64(0), resolvePodField(t, resolver.PodRestartCountResolver(), map[string]any{}))
+}
+
+func TestPodLastTerminationReasonResolver(t *testing.T) {
+	assert.Equal(t, "OOMKilled", resolvePodField(t, resolver.PodLastTerminationReasonResolver(), crashingPod()))
+	assert.Nil(t, resolvePodField(t, resolver.PodLastTerminationReasonResolver(), map[string]any{}))
+}
+
+func TestPodWaitingReasonResolver(t *testing.T) {
+	assert.Equal(t, "CrashLoopBackOff", resolvePodField(t, resolver.PodWaitingReasonResolver(), crashingPod()))
+	assert.Nil(t, resolvePodField(t, resolver.PodWaitingReasonResolver(), map[string]any{}))
+}
+
+func TestPodProblemsResolver(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      map[string]any
+		expected []resolver.PodProblem
+	}{
+		{
+			name: "crashing_pod",
+			pod:  crashingPod(),
+			expected: []resolver.PodProblem{
+				{Container: "init", Reason: "Restarted", Message: "restarted 1 times, last termination reason: Error", RestartCount: 1},
+				{Container: "app", Reason: "CrashLoopBackOff", Message: "back-off restarting failed container", RestartCount: 4},
+			},
+		},
+		{
+			name: "failed_container",
+			pod: map[string]any{
+				"status": map[string]any{
+					"containerStatuses": []any{
+						map[string]any{
+							"name": "job",
+							"state": map[string]any{
+								"terminated": map[string]any{"exitCode": float64(2)},
+							},
+						},
+					},
+				},
+			},
+			expected: []resolver.PodProblem{
+				{Container: "job", Reason: "Error", Message: "exited with code 2"},
+			},
+		},
+		{
+			name: "starting_pod",
+			pod: map[string]any{
+				"status": map[string]any{
+					"containerStatuses": []any{
+						map[string]any{
+							"name": "app",
+							"state": map[string]any{
+								"waiting": map[string]any{"reason": "ContainerCreating"},
+							},
+						},
+					},
+				},
+			},
+			expected: []resolver.PodProblem{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resolvePodField(t, resolver.PodProblemsResolver(), tt.pod))
+		})
+	}
+}
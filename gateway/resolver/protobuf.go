@@ -0,0 +1,101 @@
+package resolver
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// WithProtobuf makes the read resolvers fetch the built-in types known to the client scheme as typed objects,
+// so that they are transferred as protobuf. Other types, e.g. CRDs, are still transferred as JSON.
+func (r *Service) WithProtobuf(enabled bool) *Service {
+	r.protobuf = enabled
+	return r
+}
+
+// typedObjectFor returns an empty typed object for gvk if protobuf is enabled and the client scheme knows the type
+func (r *Service) typedObjectFor(gvk schema.GroupVersionKind) (runtime.Object, bool) {
+	if !r.protobuf {
+		return nil, false
+	}
+
+	scheme := r.runtimeClient.Scheme()
+	if scheme == nil || !scheme.Recognizes(gvk) {
+		return nil, false
+	}
+
+	obj, err := scheme.New(gvk)
+	if err != nil {
+		return nil, false
+	}
+
+	return obj, true
+}
+
+// getObject gets obj, preferring protobuf for built-in types
+func (r *Service) getObject(ctx context.Context, key client.ObjectKey, obj *unstructured.Unstructured) error {
+	gvk := obj.GroupVersionKind()
+
+	typed, ok := r.typedObjectFor(gvk)
+	if !ok {
+		return r.runtimeClient.Get(ctx, key, obj)
+	}
+
+	typedObj, ok := typed.(client.Object)
+	if !ok {
+		return r.runtimeClient.Get(ctx, key, obj)
+	}
+
+	if err := r.runtimeClient.Get(ctx, key, typedObj); err != nil {
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typedObj)
+	if err != nil {
+		return err
+	}
+
+	obj.SetUnstructuredContent(content)
+	// The type information is dropped when decoding into typed objects
+	obj.SetGroupVersionKind(gvk)
+
+	return nil
+}
+
+// listObjects lists the items of list, preferring protobuf for built-in types
+func (r *Service) listObjects(ctx context.Context, list *unstructured.UnstructuredList, opts ...client.ListOption) error {
+	itemGVK := list.GroupVersionKind()
+	itemGVK.Kind = strings.TrimSuffix(itemGVK.Kind, "List")
+	listGVK := itemGVK.GroupVersion().WithKind(itemGVK.Kind + "List")
+
+	typed, ok := r.typedObjectFor(listGVK)
+	if !ok {
+		return r.runtimeClient.List(ctx, list, opts...)
+	}
+
+	typedList, ok := typed.(client.ObjectList)
+	if !ok {
+		return r.runtimeClient.List(ctx, list, opts...)
+	}
+
+	if err := r.runtimeClient.List(ctx, typedList, opts...); err != nil {
+		return err
+	}
+
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(typedList)
+	if err != nil {
+		return err
+	}
+
+	list.SetUnstructuredContent(content)
+	list.SetGroupVersionKind(listGVK)
+	for i := range list.Items {
+		list.Items[i].SetGroupVersionKind(itemGVK)
+	}
+
+	return nil
+}
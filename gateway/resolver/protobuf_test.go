@@ -0,0 +1,55 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+var podGVK = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+func TestProtobufReads(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pod", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "nginx"}},
+		},
+	}
+
+	for _, protobuf := range []bool{false, true} {
+		runtimeClient := fake.NewClientBuilder().WithObjects(pod.DeepCopy()).Build()
+		r := resolver.New(testlogger.New().Logger, runtimeClient).WithProtobuf(protobuf)
+
+		item, err := r.GetItem(podGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.NameArg: "my-pod", resolver.NamespaceArg: "default"},
+		})
+		require.NoError(t, err)
+
+		obj := item.(map[string]any)
+		assert.Equal(t, "v1", obj["apiVersion"])
+		assert.Equal(t, "Pod", obj["kind"])
+		assert.Equal(t, "my-pod", obj["metadata"].(map[string]any)["name"])
+
+		items, err := r.ListItems(podGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.NamespaceArg: "default"},
+		})
+		require.NoError(t, err)
+
+		list := items.([]map[string]any)
+		require.Len(t, list, 1)
+		assert.Equal(t, "Pod", list[0]["kind"])
+		assert.Equal(t, "nginx", list[0]["spec"].(map[string]any)["containers"].([]any)[0].(map[string]any)["image"])
+	}
+}
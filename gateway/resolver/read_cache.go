@@ -0,0 +1,162 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// FreshArg bypasses the read cache, so that the objects are read from the API server
+	FreshArg = "fresh"
+	// ReadCacheAccessTTL is how long the result of the access review of a cached read is kept per token
+	ReadCacheAccessTTL = 30 * time.Second
+)
+
+// readCache serves the list and get queries of frequently read kinds from informers instead of the API server
+type readCache struct {
+	reader client.Reader
+	kinds  map[schema.GroupVersionKind]bool
+	access *accessReviewCache
+}
+
+// ParseReadCacheKinds parses a comma separated list of kinds formatted as group/version/Kind, or version/Kind for the
+// core group, e.g. "v1/ConfigMap,apps/v1/Deployment"
+func ParseReadCacheKinds(kinds string) ([]schema.GroupVersionKind, error) {
+	var gvks []schema.GroupVersionKind
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+
+		gvk, err := parseGroupVersionKind(kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid read cache kind %q: %w", kind, err)
+		}
+		gvks = append(gvks, gvk)
+	}
+
+	return gvks, nil
+}
+
+// parseGroupVersionKind parses a kind formatted as group/version/Kind, or version/Kind for the core group
+func parseGroupVersionKind(kind string) (schema.GroupVersionKind, error) {
+	i := strings.LastIndex(kind, "/")
+	if i <= 0 || i == len(kind)-1 {
+		return schema.GroupVersionKind{}, errors.New("expected group/version/Kind or version/Kind")
+	}
+	gv, err := schema.ParseGroupVersion(kind[:i])
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+
+	return gv.WithKind(kind[i+1:]), nil
+}
+
+// WithReadCache serves the list and get queries of the given kinds from reader, usually the informer cache of the
+// cluster, instead of sending a request to the API server per query. The cache is filled with the credentials of
+// the gateway, so the caller's permission to read the objects is checked with an access review, which is cached per
+// token. Callers without permission, queries with the fresh argument and queries requiring a consistency are served
+// by the API server.
+func (r *Service) WithReadCache(reader client.Reader, kinds []schema.GroupVersionKind) *Service {
+	if reader == nil || len(kinds) == 0 {
+		return r
+	}
+
+	r.readCache = &readCache{
+		reader: reader,
+		kinds:  make(map[schema.GroupVersionKind]bool, len(kinds)),
+		access: &accessReviewCache{
+			ttl:     ReadCacheAccessTTL,
+			entries: make(map[string]accessReviewEntry),
+		},
+	}
+	for _, gvk := range kinds {
+		r.readCache.kinds[gvk] = true
+	}
+	return r
+}
+
+// readsFromCache reports whether a query for gvk with args may be served from the read cache
+func (r *Service) readsFromCache(gvk schema.GroupVersionKind, args map[string]interface{}) bool {
+	if r.readCache == nil || !r.readCache.kinds[gvk] {
+		return false
+	}
+	if fresh, _ := args[FreshArg].(bool); fresh {
+		return false
+	}
+	// The cache can't tell whether it is as recent as a given resource version, and events are selected by fields
+	// the cache has no index for
+	if _, ok := args[ConsistencyArg].(Consistency); ok {
+		return false
+	}
+	return len(eventSelectors(args)) == 0
+}
+
+// canReadFromCache reviews whether the caller may read the objects of gvk in namespace with verb, i.e. whether the
+// API server would have served the query. An empty namespace reviews the access to all namespaces.
+func (r *Service) canReadFromCache(ctx context.Context, gvk schema.GroupVersionKind, namespace, verb string) bool {
+	key := strings.Join([]string{tokenHash(ctx), gvk.String(), namespace, verb}, "/")
+	if allowed, ok := r.readCache.access.get(key, time.Now()); ok {
+		return allowed
+	}
+
+	mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		r.log.Debug().Err(err).Str("kind", gvk.Kind).Msg("Unable to map kind for the access review of the read cache")
+		return false
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Resource:  mapping.Resource.Resource,
+			},
+		},
+	}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		r.log.Error().Err(err).Str("kind", gvk.Kind).Msg("Unable to review the access to the read cache")
+		return false
+	}
+
+	r.readCache.access.set(key, review.Status.Allowed, time.Now())
+	return review.Status.Allowed
+}
+
+// listFromCache lists the objects of list from the read cache, reporting false if the caller has to be served by the
+// API server instead. Only the label selector and namespace options are applied, the field filters are applied to
+// the items afterwards by the caller.
+func (r *Service) listFromCache(ctx context.Context, list *unstructured.UnstructuredList, namespace string, opts []client.ListOption) (bool, error) {
+	if !r.canReadFromCache(ctx, list.GroupVersionKind(), namespace, "list") {
+		return false, nil
+	}
+
+	if err := r.readCache.reader.List(ctx, list, opts...); err != nil {
+		return true, err
+	}
+	return true, r.checkMaxItems(len(list.Items))
+}
+
+// getFromCache gets obj from the read cache, reporting false if the caller has to be served by the API server instead
+func (r *Service) getFromCache(ctx context.Context, key client.ObjectKey, obj *unstructured.Unstructured) (bool, error) {
+	if !r.canReadFromCache(ctx, obj.GroupVersionKind(), key.Namespace, "get") {
+		return false, nil
+	}
+
+	if err := r.readCache.reader.Get(ctx, key, obj); err != nil {
+		return true, err
+	}
+	return true, nil
+}
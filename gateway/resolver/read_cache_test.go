@@ -0,0 +1,138 @@
+package resolver_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestReadCache(t *testing.T) {
+	configMapGVK := corev1.SchemeGroupVersion.WithKind("ConfigMap")
+
+	tests := []struct {
+		name            string
+		kinds           []schema.GroupVersionKind
+		allowed         bool
+		args            map[string]any
+		expectedSource  string
+		expectedReviews int32
+	}{
+		{
+			name:            "served_from_cache",
+			kinds:           []schema.GroupVersionKind{configMapGVK},
+			allowed:         true,
+			args:            map[string]any{resolver.NamespaceArg: "default"},
+			expectedSource:  "cache",
+			expectedReviews: 2,
+		},
+		{
+			name:            "denied_callers_read_from_api_server",
+			kinds:           []schema.GroupVersionKind{configMapGVK},
+			args:            map[string]any{resolver.NamespaceArg: "default"},
+			expectedSource:  "apiserver",
+			expectedReviews: 2,
+		},
+		{
+			name:           "fresh",
+			kinds:          []schema.GroupVersionKind{configMapGVK},
+			allowed:        true,
+			args:           map[string]any{resolver.NamespaceArg: "default", resolver.FreshArg: true},
+			expectedSource: "apiserver",
+		},
+		{
+			name:           "consistency",
+			kinds:          []schema.GroupVersionKind{configMapGVK},
+			allowed:        true,
+			args:           map[string]any{resolver.NamespaceArg: "default", resolver.ConsistencyArg: resolver.ConsistencyQuorum},
+			expectedSource: "apiserver",
+		},
+		{
+			name:           "kind_not_cached",
+			kinds:          []schema.GroupVersionKind{corev1.SchemeGroupVersion.WithKind("Secret")},
+			allowed:        true,
+			args:           map[string]any{resolver.NamespaceArg: "default"},
+			expectedSource: "apiserver",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviews atomic.Int32
+			mapper := meta.NewDefaultRESTMapper(nil)
+			mapper.Add(configMapGVK, meta.RESTScopeNamespace)
+
+			newConfigMap := func(source string) *corev1.ConfigMap {
+				return &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+					Data:       map[string]string{"source": source},
+				}
+			}
+
+			runtimeClient := fake.NewClientBuilder().
+				WithRESTMapper(mapper).
+				WithObjects(newConfigMap("apiserver")).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						review := obj.(*authorizationv1.SelfSubjectAccessReview)
+						reviews.Add(1)
+						assert.Equal(t, "default", review.Spec.ResourceAttributes.Namespace)
+						assert.Equal(t, "configmaps", review.Spec.ResourceAttributes.Resource)
+						review.Status.Allowed = tt.allowed
+						return nil
+					},
+				}).
+				Build()
+			readCache := fake.NewClientBuilder().WithObjects(newConfigMap("cache")).Build()
+
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithReadCache(readCache, tt.kinds)
+			ctx := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token")
+
+			listed, err := r.ListItems(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: ctx, Args: tt.args})
+			require.NoError(t, err)
+			require.Len(t, listed, 1)
+			assert.Equal(t, tt.expectedSource, listed.([]map[string]any)[0]["data"].(map[string]any)["source"])
+
+			getArgs := map[string]any{resolver.NameArg: "settings"}
+			for arg, value := range tt.args {
+				getArgs[arg] = value
+			}
+			got, err := r.GetItem(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: ctx, Args: getArgs})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedSource, got.(map[string]any)["data"].(map[string]any)["source"])
+
+			// The reviews of the list and the get are cached for the following queries of the same token
+			_, err = r.ListItems(configMapGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{Context: ctx, Args: tt.args})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedReviews, reviews.Load())
+		})
+	}
+}
+
+func TestParseReadCacheKinds(t *testing.T) {
+	kinds, err := resolver.ParseReadCacheKinds("v1/ConfigMap, apps/v1/Deployment,")
+	require.NoError(t, err)
+	assert.Equal(t, []schema.GroupVersionKind{
+		{Version: "v1", Kind: "ConfigMap"},
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+	}, kinds)
+
+	_, err = resolver.ParseReadCacheKinds("ConfigMap")
+	assert.Error(t, err)
+}
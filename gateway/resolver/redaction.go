@@ -0,0 +1,351 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+type RedactionAction string
+
+const (
+	// RedactionActionMask replaces the string values of a field with RedactedValue, values of other types are omitted
+	RedactionActionMask RedactionAction = "mask"
+	// RedactionActionOmit removes the field from the object
+	RedactionActionOmit RedactionAction = "omit"
+
+	// RedactedValue replaces the strings of masked fields
+	RedactedValue = "<redacted>"
+
+	// DefaultUnredactVerb is the verb a caller needs on a resource to read its redacted fields
+	DefaultUnredactVerb = "unredact"
+
+	// RedactionAccessCacheTTL is how long the result of an access review of the unredact verb is kept per token
+	RedactionAccessCacheTTL = 30 * time.Second
+)
+
+var (
+	ErrReadRedactionRules   = errors.New("failed to read redaction rules file")
+	ErrParseRedactionRules  = errors.New("failed to parse redaction rules file")
+	ErrInvalidRedactionRule = errors.New("invalid redaction rule")
+)
+
+// RedactionRule masks or omits fields of the objects of a kind in every response, unless the caller has Verb on the
+// resource. Group and Kind match every group and kind with "*", the core group is the empty group.
+// Fields are paths into the objects, e.g. data or metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"],
+// where [*] matches every element of a list or every value of a map.
+type RedactionRule struct {
+	Group  string          `json:"group"`
+	Kind   string          `json:"kind"`
+	Fields []string        `json:"fields"`
+	Action RedactionAction `json:"action,omitempty"`
+	Verb   string          `json:"verb,omitempty"`
+}
+
+// RedactionRulesConfig represents the redaction rules file structure
+type RedactionRulesConfig struct {
+	Rules []RedactionRule `json:"rules"`
+}
+
+// RedactionPolicy is a validated set of redaction rules, see NewRedactionPolicy
+type RedactionPolicy struct {
+	rules []compiledRedactionRule
+}
+
+type compiledRedactionRule struct {
+	RedactionRule
+	paths [][]redactionSegment
+}
+
+// redactionSegment is a step of a field path, either the key of a map or every element of a list or map
+type redactionSegment struct {
+	key      string
+	wildcard bool
+}
+
+// LoadRedactionRules reads the redaction rules from a YAML or JSON file.
+// An empty path results in no fields being redacted.
+func LoadRedactionRules(path string) (*RedactionPolicy, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadRedactionRules, err)
+	}
+	defer f.Close()
+
+	var cfg RedactionRulesConfig
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&cfg); err != nil {
+		return nil, errors.Join(ErrParseRedactionRules, err)
+	}
+
+	return NewRedactionPolicy(cfg.Rules)
+}
+
+// NewRedactionPolicy validates the rules and parses their field paths
+func NewRedactionPolicy(rules []RedactionRule) (*RedactionPolicy, error) {
+	policy := &RedactionPolicy{rules: make([]compiledRedactionRule, 0, len(rules))}
+
+	for _, rule := range rules {
+		if rule.Kind == "" {
+			return nil, fmt.Errorf("%w: kind is required", ErrInvalidRedactionRule)
+		}
+		if len(rule.Fields) == 0 {
+			return nil, fmt.Errorf("%w: %s has no fields", ErrInvalidRedactionRule, rule.Kind)
+		}
+
+		switch rule.Action {
+		case "":
+			rule.Action = RedactionActionMask
+		case RedactionActionMask, RedactionActionOmit:
+		default:
+			return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidRedactionRule, rule.Action)
+		}
+		if rule.Verb == "" {
+			rule.Verb = DefaultUnredactVerb
+		}
+
+		compiled := compiledRedactionRule{RedactionRule: rule}
+		for _, field := range rule.Fields {
+			path, err := parseRedactionPath(field)
+			if err != nil {
+				return nil, err
+			}
+			compiled.paths = append(compiled.paths, path)
+		}
+		policy.rules = append(policy.rules, compiled)
+	}
+
+	return policy, nil
+}
+
+// parseRedactionPath splits a field path into its segments, keys containing dots are quoted in brackets,
+// e.g. metadata.annotations["example.com/secret"]
+func parseRedactionPath(path string) ([]redactionSegment, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s in field %q", ErrInvalidRedactionRule, reason, path)
+	}
+
+	var segments []redactionSegment
+	for i := 0; i < len(path); {
+		if path[i] == '[' {
+			rest := path[i+1:]
+			switch {
+			case strings.HasPrefix(rest, "*]"):
+				segments = append(segments, redactionSegment{wildcard: true})
+				i += len("[*]")
+			case strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'"):
+				end := strings.Index(rest[1:], rest[:1]+"]")
+				if end < 0 {
+					return nil, invalid("unterminated bracket")
+				}
+				segments = append(segments, redactionSegment{key: rest[1 : end+1]})
+				i += len(`[""]`) + end
+			default:
+				return nil, invalid("brackets must contain a quoted key or *")
+			}
+		} else {
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			if end == 0 {
+				return nil, invalid("empty segment")
+			}
+			segments = append(segments, redactionSegment{key: path[i : i+end]})
+			i += end
+		}
+
+		if i < len(path) && path[i] == '.' {
+			i++
+			if i == len(path) {
+				return nil, invalid("empty segment")
+			}
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, invalid("empty segment")
+	}
+	return segments, nil
+}
+
+// WithRedaction masks or omits the fields of the policy in the objects returned by queries, mutations and
+// subscriptions. The unredact verb of a rule is reviewed with a SelfSubjectAccessReview sent with the caller's
+// credentials, for the resource and namespace of the object.
+func (r *Service) WithRedaction(policy *RedactionPolicy) *Service {
+	if policy != nil && len(policy.rules) > 0 {
+		r.redaction = policy
+		r.redactionAccess = &accessReviewCache{
+			ttl:     RedactionAccessCacheTTL,
+			entries: make(map[string]accessReviewEntry),
+		}
+	}
+	return r
+}
+
+// redact applies the rules of gvk to the objects in place, gvk must carry the original group name
+func (r *Service) redact(ctx context.Context, gvk schema.GroupVersionKind, objects ...map[string]interface{}) {
+	if r.redaction == nil {
+		return
+	}
+
+	var rules []compiledRedactionRule
+	for _, rule := range r.redaction.rules {
+		if (rule.Group == "*" || rule.Group == gvk.Group) && (rule.Kind == "*" || rule.Kind == gvk.Kind) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	for _, object := range objects {
+		if object == nil {
+			continue
+		}
+		namespace, _, _ := unstructured.NestedString(object, "metadata", "namespace")
+
+		for _, rule := range rules {
+			if r.mayUnredact(ctx, gvk, namespace, rule.Verb) {
+				continue
+			}
+			for _, path := range rule.paths {
+				redactValue(object, path, rule.Action)
+			}
+		}
+	}
+}
+
+// redactItems applies the rules of gvk to the items of a list in place
+func (r *Service) redactItems(ctx context.Context, gvk schema.GroupVersionKind, items []unstructured.Unstructured) {
+	if r.redaction == nil {
+		return
+	}
+
+	objects := make([]map[string]interface{}, len(items))
+	for i := range items {
+		objects[i] = items[i].Object
+	}
+	r.redact(ctx, gvk, objects...)
+}
+
+// mayUnredact reviews whether the caller has verb on the resource of gvk in namespace. The fields stay redacted if the
+// review fails.
+func (r *Service) mayUnredact(ctx context.Context, gvk schema.GroupVersionKind, namespace, verb string) bool {
+	key := strings.Join([]string{tokenHash(ctx), gvk.Group, gvk.Kind, namespace, verb}, "/")
+	if allowed, ok := r.redactionAccess.get(key, time.Now()); ok {
+		return allowed
+	}
+
+	mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		r.log.Error().Err(err).Str("kind", gvk.Kind).Msg("Unable to map kind for redaction access review")
+		return false
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      verb,
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Resource:  mapping.Resource.Resource,
+			},
+		},
+	}
+	if err := r.runtimeClient.Create(ctx, review); err != nil {
+		r.log.Error().Err(err).Str("kind", gvk.Kind).Str("verb", verb).Msg("Unable to review the access to redacted fields")
+		return false
+	}
+
+	r.redactionAccess.set(key, review.Status.Allowed, time.Now())
+	return review.Status.Allowed
+}
+
+// redactValue applies action to the fields of value at path and returns the redacted value, or false if the value
+// itself is omitted
+func redactValue(value interface{}, path []redactionSegment, action RedactionAction) (interface{}, bool) {
+	if len(path) == 0 {
+		if action == RedactionActionOmit {
+			return nil, false
+		}
+		return maskValue(value)
+	}
+
+	segment, rest := path[0], path[1:]
+	switch node := value.(type) {
+	case map[string]interface{}:
+		keys := []string{segment.key}
+		if segment.wildcard {
+			keys = make([]string, 0, len(node))
+			for key := range node {
+				keys = append(keys, key)
+			}
+		}
+		for _, key := range keys {
+			child, ok := node[key]
+			if !ok {
+				continue
+			}
+			if redacted, keep := redactValue(child, rest, action); keep {
+				node[key] = redacted
+			} else {
+				delete(node, key)
+			}
+		}
+	case []interface{}:
+		if !segment.wildcard {
+			return node, true
+		}
+		kept := node[:0]
+		for _, child := range node {
+			if redacted, keep := redactValue(child, rest, action); keep {
+				kept = append(kept, redacted)
+			}
+		}
+		return kept, true
+	}
+
+	return value, true
+}
+
+// maskValue replaces the strings in value with RedactedValue, so that the keys of masked maps stay visible. Values of
+// other types can't be masked without breaking their GraphQL type and are omitted.
+func maskValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return RedactedValue, true
+	case map[string]interface{}:
+		for key, child := range v {
+			if masked, keep := maskValue(child); keep {
+				v[key] = masked
+			} else {
+				delete(v, key)
+			}
+		}
+		return v, true
+	case []interface{}:
+		kept := v[:0]
+		for _, child := range v {
+			if masked, keep := maskValue(child); keep {
+				kept = append(kept, masked)
+			}
+		}
+		return kept, true
+	}
+	return nil, false
+}
@@ -0,0 +1,182 @@
+package resolver_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestLoadRedactionRules(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			content: `rules:
+- kind: Secret
+  fields: ["data", "stringData"]
+- group: "*"
+  kind: "*"
+  action: omit
+  fields: ['metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]']`,
+		},
+		{
+			name:        "missing_kind",
+			content:     `rules: [{fields: ["data"]}]`,
+			expectedErr: resolver.ErrInvalidRedactionRule,
+		},
+		{
+			name:        "unknown_action",
+			content:     `rules: [{kind: Secret, action: hide, fields: ["data"]}]`,
+			expectedErr: resolver.ErrInvalidRedactionRule,
+		},
+		{
+			name:        "unterminated_bracket",
+			content:     `rules: [{kind: Secret, fields: ['metadata.annotations["example.com/token']}]`,
+			expectedErr: resolver.ErrInvalidRedactionRule,
+		},
+		{
+			name:        "empty_segment",
+			content:     `rules: [{kind: Secret, fields: ["metadata..annotations"]}]`,
+			expectedErr: resolver.ErrInvalidRedactionRule,
+		},
+		{
+			name:        "malformed",
+			content:     `rules: {`,
+			expectedErr: resolver.ErrParseRedactionRules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "redaction.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			policy, err := resolver.LoadRedactionRules(path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, policy)
+		})
+	}
+
+	t.Run("missing_file", func(t *testing.T) {
+		_, err := resolver.LoadRedactionRules(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.ErrorIs(t, err, resolver.ErrReadRedactionRules)
+	})
+}
+
+func TestRedaction(t *testing.T) {
+	secretGVK := corev1.SchemeGroupVersion.WithKind("Secret")
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(secretGVK, meta.RESTScopeNamespace)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "credentials",
+			Namespace: "default",
+			Annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"example.com/owner": "team-a",
+			},
+		},
+		Data: map[string][]byte{"password": []byte("secret")},
+	}
+
+	policy, err := resolver.NewRedactionPolicy([]resolver.RedactionRule{
+		{Kind: "Secret", Fields: []string{"data"}},
+		{Group: "*", Kind: "*", Action: resolver.RedactionActionOmit, Fields: []string{
+			`metadata.annotations["kubectl.kubernetes.io/last-applied-configuration"]`,
+		}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name                string
+		unredact            bool
+		expectedData        interface{}
+		expectedAnnotations map[string]interface{}
+	}{
+		{
+			name:         "redacted",
+			expectedData: map[string]interface{}{"password": resolver.RedactedValue},
+			expectedAnnotations: map[string]interface{}{
+				"example.com/owner": "team-a",
+			},
+		},
+		{
+			name:         "unredact_verb",
+			unredact:     true,
+			expectedData: map[string]interface{}{"password": "c2VjcmV0"},
+			expectedAnnotations: map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"example.com/owner": "team-a",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reviewed []authorizationv1.ResourceAttributes
+			runtimeClient := fake.NewClientBuilder().
+				WithRESTMapper(mapper).
+				WithObjects(secret.DeepCopy()).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						review := obj.(*authorizationv1.SelfSubjectAccessReview)
+						reviewed = append(reviewed, *review.Spec.ResourceAttributes)
+						review.Status.Allowed = tt.unredact
+						return nil
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).WithRedaction(policy)
+			ctx := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token")
+
+			got, err := r.GetItem(secretGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    map[string]interface{}{resolver.NameArg: "credentials", resolver.NamespaceArg: "default"},
+			})
+			require.NoError(t, err)
+			object := got.(map[string]interface{})
+			assert.Equal(t, tt.expectedData, object["data"])
+			assert.Equal(t, tt.expectedAnnotations, object["metadata"].(map[string]interface{})["annotations"])
+
+			listed, err := r.ListItems(secretGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    map[string]interface{}{resolver.NamespaceArg: "default"},
+			})
+			require.NoError(t, err)
+			items := listed.([]map[string]any)
+			require.Len(t, items, 1)
+			assert.Equal(t, tt.expectedData, items[0]["data"])
+
+			// Both rules share the verb, which is reviewed once per token and namespace
+			assert.Len(t, reviewed, 1)
+			assert.Contains(t, reviewed, authorizationv1.ResourceAttributes{
+				Namespace: "default", Verb: resolver.DefaultUnredactVerb, Version: "v1", Resource: "secrets",
+			})
+		})
+	}
+}
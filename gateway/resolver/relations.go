@@ -57,6 +57,42 @@ func (r *Service) RelationResolver(fieldName string, gvk schema.GroupVersionKind
 	}
 }
 
+// ReferenceResolver resolves the relationship fields the listener adds next to *Ref fields, e.g. role next to roleRef,
+// by fetching the object the reference at refField refers to. refField is a dot-separated path for the relations
+// declared on CRDs, e.g. spec.clusterRef. The namespace of the reference is used for namespaced targets,
+// the namespace of the referencing object if the reference has none. Cluster-scoped targets are fetched without one.
+// Like RelationResolver, it only resolves in queries of single objects to prevent N+1 problems in lists.
+func (r *Service) ReferenceResolver(refField string, gvk schema.GroupVersionKind) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if !r.isRelationResolutionAllowedForOperation(r.detectOperationFromGraphQLInfo(p)) {
+			return nil, nil
+		}
+
+		parentObj, ok := p.Source.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		refObj, ok, _ := unstructured.NestedMap(parentObj, strings.Split(refField, ".")...)
+		if !ok {
+			return nil, nil
+		}
+
+		refInfo := r.extractReferenceInfo(refObj, gvk.Kind)
+		if refInfo.name == "" {
+			return nil, nil
+		}
+		if kind, _ := refObj["kind"].(string); kind == "" {
+			// The fallback of extractReferenceInfo lowercases kinds of several words, e.g. ServiceAccount
+			refInfo.kind = gvk.Kind
+		}
+		if refInfo.namespace == "" {
+			refInfo.namespace, _, _ = unstructured.NestedString(parentObj, "metadata", "namespace")
+		}
+
+		return r.resolveReference(p.Context, refInfo, gvk)
+	}
+}
+
 // extractReferenceInfo extracts reference details from a *Ref object
 func (r *Service) extractReferenceInfo(parentObj map[string]any, fieldName string) referenceInfo {
 	name, _ := parentObj["name"].(string)
@@ -102,6 +138,10 @@ func (r *Service) resolveReference(ctx context.Context, ref referenceInfo, targe
 	if ref.namespace != "" {
 		key.Namespace = ref.namespace
 	}
+	// References to cluster-scoped objects may carry the namespace of the referencing object, e.g. a ClusterRole
+	if namespaced, err := r.runtimeClient.IsObjectNamespaced(obj); err == nil && !namespaced {
+		key.Namespace = ""
+	}
 
 	if err := r.runtimeClient.Get(ctx, key, obj); err != nil {
 		// For "not found" errors, return nil to allow graceful degradation
@@ -125,6 +165,7 @@ func (r *Service) resolveReference(ctx context.Context, ref referenceInfo, targe
 	}
 
 	// Happy path: resource found successfully
+	r.redact(ctx, finalGVK, obj.Object)
 	return obj.Object, nil
 }
 
@@ -174,6 +215,9 @@ func (r *Service) detectOperationFromGraphQLInfo(p graphql.ResolveParams) string
 			if strings.HasPrefix(fieldLower, "update") {
 				return UPDATE_ITEM
 			}
+			if strings.HasPrefix(fieldLower, "apply") {
+				return APPLY_ITEM
+			}
 			if strings.HasPrefix(fieldLower, "delete") {
 				return DELETE_ITEM
 			}
@@ -0,0 +1,117 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestReferenceResolver(t *testing.T) {
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{rbacv1.SchemeGroupVersion})
+	restMapper.Add(rbacv1.SchemeGroupVersion.WithKind("Role"), meta.RESTScopeNamespace)
+	restMapper.Add(rbacv1.SchemeGroupVersion.WithKind("ClusterRole"), meta.RESTScopeRoot)
+
+	roleGVK := rbacv1.SchemeGroupVersion.WithKind("Role")
+	itemPath := &graphql.ResponsePath{Prev: &graphql.ResponsePath{Key: "RoleBinding"}, Key: "role"}
+	listPath := &graphql.ResponsePath{Prev: &graphql.ResponsePath{Key: "RoleBindings"}, Key: "role"}
+
+	binding := func(namespace string, roleRef map[string]any) map[string]any {
+		return map[string]any{
+			"metadata": map[string]any{"name": "binding", "namespace": namespace},
+			"roleRef":  roleRef,
+		}
+	}
+
+	tests := []struct {
+		name         string
+		source       map[string]any
+		gvk          schema.GroupVersionKind
+		path         *graphql.ResponsePath
+		expectedName string
+		expectedNS   string
+	}{
+		{
+			name:         "namespace_of_referencing_object",
+			source:       binding("team-a", map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "Role", "name": "reader"}),
+			gvk:          roleGVK,
+			path:         itemPath,
+			expectedName: "reader",
+			expectedNS:   "team-a",
+		},
+		{
+			name:         "cross_namespace",
+			source:       binding("team-a", map[string]any{"name": "reader", "namespace": "team-b"}),
+			gvk:          roleGVK,
+			path:         itemPath,
+			expectedName: "reader",
+			expectedNS:   "team-b",
+		},
+		{
+			name:         "cluster_scoped",
+			source:       binding("team-a", map[string]any{"apiGroup": "rbac.authorization.k8s.io", "kind": "ClusterRole", "name": "admin"}),
+			gvk:          roleGVK,
+			path:         itemPath,
+			expectedName: "admin",
+		},
+		{
+			name:   "not_found",
+			source: binding("team-a", map[string]any{"name": "missing"}),
+			gvk:    roleGVK,
+			path:   itemPath,
+		},
+		{
+			name:   "without_ref",
+			source: map[string]any{"metadata": map[string]any{"name": "binding", "namespace": "team-a"}},
+			gvk:    roleGVK,
+			path:   itemPath,
+		},
+		{
+			name:   "disabled_in_lists",
+			source: binding("team-a", map[string]any{"name": "reader"}),
+			gvk:    roleGVK,
+			path:   listPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().
+				WithRESTMapper(restMapper).
+				WithObjects(
+					&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "team-a"}},
+					&rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "reader", Namespace: "team-b"}},
+					&rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "admin"}},
+				).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			result, err := r.ReferenceResolver("roleRef", tt.gvk)(graphql.ResolveParams{
+				Context: t.Context(),
+				Source:  tt.source,
+				Info:    graphql.ResolveInfo{FieldName: "role", Path: tt.path},
+			})
+			require.NoError(t, err)
+
+			if tt.expectedName == "" {
+				assert.Nil(t, result)
+				return
+			}
+			obj, ok := result.(map[string]interface{})
+			require.True(t, ok)
+			metadata := obj["metadata"].(map[string]interface{})
+			assert.Equal(t, tt.expectedName, metadata["name"])
+			namespace, _ := metadata["namespace"].(string)
+			assert.Equal(t, tt.expectedNS, namespace)
+		})
+	}
+}
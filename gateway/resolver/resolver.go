@@ -2,13 +2,14 @@ package resolver
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
-	"sort"
 	"strings"
 
+	"github.com/go-openapi/spec"
 	"github.com/graphql-go/graphql"
 	pkgErrors "github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
@@ -20,43 +21,83 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/openmfp/golang-commons/logger"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/audit"
 )
 
 const (
-	LIST_ITEMS       = "ListItems"
-	GET_ITEM         = "GetItem"
-	GET_ITEM_AS_YAML = "GetItemAsYAML"
-	CREATE_ITEM      = "CreateItem"
-	UPDATE_ITEM      = "UpdateItem"
-	DELETE_ITEM      = "DeleteItem"
-	SUBSCRIBE_ITEM   = "SubscribeItem"
-	SUBSCRIBE_ITEMS  = "SubscribeItems"
+	LIST_ITEMS            = "ListItems"
+	LIST_ITEMS_CONNECTION = "ListItemsConnection"
+	GET_ITEM              = "GetItem"
+	GET_ITEM_AS_YAML      = "GetItemAsYAML"
+	CREATE_ITEM           = "CreateItem"
+	UPDATE_ITEM           = "UpdateItem"
+	APPLY_ITEM            = "ApplyItem"
+	UPDATE_ITEM_STATUS    = "UpdateItemStatus"
+	SCALE_ITEM            = "ScaleItem"
+	EVICT_ITEM            = "EvictItem"
+	CREATE_ITEM_TOKEN     = "CreateItemToken"
+	PROXY_ITEM            = "ProxyItem"
+	DELETE_ITEM           = "DeleteItem"
+	DELETE_MATCHING_ITEMS = "DeleteMatchingItems"
+	SUBSCRIBE_ITEM        = "SubscribeItem"
+	SUBSCRIBE_ITEMS       = "SubscribeItems"
 )
 
 type Provider interface {
 	CrudProvider
 	CustomQueriesProvider
+	CustomMutationsProvider
 	CommonResolver() graphql.FieldResolveFn
 	SanitizeGroupName(string) string
 	RelationResolver(fieldName string, gvk schema.GroupVersionKind) graphql.FieldResolveFn
+	ReferenceResolver(refField string, gvk schema.GroupVersionKind) graphql.FieldResolveFn
 }
 
 type CrudProvider interface {
 	ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	ListItemsConnection(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	GetItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	GetItemAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	TemplateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope, template map[string]interface{}) graphql.FieldResolveFn
 	CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	CreateItemFromYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	UpdateItemFromYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	ApplyItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	UpdateItemStatus(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	ScaleItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	EvictItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	CreateItemToken(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	ProxyItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	DeleteMatchingItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	SubscribeItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 	SubscribeItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
 }
 
 type CustomQueriesProvider interface {
 	TypeByCategory(m map[string][]TypeByCategory) graphql.FieldResolveFn
+	ClusterDefaults() graphql.FieldResolveFn
+	ClusterInfo() graphql.FieldResolveFn
+	LeaderOf() graphql.FieldResolveFn
+	CanI() graphql.FieldResolveFn
+	Permissions(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn
+	Owns(child schema.GroupVersionKind) graphql.FieldResolveFn
+	OwnedBy(owners []schema.GroupVersionKind) graphql.FieldResolveFn
+	ObjectEvents(gvk, eventGVK schema.GroupVersionKind) graphql.FieldResolveFn
+	ResourceMetrics(gvk schema.GroupVersionKind) graphql.FieldResolveFn
+}
+
+type CustomMutationsProvider interface {
+	CreateNamespace() graphql.FieldResolveFn
+	DeleteNamespace() graphql.FieldResolveFn
+	IssueKubeconfig() graphql.FieldResolveFn
+	ApplyManifests() graphql.FieldResolveFn
 }
 
 type Service struct {
@@ -64,19 +105,56 @@ type Service struct {
 	// groupNames stores relation between sanitized group names and original group names that are used in the Kubernetes API
 	groupNames    map[string]string // map[sanitizedGroupName]originalGroupName
 	runtimeClient client.WithWatch
+	// namespaceTemplates stores the templates available to the createNamespace mutation
+	namespaceTemplates map[string]NamespaceTemplate
+	// inputCoercion controls how create and update inputs are matched against the OpenAPI definitions
+	inputCoercion    InputCoercion
+	definitions      spec.Definitions
+	definitionsByGVK map[schema.GroupVersionKind]string
+	// clusterDefaults stores the presets of the cluster taken from the schema metadata
+	clusterDefaults *ClusterDefaults
+	// protobuf enables protobuf for reading built-in types, see WithProtobuf
+	protobuf bool
+	// kubeconfigIssuance configures the issueKubeconfig mutation, see WithKubeconfigIssuance
+	kubeconfigIssuance *KubeconfigIssuance
+	// subscriptionOrdering and subscriptionBufferSize control the updates kept for slow subscribers, see WithSubscriptionBuffer
+	subscriptionOrdering   SubscriptionOrdering
+	subscriptionBufferSize int
+	// watchMux shares the watches of subscriptions watching the same objects, see WithWatchMultiplexing
+	watchMux *watchMultiplexer
+	// discoveryClient and clusterInfo serve the clusterInfo query, see WithDiscovery
+	discoveryClient rest.Interface
+	clusterInfo     *clusterInfoCache
+	// workspaceAccess caches which kcp workspaces the callers may enter, see WithWorkspaceAccessFilter
+	workspaceAccess *accessReviewCache
+	// listChunkSize and listMaxItems control how list queries read their objects, see WithListChunking
+	listChunkSize int64
+	listMaxItems  int
+	// readCache serves the reads of frequently read kinds from informers, see WithReadCache
+	readCache *readCache
+	// permissions caches the access reviews of the canI query and the permissions fields, see WithPermissions
+	permissions *accessReviewCache
+	// redaction masks or omits fields of the returned objects, redactionAccess caches the unredact reviews, see WithRedaction
+	redaction       *RedactionPolicy
+	redactionAccess *accessReviewCache
+	// auditor records the mutations, see WithAudit
+	auditor *audit.Auditor
 }
 
 func New(log *logger.Logger, runtimeClient client.WithWatch) *Service {
 	return &Service{
-		log:           log,
-		groupNames:    make(map[string]string),
-		runtimeClient: runtimeClient,
+		log:                    log,
+		groupNames:             make(map[string]string),
+		runtimeClient:          runtimeClient,
+		namespaceTemplates:     make(map[string]NamespaceTemplate),
+		subscriptionOrdering:   SubscriptionOrderingOrdered,
+		subscriptionBufferSize: DefaultSubscriptionBufferSize,
 	}
 }
 
 // ListItems returns a GraphQL CommonResolver function that lists Kubernetes resources of the given GroupVersionKind.
 func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
+	return r.instrument(LIST_ITEMS, gvk, func(p graphql.ResolveParams) (interface{}, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, LIST_ITEMS, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 		defer span.End()
 
@@ -98,46 +176,84 @@ func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope)
 		list := &unstructured.UnstructuredList{}
 		list.SetGroupVersionKind(gvk)
 
-		var opts []client.ListOption
-
-		// Handle label selector argument
-		if labelSelector, ok := p.Args[LabelSelectorArg].(string); ok && labelSelector != "" {
-			selector, err := labels.Parse(labelSelector)
-			if err != nil {
-				log.Error().Err(err).Str(LabelSelectorArg, labelSelector).Msg("Unable to parse given label selector")
-				return nil, err
-			}
-			opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+		opts, err := listOptions(ctx, p.Args, scope, log)
+		if err != nil {
+			return nil, err
 		}
 
-		if isResourceNamespaceScoped(scope) {
-			namespace, err := getStringArg(p.Args, NamespaceArg, false)
-			if err != nil {
-				return nil, err
-			}
-			if namespace != "" {
-				opts = append(opts, client.InNamespace(namespace))
-			}
+		consistencyOpts, err := consistencyOptions(p.Args)
+		if err != nil {
+			return nil, err
+		}
+		filters, err := listFilters(p.Args)
+		if err != nil {
+			return nil, err
 		}
+		// The filters are applied to the items below, the read cache has no index for field selectors
+		cacheOpts := opts[:len(opts):len(opts)]
+		opts = append(opts, fieldSelectorOptions(append(eventSelectors(p.Args), filterSelectors(gvk, filters)...)...)...)
 
-		if err = r.runtimeClient.List(ctx, list, opts...); err != nil {
-			log.Error().Err(err).Msg("Unable to list objects")
-			return nil, pkgErrors.Wrap(err, "unable to list objects")
+		since, err := eventsSince(p.Args)
+		if err != nil {
+			return nil, err
 		}
 
 		sortBy, err := getStringArg(p.Args, SortByArg, false)
 		if err != nil {
 			return nil, err
 		}
+		sortDirection, err := getSortDirection(p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		listFn := r.listObjects
+		if listsMetadataOnly(p.Info) && (sortBy == "" || strings.HasPrefix(sortBy, "metadata.")) && filtersMetadataOnly(filters) &&
+			!r.filtersWorkspaces(gvk) {
+			log.Debug().Msg("Listing metadata only")
+			listFn = r.listMetadata
+		}
+
+		cached := false
+		if r.readsFromCache(gvk, p.Args) {
+			namespace, _ := getNamespaceArg(ctx, p.Args, scope, false)
+			cached, err = r.listFromCache(ctx, list, namespace, cacheOpts)
+		}
+
+		switch {
+		case cached:
+		// Events are by far the largest lists of most clusters, so they are not listed without limit
+		case gvk == EventGVK:
+			err = listFn(ctx, list, append(append(opts, consistencyOpts...), client.Limit(EventListLimit))...)
+		default:
+			err = r.listInChunks(ctx, listFn, list, opts, consistencyOpts)
+		}
+		if errors.Is(err, ErrTooManyItems) {
+			return nil, err
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Unable to list objects")
+			return nil, pkgErrors.Wrap(err, "unable to list objects")
+		}
+
+		if gvk == EventGVK && list.GetContinue() != "" {
+			return nil, ErrTooManyEvents
+		}
+		// Redacted fields are redacted before filtering and sorting, so that their values can't be probed with filters
+		r.redactItems(ctx, gvk, list.Items)
+		list.Items = filterEventsSince(list.Items, since)
+		list.Items = applyListFilters(list.Items, filters)
+
+		if r.filtersWorkspaces(gvk) {
+			list.Items = r.accessibleWorkspaces(ctx, list.Items)
+		}
 
 		if sortBy != "" {
 			if err := validateSortBy(list.Items, sortBy); err != nil {
 				log.Error().Err(err).Str(SortByArg, sortBy).Msg("Invalid sortBy field path")
 				return nil, err
 			}
-			sort.Slice(list.Items, func(i, j int) bool {
-				return compareUnstructured(list.Items[i], list.Items[j], sortBy) < 0
-			})
+			sortItems(list.Items, sortBy, sortDirection)
 		}
 
 		items := make([]map[string]any, len(list.Items))
@@ -146,12 +262,39 @@ func (r *Service) ListItems(gvk schema.GroupVersionKind, scope v1.ResourceScope)
 		}
 
 		return items, nil
+	})
+}
+
+// listOptions returns the options of the label selector and namespace arguments of list queries
+func listOptions(ctx context.Context, args map[string]interface{}, scope v1.ResourceScope, log *logger.Logger) ([]client.ListOption, error) {
+	var opts []client.ListOption
+
+	// Handle label selector argument
+	if labelSelector, ok := args[LabelSelectorArg].(string); ok && labelSelector != "" {
+		selector, err := labels.Parse(labelSelector)
+		if err != nil {
+			log.Error().Err(err).Str(LabelSelectorArg, labelSelector).Msg("Unable to parse given label selector")
+			return nil, err
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	namespace, err := getNamespaceArg(ctx, args, scope, false)
+	if err != nil {
+		return nil, err
 	}
+	if isResourceNamespaceScoped(scope) {
+		if namespace != "" {
+			opts = append(opts, client.InNamespace(namespace))
+		}
+	}
+
+	return opts, nil
 }
 
 // GetItem returns a GraphQL CommonResolver function that retrieves a single Kubernetes resource of the given GroupVersionKind.
 func (r *Service) GetItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
+	return r.instrument(GET_ITEM, gvk, func(p graphql.ResolveParams) (interface{}, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "GetItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 		defer span.End()
 
@@ -183,23 +326,30 @@ func (r *Service) GetItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) g
 			Name: name,
 		}
 
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
 		if isResourceNamespaceScoped(scope) {
-			namespace, err := getStringArg(p.Args, NamespaceArg, true)
-			if err != nil {
-				return nil, err
-			}
-
 			key.Namespace = namespace
 		}
 
+		cached := false
+		if r.readsFromCache(gvk, p.Args) {
+			cached, err = r.getFromCache(ctx, key, obj)
+		}
 		// Get the object using the runtime client
-		if err = r.runtimeClient.Get(ctx, key, obj); err != nil {
+		if !cached {
+			err = r.getObject(ctx, key, obj)
+		}
+		if err != nil {
 			log.Error().Err(err).Str("name", name).Str("scope", string(scope)).Msg("Unable to get object")
 			return nil, err
 		}
 
+		r.redact(ctx, gvk, obj.Object)
 		return obj.Object, nil
-	}
+	})
 }
 
 func (r *Service) GetItemAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
@@ -213,6 +363,11 @@ func (r *Service) GetItemAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceSc
 			return "", err
 		}
 
+		// The YAML is not limited by the output type, so input-only fields have to be removed explicitly
+		if object, ok := out.(map[string]interface{}); ok {
+			r.removeInputOnlyFields(schema.GroupVersionKind{Group: r.getOriginalGroupName(gvk.Group), Version: gvk.Version, Kind: gvk.Kind}, object)
+		}
+
 		var returnYaml bytes.Buffer
 		if err = yaml.NewEncoder(&returnYaml).Encode(out); err != nil {
 			return "", err
@@ -223,7 +378,7 @@ func (r *Service) GetItemAsYAML(gvk schema.GroupVersionKind, scope v1.ResourceSc
 }
 
 func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
+	return r.instrument(CREATE_ITEM, gvk, r.audited("create", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "CreateItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 		defer span.End()
 
@@ -232,17 +387,21 @@ func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		log := r.log.With().Str("operation", "create").Str("kind", gvk.Kind).Logger()
 
 		objectInput := p.Args["object"].(map[string]interface{})
+		if err := r.coerceObjectInput(gvk, objectInput); err != nil {
+			return nil, err
+		}
+		r.applyDefaults(gvk, objectInput)
 
 		obj := &unstructured.Unstructured{
 			Object: objectInput,
 		}
 		obj.SetGroupVersionKind(gvk)
 
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
 		if isResourceNamespaceScoped(scope) {
-			namespace, err := getStringArg(p.Args, NamespaceArg, true)
-			if err != nil {
-				return nil, err
-			}
 			obj.SetNamespace(namespace)
 		}
 
@@ -250,7 +409,7 @@ func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			return nil, errors.New("object metadata.name is required")
 		}
 
-		dryRunBool, err := getBoolArg(p.Args, DryRunArg, false)
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
 		if err != nil {
 			return nil, err
 		}
@@ -264,12 +423,13 @@ func (r *Service) CreateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			return nil, err
 		}
 
+		r.redact(ctx, gvk, obj.Object)
 		return obj.Object, nil
-	}
+	}))
 }
 
 func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
+	return r.instrument(UPDATE_ITEM, gvk, r.audited("update", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "UpdateItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 		defer span.End()
 
@@ -283,6 +443,10 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		}
 
 		objectInput := p.Args["object"].(map[string]interface{})
+		if err := r.coerceObjectInput(gvk, objectInput); err != nil {
+			return nil, err
+		}
+
 		// Marshal the input object to JSON to create the patch data
 		patchData, err := json.Marshal(objectInput)
 		if err != nil {
@@ -294,11 +458,11 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		existingObj.SetGroupVersionKind(gvk)
 
 		key := client.ObjectKey{Name: name}
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
 		if isResourceNamespaceScoped(scope) {
-			namespace, err := getStringArg(p.Args, NamespaceArg, true)
-			if err != nil {
-				return nil, err
-			}
 			key.Namespace = namespace
 		}
 
@@ -309,7 +473,7 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			return nil, err
 		}
 
-		dryRunBool, err := getBoolArg(p.Args, DryRunArg, false)
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
 		if err != nil {
 			return nil, err
 		}
@@ -325,13 +489,14 @@ func (r *Service) UpdateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 			return nil, err
 		}
 
+		r.redact(ctx, gvk, existingObj.Object)
 		return existingObj.Object, nil
-	}
+	}))
 }
 
 // DeleteItem returns a CommonResolver function for deleting a resource.
 func (r *Service) DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
-	return func(p graphql.ResolveParams) (interface{}, error) {
+	return r.instrument(DELETE_ITEM, gvk, r.audited("delete", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
 		ctx, span := otel.Tracer("").Start(p.Context, "DeleteItem", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
 		defer span.End()
 
@@ -348,15 +513,15 @@ func (r *Service) DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		obj.SetGroupVersionKind(gvk)
 		obj.SetName(name)
 
+		namespace, err := getNamespaceArg(ctx, p.Args, scope, true)
+		if err != nil {
+			return nil, err
+		}
 		if isResourceNamespaceScoped(scope) {
-			namespace, err := getStringArg(p.Args, NamespaceArg, true)
-			if err != nil {
-				return nil, err
-			}
 			obj.SetNamespace(namespace)
 		}
 
-		dryRunBool, err := getBoolArg(p.Args, DryRunArg, false)
+		dryRunBool, err := getDryRunArg(ctx, p.Args)
 		if err != nil {
 			return nil, err
 		}
@@ -371,7 +536,7 @@ func (r *Service) DeleteItem(gvk schema.GroupVersionKind, scope v1.ResourceScope
 		}
 
 		return true, nil
-	}
+	}))
 }
 
 func (r *Service) CommonResolver() graphql.FieldResolveFn {
@@ -422,28 +587,12 @@ func compareUnstructured(a, b unstructured.Unstructured, fieldPath string) int {
 	switch av := aVal.(type) {
 	case string:
 		if bv, ok := bVal.(string); ok {
-			return strings.Compare(av, bv)
+			return compareStrings(av, bv)
 		}
 	case int64:
 		if bv, ok := bVal.(int64); ok {
 			return compareNumbers(av, bv)
 		}
-	case int32:
-		if bv, ok := bVal.(int32); ok {
-			return compareNumbers(int64(av), int64(bv))
-		} else if bv, ok := bVal.(int64); ok {
-			return compareNumbers(int64(av), bv)
-		}
-	case float64:
-		if bv, ok := bVal.(float64); ok {
-			return compareNumbers(av, bv)
-		}
-	case float32:
-		if bv, ok := bVal.(float32); ok {
-			return compareNumbers(float64(av), float64(bv))
-		} else if bv, ok := bVal.(float64); ok {
-			return compareNumbers(float64(av), bv)
-		}
 	case bool:
 		if bv, ok := bVal.(bool); ok {
 			switch {
@@ -456,9 +605,33 @@ func compareUnstructured(a, b unstructured.Unstructured, fieldPath string) int {
 			}
 		}
 	}
+
+	// integers and floats of an object are decoded as int64 and float64, typed objects may use smaller types
+	if aNumber, ok := sortNumber(aVal); ok {
+		if bNumber, ok := sortNumber(bVal); ok {
+			return compareNumbers(aNumber, bNumber)
+		}
+	}
 	return 0 // unhandled or non-comparable types
 }
 
+func sortNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
 func compareNumbers[T int64 | float64](a, b T) int {
 	switch {
 	case a < b:
@@ -800,6 +800,24 @@ func TestCompareUnstructured(t *testing.T) {
 			b:        map[string]interface{}{"key": false},
 			expected: -1,
 		},
+		{
+			name:     "int64_vs_float64",
+			a:        map[string]interface{}{"key": int64(3)},
+			b:        map[string]interface{}{"key": float64(2.5)},
+			expected: 1,
+		},
+		{
+			name:     "timestamps",
+			a:        map[string]interface{}{"key": "2025-01-02T10:00:00+02:00"},
+			b:        map[string]interface{}{"key": "2025-01-02T09:00:00Z"},
+			expected: -1,
+		},
+		{
+			name:     "numeric_strings",
+			a:        map[string]interface{}{"key": "9"},
+			b:        map[string]interface{}{"key": "10"},
+			expected: -1,
+		},
 		{
 			name:     "missing_field",
 			a:        map[string]interface{}{},
@@ -0,0 +1,114 @@
+package resolver
+
+import (
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	// PodGVK and NodeGVK are the kinds that get the metrics field
+	PodGVK  = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Pod"}
+	NodeGVK = schema.GroupVersionKind{Group: "", Version: "v1", Kind: "Node"}
+
+	// PodMetricsGVK and NodeMetricsGVK are the kinds of the metrics.k8s.io API served by the metrics-server
+	PodMetricsGVK  = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetrics"}
+	NodeMetricsGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "NodeMetrics"}
+)
+
+// ResourceMetrics returns a resolver for the metrics field of Pods and Nodes, reading the current usage of the source
+// object from the metrics.k8s.io API. The field is null if the cluster serves no metrics API, the metrics API server is
+// unavailable or has no metrics of the object yet, e.g. of a pod that just started.
+func (r *Service) ResourceMetrics(gvk schema.GroupVersionKind) graphql.FieldResolveFn {
+	metricsGVK := NodeMetricsGVK
+	if gvk == PodGVK {
+		metricsGVK = PodMetricsGVK
+	}
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, "ResourceMetrics", trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		object, ok := p.Source.(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		name, _, _ := unstructured.NestedString(object, "metadata", "name")
+		namespace, _, _ := unstructured.NestedString(object, "metadata", "namespace")
+
+		metrics := &unstructured.Unstructured{}
+		metrics.SetGroupVersionKind(metricsGVK)
+		err := r.runtimeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, metrics)
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) || apierrors.IsServiceUnavailable(err) {
+			r.log.Debug().Err(err).Str("kind", gvk.Kind).Str("name", name).Msg("No resource metrics of object")
+			return nil, nil
+		}
+		if err != nil {
+			r.log.Error().Err(err).Str("kind", gvk.Kind).Str("name", name).Msg("Unable to get resource metrics of object")
+			return nil, err
+		}
+
+		return resourceMetrics(metrics.Object), nil
+	}
+}
+
+// resourceMetrics converts PodMetrics and NodeMetrics to the metrics field, the usage of a pod is the sum of the usage
+// of its containers
+func resourceMetrics(metrics map[string]interface{}) map[string]interface{} {
+	result := map[string]interface{}{
+		"timestamp": metrics["timestamp"],
+		"window":    metrics["window"],
+	}
+
+	containers, found, _ := unstructured.NestedSlice(metrics, "containers")
+	if !found {
+		usage, _, _ := unstructured.NestedStringMap(metrics, "usage")
+		result["usage"] = resourceUsage(usage)
+		return result
+	}
+
+	var cpu, memory resource.Quantity
+	containerMetrics := make([]map[string]interface{}, 0, len(containers))
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		usage, _, _ := unstructured.NestedStringMap(containerMap, "usage")
+		if quantity, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+			cpu.Add(quantity)
+		}
+		if quantity, err := resource.ParseQuantity(usage["memory"]); err == nil {
+			memory.Add(quantity)
+		}
+		containerMetrics = append(containerMetrics, map[string]interface{}{
+			"name":  containerMap["name"],
+			"usage": resourceUsage(usage),
+		})
+	}
+
+	result["containers"] = containerMetrics
+	result["usage"] = resourceUsage(map[string]string{"cpu": cpu.String(), "memory": memory.String()})
+	return result
+}
+
+// resourceUsage returns the CPU and memory quantities of a usage, together with their values in millicores and bytes
+func resourceUsage(usage map[string]string) map[string]interface{} {
+	result := map[string]interface{}{}
+	if quantity, err := resource.ParseQuantity(usage["cpu"]); err == nil {
+		result["cpu"] = quantity.String()
+		result["cpuMillicores"] = quantity.MilliValue()
+	}
+	if quantity, err := resource.ParseQuantity(usage["memory"]); err == nil {
+		result["memory"] = quantity.String()
+		result["memoryBytes"] = float64(quantity.Value())
+	}
+	return result
+}
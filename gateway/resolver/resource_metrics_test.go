@@ -0,0 +1,139 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestResourceMetrics(t *testing.T) {
+	podMetrics := map[string]interface{}{
+		"timestamp": "2025-01-01T00:00:00Z",
+		"window":    "15s",
+		"containers": []interface{}{
+			map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "250m", "memory": "64Mi"}},
+			map[string]interface{}{"name": "sidecar", "usage": map[string]interface{}{"cpu": "50m", "memory": "16Mi"}},
+		},
+	}
+	nodeMetrics := map[string]interface{}{
+		"timestamp": "2025-01-01T00:00:00Z",
+		"window":    "20s",
+		"usage":     map[string]interface{}{"cpu": "1500m", "memory": "2Gi"},
+	}
+	pod := map[string]interface{}{"metadata": map[string]interface{}{"name": "web", "namespace": "default"}}
+	node := map[string]interface{}{"metadata": map[string]interface{}{"name": "worker-1"}}
+
+	tests := []struct {
+		name     string
+		gvk      schema.GroupVersionKind
+		source   map[string]interface{}
+		getErr   error
+		expected interface{}
+		wantErr  bool
+	}{
+		{
+			name:   "pod",
+			gvk:    resolver.PodGVK,
+			source: pod,
+			expected: map[string]interface{}{
+				"timestamp": "2025-01-01T00:00:00Z",
+				"window":    "15s",
+				"usage":     map[string]interface{}{"cpu": "300m", "cpuMillicores": int64(300), "memory": "80Mi", "memoryBytes": float64(80 * 1024 * 1024)},
+				"containers": []map[string]interface{}{
+					{"name": "app", "usage": map[string]interface{}{"cpu": "250m", "cpuMillicores": int64(250), "memory": "64Mi", "memoryBytes": float64(64 * 1024 * 1024)}},
+					{"name": "sidecar", "usage": map[string]interface{}{"cpu": "50m", "cpuMillicores": int64(50), "memory": "16Mi", "memoryBytes": float64(16 * 1024 * 1024)}},
+				},
+			},
+		},
+		{
+			name:   "node",
+			gvk:    resolver.NodeGVK,
+			source: node,
+			expected: map[string]interface{}{
+				"timestamp": "2025-01-01T00:00:00Z",
+				"window":    "20s",
+				"usage":     map[string]interface{}{"cpu": "1500m", "cpuMillicores": int64(1500), "memory": "2Gi", "memoryBytes": float64(2 * 1024 * 1024 * 1024)},
+			},
+		},
+		{
+			name:   "no_metrics_yet",
+			gvk:    resolver.PodGVK,
+			source: pod,
+			getErr: apierrors.NewNotFound(resolver.PodMetricsGVK.GroupVersion().WithResource("pods").GroupResource(), "web"),
+		},
+		{
+			name:   "no_metrics_api",
+			gvk:    resolver.NodeGVK,
+			source: node,
+			getErr: &meta.NoKindMatchError{GroupKind: resolver.NodeMetricsGVK.GroupKind(), SearchedVersions: []string{"v1beta1"}},
+		},
+		{
+			name:   "metrics_api_unavailable",
+			gvk:    resolver.NodeGVK,
+			source: node,
+			getErr: apierrors.NewServiceUnavailable("the server is currently unable to handle the request"),
+		},
+		{
+			name:    "other_error",
+			gvk:     resolver.PodGVK,
+			source:  pod,
+			getErr:  errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKey client.ObjectKey
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, clt client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						gotKey = key
+						if tt.getErr != nil {
+							return tt.getErr
+						}
+
+						metrics := obj.(*unstructured.Unstructured)
+						if metrics.GroupVersionKind() == resolver.PodMetricsGVK {
+							metrics.Object = podMetrics
+						} else {
+							metrics.Object = nodeMetrics
+						}
+						return nil
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			result, err := r.ResourceMetrics(tt.gvk)(graphql.ResolveParams{Context: t.Context(), Source: tt.source})
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			name, _, _ := unstructured.NestedString(tt.source, "metadata", "name")
+			namespace, _, _ := unstructured.NestedString(tt.source, "metadata", "namespace")
+			assert.Equal(t, client.ObjectKey{Namespace: namespace, Name: name}, gotKey)
+			if tt.expected == nil {
+				assert.Nil(t, result)
+				return
+			}
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
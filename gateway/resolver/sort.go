@@ -0,0 +1,85 @@
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const SortDirectionArg = "sortDirection"
+
+// SortDirection is the order in which list queries return the objects sorted by the sortBy field
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "asc"
+	SortDirectionDesc SortDirection = "desc"
+)
+
+var sortDirectionEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name:        "SortDirection",
+	Description: "The order in which the objects are sorted by the sortBy field",
+	Values: graphql.EnumValueConfigMap{
+		"ASC":  &graphql.EnumValueConfig{Value: SortDirectionAsc, Description: "Smallest, oldest or alphabetically first value first"},
+		"DESC": &graphql.EnumValueConfig{Value: SortDirectionDesc, Description: "Largest, newest or alphabetically last value first"},
+	},
+})
+
+// getSortDirection returns the sortDirection argument, ascending if it is not set
+func getSortDirection(args map[string]interface{}) (SortDirection, error) {
+	value, ok := args[SortDirectionArg]
+	if !ok || value == nil {
+		return SortDirectionAsc, nil
+	}
+
+	direction, _ := value.(SortDirection)
+	switch direction {
+	case SortDirectionAsc, SortDirectionDesc:
+		return direction, nil
+	default:
+		return "", fmt.Errorf("invalid %s argument %v", SortDirectionArg, value)
+	}
+}
+
+// sortItems sorts the objects by the value of the field path, objects without the field come last in both directions
+// and objects with equal values keep their order
+func sortItems(items []unstructured.Unstructured, fieldPath string, direction SortDirection) {
+	segments := strings.Split(fieldPath, ".")
+	hasField := func(item unstructured.Unstructured) bool {
+		value, found, err := unstructured.NestedFieldNoCopy(item.Object, segments...)
+		return found && err == nil && value != nil
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if iHasField, jHasField := hasField(items[i]), hasField(items[j]); iHasField != jHasField {
+			return iHasField
+		}
+
+		if direction == SortDirectionDesc {
+			return compareUnstructured(items[i], items[j], fieldPath) > 0
+		}
+		return compareUnstructured(items[i], items[j], fieldPath) < 0
+	})
+}
+
+// compareStrings compares RFC 3339 timestamps by time and numeric strings by value, all other strings lexically
+func compareStrings(a, b string) int {
+	if aTime, err := time.Parse(time.RFC3339, a); err == nil {
+		if bTime, err := time.Parse(time.RFC3339, b); err == nil {
+			return aTime.Compare(bTime)
+		}
+	}
+
+	if aNumber, err := strconv.ParseFloat(a, 64); err == nil {
+		if bNumber, err := strconv.ParseFloat(b, 64); err == nil {
+			return compareNumbers(aNumber, bNumber)
+		}
+	}
+
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,81 @@
+package resolver_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestListItems_SortBy(t *testing.T) {
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	pod := func(name string, age time.Duration, phase corev1.PodPhase, priority int32) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", CreationTimestamp: metav1.NewTime(created.Add(-age))},
+			Spec:       corev1.PodSpec{Priority: &priority},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	runtimeClient := fake.NewClientBuilder().
+		WithObjects(
+			pod("api", 2*time.Hour, corev1.PodRunning, 100),
+			pod("job", 10*time.Hour, "", 9),
+			pod("worker", time.Hour, corev1.PodPending, 1000),
+		).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	tests := []struct {
+		name      string
+		sortBy    string
+		direction resolver.SortDirection
+		expected  []string
+	}{
+		{name: "default", expected: []string{"api", "job", "worker"}},
+		{name: "name_desc", sortBy: "metadata.name", direction: resolver.SortDirectionDesc, expected: []string{"worker", "job", "api"}},
+		{name: "oldest_first", sortBy: "metadata.creationTimestamp", direction: resolver.SortDirectionAsc, expected: []string{"job", "api", "worker"}},
+		{name: "newest_first", sortBy: "metadata.creationTimestamp", direction: resolver.SortDirectionDesc, expected: []string{"worker", "api", "job"}},
+		{name: "numbers", sortBy: "spec.priority", direction: resolver.SortDirectionDesc, expected: []string{"worker", "api", "job"}},
+		{name: "missing_field_last", sortBy: "status.phase", direction: resolver.SortDirectionDesc, expected: []string{"api", "worker", "job"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{resolver.SortByArg: "metadata.name"}
+			if tt.sortBy != "" {
+				args[resolver.SortByArg] = tt.sortBy
+				args[resolver.SortDirectionArg] = tt.direction
+			}
+
+			result, err := r.ListItems(corev1.SchemeGroupVersion.WithKind("Pod"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    args,
+			})
+			require.NoError(t, err)
+
+			names := make([]string, 0, len(tt.expected))
+			for _, item := range result.([]map[string]any) {
+				names = append(names, item["metadata"].(map[string]any)["name"].(string))
+			}
+			assert.Equal(t, tt.expected, names)
+		})
+	}
+
+	t.Run("invalid_direction", func(t *testing.T) {
+		_, err := r.ListItems(corev1.SchemeGroupVersion.WithKind("Pod"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+			Context: t.Context(),
+			Args:    map[string]any{resolver.SortByArg: "metadata.name", resolver.SortDirectionArg: "sideways"},
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,100 @@
+package resolver
+
+import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// statusCodes maps the failures of API server requests to the code in the error extensions, the first match wins
+var statusCodes = []struct {
+	matches func(error) bool
+	code    string
+}{
+	{apierrors.IsNotFound, "NotFound"},
+	{apierrors.IsAlreadyExists, "Conflict"},
+	{apierrors.IsConflict, "Conflict"},
+	{apierrors.IsInvalid, "Invalid"},
+	{apierrors.IsForbidden, "Forbidden"},
+	{apierrors.IsUnauthorized, "Unauthorized"},
+	{apierrors.IsBadRequest, "BadRequest"},
+	{apierrors.IsTooManyRequests, "TooManyRequests"},
+	{apierrors.IsTimeout, "Timeout"},
+	{apierrors.IsServerTimeout, "Timeout"},
+	{apierrors.IsResourceExpired, "Gone"},
+	{apierrors.IsGone, "Gone"},
+	{apierrors.IsServiceUnavailable, "ServiceUnavailable"},
+}
+
+// StatusError is a failed API server request, it carries the status returned by the API server to the client
+type StatusError struct {
+	err    error
+	status metav1.Status
+}
+
+func (e *StatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.err
+}
+
+// Extensions implements gqlerrors.ExtendedError, so that clients can branch on the kind of failure
+func (e *StatusError) Extensions() map[string]interface{} {
+	extensions := map[string]interface{}{
+		"code":   e.Code(),
+		"reason": string(e.status.Reason),
+		"status": e.status.Code,
+	}
+
+	if details := e.status.Details; details != nil {
+		causes := make([]map[string]interface{}, 0, len(details.Causes))
+		for _, cause := range details.Causes {
+			causes = append(causes, map[string]interface{}{
+				"reason":  string(cause.Type),
+				"message": cause.Message,
+				"field":   cause.Field,
+			})
+		}
+		extensions["details"] = map[string]interface{}{
+			"group":  details.Group,
+			"kind":   details.Kind,
+			"name":   details.Name,
+			"causes": causes,
+		}
+	}
+
+	return extensions
+}
+
+// Code returns the kind of failure, e.g. NotFound or Invalid, falling back to InternalError
+func (e *StatusError) Code() string {
+	for _, statusCode := range statusCodes {
+		if statusCode.matches(e.err) {
+			return statusCode.code
+		}
+	}
+	return "InternalError"
+}
+
+// withStatus returns err as a StatusError if it is caused by a failed API server request, other errors are returned
+// unchanged
+func withStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return err
+	}
+
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return err
+	}
+
+	return &StatusError{err: err, status: apiStatus.Status()}
+}
@@ -0,0 +1,128 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestStatusErrorExtensions(t *testing.T) {
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	tests := []struct {
+		name               string
+		err                error
+		expectedExtensions map[string]interface{}
+	}{
+		{
+			name: "not_found",
+			err:  apierrors.NewNotFound(configMaps, "settings"),
+			expectedExtensions: map[string]interface{}{
+				"code":   "NotFound",
+				"reason": "NotFound",
+				"status": int32(404),
+				"details": map[string]interface{}{
+					"group":  "",
+					"kind":   "configmaps",
+					"name":   "settings",
+					"causes": []map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name: "invalid_with_causes",
+			err: apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "settings", field.ErrorList{
+				field.Required(field.NewPath("data", "mode"), "mode is required"),
+			}),
+			expectedExtensions: map[string]interface{}{
+				"code":   "Invalid",
+				"reason": "Invalid",
+				"status": int32(422),
+				"details": map[string]interface{}{
+					"group": "",
+					"kind":  "ConfigMap",
+					"name":  "settings",
+					"causes": []map[string]interface{}{
+						{"reason": "FieldValueRequired", "message": "Required value: mode is required", "field": "data.mode"},
+					},
+				},
+			},
+		},
+		{
+			name: "already_exists_is_a_conflict",
+			err:  apierrors.NewAlreadyExists(configMaps, "settings"),
+			expectedExtensions: map[string]interface{}{
+				"code":   "Conflict",
+				"reason": "AlreadyExists",
+				"status": int32(409),
+				"details": map[string]interface{}{
+					"group":  "",
+					"kind":   "configmaps",
+					"name":   "settings",
+					"causes": []map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name: "forbidden",
+			err:  apierrors.NewForbidden(configMaps, "settings", errors.New("denied")),
+			expectedExtensions: map[string]interface{}{
+				"code":   "Forbidden",
+				"reason": "Forbidden",
+				"status": int32(403),
+				"details": map[string]interface{}{
+					"group":  "",
+					"kind":   "configmaps",
+					"name":   "settings",
+					"causes": []map[string]interface{}{},
+				},
+			},
+		},
+		{
+			name: "not_a_status_error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, clt client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					return tt.err
+				},
+			}).Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			_, err := r.GetItem(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    map[string]interface{}{resolver.NameArg: "settings", resolver.NamespaceArg: "default"},
+			})
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tt.err)
+
+			var extended gqlerrors.ExtendedError
+			if tt.expectedExtensions == nil {
+				assert.False(t, errors.As(err, &extended))
+				return
+			}
+			require.True(t, errors.As(err, &extended))
+			assert.Equal(t, tt.expectedExtensions, extended.Extensions())
+		})
+	}
+}
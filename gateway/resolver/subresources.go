@@ -0,0 +1,359 @@
+package resolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+const (
+	ReplicasArg           = "replicas"
+	GracePeriodSecondsArg = "gracePeriodSeconds"
+	ExpirationSecondsArg  = "expirationSeconds"
+	AudiencesArg          = "audiences"
+	PathArg               = "path"
+	PortArg               = "port"
+)
+
+var (
+	ErrProxyUnavailable = errors.New("proxy requests are not available for this cluster")
+	ErrInvalidProxyPath = errors.New("the proxy path must not contain .. segments")
+	ErrInvalidProxyPort = errors.New("the proxy port must be a port name or number")
+)
+
+// ScaleResult is the scale subresource of an object after a scale mutation
+type ScaleResult struct {
+	// Replicas is the desired amount of replicas
+	Replicas int64 `json:"replicas"`
+	// CurrentReplicas is the amount of replicas observed by the controller of the object
+	CurrentReplicas int64 `json:"currentReplicas"`
+	// Selector is the label selector of the replicas, in the string form of a label selector
+	Selector string `json:"selector"`
+}
+
+// TokenResult is a token issued via the token subresource of a service account
+type TokenResult struct {
+	Token string `json:"token"`
+	// ExpirationTimestamp is when the token expires, as RFC 3339 timestamp
+	ExpirationTimestamp string `json:"expirationTimestamp"`
+}
+
+// UpdateItemStatus returns a resolver that merges the status of the object input into the status subresource.
+// Only the status is sent, the API server ignores changes of other fields on the status subresource anyway.
+func (r *Service) UpdateItemStatus(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(UPDATE_ITEM_STATUS, gvk, r.audited("updateStatus", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, UPDATE_ITEM_STATUS, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "updateStatus").Str("kind", gvk.Kind).Logger()
+
+		obj, err := subresourceObject(ctx, p.Args, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		objectInput := p.Args[ObjectArg].(map[string]interface{})
+		if err := r.coerceObjectInput(gvk, objectInput); err != nil {
+			return nil, err
+		}
+		status, ok := objectInput["status"]
+		if !ok || status == nil {
+			return nil, errors.New("object status is required")
+		}
+
+		patchData, err := json.Marshal(map[string]interface{}{"status": status})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal object status: %v", err)
+		}
+
+		opts, err := subresourcePatchOptions(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		patch := client.RawPatch(types.MergePatchType, patchData)
+		if err := r.runtimeClient.Status().Patch(ctx, obj, patch, opts); err != nil {
+			log.Error().Err(err).Msg("Failed to patch object status")
+			return nil, err
+		}
+
+		r.redact(ctx, gvk, obj.Object)
+		return obj.Object, nil
+	}))
+}
+
+// ScaleItem returns a resolver that sets the desired replicas of an object via its scale subresource
+func (r *Service) ScaleItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(SCALE_ITEM, gvk, r.audited("scale", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, SCALE_ITEM, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "scale").Str("kind", gvk.Kind).Logger()
+
+		obj, err := subresourceObject(ctx, p.Args, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		replicas, ok := p.Args[ReplicasArg].(int)
+		if !ok {
+			return nil, fmt.Errorf("missing required argument: %s", ReplicasArg)
+		}
+		if replicas < 0 {
+			return nil, fmt.Errorf("%s must not be negative", ReplicasArg)
+		}
+
+		opts, err := subresourcePatchOptions(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		// The scale subresource is served as autoscaling/v1 Scale for every kind
+		scale := &unstructured.Unstructured{}
+		scale.SetGroupVersionKind(autoscalingv1.SchemeGroupVersion.WithKind("Scale"))
+		opts.SubResourceBody = scale
+
+		patch := client.RawPatch(types.MergePatchType, []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas)))
+		if err := r.runtimeClient.SubResource(common.ScaleSubresource).Patch(ctx, obj, patch, opts); err != nil {
+			log.Error().Err(err).Int(ReplicasArg, replicas).Msg("Failed to scale object")
+			return nil, err
+		}
+
+		result := ScaleResult{}
+		result.Replicas, _, _ = unstructured.NestedInt64(scale.Object, "spec", "replicas")
+		result.CurrentReplicas, _, _ = unstructured.NestedInt64(scale.Object, "status", "replicas")
+		result.Selector, _, _ = unstructured.NestedString(scale.Object, "status", "selector")
+
+		return result, nil
+	}))
+}
+
+// EvictItem returns a resolver that evicts an object via its eviction subresource, which unlike a deletion respects the
+// PodDisruptionBudgets of the object
+func (r *Service) EvictItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(EVICT_ITEM, gvk, r.audited("evict", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, EVICT_ITEM, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "evict").Str("kind", gvk.Kind).Logger()
+
+		obj, err := subresourceObject(ctx, p.Args, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		dryRun, err := getDryRunArg(ctx, p.Args)
+		if err != nil {
+			return nil, err
+		}
+
+		eviction := &unstructured.Unstructured{}
+		eviction.SetGroupVersionKind(policyv1.SchemeGroupVersion.WithKind("Eviction"))
+		eviction.SetName(obj.GetName())
+		eviction.SetNamespace(obj.GetNamespace())
+		if gracePeriodSeconds, ok := p.Args[GracePeriodSecondsArg].(int); ok {
+			if gracePeriodSeconds < 0 {
+				return nil, fmt.Errorf("%s must not be negative", GracePeriodSecondsArg)
+			}
+			if err := unstructured.SetNestedField(eviction.Object, int64(gracePeriodSeconds), "deleteOptions", "gracePeriodSeconds"); err != nil {
+				return nil, err
+			}
+		}
+
+		// SubResourceCreateOptions doesn't apply its own dry-run setting, so the option is passed on its own
+		var opts []client.SubResourceCreateOption
+		if dryRun {
+			opts = append(opts, client.DryRunAll)
+		}
+
+		// Evictions blocked by a PodDisruptionBudget fail with 429 Too Many Requests and can be retried later
+		if err := r.runtimeClient.SubResource(common.EvictionSubresource).Create(ctx, obj, eviction, opts...); err != nil {
+			log.Error().Err(err).Str("name", obj.GetName()).Msg("Failed to evict object")
+			return nil, err
+		}
+
+		return true, nil
+	}))
+}
+
+// CreateItemToken returns a resolver that issues a token of a service account via its token subresource
+func (r *Service) CreateItemToken(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(CREATE_ITEM_TOKEN, gvk, r.audited("createToken", gvk, scope, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, CREATE_ITEM_TOKEN, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "createToken").Str("kind", gvk.Kind).Logger()
+
+		// TokenRequests are not persisted, a dry run would still issue a valid token
+		if IsValidationOnly(ctx) {
+			return nil, ErrValidationOnly
+		}
+
+		obj, err := subresourceObject(ctx, p.Args, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		tokenRequest := &unstructured.Unstructured{}
+		tokenRequest.SetGroupVersionKind(authenticationv1.SchemeGroupVersion.WithKind("TokenRequest"))
+		// The API server defaults the expiration and the audiences to the ones of the API server
+		if expirationSeconds, ok := p.Args[ExpirationSecondsArg].(int); ok {
+			if err := unstructured.SetNestedField(tokenRequest.Object, int64(expirationSeconds), "spec", "expirationSeconds"); err != nil {
+				return nil, err
+			}
+		}
+		if audiences, ok := p.Args[AudiencesArg].([]interface{}); ok && len(audiences) > 0 {
+			if err := unstructured.SetNestedSlice(tokenRequest.Object, audiences, "spec", "audiences"); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := r.runtimeClient.SubResource(common.TokenSubresource).Create(ctx, obj, tokenRequest); err != nil {
+			log.Error().Err(err).Str("name", obj.GetName()).Msg("Failed to create token")
+			return nil, err
+		}
+
+		result := TokenResult{}
+		result.Token, _, _ = unstructured.NestedString(tokenRequest.Object, "status", "token")
+		result.ExpirationTimestamp, _, _ = unstructured.NestedString(tokenRequest.Object, "status", "expirationTimestamp")
+
+		return result, nil
+	}))
+}
+
+// ProxyItem returns a resolver that sends a GET request to the path of an object via its proxy subresource, e.g. to
+// an HTTP endpoint of a service or pod, and returns the response body. The request is sent with the credentials of
+// the caller through the API server.
+func (r *Service) ProxyItem(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	return r.instrument(PROXY_ITEM, gvk, func(p graphql.ResolveParams) (interface{}, error) {
+		ctx, span := otel.Tracer("").Start(p.Context, PROXY_ITEM, trace.WithAttributes(attribute.String("kind", gvk.Kind)))
+		defer span.End()
+
+		if r.discoveryClient == nil {
+			return nil, ErrProxyUnavailable
+		}
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+
+		log := r.log.With().Str("operation", "proxy").Str("kind", gvk.Kind).Logger()
+
+		obj, err := subresourceObject(ctx, p.Args, gvk, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		mapping, err := r.runtimeClient.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to map kind %s: %w", gvk.Kind, err)
+		}
+
+		proxyPath, _ := p.Args[PathArg].(string)
+		port, _ := p.Args[PortArg].(string)
+		requestPath, err := proxyRequestPath(gvk.GroupVersion(), mapping.Resource.Resource, obj, port, proxyPath)
+		if err != nil {
+			return nil, err
+		}
+		if workspace, ok := kontext.ClusterFrom(ctx); ok && !workspace.Empty() {
+			// kcp serves the workspaces below /clusters/<workspace> of its host
+			requestPath = workspace.Path().RequestPath() + requestPath
+		}
+
+		body, err := r.discoveryClient.Get().AbsPath(requestPath).Do(ctx).Raw()
+		if err != nil {
+			log.Error().Err(err).Str("name", obj.GetName()).Msg("Failed to proxy request")
+			return nil, err
+		}
+
+		return string(body), nil
+	})
+}
+
+// proxyRequestPath returns the path of a request to proxyPath via the proxy subresource of obj, e.g.
+// /api/v1/namespaces/default/services/web:http/proxy/healthz
+func proxyRequestPath(gv schema.GroupVersion, resource string, obj *unstructured.Unstructured, port, proxyPath string) (string, error) {
+	if slices.Contains(strings.Split(proxyPath, "/"), "..") {
+		return "", ErrInvalidProxyPath
+	}
+	if strings.ContainsAny(port, "/:?#") {
+		return "", ErrInvalidProxyPort
+	}
+
+	segments := []string{"/apis", gv.Group, gv.Version}
+	if gv.Group == "" {
+		segments = []string{"/api", gv.Version}
+	}
+	if obj.GetNamespace() != "" {
+		segments = append(segments, "namespaces", obj.GetNamespace())
+	}
+	name := obj.GetName()
+	if port != "" {
+		name += ":" + port
+	}
+	segments = append(segments, resource, name, common.ProxySubresource, strings.TrimPrefix(proxyPath, "/"))
+
+	return path.Join(segments...), nil
+}
+
+// subresourceObject returns the object addressed by the name and namespace arguments
+func subresourceObject(ctx context.Context, args map[string]interface{}, gvk schema.GroupVersionKind, scope v1.ResourceScope) (*unstructured.Unstructured, error) {
+	name, err := getStringArg(args, NameArg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(name)
+
+	namespace, err := getNamespaceArg(ctx, args, scope, true)
+	if err != nil {
+		return nil, err
+	}
+	if isResourceNamespaceScoped(scope) {
+		obj.SetNamespace(namespace)
+	}
+
+	return obj, nil
+}
+
+func subresourcePatchOptions(ctx context.Context, args map[string]interface{}) (*client.SubResourcePatchOptions, error) {
+	dryRun, err := getDryRunArg(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &client.SubResourcePatchOptions{}
+	if dryRun {
+		opts.DryRun = []string{"All"}
+	}
+
+	return opts, nil
+}
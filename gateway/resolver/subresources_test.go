@@ -0,0 +1,311 @@
+package resolver_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestUpdateItemStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		object         map[string]any
+		dryRun         bool
+		expectedPatch  map[string]any
+		expectedDryRun bool
+		expectedErr    string
+	}{
+		{
+			name: "sends_only_status",
+			object: map[string]any{
+				"spec":   map[string]any{"replicas": 3},
+				"status": map[string]any{"availableReplicas": 2},
+			},
+			dryRun:         true,
+			expectedPatch:  map[string]any{"status": map[string]any{"availableReplicas": float64(2)}},
+			expectedDryRun: true,
+		},
+		{
+			name:        "missing_status_ERROR",
+			object:      map[string]any{"spec": map[string]any{"replicas": 3}},
+			expectedErr: "object status is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subResource string
+			var patchType types.PatchType
+			var patchOpts client.SubResourcePatchOptions
+			var patched map[string]any
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(ctx context.Context, clt client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+						subResource = subResourceName
+						patchType = patch.Type()
+						patchOpts.ApplyOptions(opts)
+						assert.Equal(t, "nginx", obj.GetName())
+						assert.Equal(t, "default", obj.GetNamespace())
+
+						data, err := patch.Data(obj)
+						require.NoError(t, err)
+						return json.Unmarshal(data, &patched)
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			result, err := r.UpdateItemStatus(appsv1.SchemeGroupVersion.WithKind("Deployment"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args: map[string]any{
+					resolver.NameArg:      "nginx",
+					resolver.NamespaceArg: "default",
+					resolver.ObjectArg:    tt.object,
+					resolver.DryRunArg:    tt.dryRun,
+				},
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Empty(t, subResource, "nothing is patched")
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, result)
+
+			assert.Equal(t, "status", subResource)
+			assert.Equal(t, types.MergePatchType, patchType)
+			assert.Equal(t, tt.expectedPatch, patched)
+			assert.Equal(t, tt.expectedDryRun, len(patchOpts.DryRun) > 0)
+		})
+	}
+}
+
+func TestScaleItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		replicas    any
+		expected    resolver.ScaleResult
+		expectedErr string
+	}{
+		{
+			name:     "scales",
+			replicas: 3,
+			expected: resolver.ScaleResult{Replicas: 3, CurrentReplicas: 1, Selector: "app=nginx"},
+		},
+		{
+			name:        "negative_replicas_ERROR",
+			replicas:    -1,
+			expectedErr: "replicas must not be negative",
+		},
+		{
+			name:        "missing_replicas_ERROR",
+			expectedErr: "missing required argument: replicas",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subResource string
+			var patched map[string]any
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourcePatch: func(ctx context.Context, clt client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+						subResource = subResourceName
+
+						patchOpts := &client.SubResourcePatchOptions{}
+						patchOpts.ApplyOptions(opts)
+						scale, ok := patchOpts.SubResourceBody.(*unstructured.Unstructured)
+						require.True(t, ok, "the scale is decoded into the subresource body")
+						assert.Equal(t, "Scale", scale.GetKind())
+
+						data, err := patch.Data(scale)
+						require.NoError(t, err)
+						require.NoError(t, json.Unmarshal(data, &patched))
+
+						scale.Object["status"] = map[string]any{"replicas": int64(1), "selector": "app=nginx"}
+						return unstructured.SetNestedField(scale.Object, int64(3), "spec", "replicas")
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			args := map[string]any{
+				resolver.NameArg:      "nginx",
+				resolver.NamespaceArg: "default",
+			}
+			if tt.replicas != nil {
+				args[resolver.ReplicasArg] = tt.replicas
+			}
+
+			result, err := r.ScaleItem(appsv1.SchemeGroupVersion.WithKind("Deployment"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    args,
+			})
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				assert.Empty(t, subResource, "nothing is patched")
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, "scale", subResource)
+			assert.Equal(t, map[string]any{"spec": map[string]any{"replicas": float64(3)}}, patched)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestEvictItem(t *testing.T) {
+	var subResource string
+	var eviction *unstructured.Unstructured
+	var createOpts client.SubResourceCreateOptions
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, clt client.Client, subResourceName string, obj client.Object, subResourceObj client.Object, opts ...client.SubResourceCreateOption) error {
+				subResource = subResourceName
+				eviction = subResourceObj.(*unstructured.Unstructured)
+				createOpts.ApplyOptions(opts)
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	result, err := r.EvictItem(corev1.SchemeGroupVersion.WithKind("Pod"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			resolver.NameArg:               "web-7d9f8b6c5-x2x7k",
+			resolver.NamespaceArg:          "default",
+			resolver.GracePeriodSecondsArg: 30,
+			resolver.DryRunArg:             true,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+
+	assert.Equal(t, "eviction", subResource)
+	assert.Equal(t, "policy/v1", eviction.GetAPIVersion())
+	assert.Equal(t, "Eviction", eviction.GetKind())
+	assert.Equal(t, "web-7d9f8b6c5-x2x7k", eviction.GetName())
+	assert.Equal(t, "default", eviction.GetNamespace())
+	gracePeriodSeconds, _, _ := unstructured.NestedInt64(eviction.Object, "deleteOptions", "gracePeriodSeconds")
+	assert.Equal(t, int64(30), gracePeriodSeconds)
+	assert.Equal(t, []string{"All"}, createOpts.DryRun)
+}
+
+func TestCreateItemToken(t *testing.T) {
+	var subResource string
+	var spec map[string]any
+	runtimeClient := fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceCreate: func(ctx context.Context, clt client.Client, subResourceName string, obj client.Object, subResourceObj client.Object, opts ...client.SubResourceCreateOption) error {
+				subResource = subResourceName
+				tokenRequest := subResourceObj.(*unstructured.Unstructured)
+				spec, _, _ = unstructured.NestedMap(tokenRequest.Object, "spec")
+				tokenRequest.Object["status"] = map[string]any{"token": "issued-token", "expirationTimestamp": "2025-01-01T01:00:00Z"}
+				return nil
+			},
+		}).
+		Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+	result, err := r.CreateItemToken(corev1.SchemeGroupVersion.WithKind("ServiceAccount"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args: map[string]any{
+			resolver.NameArg:              "reader",
+			resolver.NamespaceArg:         "default",
+			resolver.ExpirationSecondsArg: 3600,
+			resolver.AudiencesArg:         []any{"vault"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "token", subResource)
+	assert.Equal(t, map[string]any{"expirationSeconds": int64(3600), "audiences": []any{"vault"}}, spec)
+	assert.Equal(t, resolver.TokenResult{Token: "issued-token", ExpirationTimestamp: "2025-01-01T01:00:00Z"}, result)
+}
+
+func TestProxyRequestPath(t *testing.T) {
+	tests := []struct {
+		name         string
+		gv           schema.GroupVersion
+		resource     string
+		namespace    string
+		port         string
+		proxyPath    string
+		expectedPath string
+		expectedErr  error
+	}{
+		{
+			name:         "service_port",
+			gv:           corev1.SchemeGroupVersion,
+			resource:     "services",
+			namespace:    "default",
+			port:         "http",
+			proxyPath:    "/healthz",
+			expectedPath: "/api/v1/namespaces/default/services/web:http/proxy/healthz",
+		},
+		{
+			name:         "cluster_scoped",
+			gv:           corev1.SchemeGroupVersion,
+			resource:     "nodes",
+			proxyPath:    "metrics",
+			expectedPath: "/api/v1/nodes/web/proxy/metrics",
+		},
+		{
+			name:         "group",
+			gv:           schema.GroupVersion{Group: "example.com", Version: "v1"},
+			resource:     "endpoints",
+			namespace:    "default",
+			expectedPath: "/apis/example.com/v1/namespaces/default/endpoints/web/proxy",
+		},
+		{
+			name:        "parent_path_ERROR",
+			gv:          corev1.SchemeGroupVersion,
+			resource:    "services",
+			namespace:   "default",
+			proxyPath:   "../../secrets/token",
+			expectedErr: resolver.ErrInvalidProxyPath,
+		},
+		{
+			name:        "port_with_path_ERROR",
+			gv:          corev1.SchemeGroupVersion,
+			resource:    "services",
+			namespace:   "default",
+			port:        "80/../secrets",
+			expectedErr: resolver.ErrInvalidProxyPort,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetName("web")
+			obj.SetNamespace(tt.namespace)
+
+			requestPath, err := resolver.ProxyRequestPath(tt.gv, tt.resource, obj, tt.port, tt.proxyPath)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedPath, requestPath)
+		})
+	}
+}
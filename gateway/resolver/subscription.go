@@ -3,8 +3,8 @@ package resolver
 import (
 	"fmt"
 	"reflect"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/openmfp/golang-commons/sentry"
 	"github.com/pkg/errors"
@@ -86,18 +86,15 @@ func (r *Service) runWatch(
 
 	var opts []client.ListOption
 
-	var namespace string
-	if isResourceNamespaceScoped(scope) {
-		isNamespaceRequired := singleItem
-		namespace, err = getStringArg(p.Args, NamespaceArg, isNamespaceRequired)
-		if err != nil {
-			r.log.Error().Err(err).Msg("Failed to get namespace argument")
-			resultChannel <- errors.Wrap(err, "failed to get namespace argument")
-			return
-		}
-		if namespace != "" {
-			opts = append(opts, client.InNamespace(namespace))
-		}
+	isNamespaceRequired := singleItem
+	namespace, err := getNamespaceArg(ctx, p.Args, scope, isNamespaceRequired)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to get namespace argument")
+		resultChannel <- errors.Wrap(err, "failed to get namespace argument")
+		return
+	}
+	if namespace != "" {
+		opts = append(opts, client.InNamespace(namespace))
 	}
 
 	if labelSelector != "" {
@@ -127,8 +124,31 @@ func (r *Service) runWatch(
 		resultChannel <- errors.Wrap(err, "failed to get sortBy argument")
 		return
 	}
+	sortDirection, err := getSortDirection(p.Args)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Failed to get sortDirection argument")
+		resultChannel <- errors.Wrap(err, "failed to get sortDirection argument")
+		return
+	}
+	batching, err := eventBatchingFromArgs(p.Args)
+	if err != nil {
+		r.log.Error().Err(err).Msg("Invalid event batching arguments")
+		resultChannel <- err
+		return
+	}
+
+	// A reconnecting client continues from the last update it received, the objects it already knows are listed
+	// without sending them again
+	previousObjects := make(map[string]*unstructured.Unstructured)
+	var resourceVersion string
+	if lastEventID := subscriptionCursorFromContext(ctx).resumeFrom(); lastEventID != "" {
+		if objects, ok := r.resumeState(ctx, list, opts, lastEventID); ok {
+			previousObjects = objects
+			resourceVersion = lastEventID
+		}
+	}
 
-	if !singleItem {
+	if !singleItem && resourceVersion == "" {
 		select {
 		case <-ctx.Done():
 			return
@@ -136,24 +156,121 @@ func (r *Service) runWatch(
 		}
 	}
 
-	watcher, err := r.runtimeClient.Watch(ctx, list, opts...)
+	watcher, err := r.startWatch(ctx, list, opts, resourceVersion)
+	if err != nil && resourceVersion != "" && isExpiredWatch(err) {
+		r.log.Debug().Err(err).Str("resourceVersion", resourceVersion).Msg("Resumed watch expired, starting from the current state")
+		previousObjects = make(map[string]*unstructured.Unstructured)
+		resourceVersion = ""
+		if !singleItem {
+			select {
+			case <-ctx.Done():
+				return
+			case resultChannel <- []map[string]interface{}{}:
+			}
+		}
+		watcher, err = r.startWatch(ctx, list, opts, "")
+	}
 	if err != nil {
 		r.log.Error().Err(err).Str("gvk", gvk.String()).Msg("Failed to start watch")
 
 		sentry.CaptureError(err, sentry.Tags{"namespace": namespace}, sentry.Extras{"gvk": gvk.String()})
 
-		resultChannel <- errors.Wrap(err, "failed to start watch")
+		resultChannel <- withStatus(errors.Wrap(err, "failed to start watch"))
 		return
 	}
-	defer watcher.Stop()
+	defer func() {
+		watcher.Stop()
+	}()
+
+	// Updates are buffered, so that a slow client doesn't block the watch. resultChannel is closed only after the
+	// pending updates were delivered.
+	updates := newSubscriptionBuffer(r.subscriptionOrdering, r.subscriptionBufferSize)
+	delivered := make(chan struct{})
+	go func() {
+		defer close(delivered)
+		updates.deliver(ctx, resultChannel)
+	}()
+	defer func() {
+		updates.close()
+		<-delivered
+	}()
+
+	// emit pushes the current state of the subscribed object or list, false ends the subscription
+	emit := func(resourceVersion string) bool {
+		if singleItem {
+			var singleObj *unstructured.Unstructured
+			if name != "" {
+				singleObj = previousObjects[namespace+"/"+name]
+			}
+
+			var data interface{}
+			if singleObj != nil { // object can be nil in case it is deleted
+				// The previous objects are compared with the next events, so only a copy is redacted
+				redacted := singleObj.DeepCopy()
+				r.redact(ctx, gvk, redacted.Object)
+				data = redacted.Object
+			}
+
+			updates.push(subscriptionEvent{resourceVersion: resourceVersion, data: data})
+			return true
+		}
+
+		items := make([]unstructured.Unstructured, 0, len(previousObjects))
+		for _, item := range previousObjects {
+			items = append(items, *item.DeepCopy())
+		}
+		r.redactItems(ctx, gvk, items)
+
+		if err := validateSortBy(items, sortBy); err != nil {
+			r.log.Error().Err(err).Str(SortByArg, sortBy).Msg("Invalid sortBy field path")
+			updates.push(errors.Wrap(err, "invalid sortBy field path"))
+			return false
+		}
+
+		sortItems(items, sortBy, sortDirection)
+
+		sortedItems := make([]map[string]any, len(items))
+		for i, item := range items {
+			sortedItems[i] = item.Object
+		}
+
+		updates.push(subscriptionEvent{resourceVersion: resourceVersion, data: sortedItems})
+		return true
+	}
+
+	// Updates of rapid changes are coalesced if the subscription asks for it, the batch is emitted with the state after
+	// its last change
+	coalescer := newEventCoalescer(batching)
+	var pendingResourceVersion string
 
-	previousObjects := make(map[string]*unstructured.Unstructured)
 	for {
 		select {
 		case event, ok := <-watcher.ResultChan():
 			if !ok {
+				if coalescer.pending > 0 {
+					coalescer.flush()
+					emit(pendingResourceVersion)
+				}
 				return
 			}
+			if resourceVersion != "" && isExpiredWatchEvent(event) {
+				// The resource version of the resumed watch is older than the history of the API server
+				r.log.Debug().Str("resourceVersion", resourceVersion).Msg("Resumed watch expired, starting from the current state")
+				watcher.Stop()
+				previousObjects = make(map[string]*unstructured.Unstructured)
+				resourceVersion = ""
+				coalescer.flush()
+				if !singleItem {
+					updates.push([]map[string]interface{}{})
+				}
+				watcher, err = r.startWatch(ctx, list, opts, "")
+				if err != nil {
+					r.log.Error().Err(err).Str("gvk", gvk.String()).Msg("Failed to start watch")
+					updates.push(withStatus(errors.Wrap(err, "failed to start watch")))
+					return
+				}
+				continue
+			}
 			obj, ok := event.Object.(*unstructured.Unstructured)
 			if !ok {
 				err = ErrFailedToCastEventObjectToUnstructured
@@ -161,7 +278,7 @@ func (r *Service) runWatch(
 
 				sentry.CaptureError(err, sentry.Tags{"namespace": namespace})
 
-				resultChannel <- errors.Wrap(err, "failed to cast event object to unstructured")
+				updates.push(errors.Wrap(err, "failed to cast event object to unstructured"))
 				return
 			}
 			key := obj.GetNamespace() + "/" + obj.GetName()
@@ -183,7 +300,7 @@ func (r *Service) runWatch(
 
 						sentry.CaptureError(err, sentry.Tags{"namespace": namespace})
 
-						resultChannel <- errors.Wrap(err, "failed to determine field changed")
+						updates.push(errors.Wrap(err, "failed to determine field changed"))
 						return
 					}
 					sendUpdate = changed
@@ -194,51 +311,23 @@ func (r *Service) runWatch(
 				sendUpdate = true
 			}
 
-			if sendUpdate {
-				if singleItem {
-					var singleObj *unstructured.Unstructured
-					if name != "" {
-						singleObj = previousObjects[namespace+"/"+name]
-					}
-
-					var data interface{}
-					if singleObj != nil { // object can be nil in case it is deleted
-						data = singleObj.Object
-					}
-
-					select {
-					case <-ctx.Done():
-						return
-					case resultChannel <- data:
-					}
-				} else {
-					items := make([]unstructured.Unstructured, 0, len(previousObjects))
-					for _, item := range previousObjects {
-						items = append(items, *item.DeepCopy())
-					}
-
-					err = validateSortBy(items, sortBy)
-					if err != nil {
-						r.log.Error().Err(err).Str(SortByArg, sortBy).Msg("Invalid sortBy field path")
-						resultChannel <- errors.Wrap(err, "invalid sortBy field path")
-						return
-					}
-
-					sort.Slice(items, func(i, j int) bool {
-						return compareUnstructured(items[i], items[j], sortBy) < 0
-					})
-
-					sortedItems := make([]map[string]any, len(items))
-					for i, item := range items {
-						sortedItems[i] = item.Object
-					}
-
-					select {
-					case <-ctx.Done():
-						return
-					case resultChannel <- sortedItems:
-					}
+			if !sendUpdate {
+				continue
+			}
+			if !batching.enabled() {
+				if !emit(obj.GetResourceVersion()) {
+					return
 				}
+				continue
+			}
+			pendingResourceVersion = obj.GetResourceVersion()
+			if coalescer.add(time.Now()) && !emit(pendingResourceVersion) {
+				return
+			}
+		case <-coalescer.due():
+			coalescer.flush()
+			if !emit(pendingResourceVersion) {
+				return
 			}
 		case <-ctx.Done():
 			return
@@ -349,6 +438,15 @@ func CreateSubscriptionResolver(isSingle bool) graphql.FieldResolveFn {
 	return func(p graphql.ResolveParams) (interface{}, error) {
 		source := p.Source
 
+		// The resource version is handed to the transport, which sends it as the ID of the update
+		cursor := subscriptionCursorFromContext(p.Context)
+		if event, ok := source.(subscriptionEvent); ok {
+			cursor.record(event.resourceVersion)
+			source = event.data
+		} else {
+			cursor.record("")
+		}
+
 		if err, ok := source.(error); ok {
 			return nil, err
 		}
@@ -0,0 +1,120 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	DebounceMsArg    = "debounceMs"
+	BatchWindowMsArg = "batchWindowMs"
+	MaxBatchSizeArg  = "maxBatchSize"
+
+	// MaxBatchingDelayMs is the longest debounce and batch window a subscription may ask for
+	MaxBatchingDelayMs = 60000
+)
+
+var ErrInvalidEventBatching = errors.New("invalid event batching")
+
+// eventBatching controls how the changes of a subscription are coalesced into updates. Every update carries the
+// complete state, so a batch is emitted as a single update with the state after its last change.
+type eventBatching struct {
+	debounce time.Duration
+	window   time.Duration
+	maxSize  int
+}
+
+func eventBatchingFromArgs(args map[string]interface{}) (eventBatching, error) {
+	var batching eventBatching
+
+	for _, arg := range []string{DebounceMsArg, BatchWindowMsArg} {
+		value, ok := args[arg].(int)
+		if !ok {
+			continue
+		}
+		if value < 0 || value > MaxBatchingDelayMs {
+			return eventBatching{}, fmt.Errorf("%w: %s must be between 0 and %d", ErrInvalidEventBatching, arg, MaxBatchingDelayMs)
+		}
+		if arg == DebounceMsArg {
+			batching.debounce = time.Duration(value) * time.Millisecond
+		} else {
+			batching.window = time.Duration(value) * time.Millisecond
+		}
+	}
+
+	if value, ok := args[MaxBatchSizeArg].(int); ok {
+		if value < 1 {
+			return eventBatching{}, fmt.Errorf("%w: %s must be positive", ErrInvalidEventBatching, MaxBatchSizeArg)
+		}
+		if batching.debounce == 0 && batching.window == 0 {
+			return eventBatching{}, fmt.Errorf("%w: %s requires %s or %s", ErrInvalidEventBatching, MaxBatchSizeArg, DebounceMsArg, BatchWindowMsArg)
+		}
+		batching.maxSize = value
+	}
+
+	return batching, nil
+}
+
+func (b eventBatching) enabled() bool {
+	return b.debounce > 0 || b.window > 0
+}
+
+// eventCoalescer tracks the pending changes of a subscription and when they are due
+type eventCoalescer struct {
+	eventBatching
+	pending int
+	first   time.Time
+	timer   *time.Timer
+}
+
+func newEventCoalescer(batching eventBatching) *eventCoalescer {
+	return &eventCoalescer{eventBatching: batching}
+}
+
+// add registers a change at now, true means the batch is full and has to be emitted right away
+func (c *eventCoalescer) add(now time.Time) bool {
+	if c.pending == 0 {
+		c.first = now
+	}
+	c.pending++
+
+	if c.maxSize > 0 && c.pending >= c.maxSize {
+		c.flush()
+		return true
+	}
+
+	// The debounce is restarted by every change, the window bounds how long a change waits under constant churn
+	var deadline time.Time
+	if c.debounce > 0 {
+		deadline = now.Add(c.debounce)
+	}
+	if c.window > 0 {
+		if windowEnd := c.first.Add(c.window); deadline.IsZero() || windowEnd.Before(deadline) {
+			deadline = windowEnd
+		}
+	}
+
+	if c.timer != nil {
+		c.timer.Stop()
+	}
+	c.timer = time.NewTimer(deadline.Sub(now))
+	return false
+}
+
+// due fires when the pending changes have to be emitted, it never fires without pending changes
+func (c *eventCoalescer) due() <-chan time.Time {
+	if c.timer == nil {
+		return nil
+	}
+	return c.timer.C
+}
+
+// flush forgets the pending changes, the caller emits them
+func (c *eventCoalescer) flush() {
+	c.pending = 0
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+}
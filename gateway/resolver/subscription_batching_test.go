@@ -0,0 +1,135 @@
+package resolver_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestSubscribeItems_Batching(t *testing.T) {
+	type update struct {
+		id    string
+		names []string
+	}
+
+	tests := []struct {
+		name            string
+		args            map[string]any
+		expectedUpdates []update
+		expectedErr     error
+	}{
+		{
+			name:            "unbatched",
+			args:            map[string]any{},
+			expectedUpdates: []update{{names: []string{}}, {id: "1", names: []string{"a"}}, {id: "2", names: []string{"a", "b"}}},
+		},
+		{
+			name:            "batch_window",
+			args:            map[string]any{resolver.BatchWindowMsArg: 50},
+			expectedUpdates: []update{{names: []string{}}, {id: "5", names: []string{"a", "b", "c", "d", "e"}}},
+		},
+		{
+			name:            "debounce",
+			args:            map[string]any{resolver.DebounceMsArg: 50},
+			expectedUpdates: []update{{names: []string{}}, {id: "5", names: []string{"a", "b", "c", "d", "e"}}},
+		},
+		{
+			name: "max_batch_size",
+			args: map[string]any{resolver.BatchWindowMsArg: resolver.MaxBatchingDelayMs, resolver.MaxBatchSizeArg: 2},
+			expectedUpdates: []update{
+				{names: []string{}},
+				{id: "2", names: []string{"a", "b"}},
+				{id: "4", names: []string{"a", "b", "c", "d"}},
+			},
+		},
+		{
+			name:        "max_batch_size_without_delay_ERROR",
+			args:        map[string]any{resolver.MaxBatchSizeArg: 2},
+			expectedErr: resolver.ErrInvalidEventBatching,
+		},
+		{
+			name:        "negative_debounce_ERROR",
+			args:        map[string]any{resolver.DebounceMsArg: -1},
+			expectedErr: resolver.ErrInvalidEventBatching,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					Watch: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+						watcher := watch.NewFakeWithChanSize(10, false)
+						for i, name := range []string{"a", "b", "c", "d", "e"} {
+							obj := &unstructured.Unstructured{}
+							obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+							obj.SetNamespace("default")
+							obj.SetName(name)
+							obj.SetResourceVersion(fmt.Sprint(i + 1))
+							watcher.Add(obj)
+						}
+						return watcher, nil
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+			cursor := resolver.NewSubscriptionCursor("")
+			ctx = resolver.WithSubscriptionCursor(ctx, cursor)
+
+			args := map[string]any{resolver.NamespaceArg: "default", resolver.SortByArg: "metadata.name"}
+			for key, value := range tt.args {
+				args[key] = value
+			}
+			result, err := r.SubscribeItems(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    args,
+			})
+			require.NoError(t, err)
+			updates := result.(chan interface{})
+
+			resolve := resolver.CreateSubscriptionResolver(false)
+			if tt.expectedErr != nil {
+				_, err := resolve(graphql.ResolveParams{Context: ctx, Source: <-updates})
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			for _, expected := range tt.expectedUpdates {
+				var source interface{}
+				select {
+				case source = <-updates:
+				case <-ctx.Done():
+					t.Fatal("timed out waiting for subscription update")
+				}
+
+				data, err := resolve(graphql.ResolveParams{Context: ctx, Source: source})
+				require.NoError(t, err)
+
+				names := []string{}
+				for _, item := range data.([]map[string]any) {
+					names = append(names, item["metadata"].(map[string]any)["name"].(string))
+				}
+				assert.Equal(t, expected.names, names)
+				assert.Equal(t, expected.id, cursor.Next())
+			}
+		})
+	}
+}
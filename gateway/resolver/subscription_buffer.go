@@ -0,0 +1,167 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SubscriptionOrdering controls which updates of a subscription are delivered while the client consumes them slower than they arrive
+type SubscriptionOrdering string
+
+const (
+	// SubscriptionOrderingOrdered delivers every update in the order of the watch events.
+	// If the buffer is full, the oldest pending updates are dropped and the client is told so before the next update.
+	SubscriptionOrderingOrdered SubscriptionOrdering = "ordered"
+	// SubscriptionOrderingLatest only keeps the latest pending update, as every update contains the complete state
+	// of the subscribed objects. Superseded updates are skipped without notification.
+	SubscriptionOrderingLatest SubscriptionOrdering = "latest"
+)
+
+// DefaultSubscriptionBufferSize is the amount of pending updates kept per subscription if none is configured
+const DefaultSubscriptionBufferSize = 100
+
+// EventsDroppedCode is the code in the extensions of the error sent after updates of a subscription were dropped
+const EventsDroppedCode = "EVENTS_DROPPED"
+
+var ErrUnknownSubscriptionOrdering = errors.New("unknown subscription ordering")
+
+// ParseSubscriptionOrdering validates the configured subscription ordering, empty means ordered
+func ParseSubscriptionOrdering(mode string) (SubscriptionOrdering, error) {
+	switch SubscriptionOrdering(mode) {
+	case "", SubscriptionOrderingOrdered:
+		return SubscriptionOrderingOrdered, nil
+	case SubscriptionOrderingLatest:
+		return SubscriptionOrderingLatest, nil
+	default:
+		return SubscriptionOrderingOrdered, fmt.Errorf("%w: %s", ErrUnknownSubscriptionOrdering, mode)
+	}
+}
+
+// WithSubscriptionBuffer configures how many updates are kept per subscription for slow clients and which of them are delivered
+func (r *Service) WithSubscriptionBuffer(ordering SubscriptionOrdering, size int) *Service {
+	r.subscriptionOrdering = ordering
+	r.subscriptionBufferSize = size
+	return r
+}
+
+// EventsDroppedError tells the client that updates of a subscription were dropped, so it has to refetch to catch up
+// on the changes it missed. The update following it contains the current state again.
+type EventsDroppedError struct {
+	Count int
+}
+
+func (e *EventsDroppedError) Error() string {
+	return fmt.Sprintf("%d subscription events were dropped because they were not consumed fast enough, refetch to catch up", e.Count)
+}
+
+// Extensions implements gqlerrors.ExtendedError, so that clients can recognize the error
+func (e *EventsDroppedError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code":    EventsDroppedCode,
+		"dropped": e.Count,
+	}
+}
+
+// subscriptionBuffer decouples the watch of a subscription from its client, so that a slow client doesn't block the watch.
+// Updates are delivered in the order they were pushed, which keeps the updates of every object in order.
+type subscriptionBuffer struct {
+	mu       sync.Mutex
+	ordering SubscriptionOrdering
+	size     int
+	updates  []interface{}
+	dropped  int
+	closed   bool
+	// ready is signaled whenever updates are pushed or the buffer is closed
+	ready chan struct{}
+}
+
+func newSubscriptionBuffer(ordering SubscriptionOrdering, size int) *subscriptionBuffer {
+	if size <= 0 {
+		size = DefaultSubscriptionBufferSize
+	}
+	return &subscriptionBuffer{
+		ordering: ordering,
+		size:     size,
+		ready:    make(chan struct{}, 1),
+	}
+}
+
+// push queues an update without blocking. Errors end the subscription and are pushed last, so they are never dropped.
+func (b *subscriptionBuffer) push(update interface{}) {
+	b.mu.Lock()
+	switch {
+	case b.ordering == SubscriptionOrderingLatest:
+		b.updates = b.updates[:0]
+	case len(b.updates) >= b.size:
+		b.updates = b.updates[1:]
+		b.dropped++
+	}
+	b.updates = append(b.updates, update)
+	b.mu.Unlock()
+
+	b.signal()
+}
+
+// close marks that no more updates are pushed, pending updates are still delivered
+func (b *subscriptionBuffer) close() {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+
+	b.signal()
+}
+
+func (b *subscriptionBuffer) signal() {
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the next pending update and the amount of updates dropped before it
+func (b *subscriptionBuffer) next() (update interface{}, dropped int, ok bool, closed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.updates) == 0 {
+		return nil, 0, false, b.closed
+	}
+
+	update, b.updates = b.updates[0], b.updates[1:]
+	dropped, b.dropped = b.dropped, 0
+	return update, dropped, true, b.closed
+}
+
+// deliver sends the pending updates to out until the buffer is closed and drained or ctx is done
+func (b *subscriptionBuffer) deliver(ctx context.Context, out chan<- interface{}) {
+	for {
+		update, dropped, ok, closed := b.next()
+		if !ok {
+			if closed {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.ready:
+			}
+			continue
+		}
+
+		if dropped > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- &EventsDroppedError{Count: dropped}:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case out <- update:
+		}
+	}
+}
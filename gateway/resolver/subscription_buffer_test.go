@@ -0,0 +1,137 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptionBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		ordering SubscriptionOrdering
+		size     int
+		pushed   []interface{}
+		want     []interface{}
+	}{
+		{
+			name:     "ordered_delivers_every_update_in_order",
+			ordering: SubscriptionOrderingOrdered,
+			size:     5,
+			pushed:   []interface{}{1, 2, 3},
+			want:     []interface{}{1, 2, 3},
+		},
+		{
+			name:     "ordered_drops_oldest_updates_and_notifies",
+			ordering: SubscriptionOrderingOrdered,
+			size:     3,
+			pushed:   []interface{}{1, 2, 3, 4, 5},
+			want:     []interface{}{&EventsDroppedError{Count: 2}, 3, 4, 5},
+		},
+		{
+			name:     "ordered_keeps_final_error",
+			ordering: SubscriptionOrderingOrdered,
+			size:     1,
+			pushed:   []interface{}{1, errors.New("watch failed")},
+			want:     []interface{}{&EventsDroppedError{Count: 1}, errors.New("watch failed")},
+		},
+		{
+			name:     "latest_skips_superseded_updates",
+			ordering: SubscriptionOrderingLatest,
+			size:     3,
+			pushed:   []interface{}{1, 2, 3, 4, 5},
+			want:     []interface{}{5},
+		},
+		{
+			name:     "default_size",
+			ordering: SubscriptionOrderingOrdered,
+			pushed:   []interface{}{1, 2},
+			want:     []interface{}{1, 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := newSubscriptionBuffer(tt.ordering, tt.size)
+			for _, update := range tt.pushed {
+				buffer.push(update)
+			}
+			buffer.close()
+
+			out := make(chan interface{}, len(tt.pushed)+1)
+			buffer.deliver(context.Background(), out)
+			close(out)
+
+			var got []interface{}
+			for update := range out {
+				got = append(got, update)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSubscriptionBuffer_DeliversWhilePushing(t *testing.T) {
+	buffer := newSubscriptionBuffer(SubscriptionOrderingOrdered, 10)
+	out := make(chan interface{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buffer.deliver(context.Background(), out)
+	}()
+
+	buffer.push(1)
+	require.Equal(t, 1, <-out)
+	buffer.push(2)
+	require.Equal(t, 2, <-out)
+
+	buffer.close()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deliver did not return after the buffer was closed")
+	}
+}
+
+func TestSubscriptionBuffer_StopsOnCancel(t *testing.T) {
+	buffer := newSubscriptionBuffer(SubscriptionOrderingOrdered, 10)
+	buffer.push(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nobody reads out, so deliver only returns because the context is done
+	buffer.deliver(ctx, make(chan interface{}))
+}
+
+func TestParseSubscriptionOrdering(t *testing.T) {
+	tests := []struct {
+		mode    string
+		want    SubscriptionOrdering
+		wantErr error
+	}{
+		{mode: "", want: SubscriptionOrderingOrdered},
+		{mode: "ordered", want: SubscriptionOrderingOrdered},
+		{mode: "latest", want: SubscriptionOrderingLatest},
+		{mode: "random", want: SubscriptionOrderingOrdered, wantErr: ErrUnknownSubscriptionOrdering},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			got, err := ParseSubscriptionOrdering(tt.mode)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEventsDroppedError_Extensions(t *testing.T) {
+	err := &EventsDroppedError{Count: 3}
+
+	assert.Equal(t, map[string]interface{}{"code": EventsDroppedCode, "dropped": 3}, err.Extensions())
+}
@@ -0,0 +1,236 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+var sharedWatches = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "graphql_gateway",
+	Name:      "subscription_shared_watches",
+	Help:      "Number of API server watches shared by subscriptions",
+})
+
+// WithWatchMultiplexing shares one API server watch between the subscriptions watching the same kind, namespace,
+// label selector and name with the same credentials, e.g. many dashboard tabs of a user showing the same pods
+func (r *Service) WithWatchMultiplexing(enabled bool) *Service {
+	if enabled {
+		r.watchMux = newWatchMultiplexer()
+	}
+	return r
+}
+
+// watchMultiplexer fans the events of shared API server watches out to their subscribers. Subscribers joining a watch
+// that is already running first get the current objects as Added events, like a new watch would send them. A watch
+// is stopped as soon as its last subscriber is gone.
+type watchMultiplexer struct {
+	mu      sync.Mutex
+	watches map[string]*sharedWatch
+}
+
+// sharedWatch is an API server watch and the objects it has seen, guarded by the mutex of the multiplexer
+type sharedWatch struct {
+	key         string
+	mux         *watchMultiplexer
+	upstream    watch.Interface
+	cancel      context.CancelFunc
+	objects     map[string]*unstructured.Unstructured
+	subscribers map[*watchSubscriber]struct{}
+}
+
+// watchSubscriber is the watch.Interface handed to a single subscription
+type watchSubscriber struct {
+	shared   *sharedWatch
+	result   chan watch.Event
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// subscriberBufferSize is the amount of events buffered per subscriber on top of the current objects
+const subscriberBufferSize = 100
+
+func newWatchMultiplexer() *watchMultiplexer {
+	return &watchMultiplexer{watches: make(map[string]*sharedWatch)}
+}
+
+// watchKey identifies the watches that can be shared. The credentials of the caller are part of the key, so that
+// events are only shared between subscriptions the API server authorized alike.
+func watchKey(ctx context.Context, list *unstructured.UnstructuredList, opts []client.ListOption) string {
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	labelSelector, fieldSelector := "", ""
+	if listOpts.LabelSelector != nil {
+		labelSelector = listOpts.LabelSelector.String()
+	}
+	if listOpts.FieldSelector != nil {
+		fieldSelector = listOpts.FieldSelector.String()
+	}
+
+	token, _ := ctx.Value(roundtripper.TokenKey{}).(string)
+	tokenHash := sha256.Sum256([]byte(token))
+	boundNamespace, _ := ctx.Value(roundtripper.NamespaceKey{}).(string)
+
+	return strings.Join([]string{
+		list.GroupVersionKind().String(),
+		listOpts.Namespace,
+		labelSelector,
+		fieldSelector,
+		boundNamespace,
+		hex.EncodeToString(tokenHash[:]),
+	}, "|")
+}
+
+// watch joins the shared watch of the key, start opens the API server watch if there is none. The watch runs detached
+// from the context of the subscription that opened it, but keeps its values, e.g. the token of the caller.
+func (m *watchMultiplexer) watch(ctx context.Context, key string, start func(ctx context.Context) (watch.Interface, error)) (watch.Interface, error) {
+	m.mu.Lock()
+	if shared, ok := m.watches[key]; ok {
+		defer m.mu.Unlock()
+		return shared.subscribe(), nil
+	}
+	m.mu.Unlock()
+
+	watchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	upstream, err := start(watchCtx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Another subscription may have opened the same watch in the meantime
+	if shared, ok := m.watches[key]; ok {
+		upstream.Stop()
+		cancel()
+		return shared.subscribe(), nil
+	}
+
+	shared := &sharedWatch{
+		key:         key,
+		mux:         m,
+		upstream:    upstream,
+		cancel:      cancel,
+		objects:     make(map[string]*unstructured.Unstructured),
+		subscribers: make(map[*watchSubscriber]struct{}),
+	}
+	m.watches[key] = shared
+	sharedWatches.Inc()
+	go shared.run()
+
+	return shared.subscribe(), nil
+}
+
+// subscribe adds a subscriber that starts with the current objects, the mutex of the multiplexer must be held
+func (w *sharedWatch) subscribe() *watchSubscriber {
+	s := &watchSubscriber{
+		shared: w,
+		result: make(chan watch.Event, len(w.objects)+subscriberBufferSize),
+		done:   make(chan struct{}),
+	}
+	for _, obj := range w.objects {
+		s.result <- watch.Event{Type: watch.Added, Object: obj}
+	}
+	w.subscribers[s] = struct{}{}
+
+	return s
+}
+
+// run fans the events out until the API server ends the watch, the subscribers are then ended as well.
+// The event objects are shared between the subscribers and must not be modified.
+func (w *sharedWatch) run() {
+	for event := range w.upstream.ResultChan() {
+		w.mux.mu.Lock()
+		if obj, ok := event.Object.(*unstructured.Unstructured); ok {
+			key := obj.GetNamespace() + "/" + obj.GetName()
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				w.objects[key] = obj
+			case watch.Deleted:
+				delete(w.objects, key)
+			}
+		}
+		subscribers := make([]*watchSubscriber, 0, len(w.subscribers))
+		for s := range w.subscribers {
+			subscribers = append(subscribers, s)
+		}
+		w.mux.mu.Unlock()
+
+		for _, s := range subscribers {
+			s.send(event)
+		}
+	}
+
+	w.mux.mu.Lock()
+	w.remove()
+	subscribers := w.subscribers
+	w.subscribers = map[*watchSubscriber]struct{}{}
+	w.mux.mu.Unlock()
+
+	for s := range subscribers {
+		close(s.result)
+	}
+}
+
+// remove stops the watch and removes it from the multiplexer, the mutex of the multiplexer must be held
+func (w *sharedWatch) remove() {
+	if w.mux.watches[w.key] != w {
+		return
+	}
+	delete(w.mux.watches, w.key)
+	sharedWatches.Dec()
+	w.upstream.Stop()
+	w.cancel()
+}
+
+// send blocks until the subscriber takes the event or stops, subscriptions hand their events to their own buffer,
+// so a slow client doesn't hold up the other subscribers
+func (s *watchSubscriber) send(event watch.Event) {
+	select {
+	case s.result <- event:
+	case <-s.done:
+	}
+}
+
+func (s *watchSubscriber) ResultChan() <-chan watch.Event {
+	return s.result
+}
+
+// Stop leaves the shared watch, which is stopped if this was its last subscriber
+func (s *watchSubscriber) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+
+		s.shared.mux.mu.Lock()
+		defer s.shared.mux.mu.Unlock()
+		delete(s.shared.subscribers, s)
+		if len(s.shared.subscribers) == 0 {
+			s.shared.remove()
+		}
+	})
+}
+
+// startWatch starts the watch of a subscription. Watches resumed from a resource version are never shared, as the
+// shared watches only know the current objects.
+func (r *Service) startWatch(ctx context.Context, list *unstructured.UnstructuredList, opts []client.ListOption, resourceVersion string) (watch.Interface, error) {
+	if r.watchMux == nil || resourceVersion != "" {
+		return r.runtimeClient.Watch(ctx, list, watchFromOptions(opts, resourceVersion)...)
+	}
+
+	return r.watchMux.watch(ctx, watchKey(ctx, list, opts), func(watchCtx context.Context) (watch.Interface, error) {
+		return r.runtimeClient.Watch(watchCtx, list.DeepCopy(), opts...)
+	})
+}
@@ -0,0 +1,112 @@
+package resolver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+func TestWatchMultiplexer(t *testing.T) {
+	pod := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	next := func(t *testing.T, w watch.Interface) watch.Event {
+		select {
+		case event, ok := <-w.ResultChan():
+			require.True(t, ok, "result channel closed")
+			return event
+		case <-time.After(time.Second):
+			require.Fail(t, "no event received")
+			return watch.Event{}
+		}
+	}
+
+	mux := newWatchMultiplexer()
+	var upstreams []*watch.FakeWatcher
+	start := func(ctx context.Context) (watch.Interface, error) {
+		upstream := watch.NewFake()
+		upstreams = append(upstreams, upstream)
+		return upstream, nil
+	}
+
+	first, err := mux.watch(t.Context(), "pods", start)
+	require.NoError(t, err)
+	upstreams[0].Add(pod("a"))
+	assert.Equal(t, "a", next(t, first).Object.(*unstructured.Unstructured).GetName())
+
+	// A late subscriber gets the current objects first and then shares the events
+	second, err := mux.watch(t.Context(), "pods", start)
+	require.NoError(t, err)
+	require.Len(t, upstreams, 1, "the watch is shared")
+	event := next(t, second)
+	assert.Equal(t, watch.Added, event.Type)
+	assert.Equal(t, "a", event.Object.(*unstructured.Unstructured).GetName())
+
+	upstreams[0].Modify(pod("a"))
+	assert.Equal(t, watch.Modified, next(t, first).Type)
+	assert.Equal(t, watch.Modified, next(t, second).Type)
+
+	// Other keys get their own watch
+	other, err := mux.watch(t.Context(), "configmaps", start)
+	require.NoError(t, err)
+	require.Len(t, upstreams, 2)
+	other.Stop()
+	assert.True(t, upstreams[1].IsStopped(), "the watch is stopped with its last subscriber")
+
+	first.Stop()
+	assert.False(t, upstreams[0].IsStopped())
+	second.Stop()
+	assert.True(t, upstreams[0].IsStopped())
+
+	// The next subscriber starts a new watch
+	third, err := mux.watch(t.Context(), "pods", start)
+	require.NoError(t, err)
+	require.Len(t, upstreams, 3)
+
+	// Subscribers end with the watch of the API server
+	upstreams[2].Stop()
+	select {
+	case _, ok := <-third.ResultChan():
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		require.Fail(t, "result channel not closed")
+	}
+	third.Stop()
+}
+
+func TestWatchMultiplexer_StartError(t *testing.T) {
+	mux := newWatchMultiplexer()
+	_, err := mux.watch(t.Context(), "pods", func(ctx context.Context) (watch.Interface, error) {
+		return nil, errors.New("forbidden")
+	})
+	assert.Error(t, err)
+	assert.Empty(t, mux.watches)
+}
+
+func TestWatchKey(t *testing.T) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodList"})
+
+	ctxA := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token-a")
+	ctxB := context.WithValue(t.Context(), roundtripper.TokenKey{}, "token-b")
+
+	key := watchKey(ctxA, list, []client.ListOption{client.InNamespace("default")})
+	assert.Equal(t, key, watchKey(ctxA, list, []client.ListOption{client.InNamespace("default")}))
+	assert.NotEqual(t, key, watchKey(ctxB, list, []client.ListOption{client.InNamespace("default")}), "callers don't share watches")
+	assert.NotEqual(t, key, watchKey(ctxA, list, []client.ListOption{client.InNamespace("other")}))
+	assert.NotEqual(t, key, watchKey(ctxA, list, []client.ListOption{client.InNamespace("default"), client.MatchingLabels{"app": "web"}}))
+	assert.NotContains(t, key, "token-a")
+}
@@ -0,0 +1,131 @@
+package resolver
+
+import (
+	"context"
+	"sync"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type subscriptionCursorKey struct{}
+
+// SubscriptionCursor connects a subscription with its transport. It passes the resource version a reconnecting client
+// has seen last to the watch, and the resource versions of the delivered updates back to the transport, which sends
+// them as event IDs.
+type SubscriptionCursor struct {
+	lastEventID string
+
+	mu       sync.Mutex
+	versions []string
+}
+
+// NewSubscriptionCursor creates a cursor that resumes the subscription after lastEventID, empty starts from the current state
+func NewSubscriptionCursor(lastEventID string) *SubscriptionCursor {
+	return &SubscriptionCursor{lastEventID: lastEventID}
+}
+
+// WithSubscriptionCursor adds the cursor to the context the subscription is executed with
+func WithSubscriptionCursor(ctx context.Context, cursor *SubscriptionCursor) context.Context {
+	return context.WithValue(ctx, subscriptionCursorKey{}, cursor)
+}
+
+func subscriptionCursorFromContext(ctx context.Context) *SubscriptionCursor {
+	cursor, _ := ctx.Value(subscriptionCursorKey{}).(*SubscriptionCursor)
+	return cursor
+}
+
+// Next returns the resource version of the next update returned by the subscription, empty if the update has none,
+// e.g. because it is an error. It must be called once for every result of the subscription.
+func (c *SubscriptionCursor) Next() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.versions) == 0 {
+		return ""
+	}
+
+	version := c.versions[0]
+	c.versions = c.versions[1:]
+	return version
+}
+
+// record is called while the result of an update is executed, which happens before the result is returned to the transport
+func (c *SubscriptionCursor) record(resourceVersion string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.versions = append(c.versions, resourceVersion)
+}
+
+func (c *SubscriptionCursor) resumeFrom() string {
+	if c == nil {
+		return ""
+	}
+	return c.lastEventID
+}
+
+// subscriptionEvent is an update of a subscription together with the resource version of the watch event that caused it
+type subscriptionEvent struct {
+	resourceVersion string
+	data            interface{}
+}
+
+// resumeState lists the objects of a subscription exactly at the resource version the client has seen last, so that
+// the watch can continue from there with the state the client already has. ok is false if the resource version is
+// expired or invalid, in which case the subscription starts from the current state.
+func (r *Service) resumeState(
+	ctx context.Context,
+	list *unstructured.UnstructuredList,
+	opts []client.ListOption,
+	resourceVersion string,
+) (map[string]*unstructured.Unstructured, bool) {
+	state := list.DeepCopy()
+	listOpts := append(append([]client.ListOption{}, opts...), &client.ListOptions{Raw: &metav1.ListOptions{
+		ResourceVersion:      resourceVersion,
+		ResourceVersionMatch: metav1.ResourceVersionMatchExact,
+	}})
+
+	if err := r.runtimeClient.List(ctx, state, listOpts...); err != nil {
+		r.log.Debug().Err(err).Str("resourceVersion", resourceVersion).Msg("Failed to resume subscription, starting from the current state")
+		return nil, false
+	}
+
+	objects := make(map[string]*unstructured.Unstructured, len(state.Items))
+	for i := range state.Items {
+		item := &state.Items[i]
+		objects[item.GetNamespace()+"/"+item.GetName()] = item
+	}
+
+	return objects, true
+}
+
+// watchFromOptions adds the resource version the watch continues from to the options
+func watchFromOptions(opts []client.ListOption, resourceVersion string) []client.ListOption {
+	if resourceVersion == "" {
+		return opts
+	}
+	return append(append([]client.ListOption{}, opts...), &client.ListOptions{Raw: &metav1.ListOptions{
+		ResourceVersion: resourceVersion,
+	}})
+}
+
+// isExpiredWatch reports whether the resource version a watch was started from is no longer available
+func isExpiredWatch(err error) bool {
+	return apierrors.IsResourceExpired(err) || apierrors.IsGone(err)
+}
+
+// isExpiredWatchEvent reports whether the API server ended the watch because its resource version is no longer available
+func isExpiredWatchEvent(event watch.Event) bool {
+	if event.Type != watch.Error {
+		return false
+	}
+	return isExpiredWatch(apierrors.FromObject(event.Object))
+}
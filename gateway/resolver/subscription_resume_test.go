@@ -0,0 +1,161 @@
+package resolver_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func TestSubscribeItems_Resume(t *testing.T) {
+	configMap := func(name, resourceVersion string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("ConfigMap"))
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		obj.SetResourceVersion(resourceVersion)
+		return obj
+	}
+	expiredEvent := watch.Event{Type: watch.Error, Object: &metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusGone,
+		Reason: metav1.StatusReasonExpired,
+	}}
+
+	type update struct {
+		id    string
+		names []string
+	}
+
+	tests := []struct {
+		name                     string
+		lastEventID              string
+		listErr                  error
+		firstEvents              []watch.Event
+		expectedWatchVersions    []string
+		expectedUpdates          []update
+		expectedListedAtVersions []string
+	}{
+		{
+			name:                     "resumes_after_last_event",
+			lastEventID:              "10",
+			expectedListedAtVersions: []string{"10"},
+			expectedWatchVersions:    []string{"10"},
+			expectedUpdates:          []update{{id: "11", names: []string{"a", "b"}}},
+		},
+		{
+			name:                  "starts_from_current_state",
+			expectedWatchVersions: []string{""},
+			expectedUpdates:       []update{{names: []string{}}, {id: "11", names: []string{"b"}}},
+		},
+		{
+			name:                     "expired_list_falls_back",
+			lastEventID:              "10",
+			listErr:                  apierrors.NewResourceExpired("too old resource version"),
+			expectedListedAtVersions: []string{"10"},
+			expectedWatchVersions:    []string{""},
+			expectedUpdates:          []update{{names: []string{}}, {id: "11", names: []string{"b"}}},
+		},
+		{
+			name:                     "expired_watch_falls_back",
+			lastEventID:              "10",
+			firstEvents:              []watch.Event{expiredEvent},
+			expectedListedAtVersions: []string{"10"},
+			expectedWatchVersions:    []string{"10", ""},
+			expectedUpdates:          []update{{names: []string{}}, {id: "11", names: []string{"b"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var listedAtVersions, watchVersions []string
+			runtimeClient := fake.NewClientBuilder().
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						listOpts := client.ListOptions{}
+						listOpts.ApplyOptions(opts)
+						listedAtVersions = append(listedAtVersions, listOpts.Raw.ResourceVersion)
+						assert.Equal(t, metav1.ResourceVersionMatchExact, listOpts.Raw.ResourceVersionMatch)
+						if tt.listErr != nil {
+							return tt.listErr
+						}
+
+						list.(*unstructured.UnstructuredList).Items = []unstructured.Unstructured{*configMap("a", "10")}
+						return nil
+					},
+					Watch: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) (watch.Interface, error) {
+						listOpts := client.ListOptions{}
+						listOpts.ApplyOptions(opts)
+						resourceVersion := ""
+						if listOpts.Raw != nil {
+							resourceVersion = listOpts.Raw.ResourceVersion
+						}
+						watchVersions = append(watchVersions, resourceVersion)
+
+						watcher := watch.NewFakeWithChanSize(10, false)
+						if len(watchVersions) == 1 && len(tt.firstEvents) > 0 {
+							for _, event := range tt.firstEvents {
+								watcher.Action(event.Type, event.Object)
+							}
+							return watcher, nil
+						}
+						watcher.Add(configMap("b", "11"))
+						return watcher, nil
+					},
+				}).
+				Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient)
+
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+			cursor := resolver.NewSubscriptionCursor(tt.lastEventID)
+			ctx = resolver.WithSubscriptionCursor(ctx, cursor)
+
+			result, err := r.SubscribeItems(corev1.SchemeGroupVersion.WithKind("ConfigMap"), apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: ctx,
+				Args:    map[string]any{resolver.NamespaceArg: "default", resolver.SortByArg: "metadata.name"},
+			})
+			require.NoError(t, err)
+			updates := result.(chan interface{})
+
+			resolve := resolver.CreateSubscriptionResolver(false)
+			for _, expected := range tt.expectedUpdates {
+				var source interface{}
+				select {
+				case source = <-updates:
+				case <-ctx.Done():
+					t.Fatal("timed out waiting for subscription update")
+				}
+
+				data, err := resolve(graphql.ResolveParams{Context: ctx, Source: source})
+				require.NoError(t, err)
+
+				names := []string{}
+				for _, item := range data.([]map[string]any) {
+					names = append(names, item["metadata"].(map[string]any)["name"].(string))
+				}
+				assert.Equal(t, expected.names, names)
+				assert.Equal(t, expected.id, cursor.Next())
+			}
+
+			assert.Equal(t, tt.expectedListedAtVersions, listedAtVersions)
+			assert.Equal(t, tt.expectedWatchVersions, watchVersions)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package resolver
+
+import (
+	"github.com/graphql-go/graphql"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TemplateItem returns a resolver that returns a copy of the object template generated from the schema of the kind,
+// with the apiVersion, kind and the namespace argument set. The API server is not contacted.
+func (r *Service) TemplateItem(gvk schema.GroupVersionKind, scope v1.ResourceScope, template map[string]interface{}) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		obj := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(template)}
+
+		gvk.Group = r.getOriginalGroupName(gvk.Group)
+		obj.SetGroupVersionKind(gvk)
+
+		namespace, err := getNamespaceArg(p.Context, p.Args, scope, false)
+		if err != nil {
+			return nil, err
+		}
+		if isResourceNamespaceScoped(scope) {
+			if namespace != "" {
+				obj.SetNamespace(namespace)
+			}
+		}
+
+		return obj.Object, nil
+	}
+}
@@ -0,0 +1,171 @@
+package resolver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/manager/roundtripper"
+)
+
+const (
+	// WorkspaceAccessCacheTTL is how long the result of the access review of a workspace is cached per token
+	WorkspaceAccessCacheTTL = 30 * time.Second
+	// workspaceAccessReviewConcurrency bounds the access reviews sent at once for a page of workspaces
+	workspaceAccessReviewConcurrency = 10
+	// maxWorkspacePages bounds the pages listed to fill a page with accessible workspaces
+	maxWorkspacePages = 10
+)
+
+// WorkspaceGVK is the kind of the kcp workspaces
+var WorkspaceGVK = schema.GroupVersionKind{Group: "tenancy.kcp.io", Version: "v1alpha1", Kind: "Workspace"}
+
+// listFunc lists objects into list, see listObjects and listMetadata
+type listFunc func(ctx context.Context, list *unstructured.UnstructuredList, opts ...client.ListOption) error
+
+// accessReviewCache stores the results of access reviews per token, e.g. whether a token may enter a logical cluster
+type accessReviewCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]accessReviewEntry
+}
+
+type accessReviewEntry struct {
+	allowed    bool
+	reviewedAt time.Time
+}
+
+func (c *accessReviewCache) get(key string, now time.Time) (bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || now.Sub(entry.reviewedAt) > c.ttl {
+		return false, false
+	}
+	return entry.allowed, true
+}
+
+func (c *accessReviewCache) set(key string, allowed bool, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Expired entries are dropped here, so that the tokens of users who left don't pile up
+	for k, entry := range c.entries {
+		if now.Sub(entry.reviewedAt) > c.ttl {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = accessReviewEntry{allowed: allowed, reviewedAt: now}
+}
+
+// WithWorkspaceAccessFilter only returns the kcp workspaces from list queries that the caller may enter, i.e. that
+// it has the access verb on "/" in. Without it, every workspace the caller may list is returned.
+func (r *Service) WithWorkspaceAccessFilter(enabled bool) *Service {
+	if enabled {
+		r.workspaceAccess = &accessReviewCache{
+			ttl:     WorkspaceAccessCacheTTL,
+			entries: make(map[string]accessReviewEntry),
+		}
+	}
+	return r
+}
+
+func (r *Service) filtersWorkspaces(gvk schema.GroupVersionKind) bool {
+	return r.workspaceAccess != nil && gvk == WorkspaceGVK
+}
+
+// accessibleWorkspaces returns the workspaces the caller may enter. The access reviews are sent in parallel, a workspace
+// whose review fails is left out.
+func (r *Service) accessibleWorkspaces(ctx context.Context, workspaces []unstructured.Unstructured) []unstructured.Unstructured {
+	allowed := make([]bool, len(workspaces))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workspaceAccessReviewConcurrency)
+	for i := range workspaces {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			allowed[i] = r.canAccessWorkspace(ctx, &workspaces[i])
+		}()
+	}
+	wg.Wait()
+
+	accessible := make([]unstructured.Unstructured, 0, len(workspaces))
+	for i, workspace := range workspaces {
+		if allowed[i] {
+			accessible = append(accessible, workspace)
+		}
+	}
+	return accessible
+}
+
+func (r *Service) canAccessWorkspace(ctx context.Context, workspace *unstructured.Unstructured) bool {
+	// Workspaces are backed by a logical cluster once they were scheduled, they can't be entered before
+	cluster, _, _ := unstructured.NestedString(workspace.Object, "spec", "cluster")
+	if cluster == "" {
+		return false
+	}
+
+	key := tokenHash(ctx) + "/" + cluster
+
+	if allowed, ok := r.workspaceAccess.get(key, time.Now()); ok {
+		return allowed
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			NonResourceAttributes: &authorizationv1.NonResourceAttributes{Path: "/", Verb: "access"},
+		},
+	}
+	if err := r.runtimeClient.Create(kontext.WithCluster(ctx, logicalcluster.Name(cluster)), review); err != nil {
+		r.log.Error().Err(err).Str("workspace", workspace.GetName()).Msg("Unable to review the access to workspace")
+		return false
+	}
+
+	r.workspaceAccess.set(key, review.Status.Allowed, time.Now())
+	return review.Status.Allowed
+}
+
+// tokenHash returns the hash of the token of the caller, which identifies the caller in the access review caches
+func tokenHash(ctx context.Context) string {
+	token, _ := ctx.Value(roundtripper.TokenKey{}).(string)
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// fillWorkspacePage filters a page of workspaces to the accessible ones and lists further pages until it holds first
+// workspaces again, so that clients don't receive empty pages for large hierarchies. opts must not contain the limit
+// and continue options. The continue token of list stays valid for the next page.
+func (r *Service) fillWorkspacePage(ctx context.Context, list *unstructured.UnstructuredList, first int, listFn listFunc, opts []client.ListOption) error {
+	items := r.accessibleWorkspaces(ctx, list.Items)
+
+	for pages := 1; len(items) < first && list.GetContinue() != "" && pages < maxWorkspacePages; pages++ {
+		next := &unstructured.UnstructuredList{}
+		next.SetGroupVersionKind(list.GroupVersionKind())
+
+		pageOpts := append(opts[:len(opts):len(opts)], client.Limit(int64(first-len(items))), client.Continue(list.GetContinue()))
+		if err := listFn(ctx, next, pageOpts...); err != nil {
+			return err
+		}
+
+		items = append(items, r.accessibleWorkspaces(ctx, next.Items)...)
+		list.SetContinue(next.GetContinue())
+	}
+
+	list.Items = items
+	// The remaining count includes the workspaces the caller can't enter
+	list.SetRemainingItemCount(nil)
+	return nil
+}
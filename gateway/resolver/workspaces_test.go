@@ -0,0 +1,149 @@
+package resolver_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/kontext"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+func newWorkspace(name, cluster string) unstructured.Unstructured {
+	workspace := unstructured.Unstructured{Object: map[string]any{
+		"metadata": map[string]any{"name": name},
+		"spec":     map[string]any{},
+	}}
+	workspace.SetGroupVersionKind(resolver.WorkspaceGVK)
+	if cluster != "" {
+		_ = unstructured.SetNestedField(workspace.Object, cluster, "spec", "cluster")
+	}
+	return workspace
+}
+
+// newWorkspaceClient serves the workspaces in pages keyed by their continue token and allows entering the given logical clusters
+func newWorkspaceClient(pages map[string][]unstructured.Unstructured, next map[string]string, allowed map[string]bool, reviews *atomic.Int32, listOpts *[]client.ListOptions) client.WithWatch {
+	return fake.NewClientBuilder().
+		WithInterceptorFuncs(interceptor.Funcs{
+			List: func(ctx context.Context, clt client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				var listOptions client.ListOptions
+				listOptions.ApplyOptions(opts)
+				*listOpts = append(*listOpts, listOptions)
+
+				unstructuredList := list.(*unstructured.UnstructuredList)
+				unstructuredList.Items = pages[listOptions.Continue]
+				unstructuredList.SetContinue(next[listOptions.Continue])
+				return nil
+			},
+			Create: func(ctx context.Context, clt client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+				if !ok {
+					return errors.New("unexpected object")
+				}
+				reviews.Add(1)
+
+				cluster, _ := kontext.ClusterFrom(ctx)
+				if cluster == "unreachable" {
+					return errors.New("connection refused")
+				}
+				review.Status.Allowed = allowed[cluster.String()] &&
+					review.Spec.NonResourceAttributes.Path == "/" && review.Spec.NonResourceAttributes.Verb == "access"
+				return nil
+			},
+		}).
+		Build()
+}
+
+func workspaceNames(items []map[string]any) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item["metadata"].(map[string]any)["name"].(string))
+	}
+	return names
+}
+
+func TestListItems_FiltersWorkspaces(t *testing.T) {
+	pages := map[string][]unstructured.Unstructured{
+		"": {
+			newWorkspace("team-a", "cluster-a"),
+			newWorkspace("team-b", "cluster-b"),
+			newWorkspace("scheduling", ""),
+			newWorkspace("broken", "unreachable"),
+		},
+	}
+	allowed := map[string]bool{"cluster-a": true}
+
+	var reviews atomic.Int32
+	var listOpts []client.ListOptions
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, newWorkspaceClient(pages, nil, allowed, &reviews, &listOpts)).
+		WithWorkspaceAccessFilter(true)
+
+	list := r.ListItems(resolver.WorkspaceGVK, apiextensionsv1.ClusterScoped)
+
+	result, err := list(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, workspaceNames(result.([]map[string]any)))
+	assert.Equal(t, int32(3), reviews.Load(), "unscheduled workspaces are not reviewed")
+
+	_, err = list(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), reviews.Load(), "only the failed review is sent again")
+}
+
+func TestListItems_WorkspaceFilterDisabled(t *testing.T) {
+	pages := map[string][]unstructured.Unstructured{"": {newWorkspace("team-a", "cluster-a"), newWorkspace("team-b", "cluster-b")}}
+
+	var reviews atomic.Int32
+	var listOpts []client.ListOptions
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, newWorkspaceClient(pages, nil, nil, &reviews, &listOpts))
+
+	result, err := r.ListItems(resolver.WorkspaceGVK, apiextensionsv1.ClusterScoped)(graphql.ResolveParams{Context: t.Context(), Args: map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, workspaceNames(result.([]map[string]any)))
+	assert.Zero(t, reviews.Load())
+}
+
+func TestListItemsConnection_FillsWorkspacePage(t *testing.T) {
+	pages := map[string][]unstructured.Unstructured{
+		"":   {newWorkspace("team-a", "cluster-a"), newWorkspace("team-b", "cluster-b")},
+		"p2": {newWorkspace("team-c", "cluster-c")},
+		"p3": {newWorkspace("team-d", "cluster-d")},
+	}
+	next := map[string]string{"": "p2", "p2": "p3"}
+	allowed := map[string]bool{"cluster-a": true, "cluster-c": true, "cluster-d": true}
+
+	var reviews atomic.Int32
+	var listOpts []client.ListOptions
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, newWorkspaceClient(pages, next, allowed, &reviews, &listOpts)).
+		WithWorkspaceAccessFilter(true)
+
+	result, err := r.ListItemsConnection(resolver.WorkspaceGVK, apiextensionsv1.ClusterScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]any{resolver.FirstArg: 2},
+	})
+	require.NoError(t, err)
+
+	connection := result.(resolver.ListConnection)
+	assert.Equal(t, []string{"team-a", "team-c"}, workspaceNames(connection.Items))
+	assert.True(t, connection.PageInfo.HasNextPage)
+	require.NotNil(t, connection.PageInfo.EndCursor)
+	assert.Equal(t, "p3", *connection.PageInfo.EndCursor)
+	assert.Nil(t, connection.PageInfo.RemainingItemCount)
+
+	require.Len(t, listOpts, 2)
+	assert.Equal(t, int64(2), listOpts[0].Limit)
+	assert.Equal(t, int64(1), listOpts[1].Limit, "the next page is listed for the missing workspaces only")
+	assert.Equal(t, "p2", listOpts[1].Continue)
+}
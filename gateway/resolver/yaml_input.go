@@ -0,0 +1,252 @@
+package resolver
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+const preserveUnknownFieldsExtensionKey = "x-kubernetes-preserve-unknown-fields"
+
+var (
+	ErrParseYAMLObject    = errors.New("failed to parse YAML object")
+	ErrYAMLKindMismatch   = errors.New("YAML object does not match the mutation")
+	ErrSchemaValidation   = errors.New("object does not match the schema")
+	ErrNamespaceMismatch  = errors.New("metadata.namespace does not match the namespace argument")
+	ErrYAMLNameIsRequired = errors.New("object metadata.name is required")
+)
+
+// CreateItemFromYAML returns a resolver creating the object of the yaml argument like CreateItem does. The object is
+// validated against the OpenAPI schema of the resource before it is sent to the API server.
+func (r *Service) CreateItemFromYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	createItem := r.CreateItem(gvk, scope)
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		args, err := r.yamlObjectArgs(gvk, scope, p.Args, true)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Args = args
+		return createItem(p)
+	}
+}
+
+// UpdateItemFromYAML returns a resolver updating the object of the yaml argument like UpdateItem does, the object is
+// identified by its metadata.name
+func (r *Service) UpdateItemFromYAML(gvk schema.GroupVersionKind, scope v1.ResourceScope) graphql.FieldResolveFn {
+	updateItem := r.UpdateItem(gvk, scope)
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		args, err := r.yamlObjectArgs(gvk, scope, p.Args, false)
+		if err != nil {
+			return nil, err
+		}
+
+		p.Args = args
+		return updateItem(p)
+	}
+}
+
+// yamlObjectArgs returns the arguments of the create and update mutations for the object of the yaml argument.
+// The namespace argument defaults to metadata.namespace, required fields are only checked for new objects as updates
+// are merge patches.
+func (r *Service) yamlObjectArgs(gvk schema.GroupVersionKind, scope v1.ResourceScope, args map[string]interface{}, create bool) (map[string]interface{}, error) {
+	manifest, err := getStringArg(args, YAMLArg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	object := map[string]interface{}{}
+	if err := utilyaml.Unmarshal([]byte(manifest), &object); err != nil {
+		return nil, errors.Join(ErrParseYAMLObject, err)
+	}
+	if len(object) == 0 {
+		return nil, fmt.Errorf("%w: the document is empty", ErrParseYAMLObject)
+	}
+
+	originalGVK := schema.GroupVersionKind{Group: r.getOriginalGroupName(gvk.Group), Version: gvk.Version, Kind: gvk.Kind}
+	obj := &unstructured.Unstructured{Object: object}
+	if apiVersion := obj.GetAPIVersion(); apiVersion != "" && apiVersion != originalGVK.GroupVersion().String() {
+		return nil, fmt.Errorf("%w: apiVersion %s, expected %s", ErrYAMLKindMismatch, apiVersion, originalGVK.GroupVersion())
+	}
+	if kind := obj.GetKind(); kind != "" && kind != originalGVK.Kind {
+		return nil, fmt.Errorf("%w: kind %s, expected %s", ErrYAMLKindMismatch, kind, originalGVK.Kind)
+	}
+	if obj.GetName() == "" {
+		return nil, ErrYAMLNameIsRequired
+	}
+
+	result := maps.Clone(args)
+	delete(result, YAMLArg)
+	if !create {
+		result[NameArg] = obj.GetName()
+	}
+
+	if isResourceNamespaceScoped(scope) {
+		namespace, err := getStringArg(args, NamespaceArg, false)
+		if err != nil {
+			return nil, err
+		}
+		if objNamespace := obj.GetNamespace(); objNamespace != "" {
+			if namespace != "" && namespace != objNamespace {
+				return nil, fmt.Errorf("%w: %s, namespace %s", ErrNamespaceMismatch, objNamespace, namespace)
+			}
+			result[NamespaceArg] = objNamespace
+		}
+	}
+
+	if err := r.coerceObjectInput(originalGVK, object); err != nil {
+		return nil, err
+	}
+	if err := r.validateObjectInput(originalGVK, object, create); err != nil {
+		return nil, err
+	}
+
+	result[ObjectArg] = object
+	return result, nil
+}
+
+// validateObjectInput checks the object against the OpenAPI schema of the resource, i.e. the types of the values,
+// unknown fields and, with requireFields, missing required fields. gvk must contain the original group name.
+// Resources without a known schema are not validated, the API server validates them.
+func (r *Service) validateObjectInput(gvk schema.GroupVersionKind, object map[string]any, requireFields bool) error {
+	key, ok := r.definitionsByGVK[gvk]
+	if !ok {
+		return nil
+	}
+
+	var problems []string
+	r.validateValue(r.definitions[key], object, "object", requireFields, map[string]bool{key: true}, &problems)
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaValidation, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// validateValue appends the problems of the value to problems, visitedRefs prevents endless recursion
+func (r *Service) validateValue(s spec.Schema, value any, path string, requireFields bool, visitedRefs map[string]bool, problems *[]string) {
+	if value == nil {
+		return
+	}
+
+	if refKey := strings.TrimPrefix(s.Ref.String(), "#/definitions/"); refKey != "" {
+		refSchema, ok := r.definitions[refKey]
+		if !ok || visitedRefs[refKey] {
+			return
+		}
+		// Quantities are written as numbers as well as strings in manifests, the API server accepts both
+		if strings.HasSuffix(refKey, ".api.resource.Quantity") {
+			return
+		}
+
+		visitedRefs[refKey] = true
+		defer delete(visitedRefs, refKey)
+
+		r.validateValue(refSchema, value, path, requireFields, visitedRefs, problems)
+		return
+	}
+
+	if intOrString, _ := s.Extensions[common.IntOrStringExtensionKey].(bool); intOrString || s.Format == "int-or-string" {
+		if _, ok := value.(string); !ok && !isInteger(value) {
+			*problems = append(*problems, fmt.Sprintf("%s must be an integer or a string", path))
+		}
+		return
+	}
+
+	schemaType := ""
+	if len(s.Type) > 0 {
+		schemaType = s.Type[0]
+	} else if len(s.Properties) > 0 {
+		schemaType = "object"
+	}
+
+	switch schemaType {
+	case "string":
+		if _, ok := value.(string); !ok {
+			*problems = append(*problems, fmt.Sprintf("%s must be a string", path))
+		}
+	case "integer":
+		if !isInteger(value) {
+			*problems = append(*problems, fmt.Sprintf("%s must be an integer", path))
+		}
+	case "number":
+		switch value.(type) {
+		case int, int32, int64, float32, float64:
+		default:
+			*problems = append(*problems, fmt.Sprintf("%s must be a number", path))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*problems = append(*problems, fmt.Sprintf("%s must be a boolean", path))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("%s must be a list", path))
+			return
+		}
+		if s.Items == nil || s.Items.Schema == nil {
+			return
+		}
+		for i, item := range items {
+			r.validateValue(*s.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i), requireFields, visitedRefs, problems)
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			*problems = append(*problems, fmt.Sprintf("%s must be an object", path))
+			return
+		}
+		if preserveUnknown, _ := s.Extensions[preserveUnknownFieldsExtensionKey].(bool); preserveUnknown {
+			return
+		}
+
+		if requireFields {
+			for _, name := range s.Required {
+				if _, ok := object[name]; !ok {
+					*problems = append(*problems, fmt.Sprintf("%s.%s is required", path, name))
+				}
+			}
+		}
+
+		for _, name := range slices.Sorted(maps.Keys(object)) {
+			fieldSchema, ok := s.Properties[name]
+			if !ok {
+				switch {
+				case s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+					fieldSchema = *s.AdditionalProperties.Schema
+				case s.AdditionalProperties != nil && s.AdditionalProperties.Allows, len(s.Properties) == 0:
+					continue
+				default:
+					*problems = append(*problems, fmt.Sprintf("%s.%s is not a known field", path, name))
+					continue
+				}
+			}
+			r.validateValue(fieldSchema, object[name], path+"."+name, requireFields, visitedRefs, problems)
+		}
+	}
+}
+
+func isInteger(value any) bool {
+	switch v := value.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return v == math.Trunc(v)
+	default:
+		return false
+	}
+}
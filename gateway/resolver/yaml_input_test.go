@@ -0,0 +1,158 @@
+package resolver_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+// manifestDefinitions extends the Deployment definitions with the fields every manifest has
+func manifestDefinitions() spec.Definitions {
+	definitions := deploymentDefinitions()
+
+	deployment := definitions["io.k8s.api.apps.v1.Deployment"]
+	deployment.Properties["apiVersion"] = *spec.StringProperty()
+	deployment.Properties["kind"] = *spec.StringProperty()
+	deployment.Properties["metadata"] = spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{
+			"name":      *spec.StringProperty(),
+			"namespace": *spec.StringProperty(),
+			"labels":    *spec.MapProperty(spec.StringProperty()),
+		},
+	}}
+	deployment.Required = []string{"spec"}
+	definitions["io.k8s.api.apps.v1.Deployment"] = deployment
+
+	return definitions
+}
+
+func TestCreateItemFromYAML(t *testing.T) {
+	tests := []struct {
+		name        string
+		manifest    string
+		namespace   string
+		expectedErr error
+	}{
+		{
+			name: "valid",
+			manifest: `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: team-a
+  labels:
+    app: web
+spec:
+  replicas: 2
+  strategy:
+    maxUnavailable: 25%
+`,
+		},
+		{
+			name:      "namespace_argument",
+			manifest:  "metadata:\n  name: web\nspec:\n  replicas: 2\n",
+			namespace: "team-a",
+		},
+		{
+			name:        "namespace_mismatch_ERROR",
+			manifest:    "metadata:\n  name: web\n  namespace: team-b\nspec: {}\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrNamespaceMismatch,
+		},
+		{
+			name:        "other_kind_ERROR",
+			manifest:    "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: web\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrYAMLKindMismatch,
+		},
+		{
+			name:        "unknown_field_ERROR",
+			manifest:    "metadata:\n  name: web\nspec:\n  replica: 2\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrSchemaValidation,
+		},
+		{
+			name:        "wrong_type_ERROR",
+			manifest:    "metadata:\n  name: web\nspec:\n  paused: sometimes\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrSchemaValidation,
+		},
+		{
+			name:        "missing_required_field_ERROR",
+			manifest:    "metadata:\n  name: web\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrSchemaValidation,
+		},
+		{
+			name:        "missing_name_ERROR",
+			manifest:    "spec:\n  replicas: 2\n",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrYAMLNameIsRequired,
+		},
+		{
+			name:        "invalid_yaml_ERROR",
+			manifest:    "metadata: [web",
+			namespace:   "team-a",
+			expectedErr: resolver.ErrParseYAMLObject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runtimeClient := fake.NewClientBuilder().Build()
+			r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).
+				WithInputCoercion(resolver.InputCoercionDisabled, manifestDefinitions())
+
+			args := map[string]interface{}{resolver.YAMLArg: tt.manifest}
+			if tt.namespace != "" {
+				args[resolver.NamespaceArg] = tt.namespace
+			}
+
+			_, err := r.CreateItemFromYAML(deploymentGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+				Context: t.Context(),
+				Args:    args,
+			})
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			deployment := &appsv1.Deployment{}
+			require.NoError(t, runtimeClient.Get(t.Context(), client.ObjectKey{Namespace: "team-a", Name: "web"}, deployment))
+			require.NotNil(t, deployment.Spec.Replicas)
+			assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+		})
+	}
+}
+
+func TestUpdateItemFromYAML(t *testing.T) {
+	existing := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a"}}
+	runtimeClient := fake.NewClientBuilder().WithObjects(existing).Build()
+	r := resolver.New(testlogger.New().HideLogOutput().Logger, runtimeClient).
+		WithInputCoercion(resolver.InputCoercionDisabled, manifestDefinitions())
+
+	// Required fields are not checked, the update is a merge patch
+	_, err := r.UpdateItemFromYAML(deploymentGVK, apiextensionsv1.NamespaceScoped)(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]interface{}{resolver.YAMLArg: "metadata:\n  name: web\n  namespace: team-a\n  labels:\n    app: web\n"},
+	})
+	require.NoError(t, err)
+
+	deployment := &appsv1.Deployment{}
+	require.NoError(t, runtimeClient.Get(t.Context(), client.ObjectKey{Namespace: "team-a", Name: "web"}, deployment))
+	assert.Equal(t, map[string]string{"app": "web"}, deployment.Labels)
+}
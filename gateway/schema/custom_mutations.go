@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const (
+	createNamespace = "createNamespace"
+	deleteNamespace = "deleteNamespace"
+
+	// namespaceDefinitionKey is the OpenAPI definition of core/v1 Namespace
+	namespaceDefinitionKey = "io.k8s.api.core.v1.Namespace"
+)
+
+var deleteMatchingResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "DeleteMatchingResult",
+	Description: "The objects affected by a deleteMatching mutation",
+	Fields: graphql.Fields{
+		"count": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"names": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+	},
+})
+
+// AddNamespaceMutations adds the tenant onboarding mutations if the cluster serves core/v1 Namespaces
+func (g *Gateway) AddNamespaceMutations(rootMutationFields graphql.Fields) {
+	if _, ok := g.definitions[namespaceDefinitionKey]; !ok {
+		return
+	}
+
+	resultType := graphql.NewObject(graphql.ObjectConfig{
+		Name: createNamespace + "Result",
+		Fields: graphql.Fields{
+			"name":     graphqlStringField(),
+			"template": &graphql.Field{Type: graphql.String},
+			"resources": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "The template resources created in the namespace, formatted as Kind/name",
+			},
+		},
+	})
+
+	createArgs := resolver.NewFieldConfigArguments().
+		WithName().
+		WithDryRun().
+		Complete()
+	createArgs[resolver.LabelsArg] = &graphql.ArgumentConfig{
+		Type:        stringMapScalar,
+		Description: "The labels to set on the namespace",
+	}
+	createArgs[resolver.TemplateArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "The name of the namespace template from the gateway configuration to apply",
+	}
+
+	rootMutationFields[createNamespace] = &graphql.Field{
+		Type:        resultType,
+		Args:        createArgs,
+		Resolve:     g.resolver.CreateNamespace(),
+		Description: "Create a namespace and apply the resources of the given template to it. The namespace is removed again if the template can not be applied.",
+	}
+
+	deleteArgs := resolver.NewFieldConfigArguments().
+		WithName().
+		WithDryRun().
+		Complete()
+	deleteArgs[resolver.ConfirmationArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewNonNull(graphql.String),
+		Description: "Must repeat the name of the namespace to confirm the deletion",
+	}
+
+	rootMutationFields[deleteNamespace] = &graphql.Field{
+		Type:        graphql.Boolean,
+		Args:        deleteArgs,
+		Resolve:     g.resolver.DeleteNamespace(),
+		Description: "Delete a namespace together with all of its resources",
+	}
+}
+
+const issueKubeconfig = "issueKubeconfig"
+
+var issuedKubeconfigType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "IssuedKubeconfig",
+	Description: "A kubeconfig with a short-lived service account token for direct access to the cluster",
+	Fields: graphql.Fields{
+		"kubeconfig":          graphqlStringField(),
+		"expirationTimestamp": graphqlStringField(),
+		"serviceAccount": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The service account of the token, formatted as namespace/name",
+		},
+	},
+})
+
+// WithKubeconfigIssuance adds the issueKubeconfig mutation, the resolver must be configured with resolver.WithKubeconfigIssuance
+func WithKubeconfigIssuance() Option {
+	return func(g *Gateway) {
+		g.kubeconfigIssuance = true
+	}
+}
+
+// AddKubeconfigMutation adds the mutation issuing kubeconfigs for kubectl, if it is enabled
+func (g *Gateway) AddKubeconfigMutation(rootMutationFields graphql.Fields) {
+	if !g.kubeconfigIssuance {
+		return
+	}
+
+	rootMutationFields[issueKubeconfig] = &graphql.Field{
+		Type: graphql.NewNonNull(issuedKubeconfigType),
+		Args: graphql.FieldConfigArgument{
+			resolver.TTLArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "Lifetime of the token as a duration like 30m, defaults to the maximum configured in the gateway",
+			},
+		},
+		Resolve:     g.resolver.IssueKubeconfig(),
+		Description: "Issue a kubeconfig with a short-lived token for kubectl access to the cluster, requires permission to create tokens for the configured service account",
+	}
+}
+
+const applyManifests = "applyManifests"
+
+var manifestResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ManifestResult",
+	Description: "The outcome of an object of the applyManifests mutation",
+	Fields: graphql.Fields{
+		"index": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "Position of the object, the objects argument comes before the documents of the yaml argument",
+		},
+		"apiVersion": graphqlStringField(),
+		"kind":       graphqlStringField(),
+		"namespace":  &graphql.Field{Type: graphql.String},
+		"name":       graphqlStringField(),
+		"action": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "One of created, updated, failed, skipped or rolledBack",
+		},
+		"error": &graphql.Field{Type: graphql.String},
+	},
+})
+
+// AddManifestsMutation adds the mutation applying a batch of objects of any kind
+func (g *Gateway) AddManifestsMutation(rootMutationFields graphql.Fields) {
+	args := resolver.NewFieldConfigArguments().
+		WithDryRun().
+		Complete()
+	args[resolver.ObjectsArg] = &graphql.ArgumentConfig{
+		Type:        graphql.NewList(graphql.NewNonNull(jsonStringScalar)),
+		Description: "The objects to apply in order, each a JSON object with apiVersion, kind and metadata.name",
+	}
+	args[resolver.YAMLArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Manifests to apply after the objects, several documents are separated by ---",
+	}
+	args[resolver.NamespaceArg] = &graphql.ArgumentConfig{
+		Type:        graphql.String,
+		Description: "Namespace of the namespaced objects without metadata.namespace",
+	}
+	args[resolver.StopOnErrorArg] = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "Skip the remaining objects once an object fails",
+	}
+	args[resolver.RollbackArg] = &graphql.ArgumentConfig{
+		Type:         graphql.Boolean,
+		DefaultValue: false,
+		Description:  "Delete the objects created by this mutation once an object fails, requires stopOnError. Updated objects are not restored.",
+	}
+
+	rootMutationFields[applyManifests] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(manifestResultType))),
+		Args:        args,
+		Resolve:     g.resolver.ApplyManifests(),
+		Description: "Create the given objects in order and update the ones that exist already, returning the outcome of every object",
+	}
+}
@@ -6,17 +6,51 @@ import (
 )
 
 const (
-	typeByCategory = "typeByCategory"
+	typeByCategory  = "typeByCategory"
+	clusterDefaults = "clusterDefaults"
+	clusterInfo     = "clusterInfo"
 )
 
 func (g *Gateway) AddTypeByCategoryQuery(rootQueryFields graphql.Fields) {
 	resourceType := graphql.NewObject(graphql.ObjectConfig{
 		Name: typeByCategory + "Object",
 		Fields: graphql.Fields{
-			"kind":    graphqlStringField(),
-			"group":   graphqlStringField(),
+			"kind": graphqlStringField(),
+			"group": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Field of the root query and mutation types holding the operations of the kind",
+			},
+			"apiGroup": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Group the kind is served under by the API server, empty for the core group",
+			},
 			"version": graphqlStringField(),
 			"scope":   graphqlStringField(),
+			"singular": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Field of the query returning a single object, below the group",
+			},
+			"plural": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Field of the query listing the objects, below the group",
+			},
+			"verbs": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+				Description: "Verbs the API server supports for the resource",
+			},
+			"subscriptions": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Boolean),
+				Description: "Whether changes of the resource can be subscribed to",
+			},
+			"singularSubscription": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Subscription field of a single object, empty if the resource can't be watched",
+			},
+			"pluralSubscription": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Subscription field of the list, empty if the resource can't be watched",
+			},
+			"description": &graphql.Field{Type: graphql.String},
 		},
 	})
 
@@ -29,6 +63,59 @@ func (g *Gateway) AddTypeByCategoryQuery(rootQueryFields graphql.Fields) {
 	}
 }
 
+// AddClusterDefaultsQuery adds the query returning the presets configured for the cluster
+func (g *Gateway) AddClusterDefaultsQuery(rootQueryFields graphql.Fields) {
+	defaultsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ClusterDefaults",
+		Fields: graphql.Fields{
+			"defaultNamespace": &graphql.Field{Type: graphql.String},
+			"commonKinds": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+		},
+	})
+
+	rootQueryFields[clusterDefaults] = &graphql.Field{
+		Type:        graphql.NewNonNull(defaultsType),
+		Resolve:     g.resolver.ClusterDefaults(),
+		Description: "Presets configured for the cluster, e.g. the namespace and the kinds a frontend should show initially",
+	}
+}
+
+// AddClusterInfoQuery adds the query returning the version and the API groups of the cluster
+func (g *Gateway) AddClusterInfoQuery(rootQueryFields graphql.Fields) {
+	apiGroupType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ClusterInfoAPIGroup",
+		Fields: graphql.Fields{
+			"name": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Name of the API group, empty for the core group",
+			},
+			"versions": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			},
+			"preferredVersion": graphqlStringField(),
+		},
+	})
+
+	infoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ClusterInfo",
+		Fields: graphql.Fields{
+			"version":  graphqlStringField(),
+			"platform": graphqlStringField(),
+			"apiGroups": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(apiGroupType))),
+			},
+		},
+	})
+
+	rootQueryFields[clusterInfo] = &graphql.Field{
+		Type:        graphql.NewNonNull(infoType),
+		Resolve:     g.resolver.ClusterInfo(),
+		Description: "Version and API groups of the cluster, e.g. to detect whether an API group is installed",
+	}
+}
+
 func graphqlStringField() *graphql.Field {
 	return &graphql.Field{
 		Type: graphql.NewNonNull(graphql.String),
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+const deprecations = "deprecations"
+
+// DeprecatedResource is a kind served by a deprecated API of the cluster
+type DeprecatedResource struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+	Warning string `json:"warning"`
+	// DeprecatedIn and RemovedIn are only set if the warning names the Kubernetes versions
+	DeprecatedIn *string `json:"deprecatedIn"`
+	RemovedIn    *string `json:"removedIn"`
+}
+
+// storeDeprecation remembers the deprecation warning the listener recorded for the resource, if any
+func (g *Gateway) storeDeprecation(resourceKey string, apiGVK schema.GroupVersionKind) {
+	raw, ok := g.definitions[resourceKey].Extensions[common.DeprecationExtensionKey]
+	if !ok {
+		return
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Failed to marshal deprecation")
+		return
+	}
+
+	var deprecation common.Deprecation
+	if err := json.Unmarshal(data, &deprecation); err != nil {
+		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Failed to parse deprecation")
+		return
+	}
+
+	deprecated := DeprecatedResource{
+		Group:   apiGVK.Group,
+		Version: apiGVK.Version,
+		Kind:    apiGVK.Kind,
+		Warning: deprecation.Warning,
+	}
+	if deprecation.DeprecatedIn != "" {
+		deprecated.DeprecatedIn = &deprecation.DeprecatedIn
+	}
+	if deprecation.RemovedIn != "" {
+		deprecated.RemovedIn = &deprecation.RemovedIn
+	}
+
+	g.deprecations = append(g.deprecations, deprecated)
+}
+
+// AddDeprecationsQuery adds the query listing the kinds of the cluster that are served by deprecated APIs
+func (g *Gateway) AddDeprecationsQuery(rootQueryFields graphql.Fields) {
+	slices.SortFunc(g.deprecations, func(a, b DeprecatedResource) int {
+		return cmp.Or(cmp.Compare(a.Group, b.Group), cmp.Compare(a.Version, b.Version), cmp.Compare(a.Kind, b.Kind))
+	})
+
+	deprecatedResourceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "DeprecatedResource",
+		Fields: graphql.Fields{
+			"group":   graphqlStringField(),
+			"version": graphqlStringField(),
+			"kind":    graphqlStringField(),
+			"warning": graphqlStringField(),
+			"deprecatedIn": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Kubernetes version that deprecated the API, e.g. v1.21",
+			},
+			"removedIn": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Kubernetes version that stops serving the API, e.g. v1.25",
+			},
+		},
+	})
+	rootQueryFields[deprecations] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(deprecatedResourceType))),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return g.deprecations, nil
+		},
+		Description: "Kinds served by deprecated APIs of the cluster, only listed if the listener probes for deprecation warnings",
+	}
+}
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+)
+
+// DefaultDescriptionLength is the maximum length in characters of the descriptions taken from the OpenAPI definitions
+const DefaultDescriptionLength = 1000
+
+// WithDescriptionLength limits the descriptions taken from the OpenAPI definitions to length characters,
+// longer descriptions are cut at a word boundary. 0 leaves the descriptions out, e.g. to keep introspection responses small.
+func WithDescriptionLength(length int) Option {
+	return func(g *Gateway) {
+		g.descriptionLength = max(length, 0)
+	}
+}
+
+// schemaDescription returns the sanitized description of a definition or field, or of the definition it refers to
+func (g *Gateway) schemaDescription(schema spec.Schema) string {
+	description := schema.Description
+	if description == "" && schema.Ref.GetURL() != nil {
+		description = g.definitions[strings.TrimPrefix(schema.Ref.String(), "#/definitions/")].Description
+	}
+
+	return sanitizeDescription(description, g.descriptionLength)
+}
+
+// defaultDescription returns the note on the OpenAPI default of an input field, or of the definition it refers to.
+// The default is only named in the description and not set as defaultValue of the input field, since the input types
+// are shared with the update mutations, which would otherwise reset the fields left out to their default.
+func (g *Gateway) defaultDescription(schema spec.Schema) string {
+	defaultValue := schema.Default
+	if defaultValue == nil && schema.Ref.GetURL() != nil {
+		defaultValue = g.definitions[strings.TrimPrefix(schema.Ref.String(), "#/definitions/")].Default
+	}
+	if defaultValue == nil {
+		return ""
+	}
+
+	data, err := json.Marshal(defaultValue)
+	if err != nil {
+		return ""
+	}
+	return sanitizeDescription("Defaults to "+string(data)+" on creation.", g.descriptionLength)
+}
+
+// sanitizeDescription removes control characters and surplus whitespace and cuts the description to maxLength characters
+func sanitizeDescription(description string, maxLength int) string {
+	if maxLength <= 0 {
+		return ""
+	}
+
+	description = strings.ToValidUTF8(strings.ReplaceAll(description, "\r\n", "\n"), "")
+	description = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n':
+			return r
+		case r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		default:
+			return r
+		}
+	}, description)
+
+	// Keep the paragraphs, but drop trailing spaces and more than one empty line between them
+	lines := strings.Split(description, "\n")
+	sanitized := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if line == "" && (len(sanitized) == 0 || sanitized[len(sanitized)-1] == "") {
+			continue
+		}
+		sanitized = append(sanitized, line)
+	}
+	description = strings.TrimSpace(strings.Join(sanitized, "\n"))
+
+	runes := []rune(description)
+	if len(runes) <= maxLength {
+		return description
+	}
+
+	cut := string(runes[:maxLength])
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + "…"
+}
+
+// joinDescriptions joins the non-empty descriptions as paragraphs, UI hints are appended to the OpenAPI description
+func joinDescriptions(descriptions ...string) string {
+	nonEmpty := make([]string, 0, len(descriptions))
+	for _, description := range descriptions {
+		if description != "" {
+			nonEmpty = append(nonEmpty, description)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n\n")
+}
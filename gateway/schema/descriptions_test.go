@@ -0,0 +1,123 @@
+package schema_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestSanitizeDescription(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		maxLength   int
+		expected    string
+	}{
+		{name: "unchanged", description: "Name of the object.", maxLength: 100, expected: "Name of the object."},
+		{name: "control_characters", description: "Name\tof the\x00 object.\r\n", maxLength: 100, expected: "Name of the object."},
+		{name: "empty_lines", description: "First.  \n\n\n\nSecond.\n", maxLength: 100, expected: "First.\n\nSecond."},
+		{name: "cut_at_word", description: "The phase of the pod", maxLength: 15, expected: "The phase of…"},
+		{name: "cut_multibyte", description: "äöüäöüäöü", maxLength: 3, expected: "äöü…"},
+		{name: "disabled", description: "Name of the object.", maxLength: 0, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schema.SanitizeDescriptionForTest(tt.description, tt.maxLength))
+		})
+	}
+}
+
+func TestNew_Descriptions(t *testing.T) {
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.PodStatus": spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PodStatus represents information about the status of a pod.",
+				Type:        spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"phase": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"string"}, Description: "The phase of a Pod."}},
+				},
+			},
+		},
+	}
+	pod := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Description: "Pod is a collection of containers that can run on a host.",
+			Type:        spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"status": {SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/io.k8s.api.core.v1.PodStatus")}},
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Description: "Specification of the desired behavior of the pod.\n\n\n" + strings.Repeat("word ", 100),
+						Type:        spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"nodeName": *spec.StringProperty(),
+							"restartPolicy": {SchemaProps: spec.SchemaProps{
+								Type:        spec.StringOrArray{"string"},
+								Description: "Restart policy for all containers within the pod.",
+								Default:     "Always",
+							}},
+						},
+					},
+				},
+			},
+		},
+	}
+	pod.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"},
+	})
+	pod.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions["io.k8s.api.core.v1.Pod"] = pod
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	t.Run("added", func(t *testing.T) {
+		g, err := schema.New(log, definitions, resolver.New(log, nil), schema.WithDescriptionLength(100))
+		require.NoError(t, err)
+
+		podType, ok := g.GetSchema().Type("Pod").(*graphql.Object)
+		require.True(t, ok)
+		assert.Equal(t, "Pod is a collection of containers that can run on a host.", podType.Description())
+
+		status := podType.Fields()["status"]
+		assert.Equal(t, "PodStatus represents information about the status of a pod.", status.Description, "taken from the referenced definition")
+		statusType, ok := status.Type.(*graphql.Object)
+		require.True(t, ok)
+		assert.Equal(t, "PodStatus represents information about the status of a pod.", statusType.Description())
+		assert.Equal(t, "The phase of a Pod.", statusType.Fields()["phase"].Description)
+
+		spec := podType.Fields()["spec"]
+		assert.True(t, strings.HasPrefix(spec.Description, "Specification of the desired behavior of the pod.\n\nword"))
+		assert.True(t, strings.HasSuffix(spec.Description, "word…"))
+		assert.LessOrEqual(t, len([]rune(spec.Description)), 101)
+
+		inputType, ok := g.GetSchema().Type("PodInput").(*graphql.InputObject)
+		require.True(t, ok)
+		assert.Equal(t, "Pod is a collection of containers that can run on a host.", inputType.Description())
+		assert.Equal(t, status.Description, inputType.Fields()["status"].Description())
+
+		specInputType, ok := inputType.Fields()["spec"].Type.(*graphql.InputObject)
+		require.True(t, ok)
+		assert.Equal(t, "Restart policy for all containers within the pod.\n\nDefaults to \"Always\" on creation.", specInputType.Fields()["restartPolicy"].Description())
+		assert.Nil(t, specInputType.Fields()["restartPolicy"].DefaultValue, "updates must not reset the field")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		g, err := schema.New(log, definitions, resolver.New(log, nil), schema.WithDescriptionLength(0))
+		require.NoError(t, err)
+
+		podType, ok := g.GetSchema().Type("Pod").(*graphql.Object)
+		require.True(t, ok)
+		assert.Empty(t, podType.Description())
+		assert.Empty(t, podType.Fields()["status"].Description)
+	})
+}
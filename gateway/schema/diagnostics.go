@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+const schemaDiagnostics = "schemaDiagnostics"
+
+// SkippedDefinition is a group version or a definition the listener left out of the schema
+type SkippedDefinition struct {
+	GroupVersion string `json:"groupVersion"`
+	// Definition is nil if the whole group version was left out
+	Definition *string `json:"definition"`
+	Error      string  `json:"error"`
+}
+
+// SchemaDiagnostics reports the problems found while the schema of the cluster was generated
+type SchemaDiagnostics struct {
+	SkippedDefinitions []SkippedDefinition `json:"skippedDefinitions"`
+}
+
+// WithGenerationErrors reports the group versions and definitions the listener left out of the schema file in the
+// schemaDiagnostics query
+func WithGenerationErrors(generationErrors []common.GenerationError) Option {
+	return func(g *Gateway) {
+		g.generationErrors = generationErrors
+	}
+}
+
+// AddSchemaDiagnosticsQuery adds the query reporting the definitions missing in the schema, e.g. of CRDs with an
+// invalid OpenAPI schema
+func (g *Gateway) AddSchemaDiagnosticsQuery(rootQueryFields graphql.Fields) {
+	diagnostics := SchemaDiagnostics{SkippedDefinitions: make([]SkippedDefinition, 0, len(g.generationErrors))}
+	for _, generationError := range g.generationErrors {
+		skipped := SkippedDefinition{
+			GroupVersion: generationError.GroupVersion,
+			Error:        generationError.Error,
+		}
+		if generationError.Definition != "" {
+			skipped.Definition = &generationError.Definition
+		}
+		diagnostics.SkippedDefinitions = append(diagnostics.SkippedDefinitions, skipped)
+	}
+
+	skippedDefinitionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SkippedDefinition",
+		Fields: graphql.Fields{
+			"groupVersion": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Group version of the definition, e.g. example.io/v1, v1 for the core group",
+			},
+			"definition": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Name of the OpenAPI definition, empty if the whole group version was skipped",
+			},
+			"error": graphqlStringField(),
+		},
+	})
+	diagnosticsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SchemaDiagnostics",
+		Fields: graphql.Fields{
+			"skippedDefinitions": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(skippedDefinitionType))),
+			},
+		},
+	})
+
+	rootQueryFields[schemaDiagnostics] = &graphql.Field{
+		Type: graphql.NewNonNull(diagnosticsType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return diagnostics, nil
+		},
+		Description: "Problems found while the schema was generated, e.g. definitions left out because their OpenAPI schema is invalid",
+	}
+}
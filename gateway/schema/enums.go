@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"slices"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+)
+
+// reservedEnumValues can't be used as names of enum values in GraphQL
+var reservedEnumValues = []string{"true", "false", "null"}
+
+// WithEnums generates GraphQL enums for string fields whose OpenAPI schema restricts them to enum values,
+// e.g. imagePullPolicy. Inline values of these fields are then written without quotes, e.g. {imagePullPolicy: Always},
+// which is why it is opt-in for existing clients.
+func WithEnums() Option {
+	return func(g *Gateway) {
+		g.enums = true
+	}
+}
+
+// enumType returns the GraphQL enum of a string field restricted to enum values. ok is false if the field is not an
+// enum, or if its values can't be represented as GraphQL enum values, in which case the field stays a String.
+func (g *Gateway) enumType(schema spec.Schema, typePrefix string, fieldPath []string) (*graphql.Enum, bool) {
+	if !g.enums || len(schema.Enum) == 0 || len(fieldPath) == 0 {
+		return nil, false
+	}
+
+	values := graphql.EnumValueConfigMap{}
+	for _, value := range schema.Enum {
+		str, ok := value.(string)
+		if !ok || str == "" {
+			return nil, false
+		}
+
+		name := sanitizeFieldName(str)
+		if _, exists := values[name]; exists || slices.Contains(reservedEnumValues, name) {
+			// Different values would be serialized to the same name
+			return nil, false
+		}
+		values[name] = &graphql.EnumValueConfig{Value: str}
+	}
+
+	// The prefix names the object type holding the field, e.g. Podspec for the restartPolicy of a Pod
+	typeName := sanitizeFieldName(g.generateTypeName(typePrefix, fieldPath[len(fieldPath)-1:])) + "Enum"
+	if existing, ok := g.enumsCache[typeName]; ok {
+		if !sameEnumValues(existing, values) {
+			g.log.Debug().Str("enum", typeName).Msg("Enum with the same name but different values, falling back to String")
+			return nil, false
+		}
+		return existing, true
+	}
+
+	enum := graphql.NewEnum(graphql.EnumConfig{
+		Name:        typeName,
+		Values:      values,
+		Description: g.schemaDescription(schema),
+	})
+	g.enumsCache[typeName] = enum
+
+	return enum, true
+}
+
+func sameEnumValues(enum *graphql.Enum, values graphql.EnumValueConfigMap) bool {
+	existing := enum.Values()
+	if len(existing) != len(values) {
+		return false
+	}
+
+	for _, value := range existing {
+		config, ok := values[value.Name]
+		if !ok || config.Value != value.Value {
+			return false
+		}
+	}
+
+	return true
+}
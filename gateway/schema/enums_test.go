@@ -0,0 +1,79 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_Enums(t *testing.T) {
+	enumProperty := func(values ...interface{}) spec.Schema {
+		property := *spec.StringProperty()
+		property.Enum = values
+		return property
+	}
+
+	pod := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"restartPolicy": enumProperty("Always", "OnFailure", "Never"),
+							"dnsPolicy":     enumProperty("cluster-first", "cluster_first"),
+							"reserved":      enumProperty("true", "false"),
+							"hostname":      *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+	pod.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"},
+	})
+	pod.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions := spec.Definitions{"io.k8s.api.core.v1.Pod": pod}
+
+	specFields := func(t *testing.T, opts ...schema.Option) graphql.FieldDefinitionMap {
+		log := testlogger.New().HideLogOutput().Logger
+		g, err := schema.New(log, definitions, resolver.New(log, nil), opts...)
+		require.NoError(t, err)
+
+		podType, ok := g.GetSchema().Type("Pod").(*graphql.Object)
+		require.True(t, ok)
+		specType, ok := podType.Fields()["spec"].Type.(*graphql.Object)
+		require.True(t, ok)
+		return specType.Fields()
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		fields := specFields(t, schema.WithEnums())
+
+		restartPolicy, ok := fields["restartPolicy"].Type.(*graphql.Enum)
+		require.True(t, ok, "restartPolicy is an enum")
+		assert.Equal(t, "PodspecrestartPolicyEnum", restartPolicy.Name())
+		assert.Equal(t, "OnFailure", restartPolicy.Serialize("OnFailure"))
+		assert.Equal(t, "Never", restartPolicy.ParseValue("Never"))
+		assert.Nil(t, restartPolicy.Serialize("Unknown"))
+
+		assert.Equal(t, graphql.String, fields["dnsPolicy"].Type, "values that collide when sanitized stay a String")
+		assert.Equal(t, graphql.String, fields["reserved"].Type, "reserved names stay a String")
+		assert.Equal(t, graphql.String, fields["hostname"].Type)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fields := specFields(t)
+		assert.Equal(t, graphql.String, fields["restartPolicy"].Type)
+	})
+}
@@ -0,0 +1,52 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const eventsField = "events"
+
+// WithEvents adds the events field to every resource type, listing the events about the object
+func WithEvents() Option {
+	return func(g *Gateway) {
+		g.events = true
+	}
+}
+
+// addEventsFields adds the events field to the types of all kinds except the events themselves. The events of the
+// core API are preferred, the events.k8s.io API is used for clusters serving only the latter.
+func (g *Gateway) addEventsFields() {
+	if !g.events {
+		return
+	}
+
+	eventGVK := resolver.EventGVK
+	eventType, ok := g.resourceTypes[eventGVK]
+	if !ok {
+		eventGVK = resolver.EventsV1GVK
+		if eventType, ok = g.resourceTypes[eventGVK]; !ok {
+			g.log.Debug().Msg("Skipping events fields, events are not part of the schema")
+			return
+		}
+	}
+
+	args := resolver.NewFieldConfigArguments().WithObjectEvents().Complete()
+	for gvk, resourceType := range g.resourceTypes {
+		if gvk == resolver.EventGVK || gvk == resolver.EventsV1GVK {
+			continue
+		}
+		if _, exists := resourceType.fields[eventsField]; exists {
+			g.log.Debug().Str("kind", gvk.Kind).Msg("Skipping events field, the kind has a field of that name")
+			continue
+		}
+
+		resourceType.object.AddFieldConfig(eventsField, &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(eventType.object))),
+			Args:        args,
+			Resolve:     g.resolver.ObjectEvents(gvk, eventGVK),
+			Description: "Events about this object, the most recent first",
+		})
+	}
+}
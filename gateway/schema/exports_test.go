@@ -4,6 +4,7 @@ import "k8s.io/apimachinery/pkg/runtime/schema"
 
 var StringMapScalarForTest = stringMapScalar
 var JSONStringScalarForTest = jsonStringScalar
+var Base64StringMapScalarForTest = base64StringMapScalar
 
 func GetGatewayForTest(typeNameRegistry map[string]string) *Gateway {
 	return &Gateway{
@@ -22,3 +23,7 @@ func (g *Gateway) GenerateTypeNameForTest(typePrefix string, fieldPath []string)
 func SanitizeFieldNameForTest(name string) string {
 	return sanitizeFieldName(name)
 }
+
+func SanitizeDescriptionForTest(description string, maxLength int) string {
+	return sanitizeDescription(description, maxLength)
+}
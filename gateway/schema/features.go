@@ -0,0 +1,54 @@
+package schema
+
+import (
+	"github.com/go-openapi/spec"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// WithoutMutations leaves the root mutation type out of the schema, so that the cluster can only be queried
+func WithoutMutations() Option {
+	return func(g *Gateway) {
+		g.withoutMutations = true
+	}
+}
+
+// WithoutSubscriptions leaves the root subscription type out of the schema
+func WithoutSubscriptions() Option {
+	return func(g *Gateway) {
+		g.withoutSubscriptions = true
+	}
+}
+
+// WithDeniedKinds leaves the kinds out of the schema, in all versions if the version of a kind is empty
+func WithDeniedKinds(kinds []schema.GroupVersionKind) Option {
+	return func(g *Gateway) {
+		g.deniedKinds = kinds
+	}
+}
+
+// filterDeniedKinds removes the definitions of the denied kinds. Definitions without a group version kind, e.g.
+// ObjectMeta, are kept since other resources reference them.
+func (g *Gateway) filterDeniedKinds(definitions spec.Definitions) spec.Definitions {
+	filtered := make(spec.Definitions, len(definitions))
+	for key, definition := range definitions {
+		gvk, ok := common.GroupVersionKind(definition.Extensions)
+		if ok && g.denied(gvk) {
+			g.log.Debug().Str("group", gvk.Group).Str("version", gvk.Version).Str("kind", gvk.Kind).Msg("Leaving denied kind out of the schema")
+			continue
+		}
+		filtered[key] = definition
+	}
+
+	return filtered
+}
+
+func (g *Gateway) denied(gvk schema.GroupVersionKind) bool {
+	for _, denied := range g.deniedKinds {
+		if denied.Group == gvk.Group && denied.Kind == gvk.Kind && (denied.Version == "" || denied.Version == gvk.Version) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,370 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+// federationLink declares the schema as an Apollo Federation v2 subgraph
+const federationLink = `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`
+
+// Option configures the generated schema
+type Option func(*Gateway)
+
+// WithFederation serves the schema as an Apollo Federation v2 subgraph.
+// Resources become entities keyed by their name and namespace, and the _service and _entities queries are added.
+func WithFederation() Option {
+	return func(g *Gateway) {
+		g.federation = true
+	}
+}
+
+// federationEntity is a resource type that can be referenced from other subgraphs
+type federationEntity struct {
+	object *graphql.Object
+	// gvk is the GroupVersionKind with the GraphQL group, as passed to the resolvers
+	gvk   schema.GroupVersionKind
+	scope apiextensionsv1.ResourceScope
+	// apiGroupVersionKind identifies objects returned by the API server
+	apiGroupVersionKind schema.GroupVersionKind
+}
+
+// keyFields returns the fields of the @key directive of the entity
+func (e federationEntity) keyFields() string {
+	if e.scope == apiextensionsv1.NamespaceScoped {
+		return "metadata { name namespace }"
+	}
+	return "metadata { name }"
+}
+
+// addFederationEntity registers the resource type as entity if its metadata contains the key fields
+func (g *Gateway) addFederationEntity(resourceType *graphql.Object, fields graphql.Fields, gvk schema.GroupVersionKind, apiGroup string, scope apiextensionsv1.ResourceScope) {
+	if !g.federation {
+		return
+	}
+
+	metadata, ok := fields["metadata"]
+	if !ok {
+		return
+	}
+	metadataType, ok := metadata.Type.(*graphql.Object)
+	if !ok {
+		return
+	}
+	metadataFields := metadataType.Fields()
+	if _, ok := metadataFields["name"]; !ok {
+		return
+	}
+	if _, ok := metadataFields["namespace"]; !ok && scope == apiextensionsv1.NamespaceScoped {
+		return
+	}
+
+	g.federationEntities = append(g.federationEntities, federationEntity{
+		object:              resourceType,
+		gvk:                 gvk,
+		scope:               scope,
+		apiGroupVersionKind: schema.GroupVersionKind{Group: apiGroup, Version: gvk.Version, Kind: gvk.Kind},
+	})
+}
+
+// addFederationQueries adds the _service and _entities queries of the Apollo Federation subgraph specification.
+// sdl is the schema without these queries, annotated with the federation directives.
+func (g *Gateway) addFederationQueries(queryType *graphql.Object, sdl string) {
+	serviceType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "_Service",
+		Fields: graphql.Fields{
+			"sdl": &graphql.Field{Type: graphql.String},
+		},
+	})
+
+	queryType.AddFieldConfig("_service", &graphql.Field{
+		Type: graphql.NewNonNull(serviceType),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"sdl": sdl}, nil
+		},
+	})
+
+	// The _Entity union can't be empty, so _entities is only served if there is at least one entity
+	if len(g.federationEntities) == 0 {
+		return
+	}
+
+	byName := make(map[string]federationEntity, len(g.federationEntities))
+	byAPIGroupVersionKind := make(map[schema.GroupVersionKind]*graphql.Object, len(g.federationEntities))
+	types := make([]*graphql.Object, 0, len(g.federationEntities))
+	for _, entity := range g.federationEntities {
+		byName[entity.object.Name()] = entity
+		byAPIGroupVersionKind[entity.apiGroupVersionKind] = entity.object
+		types = append(types, entity.object)
+	}
+
+	entityType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "_Entity",
+		Types: types,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			obj, ok := p.Value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			apiVersion, _ := obj["apiVersion"].(string)
+			kind, _ := obj["kind"].(string)
+			return byAPIGroupVersionKind[schema.FromAPIVersionAndKind(apiVersion, kind)]
+		},
+	})
+
+	queryType.AddFieldConfig("_entities", &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewList(entityType)),
+		Args: graphql.FieldConfigArgument{
+			"representations": &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(anyScalar))),
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			representations, _ := p.Args["representations"].([]interface{})
+			result := make([]interface{}, 0, len(representations))
+			for _, representation := range representations {
+				obj, err := g.resolveEntity(p, byName, representation)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, obj)
+			}
+			return result, nil
+		},
+	})
+}
+
+// resolveEntity gets the object referenced by the representation, objects that don't exist are resolved to null
+func (g *Gateway) resolveEntity(p graphql.ResolveParams, byName map[string]federationEntity, representation interface{}) (interface{}, error) {
+	fields, ok := representation.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid entity representation: %v", representation)
+	}
+
+	typeName, _ := fields["__typename"].(string)
+	entity, ok := byName[typeName]
+	if !ok {
+		return nil, fmt.Errorf("unknown entity type %q", typeName)
+	}
+
+	metadata, _ := fields["metadata"].(map[string]interface{})
+	args := map[string]interface{}{resolver.NameArg: metadata["name"]}
+	if entity.scope == apiextensionsv1.NamespaceScoped {
+		args[resolver.NamespaceArg] = metadata["namespace"]
+	}
+
+	obj, err := g.resolver.GetItem(entity.gvk, entity.scope)(graphql.ResolveParams{Context: p.Context, Args: args, Info: p.Info})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+
+	return obj, err
+}
+
+// anyScalar is the _Any scalar of the Apollo Federation specification, which holds entity representations
+var anyScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name: "_Any",
+	Serialize: func(value interface{}) interface{} {
+		return value
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return value
+	},
+	ParseLiteral: parseAnyLiteral,
+})
+
+// parseAnyLiteral converts an inline value of the query into its Go representation
+func parseAnyLiteral(valueAST ast.Value) interface{} {
+	switch value := valueAST.(type) {
+	case *ast.ObjectValue:
+		result := make(map[string]interface{}, len(value.Fields))
+		for _, field := range value.Fields {
+			result[field.Name.Value] = parseAnyLiteral(field.Value)
+		}
+		return result
+	case *ast.ListValue:
+		result := make([]interface{}, 0, len(value.Values))
+		for _, item := range value.Values {
+			result = append(result, parseAnyLiteral(item))
+		}
+		return result
+	case *ast.IntValue:
+		return graphql.Int.ParseLiteral(value)
+	case *ast.FloatValue:
+		return graphql.Float.ParseLiteral(value)
+	default:
+		return valueAST.GetValue()
+	}
+}
+
+// printFederationSDL prints the schema in the SDL format expected from subgraphs, with @key directives on entities.
+// Fields starting with "__" are omitted, as they are reserved for introspection and rejected during composition.
+func printFederationSDL(s graphql.Schema, entities []federationEntity) string {
+	keys := make(map[string]string, len(entities))
+	for _, entity := range entities {
+		keys[entity.object.Name()] = entity.keyFields()
+	}
+
+	var sb strings.Builder
+	sb.WriteString(federationLink + "\n\n")
+
+	sb.WriteString("schema {\n")
+	sb.WriteString(fmt.Sprintf("  query: %s\n", s.QueryType().Name()))
+	if s.MutationType() != nil {
+		sb.WriteString(fmt.Sprintf("  mutation: %s\n", s.MutationType().Name()))
+	}
+	if s.SubscriptionType() != nil {
+		sb.WriteString(fmt.Sprintf("  subscription: %s\n", s.SubscriptionType().Name()))
+	}
+	sb.WriteString("}\n")
+
+	typeMap := s.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") || isBuiltInScalar(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		sb.WriteString("\n")
+		printType(&sb, typeMap[name], keys[name])
+	}
+
+	return sb.String()
+}
+
+func isBuiltInScalar(name string) bool {
+	return slices.Contains([]string{"String", "Int", "Float", "Boolean", "ID"}, name)
+}
+
+func printType(sb *strings.Builder, t graphql.Type, keyFields string) {
+	printDescription(sb, t.Description(), "")
+
+	switch t := t.(type) {
+	case *graphql.Scalar:
+		sb.WriteString(fmt.Sprintf("scalar %s\n", t.Name()))
+	case *graphql.Enum:
+		sb.WriteString(fmt.Sprintf("enum %s {\n", t.Name()))
+		for _, value := range t.Values() {
+			printDescription(sb, value.Description, "  ")
+			sb.WriteString("  " + value.Name + deprecated(value.DeprecationReason) + "\n")
+		}
+		sb.WriteString("}\n")
+	case *graphql.Union:
+		types := make([]string, 0, len(t.Types()))
+		for _, member := range t.Types() {
+			types = append(types, member.Name())
+		}
+		sb.WriteString(fmt.Sprintf("union %s = %s\n", t.Name(), strings.Join(types, " | ")))
+	case *graphql.InputObject:
+		sb.WriteString(fmt.Sprintf("input %s {\n", t.Name()))
+		fields := t.Fields()
+		for _, name := range sortedKeys(fields) {
+			field := fields[name]
+			printDescription(sb, field.Description(), "  ")
+			sb.WriteString(fmt.Sprintf("  %s: %s%s\n", name, field.Type.String(), defaultValue(field.DefaultValue, field.Type)))
+		}
+		sb.WriteString("}\n")
+	case *graphql.Interface:
+		sb.WriteString(fmt.Sprintf("interface %s {\n", t.Name()))
+		printFields(sb, t.Fields())
+		sb.WriteString("}\n")
+	case *graphql.Object:
+		sb.WriteString("type " + t.Name())
+		if len(t.Interfaces()) > 0 {
+			interfaces := make([]string, 0, len(t.Interfaces()))
+			for _, iface := range t.Interfaces() {
+				interfaces = append(interfaces, iface.Name())
+			}
+			sb.WriteString(" implements " + strings.Join(interfaces, " & "))
+		}
+		if keyFields != "" {
+			sb.WriteString(fmt.Sprintf(" @key(fields: %q)", keyFields))
+		}
+		sb.WriteString(" {\n")
+		printFields(sb, t.Fields())
+		sb.WriteString("}\n")
+	}
+}
+
+func printFields(sb *strings.Builder, fields graphql.FieldDefinitionMap) {
+	for _, name := range sortedKeys(fields) {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		field := fields[name]
+		printDescription(sb, field.Description, "  ")
+		sb.WriteString("  " + name)
+		if len(field.Args) > 0 {
+			// Arguments are configured as map, so their order is only stable if sorted
+			args := make([]string, 0, len(field.Args))
+			for _, arg := range field.Args {
+				args = append(args, fmt.Sprintf("%s: %s%s", arg.Name(), arg.Type.String(), defaultValue(arg.DefaultValue, arg.Type)))
+			}
+			sort.Strings(args)
+			sb.WriteString("(" + strings.Join(args, ", ") + ")")
+		}
+		sb.WriteString(fmt.Sprintf(": %s%s\n", field.Type.String(), deprecated(field.DeprecationReason)))
+	}
+}
+
+func printDescription(sb *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	sb.WriteString(indent + `"""` + strings.ReplaceAll(description, `"""`, `\"""`) + `"""` + "\n")
+}
+
+func deprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" @deprecated(reason: %q)", reason)
+}
+
+// defaultValue prints the default value of an argument or input field, enum values are printed by name
+func defaultValue(value interface{}, t graphql.Input) string {
+	if value == nil {
+		return ""
+	}
+
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	if enum, ok := t.(*graphql.Enum); ok {
+		for _, enumValue := range enum.Values() {
+			if enumValue.Value == value {
+				return " = " + enumValue.Name
+			}
+		}
+	}
+
+	literal, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return " = " + string(literal)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,119 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func federationDefinitions() spec.Definitions {
+	objectMeta := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"name":      *spec.StringProperty(),
+				"namespace": *spec.StringProperty(),
+			},
+		},
+	}
+
+	configMap := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"metadata": *spec.RefSchema("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+				"data":     *spec.MapProperty(spec.StringProperty()),
+			},
+		},
+	}
+	configMap.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "ConfigMap"},
+	})
+	configMap.AddExtension(common.ScopeExtensionKey, "Namespaced")
+
+	return spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": objectMeta,
+		"io.k8s.api.core.v1.ConfigMap":                    configMap,
+	}
+}
+
+func TestNew_Federation(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+	runtimeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"theme": "dark"},
+	}).Build()
+
+	g, err := schema.New(log, federationDefinitions(), resolver.New(log, runtimeClient), schema.WithFederation())
+	require.NoError(t, err)
+
+	t.Run("service_sdl", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{Schema: *g.GetSchema(), RequestString: `{ _service { sdl } }`})
+		require.Empty(t, result.Errors)
+
+		sdl := result.Data.(map[string]interface{})["_service"].(map[string]interface{})["sdl"].(string)
+		assert.Contains(t, sdl, `extend schema @link(url: "https://specs.apollo.dev/federation/v2.0", import: ["@key"])`)
+		assert.Contains(t, sdl, "  query: PrivateNameForQuery\n")
+		assert.Contains(t, sdl, `type ConfigMap @key(fields: "metadata { name namespace }") {`)
+		assert.Contains(t, sdl, "  ConfigMap(name: String!, namespace: String): ConfigMap!\n")
+		assert.NotContains(t, sdl, "_service")
+		assert.Contains(t, sdl, "  clusterDefaults: ClusterDefaults!\n")
+	})
+
+	t.Run("entities", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:  *g.GetSchema(),
+			Context: t.Context(),
+			RequestString: `{
+				_entities(representations: [
+					{__typename: "ConfigMap", metadata: {name: "settings", namespace: "default"}},
+					{__typename: "ConfigMap", metadata: {name: "missing", namespace: "default"}}
+				]) {
+					... on ConfigMap { metadata { name } data }
+				}
+			}`,
+		})
+		require.Empty(t, result.Errors)
+
+		assert.Equal(t, map[string]interface{}{
+			"_entities": []interface{}{
+				map[string]interface{}{
+					"metadata": map[string]interface{}{"name": "settings"},
+					"data":     map[string]interface{}{"theme": "dark"},
+				},
+				nil,
+			},
+		}, result.Data)
+	})
+
+	t.Run("unknown_entity_type_ERROR", func(t *testing.T) {
+		result := graphql.Do(graphql.Params{
+			Schema:         *g.GetSchema(),
+			RequestString:  `query($representations: [_Any!]!) { _entities(representations: $representations) { __typename } }`,
+			VariableValues: map[string]interface{}{"representations": []interface{}{map[string]interface{}{"__typename": "Unknown"}}},
+		})
+		require.Len(t, result.Errors, 1)
+		assert.Contains(t, result.Errors[0].Message, `unknown entity type "Unknown"`)
+	})
+}
+
+func TestNew_WithoutFederation(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, federationDefinitions(), resolver.New(log, nil))
+	require.NoError(t, err)
+
+	queryFields := g.GetSchema().QueryType().Fields()
+	assert.NotContains(t, queryFields, "_service")
+	assert.NotContains(t, queryFields, "_entities")
+}
@@ -0,0 +1,26 @@
+package schema
+
+import "strings"
+
+// isInputOnlyField tells if the field at fieldPath of the resource that is currently generated is input-only
+func (g *Gateway) isInputOnlyField(fieldPath []string) bool {
+	path := strings.Join(fieldPath, ".")
+	for _, inputOnly := range g.inputOnlyFields {
+		if path == inputOnly {
+			return true
+		}
+	}
+	return false
+}
+
+// hasInputOnlyFieldsBelow tells if the object at fieldPath contains input-only fields,
+// in which case its output type differs from the one of other resources with the same definition
+func (g *Gateway) hasInputOnlyFieldsBelow(fieldPath []string) bool {
+	prefix := strings.Join(fieldPath, ".") + "."
+	for _, inputOnly := range g.inputOnlyFields {
+		if strings.HasPrefix(inputOnly, prefix) {
+			return true
+		}
+	}
+	return false
+}
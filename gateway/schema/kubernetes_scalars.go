@@ -0,0 +1,203 @@
+package schema
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// Definitions of the Kubernetes API that are generated as dedicated scalars
+const (
+	quantityDefinition    = "io.k8s.apimachinery.pkg.api.resource.Quantity"
+	timeDefinition        = "io.k8s.apimachinery.pkg.apis.meta.v1.Time"
+	microTimeDefinition   = "io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime"
+	intOrStringDefinition = "io.k8s.apimachinery.pkg.util.intstr.IntOrString"
+)
+
+// rfc3339Micro is the format of metav1.MicroTime
+const rfc3339Micro = "2006-01-02T15:04:05.000000Z07:00"
+
+// WithKubernetesScalars generates Quantity, Time, MicroTime and IntOrString scalars for the corresponding Kubernetes
+// types instead of String. It is opt-in, since variables of existing operations declared as String don't match them.
+func WithKubernetesScalars() Option {
+	return func(g *Gateway) {
+		g.kubernetesScalars = true
+	}
+}
+
+// kubernetesScalar returns the dedicated scalar of a field referring to refKey or having the schema, if there is one
+func (g *Gateway) kubernetesScalar(schema spec.Schema, refKey string) (*graphql.Scalar, bool) {
+	if !g.kubernetesScalars {
+		return nil, false
+	}
+
+	switch refKey {
+	case quantityDefinition:
+		return quantityScalar, true
+	case timeDefinition:
+		return timeScalar, true
+	case microTimeDefinition:
+		return microTimeScalar, true
+	case intOrStringDefinition:
+		return intOrStringScalar, true
+	}
+
+	// CRDs declare these types inline
+	if intOrString, _ := schema.Extensions[common.IntOrStringExtensionKey].(bool); intOrString {
+		return intOrStringScalar, true
+	}
+	if len(schema.Type) == 1 && schema.Type[0] == "string" && schema.Format == "date-time" {
+		return timeScalar, true
+	}
+
+	return nil, false
+}
+
+// quantityScalar is a resource.Quantity like 500m or 1Gi. Values are returned in canonical form, inputs may also be numbers.
+var quantityScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Quantity",
+	Description: "A Kubernetes resource quantity like 500m or 1Gi, in canonical form.",
+	Serialize:   serializeQuantity,
+	ParseValue:  serializeQuantity,
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch value := valueAST.(type) {
+		case *ast.StringValue:
+			return serializeQuantity(value.Value)
+		case *ast.IntValue:
+			return serializeQuantity(value.Value)
+		case *ast.FloatValue:
+			return serializeQuantity(value.Value)
+		default:
+			return nil // to tell GraphQL that the value is invalid
+		}
+	},
+})
+
+func serializeQuantity(value interface{}) interface{} {
+	var str string
+	switch val := value.(type) {
+	case string:
+		str = val
+	case int:
+		str = strconv.Itoa(val)
+	case int64:
+		str = strconv.FormatInt(val, 10)
+	case float64:
+		str = strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		return nil
+	}
+
+	quantity, err := resource.ParseQuantity(strings.TrimSpace(str))
+	if err != nil {
+		return nil
+	}
+	return quantity.String()
+}
+
+// timeScalar is a metav1.Time or a CRD date-time field. Values are RFC 3339 timestamps in UTC.
+var timeScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Time",
+	Description: "An RFC 3339 timestamp like 2006-01-02T15:04:05Z, in UTC.",
+	Serialize: func(value interface{}) interface{} {
+		return formatTime(value, time.RFC3339Nano)
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return formatTime(value, time.RFC3339Nano)
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if value, ok := valueAST.(*ast.StringValue); ok {
+			return formatTime(value.Value, time.RFC3339Nano)
+		}
+		return nil
+	},
+})
+
+// microTimeScalar is a metav1.MicroTime, which the API server only accepts with exactly six fractional digits
+var microTimeScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "MicroTime",
+	Description: "An RFC 3339 timestamp with microseconds like 2006-01-02T15:04:05.000000Z, in UTC.",
+	Serialize: func(value interface{}) interface{} {
+		return formatTime(value, rfc3339Micro)
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return formatTime(value, rfc3339Micro)
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		if value, ok := valueAST.(*ast.StringValue); ok {
+			return formatTime(value.Value, rfc3339Micro)
+		}
+		return nil
+	},
+})
+
+// formatTime returns the timestamp in UTC in the layout, nil if it is not an RFC 3339 timestamp
+func formatTime(value interface{}, layout string) interface{} {
+	var t time.Time
+	switch val := value.(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(val))
+		if err != nil {
+			return nil
+		}
+		t = parsed
+	case time.Time:
+		t = val
+	default:
+		return nil
+	}
+
+	return t.UTC().Format(layout)
+}
+
+// intOrStringScalar is an intstr.IntOrString or a CRD field with x-kubernetes-int-or-string, like the port of a probe.
+// Integers stay integers and strings stay strings, so values are passed to the API server the way they were sent.
+var intOrStringScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "IntOrString",
+	Description: "An integer or a string, like a port number or name.",
+	Serialize:   serializeIntOrString,
+	ParseValue:  serializeIntOrString,
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		switch value := valueAST.(type) {
+		case *ast.StringValue:
+			return value.Value
+		case *ast.IntValue:
+			i, err := strconv.ParseInt(value.Value, 10, 64)
+			if err != nil {
+				return nil
+			}
+			return i
+		default:
+			return nil // to tell GraphQL that the value is invalid
+		}
+	},
+})
+
+func serializeIntOrString(value interface{}) interface{} {
+	switch val := value.(type) {
+	case string:
+		return val
+	case int:
+		return int64(val)
+	case int32:
+		return int64(val)
+	case int64:
+		return val
+	case float64:
+		// Variables and JSON decoded objects hold numbers as float64
+		if val != math.Trunc(val) || math.IsInf(val, 0) {
+			return nil
+		}
+		return int64(val)
+	default:
+		return nil
+	}
+}
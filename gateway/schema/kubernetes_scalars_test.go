@@ -0,0 +1,140 @@
+package schema_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_KubernetesScalars(t *testing.T) {
+	ref := func(definition string) spec.Schema {
+		return *spec.RefProperty("#/definitions/" + definition)
+	}
+	intOrString := spec.Schema{}
+	intOrString.AddExtension(common.IntOrStringExtensionKey, true)
+
+	pod := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"cpu":          ref("io.k8s.apimachinery.pkg.api.resource.Quantity"),
+							"startTime":    ref("io.k8s.apimachinery.pkg.apis.meta.v1.Time"),
+							"renewTime":    ref("io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime"),
+							"port":         ref("io.k8s.apimachinery.pkg.util.intstr.IntOrString"),
+							"crdPort":      intOrString,
+							"crdTimestamp": *spec.DateTimeProperty(),
+							"hostname":     *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+	pod.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"},
+	})
+	pod.AddExtension(common.ScopeExtensionKey, "Namespaced")
+
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.Pod": pod,
+		"io.k8s.apimachinery.pkg.api.resource.Quantity": {SchemaProps: spec.SchemaProps{
+			OneOf: []spec.Schema{*spec.StringProperty(), *spec.Float64Property()},
+		}},
+		"io.k8s.apimachinery.pkg.apis.meta.v1.Time":      *spec.DateTimeProperty(),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.MicroTime": *spec.DateTimeProperty(),
+		"io.k8s.apimachinery.pkg.util.intstr.IntOrString": {SchemaProps: spec.SchemaProps{
+			Format: "int-or-string",
+			OneOf:  []spec.Schema{*spec.Int64Property(), *spec.StringProperty()},
+		}},
+	}
+
+	specFields := func(t *testing.T, opts ...schema.Option) graphql.FieldDefinitionMap {
+		log := testlogger.New().HideLogOutput().Logger
+		g, err := schema.New(log, definitions, resolver.New(log, nil), opts...)
+		require.NoError(t, err)
+
+		podType, ok := g.GetSchema().Type("Pod").(*graphql.Object)
+		require.True(t, ok)
+		specType, ok := podType.Fields()["spec"].Type.(*graphql.Object)
+		require.True(t, ok)
+		return specType.Fields()
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		fields := specFields(t, schema.WithKubernetesScalars())
+
+		expected := map[string]string{
+			"cpu":          "Quantity",
+			"startTime":    "Time",
+			"renewTime":    "MicroTime",
+			"port":         "IntOrString",
+			"crdPort":      "IntOrString",
+			"crdTimestamp": "Time",
+			"hostname":     "String",
+		}
+		for field, typeName := range expected {
+			assert.Equal(t, typeName, fields[field].Type.Name(), field)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		fields := specFields(t)
+		for _, field := range []string{"cpu", "startTime", "renewTime", "port", "crdPort", "crdTimestamp"} {
+			assert.Equal(t, graphql.String, fields[field].Type, field)
+		}
+	})
+
+	fields := specFields(t, schema.WithKubernetesScalars())
+	scalar := func(field string) *graphql.Scalar {
+		s, ok := fields[field].Type.(*graphql.Scalar)
+		require.True(t, ok)
+		return s
+	}
+
+	t.Run("quantity", func(t *testing.T) {
+		quantity := scalar("cpu")
+		assert.Equal(t, "1536Mi", quantity.Serialize("1.5Gi"))
+		assert.Equal(t, "1500m", quantity.ParseValue(1.5))
+		assert.Equal(t, "2", quantity.ParseValue(2))
+		assert.Equal(t, "500m", quantity.ParseLiteral(&ast.StringValue{Value: "500m"}))
+		assert.Equal(t, "4", quantity.ParseLiteral(&ast.IntValue{Value: "4"}))
+		assert.Nil(t, quantity.ParseValue("1 core"))
+		assert.Nil(t, quantity.ParseLiteral(&ast.BooleanValue{Value: true}))
+	})
+
+	t.Run("time", func(t *testing.T) {
+		timestamp := scalar("startTime")
+		assert.Equal(t, "2024-05-01T10:00:00Z", timestamp.Serialize("2024-05-01T10:00:00Z"))
+		assert.Equal(t, "2024-05-01T08:00:00.5Z", timestamp.ParseValue("2024-05-01T10:00:00.5+02:00"))
+		assert.Equal(t, "2024-05-01T10:00:00Z", timestamp.Serialize(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)))
+		assert.Nil(t, timestamp.ParseLiteral(&ast.StringValue{Value: "yesterday"}))
+
+		microTime := scalar("renewTime")
+		assert.Equal(t, "2024-05-01T10:00:00.500000Z", microTime.ParseLiteral(&ast.StringValue{Value: "2024-05-01T10:00:00.5Z"}))
+	})
+
+	t.Run("int_or_string", func(t *testing.T) {
+		port := scalar("port")
+		assert.Equal(t, int64(8080), port.Serialize(int64(8080)))
+		assert.Equal(t, "http", port.Serialize("http"))
+		assert.Equal(t, int64(8080), port.ParseValue(float64(8080)))
+		assert.Nil(t, port.ParseValue(80.5))
+		assert.Equal(t, int64(8080), port.ParseLiteral(&ast.IntValue{Value: "8080"}))
+		assert.Equal(t, "8080", port.ParseLiteral(&ast.StringValue{Value: "8080"}), "strings stay strings")
+		assert.Nil(t, port.ParseLiteral(&ast.FloatValue{Value: "1.5"}))
+	})
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const leaderOf = "leaderOf"
+
+// addLeaseFields adds computed fields for inspecting leader election to the coordination.k8s.io/v1 Lease type
+func (g *Gateway) addLeaseFields(fields graphql.Fields, apiGVK schema.GroupVersionKind) {
+	if apiGVK != resolver.LeaseGVK {
+		return
+	}
+
+	fields["holder"] = &graphql.Field{
+		Type:        graphql.String,
+		Description: "Identity of the current holder of the lease",
+		Resolve:     resolver.LeaseHolderResolver(),
+	}
+	fields["renewedSecondsAgo"] = &graphql.Field{
+		Type:        graphql.Int,
+		Description: "Seconds since the holder renewed the lease the last time",
+		Resolve:     resolver.LeaseRenewedSecondsAgoResolver(),
+	}
+	fields["expired"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Boolean),
+		Description: "Whether the lease has no holder or was not renewed within its duration",
+		Resolve:     resolver.LeaseExpiredResolver(),
+	}
+}
+
+// AddLeaderOfQuery adds the query locating the leader election lease of a deployment, if leases are part of the schema
+func (g *Gateway) AddLeaderOfQuery(rootQueryFields graphql.Fields) {
+	if g.leaseType == nil {
+		return
+	}
+
+	rootQueryFields[leaderOf] = &graphql.Field{
+		Type: g.leaseType,
+		Args: graphql.FieldConfigArgument{
+			resolver.DeploymentArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Name of the deployment running the controller",
+			},
+			resolver.NamespaceArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Namespace of the deployment and its lease",
+			},
+		},
+		Resolve:     g.resolver.LeaderOf(),
+		Description: "The leader election lease held by a pod of the deployment, or the lease named like the deployment",
+	}
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const (
+	ownsField    = "owns"
+	ownedByField = "ownedBy"
+)
+
+// WithOwnerReferences adds owns fields to the owner kinds of relations, listing the owned objects of the child kinds,
+// and ownedBy fields to the child kinds, getting the owners of their ownerReferences
+func WithOwnerReferences(relations []resolver.OwnerRelation) Option {
+	return func(g *Gateway) {
+		g.ownerRelations = relations
+	}
+}
+
+// addOwnerReferenceFields adds the owns and ownedBy fields of the relations whose owner and child kinds are part of the
+// schema. The fields are added once all resource types exist, since owners and children refer to each other.
+func (g *Gateway) addOwnerReferenceFields() {
+	children := make(map[schema.GroupVersionKind][]schema.GroupVersionKind)
+	owners := make(map[schema.GroupVersionKind][]schema.GroupVersionKind)
+	for _, relation := range g.ownerRelations {
+		_, ownerExists := g.resourceTypes[relation.Owner]
+		_, childExists := g.resourceTypes[relation.Child]
+		if !ownerExists || !childExists {
+			continue
+		}
+		if !slices.Contains(children[relation.Owner], relation.Child) {
+			children[relation.Owner] = append(children[relation.Owner], relation.Child)
+		}
+		if !slices.Contains(owners[relation.Child], relation.Owner) {
+			owners[relation.Child] = append(owners[relation.Child], relation.Owner)
+		}
+	}
+
+	for owner, childKinds := range children {
+		g.addOwnsField(owner, childKinds)
+	}
+	for child, ownerKinds := range owners {
+		g.addOwnedByField(child, ownerKinds)
+	}
+}
+
+// addOwnsField adds the owns field to the type of owner, holding a list of the owned objects per child kind
+func (g *Gateway) addOwnsField(owner schema.GroupVersionKind, childKinds []schema.GroupVersionKind) {
+	ownerType := g.resourceTypes[owner]
+	if _, exists := ownerType.fields[ownsField]; exists {
+		g.log.Debug().Str("kind", owner.Kind).Msg("Skipping owns field, the kind has a field of that name")
+		return
+	}
+
+	fields := graphql.Fields{}
+	for _, child := range childKinds {
+		childType := g.resourceTypes[child]
+		fields[childType.plural] = &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(childType.object))),
+			Resolve:     g.resolver.Owns(child),
+			Description: fmt.Sprintf("%s objects whose ownerReferences refer to the %s", child.Kind, owner.Kind),
+		}
+	}
+
+	ownerType.object.AddFieldConfig(ownsField, &graphql.Field{
+		Type: graphql.NewNonNull(graphql.NewObject(graphql.ObjectConfig{
+			Name:   ownerType.object.Name() + "Owns",
+			Fields: fields,
+		})),
+		Resolve:     g.resolver.CommonResolver(),
+		Description: "Objects owned by this object, per kind",
+	})
+}
+
+// addOwnedByField adds the ownedBy field to the type of child, a list of a union of the owner kinds
+func (g *Gateway) addOwnedByField(child schema.GroupVersionKind, ownerKinds []schema.GroupVersionKind) {
+	childType := g.resourceTypes[child]
+	if _, exists := childType.fields[ownedByField]; exists {
+		g.log.Debug().Str("kind", child.Kind).Msg("Skipping ownedBy field, the kind has a field of that name")
+		return
+	}
+
+	types := make([]*graphql.Object, 0, len(ownerKinds))
+	for _, owner := range ownerKinds {
+		types = append(types, g.resourceTypes[owner].object)
+	}
+
+	ownersType := graphql.NewUnion(graphql.UnionConfig{
+		Name:  childType.object.Name() + "Owner",
+		Types: types,
+		ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+			object, ok := p.Value.(map[string]interface{})
+			if !ok {
+				return nil
+			}
+			apiVersion, _ := object["apiVersion"].(string)
+			kind, _ := object["kind"].(string)
+
+			return g.resourceTypes[schema.FromAPIVersionAndKind(apiVersion, kind)].object
+		},
+	})
+
+	childType.object.AddFieldConfig(ownedByField, &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(ownersType))),
+		Resolve:     g.resolver.OwnedBy(ownerKinds),
+		Description: "Owners of this object referred to by its ownerReferences",
+	})
+}
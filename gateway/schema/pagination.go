@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+)
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PageInfo",
+	Description: "How to get the next page of a paginated list",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"endCursor": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Pass as after to get the next page",
+		},
+		"remainingItemCount": &graphql.Field{
+			Type:        graphql.Int,
+			Description: "Estimated amount of objects after this page, only known for lists without label selector",
+		},
+		"resourceVersion": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Pass as resourceVersion with consistency EXACT or NOT_OLDER_THAN to read the same or a more recent state again",
+		},
+	},
+})
+
+// connectionType returns the type of a page of the paginated list query of a resource
+func connectionType(singular string, resourceType *graphql.Object) *graphql.Object {
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        singular + "Connection",
+		Description: "A page of " + singular + " objects",
+		Fields: graphql.Fields{
+			"items":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(resourceType)))},
+			"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+		},
+	})
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const (
+	canI             = "canI"
+	permissionsField = "permissions"
+)
+
+var resourcePermissionsType = newResourcePermissionsType()
+
+func newResourcePermissionsType() *graphql.Object {
+	fields := graphql.Fields{}
+	for _, verb := range resolver.PermissionVerbs {
+		fields[verb] = &graphql.Field{
+			Type:    graphql.NewNonNull(graphql.Boolean),
+			Resolve: resolver.PermissionResolver(verb),
+		}
+	}
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ResourcePermissions",
+		Description: "Whether the caller may perform the verbs on the object, each selected verb is checked with an access review",
+		Fields:      fields,
+	})
+}
+
+// WithPermissions adds the canI query and the permissions field of every resource type, see resolver.WithPermissions
+func WithPermissions() Option {
+	return func(g *Gateway) {
+		g.permissions = true
+	}
+}
+
+// addPermissionsField adds the permissions field to the fields of a resource type, unless the resource has a field
+// of that name
+func (g *Gateway) addPermissionsField(fields graphql.Fields, gvk schema.GroupVersionKind, scope apiextensionsv1.ResourceScope) {
+	if !g.permissions {
+		return
+	}
+	if _, exists := fields[permissionsField]; exists {
+		g.log.Debug().Str("kind", gvk.Kind).Msg("Skipping permissions field, the kind has a field of that name")
+		return
+	}
+
+	fields[permissionsField] = &graphql.Field{
+		Type:        graphql.NewNonNull(resourcePermissionsType),
+		Resolve:     g.resolver.Permissions(gvk, scope),
+		Description: "Whether the caller may perform the verbs on the object, e.g. to hide the actions of a UI the caller may not perform",
+	}
+}
+
+// AddCanIQuery adds the query reviewing whether the caller may perform a verb on a resource
+func (g *Gateway) AddCanIQuery(rootQueryFields graphql.Fields) {
+	if !g.permissions {
+		return
+	}
+
+	rootQueryFields[canI] = &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Boolean),
+		Args: graphql.FieldConfigArgument{
+			resolver.VerbArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Verb of the request, e.g. get, list, create, update, patch or delete",
+			},
+			resolver.ResourceArg: &graphql.ArgumentConfig{
+				Type:        graphql.NewNonNull(graphql.String),
+				Description: "Plural resource name of the kind, e.g. deployments",
+			},
+			resolver.GroupArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "API group of the resource, empty for the core group",
+			},
+			resolver.SubresourceArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "Subresource, e.g. status or scale",
+			},
+			resolver.NamespaceArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "Namespace of the request, all namespaces if not set",
+			},
+			resolver.NameArg: &graphql.ArgumentConfig{
+				Type:        graphql.String,
+				Description: "Name of the object, all objects if not set",
+			},
+		},
+		Resolve:     g.resolver.CanI(),
+		Description: "Whether the caller may perform the verb on the resource, like kubectl auth can-i",
+	}
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+var podGVK = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+var podProblemType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PodProblem",
+	Description: "A container of the pod that is not running as expected",
+	Fields: graphql.Fields{
+		"container":    graphqlStringField(),
+		"reason":       graphqlStringField(),
+		"message":      &graphql.Field{Type: graphql.String},
+		"restartCount": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+	},
+})
+
+// addPodDiagnosticFields adds computed troubleshooting fields derived from the container statuses to the core/v1 Pod type
+func (g *Gateway) addPodDiagnosticFields(fields graphql.Fields, gvk *schema.GroupVersionKind) {
+	if *gvk != podGVK {
+		return
+	}
+
+	fields["restartCount"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.Int),
+		Description: "Total number of restarts of all init and regular containers",
+		Resolve:     resolver.PodRestartCountResolver(),
+	}
+	fields["lastTerminationReason"] = &graphql.Field{
+		Type:        graphql.String,
+		Description: "Reason of the most recent container termination, e.g. OOMKilled",
+		Resolve:     resolver.PodLastTerminationReasonResolver(),
+	}
+	fields["waitingReason"] = &graphql.Field{
+		Type:        graphql.String,
+		Description: "Waiting reason of the first container that is not running yet, e.g. ImagePullBackOff",
+		Resolve:     resolver.PodWaitingReasonResolver(),
+	}
+	fields["problems"] = &graphql.Field{
+		Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(podProblemType))),
+		Description: "Containers that are waiting on an error, failed or restarted",
+		Resolve:     resolver.PodProblemsResolver(),
+	}
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/go-openapi/spec"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// FullSchemaProfile is the implicit profile containing every resource of a cluster
+const FullSchemaProfile = "full"
+
+// coreGroupAlias refers to the core API group, whose name is empty, in the groups of a profile
+const coreGroupAlias = "core"
+
+var (
+	ErrReadSchemaProfiles      = errors.New("failed to read schema profiles file")
+	ErrParseSchemaProfiles     = errors.New("failed to parse schema profiles file")
+	ErrInvalidSchemaProfile    = errors.New("invalid schema profile")
+	ErrDuplicateSchemaProfile  = errors.New("duplicate schema profile name")
+	ErrReservedSchemaProfile   = errors.New("reserved schema profile name")
+	ErrEmptySchemaProfileScope = errors.New("schema profile must select at least one group or kind")
+)
+
+// SchemaProfile is a named subset of the resources of a cluster, served as a separate GraphQL schema.
+// A resource is part of the profile if its group or its kind is listed.
+type SchemaProfile struct {
+	Name   string   `json:"name"`
+	Groups []string `json:"groups,omitempty"`
+	Kinds  []string `json:"kinds,omitempty"`
+}
+
+// SchemaProfilesConfig represents the schema profiles file structure
+type SchemaProfilesConfig struct {
+	Profiles []SchemaProfile `json:"profiles"`
+}
+
+// LoadSchemaProfiles reads the schema profiles from a YAML or JSON file.
+// An empty path results in only the full schema being served.
+func LoadSchemaProfiles(path string) ([]SchemaProfile, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadSchemaProfiles, err)
+	}
+	defer f.Close()
+
+	var cfg SchemaProfilesConfig
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&cfg); err != nil {
+		return nil, errors.Join(ErrParseSchemaProfiles, err)
+	}
+
+	seen := make(map[string]bool, len(cfg.Profiles))
+	for _, profile := range cfg.Profiles {
+		switch {
+		case profile.Name == "":
+			return nil, fmt.Errorf("%w: name is required", ErrInvalidSchemaProfile)
+		case profile.Name == FullSchemaProfile:
+			return nil, fmt.Errorf("%w: %s", ErrReservedSchemaProfile, profile.Name)
+		case seen[profile.Name]:
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateSchemaProfile, profile.Name)
+		case len(profile.Groups) == 0 && len(profile.Kinds) == 0:
+			return nil, fmt.Errorf("%w: %s", ErrEmptySchemaProfileScope, profile.Name)
+		}
+		seen[profile.Name] = true
+	}
+
+	return cfg.Profiles, nil
+}
+
+// FilterDefinitions returns the definitions that are part of the profile.
+// Definitions without a group version kind, e.g. ObjectMeta, are kept since resources reference them.
+func (p SchemaProfile) FilterDefinitions(definitions spec.Definitions) spec.Definitions {
+	filtered := make(spec.Definitions, len(definitions))
+	for key, definition := range definitions {
+		gvk, ok := common.GroupVersionKind(definition.Extensions)
+		if !ok || p.includes(gvk.Group, gvk.Kind) {
+			filtered[key] = definition
+		}
+	}
+
+	return filtered
+}
+
+func (p SchemaProfile) includes(group, kind string) bool {
+	if slices.Contains(p.Kinds, kind) {
+		return true
+	}
+
+	if group == "" && slices.Contains(p.Groups, coreGroupAlias) {
+		return true
+	}
+
+	return slices.Contains(p.Groups, group)
+}
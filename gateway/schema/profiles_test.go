@@ -0,0 +1,135 @@
+package schema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func definitionWithGVK(group, kind string) spec.Schema {
+	s := spec.Schema{}
+	s.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": group, "version": "v1", "kind": kind},
+	})
+	return s
+}
+
+func TestSchemaProfile_FilterDefinitions(t *testing.T) {
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.Pod":                          definitionWithGVK("", "Pod"),
+		"io.k8s.api.core.v1.Namespace":                    definitionWithGVK("", "Namespace"),
+		"io.k8s.api.apps.v1.Deployment":                   definitionWithGVK("apps", "Deployment"),
+		"io.k8s.api.rbac.v1.Role":                         definitionWithGVK("rbac.authorization.k8s.io", "Role"),
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": {},
+	}
+
+	tests := []struct {
+		name     string
+		profile  schema.SchemaProfile
+		expected []string
+	}{
+		{
+			name:    "groups",
+			profile: schema.SchemaProfile{Name: "workloads-only", Groups: []string{"core", "apps"}},
+			expected: []string{
+				"io.k8s.api.core.v1.Pod",
+				"io.k8s.api.core.v1.Namespace",
+				"io.k8s.api.apps.v1.Deployment",
+				"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta",
+			},
+		},
+		{
+			name:    "kinds",
+			profile: schema.SchemaProfile{Name: "platform", Groups: []string{"rbac.authorization.k8s.io"}, Kinds: []string{"Namespace"}},
+			expected: []string{
+				"io.k8s.api.core.v1.Namespace",
+				"io.k8s.api.rbac.v1.Role",
+				"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := tt.profile.FilterDefinitions(definitions)
+
+			keys := make([]string, 0, len(filtered))
+			for key := range filtered {
+				keys = append(keys, key)
+			}
+			assert.ElementsMatch(t, tt.expected, keys)
+		})
+	}
+}
+
+func TestLoadSchemaProfiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr error
+		expected    []schema.SchemaProfile
+	}{
+		{
+			name: "valid",
+			content: `profiles:
+  - name: workloads-only
+    groups: [core, apps]
+  - name: platform
+    kinds: [Namespace]
+`,
+			expected: []schema.SchemaProfile{
+				{Name: "workloads-only", Groups: []string{"core", "apps"}},
+				{Name: "platform", Kinds: []string{"Namespace"}},
+			},
+		},
+		{
+			name:        "reserved_name",
+			content:     "profiles: [{name: full, groups: [apps]}]",
+			expectedErr: schema.ErrReservedSchemaProfile,
+		},
+		{
+			name:        "duplicate_name",
+			content:     "profiles: [{name: apps, groups: [apps]}, {name: apps, kinds: [Pod]}]",
+			expectedErr: schema.ErrDuplicateSchemaProfile,
+		},
+		{
+			name:        "missing_name",
+			content:     "profiles: [{groups: [apps]}]",
+			expectedErr: schema.ErrInvalidSchemaProfile,
+		},
+		{
+			name:        "empty_scope",
+			content:     "profiles: [{name: nothing}]",
+			expectedErr: schema.ErrEmptySchemaProfileScope,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "profiles.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			profiles, err := schema.LoadSchemaProfiles(path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, profiles)
+		})
+	}
+}
+
+func TestLoadSchemaProfiles_EmptyPath(t *testing.T) {
+	profiles, err := schema.LoadSchemaProfiles("")
+	require.NoError(t, err)
+	assert.Empty(t, profiles)
+}
@@ -0,0 +1,16 @@
+package schema
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// WithReadCache adds the fresh argument to the list and get queries of the kinds served from the read cache of the
+// resolver, see resolver.WithReadCache
+func WithReadCache(kinds []schema.GroupVersionKind) Option {
+	return func(g *Gateway) {
+		g.readCacheKinds = make(map[schema.GroupVersionKind]bool, len(kinds))
+		for _, gvk := range kinds {
+			g.readCacheKinds[gvk] = true
+		}
+	}
+}
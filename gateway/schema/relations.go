@@ -9,6 +9,8 @@ import (
 	"github.com/go-openapi/spec"
 	"github.com/graphql-go/graphql"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 )
 
 // addRelationFields adds relation fields to schemas that contain *Ref fields
@@ -37,6 +39,67 @@ func (g *Gateway) addRelationFields(fields graphql.Fields, properties map[string
 	}
 }
 
+// addReferenceResolvers resolves the relationship fields the listener adds next to *Ref fields, e.g. role next to
+// roleRef, and for the relations declared on CRDs to the referenced objects. The fields aren't part of the objects, so
+// they are removed from the input.
+func (g *Gateway) addReferenceResolvers(fields graphql.Fields, inputFields graphql.InputObjectConfigFieldMap, resourceScheme *spec.Schema) {
+	// refFields maps the relationship fields to the path of their reference
+	refFields := make(map[string]string)
+	for fieldName, fieldSpec := range resourceScheme.Properties {
+		if fieldSpec.Ref.GetURL() == nil {
+			continue
+		}
+		if refField, ok := findRefSibling(resourceScheme.Properties, fieldName); ok {
+			refFields[fieldName] = refField
+		}
+	}
+	declaredKinds := make(map[string]string)
+	for _, relation := range common.Relations(resourceScheme.Extensions) {
+		refFields[relation.Name] = relation.Field
+		declaredKinds[relation.Name] = relation.Kind
+	}
+
+	for fieldName, refField := range refFields {
+		fieldSpec, ok := resourceScheme.Properties[fieldName]
+		if !ok || fieldSpec.Ref.GetURL() == nil {
+			continue
+		}
+
+		sanitizedFieldName := sanitizeFieldName(fieldName)
+		field, exists := fields[sanitizedFieldName]
+		if !exists {
+			continue
+		}
+		// Targets that are still being generated are placeholders and can't hold the object
+		if _, ok := field.Type.(*graphql.Object); !ok {
+			continue
+		}
+
+		gvk, err := g.getGroupVersionKind(strings.TrimPrefix(fieldSpec.Ref.String(), "#/definitions/"))
+		if err != nil {
+			continue
+		}
+		// Fields of the CRD named like a declared relation, which the listener didn't add, are left alone
+		if kind, ok := declaredKinds[fieldName]; ok && kind != gvk.Kind {
+			continue
+		}
+
+		field.Resolve = g.resolver.ReferenceResolver(refField, *gvk)
+		delete(inputFields, sanitizedFieldName)
+	}
+}
+
+// findRefSibling returns the *Ref field a relationship field was added for. The listener lowercases the kind of the
+// relationship field, e.g. serviceaccount for serviceAccountRef.
+func findRefSibling(properties map[string]spec.Schema, fieldName string) (string, bool) {
+	for name := range properties {
+		if strings.EqualFold(name, fieldName+"Ref") {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 // enhanceRefTypeWithRelation adds a relation field to a *Ref object type
 func (g *Gateway) enhanceRefTypeWithRelation(originalType graphql.Output, baseName string) graphql.Output {
 	objType, ok := originalType.(*graphql.Object)
@@ -99,7 +162,11 @@ func (g *Gateway) findRelationTarget(baseName string) (graphql.Output, *schema.G
 			if existingType, exists := g.typesCache[defKey]; exists {
 				fieldType = existingType
 			} else {
+				// The UI hints and aliases of the resource that is being generated don't apply to the target
+				fieldUIHints, fieldAliases := g.fieldUIHints, g.fieldAliases
+				g.fieldUIHints, g.fieldAliases = nil, nil
 				ft, _, err := g.convertSwaggerTypeToGraphQL(defSchema, defKey, []string{}, make(map[string]bool))
+				g.fieldUIHints, g.fieldAliases = fieldUIHints, fieldAliases
 				if err != nil {
 					continue
 				}
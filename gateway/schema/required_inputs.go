@@ -0,0 +1,47 @@
+package schema
+
+import (
+	"slices"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+)
+
+// requiredInputExemptions are required top-level fields that stay nullable, the gateway sets apiVersion and kind
+// itself and the status is not part of the object users create
+var requiredInputExemptions = []string{"apiVersion", "kind", "metadata", "status"}
+
+// WithRequiredInputFields marks the input fields the OpenAPI schema requires as non-null, so that incomplete objects
+// are rejected by the GraphQL validation instead of the API server. The input types are shared by the create, update
+// and apply mutations, so partial updates then have to repeat the required fields of every object they contain,
+// which is why it is opt-in. The update<Kind>FromYaml mutations are not affected.
+func WithRequiredInputFields() Option {
+	return func(g *Gateway) {
+		g.requiredInputFields = true
+	}
+}
+
+// requiredInputType returns the input type of a field of the object schema, non-null if the field is required.
+// Fields with a default stay nullable, as missing fields are set to their default on creation.
+func (g *Gateway) requiredInputType(objectSchema *spec.Schema, fieldName string, fieldSpec spec.Schema, fieldPath []string, inputType graphql.Input) graphql.Input {
+	if !g.requiredInputFields || !slices.Contains(objectSchema.Required, fieldName) {
+		return inputType
+	}
+	if len(fieldPath) == 0 && slices.Contains(requiredInputExemptions, fieldName) {
+		return inputType
+	}
+	if fieldSpec.Default != nil {
+		return inputType
+	}
+
+	switch t := inputType.(type) {
+	case nil, *graphql.NonNull:
+		return inputType
+	case *graphql.InputObject:
+		// Types that are still being generated are nil, see handleObjectFieldSpecType
+		if t == nil {
+			return inputType
+		}
+	}
+	return graphql.NewNonNull(inputType)
+}
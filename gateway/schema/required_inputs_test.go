@@ -0,0 +1,90 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_RequiredInputFields(t *testing.T) {
+	container := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type:     spec.StringOrArray{"object"},
+		Required: []string{"name"},
+		Properties: map[string]spec.Schema{
+			"name":  *spec.StringProperty(),
+			"image": *spec.StringProperty(),
+		},
+	}}
+	restartPolicy := *spec.StringProperty()
+	restartPolicy.Default = "Always"
+
+	pod := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:     spec.StringOrArray{"object"},
+			Required: []string{"kind", "spec"},
+			Properties: map[string]spec.Schema{
+				"kind": *spec.StringProperty(),
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type:     spec.StringOrArray{"object"},
+						Required: []string{"containers", "restartPolicy"},
+						Properties: map[string]spec.Schema{
+							"containers":    *spec.ArrayProperty(&container),
+							"restartPolicy": restartPolicy,
+							"hostname":      *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+	pod.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "Pod"},
+	})
+	pod.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions := spec.Definitions{"io.k8s.api.core.v1.Pod": pod}
+
+	inputFields := func(t *testing.T, opts ...schema.Option) (graphql.InputObjectFieldMap, graphql.InputObjectFieldMap) {
+		log := testlogger.New().HideLogOutput().Logger
+		g, err := schema.New(log, definitions, resolver.New(log, nil), opts...)
+		require.NoError(t, err)
+
+		podInput, ok := g.GetSchema().Type("PodInput").(*graphql.InputObject)
+		require.True(t, ok)
+		specInput, ok := graphql.GetNullable(podInput.Fields()["spec"].Type).(*graphql.InputObject)
+		require.True(t, ok)
+		return podInput.Fields(), specInput.Fields()
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		podFields, specFields := inputFields(t, schema.WithRequiredInputFields())
+
+		assert.IsType(t, &graphql.NonNull{}, podFields["spec"].Type)
+		assert.Equal(t, graphql.String, podFields["kind"].Type, "kind is set by the gateway")
+
+		containers, ok := specFields["containers"].Type.(*graphql.NonNull)
+		require.True(t, ok, "containers is required")
+		containerInput, ok := containers.OfType.(*graphql.List).OfType.(*graphql.InputObject)
+		require.True(t, ok)
+		assert.IsType(t, &graphql.NonNull{}, containerInput.Fields()["name"].Type)
+		assert.Equal(t, graphql.String, containerInput.Fields()["image"].Type)
+
+		assert.Equal(t, graphql.String, specFields["restartPolicy"].Type, "fields with a default stay nullable")
+		assert.Equal(t, graphql.String, specFields["hostname"].Type)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		podFields, specFields := inputFields(t)
+
+		assert.IsType(t, &graphql.InputObject{}, podFields["spec"].Type)
+		assert.IsType(t, &graphql.List{}, specFields["containers"].Type)
+	})
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"github.com/graphql-go/graphql"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+const resourceMetricsField = "metrics"
+
+// The types are named apart from the PodMetrics and NodeMetrics kinds, which are part of the schema if the cluster
+// serves the metrics.k8s.io API
+var resourceUsageType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ResourceMetricsUsage",
+	Description: "CPU and memory usage measured by the metrics-server",
+	Fields: graphql.Fields{
+		"cpu":           &graphql.Field{Type: graphql.String, Description: "CPU usage as quantity, e.g. 250m"},
+		"cpuMillicores": &graphql.Field{Type: graphql.Int, Description: "CPU usage in millicores"},
+		"memory":        &graphql.Field{Type: graphql.String, Description: "Memory usage as quantity, e.g. 64Mi"},
+		"memoryBytes":   &graphql.Field{Type: graphql.Float, Description: "Memory usage in bytes"},
+	},
+})
+
+var containerMetricsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ContainerResourceMetrics",
+	Description: "Resource usage of a container of the pod",
+	Fields: graphql.Fields{
+		"name":  graphqlStringField(),
+		"usage": &graphql.Field{Type: graphql.NewNonNull(resourceUsageType)},
+	},
+})
+
+var podMetricsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "PodResourceMetrics",
+	Description: "Resource usage of the pod, the sum of the usage of its containers",
+	Fields: graphql.Fields{
+		"timestamp":  &graphql.Field{Type: graphql.String, Description: "End of the window the usage was measured in"},
+		"window":     &graphql.Field{Type: graphql.String, Description: "Duration of the window the usage was measured in, e.g. 15s"},
+		"usage":      &graphql.Field{Type: graphql.NewNonNull(resourceUsageType)},
+		"containers": &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(containerMetricsType)))},
+	},
+})
+
+var nodeMetricsType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "NodeResourceMetrics",
+	Description: "Resource usage of the node",
+	Fields: graphql.Fields{
+		"timestamp": &graphql.Field{Type: graphql.String, Description: "End of the window the usage was measured in"},
+		"window":    &graphql.Field{Type: graphql.String, Description: "Duration of the window the usage was measured in, e.g. 15s"},
+		"usage":     &graphql.Field{Type: graphql.NewNonNull(resourceUsageType)},
+	},
+})
+
+// WithResourceMetrics adds the metrics field to the core/v1 Pod and Node types, read from the metrics.k8s.io API
+func WithResourceMetrics() Option {
+	return func(g *Gateway) {
+		g.resourceMetrics = true
+	}
+}
+
+// addResourceMetricsFields adds the metrics field with the current CPU and memory usage to the core/v1 Pod and Node
+// types. The field is nullable, as clusters without metrics-server serve no metrics.
+func (g *Gateway) addResourceMetricsFields(fields graphql.Fields, apiGVK schema.GroupVersionKind) {
+	if !g.resourceMetrics || (apiGVK != resolver.PodGVK && apiGVK != resolver.NodeGVK) {
+		return
+	}
+	if _, exists := fields[resourceMetricsField]; exists {
+		g.log.Debug().Str("kind", apiGVK.Kind).Msg("Skipping metrics field, the kind has a field of that name")
+		return
+	}
+
+	metricsType, description := podMetricsType, "Current CPU and memory usage of the pod and its containers, null if the metrics.k8s.io API serves none"
+	if apiGVK == resolver.NodeGVK {
+		metricsType, description = nodeMetricsType, "Current CPU and memory usage of the node, null if the metrics.k8s.io API serves none"
+	}
+
+	fields[resourceMetricsField] = &graphql.Field{
+		Type:        metricsType,
+		Resolve:     g.resolver.ResourceMetrics(apiGVK),
+		Description: description,
+	}
+}
@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"encoding/base64"
 	"encoding/json"
 
 	"github.com/graphql-go/graphql"
@@ -81,20 +82,18 @@ var stringMapScalar = graphql.NewScalar(graphql.ScalarConfig{
 					return nil
 				}
 
+				var key, val string
 				for _, field := range obj.Fields {
 					switch field.Name.Value {
 					case "key":
-						if key, ok := field.Value.GetValue().(string); ok {
-							result[key] = ""
-						}
+						key, _ = field.Value.GetValue().(string)
 					case "value":
-						if val, ok := field.Value.GetValue().(string); ok {
-							for key := range result {
-								result[key] = val
-							}
-						}
+						val, _ = field.Value.GetValue().(string)
 					}
 				}
+				if key != "" {
+					result[key] = val
+				}
 			}
 
 			return result
@@ -111,3 +110,60 @@ var stringMapScalar = graphql.NewScalar(graphql.ScalarConfig{
 		}
 	},
 })
+
+// base64StringMapScalar represents maps of binary data such as ConfigMap.binaryData and Secret.data.
+// The values are base64-encoded like in the Kubernetes API, inputs with values that are not valid base64 are rejected.
+var base64StringMapScalar = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Base64StringMap",
+	Description: "A map from strings to base64-encoded binary data.",
+	Serialize: func(value interface{}) interface{} {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+
+		result := make(map[string]interface{}, len(data))
+		for key, val := range data {
+			if raw, ok := val.([]byte); ok {
+				val = base64.StdEncoding.EncodeToString(raw)
+			}
+			result[key] = val
+		}
+		return result
+	},
+	ParseValue: func(value interface{}) interface{} {
+		return parseBase64StringMap(stringMapScalar.ParseValue(value))
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		return parseBase64StringMap(stringMapScalar.ParseLiteral(valueAST))
+	},
+})
+
+// parseBase64StringMap validates the values of a parsed string map, nil tells GraphQL that the value is invalid
+func parseBase64StringMap(parsed interface{}) interface{} {
+	values := map[string]interface{}{}
+	switch val := parsed.(type) {
+	case map[string]interface{}:
+		values = val
+	case map[string]string:
+		for key, v := range val {
+			values[key] = v
+		}
+	default:
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(values))
+	for key, val := range values {
+		str, ok := val.(string)
+		if !ok {
+			return nil
+		}
+		if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+			return nil
+		}
+		result[key] = str
+	}
+
+	return result
+}
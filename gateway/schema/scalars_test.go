@@ -61,6 +61,17 @@ func TestStringMapScalar_ParseLiteral(t *testing.T) {
 			},
 			expected: map[string]string{"key": "val", "key2": "val2"},
 		},
+		{
+			name: "valid_list_value",
+			input: &ast.ListValue{
+				Kind: kinds.ListValue,
+				Values: []ast.Value{
+					keyValueLiteral("key", "val"),
+					keyValueLiteral("key2", "val2"),
+				},
+			},
+			expected: map[string]string{"key": "val", "key2": "val2"},
+		},
 		{
 			name:     "invalid_string_value",
 			input:    &ast.StringValue{Kind: kinds.StringValue, Value: "key=val"},
@@ -202,3 +213,86 @@ func TestJSONStringScalar_ProperSerialization(t *testing.T) {
 
 	t.Logf("Proper JSON output: %s", resultStr)
 }
+
+func keyValueLiteral(key, value string) *ast.ObjectValue {
+	return &ast.ObjectValue{
+		Kind: kinds.ObjectValue,
+		Fields: []*ast.ObjectField{
+			{Name: &ast.Name{Value: "key"}, Value: &ast.StringValue{Kind: kinds.StringValue, Value: key}},
+			{Name: &ast.Name{Value: "value"}, Value: &ast.StringValue{Kind: kinds.StringValue, Value: value}},
+		},
+	}
+}
+
+func TestBase64StringMapScalar(t *testing.T) {
+	tests := []struct {
+		name     string
+		parse    func() interface{}
+		expected interface{}
+	}{
+		{
+			name: "value_map",
+			parse: func() interface{} {
+				return schema.Base64StringMapScalarForTest.ParseValue(map[string]interface{}{"logo.png": "aGk="})
+			},
+			expected: map[string]interface{}{"logo.png": "aGk="},
+		},
+		{
+			name: "value_key_value_list",
+			parse: func() interface{} {
+				return schema.Base64StringMapScalarForTest.ParseValue([]interface{}{
+					map[string]interface{}{"key": "logo.png", "value": "aGk="},
+				})
+			},
+			expected: map[string]interface{}{"logo.png": "aGk="},
+		},
+		{
+			name: "value_invalid_base64",
+			parse: func() interface{} {
+				return schema.Base64StringMapScalarForTest.ParseValue(map[string]interface{}{"logo.png": "not base64!"})
+			},
+			expected: nil,
+		},
+		{
+			name: "literal_list",
+			parse: func() interface{} {
+				return schema.Base64StringMapScalarForTest.ParseLiteral(&ast.ListValue{
+					Kind:   kinds.ListValue,
+					Values: []ast.Value{keyValueLiteral("a", "YQ=="), keyValueLiteral("b", "Yg==")},
+				})
+			},
+			expected: map[string]interface{}{"a": "YQ==", "b": "Yg=="},
+		},
+		{
+			name: "literal_invalid_base64",
+			parse: func() interface{} {
+				return schema.Base64StringMapScalarForTest.ParseLiteral(&ast.ListValue{
+					Kind:   kinds.ListValue,
+					Values: []ast.Value{keyValueLiteral("a", "YQ")},
+				})
+			},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := tt.parse()
+			if !reflect.DeepEqual(out, tt.expected) {
+				t.Errorf("parse = %v, want %v", out, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBase64StringMapScalar_Serialize(t *testing.T) {
+	out := schema.Base64StringMapScalarForTest.Serialize(map[string]interface{}{
+		"encoded": "aGk=",
+		"raw":     []byte("hi"),
+	})
+
+	expected := map[string]interface{}{"encoded": "aGk=", "raw": "aGk="}
+	if !reflect.DeepEqual(out, expected) {
+		t.Errorf("Serialize() = %v, want %v", out, expected)
+	}
+}
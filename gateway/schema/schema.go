@@ -3,7 +3,9 @@ package schema
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/go-openapi/spec"
@@ -34,9 +36,81 @@ type Gateway struct {
 
 	// categoryRegistry stores resources by category for typeByCategory query
 	typeByCategory map[string][]resolver.TypeByCategory
+
+	// resources are the kinds for which operations were generated
+	resources []Resource
+
+	// deprecations are the kinds whose definitions carry a deprecation warning of the API server
+	deprecations []DeprecatedResource
+
+	// generationErrors are the definitions the listener left out of the schema, see WithGenerationErrors
+	generationErrors []common.GenerationError
+
+	// leaseType is the coordination.k8s.io/v1 Lease type returned by the leaderOf query
+	leaseType *graphql.Object
+
+	// fieldUIHints are the UI hints of the fields of the resource that is currently generated, keyed by field path
+	fieldUIHints map[string]common.UIHint
+
+	// inputOnlyFields are the paths of the fields of the resource that is currently generated which are left out of its output type
+	inputOnlyFields []string
+	// inputOnlyTypePrefix separates the output types that lack input-only fields from the types shared with other resources
+	inputOnlyTypePrefix string
+
+	// federation serves the schema as an Apollo Federation v2 subgraph, see WithFederation
+	federation         bool
+	federationEntities []federationEntity
+
+	// kubeconfigIssuance adds the issueKubeconfig mutation, see WithKubeconfigIssuance
+	kubeconfigIssuance bool
+
+	// descriptionLength limits the descriptions taken from the OpenAPI definitions, see WithDescriptionLength
+	descriptionLength int
+
+	// transformations change how the schema of the matching resources is generated, see WithTransformations
+	transformations []SchemaTransformation
+	// fieldAliases are the aliases of the fields of the resource that is currently generated, keyed by field path
+	fieldAliases map[string]string
+
+	// enums generates GraphQL enums for string fields restricted to enum values, see WithEnums
+	enums      bool
+	enumsCache map[string]*graphql.Enum
+
+	// kubernetesScalars generates Quantity, Time, MicroTime and IntOrString scalars, see WithKubernetesScalars
+	kubernetesScalars bool
+
+	// requiredInputFields marks required input fields as non-null, see WithRequiredInputFields
+	requiredInputFields bool
+
+	// readCacheKinds are the kinds whose reads may be served from the read cache, see WithReadCache
+	readCacheKinds map[schema.GroupVersionKind]bool
+
+	// permissions adds the canI query and the permissions fields, see WithPermissions
+	permissions bool
+
+	// ownerRelations are the kinds that get owns and ownedBy fields, see WithOwnerReferences
+	ownerRelations []resolver.OwnerRelation
+
+	// events adds the events field to every resource type, see WithEvents
+	events bool
+
+	// resourceMetrics adds the metrics field to the Pod and Node types, see WithResourceMetrics
+	resourceMetrics bool
+
+	// subresources are the subresources operations are generated for, see WithSubresources
+	subresources map[string]bool
+
+	// withoutMutations and withoutSubscriptions leave the root types out, see WithoutMutations and WithoutSubscriptions
+	withoutMutations     bool
+	withoutSubscriptions bool
+	// deniedKinds are left out of the schema, see WithDeniedKinds
+	deniedKinds []schema.GroupVersionKind
+
+	// resourceTypes are the types of the kinds, kept until the fields referring to the types of other kinds are added
+	resourceTypes map[schema.GroupVersionKind]relatedType
 }
 
-func New(log *logger.Logger, definitions spec.Definitions, resolverProvider resolver.Provider) (*Gateway, error) {
+func New(log *logger.Logger, definitions spec.Definitions, resolverProvider resolver.Provider, opts ...Option) (*Gateway, error) {
 	g := &Gateway{
 		log:                log,
 		resolver:           resolverProvider,
@@ -46,6 +120,19 @@ func New(log *logger.Logger, definitions spec.Definitions, resolverProvider reso
 		enhancedTypesCache: make(map[string]*graphql.Object),
 		typeNameRegistry:   make(map[string]string),
 		typeByCategory:     make(map[string][]resolver.TypeByCategory),
+		enumsCache:         make(map[string]*graphql.Enum),
+		resourceTypes:      make(map[schema.GroupVersionKind]relatedType),
+		descriptionLength:  DefaultDescriptionLength,
+	}
+	WithSubresources(DefaultSubresources)(g)
+	for _, opt := range opts {
+		opt(g)
+	}
+	if len(g.deniedKinds) > 0 {
+		g.definitions = g.filterDeniedKinds(g.definitions)
+	}
+	if len(g.transformations) > 0 {
+		g.definitions = g.applyTransformations(g.definitions)
 	}
 
 	err := g.generateGraphqlSchema()
@@ -57,6 +144,32 @@ func (g *Gateway) GetSchema() *graphql.Schema {
 	return &g.graphqlSchema
 }
 
+// Resource is a kind for which queries, mutations and subscriptions were generated
+type Resource struct {
+	Group   string
+	Version string
+	Kind    string
+	Scope   apiextensionsv1.ResourceScope
+	// GraphQLGroup is the field of the root query and mutation types that holds the operations of the kind
+	GraphQLGroup string
+	Singular     string
+	Plural       string
+	// Subresources are the subresources operations were generated for, e.g. status
+	Subresources []string
+}
+
+// relatedType is the type of a kind, to which fields referring to the types of other kinds are added
+type relatedType struct {
+	object *graphql.Object
+	fields graphql.Fields
+	plural string
+}
+
+// Resources returns the kinds for which operations were generated
+func (g *Gateway) Resources() []Resource {
+	return g.resources
+}
+
 func (g *Gateway) generateGraphqlSchema() error {
 	rootQueryFields := graphql.Fields{}
 	rootMutationFields := graphql.Fields{}
@@ -72,28 +185,57 @@ func (g *Gateway) generateGraphqlSchema() error {
 		)
 	}
 
-	g.AddTypeByCategoryQuery(rootQueryFields)
+	g.addOwnerReferenceFields()
+	g.addEventsFields()
 
-	newSchema, err := graphql.NewSchema(graphql.SchemaConfig{
+	g.AddTypeByCategoryQuery(rootQueryFields)
+	g.AddClusterDefaultsQuery(rootQueryFields)
+	g.AddClusterInfoQuery(rootQueryFields)
+	g.AddLeaderOfQuery(rootQueryFields)
+	g.AddCanIQuery(rootQueryFields)
+	g.AddDeprecationsQuery(rootQueryFields)
+	g.AddSchemaDiagnosticsQuery(rootQueryFields)
+	g.AddNamespaceMutations(rootMutationFields)
+	g.AddKubeconfigMutation(rootMutationFields)
+	g.AddManifestsMutation(rootMutationFields)
+
+	schemaConfig := graphql.SchemaConfig{
 		Query: graphql.NewObject(graphql.ObjectConfig{
 			Name:   "PrivateNameForQuery", // we must keep those name unique to avoid collision with objects having the same names
 			Fields: rootQueryFields,
 		}),
-		Mutation: graphql.NewObject(graphql.ObjectConfig{
+	}
+	if !g.withoutMutations {
+		schemaConfig.Mutation = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "PrivateNameForMutation",
 			Fields: rootMutationFields,
-		}),
-		Subscription: graphql.NewObject(graphql.ObjectConfig{
+		})
+	}
+	// graphql-go rejects root types without fields, which clusters without valid definitions have no subscriptions for
+	if !g.withoutSubscriptions && len(rootSubscriptionFields) > 0 {
+		schemaConfig.Subscription = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "PrivateNameForSubscription",
 			Fields: rootSubscriptionFields,
-		}),
-	})
+		})
+	}
 
+	newSchema, err := graphql.NewSchema(schemaConfig)
 	if err != nil {
 		g.log.Error().Err(err).Msg("Error creating GraphQL schema")
 		return err
 	}
 
+	if g.federation {
+		// The SDL served by _service describes the schema without the federation queries
+		g.addFederationQueries(schemaConfig.Query, printFederationSDL(newSchema, g.federationEntities))
+
+		newSchema, err = graphql.NewSchema(schemaConfig)
+		if err != nil {
+			g.log.Error().Err(err).Msg("Error creating federated GraphQL schema")
+			return err
+		}
+	}
+
 	g.graphqlSchema = newSchema
 
 	return nil
@@ -164,12 +306,41 @@ func (g *Gateway) processSingleResource(
 		return
 	}
 
-	err = g.storeCategory(resourceKey, gvk, resourceScope)
-	if err != nil {
-		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Error storing category")
+	originalGVK, _ := common.GroupVersionKind(resourceScheme.Extensions)
+	originalGroup := originalGVK.Group
+
+	// The names are derived from the renamed kind, the resolvers keep using the kind of the API server
+	namingGVK := *gvk
+	if transformation, ok := g.transformation(originalGroup, gvk.Kind); ok {
+		if transformation.Rename != "" {
+			namingGVK.Kind = transformation.Rename
+		}
+		g.fieldAliases = transformation.Aliases
+		defer func() { g.fieldAliases = nil }()
+	}
+
+	// Versions besides the preferred one are served under names prefixed with their version, e.g. v1beta1Widget
+	preferredVersion, _ := resourceScheme.Extensions[common.PreferredVersionKey].(string)
+	if preferredVersion != "" && preferredVersion != gvk.Version {
+		namingGVK.Kind = gvk.Version + namingGVK.Kind
 	}
 
-	singular, plural := g.getNames(gvk)
+	singular, plural := g.getNames(&namingGVK)
+
+	typeDescription := g.schemaDescription(resourceScheme)
+	if preferredVersion != "" && preferredVersion != gvk.Version {
+		typeDescription = joinDescriptions(typeDescription, fmt.Sprintf("Served version %s, the preferred version is %s.", gvk.Version, preferredVersion))
+	}
+	if hints := g.getUIHints(resourceKey); hints != nil {
+		typeDescription = joinDescriptions(typeDescription, uiHintDescription(hints.UIHint))
+		g.fieldUIHints = hints.Fields
+		defer func() { g.fieldUIHints = nil }()
+	}
+
+	if paths := common.InputOnlyFields(resourceScheme.Extensions); len(paths) > 0 {
+		g.inputOnlyFields, g.inputOnlyTypePrefix = paths, singular
+		defer func() { g.inputOnlyFields, g.inputOnlyTypePrefix = nil, "" }()
+	}
 
 	// Generate both fields and inputFields
 	fields, inputFields, err := g.generateGraphQLFields(&resourceScheme, singular, []string{}, make(map[string]bool))
@@ -183,14 +354,28 @@ func (g *Gateway) processSingleResource(
 		return
 	}
 
+	apiGVK := schema.GroupVersionKind{Group: originalGroup, Version: gvk.Version, Kind: gvk.Kind}
+
+	g.storeDeprecation(resourceKey, apiGVK)
+	g.addPodDiagnosticFields(fields, gvk)
+	g.addLeaseFields(fields, apiGVK)
+	g.addResourceMetricsFields(fields, apiGVK)
+	g.addPermissionsField(fields, *gvk, resourceScope)
+
 	resourceType := graphql.NewObject(graphql.ObjectConfig{
-		Name:   singular,
-		Fields: fields,
+		Name:        singular,
+		Fields:      fields,
+		Description: typeDescription,
 	})
+	if apiGVK == resolver.LeaseGVK {
+		g.leaseType = resourceType
+	}
+	g.resourceTypes[apiGVK] = relatedType{object: resourceType, fields: fields, plural: plural}
 
 	resourceInputType := graphql.NewInputObject(graphql.InputObjectConfig{
-		Name:   singular + "Input",
-		Fields: inputFields,
+		Name:        singular + "Input",
+		Fields:      inputFields,
+		Description: typeDescription,
 	})
 
 	listArgsBuilder := resolver.NewFieldConfigArguments().
@@ -211,24 +396,70 @@ func (g *Gateway) processSingleResource(
 	itemArgs := itemArgsBuilder.Complete()
 	creationMutationArgs := creationMutationArgsBuilder.Complete()
 
+	// Subscriptions share the list arguments, but always watch the most recent state
+	listQueryArgsBuilder := resolver.NewFieldConfigArguments().WithConsistency().WithFilter()
+	itemQueryArgsBuilder := resolver.NewFieldConfigArguments()
+	if apiGVK == resolver.EventGVK {
+		listQueryArgsBuilder.WithEventFilters()
+	}
+	if g.readCacheKinds[apiGVK] {
+		listQueryArgsBuilder.WithFresh()
+		itemQueryArgsBuilder.WithFresh()
+	}
+	listQueryArgs := listQueryArgsBuilder.Complete()
+	maps.Copy(listQueryArgs, listArgs)
+	itemQueryArgs := itemQueryArgsBuilder.Complete()
+	maps.Copy(itemQueryArgs, itemArgs)
+
 	queryGroupType.AddFieldConfig(plural, &graphql.Field{
 		Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(resourceType))),
-		Args:    listArgs,
+		Args:    listQueryArgs,
 		Resolve: g.resolver.ListItems(*gvk, resourceScope),
 	})
 
+	connectionArgsBuilder := resolver.NewFieldConfigArguments().
+		WithLabelSelector().
+		WithPagination().
+		WithConsistency().
+		WithFilter()
+	if resourceScope == apiextensionsv1.NamespaceScoped {
+		connectionArgsBuilder.WithNamespace()
+	}
+	if apiGVK == resolver.EventGVK {
+		connectionArgsBuilder.WithEventFilters()
+	}
+
+	queryGroupType.AddFieldConfig(plural+"Connection", &graphql.Field{
+		Type:        graphql.NewNonNull(connectionType(singular, resourceType)),
+		Args:        connectionArgsBuilder.Complete(),
+		Resolve:     g.resolver.ListItemsConnection(*gvk, resourceScope),
+		Description: fmt.Sprintf("List %s page by page, ordered by namespace and name", plural),
+	})
+
 	queryGroupType.AddFieldConfig(singular, &graphql.Field{
 		Type:    graphql.NewNonNull(resourceType),
-		Args:    itemArgs,
+		Args:    itemQueryArgs,
 		Resolve: g.resolver.GetItem(*gvk, resourceScope),
 	})
 
 	queryGroupType.AddFieldConfig(singular+"Yaml", &graphql.Field{
 		Type:    graphql.NewNonNull(graphql.String),
-		Args:    itemArgs,
+		Args:    itemQueryArgs,
 		Resolve: g.resolver.GetItemAsYAML(*gvk, resourceScope),
 	})
 
+	templateArgsBuilder := resolver.NewFieldConfigArguments()
+	if resourceScope == apiextensionsv1.NamespaceScoped {
+		templateArgsBuilder.WithNamespace()
+	}
+
+	queryGroupType.AddFieldConfig("template"+singular, &graphql.Field{
+		Type:        graphql.NewNonNull(resourceType),
+		Args:        templateArgsBuilder.Complete(),
+		Resolve:     g.resolver.TemplateItem(*gvk, resourceScope, g.objectTemplate(resourceScheme)),
+		Description: fmt.Sprintf("A skeleton %s with the defaults and the required fields of its schema, e.g. to pre-populate creation forms", singular),
+	})
+
 	// Mutation definitions
 	mutationGroupType.AddFieldConfig("create"+singular, &graphql.Field{
 		Type:    resourceType,
@@ -242,17 +473,67 @@ func (g *Gateway) processSingleResource(
 		Resolve: g.resolver.UpdateItem(*gvk, resourceScope),
 	})
 
+	yamlMutationArgsBuilder := resolver.NewFieldConfigArguments().WithYAML().WithDryRun()
+	if resourceScope == apiextensionsv1.NamespaceScoped {
+		yamlMutationArgsBuilder.WithNamespace()
+	}
+	yamlMutationArgs := yamlMutationArgsBuilder.Complete()
+
+	mutationGroupType.AddFieldConfig("create"+singular+"FromYaml", &graphql.Field{
+		Type:        resourceType,
+		Args:        yamlMutationArgs,
+		Resolve:     g.resolver.CreateItemFromYAML(*gvk, resourceScope),
+		Description: fmt.Sprintf("Create a %s from a YAML manifest, the manifest is validated against the schema of the resource first", singular),
+	})
+
+	mutationGroupType.AddFieldConfig("update"+singular+"FromYaml", &graphql.Field{
+		Type:        resourceType,
+		Args:        yamlMutationArgs,
+		Resolve:     g.resolver.UpdateItemFromYAML(*gvk, resourceScope),
+		Description: fmt.Sprintf("Update the %s named in metadata.name of a YAML manifest with a merge patch, the manifest is validated against the schema of the resource first", singular),
+	})
+
+	applyArgsBuilder := resolver.NewFieldConfigArguments().WithObject(resourceInputType).WithApply().WithDryRun()
+	if resourceScope == apiextensionsv1.NamespaceScoped {
+		applyArgsBuilder.WithNamespace()
+	}
+
+	mutationGroupType.AddFieldConfig("apply"+singular, &graphql.Field{
+		Type:        resourceType,
+		Args:        applyArgsBuilder.Complete(),
+		Resolve:     g.resolver.ApplyItem(*gvk, resourceScope),
+		Description: fmt.Sprintf("Create or update a %s via Server-Side Apply, only the given fields are owned by the field manager", singular),
+	})
+
+	subresources := g.addSubresourceOperations(queryGroupType, mutationGroupType, resourceScheme, resourceType, resourceInputType, gvk, resourceScope, singular)
+
 	mutationGroupType.AddFieldConfig("delete"+singular, &graphql.Field{
 		Type:    graphql.Boolean,
 		Args:    itemArgsBuilder.WithDryRun().Complete(),
 		Resolve: g.resolver.DeleteItem(*gvk, resourceScope),
 	})
 
+	deleteMatchingArgsBuilder := resolver.NewFieldConfigArguments().
+		WithLabelSelector().
+		WithDryRun().
+		WithPreview()
+	if resourceScope == apiextensionsv1.NamespaceScoped {
+		deleteMatchingArgsBuilder.WithNamespace()
+	}
+
+	mutationGroupType.AddFieldConfig("deleteMatching"+singular, &graphql.Field{
+		Type:        graphql.NewNonNull(deleteMatchingResultType),
+		Args:        deleteMatchingArgsBuilder.Complete(),
+		Resolve:     g.resolver.DeleteMatchingItems(*gvk, resourceScope),
+		Description: fmt.Sprintf("Delete all %s matching the label selector, use preview to list them without deleting", plural),
+	})
+
 	subscriptionSingular := strings.ToLower(fmt.Sprintf("%s_%s", gvk.Group, singular))
 	rootSubscriptionFields[subscriptionSingular] = &graphql.Field{
 		Type: resourceType,
 		Args: itemArgsBuilder.
 			WithSubscribeToAll().
+			WithEventBatching().
 			Complete(),
 		Resolve:     resolver.CreateSubscriptionResolver(true),
 		Subscribe:   g.resolver.SubscribeItem(*gvk, resourceScope),
@@ -264,11 +545,49 @@ func (g *Gateway) processSingleResource(
 		Type: graphql.NewList(resourceType),
 		Args: listArgsBuilder.
 			WithSubscribeToAll().
+			WithEventBatching().
 			Complete(),
 		Resolve:     resolver.CreateSubscriptionResolver(false),
 		Subscribe:   g.resolver.SubscribeItems(*gvk, resourceScope),
 		Description: fmt.Sprintf("Subscribe to changes of %s", plural),
 	}
+
+	verbs := common.Verbs(resourceScheme.Extensions)
+	if verbs == nil {
+		// Schemas of older listeners don't record the verbs, operations are generated for all of them
+		verbs = resolver.PermissionVerbs
+	}
+	subscriptions := slices.Contains(verbs, "watch")
+	resourceInfo := resolver.TypeByCategory{
+		Group:         gvk.Group,
+		APIGroup:      originalGroup,
+		Version:       gvk.Version,
+		Kind:          gvk.Kind,
+		Scope:         string(resourceScope),
+		Singular:      singular,
+		Plural:        plural,
+		Verbs:         verbs,
+		Subscriptions: subscriptions,
+		Description:   g.schemaDescription(resourceScheme),
+	}
+	if subscriptions {
+		resourceInfo.SingularSubscription, resourceInfo.PluralSubscription = subscriptionSingular, subscriptionPlural
+	}
+	if err := g.storeCategory(resourceKey, resourceInfo); err != nil {
+		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Error storing category")
+	}
+
+	g.addFederationEntity(resourceType, fields, *gvk, originalGroup, resourceScope)
+	g.resources = append(g.resources, Resource{
+		Group:        originalGroup,
+		Version:      gvk.Version,
+		Kind:         gvk.Kind,
+		Scope:        resourceScope,
+		GraphQLGroup: gvk.Group,
+		Singular:     singular,
+		Plural:       plural,
+		Subresources: subresources,
+	})
 }
 
 func (g *Gateway) getNames(gvk *schema.GroupVersionKind) (singular string, plural string) {
@@ -325,22 +644,36 @@ func (g *Gateway) generateGraphQLFields(resourceScheme *spec.Schema, typePrefix
 			return nil, nil, err
 		}
 
-		fields[sanitizedFieldName] = &graphql.Field{
-			Type: fieldType,
+		description := joinDescriptions(g.schemaDescription(fieldSpec), g.fieldDescription(currentFieldPath))
+
+		// Input-only fields, and objects whose fields are all input-only, are accepted by mutations but never returned
+		if fieldType != nil && !g.isInputOnlyField(currentFieldPath) {
+			fields[sanitizedFieldName] = &graphql.Field{
+				Type:        fieldType,
+				Description: description,
+			}
 		}
 
 		inputFields[sanitizedFieldName] = &graphql.InputObjectFieldConfig{
-			Type: inputFieldType,
+			Type:        g.requiredInputType(resourceScheme, fieldName, fieldSpec, fieldPath, inputFieldType),
+			Description: joinDescriptions(description, g.defaultDescription(fieldSpec)),
 		}
 	}
 
+	g.addAliasFields(fields, resourceScheme.Properties, fieldPath)
+
 	// Add relation fields for any *Ref fields in this schema
 	g.addRelationFields(fields, resourceScheme.Properties)
+	g.addReferenceResolvers(fields, inputFields, resourceScheme)
 
 	return fields, inputFields, nil
 }
 
 func (g *Gateway) convertSwaggerTypeToGraphQL(schema spec.Schema, typePrefix string, fieldPath []string, processingTypes map[string]bool) (graphql.Output, graphql.Input, error) {
+	if scalar, ok := g.kubernetesScalar(schema, strings.TrimPrefix(schema.Ref.String(), "#/definitions/")); ok {
+		return scalar, scalar, nil
+	}
+
 	if len(schema.Type) == 0 {
 		// Handle $ref types
 		if schema.Ref.GetURL() != nil {
@@ -389,6 +722,9 @@ func (g *Gateway) convertSwaggerTypeToGraphQL(schema spec.Schema, typePrefix str
 
 	switch schema.Type[0] {
 	case "string":
+		if enum, ok := g.enumType(schema, typePrefix, fieldPath); ok {
+			return enum, enum, nil
+		}
 		return graphql.String, graphql.String, nil
 	case "integer":
 		return graphql.Int, graphql.Int, nil
@@ -402,6 +738,9 @@ func (g *Gateway) convertSwaggerTypeToGraphQL(schema spec.Schema, typePrefix str
 			if err != nil {
 				return nil, nil, err
 			}
+			if itemType == nil {
+				return nil, graphql.NewList(inputItemType), nil
+			}
 			return graphql.NewList(itemType), graphql.NewList(inputItemType), nil
 		}
 		return graphql.NewList(graphql.String), graphql.NewList(graphql.String), nil
@@ -416,9 +755,15 @@ func (g *Gateway) convertSwaggerTypeToGraphQL(schema spec.Schema, typePrefix str
 func (g *Gateway) handleObjectFieldSpecType(fieldSpec spec.Schema, typePrefix string, fieldPath []string, processingTypes map[string]bool) (graphql.Output, graphql.Input, error) {
 	if len(fieldSpec.Properties) > 0 {
 		typeName := g.generateTypeName(typePrefix, fieldPath)
+		if g.hasInputOnlyFieldsBelow(fieldPath) && !strings.HasPrefix(typeName, g.inputOnlyTypePrefix) {
+			typeName = g.inputOnlyTypePrefix + "_" + typeName
+		}
 
 		// Check if type already generated
 		if existingType, exists := g.typesCache[typeName]; exists {
+			if existingType == nil {
+				return nil, g.inputTypesCache[typeName], nil
+			}
 			return existingType, g.inputTypesCache[typeName], nil
 		}
 
@@ -431,24 +776,35 @@ func (g *Gateway) handleObjectFieldSpecType(fieldSpec spec.Schema, typePrefix st
 			return nil, nil, err
 		}
 
-		newType := graphql.NewObject(graphql.ObjectConfig{
-			Name:   sanitizeFieldName(typeName),
-			Fields: nestedFields,
-		})
+		description := g.schemaDescription(fieldSpec)
 
 		newInputType := graphql.NewInputObject(graphql.InputObjectConfig{
-			Name:   sanitizeFieldName(typeName) + "Input",
-			Fields: nestedInputFields,
+			Name:        sanitizeFieldName(typeName) + "Input",
+			Fields:      nestedInputFields,
+			Description: description,
 		})
+		g.inputTypesCache[typeName] = newInputType
+
+		// Every field is input-only, there is nothing to return
+		if len(nestedFields) == 0 {
+			return nil, newInputType, nil
+		}
 
-		// Store the generated types
+		newType := graphql.NewObject(graphql.ObjectConfig{
+			Name:        sanitizeFieldName(typeName),
+			Fields:      nestedFields,
+			Description: description,
+		})
 		g.typesCache[typeName] = newType
-		g.inputTypesCache[typeName] = newInputType
 
 		return newType, newInputType, nil
 	} else if fieldSpec.AdditionalProperties != nil && fieldSpec.AdditionalProperties.Schema != nil {
 		// Hagndle map types
 		if len(fieldSpec.AdditionalProperties.Schema.Type) == 1 && fieldSpec.AdditionalProperties.Schema.Type[0] == "string" {
+			// This is a map[string][]byte, e.g. binaryData of ConfigMaps
+			if fieldSpec.AdditionalProperties.Schema.Format == "byte" {
+				return base64StringMapScalar, base64StringMapScalar, nil
+			}
 			// This is a map[string]string
 			return stringMapScalar, stringMapScalar, nil
 		}
@@ -465,49 +821,25 @@ func (g *Gateway) generateTypeName(typePrefix string, fieldPath []string) string
 
 // io.openmfp.core.v1alpha1.Account
 
-// getGroupVersionKind retrieves the GroupVersionKind for a given resourceKey and its OpenAPI schema.
-// It first checks for the 'x-kubernetes-group-version-kind' extension and uses it if available.
-// If not, it falls back to parsing the resourceKey.
+// getGroupVersionKind retrieves the GroupVersionKind for a given resourceKey from the 'x-kubernetes-group-version-kind'
+// extension of its OpenAPI schema, with the group name sanitized.
 func (g *Gateway) getGroupVersionKind(resourceKey string) (*schema.GroupVersionKind, error) {
-	// First, check if 'x-kubernetes-group-version-kind' extension is present
-	resourceSpec, ok := g.definitions[resourceKey]
-	if !ok || resourceSpec.Extensions == nil {
-		return nil, errors.New("no resource extensions")
-	}
-	xkGvk, ok := resourceSpec.Extensions[common.GVKExtensionKey]
+	gvk, ok := common.GroupVersionKind(g.definitions[resourceKey].Extensions)
 	if !ok {
 		return nil, errors.New("x-kubernetes-group-version-kind extension not found")
 	}
-	// xkGvk should be an array of maps
-	if gvkList, ok := xkGvk.([]interface{}); ok && len(gvkList) > 0 {
-		// Use the first item in the list
-		if gvkMap, ok := gvkList[0].(map[string]interface{}); ok {
-			group, _ := gvkMap["group"].(string)
-			version, _ := gvkMap["version"].(string)
-			kind, _ := gvkMap["kind"].(string)
-
-			// Validate that kind is not empty - empty kinds cannot be used for GraphQL type names
-			if kind == "" {
-				return nil, fmt.Errorf("kind cannot be empty for resource %s", resourceKey)
-			}
 
-			// Sanitize the group and kind names
-			return &schema.GroupVersionKind{
-				Group:   g.resolver.SanitizeGroupName(group),
-				Version: version,
-				Kind:    kind,
-			}, nil
-		}
+	// Validate that kind is not empty - empty kinds cannot be used for GraphQL type names
+	if gvk.Kind == "" {
+		return nil, fmt.Errorf("kind cannot be empty for resource %s", resourceKey)
 	}
 
-	return nil, errors.New("failed to parse x-kubernetes-group-version-kind extension")
+	gvk.Group = g.resolver.SanitizeGroupName(gvk.Group)
+	return &gvk, nil
 }
 
-func (g *Gateway) storeCategory(
-	resourceKey string,
-	gvk *schema.GroupVersionKind,
-	resourceScope apiextensionsv1.ResourceScope,
-) error {
+// storeCategory lists the resource under the categories of its definition for the typeByCategory query
+func (g *Gateway) storeCategory(resourceKey string, resource resolver.TypeByCategory) error {
 	resourceSpec, ok := g.definitions[resourceKey]
 	if !ok || resourceSpec.Extensions == nil {
 		return errors.New("no resource extensions")
@@ -532,12 +864,7 @@ func (g *Gateway) storeCategory(
 	}
 
 	for _, category := range categories {
-		g.typeByCategory[category] = append(g.typeByCategory[category], resolver.TypeByCategory{
-			Group:   gvk.Group,
-			Version: gvk.Version,
-			Kind:    gvk.Kind,
-			Scope:   string(resourceScope),
-		})
+		g.typeByCategory[category] = append(g.typeByCategory[category], resource)
 	}
 
 	return nil
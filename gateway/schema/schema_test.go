@@ -0,0 +1,604 @@
+package schema_test
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_ConfigMapBinaryDataAndImmutable(t *testing.T) {
+	configMap := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"data":       *spec.MapProperty(spec.StringProperty()),
+				"binaryData": *spec.MapProperty(spec.StrFmtProperty("byte")),
+				"immutable":  *spec.BooleanProperty(),
+			},
+		},
+	}
+	configMap.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "ConfigMap"},
+	})
+	configMap.AddExtension(common.ScopeExtensionKey, "Namespaced")
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{"io.k8s.api.core.v1.ConfigMap": configMap}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	configMapType, ok := g.GetSchema().Type("ConfigMap").(*graphql.Object)
+	require.True(t, ok)
+	fields := configMapType.Fields()
+	assert.Equal(t, "StringMapInput", fields["data"].Type.Name())
+	assert.Equal(t, "Base64StringMap", fields["binaryData"].Type.Name())
+	assert.Equal(t, graphql.Boolean, fields["immutable"].Type)
+
+	inputType, ok := g.GetSchema().Type("ConfigMapInput").(*graphql.InputObject)
+	require.True(t, ok)
+	inputFields := inputType.Fields()
+	assert.Equal(t, "Base64StringMap", inputFields["binaryData"].Type.Name())
+	assert.Equal(t, graphql.Boolean, inputFields["immutable"].Type)
+}
+
+func TestNew_UIHints(t *testing.T) {
+	account := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"displayName": *spec.StringProperty(),
+							"type":        *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+	account.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "core.openmfp.org", "version": "v1alpha1", "kind": "Account"},
+	})
+	account.AddExtension(common.ScopeExtensionKey, "Cluster")
+	// The extension is read from the schema file, i.e. as decoded JSON
+	account.AddExtension(common.UIHintsExtensionKey, map[string]interface{}{
+		"displayName": "Account",
+		"fields": map[string]interface{}{
+			"spec.type": map[string]interface{}{"widget": "select", "order": float64(2)},
+		},
+	})
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{"io.openmfp.core.v1alpha1.Account": account}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	accountType, ok := g.GetSchema().Type("Account").(*graphql.Object)
+	require.True(t, ok)
+	assert.Equal(t, `ui-hints: {"displayName":"Account"}`, accountType.Description())
+
+	specType, ok := accountType.Fields()["spec"].Type.(*graphql.Object)
+	require.True(t, ok)
+	assert.Equal(t, `ui-hints: {"widget":"select","order":2}`, specType.Fields()["type"].Description)
+	assert.Empty(t, specType.Fields()["displayName"].Description)
+
+	inputType, ok := g.GetSchema().Type("AccountInput").(*graphql.InputObject)
+	require.True(t, ok)
+	specInputType, ok := inputType.Fields()["spec"].Type.(*graphql.InputObject)
+	require.True(t, ok)
+	assert.Equal(t, `ui-hints: {"widget":"select","order":2}`, specInputType.Fields()["type"].Description())
+}
+
+func TestNew_Lease(t *testing.T) {
+	lease := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"holderIdentity": *spec.StringProperty(),
+							"renewTime":      *spec.StringProperty(),
+						},
+					},
+				},
+			},
+		},
+	}
+	lease.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "coordination.k8s.io", "version": "v1", "kind": "Lease"},
+	})
+	lease.AddExtension(common.ScopeExtensionKey, "Namespaced")
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{"io.k8s.api.coordination.v1.Lease": lease}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	leaseType, ok := g.GetSchema().Type("Lease").(*graphql.Object)
+	require.True(t, ok)
+	fields := leaseType.Fields()
+	assert.Equal(t, graphql.String, fields["holder"].Type)
+	assert.Equal(t, graphql.Int, fields["renewedSecondsAgo"].Type)
+	assert.Equal(t, "Boolean!", fields["expired"].Type.String())
+
+	leaderOf, ok := g.GetSchema().QueryType().Fields()["leaderOf"]
+	require.True(t, ok)
+	assert.Equal(t, leaseType, leaderOf.Type)
+}
+
+func TestNew_Deprecations(t *testing.T) {
+	newDefinition := func(group, version, kind string, deprecation map[string]interface{}) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"kind": *spec.StringProperty()},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": group, "version": version, "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		if deprecation != nil {
+			definition.AddExtension(common.DeprecationExtensionKey, deprecation)
+		}
+		return definition
+	}
+
+	definitions := spec.Definitions{
+		"io.k8s.api.policy.v1beta1.PodDisruptionBudget": newDefinition("policy", "v1beta1", "PodDisruptionBudget", map[string]interface{}{
+			"warning":      "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget",
+			"deprecatedIn": "v1.21",
+			"removedIn":    "v1.25",
+		}),
+		"com.example.v1alpha1.Widget": newDefinition("example.com", "v1alpha1", "Widget", map[string]interface{}{
+			"warning": "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget",
+		}),
+		"io.k8s.api.apps.v1.Deployment": newDefinition("apps", "v1", "Deployment", nil),
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *g.GetSchema(),
+		Context:       t.Context(),
+		RequestString: `{ deprecations { group version kind warning deprecatedIn removedIn } }`,
+	})
+	require.Empty(t, result.Errors)
+
+	assert.Equal(t, map[string]interface{}{
+		"deprecations": []interface{}{
+			map[string]interface{}{
+				"group":        "example.com",
+				"version":      "v1alpha1",
+				"kind":         "Widget",
+				"warning":      "example.com/v1alpha1 Widget is deprecated; use example.com/v1 Widget",
+				"deprecatedIn": nil,
+				"removedIn":    nil,
+			},
+			map[string]interface{}{
+				"group":        "policy",
+				"version":      "v1beta1",
+				"kind":         "PodDisruptionBudget",
+				"warning":      "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget",
+				"deprecatedIn": "v1.21",
+				"removedIn":    "v1.25",
+			},
+		},
+	}, result.Data)
+}
+
+func TestNew_SchemaDiagnostics(t *testing.T) {
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{}, resolver.New(log, nil), schema.WithGenerationErrors([]common.GenerationError{
+		{GroupVersion: "example.com/v1", Definition: "com.example.v1.Widget", Error: "invalid schema"},
+		{GroupVersion: "metrics.example.com/v1", Error: "service unavailable"},
+	}))
+	require.NoError(t, err)
+
+	result := graphql.Do(graphql.Params{
+		Schema:        *g.GetSchema(),
+		Context:       t.Context(),
+		RequestString: `{ schemaDiagnostics { skippedDefinitions { groupVersion definition error } } }`,
+	})
+	require.Empty(t, result.Errors)
+
+	assert.Equal(t, map[string]interface{}{
+		"schemaDiagnostics": map[string]interface{}{
+			"skippedDefinitions": []interface{}{
+				map[string]interface{}{"groupVersion": "example.com/v1", "definition": "com.example.v1.Widget", "error": "invalid schema"},
+				map[string]interface{}{"groupVersion": "metrics.example.com/v1", "definition": nil, "error": "service unavailable"},
+			},
+		},
+	}, result.Data)
+}
+
+func TestNew_ClusterDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		defaults *resolver.ClusterDefaults
+		expected map[string]interface{}
+	}{
+		{
+			name:     "not_declared",
+			expected: map[string]interface{}{"defaultNamespace": "", "commonKinds": []interface{}{}},
+		},
+		{
+			name:     "declared",
+			defaults: &resolver.ClusterDefaults{DefaultNamespace: "team-a", CommonKinds: []string{"Deployment", "ConfigMap"}},
+			expected: map[string]interface{}{"defaultNamespace": "team-a", "commonKinds": []interface{}{"Deployment", "ConfigMap"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := testlogger.New().HideLogOutput().Logger
+			g, err := schema.New(log, spec.Definitions{}, resolver.New(log, nil).WithClusterDefaults(tt.defaults))
+			require.NoError(t, err)
+
+			result := graphql.Do(graphql.Params{
+				Schema:        *g.GetSchema(),
+				Context:       t.Context(),
+				RequestString: `{ clusterDefaults { defaultNamespace commonKinds } }`,
+			})
+			require.Empty(t, result.Errors)
+			assert.Equal(t, map[string]interface{}{"clusterDefaults": tt.expected}, result.Data)
+		})
+	}
+}
+
+func TestNew_KubeconfigIssuance(t *testing.T) {
+	configMap := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{"data": *spec.MapProperty(spec.StringProperty())},
+		},
+	}
+	configMap.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "ConfigMap"},
+	})
+	configMap.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions := spec.Definitions{"io.k8s.api.core.v1.ConfigMap": configMap}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	assert.NotContains(t, g.GetSchema().MutationType().Fields(), "issueKubeconfig")
+
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithKubeconfigIssuance())
+	require.NoError(t, err)
+	field, ok := g.GetSchema().MutationType().Fields()["issueKubeconfig"]
+	require.True(t, ok)
+	assert.Equal(t, "IssuedKubeconfig!", field.Type.String())
+
+	args := make([]string, len(field.Args))
+	for i, arg := range field.Args {
+		args[i] = arg.Name()
+	}
+	assert.ElementsMatch(t, []string{"ttl"}, args)
+}
+
+func TestNew_InputOnlyFields(t *testing.T) {
+	cluster := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type: spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{
+							"endpoint":       *spec.StringProperty(),
+							"bootstrapToken": *spec.StringProperty(),
+							"credentials": {
+								SchemaProps: spec.SchemaProps{
+									Type:       spec.StringOrArray{"object"},
+									Properties: map[string]spec.Schema{"token": *spec.StringProperty()},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	cluster.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "example.com", "version": "v1", "kind": "Cluster"},
+	})
+	cluster.AddExtension(common.ScopeExtensionKey, "Cluster")
+	cluster.AddExtension(common.InputOnlyFieldsExtensionKey, []interface{}{"spec.bootstrapToken", "spec.credentials.token"})
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{"com.example.v1.Cluster": cluster}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	specType, ok := g.GetSchema().Type("Clusterspec").(*graphql.Object)
+	require.True(t, ok)
+	// credentials only holds input-only fields, so it is left out as a whole
+	assert.Equal(t, []string{"endpoint"}, sortedKeys(specType.Fields()))
+
+	specInputType, ok := g.GetSchema().Type("ClusterspecInput").(*graphql.InputObject)
+	require.True(t, ok)
+	assert.Equal(t, []string{"bootstrapToken", "credentials", "endpoint"}, sortedKeys(specInputType.Fields()))
+}
+
+func TestNew_Relations(t *testing.T) {
+	refSchema := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{"name": *spec.StringProperty(), "namespace": *spec.StringProperty()},
+		},
+	}
+	cluster := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{"spec": *spec.MapProperty(spec.StringProperty())},
+		},
+	}
+	cluster.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "example.com", "version": "v1", "kind": "Cluster"},
+	})
+	workload := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"spec": {
+					SchemaProps: spec.SchemaProps{
+						Type:       spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{"clusterRef": refSchema},
+					},
+				},
+				"backupRef": refSchema,
+				"backup":    *spec.RefSchema("#/definitions/com.example.v1.Cluster"),
+				"cluster":   *spec.RefSchema("#/definitions/com.example.v1.Cluster"),
+			},
+		},
+	}
+	workload.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "example.com", "version": "v1", "kind": "Workload"},
+	})
+	workload.AddExtension(common.RelationsExtensionKey, []interface{}{
+		map[string]interface{}{"field": "spec.clusterRef", "kind": "Cluster", "group": "example.com", "name": "cluster"},
+	})
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{
+		"com.example.v1.Cluster":  cluster,
+		"com.example.v1.Workload": workload,
+	}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	workloadType, ok := g.GetSchema().Type("Workload").(*graphql.Object)
+	require.True(t, ok)
+	for _, name := range []string{"backup", "cluster"} {
+		field := workloadType.Fields()[name]
+		require.NotNil(t, field, name)
+		assert.IsType(t, &graphql.Object{}, field.Type)
+		assert.NotNil(t, field.Resolve, "relationship field %s is resolved", name)
+	}
+
+	// The relationship fields aren't part of the object
+	workloadInputType, ok := g.GetSchema().Type("WorkloadInput").(*graphql.InputObject)
+	require.True(t, ok)
+	assert.Equal(t, []string{"backupRef", "spec"}, sortedKeys(workloadInputType.Fields()))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := slices.Collect(maps.Keys(m))
+	slices.Sort(keys)
+	return keys
+}
+
+func TestNew_Permissions(t *testing.T) {
+	configMap := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:       spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{"data": *spec.MapProperty(spec.StringProperty())},
+		},
+	}
+	configMap.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "", "version": "v1", "kind": "ConfigMap"},
+	})
+	configMap.AddExtension(common.ScopeExtensionKey, "Namespaced")
+	definitions := spec.Definitions{"io.k8s.api.core.v1.ConfigMap": configMap}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	assert.NotContains(t, g.GetSchema().QueryType().Fields(), "canI")
+	assert.NotContains(t, g.GetSchema().Type("ConfigMap").(*graphql.Object).Fields(), "permissions")
+
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithPermissions())
+	require.NoError(t, err)
+	assert.Equal(t, "Boolean!", g.GetSchema().QueryType().Fields()["canI"].Type.String())
+
+	permissions := g.GetSchema().Type("ConfigMap").(*graphql.Object).Fields()["permissions"]
+	require.NotNil(t, permissions)
+	permissionsType := graphql.GetNullable(permissions.Type).(*graphql.Object)
+	for _, verb := range resolver.PermissionVerbs {
+		assert.Contains(t, permissionsType.Fields(), verb)
+	}
+}
+
+func TestNew_OwnerReferences(t *testing.T) {
+	newDefinition := func(group, kind string) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"kind": *spec.StringProperty()},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": group, "version": "v1", "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		return definition
+	}
+	definitions := spec.Definitions{
+		"io.k8s.api.apps.v1.ReplicaSet":  newDefinition("apps", "ReplicaSet"),
+		"io.k8s.api.apps.v1.StatefulSet": newDefinition("apps", "StatefulSet"),
+		"io.k8s.api.core.v1.Pod":         newDefinition("", "Pod"),
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	assert.NotContains(t, g.GetSchema().Type("ReplicaSet").(*graphql.Object).Fields(), "owns")
+	assert.NotContains(t, g.GetSchema().Type("Pod").(*graphql.Object).Fields(), "ownedBy")
+
+	// The relations of Deployments and Jobs are left out, their kinds aren't part of the schema
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithOwnerReferences(resolver.DefaultOwnerRelations))
+	require.NoError(t, err)
+
+	owns := g.GetSchema().Type("ReplicaSet").(*graphql.Object).Fields()["owns"]
+	require.NotNil(t, owns)
+	assert.Equal(t, "[Pod!]!", graphql.GetNullable(owns.Type).(*graphql.Object).Fields()["Pods"].Type.String())
+	assert.NotContains(t, g.GetSchema().Type("Pod").(*graphql.Object).Fields(), "owns")
+
+	ownedBy := g.GetSchema().Type("Pod").(*graphql.Object).Fields()["ownedBy"]
+	require.NotNil(t, ownedBy)
+	assert.Equal(t, "[PodOwner!]!", ownedBy.Type.String())
+	owners := g.GetSchema().Type("PodOwner").(*graphql.Union).Types()
+	require.Len(t, owners, 2)
+	assert.ElementsMatch(t, []string{"ReplicaSet", "StatefulSet"}, []string{owners[0].Name(), owners[1].Name()})
+	assert.NotContains(t, g.GetSchema().Type("ReplicaSet").(*graphql.Object).Fields(), "ownedBy")
+}
+
+func TestNew_Events(t *testing.T) {
+	newDefinition := func(kind string) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"kind": *spec.StringProperty()},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": "", "version": "v1", "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		return definition
+	}
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.ConfigMap": newDefinition("ConfigMap"),
+		"io.k8s.api.core.v1.Event":     newDefinition("Event"),
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	assert.NotContains(t, g.GetSchema().Type("ConfigMap").(*graphql.Object).Fields(), "events")
+
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithEvents())
+	require.NoError(t, err)
+
+	events := g.GetSchema().Type("ConfigMap").(*graphql.Object).Fields()["events"]
+	require.NotNil(t, events)
+	assert.Equal(t, "[Event!]!", events.Type.String())
+	require.Len(t, events.Args, 2)
+	assert.ElementsMatch(t, []string{resolver.LimitArg, resolver.SinceSecondsArg}, []string{events.Args[0].Name(), events.Args[1].Name()})
+	assert.NotContains(t, g.GetSchema().Type("Event").(*graphql.Object).Fields(), "events")
+}
+
+func TestNew_ServedVersions(t *testing.T) {
+	newDefinition := func(version string, preferredVersion string) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"spec": *spec.MapProperty(spec.StringProperty()),
+				},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": "example.com", "version": version, "kind": "Widget"},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		if preferredVersion != "" {
+			definition.AddExtension(common.PreferredVersionKey, preferredVersion)
+		}
+		return definition
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{
+		"com.example.v1.Widget":      newDefinition("v1", ""),
+		"com.example.v1beta1.Widget": newDefinition("v1beta1", "v1"),
+	}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	exampleQuery, ok := g.GetSchema().QueryType().Fields()["example_com"].Type.(*graphql.Object)
+	require.True(t, ok)
+	queries := exampleQuery.Fields()
+	require.Contains(t, queries, "Widget")
+	require.Contains(t, queries, "v1beta1Widgets")
+	assert.Equal(t, "Widget!", queries["Widget"].Type.String())
+	assert.Equal(t, "v1beta1Widget!", queries["v1beta1Widget"].Type.String())
+	assert.Contains(t, g.GetSchema().Type("v1beta1Widget").Description(), "preferred version is v1")
+	assert.Contains(t, g.GetSchema().SubscriptionType().Fields(), "example_com_v1beta1widgets")
+
+	versions := map[string]string{}
+	for _, resource := range g.Resources() {
+		versions[resource.Singular] = resource.Version
+	}
+	assert.Equal(t, map[string]string{"Widget": "v1", "v1beta1Widget": "v1beta1"}, versions)
+}
+
+func TestNew_Features(t *testing.T) {
+	newDefinition := func(group, version, kind string) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"kind": *spec.StringProperty()},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": group, "version": version, "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		return definition
+	}
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.ConfigMap": newDefinition("", "v1", "ConfigMap"),
+		"io.k8s.api.core.v1.Secret":    newDefinition("", "v1", "Secret"),
+		"com.example.v1.Widget":        newDefinition("example.com", "v1", "Widget"),
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	require.NotNil(t, g.GetSchema().MutationType())
+	require.NotNil(t, g.GetSchema().SubscriptionType())
+	assert.NotNil(t, g.GetSchema().Type("Secret"))
+
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithoutMutations(), schema.WithoutSubscriptions())
+	require.NoError(t, err)
+	assert.Nil(t, g.GetSchema().MutationType())
+	assert.Nil(t, g.GetSchema().SubscriptionType())
+	assert.Contains(t, g.GetSchema().QueryType().Fields()["core"].Type.(*graphql.Object).Fields(), "ConfigMap")
+
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithDeniedKinds([]k8sschema.GroupVersionKind{
+		{Kind: "Secret"},
+		{Group: "example.com", Version: "v1beta1", Kind: "Widget"},
+	}))
+	require.NoError(t, err)
+	assert.Nil(t, g.GetSchema().Type("Secret"))
+	assert.NotNil(t, g.GetSchema().Type("ConfigMap"))
+	assert.NotNil(t, g.GetSchema().Type("Widget"))
+}
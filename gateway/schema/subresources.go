@@ -0,0 +1,172 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+)
+
+var scaleResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "ScaleResult",
+	Description: "The scale subresource of an object after a scale mutation",
+	Fields: graphql.Fields{
+		"replicas": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The desired amount of replicas",
+		},
+		"currentReplicas": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Int),
+			Description: "The amount of replicas observed by the controller of the object",
+		},
+		"selector": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The label selector of the replicas, empty if the object does not report one",
+		},
+	},
+})
+
+var tokenResultType = graphql.NewObject(graphql.ObjectConfig{
+	Name:        "TokenResult",
+	Description: "A token issued via the token subresource of a service account",
+	Fields: graphql.Fields{
+		"token": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "The issued token",
+		},
+		"expirationTimestamp": &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.String),
+			Description: "When the token expires, as RFC 3339 timestamp",
+		},
+	},
+})
+
+// DefaultSubresources are the subresources operations are generated for unless WithSubresources is given
+var DefaultSubresources = []string{common.StatusSubresource, common.ScaleSubresource}
+
+// ParseSubresources parses a comma separated list of the subresources operations are generated for, each one of
+// common.GatewaySubresources
+func ParseSubresources(value string) ([]string, error) {
+	var subresources []string
+	for _, subresource := range strings.Split(value, ",") {
+		subresource = strings.TrimSpace(subresource)
+		if subresource == "" {
+			continue
+		}
+		if !slices.Contains(common.GatewaySubresources, subresource) {
+			return nil, fmt.Errorf("unsupported subresource %q, expected one of %s", subresource, strings.Join(common.GatewaySubresources, ", "))
+		}
+		subresources = append(subresources, subresource)
+	}
+
+	return subresources, nil
+}
+
+// WithSubresources generates operations for the given subresources of the kinds serving them, instead of
+// DefaultSubresources
+func WithSubresources(subresources []string) Option {
+	return func(g *Gateway) {
+		g.subresources = make(map[string]bool, len(subresources))
+		for _, subresource := range subresources {
+			g.subresources[subresource] = true
+		}
+	}
+}
+
+// hasSubresource reports whether operations are generated for the subresource and the listener recorded it for the
+// resource
+func (g *Gateway) hasSubresource(resourceScheme spec.Schema, subresource string) bool {
+	return g.subresources[subresource] && common.HasSubresource(resourceScheme.Extensions, subresource)
+}
+
+// addSubresourceOperations adds the operations of the allowed subresources the listener recorded for the resource:
+// update<Kind>Status, scale<Kind>, evict<Kind> and create<Kind>Token mutations and proxy<Kind> queries. It returns
+// the subresources operations were generated for.
+func (g *Gateway) addSubresourceOperations(
+	queryGroupType, mutationGroupType *graphql.Object,
+	resourceScheme spec.Schema,
+	resourceType *graphql.Object,
+	resourceInputType *graphql.InputObject,
+	gvk *schema.GroupVersionKind,
+	resourceScope apiextensionsv1.ResourceScope,
+	singular string,
+) []string {
+	subresources := []string{}
+
+	itemArgsBuilder := func() *resolver.FieldConfigArgumentsBuilder {
+		builder := resolver.NewFieldConfigArguments().WithName().WithDryRun()
+		if resourceScope == apiextensionsv1.NamespaceScoped {
+			builder.WithNamespace()
+		}
+		return builder
+	}
+
+	// The status may be hidden by a schema transformation, in that case it can't be updated either
+	if _, hasStatus := resourceInputType.Fields()["status"]; hasStatus && g.hasSubresource(resourceScheme, common.StatusSubresource) {
+		mutationGroupType.AddFieldConfig("update"+singular+"Status", &graphql.Field{
+			Type:        resourceType,
+			Args:        itemArgsBuilder().WithObject(resourceInputType).Complete(),
+			Resolve:     g.resolver.UpdateItemStatus(*gvk, resourceScope),
+			Description: fmt.Sprintf("Update the status of a %s via its status subresource, only the status of the object is sent", singular),
+		})
+		subresources = append(subresources, common.StatusSubresource)
+	}
+
+	if g.hasSubresource(resourceScheme, common.ScaleSubresource) {
+		mutationGroupType.AddFieldConfig("scale"+singular, &graphql.Field{
+			Type:        graphql.NewNonNull(scaleResultType),
+			Args:        itemArgsBuilder().WithReplicas().Complete(),
+			Resolve:     g.resolver.ScaleItem(*gvk, resourceScope),
+			Description: fmt.Sprintf("Set the desired replicas of a %s via its scale subresource", singular),
+		})
+		subresources = append(subresources, common.ScaleSubresource)
+	}
+
+	if g.hasSubresource(resourceScheme, common.EvictionSubresource) {
+		mutationGroupType.AddFieldConfig("evict"+singular, &graphql.Field{
+			Type:        graphql.NewNonNull(graphql.Boolean),
+			Args:        itemArgsBuilder().WithGracePeriodSeconds().Complete(),
+			Resolve:     g.resolver.EvictItem(*gvk, resourceScope),
+			Description: fmt.Sprintf("Evict a %s via its eviction subresource, which respects its PodDisruptionBudgets unlike a deletion", singular),
+		})
+		subresources = append(subresources, common.EvictionSubresource)
+	}
+
+	if g.hasSubresource(resourceScheme, common.TokenSubresource) {
+		// Token requests can't be dry run
+		tokenArgs := resolver.NewFieldConfigArguments().WithName().WithTokenRequest()
+		if resourceScope == apiextensionsv1.NamespaceScoped {
+			tokenArgs.WithNamespace()
+		}
+		mutationGroupType.AddFieldConfig("create"+singular+"Token", &graphql.Field{
+			Type:        graphql.NewNonNull(tokenResultType),
+			Args:        tokenArgs.Complete(),
+			Resolve:     g.resolver.CreateItemToken(*gvk, resourceScope),
+			Description: fmt.Sprintf("Issue a token of a %s via its token subresource", singular),
+		})
+		subresources = append(subresources, common.TokenSubresource)
+	}
+
+	if g.hasSubresource(resourceScheme, common.ProxySubresource) {
+		proxyArgs := resolver.NewFieldConfigArguments().WithName().WithProxy()
+		if resourceScope == apiextensionsv1.NamespaceScoped {
+			proxyArgs.WithNamespace()
+		}
+		queryGroupType.AddFieldConfig("proxy"+singular, &graphql.Field{
+			Type:        graphql.String,
+			Args:        proxyArgs.Complete(),
+			Resolve:     g.resolver.ProxyItem(*gvk, resourceScope),
+			Description: fmt.Sprintf("Send a GET request to a %s via its proxy subresource and return the response body", singular),
+		})
+		subresources = append(subresources, common.ProxySubresource)
+	}
+
+	return subresources
+}
@@ -0,0 +1,138 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_SubresourceMutations(t *testing.T) {
+	newDefinition := func(kind string, subresources ...any) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"spec":   *spec.MapProperty(spec.StringProperty()),
+					"status": *spec.MapProperty(spec.StringProperty()),
+				},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": "apps", "version": "v1", "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		if len(subresources) > 0 {
+			// The extension is read from the schema file, i.e. as decoded JSON
+			definition.AddExtension(common.SubresourcesExtensionKey, subresources)
+		}
+		return definition
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment":         newDefinition("Deployment", "scale", "status"),
+		"io.k8s.api.apps.v1.ControllerRevision": newDefinition("ControllerRevision"),
+	}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	appsMutation, ok := g.GetSchema().MutationType().Fields()["apps"].Type.(*graphql.Object)
+	require.True(t, ok)
+	mutations := appsMutation.Fields()
+
+	require.Contains(t, mutations, "updateDeploymentStatus")
+	assert.Equal(t, "Deployment", mutations["updateDeploymentStatus"].Type.Name())
+	require.Contains(t, mutations, "scaleDeployment")
+	assert.Equal(t, "ScaleResult!", mutations["scaleDeployment"].Type.String())
+
+	var replicasArg *graphql.Argument
+	for _, arg := range mutations["scaleDeployment"].Args {
+		if arg.Name() == resolver.ReplicasArg {
+			replicasArg = arg
+		}
+	}
+	require.NotNil(t, replicasArg)
+	assert.Equal(t, "Int!", replicasArg.Type.String())
+
+	assert.NotContains(t, mutations, "updateControllerRevisionStatus")
+	assert.NotContains(t, mutations, "scaleControllerRevision")
+
+	for _, resource := range g.Resources() {
+		switch resource.Kind {
+		case "Deployment":
+			assert.Equal(t, []string{"status", "scale"}, resource.Subresources)
+		case "ControllerRevision":
+			assert.Empty(t, resource.Subresources)
+		}
+	}
+}
+
+func TestNew_SubresourceAllowList(t *testing.T) {
+	newDefinition := func(kind string, subresources ...any) spec.Schema {
+		definition := spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"spec": *spec.MapProperty(spec.StringProperty())},
+			},
+		}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": "", "version": "v1", "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		definition.AddExtension(common.SubresourcesExtensionKey, subresources)
+		return definition
+	}
+	definitions := spec.Definitions{
+		"io.k8s.api.core.v1.Pod":            newDefinition("Pod", "eviction", "proxy"),
+		"io.k8s.api.core.v1.ServiceAccount": newDefinition("ServiceAccount", "token"),
+	}
+
+	log := testlogger.New().HideLogOutput().Logger
+
+	coreFields := func(t *testing.T, g *schema.Gateway) (queries, mutations graphql.FieldDefinitionMap) {
+		queryType, ok := g.GetSchema().QueryType().Fields()["core"].Type.(*graphql.Object)
+		require.True(t, ok)
+		mutationType, ok := g.GetSchema().MutationType().Fields()["core"].Type.(*graphql.Object)
+		require.True(t, ok)
+		return queryType.Fields(), mutationType.Fields()
+	}
+
+	g, err := schema.New(log, definitions, resolver.New(log, nil))
+	require.NoError(t, err)
+	queries, mutations := coreFields(t, g)
+	assert.NotContains(t, mutations, "evictPod")
+	assert.NotContains(t, mutations, "createServiceAccountToken")
+	assert.NotContains(t, queries, "proxyPod")
+
+	subresources, err := schema.ParseSubresources("eviction, token,proxy")
+	require.NoError(t, err)
+	g, err = schema.New(log, definitions, resolver.New(log, nil), schema.WithSubresources(subresources))
+	require.NoError(t, err)
+	queries, mutations = coreFields(t, g)
+	require.Contains(t, mutations, "evictPod")
+	assert.Equal(t, "Boolean!", mutations["evictPod"].Type.String())
+	require.Contains(t, mutations, "createServiceAccountToken")
+	assert.Equal(t, "TokenResult!", mutations["createServiceAccountToken"].Type.String())
+	require.Contains(t, queries, "proxyPod")
+	assert.Equal(t, "String", queries["proxyPod"].Type.String())
+	assert.NotContains(t, queries, "proxyServiceAccount")
+
+	for _, resource := range g.Resources() {
+		switch resource.Kind {
+		case "Pod":
+			assert.Equal(t, []string{"eviction", "proxy"}, resource.Subresources)
+		case "ServiceAccount":
+			assert.Equal(t, []string{"token"}, resource.Subresources)
+		}
+	}
+
+	_, err = schema.ParseSubresources("exec")
+	assert.Error(t, err)
+}
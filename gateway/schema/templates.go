@@ -0,0 +1,126 @@
+package schema
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// objectTemplate returns a skeleton object of a resource for creation forms. It contains the fields with defaults,
+// set to their default, and the required fields, set to the zero value of their type. The status is left out, as
+// it is never set by users.
+func (g *Gateway) objectTemplate(resourceScheme spec.Schema) map[string]interface{} {
+	template := g.templateObject(resourceScheme, map[string]bool{})
+	delete(template, "status")
+
+	// Objects can't be created without a name, even if the metadata is not marked as required
+	metadata, _ := template["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	if _, ok := metadata["name"]; !ok {
+		metadata["name"] = ""
+	}
+	template["metadata"] = metadata
+
+	// Defaults are passed on as they were decoded, the round trip turns them into JSON values that can be deep copied
+	data, err := json.Marshal(template)
+	if err != nil {
+		g.log.Debug().Err(err).Msg("Failed to marshal object template")
+		return map[string]interface{}{"metadata": map[string]interface{}{"name": ""}}
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		g.log.Debug().Err(err).Msg("Failed to unmarshal object template")
+		return map[string]interface{}{"metadata": map[string]interface{}{"name": ""}}
+	}
+
+	return normalized
+}
+
+// templateObject returns the template of an object schema, visitedRefs prevents endless recursion
+func (g *Gateway) templateObject(s spec.Schema, visitedRefs map[string]bool) map[string]interface{} {
+	template := map[string]interface{}{}
+	for name, property := range s.Properties {
+		property, refKey := g.resolveTemplateRef(property)
+		if visitedRefs[refKey] {
+			continue
+		}
+		if refKey != "" {
+			visitedRefs[refKey] = true
+		}
+
+		switch {
+		case property.Default != nil:
+			template[name] = property.Default
+		case slices.Contains(s.Required, name):
+			template[name] = g.templatePlaceholder(property, visitedRefs)
+		case isTemplateObject(property):
+			// Optional objects are only added if they contain defaults, e.g. spec.strategy of a Deployment
+			if nested := g.templateObject(property, visitedRefs); len(nested) > 0 {
+				template[name] = nested
+			}
+		}
+
+		delete(visitedRefs, refKey)
+	}
+
+	return template
+}
+
+// templatePlaceholder returns the value of a required field without default
+func (g *Gateway) templatePlaceholder(s spec.Schema, visitedRefs map[string]bool) interface{} {
+	if intOrString, _ := s.Extensions[common.IntOrStringExtensionKey].(bool); intOrString {
+		return ""
+	}
+	if len(s.Type) == 0 {
+		return nil
+	}
+
+	switch s.Type[0] {
+	case "string":
+		if len(s.Enum) > 0 {
+			return s.Enum[0]
+		}
+		return ""
+	case "integer":
+		return int64(0)
+	case "number":
+		return float64(0)
+	case "boolean":
+		return false
+	case "array":
+		return []interface{}{}
+	case "object":
+		return g.templateObject(s, visitedRefs)
+	default:
+		return nil
+	}
+}
+
+// resolveTemplateRef returns the definition the schema refers to and its key, or the schema itself if it is no reference
+func (g *Gateway) resolveTemplateRef(s spec.Schema) (spec.Schema, string) {
+	refKey := strings.TrimPrefix(s.Ref.String(), "#/definitions/")
+	if refKey == "" {
+		return s, ""
+	}
+
+	definition, ok := g.definitions[refKey]
+	if !ok {
+		return s, ""
+	}
+
+	// A default next to the reference takes precedence over the default of the definition
+	if s.Default != nil {
+		definition.Default = s.Default
+	}
+	return definition, refKey
+}
+
+func isTemplateObject(s spec.Schema) bool {
+	return len(s.Type) == 1 && s.Type[0] == "object" && len(s.Properties) > 0
+}
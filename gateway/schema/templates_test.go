@@ -0,0 +1,113 @@
+package schema_test
+
+import (
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestNew_TemplateQuery(t *testing.T) {
+	withDefault := func(property *spec.Schema, value interface{}) spec.Schema {
+		return *property.WithDefault(value)
+	}
+	required := func(property *spec.Schema, names ...string) spec.Schema {
+		return *property.WithRequired(names...)
+	}
+	modeProperty := spec.StringProperty()
+	modeProperty.Enum = []interface{}{"Recreate", "RollingUpdate"}
+
+	deployment := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type:     spec.StringOrArray{"object"},
+			Required: []string{"spec"},
+			Properties: map[string]spec.Schema{
+				"apiVersion": *spec.StringProperty(),
+				"kind":       *spec.StringProperty(),
+				"metadata":   *spec.RefProperty("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"),
+				"spec": required(&spec.Schema{SchemaProps: spec.SchemaProps{
+					Type: spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{
+						"replicas": withDefault(spec.Int32Property(), 1),
+						"image":    *spec.StringProperty(),
+						"mode":     *modeProperty,
+						"paused":   *spec.BoolProperty(),
+						"ports":    *spec.ArrayProperty(spec.Int32Property()),
+						"strategy": {SchemaProps: spec.SchemaProps{
+							Type: spec.StringOrArray{"object"},
+							Properties: map[string]spec.Schema{
+								"maxSurge": withDefault(spec.StringProperty(), "25%"),
+							},
+						}},
+						"selector": {SchemaProps: spec.SchemaProps{
+							Type:       spec.StringOrArray{"object"},
+							Properties: map[string]spec.Schema{"matchLabels": *spec.MapProperty(spec.StringProperty())},
+						}},
+					},
+				}}, "image", "mode", "ports"),
+				"status": required(&spec.Schema{SchemaProps: spec.SchemaProps{
+					Type:       spec.StringOrArray{"object"},
+					Properties: map[string]spec.Schema{"replicas": *spec.Int32Property()},
+				}}, "replicas"),
+			},
+		},
+	}
+	deployment.AddExtension(common.GVKExtensionKey, []interface{}{
+		map[string]interface{}{"group": "apps", "version": "v1", "kind": "Deployment"},
+	})
+	deployment.AddExtension(common.ScopeExtensionKey, "Namespaced")
+
+	objectMeta := spec.Schema{SchemaProps: spec.SchemaProps{
+		Type: spec.StringOrArray{"object"},
+		Properties: map[string]spec.Schema{
+			"name":      *spec.StringProperty(),
+			"namespace": *spec.StringProperty(),
+		},
+	}}
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, spec.Definitions{
+		"io.k8s.api.apps.v1.Deployment":                   deployment,
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": objectMeta,
+	}, resolver.New(log, nil))
+	require.NoError(t, err)
+
+	appsQuery, ok := g.GetSchema().QueryType().Fields()["apps"].Type.(*graphql.Object)
+	require.True(t, ok)
+	templateQuery, ok := appsQuery.Fields()["templateDeployment"]
+	require.True(t, ok)
+	assert.Equal(t, "Deployment!", templateQuery.Type.String())
+
+	result, err := templateQuery.Resolve(graphql.ResolveParams{
+		Context: t.Context(),
+		Args:    map[string]interface{}{resolver.NamespaceArg: "default"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata":   map[string]interface{}{"name": "", "namespace": "default"},
+		"spec": map[string]interface{}{
+			"replicas": float64(1),
+			"image":    "",
+			"mode":     "Recreate",
+			"ports":    []interface{}{},
+			"strategy": map[string]interface{}{"maxSurge": "25%"},
+		},
+	}, result)
+
+	// Every call returns a fresh copy, so that callers can't change the template
+	result.(map[string]interface{})["spec"].(map[string]interface{})["image"] = "nginx"
+	again, err := templateQuery.Resolve(graphql.ResolveParams{Context: t.Context(), Args: map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.Equal(t, "", again.(map[string]interface{})["spec"].(map[string]interface{})["image"])
+	assert.Equal(t, map[string]interface{}{"name": ""}, again.(map[string]interface{})["metadata"])
+}
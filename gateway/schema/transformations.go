@@ -0,0 +1,291 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+var (
+	ErrReadSchemaTransformations   = errors.New("failed to read schema transformations file")
+	ErrParseSchemaTransformations  = errors.New("failed to parse schema transformations file")
+	ErrInvalidSchemaTransformation = errors.New("invalid schema transformation")
+)
+
+// SchemaScalar is the scalar a field is generated as, regardless of its OpenAPI type
+type SchemaScalar string
+
+const (
+	// ScalarJSON serializes the field as JSON string, e.g. for fields whose schema can't be represented in GraphQL
+	ScalarJSON SchemaScalar = "JSON"
+	// ScalarString passes the field as string
+	ScalarString SchemaScalar = "String"
+)
+
+var graphqlNameRegex = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// SchemaTransformation changes how the schema of the resources of a kind is generated.
+// Fields are addressed by their dot-separated path, items of an array are addressed like the array itself.
+type SchemaTransformation struct {
+	// Group of the kind, core refers to the core API group. Resources of all groups match if empty.
+	Group string `json:"group,omitempty"`
+	Kind  string `json:"kind"`
+	// Rename is the name of the GraphQL type instead of the kind, the names of the operations are derived from it
+	Rename string `json:"rename,omitempty"`
+	// HideFields are removed from the types, input types and operations of the resource
+	HideFields []string `json:"hideFields,omitempty"`
+	// Aliases adds a second name, the value, next to the field at the path of the key
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Scalars generates the fields at the paths of the keys as scalars instead of their OpenAPI type
+	Scalars map[string]SchemaScalar `json:"scalars,omitempty"`
+}
+
+// SchemaTransformationsConfig represents the schema transformations file structure
+type SchemaTransformationsConfig struct {
+	Transformations []SchemaTransformation `json:"transformations"`
+}
+
+// WithTransformations applies the transformations to the definitions before the schema is generated
+func WithTransformations(transformations []SchemaTransformation) Option {
+	return func(g *Gateway) {
+		g.transformations = transformations
+	}
+}
+
+// LoadSchemaTransformations reads the schema transformations from a YAML or JSON file.
+// An empty path results in the schema being generated as described by the definitions.
+func LoadSchemaTransformations(path string) ([]SchemaTransformation, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Join(ErrReadSchemaTransformations, err)
+	}
+	defer f.Close()
+
+	var cfg SchemaTransformationsConfig
+	if err := utilyaml.NewYAMLOrJSONDecoder(f, 4096).Decode(&cfg); err != nil {
+		return nil, errors.Join(ErrParseSchemaTransformations, err)
+	}
+
+	for _, transformation := range cfg.Transformations {
+		if err := transformation.validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg.Transformations, nil
+}
+
+func (t SchemaTransformation) validate() error {
+	if t.Kind == "" {
+		return fmt.Errorf("%w: kind is required", ErrInvalidSchemaTransformation)
+	}
+	if t.Rename != "" && !graphqlNameRegex.MatchString(t.Rename) {
+		return fmt.Errorf("%w: %s: %q is not a valid GraphQL name", ErrInvalidSchemaTransformation, t.Kind, t.Rename)
+	}
+
+	paths := slices.Concat(t.HideFields, slices.Collect(maps.Keys(t.Aliases)), slices.Collect(maps.Keys(t.Scalars)))
+	for _, path := range paths {
+		if path == "" || slices.Contains(strings.Split(path, "."), "") {
+			return fmt.Errorf("%w: %s: %q is not a dot-separated path", ErrInvalidSchemaTransformation, t.Kind, path)
+		}
+	}
+
+	for path, alias := range t.Aliases {
+		if !graphqlNameRegex.MatchString(alias) {
+			return fmt.Errorf("%w: %s: alias %q of %s is not a valid GraphQL name", ErrInvalidSchemaTransformation, t.Kind, alias, path)
+		}
+	}
+
+	for path, scalar := range t.Scalars {
+		if scalar != ScalarJSON && scalar != ScalarString {
+			return fmt.Errorf("%w: %s: unknown scalar %q for %s, use %s or %s", ErrInvalidSchemaTransformation, t.Kind, scalar, path, ScalarJSON, ScalarString)
+		}
+	}
+
+	return nil
+}
+
+func (t SchemaTransformation) matches(group, kind string) bool {
+	if t.Kind != kind {
+		return false
+	}
+
+	return t.Group == "" || t.Group == group || (group == "" && t.Group == coreGroupAlias)
+}
+
+// transformation returns the transformation of the resources of the group and kind, if any
+func (g *Gateway) transformation(group, kind string) (SchemaTransformation, bool) {
+	for _, transformation := range g.transformations {
+		if transformation.matches(group, kind) {
+			return transformation, true
+		}
+	}
+
+	return SchemaTransformation{}, false
+}
+
+// applyTransformations returns the definitions with the fields of the transformations hidden or replaced by scalars.
+// Definitions referenced on the way to a transformed field are copied into the resource, so that other resources
+// referencing the same definitions are not affected. The given definitions are not modified.
+func (g *Gateway) applyTransformations(definitions spec.Definitions) spec.Definitions {
+	transformed := maps.Clone(definitions)
+
+	for key, definition := range definitions {
+		gvk, ok := common.GroupVersionKind(definition.Extensions)
+		if !ok {
+			continue
+		}
+		transformation, ok := g.transformation(gvk.Group, gvk.Kind)
+		if !ok {
+			continue
+		}
+
+		log := g.log.With().Str("resource", key).Logger()
+
+		for _, path := range transformation.HideFields {
+			definition, ok = transformField(definitions, definition, strings.Split(path, "."), func(properties map[string]spec.Schema, name string) bool {
+				_, found := properties[name]
+				delete(properties, name)
+				return found
+			})
+			if !ok {
+				log.Warn().Str("field", path).Msg("Field to hide not found")
+			}
+		}
+
+		for path, scalar := range transformation.Scalars {
+			definition, ok = transformField(definitions, definition, strings.Split(path, "."), func(properties map[string]spec.Schema, name string) bool {
+				field, found := properties[name]
+				if !found {
+					return false
+				}
+
+				replacement := spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}}
+				if scalar == ScalarString {
+					replacement = *spec.StringProperty()
+				}
+				replacement.Description = g.schemaFieldDescription(definitions, field)
+				properties[name] = replacement
+				return true
+			})
+			if !ok {
+				log.Warn().Str("field", path).Msg("Field to force to a scalar not found")
+			}
+		}
+
+		// The aliases are added while generating the fields, their parents are copied so that they only appear in this resource
+		for path := range transformation.Aliases {
+			definition, ok = transformField(definitions, definition, strings.Split(path, "."), func(properties map[string]spec.Schema, name string) bool {
+				_, found := properties[name]
+				return found
+			})
+			if !ok {
+				log.Warn().Str("field", path).Msg("Field to alias not found")
+			}
+		}
+
+		transformed[key] = definition
+	}
+
+	return transformed
+}
+
+// schemaFieldDescription keeps the description of a field whose schema is replaced, falling back to the referenced definition
+func (g *Gateway) schemaFieldDescription(definitions spec.Definitions, field spec.Schema) string {
+	if field.Description == "" && field.Ref.GetURL() != nil {
+		return definitions[strings.TrimPrefix(field.Ref.String(), "#/definitions/")].Description
+	}
+	return field.Description
+}
+
+// transformField calls transform with the properties of the object holding the field at path and returns the changed schema.
+// The properties of every object on the way are copied before they are changed, references are replaced by the definition.
+func transformField(definitions spec.Definitions, schema spec.Schema, path []string, transform func(properties map[string]spec.Schema, name string) bool) (spec.Schema, bool) {
+	if schema.Ref.GetURL() != nil {
+		definition, ok := definitions[strings.TrimPrefix(schema.Ref.String(), "#/definitions/")]
+		if !ok {
+			return schema, false
+		}
+		if schema.Description != "" {
+			definition.Description = schema.Description
+		}
+		schema = definition
+	}
+
+	if slices.Contains(schema.Type, "array") {
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return schema, false
+		}
+		item, ok := transformField(definitions, *schema.Items.Schema, path, transform)
+		if ok {
+			schema.Items = &spec.SchemaOrArray{Schema: &item}
+		}
+		return schema, ok
+	}
+
+	properties := maps.Clone(schema.Properties)
+	if len(path) == 1 {
+		if !transform(properties, path[0]) {
+			return schema, false
+		}
+		schema.Properties = properties
+		return schema, true
+	}
+
+	field, found := properties[path[0]]
+	if !found {
+		return schema, false
+	}
+	field, ok := transformField(definitions, field, path[1:], transform)
+	if !ok {
+		return schema, false
+	}
+	properties[path[0]] = field
+	schema.Properties = properties
+
+	return schema, true
+}
+
+// addAliasFields adds the aliases of the fields of the object at fieldPath of the resource that is currently generated.
+// Aliases are output only, so that mutations don't receive the same field twice.
+func (g *Gateway) addAliasFields(fields graphql.Fields, properties map[string]spec.Schema, fieldPath []string) {
+	for fieldName := range properties {
+		alias, ok := g.fieldAliases[strings.Join(append(slices.Clone(fieldPath), fieldName), ".")]
+		if !ok {
+			continue
+		}
+
+		field, ok := fields[sanitizeFieldName(fieldName)]
+		if !ok {
+			continue
+		}
+		if _, exists := fields[alias]; exists {
+			g.log.Warn().Str("field", fieldName).Str("alias", alias).Msg("Alias collides with an existing field")
+			continue
+		}
+
+		fields[alias] = &graphql.Field{
+			Type:              field.Type,
+			Description:       field.Description,
+			DeprecationReason: field.DeprecationReason,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				source, _ := p.Source.(map[string]interface{})
+				return source[fieldName], nil
+			},
+		}
+	}
+}
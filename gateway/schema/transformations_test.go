@@ -0,0 +1,210 @@
+package schema_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/graphql-go/graphql"
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/resolver"
+	"github.com/openmfp/kubernetes-graphql-gateway/gateway/schema"
+)
+
+func TestLoadSchemaTransformations(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expectedErr error
+		expected    []schema.SchemaTransformation
+	}{
+		{
+			name: "valid",
+			content: `transformations:
+  - group: core
+    kind: Pod
+    rename: Workload
+    hideFields: [metadata.managedFields]
+    aliases:
+      spec.nodeName: node
+    scalars:
+      status.conditions: JSON
+`,
+			expected: []schema.SchemaTransformation{{
+				Group:      "core",
+				Kind:       "Pod",
+				Rename:     "Workload",
+				HideFields: []string{"metadata.managedFields"},
+				Aliases:    map[string]string{"spec.nodeName": "node"},
+				Scalars:    map[string]schema.SchemaScalar{"status.conditions": schema.ScalarJSON},
+			}},
+		},
+		{
+			name:        "missing_kind",
+			content:     "transformations: [{rename: Workload}]",
+			expectedErr: schema.ErrInvalidSchemaTransformation,
+		},
+		{
+			name:        "invalid_rename",
+			content:     "transformations: [{kind: Pod, rename: my-pod}]",
+			expectedErr: schema.ErrInvalidSchemaTransformation,
+		},
+		{
+			name:        "invalid_path",
+			content:     "transformations: [{kind: Pod, hideFields: [spec..nodeName]}]",
+			expectedErr: schema.ErrInvalidSchemaTransformation,
+		},
+		{
+			name:        "invalid_alias",
+			content:     "transformations: [{kind: Pod, aliases: {spec.nodeName: node-name}}]",
+			expectedErr: schema.ErrInvalidSchemaTransformation,
+		},
+		{
+			name:        "unknown_scalar",
+			content:     "transformations: [{kind: Pod, scalars: {status.conditions: Int}}]",
+			expectedErr: schema.ErrInvalidSchemaTransformation,
+		},
+		{
+			name:        "invalid_yaml",
+			content:     "transformations: {",
+			expectedErr: schema.ErrParseSchemaTransformations,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "transformations.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			transformations, err := schema.LoadSchemaTransformations(path)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, transformations)
+		})
+	}
+}
+
+func TestNew_Transformations(t *testing.T) {
+	objectMeta := spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"object"},
+			Properties: map[string]spec.Schema{
+				"name":          *spec.StringProperty(),
+				"managedFields": *spec.ArrayProperty(spec.StringProperty()),
+			},
+		},
+	}
+	metadataRef := spec.Schema{SchemaProps: spec.SchemaProps{Ref: spec.MustCreateRef("#/definitions/io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta")}}
+
+	resource := func(kind string, properties map[string]spec.Schema) spec.Schema {
+		definition := spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: properties}}
+		definition.AddExtension(common.GVKExtensionKey, []interface{}{
+			map[string]interface{}{"group": "", "version": "v1", "kind": kind},
+		})
+		definition.AddExtension(common.ScopeExtensionKey, "Namespaced")
+		return definition
+	}
+
+	definitions := spec.Definitions{
+		"io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta": objectMeta,
+		"io.k8s.api.core.v1.Pod": resource("Pod", map[string]spec.Schema{
+			"metadata": metadataRef,
+			"spec": {SchemaProps: spec.SchemaProps{
+				Type:       spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{"nodeName": *spec.StringProperty()},
+			}},
+			"status": {SchemaProps: spec.SchemaProps{
+				Type: spec.StringOrArray{"object"},
+				Properties: map[string]spec.Schema{
+					"conditions": *spec.ArrayProperty(&spec.Schema{SchemaProps: spec.SchemaProps{
+						Type:       spec.StringOrArray{"object"},
+						Properties: map[string]spec.Schema{"type": *spec.StringProperty()},
+					}}),
+				},
+			}},
+		}),
+		"io.k8s.api.core.v1.ConfigMap": resource("ConfigMap", map[string]spec.Schema{
+			"metadata": metadataRef,
+		}),
+	}
+
+	transformations := []schema.SchemaTransformation{{
+		Group:      "core",
+		Kind:       "Pod",
+		Rename:     "Workload",
+		HideFields: []string{"metadata.managedFields", "spec.missing"},
+		Aliases:    map[string]string{"spec.nodeName": "node"},
+		Scalars:    map[string]schema.SchemaScalar{"status.conditions": schema.ScalarJSON},
+	}}
+
+	log := testlogger.New().HideLogOutput().Logger
+	g, err := schema.New(log, definitions, resolver.New(log, nil), schema.WithTransformations(transformations))
+	require.NoError(t, err)
+	gqlSchema := g.GetSchema()
+
+	t.Run("rename", func(t *testing.T) {
+		assert.Nil(t, gqlSchema.Type("Pod"))
+		require.NotNil(t, gqlSchema.Type("Workload"))
+
+		coreType, ok := gqlSchema.QueryType().Fields()["core"].Type.(*graphql.Object)
+		require.True(t, ok)
+		assert.Contains(t, coreType.Fields(), "Workloads")
+		assert.NotContains(t, coreType.Fields(), "Pods")
+	})
+
+	workloadType, ok := gqlSchema.Type("Workload").(*graphql.Object)
+	require.True(t, ok)
+
+	t.Run("hide_fields", func(t *testing.T) {
+		metadataType, ok := workloadType.Fields()["metadata"].Type.(*graphql.Object)
+		require.True(t, ok)
+		assert.NotContains(t, metadataType.Fields(), "managedFields")
+		assert.Contains(t, metadataType.Fields(), "name")
+
+		configMapType, ok := gqlSchema.Type("ConfigMap").(*graphql.Object)
+		require.True(t, ok)
+		sharedMetadataType, ok := configMapType.Fields()["metadata"].Type.(*graphql.Object)
+		require.True(t, ok)
+		assert.Contains(t, sharedMetadataType.Fields(), "managedFields", "other resources keep the field")
+
+		assert.Contains(t, definitions["io.k8s.apimachinery.pkg.apis.meta.v1.ObjectMeta"].Properties, "managedFields", "the definitions are not modified")
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		specType, ok := workloadType.Fields()["spec"].Type.(*graphql.Object)
+		require.True(t, ok)
+		require.Contains(t, specType.Fields(), "node")
+		assert.Equal(t, graphql.String, specType.Fields()["node"].Type)
+
+		value, err := specType.Fields()["node"].Resolve(graphql.ResolveParams{Source: map[string]interface{}{"nodeName": "worker-1"}})
+		require.NoError(t, err)
+		assert.Equal(t, "worker-1", value)
+
+		inputType, ok := gqlSchema.Type("WorkloadInput").(*graphql.InputObject)
+		require.True(t, ok)
+		specInputType, ok := inputType.Fields()["spec"].Type.(*graphql.InputObject)
+		require.True(t, ok)
+		assert.NotContains(t, specInputType.Fields(), "node", "aliases are output only")
+	})
+
+	t.Run("scalars", func(t *testing.T) {
+		statusType, ok := workloadType.Fields()["status"].Type.(*graphql.Object)
+		require.True(t, ok)
+		assert.Equal(t, schema.JSONStringScalarForTest, statusType.Fields()["conditions"].Type)
+	})
+}
+
+func TestLoadSchemaTransformations_EmptyPath(t *testing.T) {
+	transformations, err := schema.LoadSchemaTransformations("")
+	require.NoError(t, err)
+	assert.Empty(t, transformations)
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// uiHintPrefix starts the description of types and fields with UI hints, followed by the hint as JSON
+const uiHintPrefix = "ui-hints: "
+
+// getUIHints returns the UI hints the listener copied from the CRD of the resource, if any
+func (g *Gateway) getUIHints(resourceKey string) *common.UIHints {
+	raw, ok := g.definitions[resourceKey].Extensions[common.UIHintsExtensionKey]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Failed to marshal UI hints")
+		return nil
+	}
+
+	hints, err := common.ParseUIHints(data)
+	if err != nil {
+		g.log.Debug().Err(err).Str("resource", resourceKey).Msg("Failed to parse UI hints")
+		return nil
+	}
+
+	return hints
+}
+
+// fieldDescription returns the description of the field at fieldPath of the resource that is currently generated
+func (g *Gateway) fieldDescription(fieldPath []string) string {
+	hint, ok := g.fieldUIHints[strings.Join(fieldPath, ".")]
+	if !ok {
+		return ""
+	}
+
+	return uiHintDescription(hint)
+}
+
+func uiHintDescription(hint common.UIHint) string {
+	if hint == (common.UIHint{}) {
+		return ""
+	}
+
+	data, err := json.Marshal(hint)
+	if err != nil {
+		return ""
+	}
+
+	return uiHintPrefix + string(data)
+}
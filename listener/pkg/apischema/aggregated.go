@@ -0,0 +1,165 @@
+package apischema
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/hashicorp/go-multierror"
+	runtimeSchema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+var (
+	ErrListAPIServices = errors.New("failed to list APIServices")
+	ErrGetOpenAPIV2    = errors.New("failed to get OpenAPI v2 document")
+)
+
+// apiService holds the fields of an APIService the listener needs, the kube-aggregator types are not a dependency
+type apiService struct {
+	Spec struct {
+		Group   string `json:"group"`
+		Version string `json:"version"`
+		// Service is only set for APIs served by an aggregated API server, local APIs have none
+		Service *struct{} `json:"service"`
+	} `json:"spec"`
+	Status struct {
+		Conditions []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+type apiServiceList struct {
+	Items []apiService `json:"items"`
+}
+
+// available reports whether the aggregator reaches the API server of the APIService
+func (s apiService) available() bool {
+	for _, condition := range s.Status.Conditions {
+		if condition.Type == "Available" {
+			return condition.Status == "True"
+		}
+	}
+	return false
+}
+
+// listAggregatedAPIs returns the group versions served by aggregated API servers, split into the available ones and
+// the ones the aggregator can't reach
+func listAggregatedAPIs(dc discovery.DiscoveryInterface) (available []string, unavailable []string, err error) {
+	restClient := dc.RESTClient()
+	if restClient == nil {
+		return nil, nil, ErrListAPIServices
+	}
+
+	raw, err := restClient.Get().AbsPath("/apis/apiregistration.k8s.io/v1/apiservices").DoRaw(context.Background())
+	if err != nil {
+		return nil, nil, errors.Join(ErrListAPIServices, err)
+	}
+
+	var list apiServiceList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, nil, errors.Join(ErrListAPIServices, err)
+	}
+
+	for _, service := range list.Items {
+		if service.Spec.Service == nil {
+			continue
+		}
+		groupVersion := runtimeSchema.GroupVersion{Group: service.Spec.Group, Version: service.Spec.Version}.String()
+		if service.available() {
+			available = append(available, groupVersion)
+		} else {
+			unavailable = append(unavailable, groupVersion)
+		}
+	}
+
+	return available, unavailable, nil
+}
+
+// WithOpenAPIV2Fallback adds the definitions of the aggregated group versions that are missing in the OpenAPI v3
+// documents, e.g. of aggregated API servers that only publish OpenAPI v2, from the OpenAPI v2 document the aggregator
+// merges from all API servers. Definitions already taken from OpenAPI v3 are kept.
+func (b *SchemaBuilder) WithOpenAPIV2Fallback(dc discovery.DiscoveryInterface, groupVersions []string) *SchemaBuilder {
+	missing := slices.DeleteFunc(slices.Clone(groupVersions), func(groupVersion string) bool {
+		return b.hasGroupVersion(groupVersion)
+	})
+	if len(missing) == 0 {
+		return b
+	}
+
+	restClient := dc.RESTClient()
+	if restClient == nil {
+		b.log.Warn().Err(ErrGetOpenAPIV2).Strs("groupVersions", missing).Msg("no REST client, skipping aggregated APIs")
+		return b
+	}
+
+	raw, err := restClient.Get().AbsPath("/openapi/v2").SetHeader("Accept", "application/json").DoRaw(context.Background())
+	if err != nil {
+		b.log.Warn().Err(errors.Join(ErrGetOpenAPIV2, err)).Strs("groupVersions", missing).Msg("skipping aggregated APIs")
+		return b
+	}
+
+	var document struct {
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(raw, &document); err != nil {
+		b.err = multierror.Append(b.err, errors.Join(ErrGetOpenAPIV2, err))
+		return b
+	}
+
+	for key, rawDefinition := range document.Definitions {
+		if _, ok := b.schemas[key]; ok {
+			continue
+		}
+		var definition *spec.Schema
+		if err := json.Unmarshal(rawDefinition, &definition); err != nil {
+			b.log.Debug().Err(err).Str("definition", key).Msg("skipping invalid OpenAPI v2 definition")
+			continue
+		}
+		if definition == nil {
+			continue
+		}
+
+		// Kinds of other group versions are left out, their definitions are already taken from OpenAPI v3
+		gvk, ok := definitionGroupVersionKind(definition)
+		if ok && !slices.Contains(missing, gvk.groupVersion()) {
+			continue
+		}
+		b.schemas[key] = definition
+	}
+
+	b.log.Info().Strs("groupVersions", missing).Msg("added aggregated APIs from OpenAPI v2")
+	return b
+}
+
+// hasGroupVersion reports whether a kind of the group version has a schema
+func (b *SchemaBuilder) hasGroupVersion(groupVersion string) bool {
+	for _, schema := range b.schemas {
+		gvk, ok := definitionGroupVersionKind(schema)
+		if ok && gvk.groupVersion() == groupVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// definitionGroupVersionKind returns the kind of a definition with a single GVK extension
+func definitionGroupVersionKind(schema *spec.Schema) (GroupVersionKind, bool) {
+	if schema == nil {
+		return GroupVersionKind{}, false
+	}
+	gvks, err := definitionGVKs(*schema)
+	if err != nil || len(gvks) != 1 {
+		return GroupVersionKind{}, false
+	}
+	return GroupVersionKind{Group: gvks[0].Group, Version: gvks[0].Version, Kind: gvks[0].Kind}, true
+}
+
+// groupVersion returns the group version in the format of the discovery documents, e.g. apps/v1 or v1
+func (gvk GroupVersionKind) groupVersion() string {
+	return runtimeSchema.GroupVersion{Group: gvk.Group, Version: gvk.Version}.String()
+}
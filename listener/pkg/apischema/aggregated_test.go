@@ -0,0 +1,100 @@
+package apischema_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/openapi"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	apischema "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	apischemaMocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
+)
+
+func newFakeRESTClient(t *testing.T, path string, body string) *restfake.RESTClient {
+	return &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, path, req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		}),
+	}
+}
+
+func TestListAggregatedAPIs(t *testing.T) {
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(newFakeRESTClient(t, "/apis/apiregistration.k8s.io/v1/apiservices", `{"items": [
+		{"spec": {"version": "v1"}, "status": {"conditions": [{"type": "Available", "status": "True"}]}},
+		{"spec": {"group": "metrics.k8s.io", "version": "v1beta1", "service": {"name": "metrics-server", "namespace": "kube-system"}},
+		 "status": {"conditions": [{"type": "Available", "status": "True"}]}},
+		{"spec": {"group": "custom.metrics.k8s.io", "version": "v1beta2", "service": {"name": "adapter", "namespace": "monitoring"}},
+		 "status": {"conditions": [{"type": "Available", "status": "False", "message": "endpoints not found"}]}}
+	]}`))
+
+	available, unavailable, err := apischema.ListAggregatedAPIs(dc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"metrics.k8s.io/v1beta1"}, available)
+	assert.Equal(t, []string{"custom.metrics.k8s.io/v1beta2"}, unavailable)
+}
+
+func TestListAggregatedAPIs_NoRESTClient(t *testing.T) {
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(nil)
+
+	_, _, err := apischema.ListAggregatedAPIs(dc)
+	assert.ErrorIs(t, err, apischema.ErrListAPIServices)
+}
+
+func TestWithOpenAPIV2Fallback(t *testing.T) {
+	newSchema := func(description, group, version, kind string) *spec.Schema {
+		schema := &spec.Schema{SchemaProps: spec.SchemaProps{Description: description}}
+		schema.AddExtension(common.GVKExtensionKey, []map[string]string{{"group": group, "version": version, "kind": kind}})
+		return schema
+	}
+
+	openAPIClient := apischemaMocks.NewMockClient(t)
+	openAPIClient.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(openAPIClient, nil, testlogger.New().HideLogOutput().Logger)
+	b.SetSchemas(map[string]*spec.Schema{
+		"io.k8s.api.core.v1.Pod": newSchema("from v3", "", "v1", "Pod"),
+	})
+
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(newFakeRESTClient(t, "/openapi/v2", `{"definitions": {
+		"io.k8s.api.core.v1.Pod": {"description": "from v2", "x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Pod"}]},
+		"io.k8s.api.apps.v1.Deployment": {"description": "from v2", "x-kubernetes-group-version-kind": [{"group": "apps", "version": "v1", "kind": "Deployment"}]},
+		"io.k8s.metrics.pkg.apis.metrics.v1beta1.NodeMetrics": {"description": "from v2", "x-kubernetes-group-version-kind": [{"group": "metrics.k8s.io", "version": "v1beta1", "kind": "NodeMetrics"}]},
+		"io.k8s.metrics.pkg.apis.metrics.v1beta1.ContainerMetrics": {"description": "from v2"}
+	}}`))
+
+	b.WithOpenAPIV2Fallback(dc, []string{"v1", "metrics.k8s.io/v1beta1"})
+	require.NoError(t, b.GetError())
+
+	schemas := b.GetSchemas()
+	assert.Equal(t, "from v3", schemas["io.k8s.api.core.v1.Pod"].Description)
+	assert.Contains(t, schemas, "io.k8s.metrics.pkg.apis.metrics.v1beta1.NodeMetrics")
+	assert.Contains(t, schemas, "io.k8s.metrics.pkg.apis.metrics.v1beta1.ContainerMetrics")
+	assert.NotContains(t, schemas, "io.k8s.api.apps.v1.Deployment")
+}
+
+func TestWithOpenAPIV2Fallback_NothingMissing(t *testing.T) {
+	openAPIClient := apischemaMocks.NewMockClient(t)
+	openAPIClient.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(openAPIClient, nil, testlogger.New().HideLogOutput().Logger)
+
+	// The discovery client isn't used if there are no aggregated APIs
+	b.WithOpenAPIV2Fallback(apischemaMocks.NewMockDiscoveryInterface(t), nil)
+	assert.NoError(t, b.GetError())
+}
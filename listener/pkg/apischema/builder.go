@@ -28,17 +28,22 @@ var (
 	ErrMarshalOpenAPISchema = errors.New("failed to marshal openAPI v3 runtimeSchema")
 	ErrConvertOpenAPISchema = errors.New("failed to convert openAPI v3 runtimeSchema to v2")
 	ErrCRDNoVersions        = errors.New("CRD has no versions defined")
-	ErrMarshalGVK           = errors.New("failed to marshal GVK extension")
-	ErrUnmarshalGVK         = errors.New("failed to unmarshal GVK extension")
 	ErrBuildKindRegistry    = errors.New("failed to build kind registry")
 )
 
+// DefaultRelationDepth only adds relationship fields to kinds that are not referenced themselves
+const DefaultRelationDepth = 1
+
 type SchemaBuilder struct {
 	schemas           map[string]*spec.Schema
 	err               *multierror.Error
 	log               *logger.Logger
 	kindRegistry      map[GroupVersionKind]ResourceInfo // Changed: Use GVK as key for precise lookup
 	preferredVersions map[string]string                 // map[group/kind]preferredVersion
+	// generationErrors are the group versions and definitions left out of the schema, see GenerationErrors
+	generationErrors []common.GenerationError
+	// maxRelationDepth limits the chains of relationship fields, see WithMaxRelationDepth
+	maxRelationDepth int
 }
 
 // ResourceInfo holds information about a resource for relationship resolution
@@ -54,6 +59,7 @@ func NewSchemaBuilder(oc openapi.Client, preferredApiGroups []string, log *logge
 		schemas:           make(map[string]*spec.Schema),
 		kindRegistry:      make(map[GroupVersionKind]ResourceInfo),
 		preferredVersions: make(map[string]string),
+		maxRelationDepth:  DefaultRelationDepth,
 		log:               log,
 	}
 
@@ -64,11 +70,23 @@ func NewSchemaBuilder(oc openapi.Client, preferredApiGroups []string, log *logge
 	}
 
 	for path, gv := range apiv3Paths {
-		schema, err := getSchemaForPath(preferredApiGroups, path, gv)
-		if err != nil {
+		schema, skipped, err := getSchemaForPath(preferredApiGroups, path, gv)
+		if errors.Is(err, ErrInvalidPath) || errors.Is(err, ErrNotPreferred) {
 			b.log.Debug().Err(err).Str("path", path).Msg("skipping schema path")
 			continue
 		}
+		if err != nil {
+			// The other group versions are still served, the broken one is reported in the schema file
+			_, groupVersion, _ := strings.Cut(path, separator)
+			b.log.Warn().Err(err).Str("groupVersion", groupVersion).Msg("skipping group version with invalid schema")
+			b.generationErrors = append(b.generationErrors, common.GenerationError{GroupVersion: groupVersion, Error: err.Error()})
+			continue
+		}
+		for _, definition := range skipped {
+			b.log.Warn().Str("groupVersion", definition.GroupVersion).Str("definition", definition.Definition).Str("error", definition.Error).
+				Msg("skipping invalid definition")
+		}
+		b.generationErrors = append(b.generationErrors, skipped...)
 		maps.Copy(b.schemas, schema)
 	}
 
@@ -81,26 +99,30 @@ type GroupVersionKind struct {
 	Kind    string `json:"kind"`
 }
 
+// definitionGVKs returns the kinds of the GVK extension of a definition, none if the definition has no such extension
+func definitionGVKs(schema spec.Schema) ([]metav1.GroupVersionKind, error) {
+	gvks, err := common.GroupVersionKinds(schema.Extensions)
+	if err != nil {
+		return nil, err
+	}
+
+	var converted []metav1.GroupVersionKind
+	for _, gvk := range gvks {
+		converted = append(converted, metav1.GroupVersionKind(gvk))
+	}
+	return converted, nil
+}
+
 func (b *SchemaBuilder) WithScope(rm meta.RESTMapper) *SchemaBuilder {
 	for _, schema := range b.schemas {
 		//skip resources that do not have the GVK extension:
 		//assumption: sub-resources do not have GVKs
-		if schema.VendorExtensible.Extensions == nil {
-			continue
-		}
-		var gvksVal any
-		var ok bool
-		if gvksVal, ok = schema.VendorExtensible.Extensions[common.GVKExtensionKey]; !ok {
-			continue
-		}
-		jsonBytes, err := json.Marshal(gvksVal)
+		gvks, err := definitionGVKs(*schema)
 		if err != nil {
-			b.err = multierror.Append(b.err, errors.Join(ErrMarshalGVK, err))
+			b.err = multierror.Append(b.err, err)
 			continue
 		}
-		gvks := make([]*GroupVersionKind, 0, 1)
-		if err := json.Unmarshal(jsonBytes, &gvks); err != nil {
-			b.err = multierror.Append(b.err, errors.Join(ErrUnmarshalGVK, err))
+		if len(gvks) == 0 {
 			continue
 		}
 
@@ -162,6 +184,124 @@ func (b *SchemaBuilder) WithCRDCategories(crd *apiextensionsv1.CustomResourceDef
 	return b
 }
 
+// WithCRDUIHints copies the UI hints annotation of the CRDs into the schemas of all their versions.
+// Invalid hints are skipped, so that a single CRD can't break the schema of a cluster.
+func (b *SchemaBuilder) WithCRDUIHints(crds ...*apiextensionsv1.CustomResourceDefinition) *SchemaBuilder {
+	for _, crd := range crds {
+		if crd == nil {
+			continue
+		}
+
+		raw, ok := crd.Annotations[common.UIHintsAnnotation]
+		if !ok {
+			continue
+		}
+
+		hints, err := common.ParseUIHints([]byte(raw))
+		if err != nil {
+			b.log.Warn().Err(err).Str("crdName", crd.Name).Msg("skipping UI hints of CRD")
+			continue
+		}
+
+		for _, v := range crd.Spec.Versions {
+			resourceKey := getOpenAPISchemaKey(metav1.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind})
+			resourceSchema, ok := b.schemas[resourceKey]
+			if !ok {
+				continue
+			}
+
+			resourceSchema.VendorExtensible.AddExtension(common.UIHintsExtensionKey, hints)
+		}
+	}
+	return b
+}
+
+// WithCRDRelations copies the relations annotation of the CRDs into the schemas of all their versions, see
+// WithRelationships. Invalid relations are skipped, so that a single CRD can't break the schema of a cluster.
+func (b *SchemaBuilder) WithCRDRelations(crds ...*apiextensionsv1.CustomResourceDefinition) *SchemaBuilder {
+	for _, crd := range crds {
+		if crd == nil {
+			continue
+		}
+
+		raw, ok := crd.Annotations[common.RelationsAnnotation]
+		if !ok {
+			continue
+		}
+
+		relations, err := common.ParseRelations([]byte(raw))
+		if err != nil {
+			b.log.Warn().Err(err).Str("crdName", crd.Name).Msg("skipping relations of CRD")
+			continue
+		}
+
+		for _, v := range crd.Spec.Versions {
+			resourceKey := getOpenAPISchemaKey(metav1.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind})
+			resourceSchema, ok := b.schemas[resourceKey]
+			if !ok {
+				continue
+			}
+
+			resourceSchema.VendorExtensible.AddExtension(common.RelationsExtensionKey, relations)
+		}
+	}
+	return b
+}
+
+// WithCRDInputOnlyFields copies the input-only fields annotation of the CRDs into the schemas of all their versions
+func (b *SchemaBuilder) WithCRDInputOnlyFields(crds ...*apiextensionsv1.CustomResourceDefinition) *SchemaBuilder {
+	for _, crd := range crds {
+		if crd == nil {
+			continue
+		}
+
+		paths := common.ParseFieldPaths(crd.Annotations[common.InputOnlyFieldsAnnotation])
+		if len(paths) == 0 {
+			continue
+		}
+
+		for _, v := range crd.Spec.Versions {
+			resourceKey := getOpenAPISchemaKey(metav1.GroupVersionKind{Group: crd.Spec.Group, Version: v.Name, Kind: crd.Spec.Names.Kind})
+			if resourceSchema, ok := b.schemas[resourceKey]; ok {
+				addInputOnlyFields(resourceSchema, paths)
+			}
+		}
+	}
+	return b
+}
+
+// WithInputOnlyFieldRules marks the fields of the configured rules as input-only in the schemas of all versions of their kinds
+func (b *SchemaBuilder) WithInputOnlyFieldRules(rules []common.InputOnlyFieldRule) *SchemaBuilder {
+	if len(rules) == 0 {
+		return b
+	}
+
+	for _, schema := range b.schemas {
+		gvks, err := definitionGVKs(*schema)
+		if err != nil {
+			b.err = multierror.Append(b.err, err)
+			continue
+		}
+		if len(gvks) != 1 {
+			continue
+		}
+
+		for _, rule := range rules {
+			if rule.Group == gvks[0].Group && rule.Kind == gvks[0].Kind {
+				addInputOnlyFields(schema, rule.Fields)
+			}
+		}
+	}
+	return b
+}
+
+// addInputOnlyFields merges the paths into the input-only fields extension of the schema
+func addInputOnlyFields(schema *spec.Schema, paths []string) {
+	merged := slices.Concat(common.InputOnlyFields(schema.VendorExtensible.Extensions), paths)
+	slices.Sort(merged)
+	schema.VendorExtensible.AddExtension(common.InputOnlyFieldsExtensionKey, slices.Compact(merged))
+}
+
 func (b *SchemaBuilder) WithApiResourceCategories(list []*metav1.APIResourceList) *SchemaBuilder {
 	if len(list) == 0 {
 		return b
@@ -190,6 +330,76 @@ func (b *SchemaBuilder) WithApiResourceCategories(list []*metav1.APIResourceList
 	return b
 }
 
+// WithSubresources records the subresources served by the API server which the gateway generates operations for, see
+// common.GatewaySubresources. Discovery lists subresources as separate resources named <resource>/<subresource>.
+func (b *SchemaBuilder) WithSubresources(list []*metav1.APIResourceList) *SchemaBuilder {
+	for _, apiResourceList := range list {
+		gv, err := runtimeSchema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			b.err = multierror.Append(b.err, errors.Join(ErrParseGroupVersion, err))
+			continue
+		}
+
+		kinds := make(map[string]string, len(apiResourceList.APIResources))
+		for _, apiResource := range apiResourceList.APIResources {
+			if !strings.Contains(apiResource.Name, "/") {
+				kinds[apiResource.Name] = apiResource.Kind
+			}
+		}
+
+		for _, apiResource := range apiResourceList.APIResources {
+			resource, subresource, ok := strings.Cut(apiResource.Name, "/")
+			if !ok || !slices.Contains(common.GatewaySubresources, subresource) {
+				continue
+			}
+			kind, ok := kinds[resource]
+			if !ok {
+				continue
+			}
+
+			resourceKey := getOpenAPISchemaKey(metav1.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: kind})
+			resourceSchema, ok := b.schemas[resourceKey]
+			if !ok {
+				continue
+			}
+			subresources := slices.Concat(common.Subresources(resourceSchema.Extensions), []string{subresource})
+			slices.Sort(subresources)
+			resourceSchema.VendorExtensible.AddExtension(common.SubresourcesExtensionKey, slices.Compact(subresources))
+			b.schemas[resourceKey] = resourceSchema
+		}
+	}
+	return b
+}
+
+// WithVerbs records the verbs the API server supports for every resource, so that clients of the gateway can tell
+// which of the generated operations can succeed at all
+func (b *SchemaBuilder) WithVerbs(list []*metav1.APIResourceList) *SchemaBuilder {
+	for _, apiResourceList := range list {
+		gv, err := runtimeSchema.ParseGroupVersion(apiResourceList.GroupVersion)
+		if err != nil {
+			b.err = multierror.Append(b.err, errors.Join(ErrParseGroupVersion, err))
+			continue
+		}
+
+		for _, apiResource := range apiResourceList.APIResources {
+			if strings.Contains(apiResource.Name, "/") || len(apiResource.Verbs) == 0 {
+				continue
+			}
+
+			resourceKey := getOpenAPISchemaKey(metav1.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: apiResource.Kind})
+			resourceSchema, ok := b.schemas[resourceKey]
+			if !ok {
+				continue
+			}
+			verbs := []string(slices.Clone(apiResource.Verbs))
+			slices.Sort(verbs)
+			resourceSchema.VendorExtensible.AddExtension(common.VerbsExtensionKey, slices.Compact(verbs))
+			b.schemas[resourceKey] = resourceSchema
+		}
+	}
+	return b
+}
+
 // WithPreferredVersions populates preferred version information from API discovery
 func (b *SchemaBuilder) WithPreferredVersions(apiResLists []*metav1.APIResourceList) *SchemaBuilder {
 	for _, apiResList := range apiResLists {
@@ -217,28 +427,116 @@ func (b *SchemaBuilder) WithPreferredVersions(apiResLists []*metav1.APIResourceL
 	return b
 }
 
-// WithRelationships adds relationship fields to schemas that have *Ref fields
-// Uses 1-level depth control to prevent circular references and N+1 problems
+// WithVersionPreferences records the preferred version in the schemas of the other versions a kind is served in, so
+// that the gateway serves the preferred version under the plain kind name. Requires WithPreferredVersions.
+func (b *SchemaBuilder) WithVersionPreferences() *SchemaBuilder {
+	for schemaKey, schema := range b.schemas {
+		gvks, err := definitionGVKs(*schema)
+		if err != nil {
+			b.err = multierror.Append(b.err, err)
+			continue
+		}
+		if len(gvks) != 1 {
+			continue
+		}
+
+		preferredVersion, ok := b.preferredVersions[fmt.Sprintf("%s/%s", gvks[0].Group, gvks[0].Kind)]
+		if !ok || preferredVersion == gvks[0].Version {
+			continue
+		}
+
+		schema.VendorExtensible.AddExtension(common.PreferredVersionKey, preferredVersion)
+		b.log.Debug().Str("schemaKey", schemaKey).Str("preferredVersion", preferredVersion).Msg("added version preference")
+	}
+	return b
+}
+
+// WithDeprecations adds the deprecation warnings of the API server to the schemas of the deprecated kinds
+func (b *SchemaBuilder) WithDeprecations(deprecations map[metav1.GroupVersionKind]common.Deprecation) *SchemaBuilder {
+	if len(deprecations) == 0 {
+		return b
+	}
+
+	for schemaKey, schema := range b.schemas {
+		gvks, err := definitionGVKs(*schema)
+		if err != nil {
+			b.err = multierror.Append(b.err, err)
+			continue
+		}
+		if len(gvks) != 1 {
+			continue
+		}
+
+		deprecation, ok := deprecations[gvks[0]]
+		if !ok {
+			continue
+		}
+
+		schema.VendorExtensible.AddExtension(common.DeprecationExtensionKey, deprecation)
+		b.log.Debug().Str("schemaKey", schemaKey).Str("warning", deprecation.Warning).Msg("added deprecation")
+	}
+	return b
+}
+
+// WithMaxRelationDepth limits the chains of relationship fields to depth relations, e.g. with a depth of 2 the kind
+// referenced by a binding gets relationship fields as well, but the kinds it references don't. 0 adds no relationship
+// fields. It must be set before WithRelationships.
+func (b *SchemaBuilder) WithMaxRelationDepth(depth int) *SchemaBuilder {
+	b.maxRelationDepth = depth
+	return b
+}
+
+// WithRelationships adds relationship fields to schemas that have *Ref fields or relations declared with WithCRDRelations
+// Uses depth control to prevent circular references and N+1 problems, see WithMaxRelationDepth
 func (b *SchemaBuilder) WithRelationships() *SchemaBuilder {
 	// Build kind registry first
 	b.buildKindRegistry()
 
-	// Expand relationships with 1-level depth control
-	b.expandWithSimpleDepthControl()
+	b.expandWithDepthControl()
 
 	return b
 }
 
-// expandWithSimpleDepthControl implements the working 1-level depth control
-func (b *SchemaBuilder) expandWithSimpleDepthControl() {
-	// First pass: identify relation targets
+// relationEdge is a *Ref field or a declared relation of a schema and the resource it refers to
+type relationEdge struct {
+	propName string
+	// fieldName is the relationship field added for the reference
+	fieldName string
+	target    ResourceInfo
+}
+
+// expandWithDepthControl adds the relationship fields up to the maximum depth. Kinds that are not referenced by other
+// kinds are the roots of the chains at level 0, every other kind is at the level of the longest chain without cycles
+// that leads to it from a root. A kind only gets relationship fields below the maximum depth, and only to kinds of a
+// higher level, so that no chain of relationship fields is longer than the maximum depth and none is circular.
+func (b *SchemaBuilder) expandWithDepthControl() {
+	// First pass: identify relation targets and the references of every schema
 	relationTargets := make(map[string]bool)
-	for _, schema := range b.schemas {
+	edges := make(map[string][]relationEdge)
+	for schemaKey, schema := range b.schemas {
 		if schema.Properties == nil {
 			continue
 		}
+
+		declared := make(map[string]bool)
+		for _, relation := range common.Relations(schema.Extensions) {
+			declared[relation.Field] = true
+			target := b.findResourceForRelation(schema, relation)
+			if target == nil {
+				b.log.Warn().
+					Str("kind", relation.Kind).
+					Str("group", relation.Group).
+					Str("sourceField", relation.Field).
+					Str("sourceSchema", schemaKey).
+					Msg("Declared relation has no field or target kind - skipping relationship field")
+				continue
+			}
+			relationTargets[target.SchemaKey] = true
+			edges[schemaKey] = append(edges[schemaKey], relationEdge{propName: relation.Field, fieldName: relation.Name, target: *target})
+		}
+
 		for propName := range schema.Properties {
-			if !isRefProperty(propName) {
+			if !isRefProperty(propName) || declared[propName] {
 				continue
 			}
 			baseKind := strings.TrimSuffix(propName, "Ref")
@@ -248,21 +546,74 @@ func (b *SchemaBuilder) expandWithSimpleDepthControl() {
 			for _, candidate := range candidates {
 				relationTargets[candidate.SchemaKey] = true
 			}
+
+			// Find best resource using kubectl-style priority
+			target := b.findBestResourceForKind(baseKind)
+			if target == nil {
+				// No candidates found - skip relationship field generation
+				b.log.Debug().
+					Str("kind", baseKind).
+					Str("sourceField", propName).
+					Str("sourceSchema", schemaKey).
+					Msg("No candidates found for kind - skipping relationship field")
+				continue
+			}
+			edges[schemaKey] = append(edges[schemaKey], relationEdge{propName: propName, fieldName: strings.ToLower(baseKind), target: *target})
+		}
+	}
+
+	levels := make(map[string]int)
+	for schemaKey := range edges {
+		if !relationTargets[schemaKey] {
+			b.assignRelationLevels(schemaKey, 0, map[string]bool{}, edges, levels)
 		}
 	}
 
 	b.log.Info().
 		Int("kindRegistrySize", len(b.kindRegistry)).
 		Int("relationTargets", len(relationTargets)).
-		Msg("Starting 1-level relationship expansion")
-
-	// Second pass: expand only non-targets
-	for schemaKey, schema := range b.schemas {
-		if relationTargets[schemaKey] {
-			b.log.Debug().Str("schemaKey", schemaKey).Msg("Skipping relation target (1-level depth control)")
+		Int("maxRelationDepth", b.maxRelationDepth).
+		Msg("Starting relationship expansion")
+
+	// Second pass: expand the schemas below the maximum depth, kinds of cycles no root leads to are not expanded
+	for schemaKey, schemaEdges := range edges {
+		level, ok := levels[schemaKey]
+		if !ok || level >= b.maxRelationDepth {
+			b.log.Debug().Str("schemaKey", schemaKey).Msg("Skipping relation target (depth control)")
 			continue
 		}
-		b.expandRelationshipsSimple(schema, schemaKey)
+		for _, edge := range schemaEdges {
+			if targetLevel, ok := levels[edge.target.SchemaKey]; !ok || targetLevel <= level {
+				b.log.Debug().
+					Str("sourceSchema", schemaKey).
+					Str("sourceField", edge.propName).
+					Str("targetKind", edge.target.Kind).
+					Msg("Skipping circular relationship field")
+				continue
+			}
+			b.addRelationshipField(b.schemas[schemaKey], schemaKey, edge.propName, edge.fieldName, &edge.target)
+		}
+	}
+}
+
+// assignRelationLevels raises the level of the schemas reachable from schemaKey to the length of the longest chain
+// leading to them. path holds the schemas of the current chain, which are skipped to not follow cycles. Levels are
+// only tracked up to the maximum depth, deeper schemas aren't expanded anyway.
+func (b *SchemaBuilder) assignRelationLevels(schemaKey string, level int, path map[string]bool, edges map[string][]relationEdge, levels map[string]int) {
+	if current, ok := levels[schemaKey]; ok && current >= level {
+		return
+	}
+	levels[schemaKey] = level
+	if level >= b.maxRelationDepth {
+		return
+	}
+
+	path[schemaKey] = true
+	defer delete(path, schemaKey)
+	for _, edge := range edges[schemaKey] {
+		if !path[edge.target.SchemaKey] {
+			b.assignRelationLevels(edge.target.SchemaKey, level+1, path, edges, levels)
+		}
 	}
 }
 
@@ -270,26 +621,10 @@ func (b *SchemaBuilder) expandWithSimpleDepthControl() {
 func (b *SchemaBuilder) buildKindRegistry() {
 	for schemaKey, schema := range b.schemas {
 		// Extract GVK from schema
-		if schema.VendorExtensible.Extensions == nil {
-			continue
-		}
-
-		gvksVal, ok := schema.VendorExtensible.Extensions[common.GVKExtensionKey]
-		if !ok {
-			continue
-		}
-
-		jsonBytes, err := json.Marshal(gvksVal)
+		gvks, err := definitionGVKs(*schema)
 		if err != nil {
 			b.err = multierror.Append(b.err, errors.Join(ErrBuildKindRegistry, err))
-			b.log.Debug().Err(err).Str("schemaKey", schemaKey).Msg("failed to marshal GVK")
-			continue
-		}
-
-		var gvks []*GroupVersionKind
-		if err := json.Unmarshal(jsonBytes, &gvks); err != nil {
-			b.err = multierror.Append(b.err, errors.Join(ErrBuildKindRegistry, err))
-			b.log.Debug().Err(err).Str("schemaKey", schemaKey).Msg("failed to unmarshal GVK")
+			b.log.Debug().Err(err).Str("schemaKey", schemaKey).Msg("failed to parse GVK")
 			continue
 		}
 
@@ -364,43 +699,6 @@ func (b *SchemaBuilder) warnAboutMissingPreferredVersions() {
 	}
 }
 
-// expandRelationshipsSimple adds relationship fields for the simple 1-level depth control
-func (b *SchemaBuilder) expandRelationshipsSimple(schema *spec.Schema, schemaKey string) {
-	if schema.Properties == nil {
-		return
-	}
-
-	for propName := range schema.Properties {
-		if !isRefProperty(propName) {
-			continue
-		}
-
-		baseKind := strings.TrimSuffix(propName, "Ref")
-
-		// Add relationship field using kubectl-style priority resolution
-		b.processReferenceField(schema, schemaKey, propName, baseKind)
-	}
-}
-
-// processReferenceField handles individual reference field processing with kubectl-style priority resolution
-func (b *SchemaBuilder) processReferenceField(schema *spec.Schema, schemaKey, propName, baseKind string) {
-	// Find best resource using kubectl-style priority
-	bestResource := b.findBestResourceForKind(baseKind)
-
-	if bestResource == nil {
-		// No candidates found - skip relationship field generation
-		b.log.Debug().
-			Str("kind", baseKind).
-			Str("sourceField", propName).
-			Str("sourceSchema", schemaKey).
-			Msg("No candidates found for kind - skipping relationship field")
-		return
-	}
-
-	// Generate relationship field using the best resource
-	b.addRelationshipField(schema, schemaKey, propName, baseKind, bestResource)
-}
-
 // findBestResourceForKind finds the best resource for a kind using kubectl-style priority resolution
 func (b *SchemaBuilder) findBestResourceForKind(kindName string) *ResourceInfo {
 	candidates := b.findAllCandidatesForKind(kindName)
@@ -430,6 +728,32 @@ func (b *SchemaBuilder) findBestResourceForKind(kindName string) *ResourceInfo {
 	return &best
 }
 
+// findResourceForRelation finds the resource a declared relation refers to, if the schema has the field of the
+// relation. Without a version, the preferred version of the kind is chosen.
+func (b *SchemaBuilder) findResourceForRelation(schema *spec.Schema, relation common.Relation) *ResourceInfo {
+	properties := schema.Properties
+	for _, segment := range strings.Split(relation.Field, ".") {
+		property, ok := properties[segment]
+		if !ok {
+			return nil
+		}
+		properties = property.Properties
+	}
+
+	var candidates []ResourceInfo
+	for _, candidate := range b.findAllCandidatesForKind(relation.Kind) {
+		if candidate.Group == relation.Group && (relation.Version == "" || candidate.Version == relation.Version) {
+			candidates = append(candidates, candidate)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	best := b.selectByKubectlPriority(candidates)
+	return &best
+}
+
 // findAllCandidatesForKind finds all resources that match the given kind name
 func (b *SchemaBuilder) findAllCandidatesForKind(kindName string) []ResourceInfo {
 	candidates := make([]ResourceInfo, 0)
@@ -498,20 +822,14 @@ func (b *SchemaBuilder) formatGroupVersion(resource ResourceInfo) string {
 }
 
 // addRelationshipField adds a relationship field for unambiguous references
-func (b *SchemaBuilder) addRelationshipField(schema *spec.Schema, schemaKey, propName, baseKind string, target *ResourceInfo) {
-	fieldName := strings.ToLower(baseKind)
+func (b *SchemaBuilder) addRelationshipField(schema *spec.Schema, schemaKey, propName, fieldName string, target *ResourceInfo) {
 	if _, exists := schema.Properties[fieldName]; exists {
 		return
 	}
 
-	// Create proper reference - handle empty group (core) properly
-	var refPath string
-	if target.Group == "" {
-		refPath = fmt.Sprintf("#/definitions/%s.%s", target.Version, target.Kind)
-	} else {
-		refPath = fmt.Sprintf("#/definitions/%s.%s.%s", target.Group, target.Version, target.Kind)
-	}
-	ref := spec.MustCreateRef(refPath)
+	// Reference the definition of the target, the gateway resolves the field with the sibling *Ref field or the
+	// declared relation
+	ref := spec.MustCreateRef("#/definitions/" + target.SchemaKey)
 	schema.Properties[fieldName] = spec.Schema{SchemaProps: spec.SchemaProps{Ref: ref}}
 
 	b.log.Info().
@@ -519,7 +837,7 @@ func (b *SchemaBuilder) addRelationshipField(schema *spec.Schema, schemaKey, pro
 		Str("targetField", fieldName).
 		Str("targetKind", target.Kind).
 		Str("targetGroup", target.Group).
-		Str("refPath", refPath).
+		Str("refPath", ref.String()).
 		Str("sourceSchema", schemaKey).
 		Msg("Added relationship field")
 }
@@ -534,11 +852,36 @@ func isRefProperty(name string) bool {
 	return true
 }
 
+// GenerationErrors returns the group versions and definitions left out of the schema because their OpenAPI schema
+// could not be read, sorted by group version and definition
+func (b *SchemaBuilder) GenerationErrors() []common.GenerationError {
+	common.SortGenerationErrors(b.generationErrors)
+	return b.generationErrors
+}
+
 func (b *SchemaBuilder) Complete() ([]byte, error) {
+	// Definitions that can't be written are left out one by one instead of failing the whole schema
+	for key, schema := range b.schemas {
+		if _, err := json.Marshal(schema); err != nil {
+			groupVersion := ""
+			if gvk, ok := definitionGroupVersionKind(schema); ok {
+				groupVersion = gvk.groupVersion()
+			}
+			b.log.Warn().Err(err).Str("groupVersion", groupVersion).Str("definition", key).Msg("skipping invalid definition")
+			b.generationErrors = append(b.generationErrors, common.GenerationError{
+				GroupVersion: groupVersion,
+				Definition:   key,
+				Error:        errors.Join(ErrMarshalOpenAPISchema, err).Error(),
+			})
+			delete(b.schemas, key)
+		}
+	}
+
 	v3JSON, err := json.Marshal(&schemaResponse{
 		Components: schemasComponentsWrapper{
 			Schemas: b.schemas,
 		},
+		GenerationErrors: b.GenerationErrors(),
 	})
 	if err != nil {
 		return nil, errors.Join(ErrMarshalOpenAPISchema, err)
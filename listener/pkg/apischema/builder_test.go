@@ -1,6 +1,7 @@
 package apischema_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -150,6 +151,51 @@ func TestNewSchemaBuilder(t *testing.T) {
 	}
 }
 
+// TestSchemaBuilder_GenerationErrors tests that invalid group versions and definitions are left out of the schema and
+// reported in it, while the other definitions are still written.
+func TestSchemaBuilder_GenerationErrors(t *testing.T) {
+	mock := apischemaMocks.NewMockClient(t)
+	validGV := apischemaMocks.NewMockGroupVersion(t)
+	validGV.EXPECT().Schema("application/json").Return([]byte(`{"components":{"schemas":{
+		"io.example.v1.Widget": {"type": "object"}
+	}}}`), nil)
+	brokenGV := apischemaMocks.NewMockGroupVersion(t)
+	brokenGV.EXPECT().Schema("application/json").Return([]byte(`{"components":{"schemas":{
+		"io.broken.v1.Valid": {"type": "object"},
+		"io.broken.v1.Invalid": {"required": "spec"}
+	}}}`), nil)
+	unavailableGV := apischemaMocks.NewMockGroupVersion(t)
+	unavailableGV.EXPECT().Schema("application/json").Return(nil, errors.New("service unavailable"))
+	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{
+		"apis/example.io/v1":     validGV,
+		"apis/broken.io/v1":      brokenGV,
+		"apis/unavailable.io/v1": unavailableGV,
+	}, nil)
+
+	b := apischema.NewSchemaBuilder(mock, []string{"example.io/v1", "broken.io/v1", "unavailable.io/v1"}, testlogger.New().HideLogOutput().Logger)
+	assert.Len(t, b.GetSchemas(), 2)
+
+	generationErrors := b.GenerationErrors()
+	if assert.Len(t, generationErrors, 2) {
+		assert.Equal(t, "broken.io/v1", generationErrors[0].GroupVersion)
+		assert.Equal(t, "io.broken.v1.Invalid", generationErrors[0].Definition)
+		assert.Equal(t, "unavailable.io/v1", generationErrors[1].GroupVersion)
+		assert.Empty(t, generationErrors[1].Definition)
+		assert.Contains(t, generationErrors[1].Error, "service unavailable")
+	}
+
+	result, err := b.Complete()
+	assert.NoError(t, err)
+	var file struct {
+		Definitions      map[string]any           `json:"definitions"`
+		GenerationErrors []common.GenerationError `json:"x-generation-errors"`
+	}
+	assert.NoError(t, json.Unmarshal(result, &file))
+	assert.Contains(t, file.Definitions, "io.example.v1.Widget")
+	assert.Contains(t, file.Definitions, "io.broken.v1.Valid")
+	assert.Equal(t, generationErrors, file.GenerationErrors)
+}
+
 // TestWithCRDCategories tests the WithCRDCategories method
 // for the SchemaBuilder struct. It checks if the categories are correctly added
 // to the schema's extensions.
@@ -265,6 +311,117 @@ func TestWithApiResourceCategories(t *testing.T) {
 	}
 }
 
+func TestWithSubresources(t *testing.T) {
+	tests := []struct {
+		name string
+		list []*metav1.APIResourceList
+		want []string
+	}{
+		{
+			name: "status_and_scale",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "ps", Kind: "P"},
+					{Name: "ps/status", Kind: "P"},
+					{Name: "ps/scale", Kind: "Scale"},
+					{Name: "ps/log", Kind: "P"},
+				},
+			}},
+			want: []string{"scale", "status"},
+		},
+		{
+			name: "eviction_and_proxy",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "ps", Kind: "P"},
+					{Name: "ps/proxy", Kind: "PProxyOptions"},
+					{Name: "ps/eviction", Kind: "Eviction"},
+					{Name: "ps/exec", Kind: "PExecOptions"},
+				},
+			}},
+			want: []string{"eviction", "proxy"},
+		},
+		{
+			name: "no_subresources",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{{Name: "ps", Kind: "P"}},
+			}},
+		},
+		{
+			name: "subresource_of_other_resource",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "ps", Kind: "P"},
+					{Name: "qs", Kind: "Q"},
+					{Name: "qs/status", Kind: "Q"},
+				},
+			}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := apischemaMocks.NewMockClient(t)
+			mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+			b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().Logger)
+			b.SetSchemas(map[string]*spec.Schema{
+				"h.v1.P": {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+			})
+			b.WithSubresources(tc.list)
+			assert.Equal(t, tc.want, common.Subresources(b.GetSchemas()["h.v1.P"].Extensions))
+		})
+	}
+}
+
+func TestWithVerbs(t *testing.T) {
+	tests := []struct {
+		name string
+		list []*metav1.APIResourceList
+		want []string
+	}{
+		{
+			name: "verbs_sorted",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{
+					{Name: "ps", Kind: "P", Verbs: metav1.Verbs{"list", "get", "watch"}},
+					{Name: "ps/status", Kind: "P", Verbs: metav1.Verbs{"patch"}},
+				},
+			}},
+			want: []string{"get", "list", "watch"},
+		},
+		{
+			name: "no_verbs",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{{Name: "ps", Kind: "P"}},
+			}},
+		},
+		{
+			name: "other_kind",
+			list: []*metav1.APIResourceList{{
+				GroupVersion: "h/v1",
+				APIResources: []metav1.APIResource{{Name: "qs", Kind: "Q", Verbs: metav1.Verbs{"get"}}},
+			}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := apischemaMocks.NewMockClient(t)
+			mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+			b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().Logger)
+			b.SetSchemas(map[string]*spec.Schema{
+				"h.v1.P": {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+			})
+			b.WithVerbs(tc.list)
+			assert.Equal(t, tc.want, common.Verbs(b.GetSchemas()["h.v1.P"].Extensions))
+		})
+	}
+}
+
 // TestWithScope tests the WithScope method for the SchemaBuilder struct.
 func TestWithScope(t *testing.T) {
 	gvk := schema.GroupVersionKind{Group: "g", Version: "v1", Kind: "K"}
@@ -297,3 +454,178 @@ func TestWithScope(t *testing.T) {
 	scope := b.GetSchemas()["g.v1.K"].VendorExtensible.Extensions[common.ScopeExtensionKey]
 	assert.Equal(t, apiextensionsv1.NamespaceScoped, scope, "scope value mismatch")
 }
+
+// TestDefinitionGVKs tests the parsing of the GVK extension of definitions
+func TestDefinitionGVKs(t *testing.T) {
+	tests := []struct {
+		name       string
+		extensions map[string]interface{}
+		want       []metav1.GroupVersionKind
+		wantErr    error
+	}{
+		{
+			name:       "no_extension",
+			extensions: nil,
+			want:       nil,
+		},
+		{
+			name: "single_gvk",
+			extensions: map[string]interface{}{
+				common.GVKExtensionKey: []map[string]string{{"group": "g", "version": "v1", "kind": "K"}},
+			},
+			want: []metav1.GroupVersionKind{{Group: "g", Version: "v1", Kind: "K"}},
+		},
+		{
+			name:       "unmarshalable_extension_ERROR",
+			extensions: map[string]interface{}{common.GVKExtensionKey: make(chan int)},
+			wantErr:    common.ErrInvalidGVKExtension,
+		},
+		{
+			name:       "invalid_extension_ERROR",
+			extensions: map[string]interface{}{common.GVKExtensionKey: "g/v1, Kind=K"},
+			wantErr:    common.ErrInvalidGVKExtension,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gvks, err := apischema.DefinitionGVKs(spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: tc.extensions}})
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, gvks)
+		})
+	}
+}
+
+// TestWithCRDUIHints tests that the UI hints annotation of a CRD is added to the schemas of all its versions
+// and that invalid hints are skipped.
+func TestWithCRDUIHints(t *testing.T) {
+	newCRD := func(annotations map[string]string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "ks.g", Annotations: annotations},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "g",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "K"},
+			},
+		}
+	}
+
+	order := 1
+	tests := []struct {
+		name      string
+		crd       *apiextensionsv1.CustomResourceDefinition
+		wantHints *common.UIHints
+	}{
+		{
+			name: "adds_hints",
+			crd: newCRD(map[string]string{
+				common.UIHintsAnnotation: `{"displayName": "Kay", "fields": {"spec.size": {"widget": "slider", "order": 1}}}`,
+			}),
+			wantHints: &common.UIHints{
+				UIHint: common.UIHint{DisplayName: "Kay"},
+				Fields: map[string]common.UIHint{"spec.size": {Widget: "slider", Order: &order}},
+			},
+		},
+		{
+			name: "invalid_hints",
+			crd:  newCRD(map[string]string{common.UIHintsAnnotation: `{"widgt": "slider"}`}),
+		},
+		{
+			name: "no_annotation",
+			crd:  newCRD(nil),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := apischemaMocks.NewMockClient(t)
+			mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+			b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+			b.SetSchemas(map[string]*spec.Schema{
+				"g.v1.K": {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+				"g.v2.K": {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+			})
+			b.WithCRDUIHints(tc.crd)
+
+			for _, key := range []string{"g.v1.K", "g.v2.K"} {
+				ext, found := b.GetSchemas()[key].VendorExtensible.Extensions[common.UIHintsExtensionKey]
+				if tc.wantHints == nil {
+					assert.False(t, found, "expected no UI hints")
+					continue
+				}
+				assert.Equal(t, tc.wantHints, ext)
+			}
+		})
+	}
+}
+
+// TestWithInputOnlyFields tests that the input-only fields of the CRD annotation and of the configured rules
+// are merged into the schemas of all versions of the kind.
+func TestWithInputOnlyFields(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "ks.g",
+			Annotations: map[string]string{common.InputOnlyFieldsAnnotation: "spec.token, spec.password"},
+		},
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group:    "g",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+			Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "K"},
+		},
+	}
+	newSchema := func(version string) *spec.Schema {
+		return &spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{
+			common.GVKExtensionKey: []map[string]string{{"group": "g", "version": version, "kind": "K"}},
+		}}}
+	}
+
+	mock := apischemaMocks.NewMockClient(t)
+	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+	b.SetSchemas(map[string]*spec.Schema{
+		"g.v1.K":     newSchema("v1"),
+		"g.v2.K":     newSchema("v2"),
+		"other.v1.K": {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+	})
+	b.WithCRDInputOnlyFields(crd).
+		WithInputOnlyFieldRules([]common.InputOnlyFieldRule{
+			{Group: "g", Kind: "K", Fields: []string{"spec.token", "status.secret"}},
+			{Group: "", Kind: "K", Fields: []string{"spec.other"}},
+		})
+	assert.NoError(t, b.GetError())
+
+	for _, key := range []string{"g.v1.K", "g.v2.K"} {
+		assert.Equal(t, []string{"spec.password", "spec.token", "status.secret"},
+			b.GetSchemas()[key].VendorExtensible.Extensions[common.InputOnlyFieldsExtensionKey])
+	}
+	assert.NotContains(t, b.GetSchemas()["other.v1.K"].VendorExtensible.Extensions, common.InputOnlyFieldsExtensionKey)
+}
+
+func TestWithVersionPreferences(t *testing.T) {
+	newSchema := func(version string) *spec.Schema {
+		return &spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{
+			common.GVKExtensionKey: []map[string]string{{"group": "g", "version": version, "kind": "K"}},
+		}}}
+	}
+
+	mock := apischemaMocks.NewMockClient(t)
+	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+	b.SetSchemas(map[string]*spec.Schema{
+		"g.v1beta1.K": newSchema("v1beta1"),
+		"g.v1.K":      newSchema("v1"),
+		"other.v1.L":  {VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{}}},
+	})
+	b.WithPreferredVersions([]*metav1.APIResourceList{{
+		GroupVersion: "g/v1",
+		APIResources: []metav1.APIResource{{Name: "ks", Kind: "K"}},
+	}}).WithVersionPreferences()
+	assert.NoError(t, b.GetError())
+
+	assert.Equal(t, "v1", b.GetSchemas()["g.v1beta1.K"].VendorExtensible.Extensions[common.PreferredVersionKey])
+	assert.NotContains(t, b.GetSchemas()["g.v1.K"].VendorExtensible.Extensions, common.PreferredVersionKey)
+	assert.NotContains(t, b.GetSchemas()["other.v1.L"].VendorExtensible.Extensions, common.PreferredVersionKey)
+}
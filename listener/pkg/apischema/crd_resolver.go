@@ -1,12 +1,16 @@
 package apischema
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
 	"slices"
 	"strings"
 
 	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -25,6 +29,7 @@ var (
 	ErrFilterPreferredResources = errors.New("failed to filter server preferred resources")
 	ErrGetSchemaForPath         = errors.New("failed to get schema for path")
 	ErrUnmarshalSchemaForPath   = errors.New("failed to unmarshal schema for path")
+	ErrListCRDs                 = errors.New("failed to list CRDs")
 )
 
 type GroupKindVersions struct {
@@ -36,6 +41,12 @@ type CRDResolver struct {
 	discovery.DiscoveryInterface
 	meta.RESTMapper
 	log *logger.Logger
+	// deprecationWarnings probes the resources for deprecation warnings, see WithDeprecationWarnings
+	deprecationWarnings bool
+	// inputOnlyFieldsPath points to the input-only field rules, see WithInputOnlyFieldRulesPath
+	inputOnlyFieldsPath string
+	// relationDepth limits the chains of relationship fields, see WithRelationDepth
+	relationDepth int
 }
 
 // NewCRDResolver creates a new CRDResolver with proper logger setup
@@ -44,6 +55,7 @@ func NewCRDResolver(discovery discovery.DiscoveryInterface, restMapper meta.REST
 		DiscoveryInterface: discovery,
 		RESTMapper:         restMapper,
 		log:                log,
+		relationDepth:      DefaultRelationDepth,
 	}
 }
 
@@ -77,7 +89,13 @@ func (cr *CRDResolver) ResolveApiSchema(crd *apiextensionsv1.CustomResourceDefin
 	result, err := NewSchemaBuilder(cr.OpenAPIV3(), preferredApiGroups, cr.log).
 		WithScope(cr.RESTMapper).
 		WithPreferredVersions(apiResLists).
+		WithSubresources(apiResLists).
+		WithVerbs(apiResLists).
 		WithCRDCategories(crd).
+		WithCRDUIHints(crd).
+		WithCRDRelations(crd).
+		WithCRDInputOnlyFields(crd).
+		WithMaxRelationDepth(cr.relationDepth).
 		WithRelationships().
 		Complete()
 
@@ -171,34 +189,110 @@ func getCRDGroupKindVersions(spec apiextensionsv1.CustomResourceDefinitionSpec)
 	}
 }
 
-func getSchemaForPath(preferredApiGroups []string, path string, gv openapi.GroupVersion) (map[string]*spec.Schema, error) {
+// getSchemaForPath returns the schemas of the group version of the path. Definitions that can't be read are left out
+// and returned as generation errors, so that a single invalid CRD doesn't drop the whole group version.
+func getSchemaForPath(preferredApiGroups []string, path string, gv openapi.GroupVersion) (map[string]*spec.Schema, []common.GenerationError, error) {
 	if !strings.Contains(path, separator) {
-		return nil, ErrInvalidPath
+		return nil, nil, ErrInvalidPath
 	}
 	pathApiGroupArray := strings.Split(path, separator)
 	pathApiGroup := strings.Join(pathApiGroupArray[1:], separator)
 	// filer out apiGroups that aren't in the preferred list
 	if !slices.Contains(preferredApiGroups, pathApiGroup) {
-		return nil, ErrNotPreferred
+		return nil, nil, ErrNotPreferred
 	}
 
 	b, err := gv.Schema(discovery.AcceptV1)
 	if err != nil {
-		return nil, errors.Join(ErrGetSchemaForPath, err)
+		return nil, nil, errors.Join(ErrGetSchemaForPath, err)
 	}
 
-	resp := &schemaResponse{}
-	if err := json.Unmarshal(b, resp); err != nil {
-		return nil, errors.Join(ErrUnmarshalSchemaForPath, err)
+	var resp struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
 	}
-	return resp.Components.Schemas, nil
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return nil, nil, errors.Join(ErrUnmarshalSchemaForPath, err)
+	}
+
+	schemas := make(map[string]*spec.Schema, len(resp.Components.Schemas))
+	var skipped []common.GenerationError
+	for key, raw := range resp.Components.Schemas {
+		schema := &spec.Schema{}
+		if err := json.Unmarshal(raw, schema); err != nil {
+			skipped = append(skipped, common.GenerationError{
+				GroupVersion: pathApiGroup,
+				Definition:   key,
+				Error:        errors.Join(ErrUnmarshalSchemaForPath, err).Error(),
+			})
+			continue
+		}
+		schemas[key] = schema
+	}
+	return schemas, skipped, nil
+}
+
+// listCRDs lists the CRDs via the REST client of the discovery client, which is not set up to decode them
+func listCRDs(dc discovery.DiscoveryInterface) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	restClient := dc.RESTClient()
+	if restClient == nil {
+		return nil, ErrListCRDs
+	}
+
+	raw, err := restClient.Get().AbsPath("/apis/apiextensions.k8s.io/v1/customresourcedefinitions").DoRaw(context.Background())
+	if err != nil {
+		return nil, errors.Join(ErrListCRDs, err)
+	}
+
+	var list apiextensionsv1.CustomResourceDefinitionList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, errors.Join(ErrListCRDs, err)
+	}
+
+	crds := make([]*apiextensionsv1.CustomResourceDefinition, 0, len(list.Items))
+	for i := range list.Items {
+		crds = append(crds, &list.Items[i])
+	}
+
+	return crds, nil
+}
+
+// servedVersionResources returns the discovery of the versions the CRDs are served in besides the preferred ones,
+// versions whose discovery fails are skipped
+func servedVersionResources(dc discovery.DiscoveryInterface, crds []*apiextensionsv1.CustomResourceDefinition, preferredApiGroups []string, log *logger.Logger) []*metav1.APIResourceList {
+	var groupVersions []string
+	for _, crd := range crds {
+		for _, version := range crd.Spec.Versions {
+			groupVersion := schema.GroupVersion{Group: crd.Spec.Group, Version: version.Name}.String()
+			if version.Served && !slices.Contains(preferredApiGroups, groupVersion) {
+				groupVersions = append(groupVersions, groupVersion)
+			}
+		}
+	}
+	slices.Sort(groupVersions)
+
+	resLists := make([]*metav1.APIResourceList, 0, len(groupVersions))
+	for _, groupVersion := range slices.Compact(groupVersions) {
+		resList, err := dc.ServerResourcesForGroupVersion(groupVersion)
+		if err != nil {
+			log.Debug().Err(err).Str("groupVersion", groupVersion).Msg("failed to discover served version, skipping it")
+			continue
+		}
+		resLists = append(resLists, resList)
+	}
+	return resLists
 }
 
 func (cr *CRDResolver) resolveSchema(dc discovery.DiscoveryInterface, rm meta.RESTMapper) ([]byte, error) {
 	apiResList, err := dc.ServerPreferredResources()
 	if err != nil {
-		cr.log.Error().Err(err).Msg("failed to get server preferred resources")
-		return nil, errors.Join(ErrGetServerPreferred, err)
+		// The discovery of aggregated APIs fails while their API server is unavailable, the other APIs are still served
+		if !discovery.IsGroupDiscoveryFailedError(err) || len(apiResList) == 0 {
+			cr.log.Error().Err(err).Msg("failed to get server preferred resources")
+			return nil, errors.Join(ErrGetServerPreferred, err)
+		}
+		cr.log.Warn().Err(err).Msg("skipping API groups whose discovery failed")
 	}
 
 	var preferredApiGroups []string
@@ -206,10 +300,56 @@ func (cr *CRDResolver) resolveSchema(dc discovery.DiscoveryInterface, rm meta.RE
 		preferredApiGroups = append(preferredApiGroups, apiRes.GroupVersion)
 	}
 
-	result, err := NewSchemaBuilder(dc.OpenAPIV3(), preferredApiGroups, cr.log).
+	aggregatedApiGroups, unavailableApiGroups, err := listAggregatedAPIs(dc)
+	if err != nil {
+		cr.log.Debug().Err(err).Msg("failed to list APIServices, skipping the OpenAPI v2 fallback of aggregated APIs")
+	}
+	if len(unavailableApiGroups) > 0 {
+		cr.log.Warn().Strs("groupVersions", unavailableApiGroups).Msg("aggregated APIs are unavailable, they are added once the schema is generated again")
+	}
+	aggregatedApiGroups = slices.DeleteFunc(aggregatedApiGroups, func(groupVersion string) bool {
+		return !slices.Contains(preferredApiGroups, groupVersion)
+	})
+
+	// UI hints are annotations of the CRDs and not part of the OpenAPI schema
+	crds, err := listCRDs(dc)
+	if err != nil {
+		cr.log.Debug().Err(err).Msg("failed to list CRDs, skipping UI hints")
+	}
+
+	inputOnlyFieldRules, err := loadInputOnlyFieldRules(cr.inputOnlyFieldsPath)
+	if err != nil {
+		cr.log.Error().Err(err).Str("path", cr.inputOnlyFieldsPath).Msg("failed to load input-only field rules")
+		return nil, err
+	}
+
+	var deprecations map[metav1.GroupVersionKind]common.Deprecation
+	if cr.deprecationWarnings {
+		deprecations = probeDeprecations(dc, apiResList, cr.log)
+	}
+
+	// The other served versions of the CRDs are added next to the preferred ones, the gateway prefixes their names
+	servedResList := servedVersionResources(dc, crds, preferredApiGroups, cr.log)
+	apiGroups := slices.Clone(preferredApiGroups)
+	for _, apiRes := range servedResList {
+		apiGroups = append(apiGroups, apiRes.GroupVersion)
+	}
+	allResList := slices.Concat(apiResList, servedResList)
+
+	result, err := NewSchemaBuilder(dc.OpenAPIV3(), apiGroups, cr.log).
+		WithOpenAPIV2Fallback(dc, aggregatedApiGroups).
 		WithScope(rm).
 		WithPreferredVersions(apiResList).
-		WithApiResourceCategories(apiResList).
+		WithVersionPreferences().
+		WithApiResourceCategories(allResList).
+		WithSubresources(allResList).
+		WithVerbs(allResList).
+		WithCRDUIHints(crds...).
+		WithCRDRelations(crds...).
+		WithCRDInputOnlyFields(crds...).
+		WithInputOnlyFieldRules(inputOnlyFieldRules).
+		WithDeprecations(deprecations).
+		WithMaxRelationDepth(cr.relationDepth).
 		WithRelationships().
 		Complete()
 
@@ -228,3 +368,17 @@ func (cr *CRDResolver) resolveSchema(dc discovery.DiscoveryInterface, rm meta.RE
 
 	return result, nil
 }
+
+// loadInputOnlyFieldRules reads the input-only field rules from the file at path, an empty path has no rules
+func loadInputOnlyFieldRules(path string) ([]common.InputOnlyFieldRule, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read input-only field rules: %w", err)
+	}
+
+	return common.ParseInputOnlyFieldRules(data)
+}
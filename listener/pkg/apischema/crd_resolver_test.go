@@ -1,12 +1,18 @@
 package apischema_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
 	"testing"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/openapi"
+	restfake "k8s.io/client-go/rest/fake"
 	"k8s.io/kube-openapi/pkg/validation/spec"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
@@ -187,12 +193,13 @@ func TestGetSchemaForPath(t *testing.T) {
 	assert.NoError(t, err, "failed to marshal valid response")
 
 	tests := []struct {
-		name      string
-		preferred []string
-		path      string
-		gv        openapi.GroupVersion
-		wantErr   error
-		wantCount int
+		name        string
+		preferred   []string
+		path        string
+		gv          openapi.GroupVersion
+		wantErr     error
+		wantCount   int
+		wantSkipped []string
 	}{
 		{
 			name:      "invalid_path",
@@ -230,17 +237,38 @@ func TestGetSchemaForPath(t *testing.T) {
 			}(),
 			wantCount: 1,
 		},
+		{
+			name:      "invalid_definition",
+			preferred: []string{"g/v1"},
+			path:      "apis/g/v1",
+			gv: func() openapi.GroupVersion {
+				mock := apischemaMocks.NewMockGroupVersion(t)
+				mock.EXPECT().Schema("application/json").Return([]byte(`{"components":{"schemas":{
+					"g.v1.Valid": {"type": "object"},
+					"g.v1.Broken": {"properties": "not an object"}
+				}}}`), nil)
+				return mock
+			}(),
+			wantCount:   1,
+			wantSkipped: []string{"g.v1.Broken"},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got, err := apischema.GetSchemaForPath(tc.preferred, tc.path, tc.gv)
+			got, skipped, err := apischema.GetSchemaForPath(tc.preferred, tc.path, tc.gv)
 			if tc.wantErr != nil {
 				assert.ErrorIs(t, err, tc.wantErr)
 				return
 			}
 			assert.NoError(t, err)
 			assert.Equal(t, tc.wantCount, len(got), "schema count mismatch")
+			var skippedDefinitions []string
+			for _, generationError := range skipped {
+				assert.Equal(t, "g/v1", generationError.GroupVersion)
+				skippedDefinitions = append(skippedDefinitions, generationError.Definition)
+			}
+			assert.Equal(t, tc.wantSkipped, skippedDefinitions)
 		})
 	}
 }
@@ -316,6 +344,7 @@ func TestResolveSchema(t *testing.T) {
 				openAPIClient := apischemaMocks.NewMockClient(t)
 				openAPIClient.EXPECT().Paths().Return(openAPIPaths, tc.openAPIErr)
 				dc.EXPECT().OpenAPIV3().Return(openAPIClient)
+				dc.EXPECT().RESTClient().Return(nil)
 			}
 
 			got, err := apischema.ResolveSchema(dc, rm, testlogger.New().Logger)
@@ -328,3 +357,66 @@ func TestResolveSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestListCRDs(t *testing.T) {
+	body, err := json.Marshal(apiextensionsv1.CustomResourceDefinitionList{
+		Items: []apiextensionsv1.CustomResourceDefinition{
+			{ObjectMeta: metav1.ObjectMeta{Name: "accounts.core.openmfp.org"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	restClient := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			assert.Equal(t, "/apis/apiextensions.k8s.io/v1/customresourcedefinitions", req.URL.Path)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"application/json"}},
+				Body:       io.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(restClient)
+
+	crds, err := apischema.ListCRDs(dc)
+	assert.NoError(t, err)
+	assert.Len(t, crds, 1)
+	assert.Equal(t, "accounts.core.openmfp.org", crds[0].Name)
+}
+
+func TestListCRDs_NoRESTClient(t *testing.T) {
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(nil)
+
+	_, err := apischema.ListCRDs(dc)
+	assert.ErrorIs(t, err, apischema.ErrListCRDs)
+}
+
+func TestServedVersionResources(t *testing.T) {
+	crds := []*apiextensionsv1.CustomResourceDefinition{{
+		Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+			Group: "example.com",
+			Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+				{Name: "v1", Served: true},
+				{Name: "v1beta1", Served: true},
+				{Name: "v1alpha1", Served: false},
+				{Name: "v2alpha1", Served: true},
+			},
+			Names: apiextensionsv1.CustomResourceDefinitionNames{Kind: "Widget"},
+		},
+	}}
+	v1beta1 := &metav1.APIResourceList{
+		GroupVersion: "example.com/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "widgets", Kind: "Widget"}},
+	}
+
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().ServerResourcesForGroupVersion("example.com/v1beta1").Return(v1beta1, nil)
+	dc.EXPECT().ServerResourcesForGroupVersion("example.com/v2alpha1").Return(nil, errors.New("not found"))
+
+	resLists := apischema.ServedVersionResources(dc, crds, []string{"example.com/v1"})
+	assert.Equal(t, []*metav1.APIResourceList{v1beta1}, resLists)
+}
@@ -0,0 +1,72 @@
+package apischema
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtimeSchema "k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+
+	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// probeDeprecations lists a single object of every listable resource and collects the warnings the API server
+// returns for deprecated APIs. The warnings are only sent for requests to the API, not in the discovery documents.
+func probeDeprecations(dc discovery.DiscoveryInterface, apiResLists []*metav1.APIResourceList, log *logger.Logger) map[metav1.GroupVersionKind]common.Deprecation {
+	deprecations := make(map[metav1.GroupVersionKind]common.Deprecation)
+
+	restClient := dc.RESTClient()
+	if restClient == nil {
+		log.Debug().Msg("no REST client, skipping deprecation probes")
+		return deprecations
+	}
+
+	for _, apiResList := range apiResLists {
+		gv, err := runtimeSchema.ParseGroupVersion(apiResList.GroupVersion)
+		if err != nil {
+			log.Debug().Err(err).Str("groupVersion", apiResList.GroupVersion).Msg("failed to parse group version")
+			continue
+		}
+
+		for _, resource := range apiResList.APIResources {
+			// subresources can't be listed
+			if strings.Contains(resource.Name, separator) || !slices.Contains(resource.Verbs, "list") {
+				continue
+			}
+
+			result := restClient.Get().AbsPath(resourcePath(gv, resource.Name)).Param("limit", "1").Do(context.Background())
+			if err := result.Error(); err != nil {
+				log.Debug().Err(err).Str("groupVersion", apiResList.GroupVersion).Str("resource", resource.Name).Msg("deprecation probe failed")
+				continue
+			}
+
+			warnings := result.Warnings()
+			if len(warnings) == 0 {
+				continue
+			}
+
+			gvk := metav1.GroupVersionKind{Group: gv.Group, Version: gv.Version, Kind: resource.Kind}
+			deprecations[gvk] = common.ParseDeprecationWarning(warnings[0].Text)
+
+			log.Info().
+				Str("groupVersion", apiResList.GroupVersion).
+				Str("kind", resource.Kind).
+				Str("warning", warnings[0].Text).
+				Msg("API is deprecated")
+		}
+	}
+
+	return deprecations
+}
+
+// resourcePath returns the path of a resource, the core group is served under /api instead of /apis
+func resourcePath(gv runtimeSchema.GroupVersion, resource string) string {
+	if gv.Group == "" {
+		return "/api/" + gv.Version + separator + resource
+	}
+
+	return "/apis/" + gv.Group + separator + gv.Version + separator + resource
+}
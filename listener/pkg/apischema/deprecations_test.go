@@ -0,0 +1,118 @@
+package apischema_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/openapi"
+	restfake "k8s.io/client-go/rest/fake"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	apischema "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	apischemaMocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
+)
+
+// TestProbeDeprecations tests that the warnings returned for list requests are collected per kind
+// and that subresources and resources that can't be listed are not probed.
+func TestProbeDeprecations(t *testing.T) {
+	const warning = `299 - "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget"`
+
+	var probedPaths []string
+	restClient := &restfake.RESTClient{
+		NegotiatedSerializer: scheme.Codecs.WithoutConversion(),
+		Client: restfake.CreateHTTPClient(func(req *http.Request) (*http.Response, error) {
+			probedPaths = append(probedPaths, req.URL.Path)
+			assert.Equal(t, "1", req.URL.Query().Get("limit"))
+
+			header := http.Header{"Content-Type": []string{"application/json"}}
+			if req.URL.Path == "/apis/policy/v1beta1/poddisruptionbudgets" {
+				header.Set("Warning", warning)
+			}
+			if req.URL.Path == "/apis/example.com/v1/widgets" {
+				return &http.Response{StatusCode: http.StatusForbidden, Header: header, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Header: header, Body: io.NopCloser(bytes.NewReader([]byte(`{}`)))}, nil
+		}),
+	}
+
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(restClient)
+
+	deprecations := apischema.ProbeDeprecations(dc, []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Verbs: metav1.Verbs{"get", "list"}},
+				{Name: "pods/log", Kind: "Pod", Verbs: metav1.Verbs{"get"}},
+				{Name: "bindings", Kind: "Binding", Verbs: metav1.Verbs{"create"}},
+			},
+		},
+		{
+			GroupVersion: "policy/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "poddisruptionbudgets", Kind: "PodDisruptionBudget", Verbs: metav1.Verbs{"list"}},
+			},
+		},
+		{
+			GroupVersion: "example.com/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "widgets", Kind: "Widget", Verbs: metav1.Verbs{"list"}},
+			},
+		},
+	})
+
+	assert.ElementsMatch(t, []string{"/api/v1/pods", "/apis/policy/v1beta1/poddisruptionbudgets", "/apis/example.com/v1/widgets"}, probedPaths)
+	assert.Equal(t, map[metav1.GroupVersionKind]common.Deprecation{
+		{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}: {
+			Warning:      "policy/v1beta1 PodDisruptionBudget is deprecated in v1.21+, unavailable in v1.25+; use policy/v1 PodDisruptionBudget",
+			DeprecatedIn: "v1.21",
+			RemovedIn:    "v1.25",
+		},
+	}, deprecations)
+}
+
+func TestProbeDeprecations_NoRESTClient(t *testing.T) {
+	dc := apischemaMocks.NewMockDiscoveryInterface(t)
+	dc.EXPECT().RESTClient().Return(nil)
+
+	assert.Empty(t, apischema.ProbeDeprecations(dc, []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Verbs: metav1.Verbs{"list"}}}},
+	}))
+}
+
+// TestWithDeprecations tests that the deprecation is added to the schema with the matching GVK extension only
+func TestWithDeprecations(t *testing.T) {
+	newSchema := func(group, version, kind string) *spec.Schema {
+		return &spec.Schema{VendorExtensible: spec.VendorExtensible{Extensions: map[string]interface{}{
+			common.GVKExtensionKey: []interface{}{
+				map[string]interface{}{"group": group, "version": version, "kind": kind},
+			},
+		}}}
+	}
+
+	mock := apischemaMocks.NewMockClient(t)
+	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+	b.SetSchemas(map[string]*spec.Schema{
+		"io.k8s.api.policy.v1beta1.PodDisruptionBudget": newSchema("policy", "v1beta1", "PodDisruptionBudget"),
+		"io.k8s.api.apps.v1.Deployment":                 newSchema("apps", "v1", "Deployment"),
+		"io.k8s.api.core.v1.PodSpec":                    {},
+	})
+
+	deprecation := common.Deprecation{Warning: "policy/v1beta1 PodDisruptionBudget is deprecated", DeprecatedIn: "v1.21"}
+	b.WithDeprecations(map[metav1.GroupVersionKind]common.Deprecation{
+		{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}: deprecation,
+	})
+
+	schemas := b.GetSchemas()
+	assert.Equal(t, deprecation, schemas["io.k8s.api.policy.v1beta1.PodDisruptionBudget"].Extensions[common.DeprecationExtensionKey])
+	assert.NotContains(t, schemas["io.k8s.api.apps.v1.Deployment"].Extensions, common.DeprecationExtensionKey)
+	assert.NotContains(t, schemas["io.k8s.api.core.v1.PodSpec"].Extensions, common.DeprecationExtensionKey)
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/openmfp/golang-commons/logger"
 	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 )
 
 func GetCRDGroupKindVersions(spec apiextensionsv1.CustomResourceDefinitionSpec) *GroupKindVersions {
@@ -25,7 +26,7 @@ func ErrorIfCRDNotInPreferredApiGroups(gkv *GroupKindVersions, lists []*metav1.A
 	return crdResolver.errorIfCRDNotInPreferredApiGroups(gkv, lists)
 }
 
-func GetSchemaForPath(preferred []string, path string, gv openapi.GroupVersion) (map[string]*spec.Schema, error) {
+func GetSchemaForPath(preferred []string, path string, gv openapi.GroupVersion) (map[string]*spec.Schema, []common.GenerationError, error) {
 	return getSchemaForPath(preferred, path, gv)
 }
 
@@ -34,10 +35,18 @@ func ResolveSchema(dc discovery.DiscoveryInterface, rm meta.RESTMapper, log *log
 	return crdResolver.resolveSchema(dc, rm)
 }
 
+func ListCRDs(dc discovery.DiscoveryInterface) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	return listCRDs(dc)
+}
+
 func GetOpenAPISchemaKey(gvk metav1.GroupVersionKind) string {
 	return getOpenAPISchemaKey(gvk)
 }
 
+func DefinitionGVKs(schema spec.Schema) ([]metav1.GroupVersionKind, error) {
+	return definitionGVKs(schema)
+}
+
 func GetCRDGroupVersionKind(spec apiextensionsv1.CustomResourceDefinitionSpec) (*metav1.GroupVersionKind, error) {
 	return getCRDGroupVersionKind(spec)
 }
@@ -52,9 +61,21 @@ func (b *SchemaBuilder) GetSchemas() map[string]*spec.Schema {
 }
 
 func (b *SchemaBuilder) GetError() error {
-	return b.err
+	return b.err.ErrorOrNil()
 }
 
 func (b *SchemaBuilder) SetSchemas(schemas map[string]*spec.Schema) {
 	b.schemas = schemas
 }
+
+func ProbeDeprecations(dc discovery.DiscoveryInterface, lists []*metav1.APIResourceList) map[metav1.GroupVersionKind]common.Deprecation {
+	return probeDeprecations(dc, lists, testlogger.New().HideLogOutput().Logger)
+}
+
+func ServedVersionResources(dc discovery.DiscoveryInterface, crds []*apiextensionsv1.CustomResourceDefinition, preferred []string) []*metav1.APIResourceList {
+	return servedVersionResources(dc, crds, preferred, testlogger.New().HideLogOutput().Logger)
+}
+
+func ListAggregatedAPIs(dc discovery.DiscoveryInterface) ([]string, []string, error) {
+	return listAggregatedAPIs(dc)
+}
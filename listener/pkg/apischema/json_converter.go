@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 )
 
 var (
@@ -18,11 +20,13 @@ type v3Wrapper struct {
 }
 
 type v3RootWrapper struct {
-	Components v3Wrapper `json:"components"`
+	Components       v3Wrapper                `json:"components"`
+	GenerationErrors []common.GenerationError `json:"x-generation-errors,omitempty"`
 }
 
 type v2RootWrapper struct {
-	Definitions map[string]any `json:"definitions"`
+	Definitions      map[string]any           `json:"definitions"`
+	GenerationErrors []common.GenerationError `json:"x-generation-errors,omitempty"`
 }
 
 func ConvertJSON(v3JSON []byte) ([]byte, error) {
@@ -40,7 +44,8 @@ func ConvertJSON(v3JSON []byte) ([]byte, error) {
 	e := json.NewEncoder(buf)
 	e.SetEscapeHTML(false)
 	encErr := e.Encode(&v2RootWrapper{
-		Definitions: v2,
+		Definitions:      v2,
+		GenerationErrors: data.GenerationErrors,
 	})
 	if encErr != nil {
 		return nil, errors.Join(ErrEncodeJSON, encErr)
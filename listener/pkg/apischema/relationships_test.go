@@ -1,12 +1,16 @@
 package apischema_test
 
 import (
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
 	apischema "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
 	apimocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
 	"github.com/stretchr/testify/assert"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/openapi"
 	"k8s.io/kube-openapi/pkg/validation/spec"
@@ -54,6 +58,31 @@ func Test_with_relationships_adds_single_target_field(t *testing.T) {
 	assert.Contains(t, added.Ref.String(), "#/definitions/g.v1.Role")
 }
 
+func Test_with_relationships_references_definition_key(t *testing.T) {
+	mock := apimocks.NewMockClient(t)
+	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+	b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().Logger)
+
+	// Definition keys of built-in kinds don't follow group.version.kind
+	roleKey := "io.k8s.api.rbac.v1.Role"
+	sourceKey := "io.k8s.api.rbac.v1.RoleBinding"
+	sourceSchema := schemaWithGVK("rbac.authorization.k8s.io", "v1", "RoleBinding")
+	sourceSchema.Properties = map[string]spec.Schema{
+		"roleRef": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}},
+	}
+
+	b.SetSchemas(map[string]*spec.Schema{
+		roleKey:   schemaWithGVK("rbac.authorization.k8s.io", "v1", "Role"),
+		sourceKey: sourceSchema,
+	})
+
+	b.WithRelationships()
+
+	added, ok := b.GetSchemas()[sourceKey].Properties["role"]
+	assert.True(t, ok, "expected relationship field 'role' to be added")
+	assert.Equal(t, "#/definitions/"+roleKey, added.Ref.String())
+}
+
 func Test_kubectl_style_priority_resolution_for_conflicts(t *testing.T) {
 	mock := apimocks.NewMockClient(t)
 	mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
@@ -368,3 +397,197 @@ func Test_same_kind_different_groups_with_preferred_version_still_conflicts(t *t
 	storageRefField := schemas["apps.example.com.v1.BackupApp"].Properties["storageRef"]
 	assert.NotContains(t, storageRefField.Required, "apiGroup", "apiGroup should NOT be required - backward compatible")
 }
+
+// kindWithRefs constructs a schema of the kind in the example.com group with a *Ref field for every referenced kind
+func kindWithRefs(kind string, refs ...string) *spec.Schema {
+	schema := schemaWithGVK("example.com", "v1", kind)
+	schema.Properties = map[string]spec.Schema{}
+	for _, ref := range refs {
+		schema.Properties[ref+"Ref"] = spec.Schema{SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}}
+	}
+	return schema
+}
+
+// relationFields returns the relationship fields added to the schema of the kind
+func relationFields(b *apischema.SchemaBuilder, kind string) []string {
+	var fields []string
+	for name, property := range b.GetSchemas()["example.com.v1."+kind].Properties {
+		if property.Ref.GetURL() != nil {
+			fields = append(fields, name)
+		}
+	}
+	slices.Sort(fields)
+	return fields
+}
+
+func Test_relation_depth(t *testing.T) {
+	tests := []struct {
+		name     string
+		depth    int
+		schemas  map[string][]string
+		expected map[string][]string
+	}{
+		{
+			name:     "depth_zero_adds_no_fields",
+			depth:    0,
+			schemas:  map[string][]string{"Root": {"pod"}, "Pod": nil},
+			expected: map[string][]string{"Root": nil, "Pod": nil},
+		},
+		{
+			name:  "chain_is_cut_at_the_depth",
+			depth: 2,
+			schemas: map[string][]string{
+				"Root":    {"pod"},
+				"Pod":     {"service"},
+				"Service": {"secret"},
+				"Secret":  nil,
+			},
+			expected: map[string][]string{"Root": {"pod"}, "Pod": {"service"}, "Service": nil, "Secret": nil},
+		},
+		{
+			name:  "diamond",
+			depth: 3,
+			schemas: map[string][]string{
+				"Root":  {"left", "right"},
+				"Left":  {"leaf"},
+				"Right": {"leaf"},
+				"Leaf":  nil,
+			},
+			expected: map[string][]string{"Root": {"left", "right"}, "Left": {"leaf"}, "Right": {"leaf"}, "Leaf": nil},
+		},
+		{
+			// Leaf is two relations away from Root via Middle, so it is not expanded with a depth of 2
+			name:  "longest_chain_decides",
+			depth: 2,
+			schemas: map[string][]string{
+				"Root":   {"middle", "leaf"},
+				"Middle": {"leaf"},
+				"Leaf":   {"end"},
+				"End":    nil,
+			},
+			expected: map[string][]string{"Root": {"leaf", "middle"}, "Middle": {"leaf"}, "Leaf": nil, "End": nil},
+		},
+		{
+			name:  "circular",
+			depth: 5,
+			schemas: map[string][]string{
+				"Root": {"a"},
+				"A":    {"b"},
+				"B":    {"a", "b"},
+			},
+			expected: map[string][]string{"Root": {"a"}, "A": {"b"}, "B": nil},
+		},
+		{
+			// Without a kind that isn't referenced itself, no chain has a start
+			name:     "cycle_without_root",
+			depth:    5,
+			schemas:  map[string][]string{"A": {"b"}, "B": {"a"}},
+			expected: map[string][]string{"A": nil, "B": nil},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := apimocks.NewMockClient(t)
+			mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+			b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+
+			schemas := make(map[string]*spec.Schema, len(tc.schemas))
+			for kind, refs := range tc.schemas {
+				schemas["example.com.v1."+kind] = kindWithRefs(kind, refs...)
+			}
+			b.SetSchemas(schemas)
+
+			b.WithMaxRelationDepth(tc.depth).WithRelationships()
+
+			for kind, expected := range tc.expected {
+				assert.Equal(t, expected, relationFields(b, kind), "relationship fields of %s", kind)
+			}
+		})
+	}
+}
+
+func Test_declared_relations(t *testing.T) {
+	newCRD := func(relations string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "workloads.w.io", Annotations: map[string]string{common.RelationsAnnotation: relations}},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group:    "w.io",
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1"}},
+				Names:    apiextensionsv1.CustomResourceDefinitionNames{Kind: "Workload"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		relations string
+		// expected maps the relationship fields to the definitions they refer to
+		expected map[string]string
+	}{
+		{
+			name:      "nested_field",
+			relations: `[{"field": "spec.clusterRef", "kind": "Cluster", "group": "x.io"}]`,
+			expected:  map[string]string{"cluster": "io.x.v1.Cluster"},
+		},
+		{
+			name:      "group_and_version",
+			relations: `[{"field": "spec.target", "kind": "Cluster", "group": "y.io", "version": "v2", "name": "targetCluster"}]`,
+			expected:  map[string]string{"targetCluster": "io.y.v2.Cluster"},
+		},
+		{
+			// Top-level *Ref fields whose name isn't a kind can be declared as well
+			name:      "top_level_ref_field",
+			relations: `[{"field": "placementRef", "kind": "Cluster", "group": "y.io", "version": "v1", "name": "placement"}]`,
+			expected:  map[string]string{"placement": "io.y.v1.Cluster"},
+		},
+		{
+			name:      "unknown_field",
+			relations: `[{"field": "spec.missingRef", "kind": "Cluster", "group": "x.io"}]`,
+			expected:  map[string]string{},
+		},
+		{
+			name:      "unknown_group",
+			relations: `[{"field": "spec.clusterRef", "kind": "Cluster", "group": "z.io"}]`,
+			expected:  map[string]string{},
+		},
+		{
+			name:      "invalid_annotation",
+			relations: `[{"field": "spec.clusterRef", "knd": "Cluster"}]`,
+			expected:  map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := apimocks.NewMockClient(t)
+			mock.EXPECT().Paths().Return(map[string]openapi.GroupVersion{}, nil)
+			b := apischema.NewSchemaBuilder(mock, nil, testlogger.New().HideLogOutput().Logger)
+
+			workload := schemaWithGVK("w.io", "v1", "Workload")
+			workload.Properties = map[string]spec.Schema{
+				"placementRef": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}},
+				"spec": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}, Properties: map[string]spec.Schema{
+					"clusterRef": {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}},
+					"target":     {SchemaProps: spec.SchemaProps{Type: spec.StringOrArray{"object"}}},
+				}}},
+			}
+			b.SetSchemas(map[string]*spec.Schema{
+				"io.w.v1.Workload": workload,
+				"io.x.v1.Cluster":  schemaWithGVK("x.io", "v1", "Cluster"),
+				"io.y.v1.Cluster":  schemaWithGVK("y.io", "v1", "Cluster"),
+				"io.y.v2.Cluster":  schemaWithGVK("y.io", "v2", "Cluster"),
+			})
+
+			b.WithCRDRelations(newCRD(tc.relations)).WithRelationships()
+
+			added := map[string]string{}
+			for name, property := range b.GetSchemas()["io.w.v1.Workload"].Properties {
+				if property.Ref.GetURL() != nil {
+					added[name] = strings.TrimPrefix(property.Ref.String(), "#/definitions/")
+				}
+			}
+			assert.Equal(t, tc.expected, added)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"k8s.io/kube-openapi/pkg/validation/spec"
 
 	"github.com/openmfp/golang-commons/logger"
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
 )
 
 const (
@@ -18,6 +20,8 @@ type schemasComponentsWrapper struct {
 
 type schemaResponse struct {
 	Components schemasComponentsWrapper `json:"components"`
+	// GenerationErrors are written into the schema file for the gateway, see SchemaBuilder.GenerationErrors
+	GenerationErrors []common.GenerationError `json:"x-generation-errors,omitempty"`
 }
 
 type Resolver interface {
@@ -25,14 +29,63 @@ type Resolver interface {
 }
 
 type ResolverProvider struct {
-	log *logger.Logger
+	log                 *logger.Logger
+	deprecationWarnings bool
+	inputOnlyFieldsPath string
+	relationDepth       int
 }
 
-func NewResolver(log *logger.Logger) *ResolverProvider {
-	return &ResolverProvider{log: log}
+// ResolverOption configures optional steps of the schema resolution
+type ResolverOption func(*ResolverProvider)
+
+// WithDeprecationWarnings probes every resource for deprecation warnings of the API server and adds them to the schema.
+// This costs a list request per resource on every resolution.
+func WithDeprecationWarnings() ResolverOption {
+	return func(r *ResolverProvider) {
+		r.deprecationWarnings = true
+	}
+}
+
+// WithInputOnlyFieldRulesPath marks the fields of the rules in the file at path as input-only.
+// The file is read on every resolution, so that changes are picked up with the next schema.
+func WithInputOnlyFieldRulesPath(path string) ResolverOption {
+	return func(r *ResolverProvider) {
+		r.inputOnlyFieldsPath = path
+	}
+}
+
+// WithRelationDepth limits the chains of relationship fields added for *Ref fields, see SchemaBuilder.WithMaxRelationDepth
+func WithRelationDepth(depth int) ResolverOption {
+	return func(r *ResolverProvider) {
+		r.relationDepth = depth
+	}
+}
+
+// ResolverOptionsFromConfig returns the options enabled in the configuration of the listener
+func ResolverOptionsFromConfig(appCfg config.Config) []ResolverOption {
+	var opts []ResolverOption
+	if appCfg.Listener.DeprecationWarnings {
+		opts = append(opts, WithDeprecationWarnings())
+	}
+	if appCfg.Listener.InputOnlyFieldsPath != "" {
+		opts = append(opts, WithInputOnlyFieldRulesPath(appCfg.Listener.InputOnlyFieldsPath))
+	}
+	opts = append(opts, WithRelationDepth(appCfg.Listener.RelationDepth))
+	return opts
+}
+
+func NewResolver(log *logger.Logger, opts ...ResolverOption) *ResolverProvider {
+	r := &ResolverProvider{log: log, relationDepth: DefaultRelationDepth}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *ResolverProvider) Resolve(dc discovery.DiscoveryInterface, rm meta.RESTMapper) ([]byte, error) {
 	crdResolver := NewCRDResolver(dc, rm, r.log)
+	crdResolver.deprecationWarnings = r.deprecationWarnings
+	crdResolver.inputOnlyFieldsPath = r.inputOnlyFieldsPath
+	crdResolver.relationDepth = r.relationDepth
 	return crdResolver.resolveSchema(dc, rm)
 }
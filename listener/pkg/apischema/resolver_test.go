@@ -74,6 +74,7 @@ func TestResolverProvider_Resolve(t *testing.T) {
 				openAPIClient := apischemaMocks.NewMockClient(t)
 				openAPIClient.EXPECT().Paths().Return(tt.openAPIPaths, tt.openAPIErr)
 				dc.EXPECT().OpenAPIV3().Return(openAPIClient)
+				dc.EXPECT().RESTClient().Return(nil)
 			}
 
 			got, err := resolver.Resolve(dc, rm)
@@ -0,0 +1,136 @@
+package schemametrics
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common"
+)
+
+// Stages of the schema generation reported in the failures metric
+const (
+	StageConnection = "connection"
+	StageResolution = "resolution"
+	StageMetadata   = "metadata"
+	StageStorage    = "storage"
+)
+
+var (
+	resolutionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "resolution_duration_seconds",
+		Help:      "Duration of the schema resolution from the API server per cluster",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"cluster"})
+
+	definitions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "definitions",
+		Help:      "Number of definitions in the last generated schema per cluster",
+	}, []string{"cluster"})
+
+	customResourceDefinitions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "custom_resource_definitions",
+		Help:      "Number of kinds in the last generated schema that are not built into Kubernetes per cluster",
+	}, []string{"cluster"})
+
+	skippedDefinitions = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "skipped_definitions",
+		Help:      "Number of group versions and definitions left out of the last generated schema per cluster, e.g. of CRDs with an invalid schema",
+	}, []string{"cluster"})
+
+	failuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "failures_total",
+		Help:      "Total number of failed schema generations per cluster and stage",
+	}, []string{"cluster", "stage"})
+
+	lastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time at which the stored schema was last confirmed to be up to date per cluster",
+	}, []string{"cluster"})
+
+	resyncsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "listener",
+		Subsystem: "schema",
+		Name:      "resyncs_total",
+		Help:      "Total number of regenerations of all schemas by trigger: periodic, signal or http",
+	}, []string{"trigger"})
+)
+
+func init() {
+	// The metrics are served by the metrics server of the controller manager
+	metrics.Registry.MustRegister(resolutionDuration, definitions, customResourceDefinitions, skippedDefinitions, failuresTotal, lastSuccess, resyncsTotal)
+}
+
+// ObserveResolution records the duration of a schema resolution started at start
+func ObserveResolution(cluster string, start time.Time) {
+	resolutionDuration.WithLabelValues(cluster).Observe(time.Since(start).Seconds())
+}
+
+// RecordFailure counts a failed schema generation of cluster in stage
+func RecordFailure(cluster, stage string) {
+	failuresTotal.WithLabelValues(cluster, stage).Inc()
+}
+
+// RecordSuccess records the size of the schema stored for cluster and the time it was confirmed to be up to date,
+// whether the schema was written or the stored one was unchanged
+func RecordSuccess(cluster string, schema []byte) {
+	definitionCount, customResourceCount, skippedCount := countDefinitions(schema)
+	definitions.WithLabelValues(cluster).Set(float64(definitionCount))
+	customResourceDefinitions.WithLabelValues(cluster).Set(float64(customResourceCount))
+	skippedDefinitions.WithLabelValues(cluster).Set(float64(skippedCount))
+	lastSuccess.WithLabelValues(cluster).SetToCurrentTime()
+}
+
+// RecordResync counts a regeneration of all schemas started by trigger
+func RecordResync(trigger string) {
+	resyncsTotal.WithLabelValues(trigger).Inc()
+}
+
+// Forget removes the metrics of a cluster whose schema was deleted, so that it doesn't appear stale
+func Forget(cluster string) {
+	resolutionDuration.DeleteLabelValues(cluster)
+	definitions.DeleteLabelValues(cluster)
+	customResourceDefinitions.DeleteLabelValues(cluster)
+	skippedDefinitions.DeleteLabelValues(cluster)
+	failuresTotal.DeletePartialMatch(prometheus.Labels{"cluster": cluster})
+	lastSuccess.DeleteLabelValues(cluster)
+}
+
+// countDefinitions returns the number of definitions of schema, the number of their kinds whose group is not a
+// built-in Kubernetes API group, i.e. the kinds served from custom resource definitions, and the number of group
+// versions and definitions the schema builder left out
+func countDefinitions(schema []byte) (int, int, int) {
+	var file struct {
+		Definitions      map[string]map[string]any `json:"definitions"`
+		GenerationErrors []common.GenerationError  `json:"x-generation-errors"`
+	}
+	if err := json.Unmarshal(schema, &file); err != nil {
+		return 0, 0, 0
+	}
+
+	customKinds := make(map[string]struct{})
+	for _, definition := range file.Definitions {
+		gvk, ok := common.GroupVersionKind(definition)
+		if !ok || gvk.Kind == "" || clientgoscheme.Scheme.IsGroupRegistered(gvk.Group) {
+			continue
+		}
+		customKinds[gvk.Group+"/"+gvk.Kind] = struct{}{}
+	}
+
+	return len(file.Definitions), len(customKinds), len(file.GenerationErrors)
+}
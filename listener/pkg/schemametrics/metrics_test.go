@@ -0,0 +1,80 @@
+package schemametrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDefinitions(t *testing.T) {
+	tests := []struct {
+		name                   string
+		schema                 string
+		expectedDefinitions    int
+		expectedCustomResource int
+		expectedSkipped        int
+	}{
+		{
+			name: "built_in_and_custom_kinds",
+			schema: `{"definitions": {
+				"io.k8s.api.core.v1.Pod": {"x-kubernetes-group-version-kind": [{"group": "", "version": "v1", "kind": "Pod"}]},
+				"io.k8s.api.apps.v1.Deployment": {"x-kubernetes-group-version-kind": [{"group": "apps", "version": "v1", "kind": "Deployment"}]},
+				"io.k8s.api.core.v1.PodSpec": {"type": "object"},
+				"com.example.v1.Database": {"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Database"}]},
+				"com.example.v1beta1.Database": {"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1beta1", "kind": "Database"}]},
+				"io.k8s.networking.gateway.v1.Gateway": {"x-kubernetes-group-version-kind": [{"group": "gateway.networking.k8s.io", "version": "v1", "kind": "Gateway"}]}
+			}}`,
+			expectedDefinitions:    6,
+			expectedCustomResource: 2,
+		},
+		{
+			name: "skipped_definitions",
+			schema: `{"definitions": {"io.k8s.api.core.v1.PodSpec": {"type": "object"}}, "x-generation-errors": [
+				{"groupVersion": "example.com/v1", "definition": "com.example.v1.Database", "error": "invalid schema"}
+			]}`,
+			expectedDefinitions: 1,
+			expectedSkipped:     1,
+		},
+		{
+			name:   "invalid_schema",
+			schema: `{"definitions": [`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definitionCount, customResourceCount, skippedCount := countDefinitions([]byte(tt.schema))
+			assert.Equal(t, tt.expectedDefinitions, definitionCount)
+			assert.Equal(t, tt.expectedCustomResource, customResourceCount)
+			assert.Equal(t, tt.expectedSkipped, skippedCount)
+		})
+	}
+}
+
+func TestRecordSuccessAndForget(t *testing.T) {
+	schema := []byte(`{"definitions": {"com.example.v1.Database": {"x-kubernetes-group-version-kind": [{"group": "example.com", "version": "v1", "kind": "Database"}]}}}`)
+
+	value := func(t *testing.T, metric prometheus.Metric) float64 {
+		written := &dto.Metric{}
+		require.NoError(t, metric.Write(written))
+		if written.GetCounter() != nil {
+			return written.GetCounter().GetValue()
+		}
+		return written.GetGauge().GetValue()
+	}
+
+	RecordFailure("root:metrics", StageResolution)
+	RecordSuccess("root:metrics", schema)
+	assert.Equal(t, float64(1), value(t, definitions.WithLabelValues("root:metrics")))
+	assert.Equal(t, float64(1), value(t, customResourceDefinitions.WithLabelValues("root:metrics")))
+	assert.Equal(t, float64(1), value(t, failuresTotal.WithLabelValues("root:metrics", StageResolution)))
+	assert.NotZero(t, value(t, lastSuccess.WithLabelValues("root:metrics")))
+
+	// Nothing is left to delete once the cluster is forgotten
+	Forget("root:metrics")
+	assert.False(t, lastSuccess.DeleteLabelValues("root:metrics"))
+	assert.False(t, failuresTotal.DeleteLabelValues("root:metrics", StageResolution))
+}
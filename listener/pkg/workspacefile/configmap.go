@@ -0,0 +1,166 @@
+package workspacefile
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// SchemaLabelKey marks the ConfigMaps holding schemas, the gateway watches the ConfigMaps with this label
+	SchemaLabelKey = "gateway.openmfp.org/schema"
+	// ClusterAnnotationKey holds the cluster name of a schema, which often isn't a valid ConfigMap name
+	ClusterAnnotationKey = "gateway.openmfp.org/cluster"
+	// SchemaDataKey is the binary data key of the gzipped schema, compression keeps large schemas below the ConfigMap size limit
+	SchemaDataKey = "schema.json.gz"
+
+	configMapNamePrefix = "schema-"
+	// maxConfigMapNameLength leaves room for the hash suffix within the 253 characters of a ConfigMap name
+	maxConfigMapNameLength  = 253 - len(configMapNamePrefix) - 1 - configMapNameHashLength
+	configMapNameHashLength = 10
+)
+
+var ErrInvalidSchemaConfigMap = errors.New("ConfigMap does not hold a schema")
+
+// ConfigMapIOHandler stores the schemas in ConfigMaps, so that listener and gateway can run in separate deployments
+type ConfigMapIOHandler struct {
+	client    client.Client
+	namespace string
+}
+
+func NewConfigMapIOHandler(clt client.Client, namespace string) *ConfigMapIOHandler {
+	return &ConfigMapIOHandler{
+		client:    clt,
+		namespace: namespace,
+	}
+}
+
+func (h *ConfigMapIOHandler) Read(clusterName string) ([]byte, error) {
+	configMap := &corev1.ConfigMap{}
+	if err := h.client.Get(context.Background(), client.ObjectKey{Namespace: h.namespace, Name: ConfigMapName(clusterName)}, configMap); err != nil {
+		return nil, errors.Join(ErrReadJSONFile, notExist(err))
+	}
+
+	_, JSON, err := SchemaFromConfigMap(configMap)
+	if err != nil {
+		return nil, errors.Join(ErrReadJSONFile, err)
+	}
+
+	return JSON, nil
+}
+
+func (h *ConfigMapIOHandler) Write(JSON []byte, clusterName string) error {
+	compressed, err := gzipBytes(JSON)
+	if err != nil {
+		return errors.Join(ErrWriteJSONFile, err)
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(clusterName),
+			Namespace: h.namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(context.Background(), h.client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[SchemaLabelKey] = "true"
+		if configMap.Annotations == nil {
+			configMap.Annotations = map[string]string{}
+		}
+		configMap.Annotations[ClusterAnnotationKey] = clusterName
+		configMap.BinaryData = map[string][]byte{SchemaDataKey: compressed}
+		return nil
+	})
+	if err != nil {
+		return errors.Join(ErrWriteJSONFile, err)
+	}
+
+	return nil
+}
+
+func (h *ConfigMapIOHandler) Delete(clusterName string) error {
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName(clusterName),
+			Namespace: h.namespace,
+		},
+	}
+	if err := h.client.Delete(context.Background(), configMap); err != nil {
+		return errors.Join(ErrDeleteJSONFile, notExist(err))
+	}
+
+	return nil
+}
+
+func (h *ConfigMapIOHandler) List() ([]string, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := h.client.List(context.Background(), configMaps, client.InNamespace(h.namespace), client.MatchingLabels{SchemaLabelKey: "true"}); err != nil {
+		return nil, errors.Join(ErrListJSONFiles, err)
+	}
+
+	clusterNames := make([]string, 0, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		if clusterName := configMap.Annotations[ClusterAnnotationKey]; clusterName != "" {
+			clusterNames = append(clusterNames, clusterName)
+		}
+	}
+
+	return clusterNames, nil
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the schema of a cluster. Cluster names like root:orgs:team
+// or virtual-workspace/name are turned into valid names, the hash of the cluster name keeps them unique.
+func ConfigMapName(clusterName string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r - 'A' + 'a'
+		default:
+			return '-'
+		}
+	}, clusterName)
+	if len(name) > maxConfigMapNameLength {
+		name = name[:maxConfigMapNameLength]
+	}
+
+	hash := sha256.Sum256([]byte(clusterName))
+	return configMapNamePrefix + name + "-" + hex.EncodeToString(hash[:])[:configMapNameHashLength]
+}
+
+// SchemaFromConfigMap returns the cluster name and the schema stored in a ConfigMap
+func SchemaFromConfigMap(configMap *corev1.ConfigMap) (string, []byte, error) {
+	clusterName := configMap.Annotations[ClusterAnnotationKey]
+	compressed, ok := configMap.BinaryData[SchemaDataKey]
+	if clusterName == "" || !ok {
+		return "", nil, fmt.Errorf("%w: %s/%s", ErrInvalidSchemaConfigMap, configMap.Namespace, configMap.Name)
+	}
+
+	JSON, err := gunzip(compressed)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return clusterName, JSON, nil
+}
+
+// notExist marks missing ConfigMaps like missing files, so that callers can check for fs.ErrNotExist with either backend
+func notExist(err error) error {
+	if apierrors.IsNotFound(err) {
+		return errors.Join(fs.ErrNotExist, err)
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+package workspacefile
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigMapIOHandler(t *testing.T) {
+	clt := fake.NewClientBuilder().Build()
+	handler := NewConfigMapIOHandler(clt, "schemas")
+	clusterName := "root:sap:openmfp"
+
+	_, err := handler.Read(clusterName)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorIs(t, err, ErrReadJSONFile)
+
+	require.NoError(t, handler.Write(testJSON, clusterName))
+	JSON, err := handler.Read(clusterName)
+	require.NoError(t, err)
+	assert.Equal(t, testJSON, JSON)
+
+	configMap := &corev1.ConfigMap{}
+	require.NoError(t, clt.Get(t.Context(), client.ObjectKey{Namespace: "schemas", Name: ConfigMapName(clusterName)}, configMap))
+	assert.Equal(t, "true", configMap.Labels[SchemaLabelKey])
+	assert.Equal(t, clusterName, configMap.Annotations[ClusterAnnotationKey])
+	assert.Equal(t, []byte{0x1f, 0x8b}, configMap.BinaryData[SchemaDataKey][:2], "the schema should be gzipped")
+
+	updatedJSON := []byte(`{"key":"updated"}`)
+	require.NoError(t, handler.Write(updatedJSON, clusterName))
+	JSON, err = handler.Read(clusterName)
+	require.NoError(t, err)
+	assert.Equal(t, updatedJSON, JSON)
+
+	clusterNames, err := handler.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{clusterName}, clusterNames)
+
+	require.NoError(t, handler.Delete(clusterName))
+	clusterNames, err = handler.List()
+	require.NoError(t, err)
+	assert.Empty(t, clusterNames)
+
+	err = handler.Delete(clusterName)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.ErrorIs(t, err, ErrDeleteJSONFile)
+}
+
+func TestConfigMapName(t *testing.T) {
+	names := map[string]bool{}
+	for _, clusterName := range []string{
+		"root:orgs:team",
+		"root/orgs/team",
+		"virtual-workspace/Custom_WS",
+		strings.Repeat("long-cluster-name", 20),
+	} {
+		name := ConfigMapName(clusterName)
+		assert.Empty(t, validation.IsDNS1123Subdomain(name), clusterName)
+		assert.True(t, strings.HasPrefix(name, "schema-"), name)
+		names[name] = true
+	}
+	assert.Len(t, names, 4, "names should be unique")
+}
+
+func TestSchemaFromConfigMap_Invalid(t *testing.T) {
+	_, _, err := SchemaFromConfigMap(&corev1.ConfigMap{})
+	assert.ErrorIs(t, err, ErrInvalidSchemaConfigMap)
+}
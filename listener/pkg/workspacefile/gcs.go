@@ -0,0 +1,36 @@
+package workspacefile
+
+import (
+	"errors"
+	"net/http"
+	"os"
+)
+
+const (
+	// gcsEndpoint is the XML API of Cloud Storage, which is compatible with the S3 API for requests signed with HMAC keys
+	gcsEndpoint = "https://storage.googleapis.com"
+	// gcsRegion is the region requests to the XML API are signed for, Cloud Storage accepts auto for all buckets
+	gcsRegion = "auto"
+)
+
+var ErrMissingGCSCredentials = errors.New("GCS_HMAC_ACCESS_ID and GCS_HMAC_SECRET must be set for the gcs schema storage")
+
+// GCSCredentialsFromEnv reads the HMAC key of a service account from the GCS_HMAC_ACCESS_ID and GCS_HMAC_SECRET
+// environment variables
+func GCSCredentialsFromEnv() (S3Credentials, error) {
+	credentials := S3Credentials{
+		AccessKeyID:     os.Getenv("GCS_HMAC_ACCESS_ID"),
+		SecretAccessKey: os.Getenv("GCS_HMAC_SECRET"),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return S3Credentials{}, ErrMissingGCSCredentials
+	}
+
+	return credentials, nil
+}
+
+// NewGCSIOHandler creates the handler of a Cloud Storage bucket. The schemas are stored like in S3 buckets, through the
+// XML API of Cloud Storage, which is why the handler is an S3IOHandler.
+func NewGCSIOHandler(httpClient *http.Client, bucket, prefix string, credentials S3Credentials) (*S3IOHandler, error) {
+	return NewS3IOHandler(httpClient, gcsEndpoint, gcsRegion, bucket, prefix, credentials)
+}
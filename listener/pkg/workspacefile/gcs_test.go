@@ -0,0 +1,30 @@
+package workspacefile
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCSCredentialsFromEnv(t *testing.T) {
+	t.Setenv("GCS_HMAC_ACCESS_ID", "")
+	t.Setenv("GCS_HMAC_SECRET", "secret")
+	_, err := GCSCredentialsFromEnv()
+	assert.ErrorIs(t, err, ErrMissingGCSCredentials)
+
+	t.Setenv("GCS_HMAC_ACCESS_ID", "GOOG1EXAMPLE")
+	credentials, err := GCSCredentialsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, S3Credentials{AccessKeyID: "GOOG1EXAMPLE", SecretAccessKey: "secret"}, credentials)
+}
+
+func TestNewGCSIOHandler(t *testing.T) {
+	handler, err := NewGCSIOHandler(http.DefaultClient, "schemas", "gateway/", S3Credentials{AccessKeyID: "GOOG1EXAMPLE"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://storage.googleapis.com", handler.endpoint.String())
+	assert.Equal(t, "auto", handler.region)
+	assert.Equal(t, "gateway/root.json.gz", handler.objectKey("root"))
+}
@@ -2,9 +2,12 @@ package workspacefile
 
 import (
 	"errors"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
+	"time"
 )
 
 var (
@@ -12,26 +15,38 @@ var (
 	ErrReadJSONFile     = errors.New("failed to read JSON file")
 	ErrWriteJSONFile    = errors.New("failed to write JSON to file")
 	ErrDeleteJSONFile   = errors.New("failed to delete JSON file")
+	ErrListJSONFiles    = errors.New("failed to list JSON files")
 )
 
 type IOHandler interface {
 	Read(clusterName string) ([]byte, error)
 	Write(JSON []byte, clusterName string) error
 	Delete(clusterName string) error
+	// List returns the cluster names of all stored schemas
+	List() ([]string, error)
 }
 
 type IOHandlerProvider struct {
 	schemasDir string
+	// versionsDir keeps the last keepVersions schemas of every cluster, see WithVersions
+	versionsDir  string
+	keepVersions int
+	now          func() time.Time
 }
 
-func NewIOHandler(schemasDir string) (*IOHandlerProvider, error) {
+func NewIOHandler(schemasDir string, opts ...IOHandlerOption) (*IOHandlerProvider, error) {
 	if err := os.MkdirAll(schemasDir, os.ModePerm); err != nil {
 		return nil, errors.Join(ErrCreateSchemasDir, err)
 	}
 
-	return &IOHandlerProvider{
+	h := &IOHandlerProvider{
 		schemasDir: schemasDir,
-	}, nil
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h, nil
 }
 
 func (h *IOHandlerProvider) Read(clusterName string) ([]byte, error) {
@@ -52,9 +67,13 @@ func (h *IOHandlerProvider) Write(JSON []byte, clusterName string) error {
 		return errors.Join(ErrWriteJSONFile, err)
 	}
 
-	if err := os.WriteFile(fileName, JSON, os.ModePerm); err != nil {
+	if err := writeFileAtomic(fileName, JSON, 0o644); err != nil {
 		return errors.Join(ErrWriteJSONFile, err)
 	}
+
+	if h.keepVersions > 0 {
+		return h.storeVersion(JSON, clusterName)
+	}
 	return nil
 }
 
@@ -64,5 +83,60 @@ func (h *IOHandlerProvider) Delete(clusterName string) error {
 		return errors.Join(ErrDeleteJSONFile, err)
 	}
 
+	if h.keepVersions > 0 {
+		return h.deleteVersions(clusterName)
+	}
 	return nil
 }
+
+func (h *IOHandlerProvider) List() ([]string, error) {
+	var clusterNames []string
+	err := filepath.WalkDir(h.schemasDir, func(fileName string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || IsTemporaryFile(fileName) {
+			return err
+		}
+
+		clusterName, err := filepath.Rel(h.schemasDir, fileName)
+		if err != nil {
+			return err
+		}
+		clusterNames = append(clusterNames, filepath.ToSlash(clusterName))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Join(ErrListJSONFiles, err)
+	}
+
+	return clusterNames, nil
+}
+
+// temporaryFilePrefix starts the names of the files a schema is written to before it replaces the stored one
+const temporaryFilePrefix = ".tmp-"
+
+// IsTemporaryFile reports whether the file is a schema that is still being written, which readers have to skip
+func IsTemporaryFile(fileName string) bool {
+	return strings.HasPrefix(filepath.Base(fileName), temporaryFilePrefix)
+}
+
+// writeFileAtomic writes the data to a temporary file next to the file and renames it, so that the gateway replicas
+// reading the shared directory never see a partially written file
+func writeFileAtomic(fileName string, data []byte, perm os.FileMode) error {
+	f, err := os.CreateTemp(filepath.Dir(fileName), temporaryFilePrefix+filepath.Base(fileName)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), fileName)
+}
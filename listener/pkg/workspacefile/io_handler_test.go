@@ -98,3 +98,41 @@ func TestWrite(t *testing.T) {
 		})
 	}
 }
+
+func TestList(t *testing.T) {
+	handler, err := NewIOHandler(t.TempDir())
+	assert.NoError(t, err)
+
+	clusterNames, err := handler.List()
+	assert.NoError(t, err)
+	assert.Empty(t, clusterNames)
+
+	for _, clusterName := range []string{"root:sap:openmfp", "virtual-workspace/api-export-ws"} {
+		assert.NoError(t, handler.Write(testJSON, clusterName))
+	}
+
+	// Schemas that are still being written by another replica are not listed
+	assert.NoError(t, os.WriteFile(filepath.Join(handler.schemasDir, temporaryFilePrefix+"root:other-123"), testJSON, 0o644))
+
+	clusterNames, err = handler.List()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"root:sap:openmfp", "virtual-workspace/api-export-ws"}, clusterNames)
+}
+
+func TestWrite_ReplacesFile(t *testing.T) {
+	handler, err := NewIOHandler(t.TempDir())
+	assert.NoError(t, err)
+
+	assert.NoError(t, handler.Write([]byte(`{"version":1}`), "root:sap:openmfp"))
+	assert.NoError(t, handler.Write(testJSON, "root:sap:openmfp"))
+
+	JSON, err := handler.Read("root:sap:openmfp")
+	assert.NoError(t, err)
+	assert.Equal(t, testJSON, JSON)
+
+	// The temporary file is renamed, none is left behind
+	entries, err := os.ReadDir(handler.schemasDir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.True(t, IsTemporaryFile(temporaryFilePrefix+"root:sap:openmfp-123"))
+}
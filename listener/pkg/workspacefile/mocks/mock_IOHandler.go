@@ -63,6 +63,63 @@ func (_c *MockIOHandler_Delete_Call) RunAndReturn(run func(string) error) *MockI
 	return _c
 }
 
+// List provides a mock function with no fields
+func (_m *MockIOHandler) List() ([]string, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]string, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockIOHandler_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIOHandler_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+func (_e *MockIOHandler_Expecter) List() *MockIOHandler_List_Call {
+	return &MockIOHandler_List_Call{Call: _e.mock.On("List")}
+}
+
+func (_c *MockIOHandler_List_Call) Run(run func()) *MockIOHandler_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockIOHandler_List_Call) Return(_a0 []string, _a1 error) *MockIOHandler_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockIOHandler_List_Call) RunAndReturn(run func() ([]string, error)) *MockIOHandler_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Read provides a mock function with given fields: clusterName
 func (_m *MockIOHandler) Read(clusterName string) ([]byte, error) {
 	ret := _m.Called(clusterName)
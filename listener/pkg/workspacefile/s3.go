@@ -0,0 +1,347 @@
+package workspacefile
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// s3ObjectSuffix is appended to the cluster names, the objects hold the gzipped schemas
+	s3ObjectSuffix = ".json.gz"
+
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3TimeFormat     = "20060102T150405Z"
+	s3DateFormat     = "20060102"
+	s3MaxErrorLength = 1024
+)
+
+var (
+	ErrMissingS3Credentials = errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set for the s3 schema storage")
+	ErrS3Request            = errors.New("S3 request failed")
+)
+
+// S3Credentials are the static credentials the requests to the bucket are signed with
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// S3CredentialsFromEnv reads the credentials from the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+// environment variables, like the AWS CLI does
+func S3CredentialsFromEnv() (S3Credentials, error) {
+	credentials := S3Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return S3Credentials{}, ErrMissingS3Credentials
+	}
+
+	return credentials, nil
+}
+
+// S3Object is a stored schema as listed by S3IOHandler.Objects
+type S3Object struct {
+	ClusterName string
+	ETag        string
+}
+
+// S3IOHandler stores the schemas as objects of an S3 bucket, so that listener and gateway can run in separate
+// deployments or clusters. Any S3 compatible object storage addressed with path-style URLs works, e.g. MinIO.
+type S3IOHandler struct {
+	httpClient  *http.Client
+	endpoint    *url.URL
+	bucket      string
+	prefix      string
+	region      string
+	credentials S3Credentials
+	now         func() time.Time
+}
+
+// NewS3IOHandler creates the handler of the bucket at the endpoint, the default endpoint of AWS in the region if empty.
+// The objects are named after the clusters, below the prefix.
+func NewS3IOHandler(httpClient *http.Client, endpoint, region, bucket, prefix string, credentials S3Credentials) (*S3IOHandler, error) {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: %w", endpoint, err)
+	}
+	if endpointURL.Scheme == "" || endpointURL.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q: scheme and host are required", endpoint)
+	}
+
+	return &S3IOHandler{
+		httpClient:  httpClient,
+		endpoint:    endpointURL,
+		bucket:      bucket,
+		prefix:      prefix,
+		region:      region,
+		credentials: credentials,
+		now:         time.Now,
+	}, nil
+}
+
+func (h *S3IOHandler) Read(clusterName string) ([]byte, error) {
+	compressed, err := h.do(http.MethodGet, h.objectKey(clusterName), nil, nil)
+	if err != nil {
+		return nil, errors.Join(ErrReadJSONFile, err)
+	}
+
+	JSON, err := gunzip(compressed)
+	if err != nil {
+		return nil, errors.Join(ErrReadJSONFile, err)
+	}
+
+	return JSON, nil
+}
+
+func (h *S3IOHandler) Write(JSON []byte, clusterName string) error {
+	compressed, err := gzipBytes(JSON)
+	if err != nil {
+		return errors.Join(ErrWriteJSONFile, err)
+	}
+
+	// objects are replaced as a whole, readers never see a partially written schema
+	if _, err := h.do(http.MethodPut, h.objectKey(clusterName), nil, compressed); err != nil {
+		return errors.Join(ErrWriteJSONFile, err)
+	}
+
+	return nil
+}
+
+// Delete removes the schema of the cluster. S3 doesn't report missing objects on deletion, so the object is looked up
+// first, to return fs.ErrNotExist for them like the other backends.
+func (h *S3IOHandler) Delete(clusterName string) error {
+	if _, err := h.do(http.MethodHead, h.objectKey(clusterName), nil, nil); err != nil {
+		return errors.Join(ErrDeleteJSONFile, err)
+	}
+
+	if _, err := h.do(http.MethodDelete, h.objectKey(clusterName), nil, nil); err != nil {
+		return errors.Join(ErrDeleteJSONFile, err)
+	}
+
+	return nil
+}
+
+func (h *S3IOHandler) List() ([]string, error) {
+	objects, err := h.Objects()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterNames := make([]string, 0, len(objects))
+	for _, object := range objects {
+		clusterNames = append(clusterNames, object.ClusterName)
+	}
+
+	return clusterNames, nil
+}
+
+// Objects returns the stored schemas with their ETags, which change with every write
+func (h *S3IOHandler) Objects() ([]S3Object, error) {
+	var objects []S3Object
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {h.prefix}}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		body, err := h.do(http.MethodGet, "", query, nil)
+		if err != nil {
+			return nil, errors.Join(ErrListJSONFiles, err)
+		}
+
+		var result listBucketResult
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return nil, errors.Join(ErrListJSONFiles, err)
+		}
+
+		for _, content := range result.Contents {
+			clusterName, ok := strings.CutSuffix(strings.TrimPrefix(content.Key, h.prefix), s3ObjectSuffix)
+			if !ok || clusterName == "" {
+				continue
+			}
+			objects = append(objects, S3Object{ClusterName: clusterName, ETag: content.ETag})
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return objects, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key  string `xml:"Key"`
+		ETag string `xml:"ETag"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func (h *S3IOHandler) objectKey(clusterName string) string {
+	return h.prefix + clusterName + s3ObjectSuffix
+}
+
+// do sends a signed request for the object key, or for the bucket if the key is empty, and returns the response body
+func (h *S3IOHandler) do(method, key string, query url.Values, body []byte) ([]byte, error) {
+	requestURL := *h.endpoint
+	requestURL.Path = strings.TrimSuffix(h.endpoint.Path, "/") + "/" + h.bucket
+	if key != "" {
+		requestURL.Path += "/" + key
+	}
+	requestURL.RawPath = s3Escape(requestURL.Path, false)
+	requestURL.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(context.Background(), method, requestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	signV4(req, body, h.credentials, h.region, h.now())
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Join(ErrS3Request, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Join(ErrS3Request, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound && key != "":
+		return nil, errors.Join(fs.ErrNotExist, fmt.Errorf("%w: %s %s: %s", ErrS3Request, method, key, resp.Status))
+	case resp.StatusCode >= http.StatusMultipleChoices:
+		if len(respBody) > s3MaxErrorLength {
+			respBody = respBody[:s3MaxErrorLength]
+		}
+		return nil, fmt.Errorf("%w: %s %s: %s: %s", ErrS3Request, method, requestURL.Path, resp.Status, respBody)
+	}
+
+	return respBody, nil
+}
+
+// signV4 adds the AWS Signature Version 4 headers of the S3 service to the request
+func signV4(req *http.Request, body []byte, credentials S3Credentials, region string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format(s3TimeFormat)
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	headerNames := make([]string, 0, len(headers))
+	for name := range headers {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range headerNames {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(headerNames, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3Escape(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := now.Format(s3DateFormat) + "/" + region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{s3Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), now.Format(s3DateFormat))
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// canonicalQuery encodes the query sorted by keys, as the signature requires
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// s3Escape percent-encodes everything but the unreserved characters, slashes are kept in paths
+func s3Escape(s string, encodeSlash bool) string {
+	var escaped strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9', b == '-', b == '.', b == '_', b == '~':
+			escaped.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			escaped.WriteByte(b)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", b)
+		}
+	}
+
+	return escaped.String()
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,131 @@
+package workspacefile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+)
+
+const (
+	FilesystemBackend = "filesystem"
+	ConfigMapBackend  = "configmap"
+	S3Backend         = "s3"
+	GCSBackend        = "gcs"
+
+	s3RequestTimeout = time.Minute
+)
+
+var ErrUnknownStorageBackend = errors.New("unknown schema storage backend")
+
+// NewIOHandlerFromConfig returns the IOHandler of the configured schema storage backend
+func NewIOHandlerFromConfig(appCfg config.Config) (IOHandler, error) {
+	switch appCfg.SchemaStorage.Backend {
+	case "", FilesystemBackend:
+		ioHandler, err := NewIOHandler(appCfg.OpenApiDefinitionsPath, VersionsFromConfig(appCfg))
+		if err != nil {
+			return nil, err
+		}
+
+		return ioHandler, nil
+	case ConfigMapBackend:
+		restCfg, err := StorageRestConfig(appCfg)
+		if err != nil {
+			return nil, err
+		}
+
+		clt, err := client.New(restCfg, client.Options{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create schema storage client: %w", err)
+		}
+
+		return NewConfigMapIOHandler(clt, appCfg.SchemaStorage.Namespace), nil
+	case S3Backend:
+		return S3IOHandlerFromConfig(appCfg)
+	case GCSBackend:
+		return GCSIOHandlerFromConfig(appCfg)
+	default:
+		return nil, fmt.Errorf(
+			"%w %q, supported are %q, %q, %q and %q",
+			ErrUnknownStorageBackend, appCfg.SchemaStorage.Backend, FilesystemBackend, ConfigMapBackend, S3Backend, GCSBackend,
+		)
+	}
+}
+
+// S3IOHandlerFromConfig returns the handler of the configured bucket, signing the requests with the credentials
+// of the AWS environment variables
+func S3IOHandlerFromConfig(appCfg config.Config) (*S3IOHandler, error) {
+	credentials, err := S3CredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	s3Cfg := appCfg.SchemaStorage.S3
+	return NewS3IOHandler(&http.Client{Timeout: s3RequestTimeout}, s3Cfg.Endpoint, s3Cfg.Region, s3Cfg.Bucket, s3Cfg.Prefix, credentials)
+}
+
+// GCSIOHandlerFromConfig returns the handler of the configured Cloud Storage bucket, signing the requests with the
+// HMAC key of the GCS environment variables
+func GCSIOHandlerFromConfig(appCfg config.Config) (*S3IOHandler, error) {
+	credentials, err := GCSCredentialsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	gcsCfg := appCfg.SchemaStorage.GCS
+	return NewGCSIOHandler(&http.Client{Timeout: s3RequestTimeout}, gcsCfg.Bucket, gcsCfg.Prefix, credentials)
+}
+
+// VersionsFromConfig returns the configured versioning of the filesystem backend
+func VersionsFromConfig(appCfg config.Config) IOHandlerOption {
+	return WithVersions(VersionsDir(appCfg.OpenApiDefinitionsPath, appCfg.SchemaStorage.VersionDir), appCfg.SchemaStorage.Versions)
+}
+
+// StorageRestConfig returns the config of the cluster holding the schema ConfigMaps. Without a configured kubeconfig,
+// the config is loaded like the one of the listener, i.e. from KUBECONFIG or the in-cluster service account.
+func StorageRestConfig(appCfg config.Config) (*rest.Config, error) {
+	if appCfg.SchemaStorage.Kubeconfig == "" {
+		return ctrl.GetConfig()
+	}
+
+	restCfg, err := clientcmd.BuildConfigFromFlags("", appCfg.SchemaStorage.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema storage kubeconfig: %w", err)
+	}
+
+	return restCfg, nil
+}
+
+// gzipBytes compresses the schemas of the backends storing them in size limited or billed objects
+func gzipBytes(data []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(data); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return compressed.Bytes(), nil
+}
+
+func gunzip(compressed []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+
+	return io.ReadAll(gz)
+}
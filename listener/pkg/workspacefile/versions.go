@@ -0,0 +1,215 @@
+package workspacefile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// versionFormat names the versions after the time they were written, so that they sort by name
+	versionFormat = "20060102T150405.000000000Z"
+	versionSuffix = ".json"
+	// PinnedFileName holds the version a cluster is pinned to, next to its versions
+	PinnedFileName = "pinned"
+)
+
+var (
+	ErrVersioningDisabled = errors.New("schema versioning is disabled")
+	ErrUnknownVersion     = errors.New("unknown schema version")
+	ErrWriteVersion       = errors.New("failed to write schema version")
+	ErrListVersions       = errors.New("failed to list schema versions")
+)
+
+// SchemaVersion is a previously written schema of a cluster
+type SchemaVersion struct {
+	Version   string    `json:"version"`
+	Timestamp time.Time `json:"timestamp"`
+	// Pinned is set for the version served instead of the current schema, see SchemaVersions.Pin
+	Pinned bool `json:"pinned,omitempty"`
+}
+
+// SchemaVersions keeps the previously written schemas of the clusters, so that the gateway can fall back to or be
+// pinned to a schema that built before
+type SchemaVersions interface {
+	// Versions returns the kept versions of the cluster, newest first
+	Versions(clusterName string) ([]SchemaVersion, error)
+	// VersionPath returns the file the version of the cluster is stored in
+	VersionPath(clusterName, version string) string
+	// PinnedVersion returns the version the cluster is pinned to
+	PinnedVersion(clusterName string) (string, bool)
+	// Pin serves the version instead of the current schema of the cluster until Unpin is called
+	Pin(clusterName, version string) error
+	Unpin(clusterName string) error
+}
+
+// IOHandlerOption configures the IOHandlerProvider returned by NewIOHandler
+type IOHandlerOption func(*IOHandlerProvider)
+
+// WithVersions keeps the last keep schemas written for every cluster in versionsDir. The versions directory must not be
+// below the schemas directory, whose files are all served as clusters. keep <= 0 disables versioning.
+func WithVersions(versionsDir string, keep int) IOHandlerOption {
+	return func(h *IOHandlerProvider) {
+		if keep > 0 {
+			h.versionsDir = versionsDir
+			h.keepVersions = keep
+		}
+	}
+}
+
+// VersionsDir returns the versions directory of the schemas directory if none is configured, next to it with the
+// suffix .versions
+func VersionsDir(schemasDir, versionsDir string) string {
+	if versionsDir != "" {
+		return versionsDir
+	}
+	return filepath.Clean(schemasDir) + ".versions"
+}
+
+// storeVersion keeps JSON as the newest version of the cluster, unless it equals the newest version already, and
+// removes the versions beyond the ones to keep. The pinned version is never removed.
+func (h *IOHandlerProvider) storeVersion(JSON []byte, clusterName string) error {
+	versions, err := h.Versions(clusterName)
+	if err != nil {
+		return err
+	}
+
+	if len(versions) > 0 {
+		newest, err := os.ReadFile(h.VersionPath(clusterName, versions[0].Version))
+		if err == nil && bytes.Equal(newest, JSON) {
+			return nil
+		}
+	}
+
+	dir := h.clusterVersionsDir(clusterName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Join(ErrWriteVersion, err)
+	}
+	version := h.now().UTC().Format(versionFormat)
+	if err := writeFileAtomic(h.VersionPath(clusterName, version), JSON, 0o644); err != nil {
+		return errors.Join(ErrWriteVersion, err)
+	}
+
+	versions = append([]SchemaVersion{{Version: version}}, versions...)
+	for _, old := range versions[min(h.keepVersions, len(versions)):] {
+		if old.Pinned {
+			continue
+		}
+		if err := os.Remove(h.VersionPath(clusterName, old.Version)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return errors.Join(ErrWriteVersion, err)
+		}
+	}
+
+	return nil
+}
+
+func (h *IOHandlerProvider) Versions(clusterName string) ([]SchemaVersion, error) {
+	if h.keepVersions == 0 {
+		return nil, ErrVersioningDisabled
+	}
+
+	entries, err := os.ReadDir(h.clusterVersionsDir(clusterName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Join(ErrListVersions, err)
+	}
+
+	pinned, _ := h.PinnedVersion(clusterName)
+	var versions []SchemaVersion
+	for _, entry := range entries {
+		// The directories are the versions of clusters below this one, e.g. of virtual-workspace/name
+		version, ok := strings.CutSuffix(entry.Name(), versionSuffix)
+		if entry.IsDir() || !ok {
+			continue
+		}
+		timestamp, err := time.Parse(versionFormat, version)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, SchemaVersion{Version: version, Timestamp: timestamp, Pinned: version == pinned})
+	}
+
+	slices.SortFunc(versions, func(a, b SchemaVersion) int {
+		return strings.Compare(b.Version, a.Version)
+	})
+	return versions, nil
+}
+
+func (h *IOHandlerProvider) VersionPath(clusterName, version string) string {
+	return filepath.Join(h.clusterVersionsDir(clusterName), version+versionSuffix)
+}
+
+func (h *IOHandlerProvider) PinnedVersion(clusterName string) (string, bool) {
+	if h.keepVersions == 0 {
+		return "", false
+	}
+
+	version, err := os.ReadFile(filepath.Join(h.clusterVersionsDir(clusterName), PinnedFileName))
+	if err != nil || len(bytes.TrimSpace(version)) == 0 {
+		return "", false
+	}
+	return string(bytes.TrimSpace(version)), true
+}
+
+func (h *IOHandlerProvider) Pin(clusterName, version string) error {
+	if h.keepVersions == 0 {
+		return ErrVersioningDisabled
+	}
+
+	// Versions are file names, they must not point outside the versions of the cluster
+	if _, err := time.Parse(versionFormat, version); err != nil {
+		return fmt.Errorf("%w %q of cluster %s", ErrUnknownVersion, version, clusterName)
+	}
+	if _, err := os.Stat(h.VersionPath(clusterName, version)); err != nil {
+		return fmt.Errorf("%w %q of cluster %s", ErrUnknownVersion, version, clusterName)
+	}
+
+	if err := writeFileAtomic(filepath.Join(h.clusterVersionsDir(clusterName), PinnedFileName), []byte(version), 0o644); err != nil {
+		return errors.Join(ErrWriteVersion, err)
+	}
+	return nil
+}
+
+func (h *IOHandlerProvider) Unpin(clusterName string) error {
+	if h.keepVersions == 0 {
+		return ErrVersioningDisabled
+	}
+
+	if err := os.Remove(filepath.Join(h.clusterVersionsDir(clusterName), PinnedFileName)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return errors.Join(ErrWriteVersion, err)
+	}
+	return nil
+}
+
+// deleteVersions removes the versions of a deleted cluster, but keeps the directories of the clusters below it
+func (h *IOHandlerProvider) deleteVersions(clusterName string) error {
+	entries, err := os.ReadDir(h.clusterVersionsDir(clusterName))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return errors.Join(ErrDeleteJSONFile, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(h.clusterVersionsDir(clusterName), entry.Name())); err != nil {
+			return errors.Join(ErrDeleteJSONFile, err)
+		}
+	}
+	return nil
+}
+
+func (h *IOHandlerProvider) clusterVersionsDir(clusterName string) string {
+	return filepath.Join(h.versionsDir, clusterName)
+}
@@ -0,0 +1,95 @@
+package workspacefile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ SchemaVersions = (*IOHandlerProvider)(nil)
+
+func newVersionedIOHandler(t *testing.T, keep int) *IOHandlerProvider {
+	schemasDir := filepath.Join(t.TempDir(), "definitions")
+	handler, err := NewIOHandler(schemasDir, WithVersions(VersionsDir(schemasDir, ""), keep))
+	require.NoError(t, err)
+
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	handler.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	return handler
+}
+
+func TestVersions(t *testing.T) {
+	handler := newVersionedIOHandler(t, 2)
+	clusterName := "virtual-workspace/api-export-ws"
+
+	for _, schema := range []string{`{"v":1}`, `{"v":2}`, `{"v":2}`, `{"v":3}`} {
+		require.NoError(t, handler.Write([]byte(schema), clusterName))
+	}
+
+	versions, err := handler.Versions(clusterName)
+	require.NoError(t, err)
+	require.Len(t, versions, 2, "identical schemas are kept once and only the newest versions are kept")
+	assert.True(t, versions[0].Timestamp.After(versions[1].Timestamp))
+
+	for i, expected := range []string{`{"v":3}`, `{"v":2}`} {
+		content, err := os.ReadFile(handler.VersionPath(clusterName, versions[i].Version))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(content))
+	}
+
+	clusterNames, err := handler.List()
+	require.NoError(t, err)
+	assert.Equal(t, []string{clusterName}, clusterNames, "versions are not stored below the schemas directory")
+}
+
+func TestVersions_Pin(t *testing.T) {
+	handler := newVersionedIOHandler(t, 1)
+	clusterName := "root:orgs"
+
+	require.NoError(t, handler.Write([]byte(`{"v":1}`), clusterName))
+	versions, err := handler.Versions(clusterName)
+	require.NoError(t, err)
+	require.Len(t, versions, 1)
+	pinned := versions[0].Version
+
+	assert.ErrorIs(t, handler.Pin(clusterName, "../../etc/passwd"), ErrUnknownVersion)
+	require.NoError(t, handler.Pin(clusterName, pinned))
+
+	// The pinned version is kept beyond the versions to keep
+	require.NoError(t, handler.Write([]byte(`{"v":2}`), clusterName))
+	versions, err = handler.Versions(clusterName)
+	require.NoError(t, err)
+	require.Len(t, versions, 2)
+	assert.False(t, versions[0].Pinned)
+	assert.Equal(t, SchemaVersion{Version: pinned, Timestamp: versions[1].Timestamp, Pinned: true}, versions[1])
+
+	version, ok := handler.PinnedVersion(clusterName)
+	assert.True(t, ok)
+	assert.Equal(t, pinned, version)
+
+	require.NoError(t, handler.Unpin(clusterName))
+	_, ok = handler.PinnedVersion(clusterName)
+	assert.False(t, ok)
+
+	require.NoError(t, handler.Delete(clusterName))
+	versions, err = handler.Versions(clusterName)
+	require.NoError(t, err)
+	assert.Empty(t, versions)
+}
+
+func TestVersions_Disabled(t *testing.T) {
+	handler, err := NewIOHandler(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, handler.Write(testJSON, "root"))
+	_, err = handler.Versions("root")
+	assert.ErrorIs(t, err, ErrVersioningDisabled)
+	assert.ErrorIs(t, handler.Pin("root", "20251001T120000.000000000Z"), ErrVersioningDisabled)
+}
@@ -21,17 +21,25 @@ func BuildTargetClusterConfigFromTyped(ctx context.Context, clusterAccess v1alph
 		return nil, "", errors.New("host field not found in ClusterAccess spec")
 	}
 
-	// Extract cluster name (path field or resource name)
-	clusterName := clusterAccess.GetName()
-	if spec.Path != "" {
-		clusterName = spec.Path
-	}
+	clusterName := ClusterName(clusterAccess)
 
 	// Use common auth package to build config
 	config, err := auth.BuildConfig(ctx, host, spec.Auth, spec.CA, k8sClient)
 	if err != nil {
 		return nil, "", err
 	}
+	if err := auth.ConfigureProxyFromSpec(ctx, config, spec.Proxy, k8sClient); err != nil {
+		return nil, "", err
+	}
 
 	return config, clusterName, nil
 }
+
+// ClusterName returns the name the schema of the cluster is stored under, which is the path or the resource name
+func ClusterName(clusterAccess v1alpha1.ClusterAccess) string {
+	if clusterAccess.Spec.Path != "" {
+		return clusterAccess.Spec.Path
+	}
+
+	return clusterAccess.GetName()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/openmfp/golang-commons/logger"
 	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
@@ -13,3 +14,11 @@ import (
 func InjectClusterMetadata(ctx context.Context, schemaJSON []byte, clusterAccess gatewayv1alpha1.ClusterAccess, k8sClient client.Client, log *logger.Logger) ([]byte, error) {
 	return injectClusterMetadata(ctx, schemaJSON, clusterAccess, k8sClient, log)
 }
+
+func RequestsForReferencedSecret(ctx context.Context, clt client.Reader, obj client.Object) ([]reconcile.Request, error) {
+	return requestsForReferencing(ctx, clt, referencedSecrets, obj)
+}
+
+func RequestsForReferencedConfigMap(ctx context.Context, clt client.Reader, obj client.Object) ([]reconcile.Request, error) {
+	return requestsForReferencing(ctx, clt, referencedConfigMaps, obj)
+}
@@ -11,18 +11,21 @@ import (
 )
 
 func injectClusterMetadata(ctx context.Context, schemaJSON []byte, clusterAccess gatewayv1alpha1.ClusterAccess, k8sClient client.Client, log *logger.Logger) ([]byte, error) {
-	// Determine the path
-	path := clusterAccess.Spec.Path
-	if path == "" {
-		path = clusterAccess.GetName()
-	}
+	path := ClusterName(clusterAccess)
 
 	// Create metadata injection config
 	config := auth.MetadataInjectionConfig{
-		Host: clusterAccess.Spec.Host,
-		Path: path,
-		Auth: clusterAccess.Spec.Auth,
-		CA:   clusterAccess.Spec.CA,
+		Host:     clusterAccess.Spec.Host,
+		Path:     path,
+		Auth:     clusterAccess.Spec.Auth,
+		CA:       clusterAccess.Spec.CA,
+		Defaults: clusterAccess.Spec.Defaults,
+		AuthMode: clusterAccess.Spec.AuthMode,
+		Labels:   clusterAccess.Labels,
+		Proxy:    clusterAccess.Spec.Proxy,
+		Features: clusterAccess.Spec.Features,
+
+		ClaimMappings: clusterAccess.Spec.ClaimMappings,
 	}
 
 	// Use the common metadata injection function
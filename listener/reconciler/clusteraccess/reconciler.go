@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/openmfp/golang-commons/controller/lifecycle"
 	"github.com/openmfp/golang-commons/logger"
@@ -58,7 +64,7 @@ func NewClusterAccessReconciler(
 	}
 
 	log.Info().Msg("ClusterAccess CRD registered, creating ClusterAccess reconciler")
-	return NewReconciler(opts, ioHandler, schemaResolver, log)
+	return NewReconciler(appCfg, opts, ioHandler, schemaResolver, log)
 }
 
 // CheckClusterAccessCRDStatus checks the availability and usage of ClusterAccess CRD
@@ -88,9 +94,14 @@ type ClusterAccessReconciler struct {
 	mgr              ctrl.Manager
 	opts             reconciler.ReconcilerOpts
 	lifecycleManager *lifecycle.LifecycleManager
+	// resyncPeriod is how often the schemas are generated again to pick up API changes of the clusters, 0 disables it
+	resyncPeriod time.Duration
+	// resyncEvents enqueues the ClusterAccesses again on Resync
+	resyncEvents chan event.GenericEvent
 }
 
 func NewReconciler(
+	appCfg config.Config,
 	opts reconciler.ReconcilerOpts,
 	ioHandler workspacefile.IOHandler,
 	schemaResolver apischema.Resolver,
@@ -109,6 +120,8 @@ func NewReconciler(
 		ioHandler:      ioHandler,
 		schemaResolver: schemaResolver,
 		log:            log,
+		resyncPeriod:   appCfg.Listener.ClusterAccessResyncPeriod,
+		resyncEvents:   make(chan event.GenericEvent),
 	}
 
 	// Create lifecycle manager with subroutines and condition management
@@ -129,12 +142,40 @@ func (r *ClusterAccessReconciler) GetManager() ctrl.Manager {
 	return r.mgr
 }
 
+// Reconcile generates the schema of a ClusterAccess and reports the outcome in its status.
+// Successfully reconciled clusters are reconciled again after the resync period, as API changes of the target
+// clusters, e.g. installed CRDs, don't change the ClusterAccess. Clusters using service account tokens may be
+// reconciled earlier to refresh the token.
 func (r *ClusterAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	return r.lifecycleManager.Reconcile(ctx, req, &gatewayv1alpha1.ClusterAccess{})
+	result, err := r.lifecycleManager.Reconcile(ctx, req, &gatewayv1alpha1.ClusterAccess{})
+	if err != nil || result.Requeue || r.resyncPeriod <= 0 {
+		return result, err
+	}
+
+	if result.RequeueAfter > 0 && result.RequeueAfter <= r.resyncPeriod {
+		return result, nil
+	}
+
+	// Deleted ClusterAccesses are not reconciled again, the lookup is served from the cache of the manager
+	if err := r.mgr.GetClient().Get(ctx, req.NamespacedName, &gatewayv1alpha1.ClusterAccess{}); err != nil {
+		return result, client.IgnoreNotFound(err)
+	}
+
+	return ctrl.Result{RequeueAfter: r.resyncPeriod}, nil
 }
 
+// SetupWithManager also watches the Secrets and ConfigMaps referenced by ClusterAccesses, so that rotated tokens and
+// CAs are picked up without a restart. Only their metadata is cached, the data is read when the schema is generated.
 func (r *ClusterAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&gatewayv1alpha1.ClusterAccess{}).
+		Watches(&corev1.Secret{}, r.enqueueReferencing(referencedSecrets), builder.OnlyMetadata).
+		Watches(&corev1.ConfigMap{}, r.enqueueReferencing(referencedConfigMaps), builder.OnlyMetadata).
+		WatchesRawSource(source.Channel(r.resyncEvents, &handler.EnqueueRequestForObject{})).
 		Complete(r)
 }
+
+// Resync generates the schemas of all ClusterAccesses again
+func (r *ClusterAccessReconciler) Resync(ctx context.Context) error {
+	return reconciler.EnqueueAll(ctx, r.mgr.GetClient(), &gatewayv1alpha1.ClusterAccessList{}, r.resyncEvents)
+}
@@ -0,0 +1,117 @@
+package clusteraccess
+
+import (
+	"context"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+)
+
+// referenceKey returns the key of a referenced object, references without a namespace point to the default namespace
+func referenceKey(name, namespace string) types.NamespacedName {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return types.NamespacedName{Name: name, Namespace: namespace}
+}
+
+// objectReference is a Secret or ConfigMap referenced by a field of a ClusterAccess
+type objectReference struct {
+	field     *field.Path
+	configMap bool
+	key       types.NamespacedName
+}
+
+// objectReferences returns the Secrets and ConfigMaps the CA and credentials of a ClusterAccess and its proxy are read
+// from
+func objectReferences(clusterAccess gatewayv1alpha1.ClusterAccess) []objectReference {
+	spec := field.NewPath("spec")
+	var refs []objectReference
+	if ca := clusterAccess.Spec.CA; ca != nil {
+		if ca.SecretRef != nil {
+			refs = append(refs, objectReference{field: spec.Child("ca", "secretRef"), key: referenceKey(ca.SecretRef.Name, ca.SecretRef.Namespace)})
+		}
+		if ca.ConfigMapRef != nil {
+			refs = append(refs, objectReference{field: spec.Child("ca", "configMapRef"), configMap: true, key: referenceKey(ca.ConfigMapRef.Name, ca.ConfigMapRef.Namespace)})
+		}
+	}
+	if auth := clusterAccess.Spec.Auth; auth != nil {
+		if auth.SecretRef != nil {
+			refs = append(refs, objectReference{field: spec.Child("auth", "secretRef"), key: referenceKey(auth.SecretRef.Name, auth.SecretRef.Namespace)})
+		}
+		if auth.KubeconfigSecretRef != nil {
+			refs = append(refs, objectReference{field: spec.Child("auth", "kubeconfigSecretRef"), key: referenceKey(auth.KubeconfigSecretRef.Name, auth.KubeconfigSecretRef.Namespace)})
+		}
+		if auth.ClientCertificateRef != nil {
+			refs = append(refs, objectReference{field: spec.Child("auth", "clientCertificateRef"), key: referenceKey(auth.ClientCertificateRef.Name, auth.ClientCertificateRef.Namespace)})
+		}
+	}
+	if proxy := clusterAccess.Spec.Proxy; proxy != nil && proxy.ClientCertificateRef != nil {
+		refs = append(refs, objectReference{field: spec.Child("proxy", "clientCertificateRef"), key: referenceKey(proxy.ClientCertificateRef.Name, proxy.ClientCertificateRef.Namespace)})
+	}
+	return refs
+}
+
+// referencedSecrets returns the Secrets the CA and credentials of a ClusterAccess and its proxy are read from
+func referencedSecrets(clusterAccess gatewayv1alpha1.ClusterAccess) []types.NamespacedName {
+	return referencedKeys(clusterAccess, false)
+}
+
+// referencedConfigMaps returns the ConfigMaps the CA of a ClusterAccess is read from
+func referencedConfigMaps(clusterAccess gatewayv1alpha1.ClusterAccess) []types.NamespacedName {
+	return referencedKeys(clusterAccess, true)
+}
+
+func referencedKeys(clusterAccess gatewayv1alpha1.ClusterAccess, configMaps bool) []types.NamespacedName {
+	var keys []types.NamespacedName
+	for _, ref := range objectReferences(clusterAccess) {
+		if ref.configMap == configMaps {
+			keys = append(keys, ref.key)
+		}
+	}
+	return keys
+}
+
+// requestsForReferencing returns the requests of the ClusterAccesses referencing obj, so that their schemas are
+// generated again with the rotated credentials. The gateway reloads a cluster as soon as its schema file changes.
+func requestsForReferencing(
+	ctx context.Context,
+	clt client.Reader,
+	references func(gatewayv1alpha1.ClusterAccess) []types.NamespacedName,
+	obj client.Object,
+) ([]reconcile.Request, error) {
+	clusterAccesses := &gatewayv1alpha1.ClusterAccessList{}
+	if err := clt.List(ctx, clusterAccesses); err != nil {
+		return nil, err
+	}
+
+	key := client.ObjectKeyFromObject(obj)
+	var requests []reconcile.Request
+	for _, clusterAccess := range clusterAccesses.Items {
+		if slices.Contains(references(clusterAccess), key) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: clusterAccess.GetName()}})
+		}
+	}
+	return requests, nil
+}
+
+// enqueueReferencing enqueues the ClusterAccesses referencing a changed Secret or ConfigMap
+func (r *ClusterAccessReconciler) enqueueReferencing(references func(gatewayv1alpha1.ClusterAccess) []types.NamespacedName) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		requests, err := requestsForReferencing(ctx, r.mgr.GetClient(), references, obj)
+		if err != nil {
+			r.log.Error().Err(err).Str("namespace", obj.GetNamespace()).Str("name", obj.GetName()).Msg("failed to list ClusterAccesses referencing changed object")
+			return nil
+		}
+		for _, request := range requests {
+			r.log.Info().Str("clusterAccess", request.Name).Str("namespace", obj.GetNamespace()).Str("name", obj.GetName()).Msg("referenced object changed, generating schema again")
+		}
+		return requests
+	})
+}
@@ -0,0 +1,98 @@
+package clusteraccess_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/clusteraccess"
+)
+
+func TestRequestsForReferencedObjects(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayv1alpha1.ClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{Name: "token"},
+			Spec: gatewayv1alpha1.ClusterAccessSpec{
+				Auth: &gatewayv1alpha1.AuthConfig{SecretRef: &gatewayv1alpha1.SecretRef{Name: "token", Namespace: "clusters", Key: "token"}},
+				CA:   &gatewayv1alpha1.CAConfig{ConfigMapRef: &gatewayv1alpha1.ConfigMapRef{Name: "ca", Key: "ca.crt"}},
+			},
+		},
+		&gatewayv1alpha1.ClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{Name: "kubeconfig"},
+			Spec: gatewayv1alpha1.ClusterAccessSpec{
+				Auth: &gatewayv1alpha1.AuthConfig{KubeconfigSecretRef: &gatewayv1alpha1.KubeconfigSecretRef{Name: "kubeconfig", Namespace: "clusters"}},
+				CA:   &gatewayv1alpha1.CAConfig{SecretRef: &gatewayv1alpha1.SecretRef{Name: "token", Namespace: "clusters", Key: "ca.crt"}},
+			},
+		},
+		&gatewayv1alpha1.ClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{Name: "service-account"},
+			Spec: gatewayv1alpha1.ClusterAccessSpec{
+				Auth: &gatewayv1alpha1.AuthConfig{ServiceAccount: &gatewayv1alpha1.ServiceAccountRef{Name: "gateway", Namespace: "clusters"}},
+			},
+		},
+	).Build()
+
+	object := func(name, namespace string) client.Object {
+		return &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	}
+	request := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name}}
+	}
+
+	tests := []struct {
+		name      string
+		configMap bool
+		object    client.Object
+		expected  []reconcile.Request
+	}{
+		{
+			name:     "secret_referenced_twice",
+			object:   object("token", "clusters"),
+			expected: []reconcile.Request{request("kubeconfig"), request("token")},
+		},
+		{
+			name:     "kubeconfig_secret",
+			object:   object("kubeconfig", "clusters"),
+			expected: []reconcile.Request{request("kubeconfig")},
+		},
+		{
+			name:   "secret_in_other_namespace",
+			object: object("token", "default"),
+		},
+		{
+			name:      "configmap_in_default_namespace",
+			configMap: true,
+			object:    object("ca", "default"),
+			expected:  []reconcile.Request{request("token")},
+		},
+		{
+			name:      "configmap_with_name_of_secret",
+			configMap: true,
+			object:    object("token", "clusters"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestsFor := clusteraccess.RequestsForReferencedSecret
+			if tt.configMap {
+				requestsFor = clusteraccess.RequestsForReferencedConfigMap
+			}
+
+			requests, err := requestsFor(t.Context(), clt, tt.object)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, requests)
+		})
+	}
+}
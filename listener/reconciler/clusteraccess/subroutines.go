@@ -1,10 +1,16 @@
 package clusteraccess
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"fmt"
+	"io/fs"
+	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -13,10 +19,26 @@ import (
 	"github.com/openmfp/golang-commons/controller/lifecycle"
 	commonserrors "github.com/openmfp/golang-commons/errors"
 	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler"
 )
 
+// SchemaFinalizer keeps a ClusterAccess until its schema is removed
+const SchemaFinalizer = "gateway.openmfp.org/schema"
+
+// Reasons of the SchemaGenerated and AuthError conditions
+const (
+	reasonGenerated           = "Generated"
+	reasonGenerationFailed    = "GenerationFailed"
+	reasonStorageFailed       = "StorageFailed"
+	reasonAuthError           = "AuthError"
+	reasonAuthenticated       = "Authenticated"
+	reasonInvalidAuthConfig   = "InvalidAuthConfig"
+	reasonCredentialsRejected = "CredentialsRejected"
+)
+
 // generateSchemaSubroutine processes ClusterAccess resources and generates schemas
 type generateSchemaSubroutine struct {
 	reconciler *ClusterAccessReconciler
@@ -36,7 +58,10 @@ func (s *generateSchemaSubroutine) Process(ctx context.Context, instance lifecyc
 	targetConfig, clusterName, err := BuildTargetClusterConfigFromTyped(ctx, *clusterAccess, s.reconciler.opts.Client)
 	if err != nil {
 		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to build target cluster config")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+		schemametrics.RecordFailure(ClusterName(*clusterAccess), schemametrics.StageConnection)
+		setAuthErrorCondition(clusterAccess, reasonInvalidAuthConfig, err)
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonAuthError, "The cluster config could not be built")
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
 	}
 
 	s.reconciler.log.Info().Str("clusterAccess", clusterAccessName).Str("host", targetConfig.Host).Str("clusterName", clusterName).Msg("extracted target cluster config")
@@ -45,40 +70,84 @@ func (s *generateSchemaSubroutine) Process(ctx context.Context, instance lifecyc
 	targetDiscovery, err := discovery.NewDiscoveryClientForConfig(targetConfig)
 	if err != nil {
 		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to create discovery client")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+		schemametrics.RecordFailure(clusterName, schemametrics.StageConnection)
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonGenerationFailed, err.Error())
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
 	}
 
 	// Create REST mapper for target cluster
 	targetRM, err := s.restMapperFromConfig(targetConfig)
 	if err != nil {
 		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to create REST mapper")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+		schemametrics.RecordFailure(clusterName, schemametrics.StageConnection)
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonGenerationFailed, err.Error())
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
 	}
 
 	// Create schema resolver for target cluster
 	targetResolver := apischema.NewCRDResolver(targetDiscovery, targetRM, s.reconciler.log)
 
 	// Generate schema for target cluster
+	resolutionStart := time.Now()
 	JSON, err := targetResolver.Resolve(targetDiscovery, targetRM)
+	schemametrics.ObserveResolution(clusterName, resolutionStart)
 	if err != nil {
 		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to resolve schema")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+		schemametrics.RecordFailure(clusterName, schemametrics.StageResolution)
+		if apierrors.IsUnauthorized(err) || apierrors.IsForbidden(err) {
+			setAuthErrorCondition(clusterAccess, reasonCredentialsRejected, err)
+		}
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonGenerationFailed, err.Error())
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
 	}
 
+	// The API server accepted the credentials, as the schema could be resolved
+	meta.SetStatusCondition(&clusterAccess.Status.Conditions, metav1.Condition{
+		Type:               gatewayv1alpha1.ConditionAuthError,
+		Status:             metav1.ConditionFalse,
+		Reason:             reasonAuthenticated,
+		Message:            "The cluster accepted the credentials",
+		ObservedGeneration: clusterAccess.GetGeneration(),
+	})
+
 	// Create the complete schema file with x-cluster-metadata
 	schemaWithMetadata, err := injectClusterMetadata(ctx, JSON, *clusterAccess, s.reconciler.opts.Client, s.reconciler.log)
 	if err != nil {
 		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to inject cluster metadata")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+		schemametrics.RecordFailure(clusterName, schemametrics.StageMetadata)
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonGenerationFailed, err.Error())
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
+	}
+
+	// Write the schema only if it changed, as every write makes the gateway reload the cluster
+	savedSchema, err := s.reconciler.ioHandler.Read(clusterName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to read existing schema")
+		schemametrics.RecordFailure(clusterName, schemametrics.StageStorage)
+		setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonStorageFailed, err.Error())
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
 	}
 
-	// Write schema to file using cluster name from path or resource name
-	if err := s.reconciler.ioHandler.Write(schemaWithMetadata, clusterName); err != nil {
-		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to write schema")
-		return ctrl.Result{}, commonserrors.NewOperatorError(err, false, false)
+	if errors.Is(err, fs.ErrNotExist) || !bytes.Equal(schemaWithMetadata, savedSchema) {
+		if err := s.reconciler.ioHandler.Write(schemaWithMetadata, clusterName); err != nil {
+			s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccessName).Msg("failed to write schema")
+			schemametrics.RecordFailure(clusterName, schemametrics.StageStorage)
+			setSchemaGeneratedCondition(clusterAccess, metav1.ConditionFalse, reasonStorageFailed, err.Error())
+			return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
+		}
+		s.reconciler.log.Info().Str("clusterAccess", clusterAccessName).Str("clusterName", clusterName).Msg("schema updated")
 	}
+	schemametrics.RecordSuccess(clusterName, schemaWithMetadata)
+
+	setSchemaGeneratedCondition(clusterAccess, metav1.ConditionTrue, reasonGenerated, fmt.Sprintf("The schema is stored as %s", clusterName))
 
 	s.reconciler.log.Info().Str("clusterAccess", clusterAccessName).Msg("successfully processed ClusterAccess resource")
+
+	// Service account tokens expire, so the schema is generated again with a fresh token before that happens
+	if refreshAt, ok := auth.ServiceAccountTokenRefreshAt(clusterAccess.Spec.Auth); ok {
+		return ctrl.Result{RequeueAfter: max(time.Until(refreshAt), time.Second)}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -96,7 +165,21 @@ func (s *generateSchemaSubroutine) restMapperFromConfig(cfg *rest.Config) (meta.
 	return rm, nil
 }
 
+// Finalize removes the schema of a deleted ClusterAccess, so that the gateway stops serving the cluster
 func (s *generateSchemaSubroutine) Finalize(ctx context.Context, instance lifecycle.RuntimeObject) (ctrl.Result, commonserrors.OperatorError) {
+	clusterAccess, ok := instance.(*gatewayv1alpha1.ClusterAccess)
+	if !ok {
+		return ctrl.Result{}, commonserrors.NewOperatorError(errors.New("invalid resource type"), false, false)
+	}
+
+	clusterName := ClusterName(*clusterAccess)
+	if err := s.reconciler.ioHandler.Delete(clusterName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		s.reconciler.log.Error().Err(err).Str("clusterAccess", clusterAccess.GetName()).Msg("failed to delete schema")
+		return ctrl.Result{}, commonserrors.NewOperatorError(err, true, false)
+	}
+	schemametrics.Forget(clusterName)
+
+	s.reconciler.log.Info().Str("clusterAccess", clusterAccess.GetName()).Str("clusterName", clusterName).Msg("deleted schema of removed ClusterAccess")
 	return ctrl.Result{}, nil
 }
 
@@ -105,5 +188,27 @@ func (s *generateSchemaSubroutine) GetName() string {
 }
 
 func (s *generateSchemaSubroutine) Finalizers() []string {
-	return nil
+	return []string{SchemaFinalizer}
+}
+
+// setSchemaGeneratedCondition reports the outcome of the schema generation
+func setSchemaGeneratedCondition(clusterAccess *gatewayv1alpha1.ClusterAccess, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&clusterAccess.Status.Conditions, metav1.Condition{
+		Type:               gatewayv1alpha1.ConditionSchemaGenerated,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: clusterAccess.GetGeneration(),
+	})
+}
+
+// setAuthErrorCondition reports that the listener could not authenticate against the cluster
+func setAuthErrorCondition(clusterAccess *gatewayv1alpha1.ClusterAccess, reason string, err error) {
+	meta.SetStatusCondition(&clusterAccess.Status.Conditions, metav1.Condition{
+		Type:               gatewayv1alpha1.ConditionAuthError,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: clusterAccess.GetGeneration(),
+	})
 }
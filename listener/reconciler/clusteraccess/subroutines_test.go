@@ -0,0 +1,132 @@
+package clusteraccess_test
+
+import (
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
+	apischema_mocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema/mocks"
+	workspacefile_mocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/clusteraccess"
+)
+
+func newTestReconciler(t *testing.T, ioHandler *workspacefile_mocks.MockIOHandler) *clusteraccess.ClusterAccessReconciler {
+	opts := reconciler.ReconcilerOpts{
+		Config: &rest.Config{Host: "https://test-api-server.com"},
+		Scheme: runtime.NewScheme(),
+		Client: fake.NewClientBuilder().Build(),
+		ManagerOpts: ctrl.Options{
+			Scheme:  runtime.NewScheme(),
+			Metrics: server.Options{BindAddress: "0"},
+		},
+	}
+
+	r, err := clusteraccess.NewReconciler(config.Config{}, opts, ioHandler, &apischema_mocks.MockResolver{}, testlogger.New().HideLogOutput().Logger)
+	require.NoError(t, err)
+
+	return r.(*clusteraccess.ClusterAccessReconciler)
+}
+
+func TestGenerateSchemaSubroutine_Process_Conditions(t *testing.T) {
+	unauthorizedServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"kind":"Status","apiVersion":"v1","status":"Failure","reason":"Unauthorized","code":401}`))
+	}))
+	defer unauthorizedServer.Close()
+
+	tests := []struct {
+		name         string
+		spec         gatewayv1alpha1.ClusterAccessSpec
+		expectReason string
+	}{
+		{
+			name: "missing_token_secret",
+			spec: gatewayv1alpha1.ClusterAccessSpec{
+				Host: "https://test-cluster.example.com",
+				Auth: &gatewayv1alpha1.AuthConfig{
+					SecretRef: &gatewayv1alpha1.SecretRef{Name: "missing", Namespace: "default", Key: "token"},
+				},
+			},
+			expectReason: "InvalidAuthConfig",
+		},
+		{
+			name:         "rejected_credentials",
+			spec:         gatewayv1alpha1.ClusterAccessSpec{Host: unauthorizedServer.URL},
+			expectReason: "CredentialsRejected",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subroutine := clusteraccess.NewGenerateSchemaSubroutineForTesting(newTestReconciler(t, workspacefile_mocks.NewMockIOHandler(t)))
+			clusterAccess := &gatewayv1alpha1.ClusterAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Generation: 2},
+				Spec:       tt.spec,
+			}
+
+			_, err := subroutine.Process(t.Context(), clusterAccess)
+			require.NotNil(t, err)
+			assert.True(t, err.Retry())
+
+			authError := meta.FindStatusCondition(clusterAccess.Status.Conditions, gatewayv1alpha1.ConditionAuthError)
+			require.NotNil(t, authError)
+			assert.Equal(t, metav1.ConditionTrue, authError.Status)
+			assert.Equal(t, tt.expectReason, authError.Reason)
+			assert.Equal(t, int64(2), authError.ObservedGeneration)
+
+			schemaGenerated := meta.FindStatusCondition(clusterAccess.Status.Conditions, gatewayv1alpha1.ConditionSchemaGenerated)
+			require.NotNil(t, schemaGenerated)
+			assert.Equal(t, metav1.ConditionFalse, schemaGenerated.Status)
+		})
+	}
+}
+
+func TestGenerateSchemaSubroutine_Finalize(t *testing.T) {
+	tests := []struct {
+		name        string
+		deleteErr   error
+		expectError bool
+	}{
+		{name: "schema_deleted"},
+		{name: "schema_already_missing", deleteErr: fs.ErrNotExist},
+		{name: "delete_failed_ERROR", deleteErr: errors.New("storage unavailable"), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ioHandler := workspacefile_mocks.NewMockIOHandler(t)
+			ioHandler.EXPECT().Delete("custom-path").Return(tt.deleteErr).Once()
+
+			subroutine := clusteraccess.NewGenerateSchemaSubroutineForTesting(newTestReconciler(t, ioHandler))
+			assert.Equal(t, []string{clusteraccess.SchemaFinalizer}, subroutine.Finalizers())
+
+			_, err := subroutine.Finalize(t.Context(), &gatewayv1alpha1.ClusterAccess{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster"},
+				Spec:       gatewayv1alpha1.ClusterAccessSpec{Path: "custom-path"},
+			})
+			if tt.expectError {
+				require.NotNil(t, err)
+				assert.True(t, err.Retry())
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package clusteraccess
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
+)
+
+// +kubebuilder:webhook:path=/validate-gateway-openmfp-org-v1alpha1-clusteraccess,mutating=false,failurePolicy=fail,sideEffects=None,groups=gateway.openmfp.org,resources=clusteraccesses,verbs=create;update,versions=v1alpha1,name=vclusteraccess.gateway.openmfp.org,admissionReviewVersions=v1
+
+// Validator is the validating admission webhook of ClusterAccesses. The CRD validates the host, path and auth of a
+// single ClusterAccess, the webhook rejects those whose schema file would replace the one of another ClusterAccess,
+// those referencing Secrets or ConfigMaps that don't exist, which the listener would only report in their conditions,
+// and those whose credential plugin runs a command that isn't allowed.
+type Validator struct {
+	// clusterAccesses lists the other ClusterAccesses, references reads the metadata of the referenced objects
+	clusterAccesses client.Reader
+	references      client.Reader
+}
+
+var _ admission.CustomValidator = &Validator{}
+
+// NewValidator lists the ClusterAccesses from the cache of the manager and reads the references with its API reader, so
+// that the Secrets don't have to be cached
+func NewValidator(clusterAccesses, references client.Reader) *Validator {
+	return &Validator{clusterAccesses: clusterAccesses, references: references}
+}
+
+// SetupWebhookWithManager serves the webhook with the webhook server of the manager, on every replica
+func (v *Validator) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&gatewayv1alpha1.ClusterAccess{}).
+		WithValidator(v).
+		Complete()
+}
+
+func (v *Validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, obj)
+}
+
+func (v *Validator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return v.validate(ctx, newObj)
+}
+
+func (v *Validator) ValidateDelete(context.Context, runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *Validator) validate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	clusterAccess, ok := obj.(*gatewayv1alpha1.ClusterAccess)
+	if !ok {
+		return nil, fmt.Errorf("expected a ClusterAccess, got %T", obj)
+	}
+
+	var warnings admission.Warnings
+	var errs field.ErrorList
+
+	conflict, err := v.pathConflict(ctx, *clusterAccess)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ClusterAccesses: %w", err)
+	}
+	if conflict != "" {
+		pathField, path := field.NewPath("spec", "path"), clusterAccess.Spec.Path
+		if path == "" {
+			pathField, path = field.NewPath("metadata", "name"), clusterAccess.GetName()
+		}
+		errs = append(errs, field.Invalid(pathField, path, fmt.Sprintf("the schema of ClusterAccess %s is stored under the same path", conflict)))
+	}
+
+	for _, ref := range objectReferences(*clusterAccess) {
+		kind := "Secret"
+		if ref.configMap {
+			kind = "ConfigMap"
+		}
+
+		object := &metav1.PartialObjectMetadata{TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: kind}}
+		err := v.references.Get(ctx, ref.key, object)
+		switch {
+		case apierrors.IsNotFound(err):
+			errs = append(errs, field.NotFound(ref.field.Child("name"), fmt.Sprintf("%s %s", kind, ref.key)))
+		case err != nil:
+			// The listener reports the reference in the conditions if it is missing after all
+			warnings = append(warnings, fmt.Sprintf("%s: failed to check %s %s: %v", ref.field, kind, ref.key, err))
+		}
+	}
+
+	if err := execCommandError(clusterAccess.Spec.Auth); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return warnings, apierrors.NewInvalid(gatewayv1alpha1.GroupVersion.WithKind("ClusterAccess").GroupKind(), clusterAccess.GetName(), errs)
+	}
+	return warnings, nil
+}
+
+// pathConflict returns the name of another ClusterAccess whose schema is stored under the same path
+func (v *Validator) pathConflict(ctx context.Context, clusterAccess gatewayv1alpha1.ClusterAccess) (string, error) {
+	clusterAccesses := &gatewayv1alpha1.ClusterAccessList{}
+	if err := v.clusterAccesses.List(ctx, clusterAccesses); err != nil {
+		return "", err
+	}
+
+	path := ClusterName(clusterAccess)
+	for _, other := range clusterAccesses.Items {
+		if other.GetName() != clusterAccess.GetName() && ClusterName(other) == path {
+			return other.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// execCommandError returns an error if the exec or cloud auth runs a command that isn't allowed, see
+// auth.CheckExecCommand
+func execCommandError(authConfig *gatewayv1alpha1.AuthConfig) *field.Error {
+	if _, err := auth.ExecConfigFor(authConfig); !errors.Is(err, auth.ErrExecCommandNotAllowed) {
+		return nil
+	}
+
+	authField := field.NewPath("spec", "auth")
+	if authConfig.Exec != nil {
+		return field.NotSupported(authField.Child("exec", "command"), authConfig.Exec.Command, auth.ExecCommands())
+	}
+	return field.Forbidden(authField.Child("cloud", "provider"), fmt.Sprintf("the credential plugin of %s is not allowed", authConfig.Cloud.Provider))
+}
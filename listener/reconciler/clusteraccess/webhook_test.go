@@ -0,0 +1,107 @@
+package clusteraccess_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	gatewayv1alpha1 "github.com/openmfp/kubernetes-graphql-gateway/common/apis/v1alpha1"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/clusteraccess"
+)
+
+func TestValidator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayv1alpha1.ClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{Name: "existing"},
+			Spec:       gatewayv1alpha1.ClusterAccessSpec{Path: "shared", Host: "https://existing.example.com"},
+		},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "clusters"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "ca", Namespace: "default"}},
+	).Build()
+	validator := clusteraccess.NewValidator(clt, clt)
+
+	clusterAccess := func(name, path string, auth *gatewayv1alpha1.AuthConfig, ca *gatewayv1alpha1.CAConfig) *gatewayv1alpha1.ClusterAccess {
+		return &gatewayv1alpha1.ClusterAccess{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       gatewayv1alpha1.ClusterAccessSpec{Path: path, Host: "https://cluster.example.com", Auth: auth, CA: ca},
+		}
+	}
+	tokenAuth := func(name string) *gatewayv1alpha1.AuthConfig {
+		return &gatewayv1alpha1.AuthConfig{SecretRef: &gatewayv1alpha1.SecretRef{Name: name, Namespace: "clusters", Key: "token"}}
+	}
+	configMapCA := func(name string) *gatewayv1alpha1.CAConfig {
+		return &gatewayv1alpha1.CAConfig{ConfigMapRef: &gatewayv1alpha1.ConfigMapRef{Name: name, Key: "ca.crt"}}
+	}
+
+	tests := []struct {
+		name          string
+		clusterAccess *gatewayv1alpha1.ClusterAccess
+		expectedError []string
+	}{
+		{
+			name:          "valid",
+			clusterAccess: clusterAccess("new", "", tokenAuth("token"), configMapCA("ca")),
+		},
+		{
+			name:          "update_of_itself",
+			clusterAccess: clusterAccess("existing", "shared", nil, nil),
+		},
+		{
+			name:          "path_conflict",
+			clusterAccess: clusterAccess("new", "shared", nil, nil),
+			expectedError: []string{`spec.path: Invalid value: "shared": the schema of ClusterAccess existing is stored under the same path`},
+		},
+		{
+			name:          "name_conflicts_with_path",
+			clusterAccess: clusterAccess("shared", "", nil, nil),
+			expectedError: []string{`metadata.name: Invalid value: "shared"`},
+		},
+		{
+			name:          "missing_references",
+			clusterAccess: clusterAccess("new", "", tokenAuth("other"), configMapCA("other")),
+			expectedError: []string{
+				`spec.auth.secretRef.name: Not found: "Secret clusters/other"`,
+				`spec.ca.configMapRef.name: Not found: "ConfigMap default/other"`,
+			},
+		},
+		{
+			name: "exec_command_not_allowed",
+			clusterAccess: clusterAccess("new", "", &gatewayv1alpha1.AuthConfig{
+				Exec: &gatewayv1alpha1.ExecConfig{Command: "sh", Args: []string{"-c", "id"}},
+			}, nil),
+			expectedError: []string{`spec.auth.exec.command: Unsupported value: "sh"`},
+		},
+		{
+			name: "exec_command_allowed",
+			clusterAccess: clusterAccess("new", "", &gatewayv1alpha1.AuthConfig{
+				Exec: &gatewayv1alpha1.ExecConfig{Command: "kubelogin", Args: []string{"get-token"}},
+			}, nil),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validator.ValidateCreate(context.Background(), tt.clusterAccess)
+			if len(tt.expectedError) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.True(t, apierrors.IsInvalid(err), "expected an invalid error, got %v", err)
+			for _, expected := range tt.expectedError {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/openmfp/golang-commons/logger"
 
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
 )
 
@@ -30,7 +31,9 @@ type APIBindingReconciler struct {
 	DiscoveryFactory    DiscoveryFactory
 	APISchemaResolver   apischema.Resolver
 	ClusterPathResolver ClusterPathResolver
-	Log                 *logger.Logger
+	// PathCache is optional, without it the path of the cluster is resolved on every reconcile
+	PathCache *ClusterPathCache
+	Log       *logger.Logger
 }
 
 func (r *APIBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -41,38 +44,48 @@ func (r *APIBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	logger := r.Log.With().Str("cluster", req.ClusterName).Str("name", req.Name).Logger()
 
-	clusterClt, err := r.ClusterPathResolver.ClientForCluster(req.ClusterName)
-	if err != nil {
-		logger.Error().Err(err).Msg("failed to get cluster client")
-		return ctrl.Result{}, err
-	}
-
-	clusterPath, err := PathForCluster(req.ClusterName, clusterClt)
+	clusterPath, stalePath, err := r.resolveClusterPath(req.ClusterName)
 	if err != nil {
 		if errors.Is(err, ErrClusterIsDeleted) {
-			logger.Info().Msg("cluster is deleted, triggering cleanup")
-			if err = r.IOHandler.Delete(clusterPath); err != nil {
+			if clusterPath == "" {
+				logger.Info().Msg("cluster is deleted, its path is unknown so the schema is left to the garbage collection")
+				return ctrl.Result{}, nil
+			}
+			logger.Info().Str("clusterPath", clusterPath).Msg("cluster is deleted, triggering cleanup")
+			if err = r.IOHandler.Delete(clusterPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
 				logger.Error().Err(err).Msg("failed to delete workspace file after cluster deletion")
 				return ctrl.Result{}, err
 			}
+			schemametrics.Forget(clusterPath)
 			return ctrl.Result{}, nil
 		}
 		logger.Error().Err(err).Msg("failed to get cluster path")
 		return ctrl.Result{}, err
 	}
 
+	if stalePath != "" {
+		logger.Info().Str("stalePath", stalePath).Str("clusterPath", clusterPath).Msg("cluster path changed, removing stale schema file")
+		if err := r.IOHandler.Delete(stalePath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			logger.Error().Err(err).Msg("failed to delete schema file of stale cluster path")
+			return ctrl.Result{}, err
+		}
+		schemametrics.Forget(stalePath)
+	}
+
 	logger = logger.With().Str("clusterPath", clusterPath).Logger()
 	logger.Info().Msg("starting reconciliation...")
 
 	dc, err := r.DiscoveryFactory.ClientForCluster(clusterPath)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create discovery client for cluster")
+		schemametrics.RecordFailure(clusterPath, schemametrics.StageConnection)
 		return ctrl.Result{}, err
 	}
 
 	rm, err := r.DiscoveryFactory.RestMapperForCluster(clusterPath)
 	if err != nil {
 		logger.Error().Err(err).Msg("failed to create rest mapper for cluster")
+		schemametrics.RecordFailure(clusterPath, schemametrics.StageConnection)
 		return ctrl.Result{}, err
 	}
 
@@ -86,6 +99,7 @@ func (r *APIBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	savedSchema, err := r.IOHandler.Read(clusterPath)
 	if err != nil && !errors.Is(err, fs.ErrNotExist) {
 		logger.Error().Err(err).Msg("failed to read existing schema file")
+		schemametrics.RecordFailure(clusterPath, schemametrics.StageStorage)
 		return ctrl.Result{}, err
 	}
 
@@ -93,14 +107,54 @@ func (r *APIBindingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if errors.Is(err, fs.ErrNotExist) || !bytes.Equal(currentSchema, savedSchema) {
 		if err := r.IOHandler.Write(currentSchema, clusterPath); err != nil {
 			logger.Error().Err(err).Msg("failed to write schema to filesystem")
+			schemametrics.RecordFailure(clusterPath, schemametrics.StageStorage)
 			return ctrl.Result{}, err
 		}
 		logger.Info().Msg("schema file updated")
 	}
+	schemametrics.RecordSuccess(clusterPath, currentSchema)
+
+	// Resolve the path again once the cached one expired, so that moved clusters are picked up without binding changes
+	if r.PathCache != nil {
+		return ctrl.Result{RequeueAfter: r.PathCache.TTL()}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
 
+// resolveClusterPath returns the workspace path of the logical cluster from its LogicalCluster object, using the
+// cached path until it expires. stalePath is the previously resolved path if the path changed since then.
+func (r *APIBindingReconciler) resolveClusterPath(clusterName string) (path string, stalePath string, err error) {
+	if r.PathCache != nil {
+		if path, ok := r.PathCache.Get(clusterName); ok {
+			return path, "", nil
+		}
+	}
+
+	clusterClt, err := r.ClusterPathResolver.ClientForCluster(clusterName)
+	if err != nil {
+		return "", "", err
+	}
+
+	path, err = PathForCluster(clusterName, clusterClt)
+	if err != nil {
+		if r.PathCache != nil {
+			// Without the LogicalCluster the path of a deleted cluster is only known from earlier reconciles
+			if path == "" && errors.Is(err, ErrClusterIsDeleted) {
+				path, _ = r.PathCache.LastPath(clusterName)
+			}
+			r.PathCache.Delete(clusterName)
+		}
+		return path, "", err
+	}
+
+	if r.PathCache != nil {
+		stalePath = r.PathCache.Set(clusterName, path)
+	}
+
+	return path, stalePath, nil
+}
+
 // generateCurrentSchema is a subroutine that resolves the current API schema and injects KCP metadata
 func (r *APIBindingReconciler) generateCurrentSchema(dc discovery.DiscoveryInterface, rm meta.RESTMapper, clusterPath string) ([]byte, error) {
 	// Use shared schema generation logic
@@ -8,12 +8,15 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -583,3 +586,101 @@ users:
 		})
 	}
 }
+
+func TestAPIBindingReconciler_Reconcile_ClusterPathChanged(t *testing.T) {
+	kubeconfigPath := filepath.Join(t.TempDir(), "config")
+	err := os.WriteFile(kubeconfigPath, []byte(`apiVersion: v1
+kind: Config
+current-context: test
+contexts:
+- context: {cluster: test, user: test}
+  name: test
+clusters:
+- cluster: {server: 'https://test.example.com'}
+  name: test
+users:
+- name: test
+  user: {token: test-token}
+`), 0600)
+	assert.NoError(t, err)
+	t.Setenv("KUBECONFIG", kubeconfigPath)
+
+	mockLogger, _ := logger.New(logger.DefaultConfig())
+	mockIOHandler := workspacefilemocks.NewMockIOHandler(t)
+	mockDiscoveryFactory := kcpmocks.NewMockDiscoveryFactory(t)
+	mockAPISchemaResolver := apschemamocks.NewMockResolver(t)
+	mockClusterPathResolver := kcpmocks.NewMockClusterPathResolver(t)
+	mockDiscoveryClient := kcpmocks.NewMockDiscoveryInterface(t)
+	mockRestMapper := kcpmocks.NewMockRESTMapper(t)
+
+	now := time.Now()
+	pathCache := kcp.NewClusterPathCache(time.Minute)
+	pathCache.SetNow(func() time.Time { return now })
+
+	reconciler := &kcp.ExportedAPIBindingReconciler{
+		Scheme:              runtime.NewScheme(),
+		RestConfig:          &rest.Config{Host: "https://test.example.com"},
+		IOHandler:           mockIOHandler,
+		DiscoveryFactory:    mockDiscoveryFactory,
+		APISchemaResolver:   mockAPISchemaResolver,
+		ClusterPathResolver: mockClusterPathResolver,
+		PathCache:           pathCache,
+		Log:                 mockLogger,
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "test-binding"}, ClusterName: "moved-cluster"}
+
+	expectPath := func(path string) {
+		clusterClient := mocks.NewMockClient(t)
+		mockClusterPathResolver.EXPECT().ClientForCluster("moved-cluster").Return(clusterClient, nil).Once()
+		clusterClient.EXPECT().Get(mock.Anything, client.ObjectKey{Name: "cluster"}, mock.AnythingOfType("*v1alpha1.LogicalCluster")).
+			RunAndReturn(func(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				obj.SetAnnotations(map[string]string{"kcp.io/path": path})
+				return nil
+			}).Once()
+	}
+	expectSchema := func(path string, times int) {
+		mockDiscoveryFactory.EXPECT().ClientForCluster(path).Return(mockDiscoveryClient, nil).Times(times)
+		mockDiscoveryFactory.EXPECT().RestMapperForCluster(path).Return(mockRestMapper, nil).Times(times)
+		mockAPISchemaResolver.EXPECT().Resolve(mockDiscoveryClient, mockRestMapper).Return([]byte(`{"schema": "test"}`), nil).Times(times)
+		mockIOHandler.EXPECT().Read(path).Return(nil, fs.ErrNotExist).Times(times)
+		mockIOHandler.EXPECT().Write(mock.Anything, path).Return(nil).Times(times)
+	}
+
+	// The path is resolved once and cached for the following reconcile
+	expectPath("root:org:old-name")
+	expectSchema("root:org:old-name", 2)
+	for range 2 {
+		got, err := reconciler.Reconcile(t.Context(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, got)
+	}
+
+	// Once the cached path expired, the new path is resolved and the schema file of the old path is removed
+	now = now.Add(time.Minute)
+	expectPath("root:org:new-name")
+	mockIOHandler.EXPECT().Delete("root:org:old-name").Return(nil).Once()
+	expectSchema("root:org:new-name", 1)
+
+	got, err := reconciler.Reconcile(t.Context(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{RequeueAfter: time.Minute}, got)
+
+	// Once the workspace is gone, the schema of the last resolved path is removed
+	now = now.Add(time.Minute)
+	for range 2 {
+		clusterClient := mocks.NewMockClient(t)
+		mockClusterPathResolver.EXPECT().ClientForCluster("moved-cluster").Return(clusterClient, nil).Once()
+		clusterClient.EXPECT().Get(mock.Anything, client.ObjectKey{Name: "cluster"}, mock.AnythingOfType("*v1alpha1.LogicalCluster")).
+			Return(apierrors.NewNotFound(schema.GroupResource{Group: "core.kcp.io", Resource: "logicalclusters"}, "cluster")).Once()
+	}
+	mockIOHandler.EXPECT().Delete("root:org:new-name").Return(nil).Once()
+
+	got, err = reconciler.Reconcile(t.Context(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, got)
+
+	// The path is forgotten after the cleanup, so nothing is left to delete
+	got, err = reconciler.Reconcile(t.Context(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, ctrl.Result{}, got)
+}
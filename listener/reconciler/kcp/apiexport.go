@@ -0,0 +1,99 @@
+package kcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	kcpapis "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var (
+	ErrInvalidAPIExportSource = errors.New("invalid apiExport source")
+	ErrResolveAPIExports      = errors.New("failed to resolve APIExports")
+	ErrNoAPIExportResolver    = errors.New("apiExport sources need a connection to kcp")
+)
+
+// APIExportSource selects the APIExports whose virtual workspace URLs are used instead of a fixed URL.
+// Either Name or Selector must be set.
+type APIExportSource struct {
+	// Workspace is the path of the workspace containing the APIExports, e.g. root
+	Workspace string `yaml:"workspace"`
+	// Name selects a single APIExport
+	Name string `yaml:"name,omitempty"`
+	// Selector selects every APIExport matching the label selector, e.g. example.com/graphql=true
+	Selector string `yaml:"selector,omitempty"`
+}
+
+// APIExportResolver resolves the virtual workspace URLs of APIExports
+type APIExportResolver struct {
+	clusterPathResolver ClusterPathResolver
+}
+
+// NewAPIExportResolver creates a resolver reading the APIExports with clients of the given cluster path resolver
+func NewAPIExportResolver(clusterPathResolver ClusterPathResolver) *APIExportResolver {
+	return &APIExportResolver{clusterPathResolver: clusterPathResolver}
+}
+
+// Expand returns a virtual workspace per virtual workspace URL of the APIExports selected by workspace.
+// APIExports served by several shards have several URLs, their workspaces are suffixed with the index of the URL.
+// APIExports selected by label get the name of the APIExport appended, e.g. "exports-tenancy.kcp.io".
+func (r *APIExportResolver) Expand(ctx context.Context, workspace VirtualWorkspace) ([]VirtualWorkspace, error) {
+	source := workspace.APIExport
+	if source.Workspace == "" || (source.Name == "") == (source.Selector == "") {
+		return nil, fmt.Errorf("%w: %s needs a workspace and either a name or a selector", ErrInvalidAPIExportSource, workspace.Name)
+	}
+
+	clt, err := r.clusterPathResolver.ClientForCluster(source.Workspace)
+	if err != nil {
+		return nil, errors.Join(ErrResolveAPIExports, err)
+	}
+
+	var exports []kcpapis.APIExport
+	if source.Name != "" {
+		export := kcpapis.APIExport{}
+		if err := clt.Get(ctx, client.ObjectKey{Name: source.Name}, &export); err != nil {
+			return nil, errors.Join(ErrResolveAPIExports, err)
+		}
+		exports = append(exports, export)
+	} else {
+		selector, err := labels.Parse(source.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidAPIExportSource, workspace.Name, err)
+		}
+		list := &kcpapis.APIExportList{}
+		if err := clt.List(ctx, list, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, errors.Join(ErrResolveAPIExports, err)
+		}
+		exports = list.Items
+		sort.Slice(exports, func(i, j int) bool {
+			return exports[i].Name < exports[j].Name
+		})
+	}
+
+	var workspaces []VirtualWorkspace
+	for _, export := range exports {
+		for i, virtualWorkspace := range export.Status.VirtualWorkspaces {
+			name := workspace.Name
+			if source.Selector != "" {
+				name += "-" + export.Name
+			}
+			if len(export.Status.VirtualWorkspaces) > 1 {
+				name += "-" + strconv.Itoa(i)
+			}
+
+			workspaces = append(workspaces, VirtualWorkspace{
+				Name:       name,
+				URL:        virtualWorkspace.URL,
+				Kubeconfig: workspace.Kubeconfig,
+				source:     workspace.Name,
+			})
+		}
+	}
+
+	return workspaces, nil
+}
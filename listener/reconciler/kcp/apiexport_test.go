@@ -0,0 +1,102 @@
+package kcp_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	kcpapis "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/kcp"
+	kcpmocks "github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/kcp/mocks"
+)
+
+func TestAPIExportResolver_Expand(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kcpapis.AddToScheme(scheme))
+
+	export := func(name string, labels map[string]string, urls ...string) *kcpapis.APIExport {
+		apiExport := &kcpapis.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		for _, url := range urls {
+			apiExport.Status.VirtualWorkspaces = append(apiExport.Status.VirtualWorkspaces, kcpapis.VirtualWorkspace{URL: url})
+		}
+		return apiExport
+	}
+	graphqlLabels := map[string]string{"example.com/graphql": "true"}
+
+	tests := []struct {
+		name        string
+		source      kcp.APIExportSource
+		expected    map[string]string
+		expectedErr error
+	}{
+		{
+			name:     "by_name",
+			source:   kcp.APIExportSource{Workspace: "root", Name: "tenancy.kcp.io"},
+			expected: map[string]string{"exports": "https://kcp/services/apiexport/root/tenancy.kcp.io"},
+		},
+		{
+			name:   "by_name_on_several_shards",
+			source: kcp.APIExportSource{Workspace: "root", Name: "sharded"},
+			expected: map[string]string{
+				"exports-0": "https://shard-1/services/apiexport/root/sharded",
+				"exports-1": "https://shard-2/services/apiexport/root/sharded",
+			},
+		},
+		{
+			name:   "by_selector",
+			source: kcp.APIExportSource{Workspace: "root", Selector: "example.com/graphql=true"},
+			expected: map[string]string{
+				"exports-tenancy.kcp.io": "https://kcp/services/apiexport/root/tenancy.kcp.io",
+				"exports-sharded-0":      "https://shard-1/services/apiexport/root/sharded",
+				"exports-sharded-1":      "https://shard-2/services/apiexport/root/sharded",
+			},
+		},
+		{
+			name:        "name_and_selector",
+			source:      kcp.APIExportSource{Workspace: "root", Name: "tenancy.kcp.io", Selector: "example.com/graphql=true"},
+			expectedErr: kcp.ErrInvalidAPIExportSource,
+		},
+		{
+			name:        "missing_export",
+			source:      kcp.APIExportSource{Workspace: "root", Name: "missing"},
+			expectedErr: kcp.ErrResolveAPIExports,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clt := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				export("tenancy.kcp.io", graphqlLabels, "https://kcp/services/apiexport/root/tenancy.kcp.io"),
+				export("sharded", graphqlLabels, "https://shard-1/services/apiexport/root/sharded", "https://shard-2/services/apiexport/root/sharded"),
+				export("internal", nil, "https://kcp/services/apiexport/root/internal"),
+			).Build()
+
+			clusterPathResolver := kcpmocks.NewMockClusterPathResolver(t)
+			clusterPathResolver.EXPECT().ClientForCluster("root").Return(clt, nil).Maybe()
+
+			workspaces, err := kcp.NewAPIExportResolver(clusterPathResolver).Expand(context.Background(), kcp.VirtualWorkspace{
+				Name:       "exports",
+				Kubeconfig: "/etc/kcp/kubeconfig",
+				APIExport:  &tt.source,
+			})
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr), err)
+				return
+			}
+			require.NoError(t, err)
+
+			got := make(map[string]string, len(workspaces))
+			for _, workspace := range workspaces {
+				assert.Equal(t, "/etc/kcp/kubeconfig", workspace.Kubeconfig)
+				got[workspace.Name] = workspace.URL
+			}
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
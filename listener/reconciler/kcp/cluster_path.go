@@ -5,8 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"sync"
+	"time"
 
 	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -85,6 +88,10 @@ func PathForCluster(name string, clt client.Client) (string, error) {
 	}
 	lc := &kcpcore.LogicalCluster{}
 	if err := clt.Get(context.TODO(), client.ObjectKey{Name: "cluster"}, lc); err != nil {
+		// The LogicalCluster is gone once the deletion of the workspace completed
+		if apierrors.IsNotFound(err) {
+			return "", errors.Join(ErrClusterIsDeleted, err)
+		}
 		return "", errors.Join(ErrGetLogicalCluster, err)
 	}
 
@@ -99,3 +106,87 @@ func PathForCluster(name string, clt client.Client) (string, error) {
 
 	return path, nil
 }
+
+// ClusterPathCache remembers the workspace paths of logical clusters for a limited time.
+// The path of a logical cluster can change, e.g. when kcp moves it to another shard or the front-proxy paths change,
+// so it is resolved again once it expired. The last resolved path is kept to detect such changes.
+type ClusterPathCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]clusterPathEntry
+}
+
+type clusterPathEntry struct {
+	path       string
+	resolvedAt time.Time
+}
+
+// NewClusterPathCache creates a cache whose paths expire after ttl, a ttl of 0 resolves the path on every reconcile
+func NewClusterPathCache(ttl time.Duration) *ClusterPathCache {
+	return &ClusterPathCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]clusterPathEntry),
+	}
+}
+
+// TTL returns the duration after which cached paths are resolved again
+func (c *ClusterPathCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get returns the path of the logical cluster if it was resolved within the ttl
+func (c *ClusterPathCache) Get(name string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+	if !ok || c.now().Sub(entry.resolvedAt) >= c.ttl {
+		return "", false
+	}
+
+	return entry.path, true
+}
+
+// LastPath returns the last resolved path of the logical cluster, even if it expired
+func (c *ClusterPathCache) LastPath(name string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[name]
+	return entry.path, ok
+}
+
+// Set stores the resolved path and returns the previously resolved path if it is different
+func (c *ClusterPathCache) Set(name, path string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous, ok := c.entries[name]
+	c.entries[name] = clusterPathEntry{path: path, resolvedAt: c.now()}
+	if ok && previous.path != path {
+		return previous.path
+	}
+
+	return ""
+}
+
+// Delete forgets the path of the logical cluster, e.g. because it was deleted
+func (c *ClusterPathCache) Delete(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, name)
+}
+
+// Expire makes all paths resolve again on the next reconcile, the last resolved paths are kept to detect changes
+func (c *ClusterPathCache) Expire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, entry := range c.entries {
+		entry.resolvedAt = time.Time{}
+		c.entries[name] = entry
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -277,6 +280,17 @@ func TestPathForCluster(t *testing.T) {
 			wantErr:     true,
 			errContains: "failed to get cluster path from kcp.io/path annotation",
 		},
+		{
+			name:        "logical_cluster_not_found",
+			clusterName: "removed-workspace",
+			mockSetup: func(m *mocks.MockClient) {
+				m.EXPECT().Get(mock.Anything, client.ObjectKey{Name: "cluster"}, mock.AnythingOfType("*v1alpha1.LogicalCluster")).
+					Return(apierrors.NewNotFound(schema.GroupResource{Group: "core.kcp.io", Resource: "logicalclusters"}, "cluster")).Once()
+			},
+			want:        "",
+			wantErr:     true,
+			errContains: "cluster is deleted",
+		},
 		{
 			name:        "client_get_error",
 			clusterName: "error-workspace",
@@ -328,3 +342,44 @@ func TestConstants(t *testing.T) {
 		assert.Equal(t, "cluster is deleted", kcp.ErrClusterIsDeletedExported.Error())
 	})
 }
+
+func TestClusterPathCache(t *testing.T) {
+	now := time.Now()
+	cache := kcp.NewClusterPathCache(time.Minute)
+	cache.SetNow(func() time.Time { return now })
+
+	_, ok := cache.Get("workspace-1")
+	assert.False(t, ok)
+
+	assert.Empty(t, cache.Set("workspace-1", "root:org:workspace-1"))
+	path, ok := cache.Get("workspace-1")
+	assert.True(t, ok)
+	assert.Equal(t, "root:org:workspace-1", path)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get("workspace-1")
+	assert.False(t, ok, "expired paths must be resolved again")
+
+	assert.Empty(t, cache.Set("workspace-1", "root:org:workspace-1"), "unchanged path")
+	assert.Equal(t, "root:org:workspace-1", cache.Set("workspace-1", "root:moved:workspace-1"))
+
+	now = now.Add(time.Minute)
+	path, ok = cache.LastPath("workspace-1")
+	assert.True(t, ok, "the last path is kept after it expired")
+	assert.Equal(t, "root:moved:workspace-1", path)
+
+	cache.Set("workspace-1", "root:moved:workspace-1")
+	cache.Expire()
+	_, ok = cache.Get("workspace-1")
+	assert.False(t, ok, "expired paths must be resolved again")
+	path, ok = cache.LastPath("workspace-1")
+	assert.True(t, ok, "the last path is kept after it expired")
+	assert.Equal(t, "root:moved:workspace-1", path)
+
+	cache.Delete("workspace-1")
+	_, ok = cache.Get("workspace-1")
+	assert.False(t, ok)
+	_, ok = cache.LastPath("workspace-1")
+	assert.False(t, ok)
+	assert.Empty(t, cache.Set("workspace-1", "root:other:workspace-1"), "deleted paths are forgotten")
+}
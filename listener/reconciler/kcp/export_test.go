@@ -1,6 +1,8 @@
 package kcp
 
 import (
+	"time"
+
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,3 +58,8 @@ func NewClusterPathResolverProviderWithFactory(cfg *rest.Config, scheme *runtime
 		clientFactory: factory,
 	}
 }
+
+// SetNow replaces the clock of the cluster path cache for testing
+func (c *ClusterPathCache) SetNow(now func() time.Time) {
+	c.now = now
+}
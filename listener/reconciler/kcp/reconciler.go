@@ -2,9 +2,16 @@ package kcp
 
 import (
 	"context"
+	"errors"
+	"sync"
+	"time"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	kcpctrl "sigs.k8s.io/controller-runtime/pkg/kcp"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	kcpapis "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	"github.com/openmfp/golang-commons/logger"
@@ -19,7 +26,13 @@ type KCPReconciler struct {
 	apiBindingReconciler       *APIBindingReconciler
 	virtualWorkspaceReconciler *VirtualWorkspaceReconciler
 	configWatcher              *ConfigWatcher
-	log                        *logger.Logger
+	// apiExportResyncPeriod is how often the virtual workspaces with APIExport sources are resolved again
+	apiExportResyncPeriod time.Duration
+	schemaGC              *SchemaGarbageCollector
+	schemaGCPeriod        time.Duration
+	// resyncEvents enqueues the APIBindings again on Resync
+	resyncEvents chan event.GenericEvent
+	log          *logger.Logger
 }
 
 func NewKCPReconciler(
@@ -37,14 +50,14 @@ func NewKCPReconciler(
 	}
 
 	// Create IO handler for schema files
-	ioHandler, err := workspacefile.NewIOHandler(appCfg.OpenApiDefinitionsPath)
+	ioHandler, err := workspacefile.NewIOHandlerFromConfig(appCfg)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to create IO handler")
 		return nil, err
 	}
 
 	// Create schema resolver
-	schemaResolver := apischema.NewResolver(log)
+	schemaResolver := apischema.NewResolver(log, apischema.ResolverOptionsFromConfig(appCfg)...)
 
 	// Create cluster path resolver
 	clusterPathResolver, err := NewClusterPathResolver(opts.Config, opts.Scheme)
@@ -69,6 +82,7 @@ func NewKCPReconciler(
 		DiscoveryFactory:    discoveryFactory,
 		APISchemaResolver:   schemaResolver,
 		ClusterPathResolver: clusterPathResolver,
+		PathCache:           NewClusterPathCache(appCfg.Listener.ClusterPathCacheTTL),
 		Log:                 log,
 	}
 
@@ -79,7 +93,7 @@ func NewKCPReconciler(
 		ioHandler,
 		schemaResolver,
 		log,
-	)
+	).WithAPIExportResolver(NewAPIExportResolver(clusterPathResolver))
 
 	configWatcher, err := NewConfigWatcher(virtualWSManager, log)
 	if err != nil {
@@ -92,6 +106,10 @@ func NewKCPReconciler(
 		apiBindingReconciler:       apiBindingReconciler,
 		virtualWorkspaceReconciler: virtualWorkspaceReconciler,
 		configWatcher:              configWatcher,
+		apiExportResyncPeriod:      appCfg.Listener.APIExportResyncPeriod,
+		schemaGC:                   NewSchemaGarbageCollector(ioHandler, clusterPathResolver, log),
+		schemaGCPeriod:             appCfg.Listener.SchemaGCPeriod,
+		resyncEvents:               make(chan event.GenericEvent),
 		log:                        log,
 	}
 
@@ -119,15 +137,46 @@ func (r *KCPReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Setup the APIBinding controller with cluster context - this is crucial for req.ClusterName
 	if err := ctrl.NewControllerManagedBy(mgr).
 		For(&kcpapis.APIBinding{}).
+		WatchesRawSource(source.Channel(r.resyncEvents, &handler.EnqueueRequestForObject{})).
 		Complete(kcpctrl.WithClusterInContext(r.apiBindingReconciler)); err != nil {
 		r.log.Error().Err(err).Msg("failed to setup APIBinding controller")
 		return err
 	}
 
 	r.log.Info().Msg("Successfully set up APIBinding controller")
+
+	// The garbage collection runs with the manager, so that only the leader deletes schemas
+	if r.schemaGC != nil && r.schemaGCPeriod > 0 {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			r.schemaGC.Run(ctx, r.schemaGCPeriod)
+			return nil
+		})); err != nil {
+			r.log.Error().Err(err).Msg("failed to setup schema garbage collection")
+			return err
+		}
+	}
+
 	return nil
 }
 
+// Resync generates the schemas of all workspaces and virtual workspaces again. The paths of the workspaces are
+// resolved again as well, so that moved workspaces are picked up.
+func (r *KCPReconciler) Resync(ctx context.Context) error {
+	// Handle cases where the reconciler wasn't properly initialized (e.g., in tests)
+	if r.apiBindingReconciler == nil {
+		return nil
+	}
+
+	if r.apiBindingReconciler.PathCache != nil {
+		r.apiBindingReconciler.PathCache.Expire()
+	}
+
+	return errors.Join(
+		reconciler.EnqueueAll(ctx, r.mgr.GetClient(), &kcpapis.APIBindingList{}, r.resyncEvents),
+		r.virtualWorkspaceReconciler.Resync(ctx),
+	)
+}
+
 // StartVirtualWorkspaceWatching starts watching virtual workspace configuration
 func (r *KCPReconciler) StartVirtualWorkspaceWatching(ctx context.Context, configPath string) error {
 	if configPath == "" {
@@ -137,11 +186,54 @@ func (r *KCPReconciler) StartVirtualWorkspaceWatching(ctx context.Context, confi
 
 	r.log.Info().Str("configPath", configPath).Msg("starting virtual workspace configuration watching")
 
+	// The latest config is reconciled again periodically, so that changed URLs of APIExports are picked up
+	var mu sync.Mutex
+	var latest *VirtualWorkspacesConfig
+
 	// Start config watcher with a wrapper function
 	changeHandler := func(config *VirtualWorkspacesConfig) {
+		mu.Lock()
+		latest = config
+		mu.Unlock()
+
 		if err := r.virtualWorkspaceReconciler.ReconcileConfig(ctx, config); err != nil {
 			r.log.Error().Err(err).Msg("failed to reconcile virtual workspaces config")
 		}
 	}
+
+	if r.apiExportResyncPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(r.apiExportResyncPeriod)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					mu.Lock()
+					config := latest
+					mu.Unlock()
+
+					if config == nil || !hasAPIExportSources(config) {
+						continue
+					}
+					if err := r.virtualWorkspaceReconciler.ReconcileConfig(ctx, config); err != nil {
+						r.log.Error().Err(err).Msg("failed to resync APIExport virtual workspaces")
+					}
+				}
+			}
+		}()
+	}
+
 	return r.configWatcher.Watch(ctx, configPath, changeHandler)
 }
+
+func hasAPIExportSources(config *VirtualWorkspacesConfig) bool {
+	for _, workspace := range config.VirtualWorkspaces {
+		if workspace.APIExport != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -1,11 +1,14 @@
 package kcp_test
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	kcpapis "github.com/kcp-dev/kcp/sdk/apis/apis/v1alpha1"
 	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -21,6 +24,10 @@ import (
 func TestNewKCPReconciler(t *testing.T) {
 	mockLogger, _ := logger.New(logger.DefaultConfig())
 
+	// Directories can't be created below a file, not even by root
+	blockingFile := filepath.Join(t.TempDir(), "schemas")
+	require.NoError(t, os.WriteFile(blockingFile, nil, 0o600))
+
 	tests := []struct {
 		name        string
 		appCfg      config.Config
@@ -60,7 +67,7 @@ func TestNewKCPReconciler(t *testing.T) {
 		{
 			name: "invalid_openapi_definitions_path",
 			appCfg: config.Config{
-				OpenApiDefinitionsPath: "/invalid/path/that/does/not/exist",
+				OpenApiDefinitionsPath: filepath.Join(blockingFile, "definitions"),
 			},
 			opts: reconciler.ReconcilerOpts{
 				Config: &rest.Config{
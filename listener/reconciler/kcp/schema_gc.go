@@ -0,0 +1,90 @@
+package kcp
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
+)
+
+// SchemaGarbageCollector removes the schemas of workspaces that no longer exist. The APIBinding reconciler removes the
+// schema of a deleted workspace itself, but misses workspaces deleted while the listener wasn't running.
+type SchemaGarbageCollector struct {
+	ioHandler           workspacefile.IOHandler
+	clusterPathResolver ClusterPathResolver
+	log                 *logger.Logger
+}
+
+func NewSchemaGarbageCollector(ioHandler workspacefile.IOHandler, clusterPathResolver ClusterPathResolver, log *logger.Logger) *SchemaGarbageCollector {
+	return &SchemaGarbageCollector{
+		ioHandler:           ioHandler,
+		clusterPathResolver: clusterPathResolver,
+		log:                 log,
+	}
+}
+
+// Run collects the schemas once and then every period until the context is done
+func (gc *SchemaGarbageCollector) Run(ctx context.Context, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		if _, err := gc.Collect(ctx); err != nil {
+			gc.log.Error().Err(err).Msg("failed to collect schemas of deleted workspaces")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Collect deletes the schemas of the workspaces whose LogicalCluster is gone or being deleted and returns their paths.
+// Only schemas of workspace paths are considered, virtual workspaces are removed by the virtual workspace reconciler.
+// Workspaces that can't be checked, e.g. because kcp is unavailable, keep their schema.
+func (gc *SchemaGarbageCollector) Collect(ctx context.Context) ([]string, error) {
+	clusterPaths, err := gc.ioHandler.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for _, clusterPath := range clusterPaths {
+		if ctx.Err() != nil {
+			return deleted, ctx.Err()
+		}
+		if !strings.HasPrefix(clusterPath, "root:") {
+			continue
+		}
+
+		clt, err := gc.clusterPathResolver.ClientForCluster(clusterPath)
+		if err != nil {
+			gc.log.Warn().Err(err).Str("clusterPath", clusterPath).Msg("failed to check if workspace of schema exists")
+			continue
+		}
+		if _, err := PathForCluster(clusterPath, clt); !errors.Is(err, ErrClusterIsDeleted) {
+			if err != nil {
+				gc.log.Warn().Err(err).Str("clusterPath", clusterPath).Msg("failed to check if workspace of schema exists")
+			}
+			continue
+		}
+
+		if err := gc.ioHandler.Delete(clusterPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			gc.log.Error().Err(err).Str("clusterPath", clusterPath).Msg("failed to delete schema of deleted workspace")
+			continue
+		}
+		schemametrics.Forget(clusterPath)
+		gc.log.Info().Str("clusterPath", clusterPath).Msg("deleted schema of deleted workspace")
+		deleted = append(deleted, clusterPath)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,61 @@
+package kcp_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	kcpcore "github.com/kcp-dev/kcp/sdk/apis/core/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	workspacefilemocks "github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile/mocks"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/kcp"
+	kcpmocks "github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler/kcp/mocks"
+)
+
+func TestSchemaGarbageCollector_Collect(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, kcpcore.AddToScheme(scheme))
+
+	logicalCluster := func(path string, deleting bool) client.Client {
+		lc := &kcpcore.LogicalCluster{ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster",
+			Annotations: map[string]string{"kcp.io/path": path},
+		}}
+		if deleting {
+			lc.Finalizers = []string{"core.kcp.io/logicalcluster"}
+			// A zero time is lost when the fake client stores the object
+			lc.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		}
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(lc).Build()
+	}
+
+	ioHandler := workspacefilemocks.NewMockIOHandler(t)
+	ioHandler.EXPECT().List().Return([]string{
+		"root",
+		"root:orgs:existing",
+		"root:orgs:removed",
+		"root:orgs:deleting",
+		"root:orgs:unreachable",
+		"virtual-workspace/exports",
+	}, nil).Once()
+
+	clusterPathResolver := kcpmocks.NewMockClusterPathResolver(t)
+	clusterPathResolver.EXPECT().ClientForCluster("root:orgs:existing").Return(logicalCluster("root:orgs:existing", false), nil).Once()
+	clusterPathResolver.EXPECT().ClientForCluster("root:orgs:removed").Return(fake.NewClientBuilder().WithScheme(scheme).Build(), nil).Once()
+	clusterPathResolver.EXPECT().ClientForCluster("root:orgs:deleting").Return(logicalCluster("root:orgs:deleting", true), nil).Once()
+	clusterPathResolver.EXPECT().ClientForCluster("root:orgs:unreachable").Return(nil, errors.New("connection refused")).Once()
+
+	ioHandler.EXPECT().Delete("root:orgs:removed").Return(nil).Once()
+	ioHandler.EXPECT().Delete("root:orgs:deleting").Return(nil).Once()
+
+	deleted, err := kcp.NewSchemaGarbageCollector(ioHandler, clusterPathResolver, testlogger.New().HideLogOutput().Logger).Collect(t.Context())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"root:orgs:removed", "root:orgs:deleting"}, deleted)
+}
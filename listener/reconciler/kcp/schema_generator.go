@@ -2,6 +2,7 @@ package kcp
 
 import (
 	"fmt"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/client-go/discovery"
@@ -9,6 +10,7 @@ import (
 	"github.com/openmfp/golang-commons/logger"
 	"github.com/openmfp/kubernetes-graphql-gateway/common/auth"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
 )
 
 // SchemaGenerationParams contains parameters for schema generation
@@ -29,9 +31,12 @@ func generateSchemaWithMetadata(
 	log.Debug().Str("clusterPath", params.ClusterPath).Msg("starting API schema resolution")
 
 	// Resolve current schema from API server
+	resolutionStart := time.Now()
 	rawSchema, err := apiSchemaResolver.Resolve(params.DiscoveryClient, params.RESTMapper)
+	schemametrics.ObserveResolution(params.ClusterPath, resolutionStart)
 	if err != nil {
 		log.Error().Err(err).Msg("failed to resolve server JSON schema")
+		schemametrics.RecordFailure(params.ClusterPath, schemametrics.StageResolution)
 		return nil, fmt.Errorf("failed to resolve API schema: %w", err)
 	}
 
@@ -52,6 +57,7 @@ func generateSchemaWithMetadata(
 
 	if err != nil {
 		log.Error().Err(err).Msg("failed to inject KCP cluster metadata")
+		schemametrics.RecordFailure(params.ClusterPath, schemametrics.StageMetadata)
 		return nil, fmt.Errorf("failed to inject KCP cluster metadata: %w", err)
 	}
 
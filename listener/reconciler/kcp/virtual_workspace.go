@@ -18,6 +18,7 @@ import (
 
 	"github.com/openmfp/kubernetes-graphql-gateway/common/config"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/apischema"
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
 	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/workspacefile"
 )
 
@@ -31,6 +32,11 @@ type VirtualWorkspace struct {
 	Name       string `yaml:"name"`
 	URL        string `yaml:"url"`
 	Kubeconfig string `yaml:"kubeconfig,omitempty"` // Optional path to kubeconfig for authentication
+	// APIExport resolves the URLs from APIExports instead of URL, see APIExportResolver
+	APIExport *APIExportSource `yaml:"apiExport,omitempty"`
+
+	// source is the name of the configured workspace a workspace was expanded from by its APIExport
+	source string
 }
 
 // VirtualWorkspacesConfig represents the configuration file structure
@@ -150,6 +156,8 @@ type VirtualWorkspaceReconciler struct {
 	log               *logger.Logger
 	mu                sync.RWMutex
 	currentWorkspaces map[string]VirtualWorkspace
+	// apiExports expands the workspaces with APIExport sources, see WithAPIExportResolver
+	apiExports *APIExportResolver
 }
 
 // NewVirtualWorkspaceReconciler creates a new virtual workspace reconciler
@@ -168,7 +176,14 @@ func NewVirtualWorkspaceReconciler(
 	}
 }
 
-// ReconcileConfig processes a virtual workspaces configuration update
+// WithAPIExportResolver enables the workspaces whose URLs are resolved from APIExports
+func (r *VirtualWorkspaceReconciler) WithAPIExportResolver(resolver *APIExportResolver) *VirtualWorkspaceReconciler {
+	r.apiExports = resolver
+	return r
+}
+
+// ReconcileConfig processes a virtual workspaces configuration update.
+// Workspaces with an APIExport source are resolved on every call, so that changed URLs are picked up by calling it again.
 func (r *VirtualWorkspaceReconciler) ReconcileConfig(ctx context.Context, config *VirtualWorkspacesConfig) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -178,7 +193,25 @@ func (r *VirtualWorkspaceReconciler) ReconcileConfig(ctx context.Context, config
 	// Track new workspaces for comparison
 	newWorkspaces := make(map[string]VirtualWorkspace)
 	for _, ws := range config.VirtualWorkspaces {
-		newWorkspaces[ws.Name] = ws
+		if ws.APIExport == nil {
+			newWorkspaces[ws.Name] = ws
+			continue
+		}
+
+		expanded, err := r.expandAPIExport(ctx, ws)
+		if err != nil {
+			// The schemas of the last resolved URLs are kept, the APIExports may only be unreachable for a moment
+			r.log.Error().Err(err).Str("workspace", ws.Name).Msg("failed to resolve APIExport virtual workspaces, keeping the previous ones")
+			for name, current := range r.currentWorkspaces {
+				if current.source == ws.Name {
+					newWorkspaces[name] = current
+				}
+			}
+			continue
+		}
+		for _, expandedWS := range expanded {
+			newWorkspaces[expandedWS.Name] = expandedWS
+		}
 	}
 
 	// Process new or updated workspaces
@@ -210,6 +243,29 @@ func (r *VirtualWorkspaceReconciler) ReconcileConfig(ctx context.Context, config
 	return nil
 }
 
+// Resync generates the schemas of all current virtual workspaces again, even if their URLs didn't change
+func (r *VirtualWorkspaceReconciler) Resync(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var errs []error
+	for name, workspace := range r.currentWorkspaces {
+		if err := r.processVirtualWorkspace(ctx, workspace); err != nil {
+			r.log.Error().Err(err).Str("workspace", name).Msg("failed to resync virtual workspace")
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *VirtualWorkspaceReconciler) expandAPIExport(ctx context.Context, workspace VirtualWorkspace) ([]VirtualWorkspace, error) {
+	if r.apiExports == nil {
+		return nil, ErrNoAPIExportResolver
+	}
+	return r.apiExports.Expand(ctx, workspace)
+}
+
 // processVirtualWorkspace generates schema for a single virtual workspace
 func (r *VirtualWorkspaceReconciler) processVirtualWorkspace(ctx context.Context, workspace VirtualWorkspace) error {
 	workspacePath := r.virtualWSManager.GetWorkspacePath(workspace)
@@ -223,6 +279,7 @@ func (r *VirtualWorkspaceReconciler) processVirtualWorkspace(ctx context.Context
 	// Create discovery client for the virtual workspace
 	discoveryClient, err := r.virtualWSManager.CreateDiscoveryClient(workspace)
 	if err != nil {
+		schemametrics.RecordFailure(workspacePath, schemametrics.StageConnection)
 		return fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
@@ -231,16 +288,19 @@ func (r *VirtualWorkspaceReconciler) processVirtualWorkspace(ctx context.Context
 	// Create REST config and mapper for the virtual workspace
 	virtualConfig, err := r.virtualWSManager.CreateRESTConfig(workspace)
 	if err != nil {
+		schemametrics.RecordFailure(workspacePath, schemametrics.StageConnection)
 		return fmt.Errorf("failed to create REST config: %w", err)
 	}
 
 	httpClient, err := rest.HTTPClientFor(virtualConfig)
 	if err != nil {
+		schemametrics.RecordFailure(workspacePath, schemametrics.StageConnection)
 		return fmt.Errorf("failed to create HTTP client for virtual workspace: %w", err)
 	}
 
 	restMapper, err := apiutil.NewDynamicRESTMapper(virtualConfig, httpClient)
 	if err != nil {
+		schemametrics.RecordFailure(workspacePath, schemametrics.StageConnection)
 		return fmt.Errorf("failed to create REST mapper for virtual workspace: %w", err)
 	}
 
@@ -261,8 +321,10 @@ func (r *VirtualWorkspaceReconciler) processVirtualWorkspace(ctx context.Context
 
 	// Write the schema to file
 	if err := r.ioHandler.Write(schemaWithMetadata, workspacePath); err != nil {
+		schemametrics.RecordFailure(workspacePath, schemametrics.StageStorage)
 		return fmt.Errorf("failed to write schema file: %w", err)
 	}
+	schemametrics.RecordSuccess(workspacePath, schemaWithMetadata)
 
 	r.log.Info().
 		Str("workspace", workspace.Name).
@@ -281,6 +343,7 @@ func (r *VirtualWorkspaceReconciler) removeVirtualWorkspace(name string) error {
 	if err := r.ioHandler.Delete(workspacePath); err != nil {
 		return fmt.Errorf("failed to delete schema file for workspace %s: %w", name, err)
 	}
+	schemametrics.Forget(workspacePath)
 
 	r.log.Info().Str("workspace", name).Str("path", workspacePath).Msg("removed schema file for virtual workspace")
 	return nil
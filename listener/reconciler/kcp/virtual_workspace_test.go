@@ -21,6 +21,7 @@ type MockIOHandler struct {
 	ReadFunc   func(clusterName string) ([]byte, error)
 	WriteFunc  func(data []byte, workspacePath string) error
 	DeleteFunc func(workspacePath string) error
+	ListFunc   func() ([]string, error)
 }
 
 func (m *MockIOHandler) Read(clusterName string) ([]byte, error) {
@@ -44,6 +45,13 @@ func (m *MockIOHandler) Delete(workspacePath string) error {
 	return nil
 }
 
+func (m *MockIOHandler) List() ([]string, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc()
+	}
+	return nil, nil
+}
+
 type MockAPISchemaResolver struct {
 	ResolveFunc func(discoveryClient discovery.DiscoveryInterface, restMapper meta.RESTMapper) ([]byte, error)
 }
@@ -647,6 +655,37 @@ func TestVirtualWorkspaceReconciler_ReconcileConfig_Simple(t *testing.T) {
 	}
 }
 
+func TestVirtualWorkspaceReconciler_ReconcileConfig_UnresolvedAPIExport(t *testing.T) {
+	appCfg := config.Config{}
+	appCfg.Url.VirtualWorkspacePrefix = "virtual-workspace"
+
+	var deleted []string
+	ioHandler := &MockIOHandler{
+		DeleteFunc: func(workspacePath string) error {
+			deleted = append(deleted, workspacePath)
+			return nil
+		},
+	}
+
+	// Without a resolver the APIExports can't be read, the previously resolved workspaces of the entry are kept
+	reconciler := NewVirtualWorkspaceReconciler(NewVirtualWorkspaceManager(appCfg), ioHandler, &MockAPISchemaResolver{}, testlogger.New().HideLogOutput().Logger)
+	reconciler.currentWorkspaces = map[string]VirtualWorkspace{
+		"exports-0": {Name: "exports-0", URL: "https://shard-1/services/apiexport/root/sharded", source: "exports"},
+		"static":    {Name: "static", URL: "https://kcp/services/apiexport/root/static"},
+	}
+
+	err := reconciler.ReconcileConfig(context.Background(), &VirtualWorkspacesConfig{
+		VirtualWorkspaces: []VirtualWorkspace{
+			{Name: "exports", APIExport: &APIExportSource{Workspace: "root", Name: "sharded"}},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, reconciler.currentWorkspaces, "exports-0")
+	assert.NotContains(t, reconciler.currentWorkspaces, "static")
+	assert.Equal(t, []string{"virtual-workspace/static"}, deleted)
+}
+
 func TestVirtualWorkspaceReconciler_ProcessVirtualWorkspace(t *testing.T) {
 	tests := []struct {
 		name               string
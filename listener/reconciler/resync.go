@@ -0,0 +1,147 @@
+package reconciler
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/pkg/schemametrics"
+)
+
+// ResyncPath is served by the metrics server of the listener, a POST request regenerates all schemas
+const ResyncPath = "/resync"
+
+// Triggers of a resync, reported by the resyncs metric
+const (
+	ResyncPeriodic = "periodic"
+	ResyncSignal   = "signal"
+	ResyncHTTP     = "http"
+)
+
+// Resyncable reconcilers regenerate the schemas of all their clusters on Resync, including those whose objects didn't
+// change, e.g. because a CRD was updated while the listener was down
+type Resyncable interface {
+	Resync(ctx context.Context) error
+}
+
+// Resyncer regenerates all schemas every period, on SIGUSR1 and on POST requests to ResyncPath. Triggers arriving
+// while a resync runs are coalesced into one more resync.
+type Resyncer struct {
+	log      *logger.Logger
+	period   time.Duration
+	requests chan string
+}
+
+// NewResyncer resyncs every period, 0 only resyncs on demand
+func NewResyncer(log *logger.Logger, period time.Duration) *Resyncer {
+	return &Resyncer{
+		log:      log,
+		period:   period,
+		requests: make(chan string, 1),
+	}
+}
+
+// Request asks for a resync, it returns false if one is pending already
+func (r *Resyncer) Request(trigger string) bool {
+	select {
+	case r.requests <- trigger:
+		return true
+	default:
+		return false
+	}
+}
+
+// ServeHTTP requests a resync on POST requests, it is answered before the schemas are regenerated
+func (r *Resyncer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.Request(ResyncHTTP)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// WatchSignal requests a resync on SIGUSR1 until ctx is done. It runs on every replica, so that the signal doesn't
+// terminate the replicas that aren't leading, their request is served once they are elected.
+func (r *Resyncer) WatchSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			r.Request(ResyncSignal)
+		}
+	}
+}
+
+// Runnable resyncs the reconciler with the controller manager, so that only the leader regenerates the schemas
+func (r *Resyncer) Runnable(reconciler Resyncable) manager.Runnable {
+	return manager.RunnableFunc(func(ctx context.Context) error {
+		var ticks <-chan time.Time
+		if r.period > 0 {
+			ticker := time.NewTicker(r.period)
+			defer ticker.Stop()
+			ticks = ticker.C
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-ticks:
+				r.resync(ctx, reconciler, ResyncPeriodic)
+			case trigger := <-r.requests:
+				r.resync(ctx, reconciler, trigger)
+			}
+		}
+	})
+}
+
+func (r *Resyncer) resync(ctx context.Context, reconciler Resyncable, trigger string) {
+	r.log.Info().Str("trigger", trigger).Msg("Regenerating all schemas")
+	schemametrics.RecordResync(trigger)
+
+	if err := reconciler.Resync(ctx); err != nil {
+		r.log.Error().Err(err).Str("trigger", trigger).Msg("Failed to regenerate all schemas")
+	}
+}
+
+// EnqueueAll sends a generic event for every object of list to events, which a controller watching the channel
+// reconciles again
+func EnqueueAll(ctx context.Context, c client.Client, list client.ObjectList, events chan<- event.GenericEvent) error {
+	if err := c.List(ctx, list); err != nil {
+		return err
+	}
+
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		obj, ok := item.(client.Object)
+		if !ok {
+			continue
+		}
+		select {
+		case events <- event.GenericEvent{Object: obj}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package reconciler_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openmfp/golang-commons/logger/testlogger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openmfp/kubernetes-graphql-gateway/listener/reconciler"
+)
+
+type resyncFunc func(ctx context.Context) error
+
+func (f resyncFunc) Resync(ctx context.Context) error {
+	return f(ctx)
+}
+
+func TestResyncer_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		method         string
+		expectedStatus int
+	}{
+		{name: "post", method: http.MethodPost, expectedStatus: http.StatusAccepted},
+		{name: "get", method: http.MethodGet, expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resyncer := reconciler.NewResyncer(testlogger.New().HideLogOutput().Logger, 0)
+
+			rec := httptest.NewRecorder()
+			resyncer.ServeHTTP(rec, httptest.NewRequest(tt.method, reconciler.ResyncPath, nil))
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			// A request is pending only if the handler accepted it
+			assert.Equal(t, tt.expectedStatus == http.StatusAccepted, !resyncer.Request(reconciler.ResyncHTTP))
+		})
+	}
+}
+
+func TestResyncer_Runnable(t *testing.T) {
+	resyncer := reconciler.NewResyncer(testlogger.New().HideLogOutput().Logger, 0)
+
+	// Requests arriving before the resync runs are coalesced
+	assert.True(t, resyncer.Request(reconciler.ResyncHTTP))
+	assert.False(t, resyncer.Request(reconciler.ResyncSignal))
+
+	resyncs := make(chan struct{}, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- resyncer.Runnable(resyncFunc(func(context.Context) error {
+			resyncs <- struct{}{}
+			return nil
+		})).Start(ctx)
+	}()
+
+	select {
+	case <-resyncs:
+	case <-time.After(time.Second):
+		t.Fatal("the pending request wasn't served")
+	}
+	assert.True(t, resyncer.Request(reconciler.ResyncHTTP))
+	select {
+	case <-resyncs:
+	case <-time.After(time.Second):
+		t.Fatal("the request wasn't served")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+	assert.Empty(t, resyncs, "the coalesced request must not resync again")
+}
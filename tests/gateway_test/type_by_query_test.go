@@ -3,6 +3,7 @@ package gateway_test
 import (
 	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/go-openapi/spec"
@@ -70,6 +71,12 @@ func TestTypeByCategory(t *testing.T) {
 	firstItem := typeByCategory[0].(map[string]interface{})
 
 	assert.Equal(t, "networking_istio_io", firstItem["group"])
+	assert.Equal(t, "networking.istio.io", firstItem["apiGroup"])
+	assert.Contains(t, firstItem["singular"], "DestinationRule")
+	assert.Contains(t, firstItem["plural"], "DestinationRules")
+	assert.Equal(t, true, firstItem["subscriptions"])
+	assert.Equal(t, strings.ToLower("networking_istio_io_"+firstItem["plural"].(string)), firstItem["pluralSubscription"])
+	assert.Contains(t, firstItem["verbs"], "watch", "schemas without verbs report the verbs of the generated operations")
 }
 
 func typeByCategoryQuery() string {
@@ -77,9 +84,15 @@ func typeByCategoryQuery() string {
 		query{
 		  typeByCategory(name: "istio-io"){
 			group
+			apiGroup
 			version
 			kind
 			scope
+			singular
+			plural
+			verbs
+			subscriptions
+			pluralSubscription
 		  }
 		}`
 }